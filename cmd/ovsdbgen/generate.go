@@ -0,0 +1,558 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// generate generates Go source for the tables in s, using the package name
+// pkg.
+func generate(pkg string, s *ovsdb.Schema) ([]byte, error) {
+	g := &generator{}
+
+	g.printf("// Code generated by ovsdbgen. DO NOT EDIT.\n\n")
+	g.printf("// Package %s contains bindings for the %s OVSDB schema, version %s.\n", pkg, s.Name, s.Version)
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"strings\"\n\n\t\"github.com/digitalocean/go-openvswitch/ovsdb\"\n)\n\n")
+
+	g.printf("// DatabaseName is the name of the %s database.\n", s.Name)
+	g.printf("const DatabaseName = %q\n\n", s.Name)
+
+	names := make([]string, 0, len(s.Tables))
+	for n := range s.Tables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		t, err := newTable(n, s.Tables[n])
+		if err != nil {
+			return nil, err
+		}
+
+		g.table(t)
+	}
+
+	g.printf("%s", helpers)
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v", err)
+	}
+
+	return src, nil
+}
+
+// A generator accumulates generated Go source.
+type generator struct {
+	buf bytes.Buffer
+}
+
+// printf writes formatted source to the generator's buffer.
+func (g *generator) printf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(&g.buf, format, a...)
+}
+
+// A table is a table whose Go identifiers have been computed.
+type table struct {
+	Name    string
+	Type    string
+	Const   string
+	Columns []column
+}
+
+// A column is a column whose Go identifiers have been computed.
+type column struct {
+	Name  string
+	Field string
+	Const string
+	Type  ovsdb.ColumnType
+}
+
+// hasUUIDs reports whether any of a table's columns contain UUIDs.
+func (t table) hasUUIDs() bool {
+	for _, c := range t.Columns {
+		if c.Type.Key.Type == ovsdb.TypeUUID {
+			return true
+		}
+		if c.Type.Value != nil && c.Type.Value.Type == ovsdb.TypeUUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newTable creates a table from a TableSchema.
+func newTable(name string, ts ovsdb.TableSchema) (table, error) {
+	typ := identifier(name)
+	if typ == "" {
+		return table{}, fmt.Errorf("cannot generate identifier for table %q", name)
+	}
+
+	t := table{
+		Name:  name,
+		Type:  typ,
+		Const: "Table" + typ,
+	}
+
+	names := make([]string, 0, len(ts.Columns))
+	for n := range ts.Columns {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		field := identifier(n)
+		if field == "" {
+			return table{}, fmt.Errorf("cannot generate identifier for column %q in table %q", n, name)
+		}
+
+		// Avoid a collision with the field for the row's UUID.
+		if field == "UUID" {
+			field = "UUIDColumn"
+		}
+
+		ct := ts.Columns[n].Type
+		for _, b := range []*ovsdb.BaseType{&ct.Key, ct.Value} {
+			if b != nil && atomType(b.Type) == "" {
+				return table{}, fmt.Errorf("unknown type %q for column %q in table %q", b.Type, n, name)
+			}
+		}
+
+		t.Columns = append(t.Columns, column{
+			Name:  n,
+			Field: field,
+			Const: typ + "Column" + field,
+			Type:  ct,
+		})
+	}
+
+	return t, nil
+}
+
+// table generates the constants, type, and methods for a table.
+func (g *generator) table(t table) {
+	g.printf("// Names of the %s table and its columns.\n", t.Name)
+	g.printf("const (\n")
+	g.printf("\t%s = %q\n\n", t.Const, t.Name)
+	for _, c := range t.Columns {
+		g.printf("\t%s = %q\n", c.Const, c.Name)
+	}
+	g.printf(")\n\n")
+
+	g.printf("// %s is a row in the %s table.\n", t.Type, t.Name)
+	if t.hasUUIDs() {
+		g.printf("//\n// Fields which reference other rows contain either the UUID of an existing\n")
+		g.printf("// row, or the UUID name of a row inserted earlier in the same transaction.\n")
+	}
+	g.printf("type %s struct {\n", t.Type)
+	g.printf("\tUUID string `ovsdb:\"_uuid\"`\n\n")
+	for _, c := range t.Columns {
+		g.printf("\t%s %s `ovsdb:%q`\n", c.Field, goType(c.Type), c.Name)
+	}
+	g.printf("}\n\n")
+
+	g.printf("// Row converts a %s into an ovsdb.Row.  If one or more columns are\n", t.Type)
+	g.printf("// specified, only those columns are included in the ovsdb.Row.  The UUID\n")
+	g.printf("// field is never included.\n")
+	g.printf("func (r *%s) Row(columns ...string) ovsdb.Row {\n", t.Type)
+	g.printf("\trow := make(ovsdb.Row)\n")
+	g.printf("\tset := func(column string, v interface{}) {\n")
+	g.printf("\t\tif genWantColumn(columns, column) {\n\t\t\trow[column] = v\n\t\t}\n\t}\n\n")
+	for _, c := range t.Columns {
+		g.encode(c)
+	}
+	g.printf("\n\treturn row\n}\n\n")
+
+	g.printf("// UnmarshalRow populates a %s using the columns of an ovsdb.Row.\n", t.Type)
+	g.printf("// Columns which are not present in the ovsdb.Row are left unchanged.\n")
+	g.printf("func (r *%s) UnmarshalRow(row ovsdb.Row) error {\n", t.Type)
+	g.printf("\tif v, ok := row[\"_uuid\"]; ok {\n")
+	g.printf("\t\tu, err := genDecodeUUID(v)\n")
+	g.printf("\t\tif err != nil {\n\t\t\treturn fmt.Errorf(\"column %%q: %%v\", \"_uuid\", err)\n\t\t}\n")
+	g.printf("\t\tr.UUID = u\n\t}\n\n")
+	for _, c := range t.Columns {
+		g.decode(c)
+	}
+	g.printf("\treturn nil\n}\n\n")
+}
+
+// encode generates code which encodes a column's value into a Row.
+func (g *generator) encode(c column) {
+	ct := c.Type
+	key := encodeFunc(ct.Key)
+
+	switch {
+	case ct.IsMap():
+		g.printf("\t{\n\t\tpairs := make([]interface{}, 0, len(r.%s))\n", c.Field)
+		g.printf("\t\tfor k, v := range r.%s {\n", c.Field)
+		g.printf("\t\t\tpairs = append(pairs, []interface{}{%s(k), %s(v)})\n\t\t}\n", key, encodeFunc(*ct.Value))
+		g.printf("\t\tset(%s, []interface{}{\"map\", pairs})\n\t}\n", c.Const)
+	case ct.IsSet():
+		g.printf("\t{\n\t\telems := make([]interface{}, 0, len(r.%s))\n", c.Field)
+		g.printf("\t\tfor _, e := range r.%s {\n", c.Field)
+		g.printf("\t\t\telems = append(elems, %s(e))\n\t\t}\n", key)
+		g.printf("\t\tset(%s, []interface{}{\"set\", elems})\n\t}\n", c.Const)
+	case ct.IsOptional():
+		g.printf("\tif r.%s != nil {\n", c.Field)
+		g.printf("\t\tset(%s, %s(*r.%s))\n", c.Const, key, c.Field)
+		g.printf("\t} else {\n\t\tset(%s, []interface{}{\"set\", []interface{}{}})\n\t}\n", c.Const)
+	default:
+		g.printf("\tset(%s, %s(r.%s))\n", c.Const, key, c.Field)
+	}
+}
+
+// decode generates code which decodes a column's value from a Row.
+func (g *generator) decode(c column) {
+	ct := c.Type
+	key := decodeFunc(ct.Key)
+
+	g.printf("\tif v, ok := row[%s]; ok {\n", c.Const)
+	g.printf("\t\tif err := func() error {\n")
+
+	switch {
+	case ct.IsMap():
+		g.printf("\t\t\tpairs, err := genDecodeMap(v)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n")
+		g.printf("\t\t\tm := make(%s, len(pairs))\n", goType(ct))
+		g.printf("\t\t\tfor _, p := range pairs {\n")
+		g.printf("\t\t\t\tk, err := %s(p[0])\n\t\t\t\tif err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n", key)
+		g.printf("\t\t\t\tv, err := %s(p[1])\n\t\t\t\tif err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n", decodeFunc(*ct.Value))
+		g.printf("\t\t\t\tm[k] = v\n\t\t\t}\n\n")
+		g.printf("\t\t\tr.%s = m\n", c.Field)
+	case ct.IsSet():
+		g.printf("\t\t\telems, err := genDecodeSet(v)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n")
+		g.printf("\t\t\ts := make(%s, 0, len(elems))\n", goType(ct))
+		g.printf("\t\t\tfor _, e := range elems {\n")
+		g.printf("\t\t\t\tx, err := %s(e)\n\t\t\t\tif err != nil {\n\t\t\t\t\treturn err\n\t\t\t\t}\n", key)
+		g.printf("\t\t\t\ts = append(s, x)\n\t\t\t}\n\n")
+		g.printf("\t\t\tr.%s = s\n", c.Field)
+	case ct.IsOptional():
+		g.printf("\t\t\telems, err := genDecodeSet(v)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n")
+		g.printf("\t\t\tif len(elems) == 0 {\n\t\t\t\tr.%s = nil\n\t\t\t\treturn nil\n\t\t\t}\n\n", c.Field)
+		g.printf("\t\t\tx, err := %s(elems[0])\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n", key)
+		g.printf("\t\t\tr.%s = &x\n", c.Field)
+	default:
+		g.printf("\t\t\tx, err := %s(v)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n\n", key)
+		g.printf("\t\t\tr.%s = x\n", c.Field)
+	}
+
+	g.printf("\t\t\treturn nil\n")
+	g.printf("\t\t}(); err != nil {\n")
+	g.printf("\t\t\treturn fmt.Errorf(\"column %%q: %%v\", %s, err)\n", c.Const)
+	g.printf("\t\t}\n\t}\n\n")
+}
+
+// goType returns the Go type used to represent a column type.
+func goType(ct ovsdb.ColumnType) string {
+	key := atomType(ct.Key.Type)
+
+	switch {
+	case ct.IsMap():
+		return fmt.Sprintf("map[%s]%s", key, atomType(ct.Value.Type))
+	case ct.IsSet():
+		return "[]" + key
+	case ct.IsOptional():
+		return "*" + key
+	default:
+		return key
+	}
+}
+
+// atomType returns the Go type used to represent an OVSDB atomic type.
+func atomType(typ string) string {
+	switch typ {
+	case ovsdb.TypeInteger:
+		return "int"
+	case ovsdb.TypeReal:
+		return "float64"
+	case ovsdb.TypeBoolean:
+		return "bool"
+	case ovsdb.TypeString, ovsdb.TypeUUID:
+		return "string"
+	default:
+		return ""
+	}
+}
+
+// encodeFunc returns the name of the helper which encodes an atom of type b.
+func encodeFunc(b ovsdb.BaseType) string {
+	if b.Type == ovsdb.TypeUUID {
+		return "genEncodeUUID"
+	}
+
+	return "genEncodeAtom"
+}
+
+// decodeFunc returns the name of the helper which decodes an atom of type b.
+func decodeFunc(b ovsdb.BaseType) string {
+	return "genDecode" + identifier(b.Type)
+}
+
+// initialisms are words which should be capitalized entirely in Go
+// identifiers.  Plurals of these words are also capitalized, such as "IDs".
+var initialisms = map[string]string{
+	"acl":     "ACL",
+	"bfd":     "BFD",
+	"cfm":     "CFM",
+	"cpu":     "CPU",
+	"dhcp":    "DHCP",
+	"dns":     "DNS",
+	"dscp":    "DSCP",
+	"id":      "ID",
+	"ip":      "IP",
+	"ipv4":    "IPv4",
+	"ipv6":    "IPv6",
+	"lacp":    "LACP",
+	"mac":     "MAC",
+	"mtu":     "MTU",
+	"nat":     "NAT",
+	"nb":      "NB",
+	"ofport":  "OFPort",
+	"ofproto": "OFProto",
+	"qos":     "QoS",
+	"ssl":     "SSL",
+	"stp":     "STP",
+	"rstp":    "RSTP",
+	"sb":      "SB",
+	"tcp":     "TCP",
+	"udp":     "UDP",
+	"url":     "URL",
+	"uuid":    "UUID",
+	"vip":     "VIP",
+	"vlan":    "VLAN",
+	"vtep":    "VTEP",
+}
+
+// identifier converts an OVSDB table or column name into an exported Go
+// identifier, such as "external_ids" to "ExternalIDs".
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		lw := strings.ToLower(w)
+		if s, ok := initialisms[lw]; ok {
+			b.WriteString(s)
+			continue
+		}
+		if s, ok := initialisms[strings.TrimSuffix(lw, "s")]; ok && strings.HasSuffix(lw, "s") {
+			b.WriteString(s + "s")
+			continue
+		}
+
+		rs := []rune(w)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		return ""
+	}
+
+	return s
+}
+
+// packageName converts an OVSDB database name into a Go package name, such
+// as "Open_vSwitch" to "openvswitch".
+func packageName(name string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+
+		return r
+	}, name))
+}
+
+// helpers is the static source for helper functions used by generated code.
+const helpers = `
+// genWantColumn reports whether column appears in columns, or columns is
+// empty.
+func genWantColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// genEncodeAtom encodes an atom which requires no special encoding.
+func genEncodeAtom(v interface{}) interface{} {
+	return v
+}
+
+// genEncodeUUID encodes a UUID atom.  If s is not a UUID, it is assumed to be
+// the UUID name of a row inserted earlier in the same transaction.
+func genEncodeUUID(s string) interface{} {
+	if !genIsUUID(s) {
+		return ovsdb.NamedUUID(s)
+	}
+
+	return []interface{}{"uuid", s}
+}
+
+// genIsUUID reports whether s is in the canonical UUID format.
+func genIsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// genDecodeSet decodes a set, which may be encoded as a single atom.
+func genDecodeSet(v interface{}) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "set" {
+		// A set with one element may be encoded as the element itself.
+		return []interface{}{v}, nil
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set: %v", v)
+	}
+
+	return elems, nil
+}
+
+// genDecodeMap decodes a map into key/value pairs.
+func genDecodeMap(v interface{}) ([][2]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "map" {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	pairs := make([][2]interface{}, 0, len(elems))
+	for _, e := range elems {
+		p, ok := e.([]interface{})
+		if !ok || len(p) != 2 {
+			return nil, fmt.Errorf("invalid map pair: %v", e)
+		}
+
+		pairs = append(pairs, [2]interface{}{p[0], p[1]})
+	}
+
+	return pairs, nil
+}
+
+// genDecodeInteger decodes an integer atom.
+func genDecodeInteger(v interface{}) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case float64:
+		if x != float64(int(x)) {
+			return 0, fmt.Errorf("invalid integer: %v", v)
+		}
+
+		return int(x), nil
+	case json.Number:
+		n, err := x.Int64()
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("invalid integer: %v", v)
+	}
+}
+
+// genDecodeReal decodes a real atom.
+func genDecodeReal(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	default:
+		return 0, fmt.Errorf("invalid real: %v", v)
+	}
+}
+
+// genDecodeBoolean decodes a boolean atom.
+func genDecodeBoolean(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid boolean: %v", v)
+	}
+
+	return b, nil
+}
+
+// genDecodeString decodes a string atom.
+func genDecodeString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid string: %v", v)
+	}
+
+	return s, nil
+}
+
+// genDecodeUUID decodes a UUID atom.
+func genDecodeUUID(v interface{}) (string, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "uuid" {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	s, ok := a[1].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	return s, nil
+}
+`
@@ -0,0 +1,174 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{in: "name", out: "Name"},
+		{in: "external_ids", out: "ExternalIDs"},
+		{in: "Open_vSwitch", out: "OpenVSwitch"},
+		{in: "Flow_Sample_Collector_Set", out: "FlowSampleCollectorSet"},
+		{in: "sFlow", out: "SFlow"},
+		{in: "mac_in_use", out: "MACInUse"},
+		{in: "bfd-status", out: "BFDStatus"},
+		{in: "acls", out: "ACLs"},
+		{in: "qos", out: "QoS"},
+		{in: "ofport_request", out: "OFPortRequest"},
+		{in: "ofproto", out: "OFProto"},
+		{in: "status", out: "Status"},
+		{in: "NB_Global", out: "NBGlobal"},
+		{in: "sb_cfg", out: "SBCfg"},
+		{in: "vips", out: "VIPs"},
+		{in: "vtep_logical_switches", out: "VTEPLogicalSwitches"},
+		{in: "ipv6_ra_configs", out: "IPv6RaConfigs"},
+		{in: "_uuid", out: "UUID"},
+		{in: "1foo", out: ""},
+		{in: "", out: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if diff := cmp.Diff(tt.out, identifier(tt.in)); diff != "" {
+				t.Fatalf("unexpected identifier (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	if diff := cmp.Diff("ovnnorthbound", packageName("OVN_Northbound")); diff != "" {
+		t.Fatalf("unexpected package name (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	const schema = `{
+		"name": "Open_vSwitch",
+		"version": "7.15.1",
+		"tables": {
+			"Bridge": {
+				"columns": {
+					"name": {"type": "string"},
+					"ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+					"fail_mode": {"type": {"key": "string", "min": 0, "max": 1}},
+					"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+					"flood_vlans": {"type": {"key": "integer", "min": 0, "max": 4096}}
+				}
+			}
+		}
+	}`
+
+	var s ovsdb.Schema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	b, err := generate("vswitch", &s)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	src := string(b)
+
+	for _, want := range []string{
+		"package vswitch",
+		`DatabaseName = "Open_vSwitch"`,
+		`TableBridge = "Bridge"`,
+		`BridgeColumnExternalIDs = "external_ids"`,
+		"type Bridge struct {",
+		"ExternalIDs map[string]string `ovsdb:\"external_ids\"`",
+		"FailMode    *string           `ovsdb:\"fail_mode\"`",
+		"FloodVLANs  []int             `ovsdb:\"flood_vlans\"`",
+		"Ports       []string          `ovsdb:\"ports\"`",
+		"func (r *Bridge) Row(columns ...string) ovsdb.Row {",
+		"func (r *Bridge) UnmarshalRow(row ovsdb.Row) error {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Fatalf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateTestSchema(t *testing.T) {
+	// The generated code for this schema is checked in, and tested in its
+	// own package.
+	const dir = "internal/testschema"
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "testschema.ovsschema"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var s ovsdb.Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	got, err := generate("testschema", &s)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	want, err := ioutil.ReadFile(filepath.Join(dir, "testschema.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated code: %v", err)
+	}
+
+	// The license block is prepended after generating the code.
+	if i := bytes.Index(want, []byte("// Code generated")); i != -1 {
+		want = want[i:]
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Fatalf("generated code is out of date, run go generate in %s (-want +got):\n%s", dir, diff)
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	s := &ovsdb.Schema{
+		Name: "foo",
+		Tables: map[string]ovsdb.TableSchema{
+			"bar": {
+				Columns: map[string]ovsdb.ColumnSchema{
+					"baz": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{Type: "complex"},
+							Min: 1,
+							Max: 1,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := generate("foo", s); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
@@ -0,0 +1,920 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by ovsdbgen. DO NOT EDIT.
+
+// Package testschema contains bindings for the Open_vSwitch OVSDB schema, version 7.15.1.
+package testschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// DatabaseName is the name of the Open_vSwitch database.
+const DatabaseName = "Open_vSwitch"
+
+// Names of the Bridge table and its columns.
+const (
+	TableBridge = "Bridge"
+
+	BridgeColumnExternalIDs = "external_ids"
+	BridgeColumnFailMode    = "fail_mode"
+	BridgeColumnFloodVLANs  = "flood_vlans"
+	BridgeColumnName        = "name"
+	BridgeColumnPorts       = "ports"
+	BridgeColumnSTPEnable   = "stp_enable"
+)
+
+// Bridge is a row in the Bridge table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type Bridge struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	FailMode    *string           `ovsdb:"fail_mode"`
+	FloodVLANs  []int             `ovsdb:"flood_vlans"`
+	Name        string            `ovsdb:"name"`
+	Ports       []string          `ovsdb:"ports"`
+	STPEnable   bool              `ovsdb:"stp_enable"`
+}
+
+// Row converts a Bridge into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Bridge) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(BridgeColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	if r.FailMode != nil {
+		set(BridgeColumnFailMode, genEncodeAtom(*r.FailMode))
+	} else {
+		set(BridgeColumnFailMode, []interface{}{"set", []interface{}{}})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.FloodVLANs))
+		for _, e := range r.FloodVLANs {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(BridgeColumnFloodVLANs, []interface{}{"set", elems})
+	}
+	set(BridgeColumnName, genEncodeAtom(r.Name))
+	{
+		elems := make([]interface{}, 0, len(r.Ports))
+		for _, e := range r.Ports {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(BridgeColumnPorts, []interface{}{"set", elems})
+	}
+	set(BridgeColumnSTPEnable, genEncodeAtom(r.STPEnable))
+
+	return row
+}
+
+// UnmarshalRow populates a Bridge using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Bridge) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[BridgeColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[BridgeColumnFailMode]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.FailMode = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.FailMode = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnFailMode, err)
+		}
+	}
+
+	if v, ok := row[BridgeColumnFloodVLANs]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]int, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeInteger(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.FloodVLANs = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnFloodVLANs, err)
+		}
+	}
+
+	if v, ok := row[BridgeColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnName, err)
+		}
+	}
+
+	if v, ok := row[BridgeColumnPorts]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Ports = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnPorts, err)
+		}
+	}
+
+	if v, ok := row[BridgeColumnSTPEnable]; ok {
+		if err := func() error {
+			x, err := genDecodeBoolean(v)
+			if err != nil {
+				return err
+			}
+
+			r.STPEnable = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", BridgeColumnSTPEnable, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Interface table and its columns.
+const (
+	TableInterface = "Interface"
+
+	InterfaceColumnName       = "name"
+	InterfaceColumnOFPort     = "ofport"
+	InterfaceColumnStatistics = "statistics"
+)
+
+// Interface is a row in the Interface table.
+type Interface struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Name       string         `ovsdb:"name"`
+	OFPort     *int           `ovsdb:"ofport"`
+	Statistics map[string]int `ovsdb:"statistics"`
+}
+
+// Row converts a Interface into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Interface) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	set(InterfaceColumnName, genEncodeAtom(r.Name))
+	if r.OFPort != nil {
+		set(InterfaceColumnOFPort, genEncodeAtom(*r.OFPort))
+	} else {
+		set(InterfaceColumnOFPort, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Statistics))
+		for k, v := range r.Statistics {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(InterfaceColumnStatistics, []interface{}{"map", pairs})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a Interface using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Interface) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[InterfaceColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", InterfaceColumnName, err)
+		}
+	}
+
+	if v, ok := row[InterfaceColumnOFPort]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.OFPort = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.OFPort = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", InterfaceColumnOFPort, err)
+		}
+	}
+
+	if v, ok := row[InterfaceColumnStatistics]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]int, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeInteger(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Statistics = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", InterfaceColumnStatistics, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Port table and its columns.
+const (
+	TablePort = "Port"
+
+	PortColumnInterfaces = "interfaces"
+	PortColumnName       = "name"
+	PortColumnQoS        = "qos"
+	PortColumnTag        = "tag"
+)
+
+// Port is a row in the Port table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type Port struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Interfaces []string `ovsdb:"interfaces"`
+	Name       string   `ovsdb:"name"`
+	QoS        *string  `ovsdb:"qos"`
+	Tag        *int     `ovsdb:"tag"`
+}
+
+// Row converts a Port into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Port) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.Interfaces))
+		for _, e := range r.Interfaces {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(PortColumnInterfaces, []interface{}{"set", elems})
+	}
+	set(PortColumnName, genEncodeAtom(r.Name))
+	if r.QoS != nil {
+		set(PortColumnQoS, genEncodeUUID(*r.QoS))
+	} else {
+		set(PortColumnQoS, []interface{}{"set", []interface{}{}})
+	}
+	if r.Tag != nil {
+		set(PortColumnTag, genEncodeAtom(*r.Tag))
+	} else {
+		set(PortColumnTag, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a Port using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Port) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[PortColumnInterfaces]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Interfaces = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortColumnInterfaces, err)
+		}
+	}
+
+	if v, ok := row[PortColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortColumnName, err)
+		}
+	}
+
+	if v, ok := row[PortColumnQoS]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.QoS = nil
+				return nil
+			}
+
+			x, err := genDecodeUUID(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.QoS = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortColumnQoS, err)
+		}
+	}
+
+	if v, ok := row[PortColumnTag]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Tag = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Tag = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortColumnTag, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the QoS table and its columns.
+const (
+	TableQoS = "QoS"
+
+	QoSColumnOtherConfig = "other_config"
+	QoSColumnQueues      = "queues"
+	QoSColumnType        = "type"
+)
+
+// QoS is a row in the QoS table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type QoS struct {
+	UUID string `ovsdb:"_uuid"`
+
+	OtherConfig map[string]string `ovsdb:"other_config"`
+	Queues      map[int]string    `ovsdb:"queues"`
+	Type        string            `ovsdb:"type"`
+}
+
+// Row converts a QoS into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *QoS) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.OtherConfig))
+		for k, v := range r.OtherConfig {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(QoSColumnOtherConfig, []interface{}{"map", pairs})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Queues))
+		for k, v := range r.Queues {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeUUID(v)})
+		}
+		set(QoSColumnQueues, []interface{}{"map", pairs})
+	}
+	set(QoSColumnType, genEncodeAtom(r.Type))
+
+	return row
+}
+
+// UnmarshalRow populates a QoS using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *QoS) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[QoSColumnOtherConfig]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.OtherConfig = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", QoSColumnOtherConfig, err)
+		}
+	}
+
+	if v, ok := row[QoSColumnQueues]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[int]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeInteger(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeUUID(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Queues = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", QoSColumnQueues, err)
+		}
+	}
+
+	if v, ok := row[QoSColumnType]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Type = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", QoSColumnType, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Queue table and its columns.
+const (
+	TableQueue = "Queue"
+
+	QueueColumnBurst = "burst"
+	QueueColumnDSCP  = "dscp"
+)
+
+// Queue is a row in the Queue table.
+type Queue struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Burst *float64 `ovsdb:"burst"`
+	DSCP  *int     `ovsdb:"dscp"`
+}
+
+// Row converts a Queue into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Queue) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	if r.Burst != nil {
+		set(QueueColumnBurst, genEncodeAtom(*r.Burst))
+	} else {
+		set(QueueColumnBurst, []interface{}{"set", []interface{}{}})
+	}
+	if r.DSCP != nil {
+		set(QueueColumnDSCP, genEncodeAtom(*r.DSCP))
+	} else {
+		set(QueueColumnDSCP, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a Queue using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Queue) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[QueueColumnBurst]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Burst = nil
+				return nil
+			}
+
+			x, err := genDecodeReal(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Burst = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", QueueColumnBurst, err)
+		}
+	}
+
+	if v, ok := row[QueueColumnDSCP]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.DSCP = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.DSCP = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", QueueColumnDSCP, err)
+		}
+	}
+
+	return nil
+}
+
+// genWantColumn reports whether column appears in columns, or columns is
+// empty.
+func genWantColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// genEncodeAtom encodes an atom which requires no special encoding.
+func genEncodeAtom(v interface{}) interface{} {
+	return v
+}
+
+// genEncodeUUID encodes a UUID atom.  If s is not a UUID, it is assumed to be
+// the UUID name of a row inserted earlier in the same transaction.
+func genEncodeUUID(s string) interface{} {
+	if !genIsUUID(s) {
+		return ovsdb.NamedUUID(s)
+	}
+
+	return []interface{}{"uuid", s}
+}
+
+// genIsUUID reports whether s is in the canonical UUID format.
+func genIsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// genDecodeSet decodes a set, which may be encoded as a single atom.
+func genDecodeSet(v interface{}) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "set" {
+		// A set with one element may be encoded as the element itself.
+		return []interface{}{v}, nil
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set: %v", v)
+	}
+
+	return elems, nil
+}
+
+// genDecodeMap decodes a map into key/value pairs.
+func genDecodeMap(v interface{}) ([][2]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "map" {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	pairs := make([][2]interface{}, 0, len(elems))
+	for _, e := range elems {
+		p, ok := e.([]interface{})
+		if !ok || len(p) != 2 {
+			return nil, fmt.Errorf("invalid map pair: %v", e)
+		}
+
+		pairs = append(pairs, [2]interface{}{p[0], p[1]})
+	}
+
+	return pairs, nil
+}
+
+// genDecodeInteger decodes an integer atom.
+func genDecodeInteger(v interface{}) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case float64:
+		if x != float64(int(x)) {
+			return 0, fmt.Errorf("invalid integer: %v", v)
+		}
+
+		return int(x), nil
+	case json.Number:
+		n, err := x.Int64()
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("invalid integer: %v", v)
+	}
+}
+
+// genDecodeReal decodes a real atom.
+func genDecodeReal(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	default:
+		return 0, fmt.Errorf("invalid real: %v", v)
+	}
+}
+
+// genDecodeBoolean decodes a boolean atom.
+func genDecodeBoolean(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid boolean: %v", v)
+	}
+
+	return b, nil
+}
+
+// genDecodeString decodes a string atom.
+func genDecodeString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid string: %v", v)
+	}
+
+	return s, nil
+}
+
+// genDecodeUUID decodes a UUID atom.
+func genDecodeUUID(v interface{}) (string, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "uuid" {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	s, ok := a[1].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/cmd/ovsdbgen/internal/testschema"
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	uuid1 = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+	uuid2 = "ffe2a505-c7c5-4d51-a8fb-c4d0b9b4a927"
+)
+
+// A row is a generated type which converts to and from an ovsdb.Row.
+type row interface {
+	Row(columns ...string) ovsdb.Row
+	UnmarshalRow(row ovsdb.Row) error
+}
+
+func TestRowRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   row
+		out  row
+	}{
+		{
+			name: "bridge",
+			in: &testschema.Bridge{
+				UUID:        uuid1,
+				Name:        "br0",
+				Ports:       []string{uuid1, uuid2},
+				FailMode:    strPtr("secure"),
+				STPEnable:   true,
+				FloodVLANs:  []int{10, 20},
+				ExternalIDs: map[string]string{"foo": "bar", "baz": "qux"},
+			},
+			out: &testschema.Bridge{},
+		},
+		{
+			name: "bridge empty",
+			in: &testschema.Bridge{
+				UUID:        uuid1,
+				Name:        "br0",
+				Ports:       []string{},
+				FloodVLANs:  []int{},
+				ExternalIDs: map[string]string{},
+			},
+			out: &testschema.Bridge{},
+		},
+		{
+			name: "port",
+			in: &testschema.Port{
+				UUID:       uuid1,
+				Name:       "eth0",
+				Interfaces: []string{uuid2},
+				Tag:        intPtr(100),
+				QoS:        strPtr(uuid2),
+			},
+			out: &testschema.Port{},
+		},
+		{
+			name: "interface",
+			in: &testschema.Interface{
+				UUID:       uuid1,
+				Name:       "eth0",
+				OFPort:     intPtr(1),
+				Statistics: map[string]int{"rx_packets": 10, "tx_packets": 20},
+			},
+			out: &testschema.Interface{},
+		},
+		{
+			name: "QoS",
+			in: &testschema.QoS{
+				UUID:        uuid1,
+				Type:        "linux-htb",
+				Queues:      map[int]string{0: uuid1, 1: uuid2},
+				OtherConfig: map[string]string{"max-rate": "1000000"},
+			},
+			out: &testschema.QoS{},
+		},
+		{
+			name: "queue",
+			in: &testschema.Queue{
+				UUID:  uuid1,
+				DSCP:  intPtr(46),
+				Burst: float64Ptr(1.5),
+			},
+			out: &testschema.Queue{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Pass the row through JSON, as it would be sent to and
+			// received from an OVSDB server.
+			b, err := json.Marshal(tt.in.Row())
+			if err != nil {
+				t.Fatalf("failed to marshal row: %v", err)
+			}
+
+			var r ovsdb.Row
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatalf("failed to unmarshal row: %v", err)
+			}
+
+			r["_uuid"] = []interface{}{"uuid", uuid1}
+
+			if err := tt.out.UnmarshalRow(r); err != nil {
+				t.Fatalf("failed to unmarshal row: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.in, tt.out); diff != "" {
+				t.Fatalf("unexpected row (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRowColumns(t *testing.T) {
+	b := &testschema.Bridge{
+		Name:      "br0",
+		STPEnable: true,
+	}
+
+	want := ovsdb.Row{
+		testschema.BridgeColumnName: "br0",
+	}
+
+	if diff := cmp.Diff(want, b.Row(testschema.BridgeColumnName)); diff != "" {
+		t.Fatalf("unexpected row (-want +got):\n%s", diff)
+	}
+}
+
+func TestRowNamedUUID(t *testing.T) {
+	p := &testschema.Port{
+		Name:       "eth0",
+		Interfaces: []string{"iface0"},
+		QoS:        strPtr(uuid1),
+	}
+
+	row := p.Row(testschema.PortColumnInterfaces, testschema.PortColumnQoS)
+
+	b, err := json.Marshal(row)
+	if err != nil {
+		t.Fatalf("failed to marshal row: %v", err)
+	}
+
+	want := `{"interfaces":["set",[["named-uuid","iface0"]]],"qos":["uuid","` + uuid1 + `"]}`
+
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Fatalf("unexpected JSON (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalRowError(t *testing.T) {
+	tests := []struct {
+		name string
+		row  ovsdb.Row
+	}{
+		{
+			name: "UUID",
+			row:  ovsdb.Row{"_uuid": "foo"},
+		},
+		{
+			name: "string",
+			row:  ovsdb.Row{"name": 1.0},
+		},
+		{
+			name: "optional integer",
+			row:  ovsdb.Row{"tag": "foo"},
+		},
+		{
+			name: "set",
+			row:  ovsdb.Row{"interfaces": []interface{}{"set", "foo"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p testschema.Port
+			if err := p.UnmarshalRow(tt.row); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int             { return &i }
+func float64Ptr(f float64) *float64 { return &f }
+func strPtr(s string) *string       { return &s }
@@ -0,0 +1,84 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command ovsdbgen generates Go bindings for the tables of an OVSDB schema.
+//
+// For each table in the schema, ovsdbgen emits a Go struct with one field
+// per column, constants for the names of the table and its columns, and
+// methods which convert the struct to and from an ovsdb.Row.
+//
+// ovsdbgen is typically invoked using go:generate:
+//
+//	//go:generate ovsdbgen -p vswitch -o vswitch.go vswitch.ovsschema
+//
+// The generated file is self-contained, so only a single file should be
+// generated per Go package.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+func main() {
+	var (
+		pkg = flag.String("p", "", "name of the Go package for generated code (default: schema name)")
+		out = flag.String("o", "", "output file (default: stdout)")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: ovsdbgen [flags] schema.ovsschema\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	b, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("failed to read schema: %v", err)
+	}
+
+	var s ovsdb.Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		log.Fatalf("failed to parse schema: %v", err)
+	}
+
+	if *pkg == "" {
+		*pkg = packageName(s.Name)
+	}
+
+	src, err := generate(*pkg, &s)
+	if err != nil {
+		log.Fatalf("failed to generate code: %v", err)
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatalf("failed to write output: %v", err)
+	}
+}
@@ -46,6 +46,10 @@ var (
 	// negative integer port.
 	errOutputNegativePort = errors.New("output port number must not be negative")
 
+	// errEnqueueNegativePort is returned when Enqueue is called with a
+	// negative integer port.
+	errEnqueueNegativePort = errors.New("enqueue port number must not be negative")
+
 	// errResubmitPortTableZero is returned when Resubmit is called with
 	// both port and table value set to zero.
 	errResubmitPortTableZero = errors.New("both port and table are zero for action resubmit")
@@ -162,6 +166,9 @@ func StripVLAN() Action {
 const (
 	patConnectionTracking          = "ct(%s)"
 	patConjunction                 = "conjunction(%d,%d/%d)"
+	patEnqueue                     = "enqueue:%d:%d"
+	patGroup                       = "group:%d"
+	patMeter                       = "meter:%d"
 	patModDataLinkDestination      = "mod_dl_dst:%s"
 	patModDataLinkSource           = "mod_dl_src:%s"
 	patModNetworkDestination       = "mod_nw_dst:%s"
@@ -171,6 +178,7 @@ const (
 	patModVLANVID                  = "mod_vlan_vid:%d"
 	patOutput                      = "output:%d"
 	patResubmitPort                = "resubmit:%s"
+	patSetQueue                    = "set_queue:%d"
 	patResubmitPortTable           = "resubmit(%s,%s)"
 )
 
@@ -569,6 +577,104 @@ func (a *setTunnelAction) MarshalText() ([]byte, error) {
 	return bprintf("set_tunnel:%#x", a.tunnelID), nil
 }
 
+// GroupAction processes the packet through the specified OpenFlow group.  Groups
+// are managed using OpenFlowService.AddGroup and related methods.
+func GroupAction(id uint32) Action {
+	return &groupAction{
+		id: id,
+	}
+}
+
+// A groupAction is an Action used by GroupAction.
+type groupAction struct {
+	id uint32
+}
+
+// MarshalText implements Action.
+func (a *groupAction) MarshalText() ([]byte, error) {
+	return bprintf(patGroup, a.id), nil
+}
+
+// GoString implements Action.
+func (a *groupAction) GoString() string {
+	return fmt.Sprintf("ovs.GroupAction(%d)", a.id)
+}
+
+// SetQueue sets the queue used when the packet is output to a port.  Queues
+// are configured on ports using VSwitchService.SetPortQoS.
+func SetQueue(queue uint32) Action {
+	return &setQueueAction{
+		queue: queue,
+	}
+}
+
+// A setQueueAction is an Action used by SetQueue.
+type setQueueAction struct {
+	queue uint32
+}
+
+// MarshalText implements Action.
+func (a *setQueueAction) MarshalText() ([]byte, error) {
+	return bprintf(patSetQueue, a.queue), nil
+}
+
+// GoString implements Action.
+func (a *setQueueAction) GoString() string {
+	return fmt.Sprintf("ovs.SetQueue(%d)", a.queue)
+}
+
+// Enqueue outputs the packet to the specified queue of the specified port.
+// Queues are configured on ports using VSwitchService.SetPortQoS.
+func Enqueue(port int, queue uint32) Action {
+	return &enqueueAction{
+		port:  port,
+		queue: queue,
+	}
+}
+
+// An enqueueAction is an Action used by Enqueue.
+type enqueueAction struct {
+	port  int
+	queue uint32
+}
+
+// MarshalText implements Action.
+func (a *enqueueAction) MarshalText() ([]byte, error) {
+	if a.port < 0 {
+		return nil, errEnqueueNegativePort
+	}
+
+	return bprintf(patEnqueue, a.port, a.queue), nil
+}
+
+// GoString implements Action.
+func (a *enqueueAction) GoString() string {
+	return fmt.Sprintf("ovs.Enqueue(%d, %d)", a.port, a.queue)
+}
+
+// MeterAction processes the packet through the specified OpenFlow meter.
+// Meters are managed using OpenFlowService.AddMeter and related methods.
+func MeterAction(id uint32) Action {
+	return &meterAction{
+		id: id,
+	}
+}
+
+// A meterAction is an Action used by MeterAction.
+type meterAction struct {
+	id uint32
+}
+
+// MarshalText implements Action.
+func (a *meterAction) MarshalText() ([]byte, error) {
+	return bprintf(patMeter, a.id), nil
+}
+
+// GoString implements Action.
+func (a *meterAction) GoString() string {
+	return fmt.Sprintf("ovs.MeterAction(%d)", a.id)
+}
+
 // validARPOP indicates if an ARP OP is out of range. It should be in the range
 // 1-4.
 func validARPOP(op uint16) bool {
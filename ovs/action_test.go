@@ -65,6 +65,50 @@ func TestActionConstants(t *testing.T) {
 	}
 }
 
+func TestActionEnqueue(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		a      Action
+		action string
+		err    error
+	}{
+		{
+			desc:   "set_queue",
+			a:      SetQueue(1),
+			action: "set_queue:1",
+		},
+		{
+			desc: "enqueue port -1",
+			a:    Enqueue(-1, 1),
+			err:  errEnqueueNegativePort,
+		},
+		{
+			desc:   "enqueue port 10",
+			a:      Enqueue(10, 2),
+			action: "enqueue:10:2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			action, err := tt.a.MarshalText()
+
+			if want, got := errStr(tt.err), errStr(err); want != got {
+				t.Fatalf("unexpected error:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.action, string(action); want != got {
+				t.Fatalf("unexpected Action:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+		})
+	}
+}
+
 func TestActionCT(t *testing.T) {
 	var tests = []struct {
 		desc   string
@@ -648,6 +692,22 @@ func TestActionGoString(t *testing.T) {
 			a: Conjunction(123, 1, 2),
 			s: `ovs.Conjunction(123, 1, 2)`,
 		},
+		{
+			a: GroupAction(10),
+			s: `ovs.GroupAction(10)`,
+		},
+		{
+			a: MeterAction(1),
+			s: `ovs.MeterAction(1)`,
+		},
+		{
+			a: SetQueue(2),
+			s: `ovs.SetQueue(2)`,
+		},
+		{
+			a: Enqueue(1, 2),
+			s: `ovs.Enqueue(1, 2)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,6 +17,7 @@ package ovs
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -109,7 +110,10 @@ func (p *actionParser) parseAction() (Action, string, error) {
 		case '(':
 			p.s.push()
 		case ')':
-			p.s.pop()
+			if err := p.s.pop(); err != nil {
+				_, _ = buf.WriteRune(ch)
+				return nil, "", fmt.Errorf("invalid action: %q", buf.String())
+			}
 		}
 
 		_, _ = buf.WriteRune(ch)
@@ -138,9 +142,18 @@ func (s *stack) push() {
 	*s = append(*s, struct{}{})
 }
 
-// pop removes an element from the stack.
-func (s *stack) pop() {
+// errEmptyStack is returned when an element is popped from an empty stack.
+var errEmptyStack = errors.New("stack is empty")
+
+// pop removes an element from the stack.  It returns errEmptyStack if the
+// stack has no elements.
+func (s *stack) pop() error {
+	if s.len() == 0 {
+		return errEmptyStack
+	}
+
 	*s = (*s)[:s.len()-1]
+	return nil
 }
 
 var (
@@ -309,6 +322,55 @@ func parseAction(s string) (Action, error) {
 		}
 	}
 
+	// ActionGroup, with its group ID
+	if strings.HasPrefix(s, patGroup[:len(patGroup)-2]) {
+		var id uint32
+		n, err := fmt.Sscanf(s, patGroup, &id)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return GroupAction(id), nil
+		}
+	}
+
+	// ActionMeter, with its meter ID
+	if strings.HasPrefix(s, patMeter[:len(patMeter)-2]) {
+		var id uint32
+		n, err := fmt.Sscanf(s, patMeter, &id)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return MeterAction(id), nil
+		}
+	}
+
+	// ActionSetQueue, with its queue ID
+	if strings.HasPrefix(s, patSetQueue[:len(patSetQueue)-2]) {
+		var queue uint32
+		n, err := fmt.Sscanf(s, patSetQueue, &queue)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return SetQueue(queue), nil
+		}
+	}
+
+	// ActionEnqueue, with its port number and queue ID
+	if strings.HasPrefix(s, patEnqueue[:len(patEnqueue)-5]) {
+		var port int
+		var queue uint32
+		n, err := fmt.Sscanf(s, patEnqueue, &port, &queue)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return Enqueue(port, queue), nil
+		}
+	}
+
 	// ActionOutput, with its port number
 	if strings.HasPrefix(s, patOutput[:len(patOutput)-2]) {
 		var port int
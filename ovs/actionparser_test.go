@@ -33,6 +33,11 @@ func Test_actionParser(t *testing.T) {
 			in:      "strip_vlan,resubmit(",
 			invalid: true,
 		},
+		{
+			name:    "unmatched closing parenthesis",
+			in:      "strip_vlan,drop)",
+			invalid: true,
+		},
 		{
 			name: "one action",
 			in:   "strip_vlan",
@@ -297,6 +302,38 @@ func Test_parseAction(t *testing.T) {
 			s:       "conjunxxxxx(123,3/2)",
 			invalid: true,
 		},
+		{
+			s: "group:10",
+			a: GroupAction(10),
+		},
+		{
+			s:       "group:foo",
+			invalid: true,
+		},
+		{
+			s: "meter:1",
+			a: MeterAction(1),
+		},
+		{
+			s:       "meter:foo",
+			invalid: true,
+		},
+		{
+			s: "set_queue:2",
+			a: SetQueue(2),
+		},
+		{
+			s:       "set_queue:foo",
+			invalid: true,
+		},
+		{
+			s: "enqueue:1:2",
+			a: Enqueue(1, 2),
+		},
+		{
+			s:       "enqueue:1",
+			invalid: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -15,12 +15,61 @@
 package ovs
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 )
 
+// Targets for use with AppService.Target.
+const (
+	AppTargetVSwitchd      = "ovs-vswitchd"
+	AppTargetOVSDBServer   = "ovsdb-server"
+	AppTargetOVNNorthd     = "ovn-northd"
+	AppTargetOVNController = "ovn-controller"
+)
+
+// datapathFlowDumpHeader is the prefix of lines which describe the thread
+// whose flows follow, in the output of dpctl/dump-flows for userspace
+// datapaths.
+const datapathFlowDumpHeader = "flow-dump from"
+
+// fdbShowHeader is the prefix of the header line in the output of fdb/show.
+const fdbShowHeader = "port  VLAN  MAC"
+
+// Sentinel lines in the output of coverage/show.
+const (
+	coverageShowHeader   = "Event coverage"
+	coverageShowNeverHit = "events never hit"
+)
+
 // AppService runs commands that are available from ovs-appctl
 type AppService struct {
 	c *Client
+
+	// Daemon or control socket which receives commands, or the
+	// ovs-appctl default of ovs-vswitchd if empty.
+	target string
+}
+
+// Target returns a copy of a which sends its commands to the specified
+// target, such as AppTargetOVSDBServer.  The target may be the name of a
+// running Open vSwitch daemon, or the path to its control UNIX socket, such
+// as "/var/run/openvswitch/ovsdb-server.1234.ctl".
+//
+// Target is typically used once per command, such as:
+//
+//	out, err := c.App.Target(ovs.AppTargetOVSDBServer).Exec("memory/show")
+func (a *AppService) Target(target string) *AppService {
+	a2 := *a
+	a2.target = target
+	return &a2
+}
+
+// Exec runs an arbitrary ovs-appctl command with the specified arguments,
+// and returns its output.  Exec can be used to issue commands for which
+// AppService does not provide a typed wrapper.
+func (a *AppService) Exec(command string, args ...string) ([]byte, error) {
+	return a.exec(append([]string{command}, args...)...)
 }
 
 // ProtoTrace runs ovs-appctl ofproto/trace on the given bridge and match flow
@@ -56,7 +105,349 @@ func (a *AppService) ProtoTrace(bridge string, protocol Protocol, matches []Matc
 	return pt, nil
 }
 
-// exec executes 'ovs-appctl' + args passed in
+// DumpDatapathFlows retrieves the flows installed in the specified datapath,
+// such as "system@ovs-system", using 'ovs-appctl dpctl/dump-flows'.  If
+// datapath is empty, the flows in the only existing datapath are retrieved.
+func (a *AppService) DumpDatapathFlows(datapath string) ([]*DatapathFlow, error) {
+	args := []string{"dpctl/dump-flows"}
+	if datapath != "" {
+		args = append(args, datapath)
+	}
+
+	out, err := a.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []*DatapathFlow
+	for _, line := range strings.Split(string(out), "\n") {
+		// Userspace datapaths precede the flows of each thread with a
+		// line describing the thread.
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, datapathFlowDumpHeader) {
+			continue
+		}
+
+		f := new(DatapathFlow)
+		if err := f.UnmarshalText([]byte(line)); err != nil {
+			return nil, err
+		}
+
+		flows = append(flows, f)
+	}
+
+	return flows, nil
+}
+
+// DumpConntrack retrieves all connections tracked by the connection tracker
+// of the specified datapath, using 'ovs-appctl dpctl/dump-conntrack'.  If
+// datapath is empty, the connections of the only existing datapath are
+// retrieved.
+func (a *AppService) DumpConntrack(datapath string) ([]*ConntrackEntry, error) {
+	return a.dumpConntrack(datapath)
+}
+
+// DumpConntrackZone is like DumpConntrack, but only retrieves connections in
+// the specified conntrack zone.
+func (a *AppService) DumpConntrackZone(datapath string, zone uint16) ([]*ConntrackEntry, error) {
+	return a.dumpConntrack(datapath, fmt.Sprintf("zone=%d", zone))
+}
+
+// dumpConntrack retrieves the connections tracked by a datapath, passing the
+// specified additional arguments to dpctl/dump-conntrack.
+func (a *AppService) dumpConntrack(datapath string, args ...string) ([]*ConntrackEntry, error) {
+	// Verbose output includes the connections' status and timeouts.
+	cmd := []string{"dpctl/dump-conntrack", "-m"}
+	if datapath != "" {
+		cmd = append(cmd, datapath)
+	}
+	cmd = append(cmd, args...)
+
+	out, err := a.exec(cmd...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*ConntrackEntry
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		e := new(ConntrackEntry)
+		if err := e.UnmarshalText([]byte(line)); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// FlushConntrack flushes all connections tracked by the connection tracker
+// of the specified datapath, using 'ovs-appctl dpctl/flush-conntrack'.  If
+// datapath is empty, the connections of the only existing datapath are
+// flushed.
+func (a *AppService) FlushConntrack(datapath string) error {
+	return a.flushConntrack(datapath)
+}
+
+// FlushConntrackZone flushes the connections in the specified conntrack zone
+// which match filter.  If filter is nil, all connections in the zone are
+// flushed.
+func (a *AppService) FlushConntrackZone(datapath string, zone uint16, filter *ConntrackFilter) error {
+	args := []string{fmt.Sprintf("zone=%d", zone)}
+	if filter != nil {
+		b, err := filter.MarshalText()
+		if err != nil {
+			return err
+		}
+
+		args = append(args, string(b))
+	}
+
+	return a.flushConntrack(datapath, args...)
+}
+
+// flushConntrack flushes the connections tracked by a datapath, passing the
+// specified additional arguments to dpctl/flush-conntrack.
+func (a *AppService) flushConntrack(datapath string, args ...string) error {
+	cmd := []string{"dpctl/flush-conntrack"}
+	if datapath != "" {
+		cmd = append(cmd, datapath)
+	}
+	cmd = append(cmd, args...)
+
+	_, err := a.exec(cmd...)
+	return err
+}
+
+// DumpFDB retrieves the entries in the MAC learning table of the specified
+// bridge, using 'ovs-appctl fdb/show'.
+func (a *AppService) DumpFDB(bridge string) ([]*FDBEntry, error) {
+	out, err := a.exec("fdb/show", bridge)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(out), "\n")
+
+	// The first line must be a header which describes each column.
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), fdbShowHeader) {
+		return nil, ErrInvalidFDBEntry
+	}
+
+	var entries []*FDBEntry
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		e := new(FDBEntry)
+		if err := e.UnmarshalText([]byte(line)); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// ShowBond retrieves the status of the specified bonded port, using
+// 'ovs-appctl bond/show'.
+func (a *AppService) ShowBond(bond string) (*BondStatus, error) {
+	out, err := a.exec("bond/show", bond)
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(BondStatus)
+	if err := s.UnmarshalText(out); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ShowLACP retrieves the LACP status of the specified bonded port, using
+// 'ovs-appctl lacp/show'.
+func (a *AppService) ShowLACP(bond string) (*LACPStatus, error) {
+	out, err := a.exec("lacp/show", bond)
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(LACPStatus)
+	if err := s.UnmarshalText(out); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ShowCoverage retrieves the coverage counters of the AppService's target
+// which have been hit at least once, keyed by name, using
+// 'ovs-appctl coverage/show'.
+func (a *AppService) ShowCoverage() (map[string]*CoverageCounter, error) {
+	out, err := a.exec("coverage/show")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(out), "\n")
+
+	// The first line must be a header which describes each column.
+	if !strings.HasPrefix(lines[0], coverageShowHeader) {
+		return nil, ErrInvalidCoverageCounter
+	}
+
+	counters := make(map[string]*CoverageCounter)
+	for _, line := range lines[1:] {
+		// The final line summarizes the counters which were never hit.
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, coverageShowNeverHit) {
+			continue
+		}
+
+		c := new(CoverageCounter)
+		if err := c.UnmarshalText([]byte(line)); err != nil {
+			return nil, err
+		}
+
+		counters[c.Name] = c
+	}
+
+	return counters, nil
+}
+
+// ShowPMDStats retrieves packet and cycle counters for each PMD thread of a
+// userspace datapath, and for its main thread, using
+// 'ovs-appctl dpif-netdev/pmd-stats-show'.
+func (a *AppService) ShowPMDStats() ([]*PMDStats, error) {
+	out, err := a.exec("dpif-netdev/pmd-stats-show")
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*PMDStats
+	err = splitPMDThreads(out, ErrInvalidPMDStats, func(b []byte) error {
+		s := new(PMDStats)
+		if err := s.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	})
+
+	return stats, err
+}
+
+// ShowPMDRxQueues retrieves the receive queues assigned to each PMD thread of
+// a userspace datapath, using 'ovs-appctl dpif-netdev/pmd-rxq-show'.
+func (a *AppService) ShowPMDRxQueues() ([]*PMDRxQueues, error) {
+	out, err := a.exec("dpif-netdev/pmd-rxq-show")
+	if err != nil {
+		return nil, err
+	}
+
+	var rxqs []*PMDRxQueues
+	err = splitPMDThreads(out, ErrInvalidPMDRxQueues, func(b []byte) error {
+		q := new(PMDRxQueues)
+		if err := q.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		rxqs = append(rxqs, q)
+		return nil
+	})
+
+	return rxqs, err
+}
+
+// ListLogModules retrieves the logging modules of the AppService's target,
+// and the levels at which they log to each destination, using
+// 'ovs-appctl vlog/list'.
+func (a *AppService) ListLogModules() ([]*LogModule, error) {
+	out, err := a.exec("vlog/list")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLogModules(out)
+}
+
+// SetLogLevel sets the level at which module logs to destination, using
+// 'ovs-appctl vlog/set'.  If module is empty, the level of all modules is
+// set.  If destination is empty, the level for all destinations is set.
+func (a *AppService) SetLogLevel(module string, destination LogDestination, level LogLevel) error {
+	_, err := a.exec("vlog/set", logSpec(module, destination, level))
+	return err
+}
+
+// SetLogModules sets the levels of each of modules to those in their Levels
+// map, using a single 'ovs-appctl vlog/set' command.  SetLogModules can be
+// used to restore the levels retrieved by ListLogModules after they are
+// temporarily raised using SetLogLevel.
+func (a *AppService) SetLogModules(modules ...*LogModule) error {
+	args := []string{"vlog/set"}
+	for _, m := range modules {
+		// Sort destinations so the command is deterministic.
+		dests := make([]string, 0, len(m.Levels))
+		for d := range m.Levels {
+			dests = append(dests, string(d))
+		}
+		sort.Strings(dests)
+
+		for _, d := range dests {
+			args = append(args, logSpec(m.Name, LogDestination(d), m.Levels[LogDestination(d)]))
+		}
+	}
+
+	if len(args) == 1 {
+		return nil
+	}
+
+	_, err := a.exec(args...)
+	return err
+}
+
+// ShowSTP retrieves the Spanning Tree Protocol status of the specified
+// bridge, using 'ovs-appctl stp/show'.
+func (a *AppService) ShowSTP(bridge string) (*STPStatus, error) {
+	return a.showSTP("stp/show", bridge)
+}
+
+// ShowRSTP retrieves the Rapid Spanning Tree Protocol status of the
+// specified bridge, using 'ovs-appctl rstp/show'.
+func (a *AppService) ShowRSTP(bridge string) (*STPStatus, error) {
+	return a.showSTP("rstp/show", bridge)
+}
+
+// showSTP retrieves spanning tree status using the specified command.
+func (a *AppService) showSTP(cmd string, bridge string) (*STPStatus, error) {
+	out, err := a.exec(cmd, bridge)
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(STPStatus)
+	if err := s.UnmarshalText(out); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// exec executes 'ovs-appctl' + args passed in, directed at the AppService's
+// target, if one is set.
 func (a *AppService) exec(args ...string) ([]byte, error) {
+	if a.target != "" {
+		args = append([]string{"--target=" + a.target}, args...)
+	}
+
 	return a.c.exec("ovs-appctl", args...)
 }
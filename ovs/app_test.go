@@ -0,0 +1,685 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientAppExecOK(t *testing.T) {
+	wantOut := []byte("cache:...")
+
+	// Apply Timeout option to verify arguments
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		// Verify correct command and arguments passed, including option flags
+		if want, got := "ovs-appctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{"--timeout=1", "memory/show"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return wantOut, nil
+	})
+
+	out, err := c.App.Exec("memory/show")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.Exec: %v", err)
+	}
+
+	if want, got := wantOut, out; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected output:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppExecTargetOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"--target=ovsdb-server",
+			"ovsdb-server/compact",
+			"Open_vSwitch",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	app := c.App.Target(AppTargetOVSDBServer)
+	if _, err := app.Exec("ovsdb-server/compact", "Open_vSwitch"); err != nil {
+		t.Fatalf("unexpected error for Client.App.Exec: %v", err)
+	}
+
+	// The original AppService must be unaffected by Target.
+	if c.App.target != "" {
+		t.Fatalf("unexpected target for Client.App: %q", c.App.target)
+	}
+}
+
+func TestClientAppExecError(t *testing.T) {
+	wantErr := errors.New("no such command")
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return nil, wantErr
+	})
+
+	_, err := c.App.Exec("foo/bar")
+	if err == nil || !strings.Contains(err.Error(), wantErr.Error()) {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			wantErr, err)
+	}
+}
+
+func TestClientAppProtoTraceTargetOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"--target=/var/run/openvswitch/ovs-vswitchd.1.ctl",
+			"ofproto/trace",
+			"br0",
+			"ip,in_port=1",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte("Datapath actions: drop"), nil
+	})
+
+	pt, err := c.App.Target("/var/run/openvswitch/ovs-vswitchd.1.ctl").
+		ProtoTrace("br0", ProtocolIPv4, []Match{InPortMatch(1)})
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ProtoTrace: %v", err)
+	}
+
+	if want, got := NewDataPathActions("drop"), pt.DataPathActions; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected datapath actions:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppDumpDatapathFlowsOK(t *testing.T) {
+	want := []*DatapathFlow{
+		{
+			Keys: []DatapathFlowKey{
+				{Name: "recirc_id", Value: "0"},
+				{Name: "in_port", Value: "2"},
+			},
+			Actions: []string{"drop"},
+		},
+		{
+			Keys: []DatapathFlowKey{
+				{Name: "in_port", Value: "3"},
+			},
+			Actions: []string{"2"},
+			Packets: 1,
+			Bytes:   60,
+		},
+	}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"dpctl/dump-flows", "netdev@ovs-netdev"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`flow-dump from pmd on cpu core: 1
+recirc_id(0),in_port(2), packets:0, bytes:0, used:never, actions:drop
+flow-dump from the main thread:
+in_port(3), packets:1, bytes:60, used:never, actions:2
+`), nil
+	})
+
+	got, err := c.App.DumpDatapathFlows("netdev@ovs-netdev")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.DumpDatapathFlows: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected flows:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppDumpDatapathFlowsInvalid(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"dpctl/dump-flows"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte("ovs-vswitchd: no datapaths exist\n"), nil
+	})
+
+	if _, err := c.App.DumpDatapathFlows(""); err != ErrInvalidDatapathFlow {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			ErrInvalidDatapathFlow, err)
+	}
+}
+
+func TestClientAppDumpConntrackOK(t *testing.T) {
+	want := []*ConntrackEntry{{
+		Protocol: "udp",
+		Original: ConntrackTuple{
+			Src:     net.ParseIP("192.0.2.1"),
+			Dst:     net.ParseIP("192.0.2.2"),
+			SrcPort: 53,
+			DstPort: 53,
+		},
+		Reply: ConntrackTuple{
+			Src:     net.ParseIP("192.0.2.2"),
+			Dst:     net.ParseIP("192.0.2.1"),
+			SrcPort: 53,
+			DstPort: 53,
+		},
+		Zone: 5,
+	}}
+
+	var tests = []struct {
+		desc string
+		fn   func(a *AppService) ([]*ConntrackEntry, error)
+		args []string
+	}{
+		{
+			desc: "all zones",
+			fn: func(a *AppService) ([]*ConntrackEntry, error) {
+				return a.DumpConntrack("")
+			},
+			args: []string{"dpctl/dump-conntrack", "-m"},
+		},
+		{
+			desc: "single zone",
+			fn: func(a *AppService) ([]*ConntrackEntry, error) {
+				return a.DumpConntrackZone("system@ovs-system", 5)
+			},
+			args: []string{"dpctl/dump-conntrack", "-m", "system@ovs-system", "zone=5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return []byte("udp,orig=(src=192.0.2.1,dst=192.0.2.2,sport=53,dport=53),reply=(src=192.0.2.2,dst=192.0.2.1,sport=53,dport=53),zone=5\n"), nil
+			})
+
+			got, err := tt.fn(c.App)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected entries:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientAppFlushConntrackOK(t *testing.T) {
+	var tests = []struct {
+		desc string
+		fn   func(a *AppService) error
+		args []string
+	}{
+		{
+			desc: "all",
+			fn: func(a *AppService) error {
+				return a.FlushConntrack("")
+			},
+			args: []string{"dpctl/flush-conntrack"},
+		},
+		{
+			desc: "zone",
+			fn: func(a *AppService) error {
+				return a.FlushConntrackZone("system@ovs-system", 5, nil)
+			},
+			args: []string{"dpctl/flush-conntrack", "system@ovs-system", "zone=5"},
+		},
+		{
+			desc: "zone and tuple",
+			fn: func(a *AppService) error {
+				return a.FlushConntrackZone("", 1, &ConntrackFilter{
+					Protocol: ConntrackProtocolUDP,
+					Original: ConntrackTuple{
+						Src:     net.ParseIP("192.0.2.1"),
+						DstPort: 53,
+					},
+				})
+			},
+			args: []string{"dpctl/flush-conntrack", "zone=1", "ct_nw_src=192.0.2.1,ct_nw_proto=17,ct_tp_dst=53"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return nil, nil
+			})
+
+			if err := tt.fn(c.App); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientAppFlushConntrackZoneInvalidFilter(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if err := c.App.FlushConntrackZone("", 1, &ConntrackFilter{}); err != errEmptyConntrackFilter {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			errEmptyConntrackFilter, err)
+	}
+}
+
+func TestClientAppDumpFDBOK(t *testing.T) {
+	want := []*FDBEntry{
+		{
+			PortID: 1,
+			MAC:    net.HardwareAddr{0x50, 0x54, 0x00, 0x00, 0x00, 0x01},
+			Age:    1 * time.Second,
+		},
+		{
+			PortID: PortLOCAL,
+			VLAN:   10,
+			MAC:    net.HardwareAddr{0x50, 0x54, 0x00, 0x00, 0x00, 0x02},
+			Age:    3 * time.Second,
+		},
+	}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"fdb/show", "br0"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(` port  VLAN  MAC                Age
+    1     0  50:54:00:00:00:01    1
+LOCAL    10  50:54:00:00:00:02    3
+`), nil
+	})
+
+	got, err := c.App.DumpFDB("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.DumpFDB: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected entries:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppDumpFDBNoHeader(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return []byte("    1     0  50:54:00:00:00:01    1\n"), nil
+	})
+
+	if _, err := c.App.DumpFDB("br0"); err != ErrInvalidFDBEntry {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			ErrInvalidFDBEntry, err)
+	}
+}
+
+func TestClientAppShowBondOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"bond/show", "bond0"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte("---- bond0 ----\nbond_mode: active-backup\n"), nil
+	})
+
+	s, err := c.App.ShowBond("bond0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ShowBond: %v", err)
+	}
+
+	if want, got := (&BondStatus{Name: "bond0", Mode: "active-backup"}), s; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected bond status:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppShowLACPOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"lacp/show", "bond0"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte("---- bond0 ----\n\tstatus: passive\n\tlacp_time: fast\n"), nil
+	})
+
+	s, err := c.App.ShowLACP("bond0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ShowLACP: %v", err)
+	}
+
+	if want, got := (&LACPStatus{Name: "bond0", Time: "fast"}), s; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected LACP status:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppShowCoverageOK(t *testing.T) {
+	want := map[string]*CoverageCounter{
+		"bridge_reconfigure": {
+			Name:  "bridge_reconfigure",
+			Total: 3,
+		},
+		"upcall_flow_limit_hit": {
+			Name:       "upcall_flow_limit_hit",
+			RateSecond: 100,
+			RateMinute: 50.5,
+			RateHour:   1.25,
+			Total:      12345,
+		},
+	}
+
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"--timeout=1", "--target=ovsdb-server", "coverage/show"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`Event coverage, avg rate over last: 5 seconds, last minute, last hour,  hash=2d9b7a4d:
+bridge_reconfigure         0.0/sec     0.000/sec        0.0000/sec   total: 3
+upcall_flow_limit_hit    100.0/sec    50.500/sec        1.2500/sec   total: 12345
+92 events never hit
+`), nil
+	})
+
+	got, err := c.App.Target(AppTargetOVSDBServer).ShowCoverage()
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ShowCoverage: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected coverage counters:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppShowCoverageNoHeader(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return []byte("bridge_reconfigure  0.0/sec  0.000/sec  0.0000/sec  total: 3\n"), nil
+	})
+
+	if _, err := c.App.ShowCoverage(); err != ErrInvalidCoverageCounter {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			ErrInvalidCoverageCounter, err)
+	}
+}
+
+func TestClientAppShowPMDStatsOK(t *testing.T) {
+	want := []*PMDStats{
+		{
+			PMDThread:       PMDThread{NUMAID: 0, CoreID: 1},
+			PacketsReceived: 10,
+			EMCHits:         10,
+		},
+		{
+			PMDThread:       PMDThread{MainThread: true},
+			PacketsReceived: 1,
+		},
+	}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"dpif-netdev/pmd-stats-show"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`pmd thread numa_id 0 core_id 1:
+  packets received: 10
+  emc hits: 10
+main thread:
+  packets received: 1
+`), nil
+	})
+
+	got, err := c.App.ShowPMDStats()
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ShowPMDStats: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected PMD statistics:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppShowPMDRxQueuesOK(t *testing.T) {
+	want := []*PMDRxQueues{
+		{
+			PMDThread: PMDThread{NUMAID: 0, CoreID: 1},
+			Queues: []PMDRxQueue{{
+				Port:  "dpdk0",
+				Usage: 5,
+			}},
+		},
+		{
+			PMDThread: PMDThread{NUMAID: 1, CoreID: 9},
+			Isolated:  true,
+		},
+	}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"dpif-netdev/pmd-rxq-show"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`pmd thread numa_id 0 core_id 1:
+  isolated : false
+  port: dpdk0  queue-id:  0 (enabled)  pmd usage:  5 %
+pmd thread numa_id 1 core_id 9:
+  isolated : true
+`), nil
+	})
+
+	got, err := c.App.ShowPMDRxQueues()
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ShowPMDRxQueues: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected PMD receive queues:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppShowPMDStatsNotUserspace(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return []byte("please specify an existing datapath\n"), nil
+	})
+
+	if _, err := c.App.ShowPMDStats(); err != ErrInvalidPMDStats {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			ErrInvalidPMDStats, err)
+	}
+}
+
+func TestClientAppListLogModulesOK(t *testing.T) {
+	want := []*LogModule{{
+		Name: "bfd",
+		Levels: map[LogDestination]LogLevel{
+			LogDestinationConsole: LogLevelOff,
+			LogDestinationSyslog:  LogLevelErr,
+			LogDestinationFile:    LogLevelInfo,
+		},
+	}}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"vlog/list"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`                 console    syslog    file
+                 -------    ------    ------
+bfd                OFF        ERR       INFO
+`), nil
+	})
+
+	got, err := c.App.ListLogModules()
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.ListLogModules: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected log modules:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientAppSetLogLevelOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"vlog/set", "ofproto:file:dbg"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.App.SetLogLevel("ofproto", LogDestinationFile, LogLevelDebug); err != nil {
+		t.Fatalf("unexpected error for Client.App.SetLogLevel: %v", err)
+	}
+}
+
+func TestClientAppSetLogModulesOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"vlog/set",
+			"bfd:console:off",
+			"bfd:file:info",
+			"bfd:syslog:err",
+			"ofproto:file:warn",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	err := c.App.SetLogModules(
+		&LogModule{
+			Name: "bfd",
+			Levels: map[LogDestination]LogLevel{
+				LogDestinationSyslog:  LogLevelErr,
+				LogDestinationFile:    LogLevelInfo,
+				LogDestinationConsole: LogLevelOff,
+			},
+		},
+		&LogModule{
+			Name: "ofproto",
+			Levels: map[LogDestination]LogLevel{
+				LogDestinationFile: LogLevelWarn,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error for Client.App.SetLogModules: %v", err)
+	}
+}
+
+func TestClientAppSetLogModulesEmpty(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if err := c.App.SetLogModules(); err != nil {
+		t.Fatalf("unexpected error for Client.App.SetLogModules: %v", err)
+	}
+}
+
+func TestClientAppShowSTPOK(t *testing.T) {
+	var tests = []struct {
+		desc string
+		fn   func(a *AppService) (*STPStatus, error)
+		cmd  string
+	}{
+		{
+			desc: "STP",
+			fn: func(a *AppService) (*STPStatus, error) {
+				return a.ShowSTP("br0")
+			},
+			cmd: "stp/show",
+		},
+		{
+			desc: "RSTP",
+			fn: func(a *AppService) (*STPStatus, error) {
+				return a.ShowRSTP("br0")
+			},
+			cmd: "rstp/show",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := []string{tt.cmd, "br0"}, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return []byte("---- br0 ----\nRoot ID:\n\tThis bridge is the root\n"), nil
+			})
+
+			s, err := tt.fn(c.App)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if want, got := (&STPStatus{Bridge: "br0", IsRoot: true}), s; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected STP status:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBondStatus is returned when bond status output from
+// 'ovs-appctl bond/show' is not in the expected format.
+var ErrInvalidBondStatus = errors.New("invalid bond status")
+
+// A BondStatus is the status of a bonded port, as reported by
+// 'ovs-appctl bond/show'.
+type BondStatus struct {
+	// Name is the name of the bond.
+	Name string
+
+	// Mode is the bond mode, such as "active-backup" or "balance-tcp".
+	Mode string
+
+	// HashBasis is the basis used when hashing flows to members.
+	HashBasis uint32
+
+	// UpDelay and DownDelay are the times for which a member's link must
+	// be up or down before the member is enabled or disabled.
+	UpDelay   time.Duration
+	DownDelay time.Duration
+
+	// NextRebalance is the time until flows are next rebalanced between
+	// members, or zero if the bond mode does not rebalance.
+	NextRebalance time.Duration
+
+	// LACPStatus is the status of LACP on the bond, such as "negotiated",
+	// "configured", or "off".
+	LACPStatus string
+
+	// ActiveMember and ActiveMemberMAC identify the active member of the
+	// bond, if there is one.
+	ActiveMember    string
+	ActiveMemberMAC net.HardwareAddr
+
+	// Members contains the status of each member of the bond.
+	Members []BondMember
+}
+
+// A BondMember is the status of a single member of a bond.  Versions of
+// Open vSwitch prior to 2.14 refer to bond members as slaves.
+type BondMember struct {
+	Name string
+
+	// Enabled reports whether the member may be used to forward traffic,
+	// and MayEnable whether the member's link is eligible to be enabled.
+	Enabled   bool
+	MayEnable bool
+
+	// Active reports whether the member is the active member of the bond.
+	Active bool
+
+	// Hashes contains the flow hashes assigned to the member, used to
+	// balance traffic between members.
+	Hashes []BondHash
+}
+
+// A BondHash is a flow hash assigned to a bond member, and the load it
+// recently carried.
+type BondHash struct {
+	Hash int
+
+	// Load is the number of bytes recently transmitted by flows with the
+	// hash.
+	Load uint64
+}
+
+// UnmarshalText unmarshals a BondStatus from textual form as output by
+// 'ovs-appctl bond/show':
+//
+//	---- bond0 ----
+//	bond_mode: balance-slb
+//	bond-hash-basis: 0
+//	updelay: 0 ms
+//	downdelay: 0 ms
+//	next rebalance: 6817 ms
+//	lacp_status: off
+//	active slave mac: 00:00:00:00:00:01(eth0)
+//
+//	slave eth0: enabled
+//		active slave
+//		may_enable: true
+//		hash 12: 3 kB load
+func (s *BondStatus) UnmarshalText(b []byte) error {
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		bondMode      = "bond_mode"
+		hashBasis     = "bond-hash-basis"
+		upDelay       = "updelay"
+		downDelay     = "downdelay"
+		nextRebalance = "next rebalance"
+		lacpStatus    = "lacp_status"
+		mayEnable     = "may_enable"
+		enabled       = "enabled"
+	)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	name, ok := parseStatusHeader(lines[0])
+	if !ok {
+		return ErrInvalidBondStatus
+	}
+
+	status := BondStatus{
+		Name: name,
+	}
+
+	// member points to the member whose status is being parsed, or is nil
+	// while parsing the bond's own status.
+	var member *BondMember
+
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Each member's status begins with a line such as
+		// "member eth0: enabled".
+		if n, v, ok := parseBondMemberLine(line); ok {
+			status.Members = append(status.Members, BondMember{
+				Name:    n,
+				Enabled: v == enabled,
+			})
+			member = &status.Members[len(status.Members)-1]
+			continue
+		}
+
+		if member != nil && isBondMemberWord(line, "active ") {
+			member.Active = true
+			continue
+		}
+
+		if member != nil && strings.HasPrefix(line, "hash ") {
+			h, err := parseBondHash(line)
+			if err != nil {
+				return err
+			}
+
+			member.Hashes = append(member.Hashes, h)
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			// Ignore informational lines which are not key/value pairs.
+			continue
+		}
+
+		var err error
+		switch k, v := kv[0], kv[1]; {
+		case member != nil:
+			if k == mayEnable {
+				member.MayEnable = v == "true"
+			}
+		case k == bondMode:
+			status.Mode = v
+		case k == hashBasis:
+			var n uint64
+			n, err = strconv.ParseUint(v, 10, 32)
+			status.HashBasis = uint32(n)
+		case k == upDelay:
+			status.UpDelay, err = parseMilliseconds(v)
+		case k == downDelay:
+			status.DownDelay, err = parseMilliseconds(v)
+		case k == nextRebalance:
+			status.NextRebalance, err = parseMilliseconds(v)
+		case k == lacpStatus:
+			status.LACPStatus = v
+		case isBondMemberWord(k, "active ") && strings.HasSuffix(k, " mac"):
+			status.ActiveMemberMAC, status.ActiveMember, err = parseBondActiveMember(v)
+		default:
+			// Ignore additional information reported by newer versions
+			// of OVS.
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*s = status
+	return nil
+}
+
+// parseStatusHeader parses the name from a header line of the form
+// "---- name ----", as output by bond/show and lacp/show.
+func parseStatusHeader(s string) (string, bool) {
+	const sep = "----"
+
+	s = strings.TrimSpace(s)
+	if len(s) <= 2*len(sep) || !strings.HasPrefix(s, sep) || !strings.HasSuffix(s, sep) {
+		return "", false
+	}
+
+	return strings.TrimSpace(s[len(sep) : len(s)-len(sep)]), true
+}
+
+// isBondMemberWord reports whether s begins with prefix followed by the word
+// used to refer to bond members, which is "slave" in versions of Open
+// vSwitch prior to 2.14, and "member" otherwise.
+func isBondMemberWord(s string, prefix string) bool {
+	if !strings.HasPrefix(s, prefix) {
+		return false
+	}
+
+	s = s[len(prefix):]
+	return strings.HasPrefix(s, "member") || strings.HasPrefix(s, "slave")
+}
+
+// parseBondMemberLine parses the name and state of a bond member from a line
+// of the form "member eth0: enabled".
+func parseBondMemberLine(s string) (string, string, bool) {
+	if !isBondMemberWord(s, "") {
+		return "", "", false
+	}
+
+	ss := strings.Fields(s)
+	if len(ss) != 3 || !strings.HasSuffix(ss[1], ":") {
+		return "", "", false
+	}
+
+	return strings.TrimSuffix(ss[1], ":"), ss[2], true
+}
+
+// parseBondHash parses a BondHash from a line of the form
+// "hash 12: 3 kB load".
+func parseBondHash(s string) (BondHash, error) {
+	ss := strings.Fields(s)
+	if len(ss) != 5 || ss[3] != "kB" {
+		return BondHash{}, ErrInvalidBondStatus
+	}
+
+	h, err := strconv.Atoi(strings.TrimSuffix(ss[1], ":"))
+	if err != nil {
+		return BondHash{}, err
+	}
+
+	kb, err := strconv.ParseUint(ss[2], 10, 64)
+	if err != nil {
+		return BondHash{}, err
+	}
+
+	return BondHash{
+		Hash: h,
+		Load: kb * 1024,
+	}, nil
+}
+
+// parseBondActiveMember parses the MAC address and name of a bond's active
+// member from a value of the form "00:00:00:00:00:01(eth0)".
+func parseBondActiveMember(s string) (net.HardwareAddr, string, error) {
+	i := strings.Index(s, "(")
+	if i == -1 || !strings.HasSuffix(s, ")") {
+		return nil, "", ErrInvalidBondStatus
+	}
+
+	name := s[i+1 : len(s)-1]
+	if name == "none" {
+		return nil, "", nil
+	}
+
+	mac, err := net.ParseMAC(s[:i])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return mac, name, nil
+}
+
+// parseMilliseconds parses a time.Duration from a value of the form
+// "100 ms".
+func parseMilliseconds(s string) (time.Duration, error) {
+	if !strings.HasSuffix(s, " ms") {
+		return 0, ErrInvalidBondStatus
+	}
+
+	ms, err := strconv.Atoi(strings.TrimSuffix(s, " ms"))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBondStatusUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		b    *BondStatus
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "no header",
+			s:    "bond_mode: active-backup\n",
+		},
+		{
+			desc: "invalid delay",
+			s:    "---- bond0 ----\nupdelay: 100\n",
+		},
+		{
+			desc: "invalid hash",
+			s:    "---- bond0 ----\n\nslave eth0: enabled\n\thash 12: 3 MB load\n",
+		},
+		{
+			desc: "invalid active member",
+			s:    "---- bond0 ----\nactive slave mac: 00:00:00:00:00:01\n",
+		},
+		{
+			desc: "slaves",
+			s: `---- bond0 ----
+bond_mode: balance-tcp
+bond may use recirculation: yes, Recirc-ID : 1
+bond-hash-basis: 10
+updelay: 100 ms
+downdelay: 200 ms
+next rebalance: 6817 ms
+lacp_status: negotiated
+lacp_fallback_ab: false
+active slave mac: 00:00:00:00:00:01(eth0)
+
+slave eth0: enabled
+	active slave
+	may_enable: true
+	hash 12: 3 kB load
+	hash 200: 0 kB load
+
+slave eth1: disabled
+	may_enable: false
+`,
+			b: &BondStatus{
+				Name:            "bond0",
+				Mode:            "balance-tcp",
+				HashBasis:       10,
+				UpDelay:         100 * time.Millisecond,
+				DownDelay:       200 * time.Millisecond,
+				NextRebalance:   6817 * time.Millisecond,
+				LACPStatus:      "negotiated",
+				ActiveMember:    "eth0",
+				ActiveMemberMAC: net.HardwareAddr{0, 0, 0, 0, 0, 1},
+				Members: []BondMember{
+					{
+						Name:      "eth0",
+						Enabled:   true,
+						MayEnable: true,
+						Active:    true,
+						Hashes: []BondHash{
+							{Hash: 12, Load: 3 * 1024},
+							{Hash: 200},
+						},
+					},
+					{
+						Name: "eth1",
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			desc: "members, no active member",
+			s: `---- bond1 ----
+bond_mode: active-backup
+updelay: 0 ms
+downdelay: 0 ms
+lacp_status: off
+active-backup primary: <none>
+active member mac: 00:00:00:00:00:00(none)
+
+member eth2: disabled
+  may_enable: false
+`,
+			b: &BondStatus{
+				Name:       "bond1",
+				Mode:       "active-backup",
+				LACPStatus: "off",
+				Members: []BondMember{
+					{
+						Name: "eth2",
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b := new(BondStatus)
+			err := b.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.b, b; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected BondStatus:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
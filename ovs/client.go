@@ -16,6 +16,7 @@ package ovs
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -49,11 +50,46 @@ type Client struct {
 	// Prefix all commands with "sudo".
 	sudo bool
 
-	// Implementation of ExecFunc.
-	execFunc ExecFunc
+	// Context used to cancel commands, set by WithContext.
+	ctx context.Context
 
-	// Implementation of PipeFunc.
-	pipeFunc PipeFunc
+	// Implementation of ExecContextFunc.
+	execFunc ExecContextFunc
+
+	// Implementation of PipeContextFunc.
+	pipeFunc PipeContextFunc
+
+	// Implementation of StreamFunc.
+	streamFunc StreamFunc
+}
+
+// WithContext returns a copy of c which uses ctx for all of its commands.  If
+// ctx is canceled or its deadline expires, commands which are running are
+// killed, further commands are not started, and ctx.Err() is returned.
+//
+// WithContext is typically used once per operation, such as:
+//
+//	err := c.WithContext(ctx).VSwitch.AddBridge("br0")
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("ovs: nil context")
+	}
+
+	c2 := *c
+	c2.ctx = ctx
+	c2.init()
+
+	return &c2
+}
+
+// context returns the Client's context, or context.Background if none was
+// set by WithContext.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
 }
 
 // An ExecFunc is a function which accepts input arguments and returns raw
@@ -61,17 +97,26 @@ type Client struct {
 // without OVS installed.
 type ExecFunc func(cmd string, args ...string) ([]byte, error)
 
-// shellExec is an ExecFunc which shells out to the binary cmd using the
-// arguments args, and returns its combined stdout and stderr and any errors
-// which may have occurred.
-func shellExec(cmd string, args ...string) ([]byte, error) {
-	return exec.Command(cmd, args...).CombinedOutput()
+// An ExecContextFunc is like an ExecFunc, but also accepts the context set
+// using Client.WithContext, which should be used to stop the command.
+type ExecContextFunc func(ctx context.Context, cmd string, args ...string) ([]byte, error)
+
+// shellExec is an ExecContextFunc which shells out to the binary cmd using
+// the arguments args, and returns its combined stdout and stderr and any
+// errors which may have occurred.  The process is killed if ctx is canceled.
+func shellExec(ctx context.Context, cmd string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, cmd, args...).CombinedOutput()
 }
 
-// exec executes an ExecFunc using the values from cmd and args.
-// The ExecFunc may shell out to an appropriate binary, or may be swapped
-// for testing.
+// exec executes an ExecContextFunc using the values from cmd and args.
+// The ExecContextFunc may shell out to an appropriate binary, or may be
+// swapped for testing.
 func (c *Client) exec(cmd string, args ...string) ([]byte, error) {
+	ctx := c.context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Prepend recurring flags before arguments
 	flags := append(c.flags, args...)
 
@@ -85,12 +130,17 @@ func (c *Client) exec(cmd string, args ...string) ([]byte, error) {
 
 	// Execute execFunc with all flags and clean up any whitespace or
 	// newlines from its output.
-	out, err := c.execFunc(cmd, flags...)
+	out, err := c.execFunc(ctx, cmd, flags...)
 	if out != nil {
 		out = bytes.TrimSpace(out)
 		c.debugf("exec: %q", string(out))
 	}
 	if err != nil {
+		// A command killed because ctx is done reports the context's error.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		// Wrap errors in Error type for further introspection
 		return nil, &Error{
 			Out: out,
@@ -106,10 +156,15 @@ func (c *Client) exec(cmd string, args ...string) ([]byte, error) {
 // swappable to enable testing without OVS installed.
 type PipeFunc func(stdin io.Reader, cmd string, args ...string) ([]byte, error)
 
-// shellPipe is a PipeFunc which shells out to the binary cmd using the arguments
-// args, and writing to the command's stdin using stdin.
-func shellPipe(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
-	command := exec.Command(cmd, args...)
+// A PipeContextFunc is like a PipeFunc, but also accepts the context set
+// using Client.WithContext, which should be used to stop the command.
+type PipeContextFunc func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error)
+
+// shellPipe is a PipeContextFunc which shells out to the binary cmd using the
+// arguments args, and writing to the command's stdin using stdin.  The
+// process is killed if ctx is canceled.
+func shellPipe(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+	command := exec.CommandContext(ctx, cmd, args...)
 
 	stdout, err := command.StdoutPipe()
 	if err != nil {
@@ -153,8 +208,13 @@ func shellPipe(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
 // pipe executes a PipeFunc using the values from stdin, cmd, and args.
 // stdin is used to feed input data to the stdin of a forked process.
 // The PipeFunc may shell out to an appropriate binary, or may be swapped
-// for testing.
-func (c *Client) pipe(stdin io.Reader, cmd string, args ...string) error {
+// for testing.  The combined stdout/stderr of the process is returned.
+func (c *Client) pipe(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+	ctx := c.context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Prepend recurring flags before arguments
 	flags := append(c.flags, args...)
 
@@ -172,16 +232,99 @@ func (c *Client) pipe(stdin io.Reader, cmd string, args ...string) error {
 		return len(p), nil
 	}))
 
-	if out, err := c.pipeFunc(tr, cmd, flags...); err != nil {
+	out, err := c.pipeFunc(ctx, tr, cmd, flags...)
+	if err != nil {
 		c.debugf("pipe error: %v: %q", err, string(out))
-		return &pipeError{
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, &pipeError{
 			out: out,
 			err: err,
 		}
 	}
 
+	return out, nil
+}
+
+// A StreamFunc is a function which starts a long-running command, such as
+// 'ovs-ofctl monitor', and returns its stdout for reading as the command
+// produces output.  Closing the returned io.ReadCloser stops the command if
+// it is still running, and waits for it to exit.  If all of the command's
+// output was read, any error from the command is returned.  The command
+// should also be stopped if ctx is canceled.
+type StreamFunc func(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error)
+
+// shellStream is a StreamFunc which shells out to the binary cmd using the
+// arguments args.
+func shellStream(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+	command := exec.CommandContext(ctx, cmd, args...)
+
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+
+	return &cmdStream{
+		ReadCloser: stdout,
+		cmd:        command,
+	}, nil
+}
+
+// A cmdStream is an io.ReadCloser returned by shellStream.
+type cmdStream struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+	eof bool
+}
+
+// Read implements io.Reader.
+func (s *cmdStream) Read(p []byte) (int, error) {
+	n, err := s.ReadCloser.Read(p)
+	if err == io.EOF {
+		s.eof = true
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (s *cmdStream) Close() error {
+	// Once the command has closed its output, it is exiting on its own, and
+	// its exit status is of interest to the caller.
+	if s.eof {
+		return s.cmd.Wait()
+	}
+
+	// Otherwise the caller is done reading, and the error caused by killing
+	// the command is not reported.
+	_ = s.cmd.Process.Kill()
+	_ = s.cmd.Wait()
 	return nil
+}
+
+// stream executes a StreamFunc using the values from ctx, cmd, and args.
+// The Client's recurring flags, such as its timeout, are not applied, because
+// they would stop the command before the caller is finished reading.
+func (c *Client) stream(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// If needed, prefix sudo.
+	if c.sudo {
+		args = append([]string{cmd}, args...)
+		cmd = "sudo"
+	}
+
+	c.debugf("stream: %s %v", cmd, args)
 
+	return c.streamFunc(ctx, cmd, args...)
 }
 
 // A pipeError is an error returned by Client.pipe, containing combined
@@ -214,11 +357,18 @@ func New(options ...OptionFunc) *Client {
 		ofctlFlags: make([]string, 0),
 		execFunc:   shellExec,
 		pipeFunc:   shellPipe,
+		streamFunc: shellStream,
 	}
 	for _, o := range options {
 		o(c)
 	}
 
+	c.init()
+	return c
+}
+
+// init creates the services of a Client.
+func (c *Client) init() {
 	vss := &VSwitchService{
 		c: c,
 	}
@@ -239,8 +389,6 @@ func New(options ...OptionFunc) *Client {
 		c: c,
 	}
 	c.App = app
-
-	return c
 }
 
 // An OptionFunc is a function which can apply configuration to a Client.
@@ -263,8 +411,21 @@ func Debug(enable bool) OptionFunc {
 }
 
 // Exec returns an OptionFunc which sets an ExecFunc for use with a Client.
-// This function should typically only be used in tests.
+// This function should typically only be used in tests.  Runner may be used
+// to replace both the ExecFunc and PipeFunc of a Client.
+//
+// An ExecFunc cannot be interrupted, so commands are only canceled by the
+// context set using Client.WithContext before they start.  Use ExecContext
+// to support cancelation.
 func Exec(fn ExecFunc) OptionFunc {
+	return ExecContext(func(_ context.Context, cmd string, args ...string) ([]byte, error) {
+		return fn(cmd, args...)
+	})
+}
+
+// ExecContext returns an OptionFunc which sets an ExecContextFunc for use
+// with a Client.
+func ExecContext(fn ExecContextFunc) OptionFunc {
 	return func(c *Client) {
 		c.execFunc = fn
 	}
@@ -272,12 +433,31 @@ func Exec(fn ExecFunc) OptionFunc {
 
 // Pipe returns an OptionFunc which sets a PipeFunc for use with a Client.
 // This function should typically only be used in tests.
+//
+// As with Exec, a PipeFunc is only canceled before it starts.  Use
+// PipeContext to support cancelation.
 func Pipe(fn PipeFunc) OptionFunc {
+	return PipeContext(func(_ context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		return fn(stdin, cmd, args...)
+	})
+}
+
+// PipeContext returns an OptionFunc which sets a PipeContextFunc for use with
+// a Client.
+func PipeContext(fn PipeContextFunc) OptionFunc {
 	return func(c *Client) {
 		c.pipeFunc = fn
 	}
 }
 
+// Stream returns an OptionFunc which sets a StreamFunc for use with a
+// Client.  This function should typically only be used in tests.
+func Stream(fn StreamFunc) OptionFunc {
+	return func(c *Client) {
+		c.streamFunc = fn
+	}
+}
+
 const (
 	// FlowFormatNXMTableID is a flow format which allows Nicira Extended match
 	// with the ability to place a flow in a specific table.
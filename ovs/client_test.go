@@ -16,8 +16,12 @@ package ovs
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -148,7 +152,7 @@ baz
 
 	// stdin pipe must be consumed.  This test will hang if broken.
 	buf := bytes.NewBuffer(b)
-	out, err := shellPipe(buf, "cat", "-")
+	out, err := shellPipe(context.Background(), buf, "cat", "-")
 	if err != nil {
 		t.Fatalf("failed to pipe to cat: %v", err)
 	}
@@ -159,6 +163,130 @@ baz
 	}
 }
 
+func TestClientWithContextCanceled(t *testing.T) {
+	var ran bool
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		ran = true
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.WithContext(ctx).VSwitch.AddBridge("br0"); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ran {
+		t.Fatal("command executed with canceled context")
+	}
+
+	// The original Client is unaffected.
+	if err := c.VSwitch.AddBridge("br0"); err != nil {
+		t.Fatalf("failed to add bridge: %v", err)
+	}
+	if !ran {
+		t.Fatal("command not executed")
+	}
+}
+
+func TestClientWithContextTimeout(t *testing.T) {
+	c := New(ExecContext(func(ctx context.Context, cmd string, args ...string) ([]byte, error) {
+		// Simulate a hung command which is killed by its context.
+		<-ctx.Done()
+		return []byte("killed"), ctx.Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.WithContext(ctx).VSwitch.ListBridges(); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClientWithContextPipe(t *testing.T) {
+	ctxC := make(chan context.Context, 1)
+	c := New(PipeContext(func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		ctxC <- ctx
+		return nil, nil
+	}))
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "foo")
+
+	if err := c.WithContext(ctx).OpenFlow.AddFlowBundle("br0", func(tx *FlowTransaction) error {
+		tx.Add(&Flow{Actions: []Action{Drop()}})
+		return tx.Commit()
+	}); err != nil {
+		t.Fatalf("failed to add flow bundle: %v", err)
+	}
+
+	if want, got := "foo", (<-ctxC).Value(key{}); want != got {
+		t.Fatalf("unexpected context value:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func Test_shellExecContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := shellExec(ctx, "sleep", "10"); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("command was not killed: ran for %v", d)
+	}
+}
+
+func Test_shellStream(t *testing.T) {
+	rc, err := shellStream(context.Background(), "echo", "foo")
+	if err != nil {
+		t.Fatalf("failed to start echo: %v", err)
+	}
+
+	out, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("unexpected error closing stream: %v", err)
+	}
+
+	if want, got := "foo\n", string(out); want != got {
+		t.Fatalf("unexpected output:\n- want: %q\n-  got: %q",
+			want, got)
+	}
+
+	rc, err = shellStream(context.Background(), "sh", "-c", "exit 1")
+	if err != nil {
+		t.Fatalf("failed to start sh: %v", err)
+	}
+	_, _ = ioutil.ReadAll(rc)
+
+	if err := rc.Close(); err == nil {
+		t.Fatal("expected an error from failed command, but none occurred")
+	}
+}
+
+func Test_shellStreamClose(t *testing.T) {
+	rc, err := shellStream(context.Background(), "sleep", "10")
+	if err != nil {
+		t.Fatalf("failed to start sleep: %v", err)
+	}
+
+	start := time.Now()
+	if err := rc.Close(); err != nil {
+		t.Fatalf("unexpected error closing stream: %v", err)
+	}
+
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("command was not killed: ran for %v", d)
+	}
+}
+
 // testClient creates a new Client with the specified OptionFuncs applied and
 // using the specified ExecFunc.
 func testClient(options []OptionFunc, fn ExecFunc) *Client {
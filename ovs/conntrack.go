@@ -0,0 +1,355 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidConntrackEntry is returned when connection tracking output
+	// from 'ovs-appctl dpctl/dump-conntrack' is not in the expected format.
+	ErrInvalidConntrackEntry = errors.New("invalid conntrack entry")
+
+	// errEmptyConntrackFilter is returned when a ConntrackFilter does not
+	// specify any fields.
+	errEmptyConntrackFilter = errors.New("no fields specified for conntrack filter")
+
+	// errInvalidConntrackFilterAddress is returned when a ConntrackFilter
+	// specifies an address which is not a valid IPv4 or IPv6 address.
+	errInvalidConntrackFilterAddress = errors.New("invalid address for conntrack filter")
+)
+
+// A ConntrackEntry is a connection tracked by the connection tracker of an
+// Open vSwitch datapath.
+type ConntrackEntry struct {
+	// Protocol is the transport protocol of the connection, such as "tcp",
+	// "udp", or "icmp".
+	Protocol string
+
+	// Original and Reply are the tuples of the connection in the original
+	// and reply directions.  The tuples differ when the connection is
+	// subject to NAT.
+	Original ConntrackTuple
+	Reply    ConntrackTuple
+
+	// Zone, Mark, and Labels are the conntrack zone, mark, and labels of
+	// the connection.  Labels is in hexadecimal textual form, such as "0x1",
+	// or is empty if the connection has no labels.
+	Zone   uint16
+	Mark   uint32
+	Labels string
+
+	// State is the TCP state of the connection, such as "ESTABLISHED", or is
+	// empty for protocols other than TCP.
+	State string
+
+	// ID, Status, and Timeout are only reported by verbose dumps.  Status
+	// contains the connection's status flags, such as "ASSURED", and Timeout
+	// is the time remaining before the connection expires.
+	ID      uint32
+	Status  []string
+	Timeout time.Duration
+
+	// Helper is the name of the helper applied to the connection, such as
+	// "ftp", or is empty if none is applied.
+	Helper string
+}
+
+// A ConntrackTuple is the tuple of a connection in a single direction.
+type ConntrackTuple struct {
+	Src net.IP
+	Dst net.IP
+
+	// SrcPort and DstPort are set for protocols with ports, such as TCP.
+	SrcPort uint16
+	DstPort uint16
+
+	// ICMPID, ICMPType, and ICMPCode are set for ICMP and ICMPv6.
+	ICMPID   uint16
+	ICMPType uint8
+	ICMPCode uint8
+}
+
+// UnmarshalText unmarshals a ConntrackEntry from textual form as output by
+// 'ovs-appctl dpctl/dump-conntrack':
+//
+//	tcp,orig=(src=192.0.2.1,dst=192.0.2.2,sport=34567,dport=80),reply=(src=192.0.2.2,dst=192.0.2.1,sport=80,dport=34567),zone=1,mark=5,protoinfo=(state=ESTABLISHED)
+func (e *ConntrackEntry) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := strings.TrimSpace(string(b))
+
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		orig      = "orig"
+		reply     = "reply"
+		zone      = "zone"
+		mark      = "mark"
+		labels    = "labels"
+		protoinfo = "protoinfo"
+		state     = "state"
+		id        = "id"
+		status    = "status"
+		timeout   = "timeout"
+		helper    = "helper"
+	)
+
+	fields := splitDatapathList(s)
+	if len(fields) < 3 || strings.Contains(fields[0], "=") {
+		return ErrInvalidConntrackEntry
+	}
+
+	// The protocol is always first.
+	entry := ConntrackEntry{
+		Protocol: fields[0],
+	}
+
+	var hasOrig, hasReply bool
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidConntrackEntry
+		}
+
+		var err error
+		switch kv[0] {
+		case orig:
+			entry.Original, err = parseConntrackTuple(kv[1])
+			hasOrig = true
+		case reply:
+			entry.Reply, err = parseConntrackTuple(kv[1])
+			hasReply = true
+		case zone:
+			var n uint64
+			n, err = strconv.ParseUint(kv[1], 10, 16)
+			entry.Zone = uint16(n)
+		case mark:
+			var n uint64
+			n, err = strconv.ParseUint(kv[1], 10, 32)
+			entry.Mark = uint32(n)
+		case labels:
+			entry.Labels = kv[1]
+		case protoinfo:
+			info, ok := parseParenthesized(kv[1])
+			if !ok {
+				return ErrInvalidConntrackEntry
+			}
+
+			for _, p := range strings.Split(info, ",") {
+				if strings.HasPrefix(p, state+"=") {
+					entry.State = strings.TrimPrefix(p, state+"=")
+				}
+			}
+		case id:
+			var n uint64
+			n, err = strconv.ParseUint(kv[1], 10, 32)
+			entry.ID = uint32(n)
+		case status:
+			entry.Status = strings.Split(kv[1], "|")
+		case timeout:
+			var n int
+			n, err = strconv.Atoi(kv[1])
+			entry.Timeout = time.Duration(n) * time.Second
+		case helper:
+			entry.Helper = kv[1]
+		default:
+			// Ignore additional information reported by newer versions
+			// of OVS.
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasOrig || !hasReply {
+		return ErrInvalidConntrackEntry
+	}
+
+	*e = entry
+	return nil
+}
+
+// IP protocol numbers for use with ConntrackFilter.
+const (
+	ConntrackProtocolICMP   uint8 = 1
+	ConntrackProtocolTCP    uint8 = 6
+	ConntrackProtocolUDP    uint8 = 17
+	ConntrackProtocolICMPv6 uint8 = 58
+	ConntrackProtocolSCTP   uint8 = 132
+)
+
+// A ConntrackFilter selects the connections flushed by
+// AppService.FlushConntrackZone, using the tuple of the connections in the
+// original direction.  Fields which are not set are not used to select
+// connections.
+type ConntrackFilter struct {
+	// Protocol is the IP protocol number of the connections, such as
+	// ConntrackProtocolTCP.
+	Protocol uint8
+
+	// Original is the tuple of the connections in the original direction.
+	// The ICMP fields are only used when Protocol is ConntrackProtocolICMP
+	// or ConntrackProtocolICMPv6, and the port fields are used otherwise.
+	Original ConntrackTuple
+}
+
+// MarshalText marshals a ConntrackFilter into the textual form accepted by
+// 'ovs-appctl dpctl/flush-conntrack'.
+func (f *ConntrackFilter) MarshalText() ([]byte, error) {
+	var fields []string
+
+	addr := func(key string, ip net.IP) error {
+		if ip == nil {
+			return nil
+		}
+
+		if ip4 := ip.To4(); ip4 != nil {
+			fields = append(fields, fmt.Sprintf("ct_nw_%s=%s", key, ip4))
+			return nil
+		}
+		if len(ip) != net.IPv6len {
+			return errInvalidConntrackFilterAddress
+		}
+
+		fields = append(fields, fmt.Sprintf("ct_ipv6_%s=%s", key, ip))
+		return nil
+	}
+
+	if err := addr("src", f.Original.Src); err != nil {
+		return nil, err
+	}
+	if err := addr("dst", f.Original.Dst); err != nil {
+		return nil, err
+	}
+
+	if f.Protocol != 0 {
+		fields = append(fields, fmt.Sprintf("ct_nw_proto=%d", f.Protocol))
+	}
+
+	t := f.Original
+	switch f.Protocol {
+	case ConntrackProtocolICMP, ConntrackProtocolICMPv6:
+		if t.ICMPID != 0 {
+			fields = append(fields, fmt.Sprintf("icmp_id=%d", t.ICMPID))
+		}
+		if t.ICMPType != 0 {
+			fields = append(fields, fmt.Sprintf("icmp_type=%d", t.ICMPType))
+		}
+		if t.ICMPCode != 0 {
+			fields = append(fields, fmt.Sprintf("icmp_code=%d", t.ICMPCode))
+		}
+	default:
+		if t.SrcPort != 0 {
+			fields = append(fields, fmt.Sprintf("ct_tp_src=%d", t.SrcPort))
+		}
+		if t.DstPort != 0 {
+			fields = append(fields, fmt.Sprintf("ct_tp_dst=%d", t.DstPort))
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, errEmptyConntrackFilter
+	}
+
+	return []byte(strings.Join(fields, ",")), nil
+}
+
+// parseConntrackTuple parses a ConntrackTuple of the form
+// (src=192.0.2.1,dst=192.0.2.2,sport=1,dport=2).
+func parseConntrackTuple(s string) (ConntrackTuple, error) {
+	// Constants only needed within this function, to avoid polluting the
+	// package namespace with generic names
+	const (
+		src      = "src"
+		dst      = "dst"
+		sport    = "sport"
+		dport    = "dport"
+		icmpID   = "id"
+		icmpType = "type"
+		icmpCode = "code"
+	)
+
+	s, ok := parseParenthesized(s)
+	if !ok {
+		return ConntrackTuple{}, ErrInvalidConntrackEntry
+	}
+
+	var t ConntrackTuple
+	for _, f := range strings.Split(s, ",") {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return ConntrackTuple{}, ErrInvalidConntrackEntry
+		}
+
+		switch kv[0] {
+		case src, dst:
+			ip := net.ParseIP(kv[1])
+			if ip == nil {
+				return ConntrackTuple{}, ErrInvalidConntrackEntry
+			}
+
+			if kv[0] == src {
+				t.Src = ip
+			} else {
+				t.Dst = ip
+			}
+		case sport, dport, icmpID:
+			n, err := strconv.ParseUint(kv[1], 10, 16)
+			if err != nil {
+				return ConntrackTuple{}, err
+			}
+
+			switch kv[0] {
+			case sport:
+				t.SrcPort = uint16(n)
+			case dport:
+				t.DstPort = uint16(n)
+			default:
+				t.ICMPID = uint16(n)
+			}
+		case icmpType, icmpCode:
+			n, err := strconv.ParseUint(kv[1], 10, 8)
+			if err != nil {
+				return ConntrackTuple{}, err
+			}
+
+			if kv[0] == icmpType {
+				t.ICMPType = uint8(n)
+			} else {
+				t.ICMPCode = uint8(n)
+			}
+		default:
+			return ConntrackTuple{}, ErrInvalidConntrackEntry
+		}
+	}
+
+	return t, nil
+}
+
+// parseParenthesized returns the contents of s, which must be enclosed in
+// parentheses.
+func parseParenthesized(s string) (string, bool) {
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return "", false
+	}
+
+	return s[1 : len(s)-1], true
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConntrackEntryUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		e    *ConntrackEntry
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "no protocol",
+			s:    "orig=(src=192.0.2.1,dst=192.0.2.2),reply=(src=192.0.2.2,dst=192.0.2.1),zone=1",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "no reply tuple",
+			s:    "udp,orig=(src=192.0.2.1,dst=192.0.2.2,sport=1,dport=2),zone=1",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "malformed tuple",
+			s:    "udp,orig=src=192.0.2.1,reply=(src=192.0.2.2,dst=192.0.2.1)",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "invalid address",
+			s:    "udp,orig=(src=foo,dst=192.0.2.2),reply=(src=192.0.2.2,dst=192.0.2.1)",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "unknown tuple field",
+			s:    "udp,orig=(src=192.0.2.1,foo=1),reply=(src=192.0.2.2,dst=192.0.2.1)",
+			err:  ErrInvalidConntrackEntry,
+		},
+		{
+			desc: "TCP with NAT",
+			s:    "tcp,orig=(src=192.0.2.1,dst=198.51.100.1,sport=34567,dport=80),reply=(src=10.0.0.1,dst=192.0.2.1,sport=8080,dport=34567),id=123,status=SEEN_REPLY|ASSURED|CONFIRMED|DST_NAT_DONE,timeout=431999,zone=1,mark=5,labels=0x1,protoinfo=(state=ESTABLISHED)",
+			e: &ConntrackEntry{
+				Protocol: "tcp",
+				Original: ConntrackTuple{
+					Src:     net.ParseIP("192.0.2.1"),
+					Dst:     net.ParseIP("198.51.100.1"),
+					SrcPort: 34567,
+					DstPort: 80,
+				},
+				Reply: ConntrackTuple{
+					Src:     net.ParseIP("10.0.0.1"),
+					Dst:     net.ParseIP("192.0.2.1"),
+					SrcPort: 8080,
+					DstPort: 34567,
+				},
+				Zone:    1,
+				Mark:    5,
+				Labels:  "0x1",
+				State:   "ESTABLISHED",
+				ID:      123,
+				Status:  []string{"SEEN_REPLY", "ASSURED", "CONFIRMED", "DST_NAT_DONE"},
+				Timeout: 431999 * time.Second,
+			},
+		},
+		{
+			desc: "ICMPv6",
+			s:    "icmpv6,orig=(src=2001:db8::1,dst=2001:db8::2,id=1234,type=128,code=0),reply=(src=2001:db8::2,dst=2001:db8::1,id=1234,type=129,code=0),helper=foo",
+			e: &ConntrackEntry{
+				Protocol: "icmpv6",
+				Original: ConntrackTuple{
+					Src:      net.ParseIP("2001:db8::1"),
+					Dst:      net.ParseIP("2001:db8::2"),
+					ICMPID:   1234,
+					ICMPType: 128,
+				},
+				Reply: ConntrackTuple{
+					Src:      net.ParseIP("2001:db8::2"),
+					Dst:      net.ParseIP("2001:db8::1"),
+					ICMPID:   1234,
+					ICMPType: 129,
+				},
+				Helper: "foo",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			e := new(ConntrackEntry)
+			err := e.UnmarshalText([]byte(tt.s))
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.e, e; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected ConntrackEntry:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestConntrackFilterMarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		f    *ConntrackFilter
+		s    string
+		err  error
+	}{
+		{
+			desc: "empty",
+			f:    &ConntrackFilter{},
+			err:  errEmptyConntrackFilter,
+		},
+		{
+			desc: "invalid address",
+			f: &ConntrackFilter{
+				Original: ConntrackTuple{
+					Src: net.IP{192, 0, 2},
+				},
+			},
+			err: errInvalidConntrackFilterAddress,
+		},
+		{
+			desc: "protocol only",
+			f: &ConntrackFilter{
+				Protocol: ConntrackProtocolSCTP,
+			},
+			s: "ct_nw_proto=132",
+		},
+		{
+			desc: "TCP",
+			f: &ConntrackFilter{
+				Protocol: ConntrackProtocolTCP,
+				Original: ConntrackTuple{
+					Src:     net.ParseIP("192.0.2.1"),
+					Dst:     net.ParseIP("192.0.2.2"),
+					SrcPort: 34567,
+					DstPort: 80,
+
+					// Ignored for TCP.
+					ICMPType: 8,
+				},
+			},
+			s: "ct_nw_src=192.0.2.1,ct_nw_dst=192.0.2.2,ct_nw_proto=6,ct_tp_src=34567,ct_tp_dst=80",
+		},
+		{
+			desc: "ICMPv6",
+			f: &ConntrackFilter{
+				Protocol: ConntrackProtocolICMPv6,
+				Original: ConntrackTuple{
+					Src:      net.ParseIP("2001:db8::1"),
+					ICMPID:   1234,
+					ICMPType: 128,
+					ICMPCode: 1,
+
+					// Ignored for ICMPv6.
+					SrcPort: 1,
+				},
+			},
+			s: "ct_ipv6_src=2001:db8::1,ct_nw_proto=58,icmp_id=1234,icmp_type=128,icmp_code=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.f.MarshalText()
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.s, string(b); want != got {
+				t.Fatalf("unexpected ConntrackFilter text:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+		})
+	}
+}
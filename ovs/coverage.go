@@ -0,0 +1,86 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCoverageCounter is returned when coverage counter output from
+// 'ovs-appctl coverage/show' is not in the expected format.
+var ErrInvalidCoverageCounter = errors.New("invalid coverage counter")
+
+// A CoverageCounter is a coverage counter of an Open vSwitch daemon, which
+// counts occurrences of an internal event, such as an upcall.
+type CoverageCounter struct {
+	// Name is the name of the counter, such as "dpif_flow_put".
+	Name string
+
+	// RateSecond, RateMinute, and RateHour are the average rates of the
+	// event per second, over the last five seconds, minute, and hour.
+	RateSecond float64
+	RateMinute float64
+	RateHour   float64
+
+	// Total is the number of times the event has occurred.
+	Total uint64
+}
+
+// UnmarshalText unmarshals a CoverageCounter from a line of textual output
+// from 'ovs-appctl coverage/show':
+//
+//	dpif_flow_put              0.2/sec     0.150/sec        0.0025/sec   total: 9
+func (c *CoverageCounter) UnmarshalText(b []byte) error {
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		perSec = "/sec"
+		total  = "total:"
+	)
+
+	ss := strings.Fields(string(b))
+	if len(ss) != 6 || ss[4] != total {
+		return ErrInvalidCoverageCounter
+	}
+
+	counter := CoverageCounter{
+		Name: ss[0],
+	}
+
+	rates := []*float64{&counter.RateSecond, &counter.RateMinute, &counter.RateHour}
+	for i, r := range rates {
+		s := ss[i+1]
+		if !strings.HasSuffix(s, perSec) {
+			return ErrInvalidCoverageCounter
+		}
+
+		f, err := strconv.ParseFloat(strings.TrimSuffix(s, perSec), 64)
+		if err != nil {
+			return err
+		}
+		*r = f
+	}
+
+	n, err := strconv.ParseUint(ss[5], 10, 64)
+	if err != nil {
+		return err
+	}
+	counter.Total = n
+
+	*c = counter
+	return nil
+}
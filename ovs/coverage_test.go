@@ -0,0 +1,82 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverageCounterUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		c    *CoverageCounter
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "no total",
+			s:    "dpif_flow_put   0.2/sec  0.150/sec  0.0025/sec  9",
+		},
+		{
+			desc: "invalid rate unit",
+			s:    "dpif_flow_put   0.2/min  0.150/sec  0.0025/sec  total: 9",
+		},
+		{
+			desc: "invalid rate",
+			s:    "dpif_flow_put   foo/sec  0.150/sec  0.0025/sec  total: 9",
+		},
+		{
+			desc: "invalid total",
+			s:    "dpif_flow_put   0.2/sec  0.150/sec  0.0025/sec  total: -1",
+		},
+		{
+			desc: "OK",
+			s:    "dpif_flow_put              0.2/sec     0.150/sec        0.0025/sec   total: 9",
+			c: &CoverageCounter{
+				Name:       "dpif_flow_put",
+				RateSecond: 0.2,
+				RateMinute: 0.15,
+				RateHour:   0.0025,
+				Total:      9,
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := new(CoverageCounter)
+			err := c.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.c, c; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected CoverageCounter:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
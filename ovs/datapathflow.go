@@ -0,0 +1,253 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDatapathFlow is returned when datapath flow output from
+// 'ovs-appctl dpctl/dump-flows' is not in the expected format.
+var ErrInvalidDatapathFlow = errors.New("invalid datapath flow")
+
+// A DatapathFlow is a flow installed in an Open vSwitch datapath, such as
+// the kernel flow cache.  Datapath flows are also known as megaflows.
+type DatapathFlow struct {
+	// UFID is the unique flow identifier of the flow, if one was reported.
+	UFID string
+
+	// Keys contains the fields matched by the flow, in the order they
+	// appear in the flow.  Fields which are fully wildcarded are omitted.
+	Keys []DatapathFlowKey
+
+	// Actions contains the datapath actions executed by the flow, such as
+	// "3" or "ct(commit,zone=1)", in textual form.
+	Actions []string
+
+	// Statistics about the flow.
+	Packets uint64
+	Bytes   uint64
+
+	// Used is the time since the flow last processed a packet, or zero if
+	// the flow has never processed a packet.
+	Used time.Duration
+
+	// TCPFlags contains the TCP flags seen by the flow, such as "S.", or
+	// is empty if none were seen.
+	TCPFlags string
+}
+
+// A DatapathFlowKey is a single field matched by a DatapathFlow.
+type DatapathFlowKey struct {
+	// Name is the name of the field.  Attributes of keys which contain
+	// multiple attributes are named using the key and attribute names
+	// separated by a period, such as "eth.src" or "ipv4.dst".
+	Name string
+
+	// Value is the field's value.  Keys which contain nested keys, such as
+	// "tunnel" or "encap", are not split into attributes, and Value
+	// contains all of their attributes in textual form.
+	Value string
+
+	// Mask is the mask applied to the field, or is empty if the field is
+	// matched exactly.
+	Mask string
+}
+
+// Key returns the DatapathFlowKey with the specified name, and reports
+// whether the key was found.
+func (f *DatapathFlow) Key(name string) (DatapathFlowKey, bool) {
+	for _, k := range f.Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+
+	return DatapathFlowKey{}, false
+}
+
+// UnmarshalText unmarshals a DatapathFlow from textual form as output by
+// 'ovs-appctl dpctl/dump-flows':
+//
+//	recirc_id(0),in_port(2),eth(src=00:00:00:00:00:01,dst=00:00:00:00:00:02),eth_type(0x0800),ipv4(src=192.0.2.1,frag=no), packets:10, bytes:980, used:0.500s, flags:S., actions:3
+func (f *DatapathFlow) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := strings.TrimSpace(string(b))
+
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		ufid    = "ufid"
+		packets = "packets"
+		bytes   = "bytes"
+		used    = "used"
+		flags   = "flags"
+		never   = "never"
+	)
+
+	// Actions are always last, and may contain any characters.
+	i := strings.Index(s, " actions:")
+	if i == -1 {
+		return ErrInvalidDatapathFlow
+	}
+
+	var flow DatapathFlow
+	flow.Actions = splitDatapathList(s[i+len(" actions:"):])
+
+	for _, field := range splitDatapathList(strings.TrimSuffix(s[:i], ",")) {
+		// Keys are of the form name(value), and statistics are of the
+		// form name:value.
+		if p := strings.IndexAny(field, "(:"); p != -1 && field[p] == '(' {
+			keys, err := parseDatapathFlowKey(field)
+			if err != nil {
+				return err
+			}
+
+			flow.Keys = append(flow.Keys, keys...)
+			continue
+		}
+
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return ErrInvalidDatapathFlow
+		}
+
+		switch kv[0] {
+		case ufid:
+			flow.UFID = kv[1]
+		case packets, bytes:
+			n, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			if kv[0] == packets {
+				flow.Packets = n
+			} else {
+				flow.Bytes = n
+			}
+		case used:
+			if kv[1] == never {
+				continue
+			}
+
+			d, err := time.ParseDuration(kv[1])
+			if err != nil {
+				return err
+			}
+			flow.Used = d
+		case flags:
+			flow.TCPFlags = kv[1]
+		default:
+			// Ignore additional information, such as the datapath type
+			// or offload status reported by newer versions of OVS.
+		}
+	}
+
+	if len(flow.Keys) == 0 {
+		return ErrInvalidDatapathFlow
+	}
+
+	*f = flow
+	return nil
+}
+
+// parseDatapathFlowKey parses one or more DatapathFlowKeys from a key of the
+// form name(value) or name(attr=value,...).
+func parseDatapathFlowKey(s string) ([]DatapathFlowKey, error) {
+	open := strings.Index(s, "(")
+	if open < 1 || !strings.HasSuffix(s, ")") {
+		return nil, ErrInvalidDatapathFlow
+	}
+
+	name, value := s[:open], s[open+1:len(s)-1]
+
+	// Keys which contain nested keys are not split any further.
+	if strings.ContainsAny(value, "({") {
+		return []DatapathFlowKey{{
+			Name:  name,
+			Value: value,
+		}}, nil
+	}
+
+	if !strings.Contains(value, "=") {
+		return []DatapathFlowKey{newDatapathFlowKey(name, value)}, nil
+	}
+
+	var keys []DatapathFlowKey
+	for _, attr := range strings.Split(value, ",") {
+		kv := strings.SplitN(attr, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrInvalidDatapathFlow
+		}
+
+		keys = append(keys, newDatapathFlowKey(name+"."+kv[0], kv[1]))
+	}
+
+	return keys, nil
+}
+
+// newDatapathFlowKey creates a DatapathFlowKey from a value which may
+// contain a mask separated by a slash.
+func newDatapathFlowKey(name string, value string) DatapathFlowKey {
+	k := DatapathFlowKey{
+		Name:  name,
+		Value: value,
+	}
+
+	if i := strings.Index(value, "/"); i != -1 {
+		k.Value, k.Mask = value[:i], value[i+1:]
+	}
+
+	return k
+}
+
+// splitDatapathList splits a comma-separated list of datapath keys or
+// actions, ignoring commas which are nested within parentheses or braces,
+// such as those in "ct(commit,zone=1)".
+func splitDatapathList(s string) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case ',':
+			if depth > 0 {
+				continue
+			}
+
+			if f := strings.TrimSpace(s[start:i]); f != "" {
+				out = append(out, f)
+			}
+			start = i + 1
+		}
+	}
+
+	if f := strings.TrimSpace(s[start:]); f != "" {
+		out = append(out, f)
+	}
+
+	return out
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDatapathFlowUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		f    *DatapathFlow
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  ErrInvalidDatapathFlow,
+		},
+		{
+			desc: "no actions",
+			s:    "recirc_id(0),in_port(2), packets:0, bytes:0, used:never",
+			err:  ErrInvalidDatapathFlow,
+		},
+		{
+			desc: "no keys",
+			s:    "packets:0, bytes:0, used:never, actions:drop",
+			err:  ErrInvalidDatapathFlow,
+		},
+		{
+			desc: "malformed key",
+			s:    "recirc_id(0),in_port(2, packets:0, bytes:0, used:never, actions:drop",
+			err:  ErrInvalidDatapathFlow,
+		},
+		{
+			desc: "malformed key attribute",
+			s:    "recirc_id(0),eth(src=00:00:00:00:00:01,dst), packets:0, bytes:0, used:never, actions:drop",
+			err:  ErrInvalidDatapathFlow,
+		},
+		{
+			desc: "unused flow",
+			s:    "recirc_id(0),in_port(2),eth_type(0x0806), packets:0, bytes:0, used:never, actions:drop",
+			f: &DatapathFlow{
+				Keys: []DatapathFlowKey{
+					{Name: "recirc_id", Value: "0"},
+					{Name: "in_port", Value: "2"},
+					{Name: "eth_type", Value: "0x0806"},
+				},
+				Actions: []string{"drop"},
+			},
+		},
+		{
+			desc: "masks and statistics",
+			s:    "ufid:5d9c8ba0-3aa4-4c6c-9c4d-4f2d5e6ea0a1, recirc_id(0),in_port(2),eth(src=00:00:00:00:00:01,dst=00:00:00:00:00:02/01:00:00:00:00:00),eth_type(0x0800),ipv4(src=192.0.2.0/255.255.255.0,proto=6,frag=no),tcp_flags(syn), packets:10, bytes:980, used:0.500s, flags:S., dp:tc, actions:ct(commit,zone=1),3",
+			f: &DatapathFlow{
+				UFID: "5d9c8ba0-3aa4-4c6c-9c4d-4f2d5e6ea0a1",
+				Keys: []DatapathFlowKey{
+					{Name: "recirc_id", Value: "0"},
+					{Name: "in_port", Value: "2"},
+					{Name: "eth.src", Value: "00:00:00:00:00:01"},
+					{Name: "eth.dst", Value: "00:00:00:00:00:02", Mask: "01:00:00:00:00:00"},
+					{Name: "eth_type", Value: "0x0800"},
+					{Name: "ipv4.src", Value: "192.0.2.0", Mask: "255.255.255.0"},
+					{Name: "ipv4.proto", Value: "6"},
+					{Name: "ipv4.frag", Value: "no"},
+					{Name: "tcp_flags", Value: "syn"},
+				},
+				Actions:  []string{"ct(commit,zone=1)", "3"},
+				Packets:  10,
+				Bytes:    980,
+				Used:     500 * time.Millisecond,
+				TCPFlags: "S.",
+			},
+		},
+		{
+			desc: "nested keys",
+			s:    "tunnel(tun_id=0x5,src=192.0.2.1,dst=192.0.2.2,flags(-df+csum+key)),recirc_id(0),in_port(3), packets:1, bytes:60, used:2.5s, actions:set(tunnel(tun_id=0x6,dst=192.0.2.3,ttl=64,flags(df|key))),4",
+			f: &DatapathFlow{
+				Keys: []DatapathFlowKey{
+					{Name: "tunnel", Value: "tun_id=0x5,src=192.0.2.1,dst=192.0.2.2,flags(-df+csum+key)"},
+					{Name: "recirc_id", Value: "0"},
+					{Name: "in_port", Value: "3"},
+				},
+				Actions: []string{"set(tunnel(tun_id=0x6,dst=192.0.2.3,ttl=64,flags(df|key)))", "4"},
+				Packets: 1,
+				Bytes:   60,
+				Used:    2500 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f := new(DatapathFlow)
+			err := f.UnmarshalText([]byte(tt.s))
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected DatapathFlow:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestDatapathFlowKey(t *testing.T) {
+	f := &DatapathFlow{
+		Keys: []DatapathFlowKey{
+			{Name: "in_port", Value: "2"},
+			{Name: "ipv4.src", Value: "192.0.2.0", Mask: "255.255.255.0"},
+		},
+	}
+
+	k, ok := f.Key("ipv4.src")
+	if !ok {
+		t.Fatal("expected key ipv4.src to be found")
+	}
+
+	if want, got := (DatapathFlowKey{Name: "ipv4.src", Value: "192.0.2.0", Mask: "255.255.255.0"}), k; want != got {
+		t.Fatalf("unexpected key:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	if _, ok := f.Key("ipv6.src"); ok {
+		t.Fatal("expected key ipv6.src not to be found")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFDBEntry is returned when MAC learning table output from
+// 'ovs-appctl fdb/show' is not in the expected format.
+var ErrInvalidFDBEntry = errors.New("invalid fdb entry")
+
+// An FDBEntry is an entry in the MAC learning table of a bridge, which
+// records the port on which a MAC address was learned.
+type FDBEntry struct {
+	// PortID is the OpenFlow port number on which the MAC address was
+	// learned, or PortLOCAL for the bridge's local port.
+	PortID int
+
+	VLAN uint16
+	MAC  net.HardwareAddr
+
+	// Age is the time since the entry was last refreshed.  Static entries
+	// do not age, and have an Age of zero.
+	Age    time.Duration
+	Static bool
+}
+
+// UnmarshalText unmarshals an FDBEntry from a line of textual output from
+// 'ovs-appctl fdb/show':
+//
+//	1     0  50:54:00:00:00:01    1
+func (e *FDBEntry) UnmarshalText(b []byte) error {
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		static = "static"
+	)
+
+	ss := strings.Fields(string(b))
+	if len(ss) != 4 {
+		return ErrInvalidFDBEntry
+	}
+
+	var entry FDBEntry
+	if ss[0] == portLOCAL {
+		entry.PortID = PortLOCAL
+	} else {
+		id, err := strconv.ParseInt(ss[0], 10, 0)
+		if err != nil {
+			return err
+		}
+		entry.PortID = int(id)
+	}
+
+	vlan, err := strconv.ParseUint(ss[1], 10, 16)
+	if err != nil {
+		return err
+	}
+	entry.VLAN = uint16(vlan)
+
+	mac, err := net.ParseMAC(ss[2])
+	if err != nil {
+		return err
+	}
+	entry.MAC = mac
+
+	if ss[3] == static {
+		entry.Static = true
+	} else {
+		age, err := strconv.Atoi(ss[3])
+		if err != nil {
+			return err
+		}
+		entry.Age = time.Duration(age) * time.Second
+	}
+
+	*e = entry
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFDBEntryUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		e    *FDBEntry
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "too few fields",
+			s:    "    1     0  50:54:00:00:00:01",
+		},
+		{
+			desc: "invalid port",
+			s:    "  foo     0  50:54:00:00:00:01    1",
+		},
+		{
+			desc: "invalid VLAN",
+			s:    "    1  4096000  50:54:00:00:00:01    1",
+		},
+		{
+			desc: "invalid MAC",
+			s:    "    1     0  foo    1",
+		},
+		{
+			desc: "invalid age",
+			s:    "    1     0  50:54:00:00:00:01    foo",
+		},
+		{
+			desc: "OK",
+			s:    "    1    10  50:54:00:00:00:01    12",
+			e: &FDBEntry{
+				PortID: 1,
+				VLAN:   10,
+				MAC:    net.HardwareAddr{0x50, 0x54, 0x00, 0x00, 0x00, 0x01},
+				Age:    12 * time.Second,
+			},
+			ok: true,
+		},
+		{
+			desc: "LOCAL static",
+			s:    "LOCAL     0  50:54:00:00:00:02  static",
+			e: &FDBEntry{
+				PortID: PortLOCAL,
+				MAC:    net.HardwareAddr{0x50, 0x54, 0x00, 0x00, 0x00, 0x02},
+				Static: true,
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			e := new(FDBEntry)
+			err := e.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.e, e; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected FDBEntry:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
@@ -98,6 +98,7 @@ const (
 	keyActions  = "actions"
 	idleTimeout = "idle_timeout"
 	inPort      = "in_port"
+	outPort     = "out_port"
 	table       = "table"
 	duration    = "duration"
 	nPackets    = "n_packets"
@@ -105,7 +106,8 @@ const (
 	hardAge     = "hard_age"
 	idleAge     = "idle_age"
 
-	portLOCAL = "LOCAL"
+	portLOCAL      = "LOCAL"
+	portCONTROLLER = "CONTROLLER"
 )
 
 var (
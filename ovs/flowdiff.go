@@ -0,0 +1,114 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// errInvalidFlowDiff is returned when output from 'ovs-ofctl diff-flows'
+	// does not match the expected output format.
+	errInvalidFlowDiff = errors.New("invalid flow diff")
+)
+
+// diffFlowsDifferent is the exit status returned by 'ovs-ofctl diff-flows'
+// when differences are found.
+const diffFlowsDifferent = 2
+
+// A FlowDiff is the difference between the flows installed on a bridge and a
+// desired set of flows, as reported by 'ovs-ofctl diff-flows'.  A flow whose
+// actions or other attributes differ appears in both Removed and Added.
+type FlowDiff struct {
+	// Removed contains flows which are installed on the bridge, but are not
+	// in the desired set of flows.
+	Removed []*Flow
+
+	// Added contains flows which are in the desired set of flows, but are
+	// not installed on the bridge.
+	Added []*Flow
+}
+
+// Empty reports whether the FlowDiff contains no differences.
+func (d *FlowDiff) Empty() bool {
+	return len(d.Removed) == 0 && len(d.Added) == 0
+}
+
+// parseFlowDiff parses the output of 'ovs-ofctl diff-flows', in which each
+// line is a flow prefixed by "-" or "+":
+//
+//	-priority=10,ip actions=drop
+//	+table=1 priority=20,ip cookie=0x1 idle_timeout=10 actions=output:1
+func parseFlowDiff(b []byte) (*FlowDiff, error) {
+	d := new(FlowDiff)
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		// Attributes which precede the actions are separated by spaces,
+		// rather than the commas expected by Flow.UnmarshalText.
+		i := strings.Index(line, " "+keyActions+"=")
+		if i == -1 {
+			return nil, errInvalidFlowDiff
+		}
+		text := strings.Replace(line[1:i], " ", ",", -1) + line[i:]
+
+		f := new(Flow)
+		if err := f.UnmarshalText([]byte(text)); err != nil {
+			return nil, err
+		}
+
+		switch line[0] {
+		case '-':
+			d.Removed = append(d.Removed, f)
+		case '+':
+			d.Added = append(d.Added, f)
+		default:
+			return nil, errInvalidFlowDiff
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// exitStatus returns the exit status of the process which returned err, if
+// err indicates that a process exited unsuccessfully.
+func exitStatus(err error) (int, bool) {
+	ee, ok := err.(*exec.ExitError)
+	if !ok {
+		return 0, false
+	}
+
+	ws, ok := ee.Sys().(interface {
+		ExitStatus() int
+	})
+	if !ok {
+		return 0, false
+	}
+
+	return ws.ExitStatus(), true
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_parseFlowDiff(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		d    *FlowDiff
+		ok   bool
+	}{
+		{
+			desc: "no actions",
+			s:    "-priority=10,ip",
+		},
+		{
+			desc: "unknown prefix",
+			s:    "*priority=10,ip actions=drop",
+		},
+		{
+			desc: "invalid flow",
+			s:    "+priority=foo,ip actions=drop",
+		},
+		{
+			desc: "no differences",
+			d:    &FlowDiff{},
+			ok:   true,
+		},
+		{
+			desc: "differences",
+			s: `-priority=10,ip actions=drop
++priority=10,ip actions=output:1
++table=1 priority=20,in_port=2 cookie=0x1 idle_timeout=10 actions=normal
+`,
+			d: &FlowDiff{
+				Removed: []*Flow{{
+					Priority: 10,
+					Protocol: ProtocolIPv4,
+					Matches:  []Match{},
+					Actions:  []Action{Drop()},
+				}},
+				Added: []*Flow{
+					{
+						Priority: 10,
+						Protocol: ProtocolIPv4,
+						Matches:  []Match{},
+						Actions:  []Action{Output(1)},
+					},
+					{
+						Priority:    20,
+						InPort:      2,
+						Matches:     []Match{},
+						Table:       1,
+						IdleTimeout: 10,
+						Cookie:      0x1,
+						Actions:     []Action{Normal()},
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			d, err := parseFlowDiff([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.d, d; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected FlowDiff:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+
+			if want, got := len(tt.s) == 0, d.Empty(); want != got {
+				t.Fatalf("unexpected FlowDiff.Empty:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func Test_exitStatus(t *testing.T) {
+	if _, ok := exitStatus(errors.New("foo")); ok {
+		t.Fatal("exit status should not be reported for non-process error")
+	}
+
+	status, ok := exitStatus(mustExitError(t, 2))
+	if !ok {
+		t.Fatal("exit status was not reported")
+	}
+
+	if want, got := 2, status; want != got {
+		t.Fatalf("unexpected exit status:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+// mustExitError returns the error produced by a process which exits with the
+// specified status.
+func mustExitError(t *testing.T, status int) error {
+	t.Helper()
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skipf("skipping, sh not found: %v", err)
+	}
+
+	err = exec.Command(sh, "-c", "exit "+strconv.Itoa(status)).Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("unexpected error from sh: %v", err)
+	}
+
+	return err
+}
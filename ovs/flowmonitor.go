@@ -0,0 +1,250 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidFlowEvent is returned when flow events from 'ovs-ofctl
+	// monitor' do not match the expected output format.
+	ErrInvalidFlowEvent = errors.New("invalid flow monitor event")
+)
+
+// A FlowEventType is the type of a FlowEvent.
+type FlowEventType string
+
+// FlowEventType constants which may appear in a FlowEvent.
+const (
+	// FlowEventInitial reports a flow which was present when the
+	// FlowMonitor was created.
+	FlowEventInitial FlowEventType = "INITIAL"
+
+	// FlowEventAdded reports a flow which was added.
+	FlowEventAdded FlowEventType = "ADDED"
+
+	// FlowEventDeleted reports a flow which was deleted.
+	FlowEventDeleted FlowEventType = "DELETED"
+
+	// FlowEventModified reports a flow whose actions were modified.
+	FlowEventModified FlowEventType = "MODIFIED"
+
+	// FlowEventAbbreviated reports changes made by this connection, which
+	// are abbreviated by Open vSwitch.  Abbreviated events carry no Flow.
+	FlowEventAbbreviated FlowEventType = "ABBREVIATED"
+)
+
+// A FlowEvent is a change to the flow table of a bridge, as reported by
+// 'ovs-ofctl monitor'.
+type FlowEvent struct {
+	Type FlowEventType
+
+	// Reason is the reason a flow was deleted, such as "delete" or
+	// "idle", for FlowEventDeleted events.
+	Reason string
+
+	// Flow is the flow which changed.  It is nil for FlowEventAbbreviated
+	// events.
+	Flow *Flow
+}
+
+// UnmarshalText unmarshals a FlowEvent from textual form as output by
+// 'ovs-ofctl monitor':
+//
+//	event=DELETED reason=delete table=0 cookie=0 priority=10,ip actions=drop
+func (e *FlowEvent) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := strings.TrimSpace(string(b))
+
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		event       = "event"
+		reason      = "reason"
+		hardTimeout = "hard_timeout"
+		importance  = "importance"
+	)
+
+	var (
+		ev    FlowEvent
+		attrs []string
+	)
+
+	// Attributes which precede the actions are separated by spaces, rather
+	// than the commas expected by Flow.UnmarshalText.
+	i := strings.Index(s, " "+keyActions+"=")
+	head, actions := s, ""
+	if i != -1 {
+		head, actions = s[:i], s[i+1:]
+	}
+
+	for _, f := range strings.Fields(head) {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidFlowEvent
+		}
+
+		switch kv[0] {
+		case event:
+			ev.Type = FlowEventType(kv[1])
+		case reason:
+			ev.Reason = kv[1]
+		case hardTimeout, importance:
+			// Not represented by Flow.
+		default:
+			attrs = append(attrs, f)
+		}
+	}
+
+	switch ev.Type {
+	case FlowEventAbbreviated:
+		*e = ev
+		return nil
+	case FlowEventInitial, FlowEventAdded, FlowEventDeleted, FlowEventModified:
+	default:
+		return ErrInvalidFlowEvent
+	}
+
+	if actions == "" {
+		return ErrInvalidFlowEvent
+	}
+
+	f := new(Flow)
+	if err := f.UnmarshalText([]byte(strings.Join(attrs, ",") + " " + actions)); err != nil {
+		return err
+	}
+
+	ev.Flow = f
+	*e = ev
+	return nil
+}
+
+// A FlowMonitor streams changes to the flow table of a bridge.  FlowMonitors
+// are created using OpenFlowService.MonitorFlows.
+type FlowMonitor struct {
+	events chan FlowEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// MonitorFlows starts 'ovs-ofctl monitor' for the specified bridge, and
+// returns a FlowMonitor which reports changes to the bridge's flow table,
+// beginning with a FlowEventInitial event for each existing flow.
+//
+// The Timeout option is not applied to the monitor.  The monitor stops when
+// FlowMonitor.Close is called, or when the context set using
+// Client.WithContext is canceled.  MonitorFlows requires that the Client's
+// CommandRunner, if any, implements StreamRunner.
+func (o *OpenFlowService) MonitorFlows(bridge string) (*FlowMonitor, error) {
+	args := []string{"monitor"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge, "watch:")
+
+	ctx, cancel := context.WithCancel(o.c.context())
+
+	rc, err := o.c.stream(ctx, "ovs-ofctl", args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	m := &FlowMonitor{
+		events: make(chan FlowEvent),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(m.done)
+		defer close(m.events)
+
+		err := m.run(ctx, bufio.NewScanner(rc))
+
+		// Always stop the command, even if an event could not be parsed.
+		cerr := rc.Close()
+		if err == nil {
+			err = cerr
+		}
+
+		// Errors caused by stopping the monitor are not reported.
+		if ctx.Err() != nil {
+			err = nil
+		}
+
+		m.mu.Lock()
+		m.err = err
+		m.mu.Unlock()
+	}()
+
+	return m, nil
+}
+
+// run parses events from s and sends them on the FlowMonitor's events
+// channel until s is exhausted or ctx is canceled.
+func (m *FlowMonitor) run(ctx context.Context, s *bufio.Scanner) error {
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		// Ignore message headers, such as "NXST_FLOW_MONITOR reply".
+		if !strings.HasPrefix(line, "event=") {
+			continue
+		}
+
+		var e FlowEvent
+		if err := e.UnmarshalText([]byte(line)); err != nil {
+			return err
+		}
+
+		select {
+		case m.events <- e:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return s.Err()
+}
+
+// Events returns a channel of FlowEvents.  The channel is closed when the
+// FlowMonitor stops, after which Err reports the reason.
+func (m *FlowMonitor) Events() <-chan FlowEvent {
+	return m.events
+}
+
+// Err returns the error which stopped the FlowMonitor, such as an error
+// returned by 'ovs-ofctl monitor' or an event which could not be parsed.
+// Err returns nil if the FlowMonitor is running or was stopped by Close.
+func (m *FlowMonitor) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.err
+}
+
+// Close stops the FlowMonitor and waits for 'ovs-ofctl monitor' to exit.
+func (m *FlowMonitor) Close() error {
+	m.cancel()
+	<-m.done
+
+	return nil
+}
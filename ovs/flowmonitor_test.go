@@ -0,0 +1,296 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlowEventUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		e    *FlowEvent
+		ok   bool
+	}{
+		{
+			desc: "empty string",
+		},
+		{
+			desc: "unknown event",
+			s:    "event=FOO table=0 cookie=0 priority=10,ip actions=drop",
+		},
+		{
+			desc: "no actions",
+			s:    "event=ADDED table=0 cookie=0 priority=10,ip",
+		},
+		{
+			desc: "malformed attribute",
+			s:    "event=ADDED foo priority=10,ip actions=drop",
+		},
+		{
+			desc: "invalid flow",
+			s:    "event=ADDED table=foo priority=10,ip actions=drop",
+		},
+		{
+			desc: "abbreviated",
+			s:    "event=ABBREVIATED xid=0x1",
+			e: &FlowEvent{
+				Type: FlowEventAbbreviated,
+			},
+			ok: true,
+		},
+		{
+			desc: "added",
+			s:    " event=ADDED table=1 cookie=0x10 idle_timeout=30 hard_timeout=60 priority=10,ip,in_port=1 actions=output:2",
+			e: &FlowEvent{
+				Type: FlowEventAdded,
+				Flow: &Flow{
+					Priority:    10,
+					Protocol:    ProtocolIPv4,
+					InPort:      1,
+					Matches:     []Match{},
+					Table:       1,
+					IdleTimeout: 30,
+					Cookie:      0x10,
+					Actions:     []Action{Output(2)},
+				},
+			},
+			ok: true,
+		},
+		{
+			desc: "deleted",
+			s:    "event=DELETED reason=idle table=0 cookie=0 priority=20,ipv6 actions=drop",
+			e: &FlowEvent{
+				Type:   FlowEventDeleted,
+				Reason: "idle",
+				Flow: &Flow{
+					Priority: 20,
+					Protocol: ProtocolIPv6,
+					Matches:  []Match{},
+					Actions:  []Action{Drop()},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			e := new(FlowEvent)
+			err := e.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.e, e; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected FlowEvent:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientOpenFlowMonitorFlows(t *testing.T) {
+	const out = `NXST_FLOW_MONITOR reply (xid=0x4):
+ event=INITIAL table=0 cookie=0 priority=10,ip actions=drop
+NXST_FLOW_MONITOR reply (xid=0x0):
+ event=MODIFIED table=0 cookie=0 priority=10,ip actions=normal
+`
+
+	stream := Stream(func(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+		if want, got := "sudo", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		// The timeout must not be applied to the monitor.
+		wantArgs := []string{"ovs-ofctl", "monitor", "--protocols=OpenFlow13", "br0", "watch:"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return testStream(ctx, out), nil
+	})
+
+	options := []OptionFunc{
+		Timeout(1),
+		Sudo(),
+		Protocols([]string{ProtocolOpenFlow13}),
+		stream,
+	}
+
+	c := testClient(options, nil)
+
+	m, err := c.OpenFlow.MonitorFlows("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.MonitorFlows: %v", err)
+	}
+
+	want := []FlowEvent{
+		{
+			Type: FlowEventInitial,
+			Flow: &Flow{
+				Priority: 10,
+				Protocol: ProtocolIPv4,
+				Matches:  []Match{},
+				Actions:  []Action{Drop()},
+			},
+		},
+		{
+			Type: FlowEventModified,
+			Flow: &Flow{
+				Priority: 10,
+				Protocol: ProtocolIPv4,
+				Matches:  []Match{},
+				Actions:  []Action{Normal()},
+			},
+		},
+	}
+
+	var got []FlowEvent
+	for range want {
+		got = append(got, <-m.Events())
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected events:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("failed to close monitor: %v", err)
+	}
+
+	if _, ok := <-m.Events(); ok {
+		t.Fatal("events channel should be closed")
+	}
+	if err := m.Err(); err != nil {
+		t.Fatalf("unexpected error after close: %v", err)
+	}
+}
+
+func TestClientOpenFlowMonitorFlowsInvalidEvent(t *testing.T) {
+	stream := Stream(func(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+		return &testReadCloser{
+			r: strings.NewReader("event=FOO table=0 actions=drop\n"),
+		}, nil
+	})
+
+	c := testClient([]OptionFunc{stream}, nil)
+
+	m, err := c.OpenFlow.MonitorFlows("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.MonitorFlows: %v", err)
+	}
+	defer m.Close()
+
+	if _, ok := <-m.Events(); ok {
+		t.Fatal("events channel should be closed")
+	}
+
+	if want, got := ErrInvalidFlowEvent, m.Err(); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowMonitorFlowsCommandError(t *testing.T) {
+	errFoo := errors.New("exit status 1")
+
+	stream := Stream(func(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+		return &testReadCloser{
+			r:   strings.NewReader(""),
+			err: errFoo,
+		}, nil
+	})
+
+	c := testClient([]OptionFunc{stream}, nil)
+
+	m, err := c.OpenFlow.MonitorFlows("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.MonitorFlows: %v", err)
+	}
+	defer m.Close()
+
+	if _, ok := <-m.Events(); ok {
+		t.Fatal("events channel should be closed")
+	}
+
+	if want, got := errFoo, m.Err(); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowMonitorFlowsStreamUnsupported(t *testing.T) {
+	c := New(Runner(RunnerFunc(func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		return nil, nil
+	})))
+
+	if _, err := c.OpenFlow.MonitorFlows("br0"); err != errStreamUnsupported {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			errStreamUnsupported, err)
+	}
+}
+
+func TestClientOpenFlowMonitorFlowsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := testClient([]OptionFunc{Stream(func(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+		t.Fatal("command should not be started")
+		return nil, nil
+	})}, nil).WithContext(ctx)
+
+	if _, err := c.OpenFlow.MonitorFlows("br0"); err != context.Canceled {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			context.Canceled, err)
+	}
+}
+
+// testStream returns an io.ReadCloser which produces out, and then blocks
+// until ctx is canceled, as 'ovs-ofctl monitor' does.
+func testStream(ctx context.Context, out string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(pw, out)
+		<-ctx.Done()
+		_ = pw.Close()
+	}()
+
+	return pr
+}
+
+// A testReadCloser is an io.ReadCloser which returns err when closed.
+type testReadCloser struct {
+	r   io.Reader
+	err error
+}
+
+func (rc *testReadCloser) Read(b []byte) (int, error) { return rc.r.Read(b) }
+func (rc *testReadCloser) Close() error               { return rc.err }
@@ -0,0 +1,259 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidGroup is returned when groups from 'ovs-ofctl dump-groups'
+	// do not match the expected output format.
+	ErrInvalidGroup = errors.New("invalid openflow group")
+
+	// errInvalidGroupType is returned when a Group has an unknown or empty
+	// GroupType.
+	errInvalidGroupType = errors.New("invalid or missing group type")
+
+	// errNoBucketActions is returned when a Bucket has no actions.
+	errNoBucketActions = errors.New("no actions defined for group bucket")
+)
+
+// A GroupType is the type of an OpenFlow group, which determines how the
+// group's buckets are used to process a packet.
+type GroupType string
+
+// GroupType constants which may be used with a Group.
+const (
+	// GroupAll executes all buckets, and is typically used for multicast
+	// and broadcast forwarding.
+	GroupAll GroupType = "all"
+
+	// GroupSelect executes one bucket, chosen using a hash of the packet
+	// and the bucket weights, and is typically used for ECMP.
+	GroupSelect GroupType = "select"
+
+	// GroupIndirect executes its only bucket.
+	GroupIndirect GroupType = "indirect"
+
+	// GroupFastFailover executes the first live bucket, as determined by
+	// each bucket's watch port or watch group.
+	GroupFastFailover GroupType = "ff"
+)
+
+// A Group is an OpenFlow group, as added by 'ovs-ofctl add-group' and
+// reported by 'ovs-ofctl dump-groups'.
+type Group struct {
+	ID      uint32
+	Type    GroupType
+	Buckets []Bucket
+}
+
+// A Bucket is a set of actions executed by a Group.
+type Bucket struct {
+	// Weight is used to choose between the buckets of a GroupSelect group.
+	// If zero, Open vSwitch uses a default weight.
+	Weight int
+
+	// WatchPort and WatchGroup determine whether the bucket is live in a
+	// GroupFastFailover group.  Each is only used if non-zero.
+	WatchPort  int
+	WatchGroup uint32
+
+	Actions []Action
+}
+
+// Constants used repeatedly when marshaling and unmarshaling groups.
+const (
+	groupID          = "group_id"
+	groupType        = "type"
+	groupBucket      = "bucket"
+	bucketID         = "bucket_id"
+	bucketWeight     = "weight"
+	bucketWatchPort  = "watch_port"
+	bucketWatchGroup = "watch_group"
+)
+
+// MarshalText marshals a Group into its textual form, as accepted by
+// 'ovs-ofctl add-group'.
+func (g *Group) MarshalText() ([]byte, error) {
+	switch g.Type {
+	case GroupAll, GroupSelect, GroupIndirect, GroupFastFailover:
+	default:
+		return nil, errInvalidGroupType
+	}
+
+	b := []byte(groupID + "=")
+	b = strconv.AppendUint(b, uint64(g.ID), 10)
+	b = append(b, ","+groupType+"="...)
+	b = append(b, g.Type...)
+
+	for _, bk := range g.Buckets {
+		if len(bk.Actions) == 0 {
+			return nil, errNoBucketActions
+		}
+
+		b = append(b, ","+groupBucket+"="...)
+
+		// Bucket parameters use a colon rather than an equals sign, to
+		// distinguish them from the bucket itself.
+		if bk.Weight != 0 {
+			b = append(b, bucketWeight+":"...)
+			b = strconv.AppendInt(b, int64(bk.Weight), 10)
+			b = append(b, ',')
+		}
+		if bk.WatchPort != 0 {
+			b = append(b, bucketWatchPort+":"...)
+			b = strconv.AppendInt(b, int64(bk.WatchPort), 10)
+			b = append(b, ',')
+		}
+		if bk.WatchGroup != 0 {
+			b = append(b, bucketWatchGroup+":"...)
+			b = strconv.AppendUint(b, uint64(bk.WatchGroup), 10)
+			b = append(b, ',')
+		}
+
+		actions := make([]string, 0, len(bk.Actions))
+		for _, a := range bk.Actions {
+			ab, err := a.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+
+			actions = append(actions, string(ab))
+		}
+
+		b = append(b, keyActions+"="+strings.Join(actions, ",")...)
+	}
+
+	return b, nil
+}
+
+// UnmarshalText unmarshals a Group from textual form as output by
+// 'ovs-ofctl dump-groups':
+//
+//	group_id=1,type=select,bucket=weight:100,actions=output:1,bucket=weight:100,actions=output:2
+//
+// Bucket IDs and group properties such as selection_method are ignored.
+func (g *Group) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := strings.TrimSpace(string(b))
+
+	ss := strings.Split(s, ","+groupBucket+"=")
+
+	var (
+		hasID   bool
+		hasType bool
+	)
+
+	for _, kv := range strings.Split(ss[0], ",") {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 {
+			continue
+		}
+
+		switch kvs[0] {
+		case groupID:
+			id, err := strconv.ParseUint(kvs[1], 10, 32)
+			if err != nil {
+				return err
+			}
+			g.ID = uint32(id)
+			hasID = true
+		case groupType:
+			g.Type = GroupType(kvs[1])
+			hasType = true
+		}
+	}
+
+	if !hasID || !hasType {
+		return ErrInvalidGroup
+	}
+
+	g.Buckets = nil
+	for _, bs := range ss[1:] {
+		var bk Bucket
+		if err := bk.unmarshalText(bs); err != nil {
+			return err
+		}
+
+		g.Buckets = append(g.Buckets, bk)
+	}
+
+	return nil
+}
+
+// unmarshalText unmarshals a Bucket from the textual form which follows
+// "bucket=" in a group.
+func (bk *Bucket) unmarshalText(s string) error {
+	i := strings.Index(s, keyActions+"=")
+	if i == -1 {
+		return ErrInvalidGroup
+	}
+
+	params, actions := s[:i], s[i+len(keyActions)+1:]
+
+	for _, p := range strings.Split(params, ",") {
+		if p == "" {
+			continue
+		}
+
+		// Parameters may be separated from their values by either a colon or
+		// an equals sign.
+		kv := strings.FieldsFunc(p, func(r rune) bool {
+			return r == ':' || r == '='
+		})
+		if len(kv) != 2 {
+			return ErrInvalidGroup
+		}
+
+		switch kv[0] {
+		case bucketID:
+		case bucketWeight:
+			w, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return err
+			}
+			bk.Weight = w
+		case bucketWatchPort:
+			port, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return err
+			}
+			bk.WatchPort = port
+		case bucketWatchGroup:
+			id, err := strconv.ParseUint(kv[1], 10, 32)
+			if err != nil {
+				return err
+			}
+			bk.WatchGroup = uint32(id)
+		default:
+			return ErrInvalidGroup
+		}
+	}
+
+	as, _, err := newActionParser(strings.NewReader(actions)).Parse()
+	if err != nil {
+		return err
+	}
+	if len(as) == 0 {
+		return errNoBucketActions
+	}
+
+	bk.Actions = as
+	return nil
+}
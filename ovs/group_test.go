@@ -0,0 +1,222 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupMarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		g    *Group
+		s    string
+		err  error
+	}{
+		{
+			desc: "no type",
+			g:    &Group{ID: 1},
+			err:  errInvalidGroupType,
+		},
+		{
+			desc: "bucket without actions",
+			g: &Group{
+				ID:      1,
+				Type:    GroupAll,
+				Buckets: []Bucket{{Weight: 10}},
+			},
+			err: errNoBucketActions,
+		},
+		{
+			desc: "invalid action",
+			g: &Group{
+				ID:   1,
+				Type: GroupIndirect,
+				Buckets: []Bucket{{
+					Actions: []Action{Output(-1)},
+				}},
+			},
+			err: errOutputNegativePort,
+		},
+		{
+			desc: "no buckets",
+			g: &Group{
+				ID:   1,
+				Type: GroupAll,
+			},
+			s: "group_id=1,type=all",
+		},
+		{
+			desc: "select",
+			g: &Group{
+				ID:   10,
+				Type: GroupSelect,
+				Buckets: []Bucket{
+					{
+						Weight:  100,
+						Actions: []Action{ModVLANVID(10), Output(1)},
+					},
+					{
+						Weight:  50,
+						Actions: []Action{Output(2)},
+					},
+				},
+			},
+			s: "group_id=10,type=select,bucket=weight:100,actions=mod_vlan_vid:10,output:1,bucket=weight:50,actions=output:2",
+		},
+		{
+			desc: "fast failover",
+			g: &Group{
+				ID:   2,
+				Type: GroupFastFailover,
+				Buckets: []Bucket{
+					{
+						WatchPort: 1,
+						Actions:   []Action{Output(1)},
+					},
+					{
+						WatchGroup: 3,
+						Actions:    []Action{GroupAction(3)},
+					},
+				},
+			},
+			s: "group_id=2,type=ff,bucket=watch_port:1,actions=output:1,bucket=watch_group:3,actions=group:3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.g.MarshalText()
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.s, string(b); want != got {
+				t.Fatalf("unexpected Group text:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestGroupUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		g    *Group
+		ok   bool
+	}{
+		{
+			desc: "empty string",
+		},
+		{
+			desc: "no type",
+			s:    "group_id=1",
+		},
+		{
+			desc: "invalid ID",
+			s:    "group_id=foo,type=all",
+		},
+		{
+			desc: "bucket without actions",
+			s:    "group_id=1,type=all,bucket=weight:100",
+		},
+		{
+			desc: "unknown bucket parameter",
+			s:    "group_id=1,type=all,bucket=foo:1,actions=output:1",
+		},
+		{
+			desc: "invalid bucket action",
+			s:    "group_id=1,type=all,bucket=actions=foo",
+		},
+		{
+			desc: "unbalanced bucket action",
+			s:    "group_id=1,type=all,bucket=actions=drop)",
+		},
+		{
+			desc: "no buckets",
+			s:    "group_id=1,type=all",
+			g: &Group{
+				ID:   1,
+				Type: GroupAll,
+			},
+			ok: true,
+		},
+		{
+			desc: "select with bucket IDs",
+			s:    " group_id=10,type=select,selection_method=hash,bucket=bucket_id:0,weight:100,actions=mod_vlan_vid:10,output:1,bucket=bucket_id:1,weight:50,actions=output:2",
+			g: &Group{
+				ID:   10,
+				Type: GroupSelect,
+				Buckets: []Bucket{
+					{
+						Weight:  100,
+						Actions: []Action{ModVLANVID(10), Output(1)},
+					},
+					{
+						Weight:  50,
+						Actions: []Action{Output(2)},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			desc: "fast failover",
+			s:    "group_id=2,type=ff,bucket=watch_port=1,actions=output:1,bucket=watch_group:3,actions=group:3",
+			g: &Group{
+				ID:   2,
+				Type: GroupFastFailover,
+				Buckets: []Bucket{
+					{
+						WatchPort: 1,
+						Actions:   []Action{Output(1)},
+					},
+					{
+						WatchGroup: 3,
+						Actions:    []Action{GroupAction(3)},
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			g := new(Group)
+			err := g.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.g, g; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Group:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
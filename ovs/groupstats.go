@@ -0,0 +1,144 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidGroupStats is returned when group statistics from 'ovs-ofctl
+	// dump-group-stats' do not match the expected output format.
+	ErrInvalidGroupStats = errors.New("invalid group statistics")
+)
+
+// GroupStats contains statistics about an OpenFlow group, including the
+// number of packets and bytes processed by the group and by each of its
+// buckets.
+type GroupStats struct {
+	GroupID     uint32
+	Duration    time.Duration
+	RefCount    uint32
+	PacketCount uint64
+	ByteCount   uint64
+
+	// Buckets contains statistics for each of the group's buckets, in the
+	// order the buckets appear in the group.
+	Buckets []BucketStats
+}
+
+// BucketStats contains statistics about a single bucket of an OpenFlow
+// group.
+type BucketStats struct {
+	PacketCount uint64
+	ByteCount   uint64
+}
+
+// UnmarshalText unmarshals a GroupStats from textual form as output by
+// 'ovs-ofctl dump-group-stats':
+//
+//	group_id=1,duration=12.345s,ref_count=1,packet_count=10,byte_count=1000,bucket0:packet_count=5,byte_count=500,bucket1:packet_count=5,byte_count=500
+func (g *GroupStats) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := strings.TrimSpace(string(b))
+
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		refCount    = "ref_count"
+		packetCount = "packet_count"
+		byteCount   = "byte_count"
+	)
+
+	var (
+		stats GroupStats
+		hasID bool
+
+		// bucket points to the bucket whose counters are being parsed, or
+		// is nil while parsing the group's own counters.
+		bucket *BucketStats
+	)
+
+	for _, f := range strings.Split(s, ",") {
+		// Bucket counters are introduced by a "bucketN:" prefix.
+		if strings.HasPrefix(f, groupBucket) {
+			i := strings.Index(f, ":")
+			if i == -1 {
+				return ErrInvalidGroupStats
+			}
+			if _, err := strconv.Atoi(f[len(groupBucket):i]); err != nil {
+				return ErrInvalidGroupStats
+			}
+
+			stats.Buckets = append(stats.Buckets, BucketStats{})
+			bucket = &stats.Buckets[len(stats.Buckets)-1]
+			f = f[i+1:]
+		}
+
+		kv := strings.Split(f, "=")
+		if len(kv) != 2 {
+			return ErrInvalidGroupStats
+		}
+
+		if kv[0] == duration {
+			d, err := time.ParseDuration(kv[1])
+			if err != nil {
+				return err
+			}
+
+			stats.Duration = d
+			continue
+		}
+
+		n, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case kv[0] == groupID && bucket == nil:
+			if n > 0xffffffff {
+				return ErrInvalidGroupStats
+			}
+			stats.GroupID = uint32(n)
+			hasID = true
+		case kv[0] == refCount && bucket == nil:
+			if n > 0xffffffff {
+				return ErrInvalidGroupStats
+			}
+			stats.RefCount = uint32(n)
+		case kv[0] == packetCount && bucket == nil:
+			stats.PacketCount = n
+		case kv[0] == byteCount && bucket == nil:
+			stats.ByteCount = n
+		case kv[0] == packetCount:
+			bucket.PacketCount = n
+		case kv[0] == byteCount:
+			bucket.ByteCount = n
+		default:
+			return ErrInvalidGroupStats
+		}
+	}
+
+	if !hasID {
+		return ErrInvalidGroupStats
+	}
+
+	*g = stats
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupStatsUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		s     string
+		stats *GroupStats
+		ok    bool
+	}{
+		{
+			desc: "empty string",
+		},
+		{
+			desc: "no group ID",
+			s:    "duration=1.000s,ref_count=0,packet_count=0,byte_count=0",
+		},
+		{
+			desc: "invalid duration",
+			s:    "group_id=1,duration=foo,ref_count=0,packet_count=0,byte_count=0",
+		},
+		{
+			desc: "invalid counter",
+			s:    "group_id=1,duration=1.000s,ref_count=0,packet_count=foo,byte_count=0",
+		},
+		{
+			desc: "unknown field",
+			s:    "group_id=1,duration=1.000s,foo=1",
+		},
+		{
+			desc: "invalid bucket",
+			s:    "group_id=1,duration=1.000s,ref_count=0,packet_count=0,byte_count=0,bucketfoo:packet_count=0,byte_count=0",
+		},
+		{
+			desc: "group ID in bucket",
+			s:    "group_id=1,duration=1.000s,bucket0:group_id=2",
+		},
+		{
+			desc: "no buckets",
+			s:    "group_id=1,duration=1.500s,ref_count=2,packet_count=10,byte_count=1000",
+			stats: &GroupStats{
+				GroupID:     1,
+				Duration:    1500 * time.Millisecond,
+				RefCount:    2,
+				PacketCount: 10,
+				ByteCount:   1000,
+			},
+			ok: true,
+		},
+		{
+			desc: "buckets",
+			s:    " group_id=10,duration=12.345s,ref_count=1,packet_count=30,byte_count=3000,bucket0:packet_count=20,byte_count=2000,bucket1:packet_count=10,byte_count=1000",
+			stats: &GroupStats{
+				GroupID:     10,
+				Duration:    12345 * time.Millisecond,
+				RefCount:    1,
+				PacketCount: 30,
+				ByteCount:   3000,
+				Buckets: []BucketStats{
+					{PacketCount: 20, ByteCount: 2000},
+					{PacketCount: 10, ByteCount: 1000},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			stats := new(GroupStats)
+			err := stats.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.stats, stats; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected GroupStats:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNoIPFIXTargets is returned when an IPFIX does not specify any collector
+// targets.
+var errNoIPFIXTargets = errors.New("no targets specified for ipfix")
+
+// An IPFIX is an IPFIX export configuration, as stored in the IPFIX table of
+// the Open vSwitch database.  Zero values are omitted, so the Open vSwitch
+// defaults are used.
+type IPFIX struct {
+	// Targets contains the collectors to which flow records are sent, of
+	// the form "ip:port".
+	Targets []string
+
+	// Sampling is the rate at which packets are sampled: on average, one of
+	// every Sampling packets is sampled.  Only used by bridge-level
+	// configurations; flow-based sampling uses the sample action's
+	// probability.
+	Sampling int
+
+	// ObsDomainID and ObsPointID are the IPFIX Observation Domain ID and
+	// Observation Point ID sent in each record.  Only used by bridge-level
+	// configurations; flow-based sampling uses the IDs specified by the
+	// sample action.
+	ObsDomainID uint32
+	ObsPointID  uint32
+
+	// CacheActiveTimeout is the maximum time, in seconds, for which flow
+	// records are cached before they are sent, and CacheMaxFlows is the
+	// maximum number of flow records which are cached.
+	CacheActiveTimeout int
+	CacheMaxFlows      int
+}
+
+// args creates the ovs-vsctl arguments which configure an IPFIX row.
+func (i IPFIX) args() []string {
+	s := []string{
+		fmt.Sprintf("targets=%s", stringSet(i.Targets)),
+	}
+
+	if i.Sampling > 0 {
+		s = append(s, fmt.Sprintf("sampling=%d", i.Sampling))
+	}
+	if i.ObsDomainID > 0 {
+		s = append(s, fmt.Sprintf("obs_domain_id=%d", i.ObsDomainID))
+	}
+	if i.ObsPointID > 0 {
+		s = append(s, fmt.Sprintf("obs_point_id=%d", i.ObsPointID))
+	}
+	if i.CacheActiveTimeout > 0 {
+		s = append(s, fmt.Sprintf("cache_active_timeout=%d", i.CacheActiveTimeout))
+	}
+	if i.CacheMaxFlows > 0 {
+		s = append(s, fmt.Sprintf("cache_max_flows=%d", i.CacheMaxFlows))
+	}
+
+	return s
+}
+
+// EnableIPFIX configures the specified bridge to sample packets and export
+// flow records using the configuration in ipfix.  Any bridge-level IPFIX
+// configuration which was previously applied to the bridge is replaced.
+func (v *VSwitchService) EnableIPFIX(bridge string, ipfix IPFIX) error {
+	if len(ipfix.Targets) == 0 {
+		return errNoIPFIXTargets
+	}
+
+	args := []string{
+		"set", "bridge", bridge, "ipfix=@ipfix",
+		"--", "--id=@ipfix", "create", "ipfix",
+	}
+	args = append(args, ipfix.args()...)
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// DisableIPFIX removes the bridge-level IPFIX configuration of the specified
+// bridge.  If IPFIX is not enabled on the bridge, DisableIPFIX has no effect.
+// Flow sample collector sets are not affected.
+func (v *VSwitchService) DisableIPFIX(bridge string) error {
+	_, err := v.exec("clear", "bridge", bridge, "ipfix")
+	return err
+}
+
+// AddFlowSampleCollectorSet creates a flow sample collector set with the
+// specified ID on the specified bridge, which exports flow records for the
+// packets sampled by flows with the sample action using the configuration
+// in ipfix.
+func (v *VSwitchService) AddFlowSampleCollectorSet(bridge string, id uint32, ipfix IPFIX) error {
+	if len(ipfix.Targets) == 0 {
+		return errNoIPFIXTargets
+	}
+
+	args := []string{
+		"--", "--id=@br", "get", "bridge", bridge,
+		"--", "create", "flow_sample_collector_set",
+		fmt.Sprintf("id=%d", id), "bridge=@br", "ipfix=@ipfix",
+		"--", "--id=@ipfix", "create", "ipfix",
+	}
+	args = append(args, ipfix.args()...)
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// DeleteFlowSampleCollectorSet deletes the flow sample collector sets with
+// the specified ID.  If no such collector sets exist,
+// DeleteFlowSampleCollectorSet has no effect.
+func (v *VSwitchService) DeleteFlowSampleCollectorSet(id uint32) error {
+	sets, err := v.Find("flow_sample_collector_set", fmt.Sprintf("id=%d", id))
+	if err != nil {
+		return err
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	var args []string
+	for _, s := range sets {
+		args = append(args, "--", "destroy", "flow_sample_collector_set", s.UUID())
+	}
+
+	_, err = v.exec(args...)
+	return err
+}
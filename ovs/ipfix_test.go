@@ -0,0 +1,163 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchIPFIXNoTargets(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if want, got := errNoIPFIXTargets, c.VSwitch.EnableIPFIX("br0", IPFIX{}); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	if want, got := errNoIPFIXTargets, c.VSwitch.AddFlowSampleCollectorSet("br0", 1, IPFIX{}); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchEnableIPFIXOK(t *testing.T) {
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-vsctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"--timeout=1",
+			"set", "bridge", "br0", "ipfix=@ipfix",
+			"--", "--id=@ipfix", "create", "ipfix",
+			`targets=["192.0.2.1:4739"]`,
+			"sampling=64",
+			"obs_domain_id=123",
+			"obs_point_id=456",
+			"cache_active_timeout=60",
+			"cache_max_flows=1000",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	err := c.VSwitch.EnableIPFIX("br0", IPFIX{
+		Targets:            []string{"192.0.2.1:4739"},
+		Sampling:           64,
+		ObsDomainID:        123,
+		ObsPointID:         456,
+		CacheActiveTimeout: 60,
+		CacheMaxFlows:      1000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.EnableIPFIX: %v", err)
+	}
+}
+
+func TestClientVSwitchDisableIPFIXOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"clear", "bridge", "br0", "ipfix"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.VSwitch.DisableIPFIX("br0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.DisableIPFIX: %v", err)
+	}
+}
+
+func TestClientVSwitchAddFlowSampleCollectorSetOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"--", "--id=@br", "get", "bridge", "br0",
+			"--", "create", "flow_sample_collector_set",
+			"id=1", "bridge=@br", "ipfix=@ipfix",
+			"--", "--id=@ipfix", "create", "ipfix",
+			`targets=["192.0.2.1:4739"]`,
+			"cache_max_flows=10",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	err := c.VSwitch.AddFlowSampleCollectorSet("br0", 1, IPFIX{
+		Targets:       []string{"192.0.2.1:4739"},
+		CacheMaxFlows: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.AddFlowSampleCollectorSet: %v", err)
+	}
+}
+
+func TestClientVSwitchDeleteFlowSampleCollectorSet(t *testing.T) {
+	var tests = []struct {
+		desc string
+		out  string
+		cmds [][]string
+	}{
+		{
+			desc: "not found",
+			out:  `{"data":[],"headings":["_uuid"]}`,
+		},
+		{
+			desc: "found",
+			out:  `{"data":[[["uuid","5f3a7a3e-0000-4000-8000-000000000001"]],[["uuid","5f3a7a3e-0000-4000-8000-000000000002"]]],"headings":["_uuid"]}`,
+			cmds: [][]string{{
+				"--", "destroy", "flow_sample_collector_set", "5f3a7a3e-0000-4000-8000-000000000001",
+				"--", "destroy", "flow_sample_collector_set", "5f3a7a3e-0000-4000-8000-000000000002",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var cmds [][]string
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				wantFind := []string{"--format=json", "--data=json", "find", "flow_sample_collector_set", "id=1"}
+				if reflect.DeepEqual(wantFind, args) {
+					return []byte(tt.out), nil
+				}
+
+				cmds = append(cmds, args)
+				return nil, nil
+			})
+
+			if err := c.VSwitch.DeleteFlowSampleCollectorSet(1); err != nil {
+				t.Fatalf("unexpected error for Client.VSwitch.DeleteFlowSampleCollectorSet: %v", err)
+			}
+
+			if want, got := tt.cmds, cmds; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLACPStatus is returned when LACP status output from
+// 'ovs-appctl lacp/show' is not in the expected format.
+var ErrInvalidLACPStatus = errors.New("invalid lacp status")
+
+// An LACPStatus is the status of LACP negotiation on a bonded port, as
+// reported by 'ovs-appctl lacp/show'.
+type LACPStatus struct {
+	// Name is the name of the bond.
+	Name string
+
+	// Active reports whether LACP is in active mode, rather than passive
+	// mode, and Negotiated whether LACP negotiation succeeded.
+	Active     bool
+	Negotiated bool
+
+	// SystemID and SystemPriority identify the local system.
+	SystemID       string
+	SystemPriority int
+
+	// AggregationKey is the LACP aggregation key of the bond.
+	AggregationKey int
+
+	// Time is the LACP timing mode, such as "slow" or "fast".
+	Time string
+
+	// Members contains the LACP status of each member of the bond.
+	Members []LACPMember
+}
+
+// An LACPMember is the LACP status of a single member of a bond.
+type LACPMember struct {
+	Name string
+
+	// Status is the state of the member's LACP receive state machine, such
+	// as "current", "expired", or "defaulted", and Attached reports whether
+	// the member is attached to the aggregate.
+	Status   string
+	Attached bool
+
+	PortID       int
+	PortPriority int
+	MayEnable    bool
+
+	// ActorState and PartnerState contain the LACP state flags of the
+	// local and remote ends of the member's link, such as "activity" and
+	// "synchronized".
+	ActorState   []string
+	PartnerState []string
+
+	// PartnerSystemID identifies the remote system connected to the
+	// member's link.
+	PartnerSystemID string
+}
+
+// UnmarshalText unmarshals an LACPStatus from textual form as output by
+// 'ovs-appctl lacp/show':
+//
+//	---- bond0 ----
+//		status: active negotiated
+//		sys_id: 00:00:00:00:00:01
+//		sys_priority: 65534
+//		aggregation key: 1
+//		lacp_time: slow
+//
+//	slave: eth0: current attached
+//		port_id: 1
+//		port_priority: 65535
+//		may_enable: true
+//
+//		actor state: activity aggregation synchronized collecting distributing
+//
+//		partner sys_id: 00:00:00:00:00:02
+//		partner state: activity aggregation synchronized collecting distributing
+func (s *LACPStatus) UnmarshalText(b []byte) error {
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		status         = "status"
+		sysID          = "sys_id"
+		sysPriority    = "sys_priority"
+		aggregationKey = "aggregation key"
+		lacpTime       = "lacp_time"
+		portID         = "port_id"
+		portPriority   = "port_priority"
+		mayEnable      = "may_enable"
+		actorState     = "actor state"
+		partnerState   = "partner state"
+		partnerSysID   = "partner sys_id"
+	)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	name, ok := parseStatusHeader(lines[0])
+	if !ok {
+		return ErrInvalidLACPStatus
+	}
+
+	lacp := LACPStatus{
+		Name: name,
+	}
+
+	// member points to the member whose status is being parsed, or is nil
+	// while parsing the bond's own status.
+	var member *LACPMember
+
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			// Ignore informational lines which are not key/value pairs.
+			continue
+		}
+		k, v := kv[0], kv[1]
+
+		// Each member's status begins with a line such as
+		// "member: eth0: current attached".
+		if k == "member" || k == "slave" {
+			m, err := parseLACPMemberLine(v)
+			if err != nil {
+				return err
+			}
+
+			lacp.Members = append(lacp.Members, m)
+			member = &lacp.Members[len(lacp.Members)-1]
+			continue
+		}
+
+		var err error
+		if member == nil {
+			switch k {
+			case status:
+				for _, f := range strings.Fields(v) {
+					switch f {
+					case "active":
+						lacp.Active = true
+					case "negotiated":
+						lacp.Negotiated = true
+					}
+				}
+			case sysID:
+				lacp.SystemID = v
+			case sysPriority:
+				lacp.SystemPriority, err = strconv.Atoi(v)
+			case aggregationKey:
+				lacp.AggregationKey, err = strconv.Atoi(v)
+			case lacpTime:
+				lacp.Time = v
+			}
+		} else {
+			switch k {
+			case portID:
+				member.PortID, err = strconv.Atoi(v)
+			case portPriority:
+				member.PortPriority, err = strconv.Atoi(v)
+			case mayEnable:
+				member.MayEnable = v == "true"
+			case actorState:
+				member.ActorState = strings.Fields(v)
+			case partnerState:
+				member.PartnerState = strings.Fields(v)
+			case partnerSysID:
+				member.PartnerSystemID = v
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*s = lacp
+	return nil
+}
+
+// parseLACPMemberLine parses the name and status of a bond member from a
+// value of the form "eth0: current attached".
+func parseLACPMemberLine(s string) (LACPMember, error) {
+	kv := strings.SplitN(s, ": ", 2)
+	if len(kv) != 2 {
+		return LACPMember{}, ErrInvalidLACPStatus
+	}
+
+	ss := strings.Fields(kv[1])
+	if len(ss) == 0 {
+		return LACPMember{}, ErrInvalidLACPStatus
+	}
+
+	m := LACPMember{
+		Name:   kv[0],
+		Status: ss[0],
+	}
+
+	for _, f := range ss[1:] {
+		if f == "attached" {
+			m.Attached = true
+		}
+	}
+
+	return m, nil
+}
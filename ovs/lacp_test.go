@@ -0,0 +1,134 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLACPStatusUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		l    *LACPStatus
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "no header",
+			s:    "\tstatus: active negotiated\n",
+		},
+		{
+			desc: "invalid priority",
+			s:    "---- bond0 ----\n\tsys_priority: foo\n",
+		},
+		{
+			desc: "invalid member",
+			s:    "---- bond0 ----\n\nslave: eth0\n",
+		},
+		{
+			desc: "OK",
+			s: `---- bond0 ----
+	status: active negotiated
+	sys_id: 00:00:00:00:00:01
+	sys_priority: 65534
+	aggregation key: 1
+	lacp_time: slow
+
+slave: eth0: current attached
+	port_id: 1
+	port_priority: 65535
+	may_enable: true
+
+	actor sys_id: 00:00:00:00:00:01
+	actor sys_priority: 65534
+	actor port_id: 1
+	actor port_priority: 65535
+	actor key: 1
+	actor state: activity aggregation synchronized collecting distributing
+
+	partner sys_id: 00:00:00:00:00:02
+	partner sys_priority: 32768
+	partner port_id: 3
+	partner state: activity aggregation synchronized collecting distributing
+
+member: eth1: defaulted detached
+	port_id: 2
+	port_priority: 65535
+	may_enable: false
+
+	actor state: activity aggregation defaulted
+
+	partner sys_id: 00:00:00:00:00:00
+	partner state:
+`,
+			l: &LACPStatus{
+				Name:           "bond0",
+				Active:         true,
+				Negotiated:     true,
+				SystemID:       "00:00:00:00:00:01",
+				SystemPriority: 65534,
+				AggregationKey: 1,
+				Time:           "slow",
+				Members: []LACPMember{
+					{
+						Name:            "eth0",
+						Status:          "current",
+						Attached:        true,
+						PortID:          1,
+						PortPriority:    65535,
+						MayEnable:       true,
+						ActorState:      []string{"activity", "aggregation", "synchronized", "collecting", "distributing"},
+						PartnerState:    []string{"activity", "aggregation", "synchronized", "collecting", "distributing"},
+						PartnerSystemID: "00:00:00:00:00:02",
+					},
+					{
+						Name:            "eth1",
+						Status:          "defaulted",
+						PortID:          2,
+						PortPriority:    65535,
+						ActorState:      []string{"activity", "aggregation", "defaulted"},
+						PartnerSystemID: "00:00:00:00:00:00",
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := new(LACPStatus)
+			err := l.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.l, l; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected LACPStatus:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
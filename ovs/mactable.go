@@ -0,0 +1,60 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// errInvalidMACTableSize is returned when SetMACTableSize is called
+	// with a size less than 1.
+	errInvalidMACTableSize = errors.New("MAC table size must be at least 1")
+
+	// errInvalidMACAgingTime is returned when SetMACAgingTime is called
+	// with an aging time less than 1 second.
+	errInvalidMACAgingTime = errors.New("MAC aging time must be at least 1 second")
+)
+
+// SetMACTableSize sets the maximum number of MAC addresses which the
+// specified bridge is able to learn.  Open vSwitch defaults to 2048 entries,
+// which may be too few when a bridge forwards traffic for very large L2
+// domains.
+func (v *VSwitchService) SetMACTableSize(bridge string, size int) error {
+	if size < 1 {
+		return errInvalidMACTableSize
+	}
+
+	return v.setBridgeOtherConfig(bridge, "mac-table-size", size)
+}
+
+// SetMACAgingTime sets the number of seconds after which a learned MAC
+// address which has not been seen again is removed from the specified
+// bridge's MAC table.  Open vSwitch defaults to 300 seconds.
+func (v *VSwitchService) SetMACAgingTime(bridge string, seconds int) error {
+	if seconds < 1 {
+		return errInvalidMACAgingTime
+	}
+
+	return v.setBridgeOtherConfig(bridge, "mac-aging-time", seconds)
+}
+
+// setBridgeOtherConfig sets an integer key in the other_config column of the
+// specified bridge.
+func (v *VSwitchService) setBridgeOtherConfig(bridge string, key string, value int) error {
+	_, err := v.exec("set", "bridge", bridge, fmt.Sprintf("other_config:%s=%d", key, value))
+	return err
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchMACTableOK(t *testing.T) {
+	var tests = []struct {
+		desc string
+		fn   func(v *VSwitchService) error
+		args []string
+	}{
+		{
+			desc: "table size",
+			fn: func(v *VSwitchService) error {
+				return v.SetMACTableSize("br0", 65536)
+			},
+			args: []string{"set", "bridge", "br0", "other_config:mac-table-size=65536"},
+		},
+		{
+			desc: "aging time",
+			fn: func(v *VSwitchService) error {
+				return v.SetMACAgingTime("br0", 900)
+			},
+			args: []string{"set", "bridge", "br0", "other_config:mac-aging-time=900"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := "ovs-vsctl", cmd; want != got {
+					t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return nil, nil
+			})
+
+			if err := tt.fn(c.VSwitch); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchMACTableInvalid(t *testing.T) {
+	var tests = []struct {
+		desc string
+		fn   func(v *VSwitchService) error
+		err  error
+	}{
+		{
+			desc: "zero table size",
+			fn: func(v *VSwitchService) error {
+				return v.SetMACTableSize("br0", 0)
+			},
+			err: errInvalidMACTableSize,
+		},
+		{
+			desc: "negative aging time",
+			fn: func(v *VSwitchService) error {
+				return v.SetMACAgingTime("br0", -1)
+			},
+			err: errInvalidMACAgingTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				t.Fatal("command should not have been executed")
+				return nil, nil
+			})
+
+			if want, got := tt.err, tt.fn(c.VSwitch); want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
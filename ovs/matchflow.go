@@ -42,6 +42,10 @@ type MatchFlow struct {
 	Matches  []Match
 	Table    int
 
+	// OutPort, if non-zero, restricts matching to flows which output
+	// packets to the specified port.  Use PortLOCAL for the LOCAL port.
+	OutPort int
+
 	// Cookie indicates a cookie value to use when matching flows.
 	Cookie uint64
 
@@ -105,6 +109,17 @@ func (f *MatchFlow) MarshalText() ([]byte, error) {
 		b = append(b, ',')
 	}
 
+	if f.OutPort != 0 {
+		b = append(b, outPort+"="...)
+
+		if f.OutPort == PortLOCAL {
+			b = append(b, portLOCAL...)
+		} else {
+			b = strconv.AppendInt(b, int64(f.OutPort), 10)
+		}
+		b = append(b, ',')
+	}
+
 	if f.Cookie > 0 {
 		// Hexadecimal cookies and masks are much easier to read.
 		b = append(b, cookie+"="...)
@@ -58,6 +58,24 @@ func TestMatchFlowMarshalText(t *testing.T) {
 			},
 			s: "in_port=LOCAL,table=0",
 		},
+		{
+			desc: "Flow with out_port=1 and cookie, in any table",
+			f: &MatchFlow{
+				OutPort:    1,
+				Cookie:     0x1,
+				CookieMask: 0xf,
+				Table:      AnyTable,
+			},
+			s: "out_port=1,cookie=0x0000000000000001/0x000000000000000f",
+		},
+		{
+			desc: "Flow with out_port=LOCAL",
+			f: &MatchFlow{
+				OutPort: PortLOCAL,
+				Table:   2,
+			},
+			s: "out_port=LOCAL,table=2",
+		},
 		{
 			desc: "ARP Flow",
 			f: &MatchFlow{
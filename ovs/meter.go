@@ -0,0 +1,229 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidMeter is returned when meters from 'ovs-ofctl dump-meters'
+	// do not match the expected output format.
+	ErrInvalidMeter = errors.New("invalid openflow meter")
+
+	// errNoMeterBands is returned when a Meter has no bands.
+	errNoMeterBands = errors.New("no bands defined for meter")
+
+	// errInvalidMeterBandType is returned when a MeterBand has an unknown or
+	// empty MeterBandType.
+	errInvalidMeterBandType = errors.New("invalid or missing meter band type")
+)
+
+// A MeterBandType is the type of a MeterBand, which determines how packets
+// which exceed the band's rate are processed.
+type MeterBandType string
+
+// MeterBandType constants which may be used with a MeterBand.
+const (
+	// MeterBandDrop drops packets which exceed the band's rate.
+	MeterBandDrop MeterBandType = "drop"
+
+	// MeterBandDSCPRemark increases the drop precedence of the DSCP field
+	// of packets which exceed the band's rate.
+	MeterBandDSCPRemark MeterBandType = "dscp_remark"
+)
+
+// A Meter is an OpenFlow meter, as added by 'ovs-ofctl add-meter' and
+// reported by 'ovs-ofctl dump-meters'.  Flows refer to meters using
+// MeterAction.
+type Meter struct {
+	ID uint32
+
+	// PacketsPerSecond specifies that band rates are measured in packets
+	// per second, rather than kilobits per second.
+	PacketsPerSecond bool
+
+	// Burst specifies that the BurstSize of each band is used.
+	Burst bool
+
+	// Stats enables collection of meter statistics.
+	Stats bool
+
+	Bands []MeterBand
+}
+
+// A MeterBand is a rate above which a Meter processes packets according to
+// the band's type.
+type MeterBand struct {
+	Type MeterBandType
+
+	// Rate is measured in kilobits or packets per second, as determined
+	// by Meter.PacketsPerSecond.
+	Rate uint32
+
+	// BurstSize is measured in kilobits or packets, and is only used if
+	// Meter.Burst is set.
+	BurstSize uint32
+
+	// PrecLevel is the amount by which a MeterBandDSCPRemark band
+	// increases the drop precedence.
+	PrecLevel uint8
+}
+
+// Constants used repeatedly when marshaling and unmarshaling meters.
+const (
+	meterID         = "meter"
+	meterKbps       = "kbps"
+	meterPktps      = "pktps"
+	meterBurst      = "burst"
+	meterStats      = "stats"
+	meterBands      = "bands"
+	bandType        = "type"
+	bandRate        = "rate"
+	bandBurstSize   = "burst_size"
+	bandPrecLevel   = "prec_level"
+	meterBandsEqual = meterBands + "="
+)
+
+// MarshalText marshals a Meter into its textual form, as accepted by
+// 'ovs-ofctl add-meter'.
+func (m *Meter) MarshalText() ([]byte, error) {
+	if len(m.Bands) == 0 {
+		return nil, errNoMeterBands
+	}
+
+	b := []byte(meterID + "=")
+	b = strconv.AppendUint(b, uint64(m.ID), 10)
+
+	if m.PacketsPerSecond {
+		b = append(b, ","+meterPktps...)
+	} else {
+		b = append(b, ","+meterKbps...)
+	}
+	if m.Burst {
+		b = append(b, ","+meterBurst...)
+	}
+	if m.Stats {
+		b = append(b, ","+meterStats...)
+	}
+
+	b = append(b, ","+meterBandsEqual...)
+
+	for i, band := range m.Bands {
+		switch band.Type {
+		case MeterBandDrop, MeterBandDSCPRemark:
+		default:
+			return nil, errInvalidMeterBandType
+		}
+
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = append(b, bandType+"="...)
+		b = append(b, band.Type...)
+		b = append(b, ","+bandRate+"="...)
+		b = strconv.AppendUint(b, uint64(band.Rate), 10)
+
+		if band.BurstSize != 0 {
+			b = append(b, ","+bandBurstSize+"="...)
+			b = strconv.AppendUint(b, uint64(band.BurstSize), 10)
+		}
+		if band.Type == MeterBandDSCPRemark {
+			b = append(b, ","+bandPrecLevel+"="...)
+			b = strconv.AppendUint(b, uint64(band.PrecLevel), 10)
+		}
+	}
+
+	return b, nil
+}
+
+// UnmarshalText unmarshals a Meter from textual form as output by
+// 'ovs-ofctl dump-meters', in which each band appears on its own line:
+//
+//	meter=1 kbps burst stats bands=
+//	type=drop rate=1000 burst_size=100
+func (m *Meter) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := string(b)
+
+	var (
+		meter Meter
+		hasID bool
+		band  *MeterBand
+	)
+
+	for _, f := range strings.Fields(s) {
+		switch f {
+		case meterKbps:
+			continue
+		case meterPktps:
+			meter.PacketsPerSecond = true
+			continue
+		case meterBurst:
+			meter.Burst = true
+			continue
+		case meterStats:
+			meter.Stats = true
+			continue
+		case meterBandsEqual:
+			continue
+		}
+
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidMeter
+		}
+
+		if kv[0] == bandType {
+			meter.Bands = append(meter.Bands, MeterBand{
+				Type: MeterBandType(kv[1]),
+			})
+			band = &meter.Bands[len(meter.Bands)-1]
+			continue
+		}
+
+		n, err := strconv.ParseUint(kv[1], 10, 32)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case kv[0] == meterID && band == nil:
+			meter.ID = uint32(n)
+			hasID = true
+		case kv[0] == bandRate && band != nil:
+			band.Rate = uint32(n)
+		case kv[0] == bandBurstSize && band != nil:
+			band.BurstSize = uint32(n)
+		case kv[0] == bandPrecLevel && band != nil:
+			if n > 0xff {
+				return ErrInvalidMeter
+			}
+			band.PrecLevel = uint8(n)
+		default:
+			return ErrInvalidMeter
+		}
+	}
+
+	if !hasID {
+		return ErrInvalidMeter
+	}
+
+	*m = meter
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMeterMarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		m    *Meter
+		s    string
+		err  error
+	}{
+		{
+			desc: "no bands",
+			m:    &Meter{ID: 1},
+			err:  errNoMeterBands,
+		},
+		{
+			desc: "invalid band type",
+			m: &Meter{
+				ID:    1,
+				Bands: []MeterBand{{Rate: 1000}},
+			},
+			err: errInvalidMeterBandType,
+		},
+		{
+			desc: "drop",
+			m: &Meter{
+				ID: 1,
+				Bands: []MeterBand{{
+					Type: MeterBandDrop,
+					Rate: 1000,
+				}},
+			},
+			s: "meter=1,kbps,bands=type=drop,rate=1000",
+		},
+		{
+			desc: "all options",
+			m: &Meter{
+				ID:               2,
+				PacketsPerSecond: true,
+				Burst:            true,
+				Stats:            true,
+				Bands: []MeterBand{
+					{
+						Type:      MeterBandDrop,
+						Rate:      1000,
+						BurstSize: 100,
+					},
+					{
+						Type:      MeterBandDSCPRemark,
+						Rate:      500,
+						PrecLevel: 1,
+					},
+				},
+			},
+			s: "meter=2,pktps,burst,stats,bands=type=drop,rate=1000,burst_size=100,type=dscp_remark,rate=500,prec_level=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.m.MarshalText()
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.s, string(b); want != got {
+				t.Fatalf("unexpected Meter text:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestMeterUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		m    *Meter
+		ok   bool
+	}{
+		{
+			desc: "empty string",
+		},
+		{
+			desc: "no meter ID",
+			s:    "kbps bands=\ntype=drop rate=1000",
+		},
+		{
+			desc: "invalid meter ID",
+			s:    "meter=foo kbps bands=\ntype=drop rate=1000",
+		},
+		{
+			desc: "unknown flag",
+			s:    "meter=1 foo bands=\ntype=drop rate=1000",
+		},
+		{
+			desc: "rate without band",
+			s:    "meter=1 kbps rate=1000",
+		},
+		{
+			desc: "invalid prec level",
+			s:    "meter=1 kbps bands=\ntype=dscp_remark rate=1000 prec_level=256",
+		},
+		{
+			desc: "drop",
+			s:    "meter=1 kbps bands=\ntype=drop rate=1000\n",
+			m: &Meter{
+				ID: 1,
+				Bands: []MeterBand{{
+					Type: MeterBandDrop,
+					Rate: 1000,
+				}},
+			},
+			ok: true,
+		},
+		{
+			desc: "all options",
+			s:    "meter=2 pktps burst stats bands=\ntype=drop rate=1000 burst_size=100\ntype=dscp_remark rate=500 burst_size=50 prec_level=1\n",
+			m: &Meter{
+				ID:               2,
+				PacketsPerSecond: true,
+				Burst:            true,
+				Stats:            true,
+				Bands: []MeterBand{
+					{
+						Type:      MeterBandDrop,
+						Rate:      1000,
+						BurstSize: 100,
+					},
+					{
+						Type:      MeterBandDSCPRemark,
+						Rate:      500,
+						BurstSize: 50,
+						PrecLevel: 1,
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := new(Meter)
+			err := m.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Meter:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
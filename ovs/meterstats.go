@@ -0,0 +1,145 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidMeterStats is returned when meter statistics from 'ovs-ofctl
+	// meter-stats' do not match the expected output format.
+	ErrInvalidMeterStats = errors.New("invalid meter statistics")
+)
+
+// MeterStats contains statistics about an OpenFlow meter, including the
+// number of packets and bytes processed by the meter and by each of its
+// bands.
+type MeterStats struct {
+	MeterID       uint32
+	FlowCount     uint32
+	PacketInCount uint64
+	ByteInCount   uint64
+	Duration      time.Duration
+
+	// Bands contains statistics for each of the meter's bands, in the
+	// order the bands appear in the meter.  A band's counters indicate the
+	// packets and bytes which exceeded the band's rate.
+	Bands []MeterBandStats
+}
+
+// MeterBandStats contains statistics about a single band of an OpenFlow
+// meter.
+type MeterBandStats struct {
+	PacketCount uint64
+	ByteCount   uint64
+}
+
+// UnmarshalText unmarshals a MeterStats from textual form as output by
+// 'ovs-ofctl meter-stats', in which each band appears on its own line:
+//
+//	meter:1 flow_count:1 packet_in_count:10 byte_in_count:1000 duration:12.345s bands:
+//	0: packet_count:5 byte_count:500
+func (m *MeterStats) UnmarshalText(b []byte) error {
+	// Make a copy per documentation for encoding.TextUnmarshaler.
+	s := string(b)
+
+	// Constants only needed within this method, to avoid polluting the
+	// package namespace with generic names
+	const (
+		flowCount     = "flow_count"
+		packetInCount = "packet_in_count"
+		byteInCount   = "byte_in_count"
+		packetCount   = "packet_count"
+		byteCount     = "byte_count"
+	)
+
+	var (
+		stats MeterStats
+		hasID bool
+		band  *MeterBandStats
+	)
+
+	for _, f := range strings.Fields(s) {
+		if f == meterBands+":" {
+			continue
+		}
+
+		// Band counters are introduced by a "N:" field.
+		if strings.HasSuffix(f, ":") {
+			if _, err := strconv.Atoi(strings.TrimSuffix(f, ":")); err != nil {
+				return ErrInvalidMeterStats
+			}
+
+			stats.Bands = append(stats.Bands, MeterBandStats{})
+			band = &stats.Bands[len(stats.Bands)-1]
+			continue
+		}
+
+		kv := strings.SplitN(f, ":", 2)
+		if len(kv) != 2 {
+			return ErrInvalidMeterStats
+		}
+
+		if kv[0] == duration && band == nil {
+			d, err := time.ParseDuration(kv[1])
+			if err != nil {
+				return err
+			}
+
+			stats.Duration = d
+			continue
+		}
+
+		n, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case kv[0] == meterID && band == nil:
+			if n > 0xffffffff {
+				return ErrInvalidMeterStats
+			}
+			stats.MeterID = uint32(n)
+			hasID = true
+		case kv[0] == flowCount && band == nil:
+			if n > 0xffffffff {
+				return ErrInvalidMeterStats
+			}
+			stats.FlowCount = uint32(n)
+		case kv[0] == packetInCount && band == nil:
+			stats.PacketInCount = n
+		case kv[0] == byteInCount && band == nil:
+			stats.ByteInCount = n
+		case kv[0] == packetCount && band != nil:
+			band.PacketCount = n
+		case kv[0] == byteCount && band != nil:
+			band.ByteCount = n
+		default:
+			return ErrInvalidMeterStats
+		}
+	}
+
+	if !hasID {
+		return ErrInvalidMeterStats
+	}
+
+	*m = stats
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMeterStatsUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		s     string
+		stats *MeterStats
+		ok    bool
+	}{
+		{
+			desc: "empty string",
+		},
+		{
+			desc: "no meter ID",
+			s:    "flow_count:1 packet_in_count:10 byte_in_count:1000 duration:1.000s bands:",
+		},
+		{
+			desc: "invalid duration",
+			s:    "meter:1 duration:foo bands:",
+		},
+		{
+			desc: "invalid counter",
+			s:    "meter:1 packet_in_count:foo bands:",
+		},
+		{
+			desc: "unknown field",
+			s:    "meter:1 foo:1 bands:",
+		},
+		{
+			desc: "invalid band",
+			s:    "meter:1 bands:\nfoo: packet_count:1 byte_count:100",
+		},
+		{
+			desc: "band counter before band",
+			s:    "meter:1 packet_count:1",
+		},
+		{
+			desc: "no bands",
+			s:    "meter:1 flow_count:2 packet_in_count:10 byte_in_count:1000 duration:1.500s bands:\n",
+			stats: &MeterStats{
+				MeterID:       1,
+				FlowCount:     2,
+				PacketInCount: 10,
+				ByteInCount:   1000,
+				Duration:      1500 * time.Millisecond,
+			},
+			ok: true,
+		},
+		{
+			desc: "bands",
+			s:    "meter:2 flow_count:1 packet_in_count:30 byte_in_count:3000 duration:12.345s bands:\n0: packet_count:20 byte_count:2000\n1: packet_count:5 byte_count:500\n",
+			stats: &MeterStats{
+				MeterID:       2,
+				FlowCount:     1,
+				PacketInCount: 30,
+				ByteInCount:   3000,
+				Duration:      12345 * time.Millisecond,
+				Bands: []MeterBandStats{
+					{PacketCount: 20, ByteCount: 2000},
+					{PacketCount: 5, ByteCount: 500},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			stats := new(MeterStats)
+			err := stats.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.stats, stats; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected MeterStats:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// errNoMirrorName is returned when a Mirror does not specify a name.
+	errNoMirrorName = errors.New("no name specified for mirror")
+
+	// errInvalidMirrorOutput is returned when a Mirror does not specify
+	// exactly one of an output port or an output VLAN.
+	errInvalidMirrorOutput = errors.New("mirror must specify exactly one of output port or output VLAN")
+)
+
+// A Mirror is a port mirroring configuration for a bridge, as stored in the
+// Mirror table of the Open vSwitch database.  A Mirror selects packets
+// received or transmitted by a bridge, and sends copies of them to an
+// output port (SPAN) or to an output VLAN (RSPAN).
+type Mirror struct {
+	// Name is the name of the mirror, which must be unique.
+	Name string
+
+	// SelectAll selects all packets on the bridge.
+	SelectAll bool
+
+	// SelectSrcPorts and SelectDstPorts select packets received on, and
+	// transmitted by, the named ports.
+	SelectSrcPorts []string
+	SelectDstPorts []string
+
+	// SelectVLANs restricts the selected packets to those on the specified
+	// VLANs.
+	SelectVLANs []int
+
+	// OutputPort is the name of the port to which selected packets are
+	// sent.
+	OutputPort string
+
+	// OutputVLAN is the VLAN to which selected packets are sent.
+	OutputVLAN int
+}
+
+// CreateMirror creates a Mirror on the specified bridge, in a single
+// transaction.
+func (v *VSwitchService) CreateMirror(bridge string, mirror Mirror) error {
+	if mirror.Name == "" {
+		return errNoMirrorName
+	}
+	if (mirror.OutputPort == "") == (mirror.OutputVLAN == 0) {
+		return errInvalidMirrorOutput
+	}
+
+	var (
+		args []string
+		ids  = make(map[string]string)
+	)
+
+	// Each port is referred to using a named UUID, which must be declared
+	// only once.
+	ref := func(port string) string {
+		if id, ok := ids[port]; ok {
+			return id
+		}
+
+		id := "@p" + strconv.Itoa(len(ids))
+		ids[port] = id
+		args = append(args, "--", "--id="+id, "get", "port", port)
+		return id
+	}
+
+	refs := func(ports []string) string {
+		ss := make([]string, 0, len(ports))
+		for _, p := range ports {
+			ss = append(ss, ref(p))
+		}
+
+		return strings.Join(ss, ",")
+	}
+
+	create := []string{
+		"--", "--id=@m", "create", "mirror", fmt.Sprintf("name=%s", mirror.Name),
+	}
+
+	if mirror.SelectAll {
+		create = append(create, "select-all=true")
+	}
+	if len(mirror.SelectSrcPorts) > 0 {
+		create = append(create, "select-src-port="+refs(mirror.SelectSrcPorts))
+	}
+	if len(mirror.SelectDstPorts) > 0 {
+		create = append(create, "select-dst-port="+refs(mirror.SelectDstPorts))
+	}
+	if len(mirror.SelectVLANs) > 0 {
+		vlans := make([]string, 0, len(mirror.SelectVLANs))
+		for _, vlan := range mirror.SelectVLANs {
+			vlans = append(vlans, strconv.Itoa(vlan))
+		}
+
+		create = append(create, "select-vlan="+strings.Join(vlans, ","))
+	}
+
+	if mirror.OutputPort != "" {
+		create = append(create, "output-port="+ref(mirror.OutputPort))
+	} else {
+		create = append(create, fmt.Sprintf("output-vlan=%d", mirror.OutputVLAN))
+	}
+
+	args = append(args, create...)
+	args = append(args, "--", "add", "bridge", bridge, "mirrors", "@m")
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// DeleteMirror deletes the Mirror with the specified name from the specified
+// bridge.
+func (v *VSwitchService) DeleteMirror(bridge string, name string) error {
+	_, err := v.exec(
+		"--", "--id=@m", "get", "mirror", name,
+		"--", "remove", "bridge", bridge, "mirrors", "@m",
+	)
+	return err
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchCreateMirrorInvalid(t *testing.T) {
+	var tests = []struct {
+		desc string
+		m    Mirror
+		err  error
+	}{
+		{
+			desc: "no name",
+			m: Mirror{
+				OutputPort: "tap0",
+			},
+			err: errNoMirrorName,
+		},
+		{
+			desc: "no output",
+			m: Mirror{
+				Name: "m0",
+			},
+			err: errInvalidMirrorOutput,
+		},
+		{
+			desc: "output port and VLAN",
+			m: Mirror{
+				Name:       "m0",
+				OutputPort: "tap0",
+				OutputVLAN: 10,
+			},
+			err: errInvalidMirrorOutput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				t.Fatal("command should not have been executed")
+				return nil, nil
+			})
+
+			if want, got := tt.err, c.VSwitch.CreateMirror("br0", tt.m); want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchCreateMirrorOK(t *testing.T) {
+	var tests = []struct {
+		desc string
+		m    Mirror
+		args []string
+	}{
+		{
+			desc: "SPAN",
+			m: Mirror{
+				Name:           "m0",
+				SelectSrcPorts: []string{"eth0", "eth1"},
+				SelectDstPorts: []string{"eth0"},
+				SelectVLANs:    []int{10, 20},
+				OutputPort:     "tap0",
+			},
+			args: []string{
+				"--timeout=1",
+				"--", "--id=@p0", "get", "port", "eth0",
+				"--", "--id=@p1", "get", "port", "eth1",
+				"--", "--id=@p2", "get", "port", "tap0",
+				"--", "--id=@m", "create", "mirror", "name=m0",
+				"select-src-port=@p0,@p1", "select-dst-port=@p0",
+				"select-vlan=10,20", "output-port=@p2",
+				"--", "add", "bridge", "br0", "mirrors", "@m",
+			},
+		},
+		{
+			desc: "RSPAN",
+			m: Mirror{
+				Name:       "m1",
+				SelectAll:  true,
+				OutputVLAN: 100,
+			},
+			args: []string{
+				"--timeout=1",
+				"--", "--id=@m", "create", "mirror", "name=m1",
+				"select-all=true", "output-vlan=100",
+				"--", "add", "bridge", "br0", "mirrors", "@m",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := "ovs-vsctl", cmd; want != got {
+					t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return nil, nil
+			})
+
+			if err := c.VSwitch.CreateMirror("br0", tt.m); err != nil {
+				t.Fatalf("unexpected error for Client.VSwitch.CreateMirror: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchDeleteMirrorOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"--", "--id=@m", "get", "mirror", "m0",
+			"--", "remove", "bridge", "br0", "mirrors", "@m",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.VSwitch.DeleteMirror("br0", "m0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.DeleteMirror: %v", err)
+	}
+}
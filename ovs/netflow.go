@@ -0,0 +1,55 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNoNetFlowTargets is returned when EnableNetFlow is called without any
+// collector targets.
+var errNoNetFlowTargets = errors.New("no targets specified for netflow")
+
+// EnableNetFlow configures the specified bridge to export NetFlow records to
+// each of targets, which are collectors of the form "ip:port".  Records for
+// long-lived flows are exported every activeTimeout seconds; if zero, the
+// Open vSwitch default is used.  If addIDToInterface is true, the bridge's
+// datapath ID is included in the interface fields of each record, so that
+// multiple bridges may export to the same collector.
+//
+// Any NetFlow configuration which was previously applied to the bridge is
+// replaced.
+func (v *VSwitchService) EnableNetFlow(bridge string, targets []string, activeTimeout int, addIDToInterface bool) error {
+	if len(targets) == 0 {
+		return errNoNetFlowTargets
+	}
+
+	_, err := v.exec(
+		"set", "bridge", bridge, "netflow=@nf",
+		"--", "--id=@nf", "create", "netflow",
+		fmt.Sprintf("targets=%s", stringSet(targets)),
+		fmt.Sprintf("active_timeout=%d", activeTimeout),
+		fmt.Sprintf("add_id_to_interface=%t", addIDToInterface),
+	)
+	return err
+}
+
+// DisableNetFlow removes the NetFlow configuration of the specified bridge.
+// If NetFlow is not enabled on the bridge, DisableNetFlow has no effect.
+func (v *VSwitchService) DisableNetFlow(bridge string) error {
+	_, err := v.exec("clear", "bridge", bridge, "netflow")
+	return err
+}
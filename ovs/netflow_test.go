@@ -0,0 +1,76 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchEnableNetFlowNoTargets(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if want, got := errNoNetFlowTargets, c.VSwitch.EnableNetFlow("br0", nil, 60, false); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchEnableNetFlowOK(t *testing.T) {
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-vsctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"--timeout=1",
+			"set", "bridge", "br0", "netflow=@nf",
+			"--", "--id=@nf", "create", "netflow",
+			`targets=["192.0.2.1:5566","192.0.2.2:5566"]`,
+			"active_timeout=60",
+			"add_id_to_interface=true",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	err := c.VSwitch.EnableNetFlow("br0", []string{"192.0.2.1:5566", "192.0.2.2:5566"}, 60, true)
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.EnableNetFlow: %v", err)
+	}
+}
+
+func TestClientVSwitchDisableNetFlowOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"clear", "bridge", "br0", "netflow"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.VSwitch.DisableNetFlow("br0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.DisableNetFlow: %v", err)
+	}
+}
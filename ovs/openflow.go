@@ -18,9 +18,12 @@ import (
 	"bufio"
 	"bytes"
 	"encoding"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -31,6 +34,14 @@ var (
 	// errNotCommitted is returned when a flow bundle transaction is not
 	// committed, and thus, the contents of the bundle are discarded.
 	errNotCommitted = errors.New("flow bundle not committed, discarding flows")
+
+	// errPacketOutNoPacket is returned when PacketOut is called with an
+	// empty packet.
+	errPacketOutNoPacket = errors.New("no packet specified for packet-out")
+
+	// errPacketOutNoActions is returned when PacketOut is called without
+	// any actions.
+	errPacketOutNoActions = errors.New("no actions specified for packet-out")
 )
 
 // An OpenFlowService is used in a Client to execute 'ovs-ofctl' commands.
@@ -54,8 +65,8 @@ func (o *OpenFlowService) AddFlow(bridge string, flow *Flow) error {
 	return err
 }
 
-// A FlowTransaction is a transaction used when adding or deleting
-// multiple flows using an Open vSwitch flow bundle.
+// A FlowTransaction is a transaction used when adding, modifying, or
+// deleting multiple flows using an Open vSwitch flow bundle.
 type FlowTransaction struct {
 	flows     []flowDirective
 	committed bool
@@ -71,14 +82,37 @@ type flowDirective struct {
 
 // Possible flowDirective directive values.
 const (
-	dirAdd    = "add"
-	dirDelete = "delete"
+	dirAdd          = "add"
+	dirModify       = "modify"
+	dirModifyStrict = "modify_strict"
+	dirDelete       = "delete"
 )
 
 // Add pushes zero or more Flows on to the transaction, to be added by
 // Open vSwitch.  If any of the flows are invalid, Add becomes a no-op
 // and the error will be surfaced when Commit is called.
 func (tx *FlowTransaction) Add(flows ...*Flow) {
+	tx.pushFlows(dirAdd, flows)
+}
+
+// Modify pushes zero or more Flows on to the transaction, to be modified by
+// Open vSwitch.  The actions of all existing flows which match each Flow's
+// table, protocol, input port, and matches are replaced by the Flow's actions,
+// regardless of priority.  If any of the flows are invalid, Modify becomes a
+// no-op and the error will be surfaced when Commit is called.
+func (tx *FlowTransaction) Modify(flows ...*Flow) {
+	tx.pushFlows(dirModify, flows)
+}
+
+// ModifyStrict is like Modify, but only modifies existing flows which have
+// the same priority and exactly the same matches as each Flow.
+func (tx *FlowTransaction) ModifyStrict(flows ...*Flow) {
+	tx.pushFlows(dirModifyStrict, flows)
+}
+
+// pushFlows pushes zero or more Flows on to the transaction with the
+// specified directive.
+func (tx *FlowTransaction) pushFlows(directive string, flows []*Flow) {
 	if tx.err != nil {
 		return
 	}
@@ -88,7 +122,7 @@ func (tx *FlowTransaction) Add(flows ...*Flow) {
 		tms = append(tms, f)
 	}
 
-	tx.push(dirAdd, tms...)
+	tx.push(directive, tms...)
 }
 
 // Delete pushes zero or more MatchFlows on to the transaction, to be deleted
@@ -145,10 +179,12 @@ func (tx *FlowTransaction) Discard(err error) error {
 	return fmt.Errorf("discarding add flow transaction: %v", err)
 }
 
-// AddFlowBundle creates an Open vSwitch flow bundle and enables adding and
-// removing flows to and from the specified bridge using a FlowTransaction.
-// This function enables atomic addition and deletion of flows to and from
-// Open vSwitch.
+// AddFlowBundle creates an Open vSwitch flow bundle and enables adding,
+// modifying, and removing flows on the specified bridge using a
+// FlowTransaction.  This function enables atomic addition, modification, and
+// deletion of flows: either all of the operations in the transaction are
+// applied, or none of them are, and packets are never processed by a
+// partially updated set of flows.
 func (o *OpenFlowService) AddFlowBundle(bridge string, fn func(tx *FlowTransaction) error) error {
 	// Flows will be added to and read from an in-memory buffer.  The buffer's
 	// contents are piped to 'ovs-ofctl' using stdin.
@@ -179,7 +215,76 @@ func (o *OpenFlowService) AddFlowBundle(bridge string, fn func(tx *FlowTransacti
 	// Read from stdin.
 	args = append(args, bridge, "-")
 
-	return o.pipe(buf, args...)
+	_, err := o.pipe(buf, args...)
+	return err
+}
+
+// ReplaceFlows replaces all flows on the specified bridge with flows.  Open
+// vSwitch compares the installed flows with flows, and only adds, modifies,
+// or deletes the flows which differ, so flows which are already in the
+// desired state are left untouched and their statistics are preserved.
+func (o *OpenFlowService) ReplaceFlows(bridge string, flows []*Flow) error {
+	buf, err := flowsBuffer(flows)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"replace-flows"}
+	args = append(args, o.c.ofctlFlags...)
+	// Read from stdin.
+	args = append(args, bridge, "-")
+
+	_, err = o.pipe(buf, args...)
+	return err
+}
+
+// DiffFlows compares the flows installed on the specified bridge with flows,
+// and returns the differences between them.  Applying the differences, such
+// as by using AddFlowBundle or ReplaceFlows, converges the bridge to flows.
+func (o *OpenFlowService) DiffFlows(bridge string, flows []*Flow) (*FlowDiff, error) {
+	buf, err := flowsBuffer(flows)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"diff-flows"}
+	args = append(args, o.c.ofctlFlags...)
+	// Read from stdin.
+	args = append(args, bridge, "-")
+
+	out, err := o.pipe(buf, args...)
+	if err != nil {
+		// 'ovs-ofctl diff-flows' exits unsuccessfully when differences are
+		// found, so its output is still used.
+		pe, ok := err.(*pipeError)
+		if !ok {
+			return nil, err
+		}
+		if status, ok := exitStatus(pe.err); !ok || status != diffFlowsDifferent {
+			return nil, err
+		}
+
+		out = pe.out
+	}
+
+	return parseFlowDiff(out)
+}
+
+// flowsBuffer creates a buffer containing the textual form of flows, one per
+// line, as accepted by 'ovs-ofctl' commands which read flows from a file.
+func flowsBuffer(flows []*Flow) (*bytes.Buffer, error) {
+	buf := bytes.NewBuffer(nil)
+	for _, f := range flows {
+		fb, err := f.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		_, _ = buf.Write(fb)
+		_ = buf.WriteByte('\n')
+	}
+
+	return buf, nil
 }
 
 // DelFlows removes flows that match MatchFlow from a bridge attached to Open vSwitch.
@@ -201,6 +306,245 @@ func (o *OpenFlowService) DelFlows(bridge string, flow *MatchFlow) error {
 	return err
 }
 
+// AddGroup adds a Group to a bridge attached to Open vSwitch.  Groups
+// require OpenFlow 1.1 or later, which may be enabled using the Protocols
+// OptionFunc.
+func (o *OpenFlowService) AddGroup(bridge string, group *Group) error {
+	return o.groupMod("add-group", bridge, group)
+}
+
+// ModGroup modifies an existing Group on a bridge attached to Open vSwitch,
+// replacing its type and buckets.
+func (o *OpenFlowService) ModGroup(bridge string, group *Group) error {
+	return o.groupMod("mod-group", bridge, group)
+}
+
+// DelGroups removes groups with the specified IDs from a bridge attached to
+// Open vSwitch.
+//
+// If no IDs are specified, all groups will be deleted from the specified
+// bridge.
+func (o *OpenFlowService) DelGroups(bridge string, ids ...uint32) error {
+	args := []string{"del-groups"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge)
+
+	if len(ids) == 0 {
+		_, err := o.exec(args...)
+		return err
+	}
+
+	// 'ovs-ofctl del-groups' accepts only a single group per invocation.
+	for _, id := range ids {
+		idArgs := append(args[:len(args):len(args)],
+			groupID+"="+strconv.FormatUint(uint64(id), 10))
+
+		if _, err := o.exec(idArgs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DumpGroups retrieves all groups for the specified bridge.
+func (o *OpenFlowService) DumpGroups(bridge string) ([]*Group, error) {
+	args := []string{"dump-groups"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge)
+
+	out, err := o.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*Group
+	err = parseEachLine(out, dumpGroupsPrefix, func(b []byte) error {
+		g := new(Group)
+		if err := g.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		groups = append(groups, g)
+		return nil
+	})
+
+	return groups, err
+}
+
+// DumpGroupStats retrieves statistics about all groups for the specified
+// bridge, including statistics for each of their buckets.
+func (o *OpenFlowService) DumpGroupStats(bridge string) ([]*GroupStats, error) {
+	args := []string{"dump-group-stats"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge)
+
+	out, err := o.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*GroupStats
+	err = parseEachLine(out, dumpGroupStatsPrefix, func(b []byte) error {
+		s := new(GroupStats)
+		if err := s.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	})
+
+	return stats, err
+}
+
+// AddMeter adds a Meter to a bridge attached to Open vSwitch.  Meters
+// require OpenFlow 1.3 or later, which may be enabled using the Protocols
+// OptionFunc.
+func (o *OpenFlowService) AddMeter(bridge string, meter *Meter) error {
+	return o.meterMod("add-meter", bridge, meter)
+}
+
+// ModMeter modifies an existing Meter on a bridge attached to Open vSwitch,
+// replacing its flags and bands.
+func (o *OpenFlowService) ModMeter(bridge string, meter *Meter) error {
+	return o.meterMod("mod-meter", bridge, meter)
+}
+
+// DelMeter removes the meter with the specified ID from a bridge attached to
+// Open vSwitch.
+func (o *OpenFlowService) DelMeter(bridge string, id uint32) error {
+	args := []string{"del-meter"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge, meterID+"="+strconv.FormatUint(uint64(id), 10))
+
+	_, err := o.exec(args...)
+	return err
+}
+
+// DumpMeters retrieves all meters for the specified bridge.
+func (o *OpenFlowService) DumpMeters(bridge string) ([]*Meter, error) {
+	args := []string{"dump-meters"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge)
+
+	out, err := o.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var meters []*Meter
+	err = parseEachBlock(out, dumpMetersPrefix, []byte(meterID+"="), ErrInvalidMeter, func(b []byte) error {
+		m := new(Meter)
+		if err := m.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		meters = append(meters, m)
+		return nil
+	})
+
+	return meters, err
+}
+
+// DumpMeterStats retrieves statistics about all meters for the specified
+// bridge, including statistics for each of their bands.
+func (o *OpenFlowService) DumpMeterStats(bridge string) ([]*MeterStats, error) {
+	args := []string{"meter-stats"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge)
+
+	out, err := o.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*MeterStats
+	err = parseEachBlock(out, dumpMeterStatsPrefix, []byte(meterID+":"), ErrInvalidMeterStats, func(b []byte) error {
+		s := new(MeterStats)
+		if err := s.UnmarshalText(b); err != nil {
+			return err
+		}
+
+		stats = append(stats, s)
+		return nil
+	})
+
+	return stats, err
+}
+
+// meterMod calls the specified 'ovs-ofctl' meter modification command with
+// the textual form of meter.
+func (o *OpenFlowService) meterMod(command string, bridge string, meter *Meter) error {
+	mb, err := meter.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	args := []string{command}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge, string(mb))
+
+	_, err = o.exec(args...)
+	return err
+}
+
+// groupMod calls the specified 'ovs-ofctl' group modification command with
+// the textual form of group.
+func (o *OpenFlowService) groupMod(command string, bridge string, group *Group) error {
+	gb, err := group.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	args := []string{command}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge, string(gb))
+
+	_, err = o.exec(args...)
+	return err
+}
+
+// PacketOut injects packet, a complete Ethernet frame, into the specified
+// bridge as though it had been received on inPort, and processes it using
+// actions, such as Output or Resubmit.  Use PortLOCAL to inject the packet
+// from the LOCAL port, or zero to inject it from the controller.
+func (o *OpenFlowService) PacketOut(bridge string, inPort int, packet []byte, actions ...Action) error {
+	if len(packet) == 0 {
+		return errPacketOutNoPacket
+	}
+	if len(actions) == 0 {
+		return errPacketOutNoActions
+	}
+
+	as := make([]string, 0, len(actions))
+	for _, a := range actions {
+		ab, err := a.MarshalText()
+		if err != nil {
+			return err
+		}
+
+		as = append(as, string(ab))
+	}
+
+	var port string
+	switch inPort {
+	case 0:
+		port = portCONTROLLER
+	case PortLOCAL:
+		port = portLOCAL
+	default:
+		port = strconv.Itoa(inPort)
+	}
+
+	args := []string{"packet-out"}
+	args = append(args, o.c.ofctlFlags...)
+	args = append(args, bridge, port, strings.Join(as, ","), hex.EncodeToString(packet))
+
+	_, err := o.exec(args...)
+	return err
+}
+
 // ModPort modifies the specified characteristics for the specified port.
 func (o *OpenFlowService) ModPort(bridge string, port string, action PortAction) error {
 	_, err := o.exec("mod-port", bridge, string(port), string(action))
@@ -259,7 +603,28 @@ func (o *OpenFlowService) DumpTables(bridge string) ([]*Table, error) {
 // If a table has no active flows and has not been used for a lookup or matched
 // by an incoming packet, it is filtered from the output.
 func (o *OpenFlowService) DumpFlows(bridge string) ([]*Flow, error) {
-	out, err := o.exec("dump-flows", bridge)
+	return o.DumpFlowsWithFlowArgs(bridge, nil)
+}
+
+// DumpFlowsWithFlowArgs retrieves statistics about the flows for the
+// specified bridge which match flow, so that only the flows of interest are
+// retrieved and parsed.  flow may specify a table, cookie and cookie mask,
+// output port, and any other matches.  Use AnyTable to match flows in all
+// tables.
+//
+// If flow is nil, all flows are retrieved, as with DumpFlows.
+func (o *OpenFlowService) DumpFlowsWithFlowArgs(bridge string, flow *MatchFlow) ([]*Flow, error) {
+	args := []string{"dump-flows", bridge}
+	if flow != nil {
+		fb, err := flow.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, string(fb))
+	}
+
+	out, err := o.exec(args...)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +675,22 @@ var (
 	// dumpAggregatePrefix is a sentinel value returned at the beginning of
 	// the output from "ovs-ofctl dump-aggregate"
 	dumpAggregatePrefix = []byte("NXST_AGGREGATE reply")
+
+	// dumpGroupsPrefix is a sentinel value returned at the beginning of
+	// the output from 'ovs-ofctl dump-groups'.
+	dumpGroupsPrefix = []byte("OFPST_GROUP_DESC reply")
+
+	// dumpGroupStatsPrefix is a sentinel value returned at the beginning of
+	// the output from 'ovs-ofctl dump-group-stats'.
+	dumpGroupStatsPrefix = []byte("OFPST_GROUP reply")
+
+	// dumpMetersPrefix is a sentinel value returned at the beginning of
+	// the output from 'ovs-ofctl dump-meters'.
+	dumpMetersPrefix = []byte("OFPST_METER_CONFIG reply")
+
+	// dumpMeterStatsPrefix is a sentinel value returned at the beginning of
+	// the output from 'ovs-ofctl meter-stats'.
+	dumpMeterStatsPrefix = []byte("OFPST_METER reply")
 )
 
 // dumpPorts calls 'ovs-ofctl dump-ports' with the specified arguments and
@@ -405,6 +786,49 @@ func parseEachLine(in []byte, prefix []byte, fn func(b []byte) error) error {
 	return scanner.Err()
 }
 
+// parseEachBlock parses ovs-ofctl output from the input buffer, ensuring it
+// has the specified prefix, and invoking the input function on each block of
+// lines which begins with a line that has the specified start prefix, so
+// structures which span multiple lines can be parsed.  If any non-empty lines
+// appear before the first block, errInvalid is returned.
+func parseEachBlock(in []byte, prefix []byte, start []byte, errInvalid error, fn func(b []byte) error) error {
+	var buf []byte
+
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+
+		b := buf
+		buf = nil
+		return fn(b)
+	}
+
+	err := parseEachLine(in, prefix, func(b []byte) error {
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			return nil
+		}
+
+		if bytes.HasPrefix(b, start) {
+			if err := flush(); err != nil {
+				return err
+			}
+		} else if len(buf) == 0 {
+			return errInvalid
+		}
+
+		buf = append(buf, b...)
+		buf = append(buf, '\n')
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
 // parseEach parses ovs-ofctl output from the input buffer, ensuring it has the
 // specified prefix, and invoking the input function on each two lines scanned,
 // so more complex structures can be parsed.
@@ -470,6 +894,6 @@ func (o *OpenFlowService) exec(args ...string) ([]byte, error) {
 }
 
 // pipe executes a PipeFunc using 'ovs-ofctl'.
-func (o *OpenFlowService) pipe(stdin io.Reader, args ...string) error {
+func (o *OpenFlowService) pipe(stdin io.Reader, args ...string) ([]byte, error) {
 	return o.c.pipe(stdin, "ovs-ofctl", args...)
 }
@@ -19,10 +19,12 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"io/ioutil"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestClientOpenFlowAddFlowInvalidFlow(t *testing.T) {
@@ -1119,3 +1121,854 @@ func mustVerifyFlowBundle(t *testing.T, stdin io.Reader, flows []*Flow, matchFlo
 		}
 	}
 }
+
+func TestClientOpenFlowAddGroupOK(t *testing.T) {
+	group := &Group{
+		ID:   1,
+		Type: GroupSelect,
+		Buckets: []Bucket{
+			{Actions: []Action{Output(1)}},
+			{Actions: []Action{Output(2)}},
+		},
+	}
+
+	options := []OptionFunc{
+		Timeout(1),
+		Protocols([]string{ProtocolOpenFlow13}),
+	}
+
+	c := testClient(options, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-ofctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"--timeout=1",
+			"add-group",
+			"--protocols=OpenFlow13",
+			"br0",
+			"group_id=1,type=select,bucket=actions=output:1,bucket=actions=output:2",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.OpenFlow.AddGroup("br0", group); err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.AddGroup: %v", err)
+	}
+}
+
+func TestClientOpenFlowModGroupOK(t *testing.T) {
+	group := &Group{
+		ID:   1,
+		Type: GroupIndirect,
+		Buckets: []Bucket{
+			{Actions: []Action{Output(3)}},
+		},
+	}
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{
+			"mod-group",
+			"br0",
+			"group_id=1,type=indirect,bucket=actions=output:3",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.OpenFlow.ModGroup("br0", group); err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.ModGroup: %v", err)
+	}
+}
+
+func TestClientOpenFlowModGroupInvalidGroup(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not be executed")
+		return nil, nil
+	})
+
+	err := c.OpenFlow.ModGroup("br0", &Group{ID: 1})
+	if want, got := errInvalidGroupType, err; want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDelGroups(t *testing.T) {
+	tests := []struct {
+		desc string
+		ids  []uint32
+		want [][]string
+	}{
+		{
+			desc: "all groups",
+			want: [][]string{
+				{"del-groups", "--protocols=OpenFlow13", "br0"},
+			},
+		},
+		{
+			desc: "specified groups",
+			ids:  []uint32{1, 2},
+			want: [][]string{
+				{"del-groups", "--protocols=OpenFlow13", "br0", "group_id=1"},
+				{"del-groups", "--protocols=OpenFlow13", "br0", "group_id=2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got [][]string
+			c := testClient([]OptionFunc{Protocols([]string{ProtocolOpenFlow13})}, func(cmd string, args ...string) ([]byte, error) {
+				got = append(got, args)
+				return nil, nil
+			})
+
+			if err := c.OpenFlow.DelGroups("br0", tt.ids...); err != nil {
+				t.Fatalf("unexpected error for Client.OpenFlow.DelGroups: %v", err)
+			}
+
+			if want := tt.want; !reflect.DeepEqual(want, got) {
+				t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientOpenFlowDumpGroups(t *testing.T) {
+	tests := []struct {
+		desc   string
+		out    string
+		groups []*Group
+		err    error
+	}{
+		{
+			desc: "unexpected prefix",
+			out:  "NXST_FLOW reply (xid=0x4):\n",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "invalid group",
+			out: `OFPST_GROUP_DESC reply (OF1.3) (xid=0x2):
+ group_id=1
+`,
+			err: ErrInvalidGroup,
+		},
+		{
+			desc: "no groups",
+			out:  "OFPST_GROUP_DESC reply (OF1.3) (xid=0x2):\n",
+		},
+		{
+			desc: "groups",
+			out: `OFPST_GROUP_DESC reply (OF1.3) (xid=0x2):
+ group_id=1,type=select,bucket=weight:100,actions=output:1,bucket=weight:100,actions=output:2
+ group_id=2,type=ff,bucket=watch_port:1,actions=output:1,bucket=watch_port:2,actions=output:2
+`,
+			groups: []*Group{
+				{
+					ID:   1,
+					Type: GroupSelect,
+					Buckets: []Bucket{
+						{Weight: 100, Actions: []Action{Output(1)}},
+						{Weight: 100, Actions: []Action{Output(2)}},
+					},
+				},
+				{
+					ID:   2,
+					Type: GroupFastFailover,
+					Buckets: []Bucket{
+						{WatchPort: 1, Actions: []Action{Output(1)}},
+						{WatchPort: 2, Actions: []Action{Output(2)}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				wantArgs := []string{"dump-groups", "br0"}
+				if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return []byte(tt.out), nil
+			})
+
+			groups, err := c.OpenFlow.DumpGroups("br0")
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.groups, groups; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected groups:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientOpenFlowDumpGroupStatsOK(t *testing.T) {
+	c := testClient([]OptionFunc{Protocols([]string{ProtocolOpenFlow13})}, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"dump-group-stats", "--protocols=OpenFlow13", "br0"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`OFPST_GROUP reply (OF1.3) (xid=0x2):
+ group_id=1,duration=10.000s,ref_count=1,packet_count=3,byte_count=300,bucket0:packet_count=2,byte_count=200,bucket1:packet_count=1,byte_count=100
+ group_id=2,duration=5.000s,ref_count=0,packet_count=0,byte_count=0
+`), nil
+	})
+
+	stats, err := c.OpenFlow.DumpGroupStats("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.DumpGroupStats: %v", err)
+	}
+
+	want := []*GroupStats{
+		{
+			GroupID:     1,
+			Duration:    10 * time.Second,
+			RefCount:    1,
+			PacketCount: 3,
+			ByteCount:   300,
+			Buckets: []BucketStats{
+				{PacketCount: 2, ByteCount: 200},
+				{PacketCount: 1, ByteCount: 100},
+			},
+		},
+		{
+			GroupID:  2,
+			Duration: 5 * time.Second,
+		},
+	}
+
+	if want, got := want, stats; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected group stats:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDumpGroupStatsInvalid(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return []byte("OFPST_GROUP reply (OF1.3) (xid=0x2):\n foo\n"), nil
+	})
+
+	_, err := c.OpenFlow.DumpGroupStats("br0")
+	if want, got := ErrInvalidGroupStats, err; want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowAddMeterOK(t *testing.T) {
+	meter := &Meter{
+		ID: 1,
+		Bands: []MeterBand{{
+			Type: MeterBandDrop,
+			Rate: 1000,
+		}},
+	}
+
+	c := testClient([]OptionFunc{Protocols([]string{ProtocolOpenFlow13})}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-ofctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"add-meter",
+			"--protocols=OpenFlow13",
+			"br0",
+			"meter=1,kbps,bands=type=drop,rate=1000",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.OpenFlow.AddMeter("br0", meter); err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.AddMeter: %v", err)
+	}
+}
+
+func TestClientOpenFlowModMeterInvalidMeter(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not be executed")
+		return nil, nil
+	})
+
+	err := c.OpenFlow.ModMeter("br0", &Meter{ID: 1})
+	if want, got := errNoMeterBands, err; want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDelMeterOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"del-meter", "br0", "meter=10"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.OpenFlow.DelMeter("br0", 10); err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.DelMeter: %v", err)
+	}
+}
+
+func TestClientOpenFlowDumpMeters(t *testing.T) {
+	tests := []struct {
+		desc   string
+		out    string
+		meters []*Meter
+		err    error
+	}{
+		{
+			desc: "unexpected prefix",
+			out:  "OFPST_GROUP reply (OF1.3) (xid=0x2):\n",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "band without meter",
+			out: `OFPST_METER_CONFIG reply (OF1.3) (xid=0x2):
+type=drop rate=1000
+`,
+			err: ErrInvalidMeter,
+		},
+		{
+			desc: "no meters",
+			out:  "OFPST_METER_CONFIG reply (OF1.3) (xid=0x2):\n",
+		},
+		{
+			desc: "meters",
+			out: `OFPST_METER_CONFIG reply (OF1.3) (xid=0x2):
+meter=1 kbps bands=
+type=drop rate=1000
+
+meter=2 pktps burst stats bands=
+type=drop rate=100 burst_size=10
+type=dscp_remark rate=50 burst_size=5 prec_level=2
+`,
+			meters: []*Meter{
+				{
+					ID: 1,
+					Bands: []MeterBand{
+						{Type: MeterBandDrop, Rate: 1000},
+					},
+				},
+				{
+					ID:               2,
+					PacketsPerSecond: true,
+					Burst:            true,
+					Stats:            true,
+					Bands: []MeterBand{
+						{Type: MeterBandDrop, Rate: 100, BurstSize: 10},
+						{Type: MeterBandDSCPRemark, Rate: 50, BurstSize: 5, PrecLevel: 2},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				wantArgs := []string{"dump-meters", "br0"}
+				if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return []byte(tt.out), nil
+			})
+
+			meters, err := c.OpenFlow.DumpMeters("br0")
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.meters, meters; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected meters:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientOpenFlowDumpMeterStatsOK(t *testing.T) {
+	c := testClient([]OptionFunc{Protocols([]string{ProtocolOpenFlow13})}, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"meter-stats", "--protocols=OpenFlow13", "br0"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`OFPST_METER reply (OF1.3) (xid=0x2):
+meter:1 flow_count:1 packet_in_count:10 byte_in_count:1000 duration:10.000s bands:
+0: packet_count:4 byte_count:400
+
+meter:2 flow_count:0 packet_in_count:0 byte_in_count:0 duration:5.000s bands:
+0: packet_count:0 byte_count:0
+`), nil
+	})
+
+	stats, err := c.OpenFlow.DumpMeterStats("br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.DumpMeterStats: %v", err)
+	}
+
+	want := []*MeterStats{
+		{
+			MeterID:       1,
+			FlowCount:     1,
+			PacketInCount: 10,
+			ByteInCount:   1000,
+			Duration:      10 * time.Second,
+			Bands: []MeterBandStats{
+				{PacketCount: 4, ByteCount: 400},
+			},
+		},
+		{
+			MeterID:  2,
+			Duration: 5 * time.Second,
+			Bands:    []MeterBandStats{{}},
+		},
+	}
+
+	if want, got := want, stats; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected meter stats:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDumpMeterStatsInvalid(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return []byte("OFPST_METER reply (OF1.3) (xid=0x2):\n0: packet_count:0 byte_count:0\n"), nil
+	})
+
+	_, err := c.OpenFlow.DumpMeterStats("br0")
+	if want, got := ErrInvalidMeterStats, err; want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowAddFlowBundleModifyOK(t *testing.T) {
+	add := &Flow{
+		Priority: 10,
+		Protocol: ProtocolIPv4,
+		Actions:  []Action{Normal()},
+	}
+	modify := &Flow{
+		Priority: 20,
+		Protocol: ProtocolIPv6,
+		Actions:  []Action{Drop()},
+	}
+	modifyStrict := &Flow{
+		Priority: 30,
+		InPort:   1,
+		Actions:  []Action{Output(2)},
+	}
+	del := &MatchFlow{
+		Cookie: 0xdeadbeef,
+	}
+
+	var stdin []byte
+	pipe := Pipe(func(r io.Reader, cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"--bundle", "add-flow", "br0", "-"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read flow bundle: %v", err)
+		}
+
+		stdin = b
+		return nil, nil
+	})
+
+	c := testClient([]OptionFunc{pipe}, nil)
+
+	err := c.OpenFlow.AddFlowBundle("br0", func(tx *FlowTransaction) error {
+		tx.Delete(del)
+		tx.Add(add)
+		tx.Modify(modify)
+		tx.ModifyStrict(modifyStrict)
+
+		return tx.Commit()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.AddFlowBundle: %v", err)
+	}
+
+	// Directives must be applied in the order they were pushed.
+	want := strings.Join([]string{
+		"delete cookie=0x00000000deadbeef/-1,table=0",
+		"add priority=10,ip,table=0,idle_timeout=0,actions=normal",
+		"modify priority=20,ipv6,table=0,idle_timeout=0,actions=drop",
+		"modify_strict priority=30,in_port=1,table=0,idle_timeout=0,actions=output:2",
+	}, "\n") + "\n"
+
+	if got := string(stdin); want != got {
+		t.Fatalf("unexpected flow bundle:\n- want: %q\n-  got: %q",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowAddFlowBundleModifyError(t *testing.T) {
+	c := testClient(nil, nil)
+	err := c.OpenFlow.AddFlowBundle("br0", func(tx *FlowTransaction) error {
+		// No actions, malformed flow.
+		tx.Modify(&Flow{Priority: 10})
+		tx.ModifyStrict(&Flow{
+			Priority: 20,
+			Actions:  []Action{Drop()},
+		})
+
+		return tx.Commit()
+	})
+
+	want := &FlowError{
+		Err: errNoActions,
+	}
+	if got := err; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected error for Client.OpenFlow.AddFlowBundle:\n- want: %#v\n-  got: %#v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowReplaceFlowsOK(t *testing.T) {
+	flows := []*Flow{
+		{
+			Priority: 10,
+			Protocol: ProtocolIPv4,
+			Actions:  []Action{Drop()},
+		},
+		{
+			Priority: 20,
+			InPort:   1,
+			Actions:  []Action{Normal()},
+		},
+	}
+
+	pipe := Pipe(func(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-ofctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{"replace-flows", "--flow-format=NXM+table_id", "br0", "-"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			t.Fatalf("failed to read flows: %v", err)
+		}
+
+		want := "priority=10,ip,table=0,idle_timeout=0,actions=drop\n" +
+			"priority=20,in_port=1,table=0,idle_timeout=0,actions=normal\n"
+		if got := string(b); want != got {
+			t.Fatalf("unexpected flows:\n- want: %q\n-  got: %q",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	c := testClient([]OptionFunc{FlowFormat(FlowFormatNXMTableID), pipe}, nil)
+
+	if err := c.OpenFlow.ReplaceFlows("br0", flows); err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.ReplaceFlows: %v", err)
+	}
+}
+
+func TestClientOpenFlowReplaceFlowsInvalidFlow(t *testing.T) {
+	c := testClient([]OptionFunc{Pipe(func(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not be executed")
+		return nil, nil
+	})}, nil)
+
+	err := c.OpenFlow.ReplaceFlows("br0", []*Flow{{Priority: 10}})
+
+	want := &FlowError{
+		Err: errNoActions,
+	}
+	if got := err; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected error for Client.OpenFlow.ReplaceFlows:\n- want: %#v\n-  got: %#v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDiffFlows(t *testing.T) {
+	flows := []*Flow{{
+		Priority: 10,
+		Protocol: ProtocolIPv4,
+		Actions:  []Action{Output(1)},
+	}}
+
+	tests := []struct {
+		desc string
+		out  string
+		err  error
+		d    *FlowDiff
+		ok   bool
+	}{
+		{
+			desc: "no differences",
+			d:    &FlowDiff{},
+			ok:   true,
+		},
+		{
+			desc: "differences",
+			out:  "-priority=10,ip actions=drop\n+priority=10,ip actions=output:1\n",
+			err:  mustExitError(t, diffFlowsDifferent),
+			d: &FlowDiff{
+				Removed: []*Flow{{
+					Priority: 10,
+					Protocol: ProtocolIPv4,
+					Matches:  []Match{},
+					Actions:  []Action{Drop()},
+				}},
+				Added: []*Flow{{
+					Priority: 10,
+					Protocol: ProtocolIPv4,
+					Matches:  []Match{},
+					Actions:  []Action{Output(1)},
+				}},
+			},
+			ok: true,
+		},
+		{
+			desc: "command failed",
+			out:  "ovs-ofctl: br0 is not a bridge or a socket",
+			err:  mustExitError(t, 1),
+		},
+		{
+			desc: "other error",
+			err:  errors.New("foo"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			pipe := Pipe(func(stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+				wantArgs := []string{"diff-flows", "br0", "-"}
+				if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				b, err := ioutil.ReadAll(stdin)
+				if err != nil {
+					t.Fatalf("failed to read flows: %v", err)
+				}
+
+				want := "priority=10,ip,table=0,idle_timeout=0,actions=output:1\n"
+				if got := string(b); want != got {
+					t.Fatalf("unexpected flows:\n- want: %q\n-  got: %q",
+						want, got)
+				}
+
+				return []byte(tt.out), tt.err
+			})
+
+			c := testClient([]OptionFunc{pipe}, nil)
+
+			d, err := c.OpenFlow.DiffFlows("br0", flows)
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if !tt.ok {
+				return
+			}
+
+			if want, got := tt.d, d; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected FlowDiff:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientOpenFlowDumpFlowsWithFlowArgs(t *testing.T) {
+	flow := &MatchFlow{
+		Protocol:   ProtocolIPv4,
+		Cookie:     0x10,
+		CookieMask: 0xf0,
+		OutPort:    2,
+		Table:      AnyTable,
+	}
+
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-ofctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"--timeout=1",
+			"dump-flows",
+			"br0",
+			"ip,out_port=2,cookie=0x0000000000000010/0x00000000000000f0",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(`NXST_FLOW reply (xid=0x4):
+ cookie=0x10, duration=9215.748s, table=0, n_packets=6, n_bytes=480, idle_age=9206, priority=820,ip actions=output:2
+`), nil
+	})
+
+	flows, err := c.OpenFlow.DumpFlowsWithFlowArgs("br0", flow)
+	if err != nil {
+		t.Fatalf("unexpected error for Client.OpenFlow.DumpFlowsWithFlowArgs: %v", err)
+	}
+
+	want := []*Flow{{
+		Priority: 820,
+		Protocol: ProtocolIPv4,
+		Matches:  []Match{},
+		Cookie:   0x10,
+		Actions:  []Action{Output(2)},
+	}}
+
+	if want, got := want, flows; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected flows:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowDumpFlowsWithFlowArgsInvalidFlow(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not be executed")
+		return nil, nil
+	})
+
+	_, err := c.OpenFlow.DumpFlowsWithFlowArgs("br0", &MatchFlow{Table: AnyTable})
+
+	want := &MatchFlowError{
+		Err: errEmptyMatchFlow,
+	}
+	if got := err; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected error:\n- want: %#v\n-  got: %#v",
+			want, got)
+	}
+}
+
+func TestClientOpenFlowPacketOut(t *testing.T) {
+	packet := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0x08, 0x06}
+
+	tests := []struct {
+		desc    string
+		inPort  int
+		packet  []byte
+		actions []Action
+		args    []string
+		err     error
+	}{
+		{
+			desc:    "no packet",
+			actions: []Action{Output(1)},
+			err:     errPacketOutNoPacket,
+		},
+		{
+			desc:   "no actions",
+			packet: packet,
+			err:    errPacketOutNoActions,
+		},
+		{
+			desc:    "invalid action",
+			packet:  packet,
+			actions: []Action{Output(-1)},
+			err:     errOutputNegativePort,
+		},
+		{
+			desc:    "controller",
+			packet:  packet,
+			actions: []Action{Output(1)},
+			args:    []string{"packet-out", "--protocols=OpenFlow13", "br0", "CONTROLLER", "output:1", "ffffffffffffdeadbeefdead0806"},
+		},
+		{
+			desc:    "LOCAL",
+			inPort:  PortLOCAL,
+			packet:  packet,
+			actions: []Action{ModVLANVID(10), Output(2)},
+			args:    []string{"packet-out", "--protocols=OpenFlow13", "br0", "LOCAL", "mod_vlan_vid:10,output:2", "ffffffffffffdeadbeefdead0806"},
+		},
+		{
+			desc:    "port",
+			inPort:  3,
+			packet:  packet,
+			actions: []Action{Resubmit(0, 10)},
+			args:    []string{"packet-out", "--protocols=OpenFlow13", "br0", "3", "resubmit(,10)", "ffffffffffffdeadbeefdead0806"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var args []string
+			c := testClient([]OptionFunc{Protocols([]string{ProtocolOpenFlow13})}, func(cmd string, a ...string) ([]byte, error) {
+				if want, got := "ovs-ofctl", cmd; want != got {
+					t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				args = a
+				return nil, nil
+			})
+
+			err := c.OpenFlow.PacketOut("br0", tt.inPort, tt.packet, tt.actions...)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+
+			if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+				t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPMDStats is returned when PMD statistics output from
+	// 'ovs-appctl dpif-netdev/pmd-stats-show' is not in the expected format.
+	ErrInvalidPMDStats = errors.New("invalid pmd statistics")
+
+	// ErrInvalidPMDRxQueues is returned when PMD receive queue output from
+	// 'ovs-appctl dpif-netdev/pmd-rxq-show' is not in the expected format.
+	ErrInvalidPMDRxQueues = errors.New("invalid pmd receive queues")
+)
+
+// A PMDThread identifies a poll mode driver thread of a userspace datapath,
+// such as one using DPDK.
+type PMDThread struct {
+	// NUMAID and CoreID identify the NUMA node and CPU core on which the
+	// thread runs.
+	NUMAID int
+	CoreID int
+
+	// MainThread reports whether the statistics are those of the main
+	// thread, rather than a PMD thread.  NUMAID and CoreID are not set for
+	// the main thread.
+	MainThread bool
+}
+
+// parsePMDThread parses a PMDThread from a line of the form
+// "pmd thread numa_id 0 core_id 1:" or "main thread:".
+func parsePMDThread(s string) (PMDThread, bool) {
+	s = strings.TrimSpace(s)
+	if s == "main thread:" {
+		return PMDThread{MainThread: true}, true
+	}
+
+	var t PMDThread
+	if n, err := fmt.Sscanf(s, "pmd thread numa_id %d core_id %d:", &t.NUMAID, &t.CoreID); err != nil || n != 2 {
+		return PMDThread{}, false
+	}
+
+	return t, true
+}
+
+// PMDStats contains packet and cycle counters for a single PMD thread.
+type PMDStats struct {
+	PMDThread
+
+	PacketsReceived      uint64
+	PacketRecirculations uint64
+
+	// Counters of packets which were processed by each level of the
+	// datapath's flow cache: the exact match cache, the signature match
+	// cache, and the megaflow cache.  Packets which miss all levels are
+	// upcalled to ovs-vswitchd.
+	EMCHits      uint64
+	SMCHits      uint64
+	MegaflowHits uint64
+
+	MissWithSuccessUpcall uint64
+	MissWithFailedUpcall  uint64
+
+	// IdleCycles and ProcessingCycles are the CPU cycles spent polling
+	// without receiving packets, and processing packets.
+	IdleCycles       uint64
+	ProcessingCycles uint64
+}
+
+// UnmarshalText unmarshals a PMDStats from the textual form of a single
+// thread's statistics as output by 'ovs-appctl dpif-netdev/pmd-stats-show':
+//
+//	pmd thread numa_id 0 core_id 1:
+//	  packets received: 1000
+//	  packet recirculations: 0
+//	  emc hits: 900
+//	  smc hits: 0
+//	  megaflow hits: 90
+//	  miss with success upcall: 10
+//	  miss with failed upcall: 0
+//	  idle cycles: 1000 (50.00%)
+//	  processing cycles: 1000 (50.00%)
+func (p *PMDStats) UnmarshalText(b []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	t, ok := parsePMDThread(lines[0])
+	if !ok {
+		return ErrInvalidPMDStats
+	}
+
+	stats := PMDStats{
+		PMDThread: t,
+	}
+
+	counters := map[string]*uint64{
+		"packets received":         &stats.PacketsReceived,
+		"packet recirculations":    &stats.PacketRecirculations,
+		"emc hits":                 &stats.EMCHits,
+		"smc hits":                 &stats.SMCHits,
+		"megaflow hits":            &stats.MegaflowHits,
+		"miss with success upcall": &stats.MissWithSuccessUpcall,
+		"miss with failed upcall":  &stats.MissWithFailedUpcall,
+		"idle cycles":              &stats.IdleCycles,
+		"processing cycles":        &stats.ProcessingCycles,
+	}
+
+	for _, line := range lines[1:] {
+		// Averages and other counters which can be derived from those
+		// above are ignored.
+		kv := strings.SplitN(strings.TrimSpace(line), ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		c, ok := counters[kv[0]]
+		if !ok {
+			continue
+		}
+
+		// Cycle counters are followed by a percentage.
+		ss := strings.Fields(kv[1])
+		if len(ss) == 0 {
+			return ErrInvalidPMDStats
+		}
+
+		n, err := strconv.ParseUint(ss[0], 10, 64)
+		if err != nil {
+			return err
+		}
+		*c = n
+	}
+
+	*p = stats
+	return nil
+}
+
+// PMDRxQueues contains the receive queues assigned to a single PMD thread.
+type PMDRxQueues struct {
+	PMDThread
+
+	// Isolated reports whether the thread only polls queues which were
+	// explicitly assigned to it.
+	Isolated bool
+
+	Queues []PMDRxQueue
+}
+
+// A PMDRxQueue is a receive queue of a port which is polled by a PMD thread.
+type PMDRxQueue struct {
+	Port    string
+	QueueID int
+
+	// Disabled reports whether the queue is disabled, such as a queue of a
+	// vhost-user port which is not in use by its guest.
+	Disabled bool
+
+	// Usage is the percentage of the thread's processing cycles recently
+	// spent on the queue, or -1 if it is not available.
+	Usage int
+}
+
+// UnmarshalText unmarshals a PMDRxQueues from the textual form of a single
+// thread's receive queues as output by 'ovs-appctl dpif-netdev/pmd-rxq-show':
+//
+//	pmd thread numa_id 0 core_id 1:
+//	  isolated : false
+//	  port: dpdk0             queue-id:  0 (enabled)   pmd usage: 10 %
+func (p *PMDRxQueues) UnmarshalText(b []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	t, ok := parsePMDThread(lines[0])
+	if !ok {
+		return ErrInvalidPMDRxQueues
+	}
+
+	rxqs := PMDRxQueues{
+		PMDThread: t,
+	}
+
+	for _, line := range lines[1:] {
+		ss := strings.Fields(line)
+		if len(ss) == 0 {
+			continue
+		}
+
+		switch ss[0] {
+		case "isolated":
+			rxqs.Isolated = ss[len(ss)-1] == "true"
+		case "port:":
+			q, err := parsePMDRxQueue(ss)
+			if err != nil {
+				return err
+			}
+
+			rxqs.Queues = append(rxqs.Queues, q)
+		default:
+			// Ignore additional information reported by newer versions
+			// of OVS, such as the thread's overall usage.
+		}
+	}
+
+	*p = rxqs
+	return nil
+}
+
+// parsePMDRxQueue parses a PMDRxQueue from the fields of a line of the form
+// "port: dpdk0 queue-id: 0 (enabled) pmd usage: 10 %".
+func parsePMDRxQueue(ss []string) (PMDRxQueue, error) {
+	if len(ss) < 4 || ss[2] != "queue-id:" {
+		return PMDRxQueue{}, ErrInvalidPMDRxQueues
+	}
+
+	id, err := strconv.Atoi(ss[3])
+	if err != nil {
+		return PMDRxQueue{}, err
+	}
+
+	q := PMDRxQueue{
+		Port:    ss[1],
+		QueueID: id,
+		Usage:   -1,
+	}
+
+	ss = ss[4:]
+	if len(ss) > 0 && ss[0] == "(disabled)" {
+		q.Disabled = true
+	}
+
+	for i := 0; i+2 < len(ss); i++ {
+		if ss[i] != "usage:" {
+			continue
+		}
+
+		// Usage may also be reported as "NOT AVAIL".
+		if ss[i+2] == "%" {
+			u, err := strconv.Atoi(ss[i+1])
+			if err != nil {
+				return PMDRxQueue{}, err
+			}
+			q.Usage = u
+		}
+		break
+	}
+
+	return q, nil
+}
+
+// splitPMDThreads splits the output of a dpif-netdev command into blocks
+// which each describe a single thread, and invokes fn on each block.
+func splitPMDThreads(out []byte, errInvalid error, fn func(b []byte) error) error {
+	var buf []byte
+
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+
+		b := buf
+		buf = nil
+		return fn(b)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if _, ok := parsePMDThread(line); ok {
+			if err := flush(); err != nil {
+				return err
+			}
+		} else if len(buf) == 0 {
+			return errInvalid
+		}
+
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	return flush()
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPMDStatsUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		p    *PMDStats
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "invalid header",
+			s:    "pmd thread core_id 1:\n  packets received: 1\n",
+		},
+		{
+			desc: "invalid counter",
+			s:    "main thread:\n  packets received: foo\n",
+		},
+		{
+			desc: "missing cycle count",
+			s:    "main thread:\n  idle cycles: (50.00%)\n",
+		},
+		{
+			desc: "PMD thread",
+			s: `pmd thread numa_id 1 core_id 3:
+  packets received: 1000
+  packet recirculations: 5
+  avg. datapath passes per packet: 1.00
+  emc hits: 900
+  smc hits: 2
+  megaflow hits: 88
+  avg. subtable lookups per megaflow hit: 1.00
+  miss with success upcall: 9
+  miss with failed upcall: 1
+  avg. packets per output batch: 1.00
+  idle cycles: 3000 (75.00%)
+  processing cycles: 1000 (25.00%)
+  avg cycles per packet: 4.00 (4000/1000)
+  avg processing cycles per packet: 1.00 (1000/1000)
+`,
+			p: &PMDStats{
+				PMDThread: PMDThread{
+					NUMAID: 1,
+					CoreID: 3,
+				},
+				PacketsReceived:       1000,
+				PacketRecirculations:  5,
+				EMCHits:               900,
+				SMCHits:               2,
+				MegaflowHits:          88,
+				MissWithSuccessUpcall: 9,
+				MissWithFailedUpcall:  1,
+				IdleCycles:            3000,
+				ProcessingCycles:      1000,
+			},
+			ok: true,
+		},
+		{
+			desc: "main thread",
+			s:    "main thread:\n  packets received: 7\n",
+			p: &PMDStats{
+				PMDThread: PMDThread{
+					MainThread: true,
+				},
+				PacketsReceived: 7,
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := new(PMDStats)
+			err := p.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected PMDStats:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestPMDRxQueuesUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		p    *PMDRxQueues
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "invalid header",
+			s:    "thread 1:\n  isolated : false\n",
+		},
+		{
+			desc: "invalid queue",
+			s:    "pmd thread numa_id 0 core_id 1:\n  port: dpdk0\n",
+		},
+		{
+			desc: "invalid queue ID",
+			s:    "pmd thread numa_id 0 core_id 1:\n  port: dpdk0  queue-id: foo\n",
+		},
+		{
+			desc: "invalid usage",
+			s:    "pmd thread numa_id 0 core_id 1:\n  port: dpdk0  queue-id: 0  pmd usage: foo %\n",
+		},
+		{
+			desc: "OK",
+			s: `pmd thread numa_id 0 core_id 1:
+  isolated : true
+  port: dpdk0             queue-id:  0 (enabled)   pmd usage: 10 %
+  port: vhost0            queue-id:  1 (disabled)  pmd usage:  0 %
+  port: dpdk1             queue-id:  2 (enabled)   pmd usage: NOT AVAIL
+  overhead:  2 %
+`,
+			p: &PMDRxQueues{
+				PMDThread: PMDThread{
+					CoreID: 1,
+				},
+				Isolated: true,
+				Queues: []PMDRxQueue{
+					{
+						Port:  "dpdk0",
+						Usage: 10,
+					},
+					{
+						Port:     "vhost0",
+						QueueID:  1,
+						Disabled: true,
+					},
+					{
+						Port:    "dpdk1",
+						QueueID: 2,
+						Usage:   -1,
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			desc: "no state or usage",
+			s:    "pmd thread numa_id 0 core_id 2:\n\tisolated : false\n\tport: dpdk0\tqueue-id: 3\n",
+			p: &PMDRxQueues{
+				PMDThread: PMDThread{
+					CoreID: 2,
+				},
+				Queues: []PMDRxQueue{{
+					Port:    "dpdk0",
+					QueueID: 3,
+					Usage:   -1,
+				}},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := new(PMDRxQueues)
+			err := p.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.p, p; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected PMDRxQueues:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -30,6 +31,9 @@ var (
 	datapathActionsRegexp = regexp.MustCompile(`Datapath actions: (.*)`)
 	initialFlowRegexp     = regexp.MustCompile(`Flow: (.*)`)
 	finalFlowRegexp       = regexp.MustCompile(`Final flow: (.*)`)
+	megaflowRegexp        = regexp.MustCompile(`^Megaflow: (.*)`)
+	bridgeRegexp          = regexp.MustCompile(`^\s*bridge\("(.*)"\)$`)
+	ruleRegexp            = regexp.MustCompile(`^\s*(\d+)\. (.*)$`)
 
 	pushVLANPattern = `push_vlan(vid=[0-9]+,pcp=[0-9]+)`
 )
@@ -107,18 +111,93 @@ func (df *DataPathFlows) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// ProtoTraceRule is an OpenFlow table lookup performed while tracing a packet
+// with ofproto/trace, and the actions executed as a result.
+type ProtoTraceRule struct {
+	// Bridge is the bridge which contains the table.
+	Bridge string
+
+	// Table is the table in which the lookup was performed.
+	Table int
+
+	// Matched reports whether the lookup matched a flow.  If false, the
+	// remaining fields describing the flow are empty.
+	Matched bool
+
+	// Match, Priority, and Cookie describe the flow which matched.
+	Match    string
+	Priority int
+	Cookie   uint64
+
+	// Actions contains the actions executed by the table lookup, such as
+	// "resubmit(,2)" or "output:1", in textual form.
+	Actions []string
+}
+
 // ProtoTrace is a type representing output from ovs-app-ctl ofproto/trace
 type ProtoTrace struct {
 	InputFlow       *DataPathFlows
 	FinalFlow       *DataPathFlows
 	DataPathActions DataPathActions
+
+	// Rules contains the table lookups performed while tracing the packet,
+	// in the order they were performed, including those performed by
+	// resubmit actions.
+	Rules []ProtoTraceRule
+
+	// Megaflow is the datapath flow which would be installed for the
+	// packet, in textual form.
+	Megaflow string
 }
 
 // UnmarshalText unmarshals ProtoTrace text into a ProtoTrace type.
 // Not implemented yet.
 func (pt *ProtoTrace) UnmarshalText(b []byte) error {
+	var (
+		bridge string
+		rule   *ProtoTraceRule
+	)
+
 	lines := strings.Split(string(b), "\n")
 	for _, line := range lines {
+		if matches := bridgeRegexp.FindStringSubmatch(line); len(matches) > 0 {
+			bridge, rule = matches[1], nil
+			continue
+		}
+
+		if matches := ruleRegexp.FindStringSubmatch(line); len(matches) > 0 {
+			r, err := parseProtoTraceRule(matches[1], matches[2])
+			if err != nil {
+				return err
+			}
+
+			r.Bridge = bridge
+			pt.Rules = append(pt.Rules, r)
+			rule = &pt.Rules[len(pt.Rules)-1]
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			// A blank line ends the list of table lookups.
+			rule = nil
+			continue
+		}
+
+		if rule != nil {
+			// Lines following a table lookup are the actions it executed,
+			// or notes about them beginning with "->".
+			if s := strings.TrimSpace(line); !strings.HasPrefix(s, "->") {
+				rule.Actions = append(rule.Actions, s)
+			}
+
+			continue
+		}
+
+		if matches := megaflowRegexp.FindStringSubmatch(line); len(matches) > 0 {
+			pt.Megaflow = matches[1]
+			continue
+		}
+
 		if matches, matched := checkForDataPathActions(line); matched {
 			// first index is always the left most match, following
 			// are the actual matches
@@ -155,6 +234,55 @@ func (pt *ProtoTrace) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// parseProtoTraceRule parses a table lookup from ofproto/trace output, such
+// as "ip,in_port=3, priority 32768, cookie 0x1" or "No match.", performed in
+// the specified table.
+func parseProtoTraceRule(table string, s string) (ProtoTraceRule, error) {
+	id, err := strconv.Atoi(table)
+	if err != nil {
+		return ProtoTraceRule{}, err
+	}
+
+	r := ProtoTraceRule{
+		Table: id,
+	}
+
+	if strings.HasPrefix(s, "No match") {
+		return r, nil
+	}
+
+	r.Matched = true
+
+	// Match fields are separated by commas, while the match and the flow's
+	// attributes are separated by a comma and a space.
+	for _, f := range strings.Split(s, ", ") {
+		kv := strings.SplitN(f, " ", 2)
+		if len(kv) != 2 {
+			r.Match = f
+			continue
+		}
+
+		switch kv[0] {
+		case priority:
+			p, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return ProtoTraceRule{}, err
+			}
+			r.Priority = p
+		case cookie:
+			c, err := strconv.ParseUint(kv[1], 0, 64)
+			if err != nil {
+				return ProtoTraceRule{}, err
+			}
+			r.Cookie = c
+		default:
+			r.Match = f
+		}
+	}
+
+	return r, nil
+}
+
 func checkForDataPathActions(s string) ([]string, bool) {
 	matches := datapathActionsRegexp.FindStringSubmatch(s)
 	if len(matches) == 0 {
@@ -123,3 +123,79 @@ Datapath actions: drop`,
 		})
 	}
 }
+
+func TestProtoTraceUnmarshalTextRules(t *testing.T) {
+	output := `Flow: tcp,in_port=3,vlan_tci=0x0000,dl_src=00:00:00:00:00:00,dl_dst=00:00:00:00:00:00,nw_src=192.0.2.2,nw_dst=0.0.0.0,nw_tos=0,nw_ecn=0,nw_ttl=0,tp_src=0,tp_dst=22,tcp_flags=0
+
+bridge("br0")
+-------------
+ 0. ip,in_port=3,nw_src=192.0.2.0/24, priority 32768, cookie 0x10
+    resubmit(,2)
+ 2. tcp,tp_dst=22, priority 32768
+    output:1
+     -> output to kernel tunnel
+ 3. No match.
+    drop
+
+Final flow: unchanged
+Megaflow: recirc_id=0,tcp,in_port=3,nw_src=192.0.2.0/24,nw_frag=no,tp_dst=22
+Datapath actions: 1`
+
+	want := []ProtoTraceRule{
+		{
+			Bridge:   "br0",
+			Table:    0,
+			Matched:  true,
+			Match:    "ip,in_port=3,nw_src=192.0.2.0/24",
+			Priority: 32768,
+			Cookie:   0x10,
+			Actions:  []string{"resubmit(,2)"},
+		},
+		{
+			Bridge:   "br0",
+			Table:    2,
+			Matched:  true,
+			Match:    "tcp,tp_dst=22",
+			Priority: 32768,
+			Actions:  []string{"output:1"},
+		},
+		{
+			Bridge:  "br0",
+			Table:   3,
+			Actions: []string{"drop"},
+		},
+	}
+
+	pt := &ProtoTrace{}
+	if err := pt.UnmarshalText([]byte(output)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := pt.Rules; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected rules:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	wantMegaflow := "recirc_id=0,tcp,in_port=3,nw_src=192.0.2.0/24,nw_frag=no,tp_dst=22"
+	if got := pt.Megaflow; wantMegaflow != got {
+		t.Fatalf("unexpected megaflow:\n- want: %v\n-  got: %v",
+			wantMegaflow, got)
+	}
+
+	if want, got := NewDataPathActions("1"), pt.DataPathActions; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected datapath actions:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestProtoTraceUnmarshalTextInvalidCookie(t *testing.T) {
+	output := `bridge("br0")
+-------------
+ 0. ip, priority 32768, cookie 0xzz
+    drop`
+
+	pt := &ProtoTrace{}
+	if err := pt.UnmarshalText([]byte(output)); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
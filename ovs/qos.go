@@ -0,0 +1,223 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+var (
+	// errInvalidQoSType is returned when a QoS does not specify a valid type.
+	errInvalidQoSType = errors.New("invalid or missing qos type")
+
+	// errNoQoSQueues is returned when a QoS does not specify any queues.
+	errNoQoSQueues = errors.New("no queues defined for qos")
+)
+
+// A QoSType is a type of QoS configuration, which determines the queueing
+// discipline used to shape traffic transmitted by a port.
+type QoSType string
+
+// QoSType constants which are available for use with SetPortQoS.
+const (
+	// QoSTypeLinuxHTB uses the Linux hierarchical token bucket
+	// classifier.
+	QoSTypeLinuxHTB QoSType = "linux-htb"
+
+	// QoSTypeLinuxHFSC uses the Linux hierarchical fair service curve
+	// classifier.
+	QoSTypeLinuxHFSC QoSType = "linux-hfsc"
+)
+
+// A QoS is a QoS configuration for a port, as stored in the QoS table of the
+// Open vSwitch database.
+type QoS struct {
+	Type QoSType
+
+	// MaxRate is the maximum rate shared by all queues, in bits per second.
+	// If zero, the link speed of the port is used.
+	MaxRate int64
+
+	// Queues contains the queues of the configuration, keyed by queue ID.
+	// Flows select a queue by its ID using the SetQueue or Enqueue actions.
+	Queues map[uint32]Queue
+}
+
+// A Queue is a queue of a QoS configuration, as stored in the Queue table of
+// the Open vSwitch database.  Zero values are omitted, so the defaults of
+// the QoS type are used.
+type Queue struct {
+	// MinRate and MaxRate are the minimum guaranteed and maximum allowed
+	// rates of the queue, in bits per second.
+	MinRate int64
+	MaxRate int64
+
+	// Burst is the maximum burst size of the queue, in bits.  Burst is only
+	// supported by QoSTypeLinuxHTB.
+	Burst int64
+
+	// Priority is the priority of the queue.  Queues with lower values
+	// are served first once their minimum rates are met.  Priority is only
+	// supported by QoSTypeLinuxHTB.
+	Priority int
+}
+
+// args creates the ovs-vsctl arguments which configure a queue.
+func (q Queue) args() []string {
+	var s []string
+
+	if q.MinRate > 0 {
+		s = append(s, fmt.Sprintf("other-config:min-rate=%d", q.MinRate))
+	}
+	if q.MaxRate > 0 {
+		s = append(s, fmt.Sprintf("other-config:max-rate=%d", q.MaxRate))
+	}
+	if q.Burst > 0 {
+		s = append(s, fmt.Sprintf("other-config:burst=%d", q.Burst))
+	}
+	if q.Priority > 0 {
+		s = append(s, fmt.Sprintf("other-config:priority=%d", q.Priority))
+	}
+
+	return s
+}
+
+// SetPortQoS creates a QoS configuration and its queues, and applies it to
+// the specified port, in a single transaction.  Any QoS configuration which
+// was previously applied to the port is detached, but not destroyed; use
+// ClearPortQoS to remove it first.
+func (v *VSwitchService) SetPortQoS(port string, qos QoS) error {
+	switch qos.Type {
+	case QoSTypeLinuxHTB, QoSTypeLinuxHFSC:
+	default:
+		return errInvalidQoSType
+	}
+
+	if len(qos.Queues) == 0 {
+		return errNoQoSQueues
+	}
+
+	// Sort queues so the command is deterministic.
+	ids := make([]int, 0, len(qos.Queues))
+	for id := range qos.Queues {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+
+	args := []string{
+		"set", "port", port, "qos=@qos",
+		"--", "--id=@qos", "create", "qos", fmt.Sprintf("type=%s", qos.Type),
+	}
+	if qos.MaxRate > 0 {
+		args = append(args, fmt.Sprintf("other-config:max-rate=%d", qos.MaxRate))
+	}
+
+	for _, id := range ids {
+		args = append(args, fmt.Sprintf("queues:%d=@q%d", id, id))
+	}
+
+	for _, id := range ids {
+		args = append(args, "--", fmt.Sprintf("--id=@q%d", id), "create", "queue")
+		args = append(args, qos.Queues[uint32(id)].args()...)
+	}
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// ClearPortQoS removes the QoS configuration applied to the specified port,
+// and destroys the configuration and its queues, in a single transaction.
+// If no QoS configuration is applied to the port, ClearPortQoS has no effect.
+//
+// The QoS configuration must not be applied to any other ports.
+func (v *VSwitchService) ClearPortQoS(port string) error {
+	qos, err := v.portQoS(port)
+	if err != nil || qos == nil {
+		return err
+	}
+
+	args := []string{
+		"clear", "port", port, "qos",
+		"--", "destroy", "qos", qos.UUID(),
+	}
+
+	// Sort queues so the command is deterministic.
+	var queues []string
+	for _, q := range qos.Map("queues") {
+		queues = append(queues, q)
+	}
+	sort.Strings(queues)
+
+	for _, q := range queues {
+		args = append(args, "--", "destroy", "queue", q)
+	}
+
+	_, err = v.exec(args...)
+	return err
+}
+
+// QueueIDs returns the IDs of the queues of the QoS configuration applied to
+// the specified port, which may be used with the SetQueue and Enqueue
+// actions.  If no QoS configuration is applied to the port, QueueIDs returns
+// no IDs.
+func (v *VSwitchService) QueueIDs(port string) ([]uint32, error) {
+	qos, err := v.portQoS(port)
+	if err != nil || qos == nil {
+		return nil, err
+	}
+
+	var ids []uint32
+	for k := range qos.Map("queues") {
+		id, err := strconv.ParseUint(k, 10, 32)
+		if err != nil {
+			return nil, errInvalidRecords
+		}
+
+		ids = append(ids, uint32(id))
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// portQoS returns the record of the QoS configuration applied to the
+// specified port, or nil if none is applied.
+func (v *VSwitchService) portQoS(port string) (Record, error) {
+	ports, err := v.List("port", port)
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) != 1 {
+		return nil, errInvalidRecords
+	}
+
+	uuid := ports[0].String("qos")
+	if uuid == "" {
+		return nil, nil
+	}
+
+	qoss, err := v.List("qos", uuid)
+	if err != nil {
+		return nil, err
+	}
+	if len(qoss) != 1 {
+		return nil, errInvalidRecords
+	}
+
+	return qoss[0], nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchSetPortQoSInvalid(t *testing.T) {
+	var tests = []struct {
+		desc string
+		qos  QoS
+		err  error
+	}{
+		{
+			desc: "no type",
+			qos: QoS{
+				Queues: map[uint32]Queue{0: {}},
+			},
+			err: errInvalidQoSType,
+		},
+		{
+			desc: "no queues",
+			qos: QoS{
+				Type: QoSTypeLinuxHTB,
+			},
+			err: errNoQoSQueues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				t.Fatal("command should not have been executed")
+				return nil, nil
+			})
+
+			if want, got := tt.err, c.VSwitch.SetPortQoS("eth0", tt.qos); want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchSetPortQoSOK(t *testing.T) {
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-vsctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{
+			"--timeout=1",
+			"set", "port", "eth0", "qos=@qos",
+			"--", "--id=@qos", "create", "qos", "type=linux-htb", "other-config:max-rate=1000000000",
+			"queues:0=@q0", "queues:10=@q10",
+			"--", "--id=@q0", "create", "queue",
+			"--", "--id=@q10", "create", "queue",
+			"other-config:min-rate=1000", "other-config:max-rate=2000",
+			"other-config:burst=3000", "other-config:priority=1",
+		}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	err := c.VSwitch.SetPortQoS("eth0", QoS{
+		Type:    QoSTypeLinuxHTB,
+		MaxRate: 1000000000,
+		Queues: map[uint32]Queue{
+			10: {
+				MinRate:  1000,
+				MaxRate:  2000,
+				Burst:    3000,
+				Priority: 1,
+			},
+			0: {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.SetPortQoS: %v", err)
+	}
+}
+
+// qosExec returns an ExecFunc which reports a port with the specified QoS
+// column, and a QoS record with two queues, and records any other commands
+// in cmds.
+func qosExec(t *testing.T, portQoS string, cmds *[][]string) ExecFunc {
+	const (
+		qosUUID = "5f3a7a3e-0000-4000-8000-000000000001"
+	)
+
+	return func(cmd string, args ...string) ([]byte, error) {
+		if len(args) < 5 || args[2] != "list" {
+			*cmds = append(*cmds, args)
+			return nil, nil
+		}
+
+		switch args[3] {
+		case "port":
+			return []byte(`{"data":[[["uuid","5f3a7a3e-0000-4000-8000-00000000000a"],"eth0",` + portQoS + `]],"headings":["_uuid","name","qos"]}`), nil
+		case "qos":
+			if want, got := qosUUID, args[4]; want != got {
+				t.Fatalf("unexpected QoS UUID:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+
+			return []byte(`{"data":[[["uuid","` + qosUUID + `"],["map",[[0,["uuid","5f3a7a3e-0000-4000-8000-000000000003"]],[1,["uuid","5f3a7a3e-0000-4000-8000-000000000002"]]]]]],"headings":["_uuid","queues"]}`), nil
+		default:
+			t.Fatalf("unexpected table: %q", args[3])
+			return nil, nil
+		}
+	}
+}
+
+func TestClientVSwitchClearPortQoSOK(t *testing.T) {
+	var cmds [][]string
+	c := testClient(nil, qosExec(t, `["uuid","5f3a7a3e-0000-4000-8000-000000000001"]`, &cmds))
+
+	if err := c.VSwitch.ClearPortQoS("eth0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.ClearPortQoS: %v", err)
+	}
+
+	want := [][]string{{
+		"clear", "port", "eth0", "qos",
+		"--", "destroy", "qos", "5f3a7a3e-0000-4000-8000-000000000001",
+		"--", "destroy", "queue", "5f3a7a3e-0000-4000-8000-000000000002",
+		"--", "destroy", "queue", "5f3a7a3e-0000-4000-8000-000000000003",
+	}}
+	if got := cmds; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected commands:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchClearPortQoSNone(t *testing.T) {
+	var cmds [][]string
+	c := testClient(nil, qosExec(t, `["set",[]]`, &cmds))
+
+	if err := c.VSwitch.ClearPortQoS("eth0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.ClearPortQoS: %v", err)
+	}
+
+	if len(cmds) != 0 {
+		t.Fatalf("unexpected commands: %v", cmds)
+	}
+}
+
+func TestClientVSwitchQueueIDs(t *testing.T) {
+	var tests = []struct {
+		desc string
+		qos  string
+		ids  []uint32
+	}{
+		{
+			desc: "no QoS",
+			qos:  `["set",[]]`,
+		},
+		{
+			desc: "QoS",
+			qos:  `["uuid","5f3a7a3e-0000-4000-8000-000000000001"]`,
+			ids:  []uint32{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var cmds [][]string
+			c := testClient(nil, qosExec(t, tt.qos, &cmds))
+
+			ids, err := c.VSwitch.QueueIDs("eth0")
+			if err != nil {
+				t.Fatalf("unexpected error for Client.VSwitch.QueueIDs: %v", err)
+			}
+
+			if want, got := tt.ids, ids; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected queue IDs:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
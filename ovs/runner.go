@@ -0,0 +1,125 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// errStreamUnsupported is returned when a long-running command is started
+// using a CommandRunner which does not implement StreamRunner.
+var errStreamUnsupported = errors.New("command runner does not support streaming commands")
+
+// A CommandRunner runs the Open vSwitch commands executed by a Client, such
+// as 'ovs-vsctl' and 'ovs-ofctl'.  CommandRunners may be used to mock command
+// output in tests, instrument commands, or run commands in another
+// environment, such as a container or chroot.
+type CommandRunner interface {
+	// Run runs cmd with arguments args, and returns its combined stdout
+	// and stderr.  If stdin is not nil, its contents are written to the
+	// command's stdin.  The command should be stopped if ctx is canceled.
+	Run(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error)
+}
+
+// A RunnerFunc is an adapter which allows the use of a function as a
+// CommandRunner.
+type RunnerFunc func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error)
+
+// Run implements CommandRunner.
+func (fn RunnerFunc) Run(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+	return fn(ctx, stdin, cmd, args...)
+}
+
+var _ CommandRunner = RunnerFunc(nil)
+
+// A StreamRunner is a CommandRunner which can also run long-running
+// commands, such as 'ovs-ofctl monitor', whose output is read as it is
+// produced.  Stream behaves as described for StreamFunc.
+type StreamRunner interface {
+	CommandRunner
+	Stream(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error)
+}
+
+// LocalRunner returns a CommandRunner which runs commands on the local
+// machine.  It is used by Clients created with New unless the Runner, Exec,
+// or Pipe options are used, and may be wrapped to instrument commands.  The
+// returned CommandRunner also implements StreamRunner.
+func LocalRunner() CommandRunner {
+	return localRunner{}
+}
+
+// A localRunner is the CommandRunner returned by LocalRunner.
+type localRunner struct{}
+
+var _ StreamRunner = localRunner{}
+
+// Run implements CommandRunner.
+func (localRunner) Run(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+	if stdin == nil {
+		return shellExec(ctx, cmd, args...)
+	}
+
+	return shellPipe(ctx, stdin, cmd, args...)
+}
+
+// Stream implements StreamRunner.
+func (localRunner) Stream(ctx context.Context, cmd string, args ...string) (io.ReadCloser, error) {
+	return shellStream(ctx, cmd, args...)
+}
+
+// PrefixRunner returns a CommandRunner which runs each command using r,
+// prefixed by the command and arguments in prefix.  For example, a prefix of
+// "docker", "exec", "-i", "ovs" runs commands in the "ovs" container, and a
+// prefix of "chroot", "/host" runs commands in a chroot.
+//
+// If prefix is empty, r is returned.
+func PrefixRunner(r CommandRunner, prefix ...string) CommandRunner {
+	if len(prefix) == 0 {
+		return r
+	}
+
+	return RunnerFunc(func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		pargs := make([]string, 0, len(prefix)+len(args))
+		pargs = append(pargs, prefix[1:]...)
+		pargs = append(pargs, cmd)
+		pargs = append(pargs, args...)
+
+		return r.Run(ctx, stdin, prefix[0], pargs...)
+	})
+}
+
+// Runner returns an OptionFunc which sets the CommandRunner used by a Client
+// to run commands, replacing any ExecFunc, PipeFunc, or StreamFunc.  If r
+// does not implement StreamRunner, long-running commands such as those used
+// by OpenFlowService.MonitorFlows return an error.
+func Runner(r CommandRunner) OptionFunc {
+	return func(c *Client) {
+		c.execFunc = func(ctx context.Context, cmd string, args ...string) ([]byte, error) {
+			return r.Run(ctx, nil, cmd, args...)
+		}
+		c.pipeFunc = r.Run
+
+		if sr, ok := r.(StreamRunner); ok {
+			c.streamFunc = sr.Stream
+			return
+		}
+
+		c.streamFunc = func(_ context.Context, _ string, _ ...string) (io.ReadCloser, error) {
+			return nil, errStreamUnsupported
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A runnerCall is the arguments of a call to CommandRunner.Run.
+type runnerCall struct {
+	stdin string
+	cmd   string
+	args  []string
+}
+
+// recordRunner returns a CommandRunner which records each call on calls.
+func recordRunner(calls *[]runnerCall) CommandRunner {
+	return RunnerFunc(func(_ context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		call := runnerCall{
+			cmd:  cmd,
+			args: args,
+		}
+
+		if stdin != nil {
+			b, err := ioutil.ReadAll(stdin)
+			if err != nil {
+				return nil, err
+			}
+			call.stdin = string(b)
+		}
+
+		*calls = append(*calls, call)
+		return nil, nil
+	})
+}
+
+func TestClientRunner(t *testing.T) {
+	var calls []runnerCall
+	c := New(Runner(recordRunner(&calls)))
+
+	if err := c.VSwitch.AddBridge("br0"); err != nil {
+		t.Fatalf("failed to add bridge: %v", err)
+	}
+
+	if err := c.OpenFlow.AddFlowBundle("br0", func(tx *FlowTransaction) error {
+		tx.Add(&Flow{Actions: []Action{Drop()}})
+		return tx.Commit()
+	}); err != nil {
+		t.Fatalf("failed to add flow bundle: %v", err)
+	}
+
+	want := []runnerCall{
+		{
+			cmd:  "ovs-vsctl",
+			args: []string{"--may-exist", "add-br", "br0"},
+		},
+		{
+			stdin: "add priority=0,table=0,idle_timeout=0,actions=drop\n",
+			cmd:   "ovs-ofctl",
+			args:  []string{"--bundle", "add-flow", "br0", "-"},
+		},
+	}
+
+	if got := calls; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected calls:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestPrefixRunner(t *testing.T) {
+	var calls []runnerCall
+	r := PrefixRunner(recordRunner(&calls), "docker", "exec", "-i", "ovs")
+
+	c := New(Runner(r), Sudo())
+	if err := c.VSwitch.DeleteBridge("br0"); err != nil {
+		t.Fatalf("failed to delete bridge: %v", err)
+	}
+
+	want := []runnerCall{{
+		cmd:  "docker",
+		args: []string{"exec", "-i", "ovs", "sudo", "ovs-vsctl", "--if-exists", "del-br", "br0"},
+	}}
+
+	if got := calls; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected calls:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestPrefixRunnerEmpty(t *testing.T) {
+	r := LocalRunner()
+	if PrefixRunner(r) != r {
+		t.Fatal("expected empty prefix to return the original CommandRunner")
+	}
+}
+
+func TestLocalRunner(t *testing.T) {
+	r := LocalRunner()
+	ctx := context.Background()
+
+	out, err := r.Run(ctx, nil, "echo", "foo")
+	if err != nil {
+		t.Fatalf("failed to run echo: %v", err)
+	}
+
+	if want, got := "foo", strings.TrimSpace(string(out)); want != got {
+		t.Fatalf("unexpected output:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	out, err = r.Run(ctx, bytes.NewBufferString("bar"), "cat", "-")
+	if err != nil {
+		t.Fatalf("failed to run cat: %v", err)
+	}
+
+	if want, got := "bar", string(out); want != got {
+		t.Fatalf("unexpected output:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
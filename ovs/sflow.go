@@ -0,0 +1,87 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNoSFlowTargets is returned when an SFlow does not specify any collector
+// targets.
+var errNoSFlowTargets = errors.New("no targets specified for sflow")
+
+// An SFlow is an sFlow export configuration for a bridge, as stored in the
+// sFlow table of the Open vSwitch database.  Zero values are omitted, so
+// the Open vSwitch defaults are used.
+type SFlow struct {
+	// Targets contains the collectors to which samples are sent, of the
+	// form "ip:port".
+	Targets []string
+
+	// Agent is the name of the interface whose IP address is reported as
+	// the source of samples.  If empty, an address is chosen automatically.
+	Agent string
+
+	// Sampling is the rate at which packets are sampled: on average, one
+	// of every Sampling packets is sent to the collectors.
+	Sampling int
+
+	// Polling is the interval, in seconds, at which interface counters
+	// are sent to the collectors.
+	Polling int
+
+	// Header is the number of bytes of each sampled packet which is sent
+	// to the collectors.
+	Header int
+}
+
+// EnableSFlow configures the specified bridge to export sFlow samples using
+// the configuration in sflow.  Any sFlow configuration which was previously
+// applied to the bridge is replaced.
+func (v *VSwitchService) EnableSFlow(bridge string, sflow SFlow) error {
+	if len(sflow.Targets) == 0 {
+		return errNoSFlowTargets
+	}
+
+	args := []string{
+		"set", "bridge", bridge, "sflow=@sf",
+		"--", "--id=@sf", "create", "sflow",
+		fmt.Sprintf("targets=%s", stringSet(sflow.Targets)),
+	}
+
+	if sflow.Agent != "" {
+		args = append(args, fmt.Sprintf("agent=%q", sflow.Agent))
+	}
+	if sflow.Sampling > 0 {
+		args = append(args, fmt.Sprintf("sampling=%d", sflow.Sampling))
+	}
+	if sflow.Polling > 0 {
+		args = append(args, fmt.Sprintf("polling=%d", sflow.Polling))
+	}
+	if sflow.Header > 0 {
+		args = append(args, fmt.Sprintf("header=%d", sflow.Header))
+	}
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// DisableSFlow removes the sFlow configuration of the specified bridge.  If
+// sFlow is not enabled on the bridge, DisableSFlow has no effect.
+func (v *VSwitchService) DisableSFlow(bridge string) error {
+	_, err := v.exec("clear", "bridge", bridge, "sflow")
+	return err
+}
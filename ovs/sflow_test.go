@@ -0,0 +1,103 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchEnableSFlowNoTargets(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if want, got := errNoSFlowTargets, c.VSwitch.EnableSFlow("br0", SFlow{Sampling: 64}); want != got {
+		t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchEnableSFlowOK(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		sflow SFlow
+		args  []string
+	}{
+		{
+			desc: "defaults",
+			sflow: SFlow{
+				Targets: []string{"192.0.2.1:6343"},
+			},
+			args: []string{
+				"set", "bridge", "br0", "sflow=@sf",
+				"--", "--id=@sf", "create", "sflow",
+				`targets=["192.0.2.1:6343"]`,
+			},
+		},
+		{
+			desc: "all options",
+			sflow: SFlow{
+				Targets:  []string{"192.0.2.1:6343", "192.0.2.2:6343"},
+				Agent:    "eth0",
+				Sampling: 64,
+				Polling:  10,
+				Header:   128,
+			},
+			args: []string{
+				"set", "bridge", "br0", "sflow=@sf",
+				"--", "--id=@sf", "create", "sflow",
+				`targets=["192.0.2.1:6343","192.0.2.2:6343"]`,
+				`agent="eth0"`,
+				"sampling=64",
+				"polling=10",
+				"header=128",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return nil, nil
+			})
+
+			if err := c.VSwitch.EnableSFlow("br0", tt.sflow); err != nil {
+				t.Fatalf("unexpected error for Client.VSwitch.EnableSFlow: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchDisableSFlowOK(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := []string{"clear", "bridge", "br0", "sflow"}, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return nil, nil
+	})
+
+	if err := c.VSwitch.DisableSFlow("br0"); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.DisableSFlow: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// SSHRunner returns a CommandRunner which runs commands on a remote host
+// using the 'ssh' binary, so that a Client can manage Open vSwitch on a
+// hypervisor without an agent.  host is passed to ssh as its destination,
+// such as "root@hv1.example.com", and sshArgs are passed to ssh before the
+// destination, such as "-i", "/path/to/key", "-o", "BatchMode=yes".
+//
+// Each argument is quoted for the remote shell, so flows and other arguments
+// containing shell metacharacters are passed unchanged.  Input is written to
+// the remote command's stdin, and canceling the context kills the local ssh
+// process, which closes the session.
+//
+// Authentication must not require interaction: use keys loaded by ssh-agent
+// or specified by sshArgs, and options such as BatchMode.
+//
+//	c := ovs.New(ovs.Runner(ovs.SSHRunner("root@hv1", "-o", "BatchMode=yes")))
+func SSHRunner(host string, sshArgs ...string) CommandRunner {
+	return sshRunner(LocalRunner(), host, sshArgs...)
+}
+
+// sshRunner implements SSHRunner using r to run the ssh binary.
+func sshRunner(r CommandRunner, host string, sshArgs ...string) CommandRunner {
+	return RunnerFunc(func(ctx context.Context, stdin io.Reader, cmd string, args ...string) ([]byte, error) {
+		// The remote command is interpreted by a shell on the remote host.
+		quoted := make([]string, 0, 1+len(args))
+		quoted = append(quoted, shellQuote(cmd))
+		for _, a := range args {
+			quoted = append(quoted, shellQuote(a))
+		}
+
+		sargs := make([]string, 0, len(sshArgs)+3)
+		sargs = append(sargs, sshArgs...)
+		sargs = append(sargs, "--", host, strings.Join(quoted, " "))
+
+		return r.Run(ctx, stdin, "ssh", sargs...)
+	})
+}
+
+// shellQuote quotes s for use as a single argument in a POSIX shell, unless
+// it contains only characters which do not require quoting.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	safe := true
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("-_./:=,@+%", r):
+		default:
+			safe = false
+		}
+	}
+	if safe {
+		return s
+	}
+
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
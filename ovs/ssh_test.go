@@ -0,0 +1,92 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSSHRunner(t *testing.T) {
+	var calls []runnerCall
+	r := sshRunner(recordRunner(&calls), "root@hv1", "-o", "BatchMode=yes")
+
+	c := New(Runner(r))
+
+	if err := c.VSwitch.AddBridge("br0"); err != nil {
+		t.Fatalf("failed to add bridge: %v", err)
+	}
+
+	if err := c.OpenFlow.AddFlowBundle("br0", func(tx *FlowTransaction) error {
+		tx.Add(&Flow{Actions: []Action{Drop()}})
+		return tx.Commit()
+	}); err != nil {
+		t.Fatalf("failed to add flow bundle: %v", err)
+	}
+
+	want := []runnerCall{
+		{
+			cmd:  "ssh",
+			args: []string{"-o", "BatchMode=yes", "--", "root@hv1", "ovs-vsctl --may-exist add-br br0"},
+		},
+		{
+			stdin: "add priority=0,table=0,idle_timeout=0,actions=drop\n",
+			cmd:   "ssh",
+			args:  []string{"-o", "BatchMode=yes", "--", "root@hv1", "ovs-ofctl --bundle add-flow br0 -"},
+		},
+	}
+
+	if got := calls; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected calls:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func Test_shellQuote(t *testing.T) {
+	var tests = []struct {
+		s    string
+		want string
+	}{
+		{
+			s:    "",
+			want: "''",
+		},
+		{
+			s:    "priority=10,ip,nw_src=192.168.1.0/24,actions=drop",
+			want: "priority=10,ip,nw_src=192.168.1.0/24,actions=drop",
+		},
+		{
+			s:    "actions=load:0x1->NXM_NX_REG0[],resubmit(,1)",
+			want: "'actions=load:0x1->NXM_NX_REG0[],resubmit(,1)'",
+		},
+		{
+			s:    "external_ids:name=it's",
+			want: `'external_ids:name=it'\''s'`,
+		},
+		{
+			s:    "$(reboot)",
+			want: "'$(reboot)'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if want, got := tt.want, shellQuote(tt.s); want != got {
+				t.Fatalf("unexpected quoted string:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSTPStatus is returned when spanning tree status output from
+// 'ovs-appctl stp/show' or 'ovs-appctl rstp/show' is not in the expected
+// format.
+var ErrInvalidSTPStatus = errors.New("invalid stp status")
+
+// EnableSTP enables the Spanning Tree Protocol on the specified bridge.
+func (v *VSwitchService) EnableSTP(bridge string) error {
+	_, err := v.exec("set", "bridge", bridge, "stp_enable=true")
+	return err
+}
+
+// DisableSTP disables the Spanning Tree Protocol on the specified bridge.
+func (v *VSwitchService) DisableSTP(bridge string) error {
+	_, err := v.exec("set", "bridge", bridge, "stp_enable=false")
+	return err
+}
+
+// EnableRSTP enables the Rapid Spanning Tree Protocol on the specified
+// bridge.  STP and RSTP should not be enabled on the same bridge.
+func (v *VSwitchService) EnableRSTP(bridge string) error {
+	_, err := v.exec("set", "bridge", bridge, "rstp_enable=true")
+	return err
+}
+
+// DisableRSTP disables the Rapid Spanning Tree Protocol on the specified
+// bridge.
+func (v *VSwitchService) DisableRSTP(bridge string) error {
+	_, err := v.exec("set", "bridge", bridge, "rstp_enable=false")
+	return err
+}
+
+// STPPortOptions enables configuration of a port which participates in the
+// Spanning Tree Protocol or Rapid Spanning Tree Protocol.  Zero values are
+// omitted, so the Open vSwitch defaults are used.
+type STPPortOptions struct {
+	// Priority is the port's priority, which is used to select a port
+	// when multiple ports lead to the root bridge.  Lower values are
+	// preferred.
+	Priority int
+
+	// PathCost is the cost of the port's link, which is used to determine
+	// the best path to the root bridge.
+	PathCost int
+}
+
+// slice creates a string slice containing any non-zero option values from
+// the struct in the format expected by Open vSwitch, with the specified
+// other_config key prefix.
+func (o STPPortOptions) slice(prefix string) []string {
+	var s []string
+
+	if o.Priority > 0 {
+		s = append(s, fmt.Sprintf("other_config:%s-port-priority=%d", prefix, o.Priority))
+	}
+	if o.PathCost > 0 {
+		s = append(s, fmt.Sprintf("other_config:%s-path-cost=%d", prefix, o.PathCost))
+	}
+
+	return s
+}
+
+// SetPortSTP sets the Spanning Tree Protocol configuration of the specified
+// port using the values from an STPPortOptions struct.
+func (v *VSwitchService) SetPortSTP(port string, options STPPortOptions) error {
+	return v.setPortSTP(port, options.slice("stp"))
+}
+
+// SetPortRSTP sets the Rapid Spanning Tree Protocol configuration of the
+// specified port using the values from an STPPortOptions struct.
+func (v *VSwitchService) SetPortRSTP(port string, options STPPortOptions) error {
+	return v.setPortSTP(port, options.slice("rstp"))
+}
+
+// setPortSTP sets the specified options on a port, if any are set.
+func (v *VSwitchService) setPortSTP(port string, options []string) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	args := []string{"set", "port", port}
+	args = append(args, options...)
+
+	_, err := v.exec(args...)
+	return err
+}
+
+// An STPStatus is the status of the Spanning Tree Protocol or Rapid Spanning
+// Tree Protocol on a bridge, as reported by 'ovs-appctl stp/show' or
+// 'ovs-appctl rstp/show'.
+type STPStatus struct {
+	// Bridge is the name of the bridge.
+	Bridge string
+
+	// RootPriority and RootAddress identify the root bridge of the
+	// spanning tree.
+	RootPriority int
+	RootAddress  string
+
+	// BridgePriority and BridgeAddress identify this bridge.
+	BridgePriority int
+	BridgeAddress  string
+
+	// IsRoot reports whether this bridge is the root bridge.  If not,
+	// RootPort is the port which leads to the root bridge, and RootPathCost
+	// is the cost of the path to the root bridge, if reported.
+	IsRoot       bool
+	RootPort     string
+	RootPathCost int
+
+	// Ports contains the status of each port which participates in the
+	// spanning tree.
+	Ports []STPPortStatus
+}
+
+// An STPPortStatus is the status of a single port which participates in the
+// Spanning Tree Protocol or Rapid Spanning Tree Protocol.
+type STPPortStatus struct {
+	Interface string
+
+	// Role and State are the role and state of the port, such as
+	// "designated" and "forwarding".  Both are lowercase regardless of the
+	// protocol's output.
+	Role  string
+	State string
+
+	Cost int
+
+	// Priority and PortNumber identify the port within the bridge.
+	Priority   int
+	PortNumber int
+}
+
+// UnmarshalText unmarshals an STPStatus from textual form as output by
+// 'ovs-appctl stp/show':
+//
+//	---- br0 ----
+//	Root ID:
+//		stp-priority    32768
+//		stp-system-id   00:00:00:00:00:01
+//		This bridge is the root
+//
+//	Bridge ID:
+//		stp-priority    32768
+//		stp-system-id   00:00:00:00:00:01
+//
+//		Interface  Role       State      Cost     Pri.Nbr
+//		---------- ---------- ---------- -------- -------
+//		eth0       designated forwarding 19       128.1
+//
+// or by 'ovs-appctl rstp/show', which uses "priority" and "address" instead
+// of "stp-priority" and "stp-system-id".
+func (s *STPStatus) UnmarshalText(b []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	name, ok := parseStatusHeader(lines[0])
+	if !ok {
+		return ErrInvalidSTPStatus
+	}
+
+	status := STPStatus{
+		Bridge: name,
+	}
+
+	// Destinations for bridge identifiers, which depend on the section
+	// being parsed.
+	var (
+		priority *int
+		address  *string
+		ports    bool
+	)
+
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		ss := strings.Fields(line)
+		if len(ss) == 0 {
+			continue
+		}
+
+		switch {
+		case line == "Root ID:":
+			priority, address = &status.RootPriority, &status.RootAddress
+			continue
+		case line == "Bridge ID:":
+			priority, address = &status.BridgePriority, &status.BridgeAddress
+			continue
+		case ss[0] == "Interface":
+			ports = true
+			continue
+		case strings.HasPrefix(ss[0], "---"):
+			continue
+		case line == "This bridge is the root":
+			status.IsRoot = true
+			continue
+		}
+
+		if ports {
+			p, err := parseSTPPortStatus(ss)
+			if err != nil {
+				return err
+			}
+
+			status.Ports = append(status.Ports, p)
+			continue
+		}
+
+		if len(ss) < 2 {
+			continue
+		}
+
+		var err error
+		k, v := strings.ToLower(strings.Join(ss[:len(ss)-1], " ")), ss[len(ss)-1]
+		switch k {
+		case "stp-priority", "priority":
+			if priority != nil {
+				*priority, err = strconv.Atoi(v)
+			}
+		case "stp-system-id", "address":
+			if address != nil {
+				*address = v
+			}
+		case "root-port", "root port":
+			status.RootPort = v
+		case "root-path-cost", "root path cost":
+			status.RootPathCost, err = strconv.Atoi(v)
+		default:
+			// Ignore timers and additional information reported by
+			// newer versions of OVS.
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*s = status
+	return nil
+}
+
+// parseSTPPortStatus parses an STPPortStatus from the fields of a line of the
+// form "eth0 designated forwarding 19 128.1".
+func parseSTPPortStatus(ss []string) (STPPortStatus, error) {
+	if len(ss) != 5 {
+		return STPPortStatus{}, ErrInvalidSTPStatus
+	}
+
+	cost, err := strconv.Atoi(ss[3])
+	if err != nil {
+		return STPPortStatus{}, err
+	}
+
+	pn := strings.SplitN(ss[4], ".", 2)
+	if len(pn) != 2 {
+		return STPPortStatus{}, ErrInvalidSTPStatus
+	}
+
+	pri, err := strconv.Atoi(pn[0])
+	if err != nil {
+		return STPPortStatus{}, err
+	}
+
+	num, err := strconv.Atoi(pn[1])
+	if err != nil {
+		return STPPortStatus{}, err
+	}
+
+	return STPPortStatus{
+		Interface:  ss[0],
+		Role:       strings.ToLower(ss[1]),
+		State:      strings.ToLower(ss[2]),
+		Cost:       cost,
+		Priority:   pri,
+		PortNumber: num,
+	}, nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchSTPOK(t *testing.T) {
+	var tests = []struct {
+		desc string
+		fn   func(v *VSwitchService) error
+		args []string
+	}{
+		{
+			desc: "enable STP",
+			fn: func(v *VSwitchService) error {
+				return v.EnableSTP("br0")
+			},
+			args: []string{"set", "bridge", "br0", "stp_enable=true"},
+		},
+		{
+			desc: "disable STP",
+			fn: func(v *VSwitchService) error {
+				return v.DisableSTP("br0")
+			},
+			args: []string{"set", "bridge", "br0", "stp_enable=false"},
+		},
+		{
+			desc: "enable RSTP",
+			fn: func(v *VSwitchService) error {
+				return v.EnableRSTP("br0")
+			},
+			args: []string{"set", "bridge", "br0", "rstp_enable=true"},
+		},
+		{
+			desc: "disable RSTP",
+			fn: func(v *VSwitchService) error {
+				return v.DisableRSTP("br0")
+			},
+			args: []string{"set", "bridge", "br0", "rstp_enable=false"},
+		},
+		{
+			desc: "STP port",
+			fn: func(v *VSwitchService) error {
+				return v.SetPortSTP("eth0", STPPortOptions{
+					Priority: 64,
+					PathCost: 100,
+				})
+			},
+			args: []string{
+				"set", "port", "eth0",
+				"other_config:stp-port-priority=64",
+				"other_config:stp-path-cost=100",
+			},
+		},
+		{
+			desc: "RSTP port",
+			fn: func(v *VSwitchService) error {
+				return v.SetPortRSTP("eth0", STPPortOptions{
+					PathCost: 2000,
+				})
+			},
+			args: []string{
+				"set", "port", "eth0",
+				"other_config:rstp-path-cost=2000",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+				if want, got := "ovs-vsctl", cmd; want != got {
+					t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				if want, got := tt.args, args; !reflect.DeepEqual(want, got) {
+					t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+						want, got)
+				}
+
+				return nil, nil
+			})
+
+			if err := tt.fn(c.VSwitch); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientVSwitchSetPortSTPNoOptions(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		t.Fatal("command should not have been executed")
+		return nil, nil
+	})
+
+	if err := c.VSwitch.SetPortSTP("eth0", STPPortOptions{}); err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.SetPortSTP: %v", err)
+	}
+}
+
+func TestSTPStatusUnmarshalText(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		st   *STPStatus
+		ok   bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "no header",
+			s:    "Root ID:\n\tstp-priority 32768\n",
+		},
+		{
+			desc: "invalid priority",
+			s:    "---- br0 ----\nRoot ID:\n\tstp-priority foo\n",
+		},
+		{
+			desc: "invalid port",
+			s:    "---- br0 ----\n\tInterface  Role  State  Cost  Pri.Nbr\n\teth0 designated forwarding 19\n",
+		},
+		{
+			desc: "invalid port number",
+			s:    "---- br0 ----\n\tInterface  Role  State  Cost  Pri.Nbr\n\teth0 designated forwarding 19 128\n",
+		},
+		{
+			desc: "STP root",
+			s: `---- br0 ----
+Root ID:
+	stp-priority    32768
+	stp-system-id   00:00:00:00:00:01
+	stp-hello-time  2s
+	stp-max-age     20s
+	stp-fwd-delay   15s
+	This bridge is the root
+
+Bridge ID:
+	stp-priority    32768
+	stp-system-id   00:00:00:00:00:01
+	stp-hello-time  2s
+	stp-max-age     20s
+	stp-fwd-delay   15s
+
+	Interface  Role       State      Cost     Pri.Nbr
+	---------- ---------- ---------- -------- -------
+	eth0       designated forwarding 19       128.1
+	eth1       designated learning   19       128.2
+`,
+			st: &STPStatus{
+				Bridge:         "br0",
+				RootPriority:   32768,
+				RootAddress:    "00:00:00:00:00:01",
+				BridgePriority: 32768,
+				BridgeAddress:  "00:00:00:00:00:01",
+				IsRoot:         true,
+				Ports: []STPPortStatus{
+					{
+						Interface:  "eth0",
+						Role:       "designated",
+						State:      "forwarding",
+						Cost:       19,
+						Priority:   128,
+						PortNumber: 1,
+					},
+					{
+						Interface:  "eth1",
+						Role:       "designated",
+						State:      "learning",
+						Cost:       19,
+						Priority:   128,
+						PortNumber: 2,
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			desc: "RSTP non-root",
+			s: `---- br1 ----
+Root ID:
+  priority    4096
+  address     00:00:00:00:00:0a
+  root-port   eth0
+  root-path-cost 2000
+  Hello Time  2s
+  Max Age     20s
+  Forward Delay 15s
+
+Bridge ID:
+  priority    32768
+  address     00:00:00:00:00:02
+  Hello Time  2s
+  Max Age     20s
+  Forward Delay  15s
+
+  Interface  Role       State      Cost     Pri.Nbr
+  ---------- ---------- ---------- -------- -------
+  eth0       Root       Forwarding 2000     128.1
+  eth1       Alternate  Discarding 2000     128.2
+`,
+			st: &STPStatus{
+				Bridge:         "br1",
+				RootPriority:   4096,
+				RootAddress:    "00:00:00:00:00:0a",
+				BridgePriority: 32768,
+				BridgeAddress:  "00:00:00:00:00:02",
+				RootPort:       "eth0",
+				RootPathCost:   2000,
+				Ports: []STPPortStatus{
+					{
+						Interface:  "eth0",
+						Role:       "root",
+						State:      "forwarding",
+						Cost:       2000,
+						Priority:   128,
+						PortNumber: 1,
+					},
+					{
+						Interface:  "eth1",
+						Role:       "alternate",
+						State:      "discarding",
+						Cost:       2000,
+						Priority:   128,
+						PortNumber: 2,
+					},
+				},
+			},
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			st := new(STPStatus)
+			err := st.UnmarshalText([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.st, st; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected STPStatus:\n- want: %#v\n-  got: %#v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidLogModules is returned when log level output from
+// 'ovs-appctl vlog/list' is not in the expected format.
+var ErrInvalidLogModules = errors.New("invalid log modules")
+
+// A LogDestination is a destination to which an Open vSwitch daemon writes
+// log messages.
+type LogDestination string
+
+// LogDestination constants which are available to Open vSwitch daemons.
+const (
+	LogDestinationConsole LogDestination = "console"
+	LogDestinationSyslog  LogDestination = "syslog"
+	LogDestinationFile    LogDestination = "file"
+)
+
+// A LogLevel is the minimum severity of the log messages which an Open
+// vSwitch daemon writes to a LogDestination.
+type LogLevel string
+
+// LogLevel constants which are available to Open vSwitch daemons, from the
+// least to the most verbose.
+const (
+	LogLevelOff   LogLevel = "off"
+	LogLevelEmer  LogLevel = "emer"
+	LogLevelErr   LogLevel = "err"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelDebug LogLevel = "dbg"
+)
+
+// logAny is used by vlog/set to refer to all modules or destinations.
+const logAny = "ANY"
+
+// A LogModule is a module of an Open vSwitch daemon, and the levels at which
+// it logs to each LogDestination.
+type LogModule struct {
+	Name   string
+	Levels map[LogDestination]LogLevel
+}
+
+// parseLogModules parses LogModules from the textual output of
+// 'ovs-appctl vlog/list':
+//
+//	                 console    syslog    file
+//	                 -------    ------    ------
+//	backtrace          OFF        ERR       INFO
+//	bfd                OFF        ERR       INFO
+func parseLogModules(b []byte) ([]*LogModule, error) {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) < 2 {
+		return nil, ErrInvalidLogModules
+	}
+
+	// The first line names each destination, and the second line
+	// underlines them.
+	var dests []LogDestination
+	for _, d := range strings.Fields(lines[0]) {
+		dests = append(dests, LogDestination(d))
+	}
+
+	if len(strings.Fields(lines[1])) != len(dests) || !strings.HasPrefix(strings.TrimSpace(lines[1]), "-") {
+		return nil, ErrInvalidLogModules
+	}
+
+	var modules []*LogModule
+	for _, line := range lines[2:] {
+		ss := strings.Fields(line)
+		if len(ss) == 0 {
+			continue
+		}
+		if len(ss) != len(dests)+1 {
+			return nil, ErrInvalidLogModules
+		}
+
+		m := &LogModule{
+			Name:   ss[0],
+			Levels: make(map[LogDestination]LogLevel, len(dests)),
+		}
+
+		for i, d := range dests {
+			m.Levels[d] = LogLevel(strings.ToLower(ss[i+1]))
+		}
+
+		modules = append(modules, m)
+	}
+
+	return modules, nil
+}
+
+// logSpec creates a vlog/set specification which sets the level of module
+// for destination.  An empty module or destination refers to all modules or
+// destinations.
+func logSpec(module string, destination LogDestination, level LogLevel) string {
+	if module == "" {
+		module = logAny
+	}
+	if destination == "" {
+		destination = logAny
+	}
+
+	return module + ":" + string(destination) + ":" + string(level)
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseLogModules(t *testing.T) {
+	var tests = []struct {
+		desc string
+		s    string
+		m    []*LogModule
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  ErrInvalidLogModules,
+		},
+		{
+			desc: "no underline",
+			s:    "  console    syslog    file\nbfd   OFF   ERR   INFO\n",
+			err:  ErrInvalidLogModules,
+		},
+		{
+			desc: "missing level",
+			s:    "  console    syslog    file\n  -------    ------    ------\nbfd   OFF   ERR\n",
+			err:  ErrInvalidLogModules,
+		},
+		{
+			desc: "OK",
+			s: `                 console    syslog    file
+                 -------    ------    ------
+backtrace          OFF        ERR       INFO
+bfd                EMER       WARN      DBG
+`,
+			m: []*LogModule{
+				{
+					Name: "backtrace",
+					Levels: map[LogDestination]LogLevel{
+						LogDestinationConsole: LogLevelOff,
+						LogDestinationSyslog:  LogLevelErr,
+						LogDestinationFile:    LogLevelInfo,
+					},
+				},
+				{
+					Name: "bfd",
+					Levels: map[LogDestination]LogLevel{
+						LogDestinationConsole: LogLevelEmer,
+						LogDestinationSyslog:  LogLevelWarn,
+						LogDestinationFile:    LogLevelDebug,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m, err := parseLogModules([]byte(tt.s))
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+
+			if want, got := tt.m, m; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected LogModules:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
+
+func Test_logSpec(t *testing.T) {
+	var tests = []struct {
+		module string
+		dest   LogDestination
+		level  LogLevel
+		s      string
+	}{
+		{
+			level: LogLevelDebug,
+			s:     "ANY:ANY:dbg",
+		},
+		{
+			module: "ofproto",
+			level:  LogLevelInfo,
+			s:      "ofproto:ANY:info",
+		},
+		{
+			module: "bridge",
+			dest:   LogDestinationFile,
+			level:  LogLevelWarn,
+			s:      "bridge:file:warn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if want, got := tt.s, logSpec(tt.module, tt.dest, tt.level); want != got {
+				t.Fatalf("unexpected log spec:\n- want: %q\n-  got: %q",
+					want, got)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package ovs
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -152,6 +153,18 @@ func (v *VSwitchService) exec(args ...string) ([]byte, error) {
 	return v.c.exec("ovs-vsctl", args...)
 }
 
+// stringSet formats ss as a set of strings in the syntax accepted by
+// 'ovs-vsctl' for column values, quoting each string so that it may
+// contain characters such as colons.
+func stringSet(ss []string) string {
+	qs := make([]string, 0, len(ss))
+	for _, s := range ss {
+		qs = append(qs, strconv.Quote(s))
+	}
+
+	return "[" + strings.Join(qs, ",") + "]"
+}
+
 // A VSwitchGetService is used in a VSwitchService to execute 'ovs-vsctl get'
 // subcommands.
 type VSwitchGetService struct {
@@ -0,0 +1,221 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// errInvalidRecords is returned when ovs-vsctl table output cannot be
+	// decoded.
+	errInvalidRecords = errors.New("invalid ovs-vsctl table output")
+)
+
+// A Record is a row of a table in the Open vSwitch database, as reported by
+// 'ovs-vsctl --format=json --data=json'.  Records are keyed by column name.
+//
+// Column values are decoded from OVSDB JSON notation: strings and booleans
+// are stored as-is, integers as int64, reals as float64, UUIDs as strings,
+// sets as []interface{}, and maps as map[string]interface{}.  A set with
+// exactly one element may be stored as that element alone.
+type Record map[string]interface{}
+
+// UUID returns the UUID of the record, or an empty string if the _uuid
+// column was not reported.
+func (r Record) UUID() string {
+	return r.String("_uuid")
+}
+
+// String returns the value of a string or UUID column, or an empty string
+// if the column is empty, missing, or does not contain a string.
+func (r Record) String(column string) string {
+	s, _ := r[column].(string)
+	return s
+}
+
+// Strings returns the elements of a set column which contain strings or
+// UUIDs.
+func (r Record) Strings(column string) []string {
+	switch v := r[column].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var ss []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				ss = append(ss, s)
+			}
+		}
+		return ss
+	default:
+		return nil
+	}
+}
+
+// Map returns the entries of a map column, with each value formatted as a
+// string.
+func (r Record) Map(column string) map[string]string {
+	m, ok := r[column].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = fmt.Sprint(v)
+	}
+
+	return out
+}
+
+// List returns records from the specified table of the Open vSwitch
+// database.  If no records are specified, all records in the table are
+// returned.  Records may be identified by UUID, or by name for tables such
+// as Bridge, Port, and Interface.
+func (v *VSwitchService) List(table string, records ...string) ([]Record, error) {
+	args := []string{"--format=json", "--data=json", "list", table}
+	args = append(args, records...)
+
+	return v.records(args...)
+}
+
+// Find returns all records from the specified table of the Open vSwitch
+// database which match all of the specified conditions, such as
+// "name=br0" or "other_config:disable-in-band=true".
+func (v *VSwitchService) Find(table string, conditions ...string) ([]Record, error) {
+	args := []string{"--format=json", "--data=json", "find", table}
+	args = append(args, conditions...)
+
+	return v.records(args...)
+}
+
+// records executes ovs-vsctl with args and parses its table output.
+func (v *VSwitchService) records(args ...string) ([]Record, error) {
+	out, err := v.exec(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRecords(out)
+}
+
+// parseRecords parses the output of ovs-vsctl commands which produce tables,
+// when formatted using '--format=json --data=json'.
+func parseRecords(b []byte) ([]Record, error) {
+	var table struct {
+		Headings []string        `json:"headings"`
+		Data     [][]interface{} `json:"data"`
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&table); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(table.Data))
+	for _, row := range table.Data {
+		if len(row) != len(table.Headings) {
+			return nil, errInvalidRecords
+		}
+
+		r := make(Record, len(row))
+		for i, c := range table.Headings {
+			v, err := parseRecordValue(row[i])
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %v", c, err)
+			}
+
+			r[c] = v
+		}
+
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// parseRecordValue decodes a column value in OVSDB JSON notation.
+func parseRecordValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string, bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		if len(v) != 2 {
+			return nil, errInvalidRecords
+		}
+
+		tag, _ := v[0].(string)
+		switch tag {
+		case "uuid", "named-uuid":
+			s, ok := v[1].(string)
+			if !ok {
+				return nil, errInvalidRecords
+			}
+			return s, nil
+		case "set":
+			es, ok := v[1].([]interface{})
+			if !ok {
+				return nil, errInvalidRecords
+			}
+
+			set := make([]interface{}, 0, len(es))
+			for _, e := range es {
+				ev, err := parseRecordValue(e)
+				if err != nil {
+					return nil, err
+				}
+				set = append(set, ev)
+			}
+			return set, nil
+		case "map":
+			ps, ok := v[1].([]interface{})
+			if !ok {
+				return nil, errInvalidRecords
+			}
+
+			m := make(map[string]interface{}, len(ps))
+			for _, p := range ps {
+				kv, ok := p.([]interface{})
+				if !ok || len(kv) != 2 {
+					return nil, errInvalidRecords
+				}
+
+				k, err := parseRecordValue(kv[0])
+				if err != nil {
+					return nil, err
+				}
+				ev, err := parseRecordValue(kv[1])
+				if err != nil {
+					return nil, err
+				}
+
+				m[fmt.Sprint(k)] = ev
+			}
+			return m, nil
+		}
+	}
+
+	return nil, errInvalidRecords
+}
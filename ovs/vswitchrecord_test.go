@@ -0,0 +1,194 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClientVSwitchList(t *testing.T) {
+	out := `{"data":[[["uuid","5f3a7a3e-0000-4000-8000-000000000001"],"br0",["set",[]],"secure",["set",[["uuid","5f3a7a3e-0000-4000-8000-000000000002"],["uuid","5f3a7a3e-0000-4000-8000-000000000003"]]],["map",[["disable-in-band","true"],["hwaddr","00:01:02:03:04:05"]]],true,["set",["OpenFlow10","OpenFlow13"]]]],"headings":["_uuid","name","controller","fail_mode","ports","other_config","stp_enable","protocols"]}`
+
+	c := testClient([]OptionFunc{Timeout(1)}, func(cmd string, args ...string) ([]byte, error) {
+		if want, got := "ovs-vsctl", cmd; want != got {
+			t.Fatalf("incorrect command:\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		wantArgs := []string{"--timeout=1", "--format=json", "--data=json", "list", "Bridge", "br0"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(out), nil
+	})
+
+	records, err := c.VSwitch.List("Bridge", "br0")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.List: %v", err)
+	}
+
+	want := []Record{{
+		"_uuid":      "5f3a7a3e-0000-4000-8000-000000000001",
+		"name":       "br0",
+		"controller": []interface{}{},
+		"fail_mode":  "secure",
+		"ports": []interface{}{
+			"5f3a7a3e-0000-4000-8000-000000000002",
+			"5f3a7a3e-0000-4000-8000-000000000003",
+		},
+		"other_config": map[string]interface{}{
+			"disable-in-band": "true",
+			"hwaddr":          "00:01:02:03:04:05",
+		},
+		"stp_enable": true,
+		"protocols":  []interface{}{"OpenFlow10", "OpenFlow13"},
+	}}
+
+	if want, got := want, records; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected records:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	r := records[0]
+	if want, got := "5f3a7a3e-0000-4000-8000-000000000001", r.UUID(); want != got {
+		t.Fatalf("unexpected UUID:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+	if want, got := "secure", r.String("fail_mode"); want != got {
+		t.Fatalf("unexpected fail mode:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+	if want, got := "", r.String("controller"); want != got {
+		t.Fatalf("unexpected controller:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+	if want, got := []string{"OpenFlow10", "OpenFlow13"}, r.Strings("protocols"); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected protocols:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+	if want, got := []string{"br0"}, r.Strings("name"); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected name:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+
+	wantConfig := map[string]string{
+		"disable-in-band": "true",
+		"hwaddr":          "00:01:02:03:04:05",
+	}
+	if want, got := wantConfig, r.Map("other_config"); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected other_config:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchFind(t *testing.T) {
+	out := `{"data":[["eth0",["set",[1]],["map",[[0,["uuid","5f3a7a3e-0000-4000-8000-000000000004"]]]],1.5]],"headings":["name","ofport","queues","ratio"]}`
+
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		wantArgs := []string{"--format=json", "--data=json", "find", "Interface", "type=internal", "admin_state=up"}
+		if want, got := wantArgs, args; !reflect.DeepEqual(want, got) {
+			t.Fatalf("incorrect arguments\n- want: %v\n-  got: %v",
+				want, got)
+		}
+
+		return []byte(out), nil
+	})
+
+	records, err := c.VSwitch.Find("Interface", "type=internal", "admin_state=up")
+	if err != nil {
+		t.Fatalf("unexpected error for Client.VSwitch.Find: %v", err)
+	}
+
+	want := []Record{{
+		"name":   "eth0",
+		"ofport": []interface{}{int64(1)},
+		"queues": map[string]interface{}{
+			"0": "5f3a7a3e-0000-4000-8000-000000000004",
+		},
+		"ratio": 1.5,
+	}}
+
+	if want, got := want, records; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected records:\n- want: %v\n-  got: %v",
+			want, got)
+	}
+}
+
+func TestClientVSwitchListError(t *testing.T) {
+	c := testClient(nil, func(cmd string, args ...string) ([]byte, error) {
+		return nil, errors.New("no row")
+	})
+
+	if _, err := c.VSwitch.List("Bridge", "br0"); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func Test_parseRecords(t *testing.T) {
+	tests := []struct {
+		desc string
+		s    string
+		want []Record
+		ok   bool
+	}{
+		{
+			desc: "empty",
+			s:    `{"data":[],"headings":["name"]}`,
+			want: []Record{},
+			ok:   true,
+		},
+		{
+			desc: "not JSON",
+			s:    "name : br0",
+		},
+		{
+			desc: "wrong number of columns",
+			s:    `{"data":[["br0","secure"]],"headings":["name"]}`,
+		},
+		{
+			desc: "unknown tag",
+			s:    `{"data":[[["foo","bar"]]],"headings":["name"]}`,
+		},
+		{
+			desc: "invalid map",
+			s:    `{"data":[[["map",[["a"]]]]],"headings":["other_config"]}`,
+		},
+		{
+			desc: "null value",
+			s:    `{"data":[[null]],"headings":["name"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := parseRecords([]byte(tt.s))
+			if err != nil && tt.ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			if want, got := tt.want, got; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected records:\n- want: %v\n-  got: %v",
+					want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// A BackpressurePolicy determines how a Client handles update notifications
+// for a monitor whose consumer is not receiving updates quickly enough.
+type BackpressurePolicy int
+
+// Possible BackpressurePolicy values.
+const (
+	// Stop reading from the connection until the consumer receives a
+	// queued update.  The server stops sending once its own buffers fill, so
+	// no updates are lost, but responses to RPCs and updates for all other
+	// monitors are delayed until the consumer catches up.
+	BackpressureBlock BackpressurePolicy = iota
+
+	// Merge each new update into the most recently queued update when the
+	// result can be represented as a single update, such as when a row is
+	// modified several times, or inserted and then deleted.  Updates which
+	// cannot be merged are handled as with BackpressureBlock.  The number of
+	// merged updates is reported by ClientStats.
+	BackpressureCoalesce
+
+	// Discard new updates until the consumer receives a queued update.  The
+	// number of discarded updates is reported by ClientStats.  Discarding
+	// updates leaves the consumer's view of the database inconsistent, so it
+	// is only suitable for consumers which act on individual events, and a
+	// Cache must not be used with it.
+	BackpressureDrop
+)
+
+// MonitorBackpressure limits the number of update notifications queued for
+// each monitor to limit, in addition to those buffered by its updates
+// channel, and specifies the policy applied when the limit is reached.
+//
+// By default, updates are queued in memory without limit so that a slow
+// consumer does not delay RPCs or other monitors, at the cost of unbounded
+// memory growth if the consumer never catches up.  The limit does not apply
+// while a monitor is being re-established after reconnecting.
+func MonitorBackpressure(limit int, policy BackpressurePolicy) OptionFunc {
+	return func(c *Client) error {
+		if limit <= 0 {
+			return errors.New("monitor backpressure limit must be greater than zero")
+		}
+
+		switch policy {
+		case BackpressureBlock, BackpressureCoalesce, BackpressureDrop:
+		default:
+			return errors.New("invalid monitor backpressure policy")
+		}
+
+		c.queueLimit, c.queuePolicy = limit, policy
+		return nil
+	}
+}
+
+// A delivery delivers an update to a monitor's consumer.
+type delivery struct {
+	// send delivers the update, and must return when done is closed.
+	send func(done <-chan struct{})
+
+	// If set, the contents of the update, and a function which merges the
+	// contents of a later delivery into this one and reports whether it was
+	// able to do so.  merge is only called while the delivery is queued.
+	value interface{}
+	merge func(next delivery) bool
+}
+
+// admit applies the Client's backpressure policy before d is queued, and
+// reports whether d must still be added to the queue.  b.mu must be held.
+func (b *monitorBase) admit(d delivery) bool {
+	limit := b.c.queueLimit
+	if limit == 0 {
+		return true
+	}
+
+	for len(b.queue) >= limit {
+		switch b.c.queuePolicy {
+		case BackpressureDrop:
+			atomic.AddInt64(&b.c.monDropped, 1)
+			return false
+		case BackpressureCoalesce:
+			if last := b.queue[len(b.queue)-1]; last.merge != nil && last.merge(d) {
+				atomic.AddInt64(&b.c.monCoalesced, 1)
+				return false
+			}
+		}
+
+		// Wait for the delivery goroutine to make room.
+		if b.space == nil {
+			b.space = sync.NewCond(&b.mu)
+		}
+		b.space.Wait()
+
+		if b.closed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeTableUpdates merges the changes in next, which occurred after those
+// in u, into u, and reports whether it was able to do so.  u is unmodified
+// if the changes cannot be merged.
+func mergeTableUpdates(u, next TableUpdates) bool {
+	merged := make(map[string]map[string]*RowUpdate)
+	for table, tu := range next {
+		for uuid, n := range tu {
+			ru, ok := u[table][uuid]
+			if !ok {
+				continue
+			}
+
+			m, ok := mergeRowUpdate(ru, n)
+			if !ok {
+				return false
+			}
+
+			if merged[table] == nil {
+				merged[table] = make(map[string]*RowUpdate)
+			}
+			merged[table][uuid] = m
+		}
+	}
+
+	for table, tu := range next {
+		if u[table] == nil {
+			u[table] = make(TableUpdate, len(tu))
+		}
+
+		for uuid, n := range tu {
+			m, ok := merged[table][uuid]
+			switch {
+			case !ok:
+				u[table][uuid] = n
+			case m == nil:
+				// Inserted and then deleted.
+				delete(u[table], uuid)
+			default:
+				u[table][uuid] = *m
+			}
+		}
+	}
+
+	return true
+}
+
+// mergeRowUpdate merges two successive changes to a row.  It returns nil if
+// the changes cancel out, and false if they cannot be merged.
+func mergeRowUpdate(ru, next RowUpdate) (*RowUpdate, bool) {
+	switch {
+	case ru.New == nil:
+		// Deleted, and inserted again.
+		return nil, false
+	case ru.Old == nil && next.New == nil:
+		// Inserted, and deleted again.
+		return nil, true
+	case ru.Old == nil:
+		// Inserted, and modified or deleted.
+		return &RowUpdate{New: next.New}, true
+	}
+
+	// Old contains the value of each changed column before the first change.
+	old := make(Row, len(ru.Old)+len(next.Old))
+	for column, v := range next.Old {
+		old[column] = v
+	}
+	for column, v := range ru.Old {
+		old[column] = v
+	}
+
+	return &RowUpdate{Old: old, New: next.New}, true
+}
+
+// mergeTableUpdates2 is like mergeTableUpdates, for TableUpdates2.  Without
+// the database's schema, the diffs of modified set and map columns cannot be
+// combined, so rows which are modified by both u and next are only merged if
+// different columns are modified.
+func mergeTableUpdates2(u, next TableUpdates2) bool {
+	if u == nil || next == nil {
+		// Resets cannot be merged.
+		return false
+	}
+
+	merged := make(map[string]map[string]*RowUpdate2)
+	for table, tu := range next {
+		for uuid, n := range tu {
+			ru, ok := u[table][uuid]
+			if !ok {
+				continue
+			}
+
+			m, ok := mergeRowUpdate2(ru, n)
+			if !ok {
+				return false
+			}
+
+			if merged[table] == nil {
+				merged[table] = make(map[string]*RowUpdate2)
+			}
+			merged[table][uuid] = m
+		}
+	}
+
+	for table, tu := range next {
+		if u[table] == nil {
+			u[table] = make(TableUpdate2, len(tu))
+		}
+
+		for uuid, n := range tu {
+			m, ok := merged[table][uuid]
+			switch {
+			case !ok:
+				u[table][uuid] = n
+			case m == nil:
+				delete(u[table], uuid)
+			default:
+				u[table][uuid] = *m
+			}
+		}
+	}
+
+	return true
+}
+
+// mergeRowUpdate2 is like mergeRowUpdate, for RowUpdate2s.
+func mergeRowUpdate2(ru, next RowUpdate2) (*RowUpdate2, bool) {
+	inserted := ru.Initial != nil || ru.Insert != nil
+
+	switch {
+	case next.Delete && inserted:
+		return nil, true
+	case next.Delete && ru.Modify != nil:
+		return &RowUpdate2{Delete: true}, true
+	case ru.Modify == nil || next.Modify == nil:
+		return nil, false
+	}
+
+	modify := make(Row, len(ru.Modify)+len(next.Modify))
+	for column, v := range ru.Modify {
+		modify[column] = v
+	}
+	for column, v := range next.Modify {
+		if _, ok := modify[column]; ok {
+			return nil, false
+		}
+
+		modify[column] = v
+	}
+
+	return &RowUpdate2{Modify: modify}, true
+}
@@ -0,0 +1,340 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMonitorBackpressureInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		policy ovsdb.BackpressurePolicy
+	}{
+		{
+			name:   "limit",
+			policy: ovsdb.BackpressureBlock,
+		},
+		{
+			name:   "policy",
+			limit:  1,
+			policy: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _, done := jsonrpc.TestNetConn(t, nil)
+			defer done()
+
+			if _, err := ovsdb.New(conn, ovsdb.MonitorBackpressure(tt.limit, tt.policy)); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestMonitorBackpressureBlock(t *testing.T) {
+	const (
+		n     = 64
+		limit = 2
+	)
+
+	c, m, notify, done := testBackpressureMonitor(t, limit, ovsdb.BackpressureBlock)
+	defer done()
+
+	// Sending blocks once the client stops reading, so send in the
+	// background.
+	go func() {
+		for i := 0; i < n; i++ {
+			notify(bridgeUpdate(i))
+		}
+	}()
+
+	// Give the client time to fill its queue.
+	time.Sleep(100 * time.Millisecond)
+
+	if q := c.Stats().Monitors.Queued; q > limit {
+		t.Fatalf("queue exceeded limit: %d > %d", q, limit)
+	}
+
+	// Every update is delivered, in order.
+	for i := 0; i < n; i++ {
+		if diff := cmp.Diff(bridgeUpdate(i), receive(t, m)); diff != "" {
+			t.Fatalf("unexpected update %d (-want +got):\n%s", i, diff)
+		}
+	}
+
+	if diff := cmp.Diff(0, c.Stats().Monitors.Dropped); diff != "" {
+		t.Fatalf("unexpected dropped updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestMonitorBackpressureDrop(t *testing.T) {
+	const (
+		n     = 64
+		limit = 2
+	)
+
+	c, m, notify, done := testBackpressureMonitor(t, limit, ovsdb.BackpressureDrop)
+	defer done()
+
+	for i := 0; i < n; i++ {
+		notify(bridgeUpdate(i))
+	}
+
+	// Wait for the client to handle every update, without receiving any.
+	timeout := time.After(2 * time.Second)
+	for {
+		s := c.Stats().Monitors
+		if s.Dropped > 0 && s.Queued <= limit && s.Dropped+len(m.Updates())+s.Queued >= n-1 {
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for dropped updates: %+v", s)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// Updates which were not dropped are delivered in order.
+	var got int
+	last := -1
+	for got+c.Stats().Monitors.Dropped < n {
+		u := receive(t, m)
+		for id := range u["Bridge"] {
+			i, _ := strconv.Atoi(id)
+			if i <= last {
+				t.Fatalf("update %d delivered after %d", i, last)
+			}
+			last = i
+		}
+
+		got++
+	}
+}
+
+func TestMonitorBackpressureCoalesce(t *testing.T) {
+	const (
+		n     = 64
+		limit = 2
+	)
+
+	c, m, notify, done := testBackpressureMonitor(t, limit, ovsdb.BackpressureCoalesce)
+	defer done()
+
+	// Modify the same row repeatedly.
+	modify := func(i int) ovsdb.TableUpdates {
+		return ovsdb.TableUpdates{"Bridge": {"br0": {
+			Old: ovsdb.Row{"n": int64(i - 1)},
+			New: ovsdb.Row{"n": int64(i), "name": "br0"},
+		}}}
+	}
+
+	for i := 1; i <= n; i++ {
+		notify(modify(i))
+	}
+
+	// The final update reflects every modification.
+	var got int
+	for {
+		u := receive(t, m)
+		got++
+
+		if u["Bridge"]["br0"].New["n"] == int64(n) {
+			break
+		}
+	}
+
+	s := c.Stats().Monitors
+	if s.Coalesced == 0 {
+		t.Fatal("expected updates to be coalesced")
+	}
+
+	if diff := cmp.Diff(n, got+s.Coalesced); diff != "" {
+		t.Fatalf("unexpected number of updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestCondMonitorBackpressureCoalesce(t *testing.T) {
+	const limit = 1
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		switch req.Method {
+		case "monitor_cond":
+			idC <- req.Params.([]interface{})[1].(string)
+		case "monitor_cancel":
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, struct{}{}),
+		}
+	}, ovsdb.MonitorBackpressure(limit, ovsdb.BackpressureCoalesce))
+	defer done()
+
+	m, err := c.MonitorCond(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+	defer m.Cancel(context.Background())
+
+	id := <-idC
+
+	notify := func(updates string) {
+		notifC <- &jsonrpc.Response{
+			Method: "update2",
+			Params: []byte(`["` + id + `",` + updates + `]`),
+		}
+	}
+
+	// Fill the updates channel and the queue with modifications of the same
+	// column of another row, which cannot be merged, and wait for them to be
+	// queued.
+	n := cap(m.Updates()) + 1 + limit
+	for i := 0; i < n; i++ {
+		notify(`{"Bridge":{"br9":{"modify":{"name":"` + strconv.Itoa(i) + `"}}}}`)
+	}
+
+	timeout := time.After(2 * time.Second)
+	for c.Stats().Monitors.Queued < limit {
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for queued updates")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// Each of these updates can be merged into the last queued update.
+	notify(`{"Bridge":{"br0":{"insert":{"name":"br0"}}}}`)
+	notify(`{"Bridge":{"br0":{"delete":null}}}`)
+	notify(`{"Bridge":{"br1":{"modify":{"name":"br1"}}}}`)
+	notify(`{"Bridge":{"br1":{"modify":{"ports":["set",[]]}}}}`)
+	notify(`{"Bridge":{"br2":{"modify":{"name":"br2"}}}}`)
+	notify(`{"Bridge":{"br2":{"delete":null}}}`)
+
+	for c.Stats().Monitors.Coalesced < 6 {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for coalesced updates: %+v", c.Stats().Monitors)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// Receive updates until the merged update arrives.
+	var last ovsdb.TableUpdates2
+	for !last["Bridge"]["br2"].Delete {
+		select {
+		case last = <-m.Updates():
+		case <-timeout:
+			t.Fatal("timed out waiting for updates")
+		}
+	}
+
+	want := ovsdb.TableUpdates2{"Bridge": {
+		"br9": {Modify: ovsdb.Row{"name": strconv.Itoa(n - 1)}},
+		"br1": {Modify: ovsdb.Row{
+			"name":  "br1",
+			"ports": []interface{}{"set", []interface{}{}},
+		}},
+		"br2": {Delete: true},
+	}}
+
+	if diff := cmp.Diff(want, last); diff != "" {
+		t.Fatalf("unexpected coalesced updates (-want +got):\n%s", diff)
+	}
+}
+
+// testBackpressureMonitor creates a Monitor of the Bridge table using a
+// Client configured with the specified backpressure options.  The returned
+// function sends update notifications for the Monitor.
+func testBackpressureMonitor(t *testing.T, limit int, policy ovsdb.BackpressurePolicy) (*ovsdb.Client, *ovsdb.Monitor, func(ovsdb.TableUpdates), func()) {
+	t.Helper()
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		var res interface{} = struct{}{}
+		switch req.Method {
+		case "monitor":
+			idC <- req.Params.([]interface{})[1].(string)
+			res = ovsdb.TableUpdates{}
+		case "monitor_cancel":
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	}, ovsdb.MonitorBackpressure(limit, policy))
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	id := <-idC
+
+	notify := func(updates ovsdb.TableUpdates) {
+		notifC <- &jsonrpc.Response{
+			Method: "update",
+			Params: mustMarshalJSON(t, []interface{}{id, updates}),
+		}
+	}
+
+	return c, m, notify, func() {
+		_ = m.Cancel(context.Background())
+		done()
+	}
+}
+
+// bridgeUpdate returns a TableUpdates which inserts the bridge i.
+func bridgeUpdate(i int) ovsdb.TableUpdates {
+	return ovsdb.TableUpdates{"Bridge": {strconv.Itoa(i): {
+		New: ovsdb.Row{"name": "br" + strconv.Itoa(i)},
+	}}}
+}
+
+// receive receives a single update from m.
+func receive(t *testing.T, m *ovsdb.Monitor) ovsdb.TableUpdates {
+	t.Helper()
+
+	select {
+	case u := <-m.Updates():
+		return u
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for update")
+		return nil
+	}
+}
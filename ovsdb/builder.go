@@ -0,0 +1,205 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// A TransactionBuilder builds a transaction from a series of operations.
+// Rows inserted by a TransactionBuilder are automatically assigned named
+// UUIDs, which can be used to refer to those rows in later operations.
+//
+// TransactionBuilders are created using Client.NewTransaction, and are not
+// safe for concurrent use.
+type TransactionBuilder struct {
+	c   *Client
+	db  string
+	ops []TransactOp
+}
+
+// NewTransaction creates a TransactionBuilder for a transaction on the
+// specified database.
+func (c *Client) NewTransaction(db string) *TransactionBuilder {
+	return &TransactionBuilder{
+		c:  c,
+		db: db,
+	}
+}
+
+// Insert adds an operation which inserts row into table, and returns a
+// NamedUUID which refers to the new row.  The NamedUUID can be used as a
+// column value in the rows, conditions, and mutations of later operations.
+func (b *TransactionBuilder) Insert(table string, row Row) NamedUUID {
+	// "row" followed by the operation's index is always a valid named
+	// UUID, and is unique within the transaction.
+	n := NamedUUID("row" + strconv.Itoa(len(b.ops)))
+
+	b.ops = append(b.ops, Insert{
+		Table:    table,
+		UUIDName: string(n),
+		Row:      row,
+	})
+
+	return n
+}
+
+// InsertChild adds an operation which inserts row into table, and
+// operations which add a reference to the new row to each of parents, and
+// returns a NamedUUID which refers to the new row.
+//
+// OVSDB servers delete rows in non-root tables which are not referred to by
+// any other row when a transaction commits, so rows inserted into tables
+// such as Port or Interface must be referred to by a parent row in the same
+// transaction.
+func (b *TransactionBuilder) InsertChild(table string, row Row, parents ...Parent) NamedUUID {
+	n := b.Insert(table, row)
+	for _, p := range parents {
+		b.Op(p.mutate(n))
+	}
+
+	return n
+}
+
+// Update adds an operation which sets the columns in row for all rows in
+// table which match where.
+func (b *TransactionBuilder) Update(table string, where []Cond, row Row) *TransactionBuilder {
+	return b.Op(Update{
+		Table: table,
+		Where: where,
+		Row:   row,
+	})
+}
+
+// Mutate adds an operation which applies mutations to all rows in table
+// which match where.
+func (b *TransactionBuilder) Mutate(table string, where []Cond, mutations ...Mutation) *TransactionBuilder {
+	return b.Op(Mutate{
+		Table:     table,
+		Where:     where,
+		Mutations: mutations,
+	})
+}
+
+// Op adds an arbitrary operation to the transaction.
+func (b *TransactionBuilder) Op(op TransactOp) *TransactionBuilder {
+	b.ops = append(b.ops, op)
+	return b
+}
+
+// Ops returns the operations added to the transaction so far.
+func (b *TransactionBuilder) Ops() []TransactOp {
+	return b.ops
+}
+
+// A TransactionResult is the result of a transaction built by a
+// TransactionBuilder, or performed by Client.TransactResult.
+type TransactionResult struct {
+	// The results of each operation, in the order the operations were
+	// added.
+	Operations []OperationResult
+
+	// The UUIDs of the rows inserted by the transaction, keyed by the
+	// NamedUUIDs returned by TransactionBuilder.Insert, or by the UUIDName
+	// of each Insert passed to Client.TransactResult.
+	UUIDs map[NamedUUID]UUID
+}
+
+// newTransactionResult creates a TransactionResult for the results of ops.
+func newTransactionResult(ops []TransactOp, results []OperationResult) *TransactionResult {
+	uuids := make(map[NamedUUID]UUID)
+	for i, op := range ops {
+		var name string
+		switch op := op.(type) {
+		case Insert:
+			name = op.UUIDName
+		case *Insert:
+			name = op.UUIDName
+		}
+
+		if name != "" && i < len(results) && results[i].UUID != "" {
+			uuids[NamedUUID(name)] = UUID(results[i].UUID)
+		}
+	}
+
+	return &TransactionResult{
+		Operations: results,
+		UUIDs:      uuids,
+	}
+}
+
+// Commit executes the transaction.  If any operation fails, the first
+// *Error returned by the OVSDB server is returned, as with Client.Transact.
+func (b *TransactionBuilder) Commit(ctx context.Context) (*TransactionResult, error) {
+	return b.c.TransactResult(ctx, b.db, b.ops)
+}
+
+// A Parent identifies the rows which must refer to a row inserted by
+// InsertChild or TransactionBuilder.InsertChild, and the column of those
+// rows which holds the reference.
+type Parent struct {
+	// The name of the parent table.
+	Table string
+
+	// Zero or more Conds which determine the parent rows.  If empty, all
+	// rows in the table refer to the new row.
+	Where []Cond
+
+	// The set or map column which refers to the new row.  The reference is
+	// inserted as an element of a set column, or as the value of Key in a
+	// map column.
+	Column string
+
+	// If non-nil, Column is a map column, and the reference is inserted
+	// with this key.
+	Key interface{}
+}
+
+// mutate returns an operation which adds a reference to uuid to the parent
+// rows.
+func (p Parent) mutate(uuid NamedUUID) TransactOp {
+	var v interface{} = Set{uuid}
+	if p.Key != nil {
+		v = Map{p.Key: uuid}
+	}
+
+	return Mutate{
+		Table:     p.Table,
+		Where:     p.Where,
+		Mutations: []Mutation{MutateInsert(p.Column, v)},
+	}
+}
+
+// InsertChild returns ins, followed by operations which add a reference to
+// the row inserted by ins to each of parents, as described for
+// TransactionBuilder.InsertChild.  ins must specify a UUIDName.
+func InsertChild(ins Insert, parents ...Parent) ([]TransactOp, error) {
+	if !isID(ins.UUIDName) {
+		return nil, errors.New("insert of child row must specify a valid UUID name")
+	}
+	if len(parents) == 0 {
+		return nil, errors.New("insert of child row must specify at least one parent")
+	}
+
+	ops := make([]TransactOp, 0, 1+len(parents))
+	ops = append(ops, ins)
+	for _, p := range parents {
+		ops = append(ops, p.mutate(NamedUUID(ins.UUIDName)))
+	}
+
+	return ops, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	const (
+		ifaceUUID = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+		portUUID  = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a3"
+	)
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("transact", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		var want []interface{}
+		err := json.Unmarshal([]byte(`["Open_vSwitch",
+			{"op":"insert","table":"Interface","uuid-name":"row0","row":{"name":"eth0"}},
+			{"op":"insert","table":"Port","uuid-name":"row1","row":{"name":"eth0","interfaces":["named-uuid","row0"]}},
+			{"op":"mutate","table":"Bridge","where":[["name","==","br0"]],"mutations":[["ports","insert",["set",[["named-uuid","row1"]]]]]},
+			{"op":"update","table":"Interface","where":[["_uuid","==",["named-uuid","row0"]]],"row":{"mtu_request":9000}}
+		]`), &want)
+		if err != nil {
+			panicf("failed to unmarshal parameters: %v", err)
+		}
+
+		if diff := cmp.Diff(want, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID: &req.ID,
+			Result: []byte(`[
+				{"uuid":["uuid","` + ifaceUUID + `"]},
+				{"uuid":["uuid","` + portUUID + `"]},
+				{"count":1},
+				{"count":1}
+			]`),
+		}
+	})
+	defer done()
+
+	b := c.NewTransaction("Open_vSwitch")
+
+	iface := b.Insert("Interface", ovsdb.Row{"name": "eth0"})
+	port := b.Insert("Port", ovsdb.Row{"name": "eth0", "interfaces": iface})
+
+	b.
+		Mutate("Bridge", []ovsdb.Cond{ovsdb.Equal("name", "br0")},
+			ovsdb.MutateInsert("ports", ovsdb.Set{port}),
+		).
+		Update("Interface", []ovsdb.Cond{ovsdb.Equal("_uuid", iface)},
+			ovsdb.Row{"mtu_request": 9000},
+		)
+
+	if diff := cmp.Diff(4, len(b.Ops())); diff != "" {
+		t.Fatalf("unexpected number of operations (-want +got):\n%s", diff)
+	}
+
+	res, err := b.Commit(context.Background())
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	want := &ovsdb.TransactionResult{
+		Operations: []ovsdb.OperationResult{
+			{UUID: ifaceUUID},
+			{UUID: portUUID},
+			{Count: 1},
+			{Count: 1},
+		},
+		UUIDs: map[ovsdb.NamedUUID]ovsdb.UUID{
+			iface: ifaceUUID,
+			port:  portUUID,
+		},
+	}
+
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactionBuilderError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`[{"error":"constraint violation","details":"duplicate name"}]`),
+		}
+	})
+	defer done()
+
+	b := c.NewTransaction("Open_vSwitch")
+	b.Insert("Bridge", ovsdb.Row{"name": "br0"})
+
+	if _, err := b.Commit(context.Background()); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestTransactionBuilderInsertChild(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		var want []interface{}
+		err := json.Unmarshal([]byte(`["OVN_Northbound",
+			{"op":"insert","table":"ACL","uuid-name":"row0","row":{"priority":1000}},
+			{"op":"mutate","table":"Logical_Switch","where":[["name","==","ls0"]],"mutations":[["acls","insert",["set",[["named-uuid","row0"]]]]]},
+			{"op":"mutate","table":"Port_Group","where":[],"mutations":[["acls","insert",["set",[["named-uuid","row0"]]]]]},
+			{"op":"mutate","table":"Logical_Router","where":[["name","==","lr0"]],"mutations":[["options","insert",["map",[["acl",["named-uuid","row0"]]]]]]}
+		]`), &want)
+		if err != nil {
+			panicf("failed to unmarshal parameters: %v", err)
+		}
+
+		if diff := cmp.Diff(want, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`[{"uuid":["uuid","36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"]},{"count":1},{"count":2},{"count":1}]`),
+		}
+	})
+	defer done()
+
+	b := c.NewTransaction("OVN_Northbound")
+
+	acl := b.InsertChild("ACL", ovsdb.Row{"priority": 1000},
+		ovsdb.Parent{
+			Table:  "Logical_Switch",
+			Where:  []ovsdb.Cond{ovsdb.Equal("name", "ls0")},
+			Column: "acls",
+		},
+		ovsdb.Parent{
+			Table:  "Port_Group",
+			Column: "acls",
+		},
+		ovsdb.Parent{
+			Table:  "Logical_Router",
+			Where:  []ovsdb.Cond{ovsdb.Equal("name", "lr0")},
+			Column: "options",
+			Key:    "acl",
+		},
+	)
+
+	res, err := b.Commit(context.Background())
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.UUID("36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"), res.UUIDs[acl]); diff != "" {
+		t.Fatalf("unexpected child UUID (-want +got):\n%s", diff)
+	}
+}
+
+func TestInsertChild(t *testing.T) {
+	parent := ovsdb.Parent{
+		Table:  "Bridge",
+		Where:  []ovsdb.Cond{ovsdb.Equal("name", "br0")},
+		Column: "ports",
+	}
+
+	tests := []struct {
+		name    string
+		ins     ovsdb.Insert
+		parents []ovsdb.Parent
+		want    string
+		ok      bool
+	}{
+		{
+			name:    "no UUID name",
+			ins:     ovsdb.Insert{Table: "Port"},
+			parents: []ovsdb.Parent{parent},
+		},
+		{
+			name:    "invalid UUID name",
+			ins:     ovsdb.Insert{Table: "Port", UUIDName: "new-port"},
+			parents: []ovsdb.Parent{parent},
+		},
+		{
+			name: "no parents",
+			ins:  ovsdb.Insert{Table: "Port", UUIDName: "new_port"},
+		},
+		{
+			name: "OK",
+			ins: ovsdb.Insert{
+				Table:    "Port",
+				UUIDName: "new_port",
+				Row:      ovsdb.Row{"name": "eth0"},
+			},
+			parents: []ovsdb.Parent{parent},
+			want: `[
+				{"op":"insert","table":"Port","uuid-name":"new_port","row":{"name":"eth0"}},
+				{"op":"mutate","table":"Bridge","where":[["name","==","br0"]],"mutations":[["ports","insert",["set",[["named-uuid","new_port"]]]]]}
+			]`,
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ovsdb.InsertChild(tt.ins, tt.parents...)
+			if err != nil {
+				if tt.ok {
+					t.Fatalf("failed to build operations: %v", err)
+				}
+
+				return
+			}
+			if !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("failed to unmarshal expected operations: %v", err)
+			}
+			if err := json.Unmarshal(mustMarshalJSON(t, ops), &got); err != nil {
+				t.Fatalf("failed to unmarshal operations: %v", err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected operations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// A Cache maintains an in-memory copy of the rows of one or more tables,
+// which is kept up to date using a CondMonitor.  Caches are created using
+// Client.MonitorCache.
+//
+// The values of the columns in a cached Row are atoms, such as strings,
+// float64s, bools, and UUIDs, or Sets and Maps, according to each column's
+// type in the database's schema.  Rows returned by a Cache are shared and
+// must not be modified.  Changes to the cached rows may be received as
+// WatchEvents using Cache.Watch.
+type Cache struct {
+	m *CondMonitor
+
+	// mu guards schema, which is replaced if the database changes, and
+	// tables.
+	mu     sync.RWMutex
+	schema *Schema
+	tables map[string]*cacheTable
+
+	// Watchers which receive changes to rows, the rows of each table
+	// before the Cache was last reset, or nil, and whether the Cache is no
+	// longer updated.
+	watchers []*Watcher
+	prev     map[string]map[string]Row
+	finished bool
+
+	// The ID of the last transaction reflected in tables, if the Cache was
+	// created by Client.MonitorCacheSince.
+	txnID string
+
+	done chan struct{}
+}
+
+// A cacheTable is the cached contents of a single table.
+type cacheTable struct {
+	schema TableSchema
+	rows   map[string]Row
+
+	// Maps an indexed column to the UUIDs of the rows with each value.
+	indexes map[string]map[string]map[string]struct{}
+}
+
+// MonitorCache begins monitoring one or more tables of the specified
+// database, and returns a Cache which contains the monitored rows.  The keys
+// of requests are the names of the tables to monitor.
+//
+// The keys of indexes are table names, and the values are the columns of
+// each table which are indexed for use with Cache.Lookup and Cache.List.  Each element of
+// an indexed set column is indexed.  Map columns may not be indexed.
+//
+// requests may select columns and rows, but not the kinds of changes to
+// monitor: the Cache must observe every change to its rows.
+//
+// The Cache is updated until Cache.Cancel is called or the Client is closed.
+// If the Client reconnects, the Cache is refreshed from the new connection.
+func (c *Client) MonitorCache(ctx context.Context, db string, requests map[string]MonitorCondRequest, indexes map[string][]string) (*Cache, error) {
+	return c.monitorCache(ctx, db, requests, indexes, nil, false)
+}
+
+// MonitorCacheSince is like MonitorCache, but uses the monitor_cond_since RPC
+// so that the Cache records the ID of the last transaction reflected in its
+// contents, and can be saved using Cache.Snapshot.
+//
+// If snap is not nil, the Cache is first populated with the contents of snap,
+// typically saved by a previous process, and monitoring resumes from the
+// snapshot's transaction ID.  If the server still has a record of that
+// transaction, only the changes which occurred after it are downloaded.
+// Otherwise, the contents of snap are discarded and the full contents of the
+// monitored rows are downloaded.  requests should be the same as those used
+// to create the Cache which produced snap.
+func (c *Client) MonitorCacheSince(ctx context.Context, db string, requests map[string]MonitorCondRequest, indexes map[string][]string, snap *CacheSnapshot) (*Cache, error) {
+	if snap != nil && snap.Database != db {
+		return nil, fmt.Errorf("cannot restore snapshot of database %q into cache of database %q",
+			snap.Database, db)
+	}
+
+	return c.monitorCache(ctx, db, requests, indexes, snap, true)
+}
+
+// monitorCache implements MonitorCache and MonitorCacheSince.
+func (c *Client) monitorCache(ctx context.Context, db string, requests map[string]MonitorCondRequest, indexes map[string][]string, snap *CacheSnapshot, since bool) (*Cache, error) {
+	schema, err := c.GetSchema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]*cacheTable, len(requests))
+	for name, req := range requests {
+		ts, ok := schema.Tables[name]
+		if !ok {
+			return nil, fmt.Errorf("table %q not found in database %q", name, db)
+		}
+
+		// A Cache must observe every change to remain consistent.
+		if !req.Select.all() {
+			return nil, fmt.Errorf("cannot cache table %q without selecting all changes", name)
+		}
+
+		tables[name] = newCacheTable(ts)
+	}
+
+	for name, columns := range indexes {
+		t, ok := tables[name]
+		if !ok {
+			return nil, fmt.Errorf("cannot index table %q which is not monitored", name)
+		}
+
+		for _, column := range columns {
+			cs, ok := t.schema.Columns[column]
+			if !ok {
+				return nil, fmt.Errorf("cannot index unknown column %q in table %q", column, name)
+			}
+			if cs.Type.IsMap() {
+				return nil, fmt.Errorf("cannot index map column %q in table %q", column, name)
+			}
+
+			t.indexes[column] = make(map[string]map[string]struct{})
+		}
+	}
+
+	var lastTxnID string
+	if snap != nil {
+		for name, rows := range snap.Tables {
+			t, ok := tables[name]
+			if !ok {
+				continue
+			}
+
+			for uuid, row := range rows {
+				t.update(uuid, RowUpdate2{Initial: row})
+			}
+		}
+
+		lastTxnID = snap.TransactionID
+	}
+
+	var m *CondMonitor
+	if since {
+		m, err = c.monitorCondSince(ctx, db, requests, lastTxnID, true)
+	} else {
+		m, err = c.monitorCond(ctx, db, requests, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &Cache{
+		m:      m,
+		schema: schema,
+		tables: tables,
+		done:   make(chan struct{}),
+	}
+
+	if snap != nil && !m.Found {
+		// The server could not resume from the snapshot, so Initial contains
+		// the full contents of the monitored rows.
+		cc.reset()
+	}
+
+	cc.apply(m.Initial, m.LastTransactionID())
+
+	m.reqMu.Lock()
+	m.onSchema = cc.setSchema
+	m.reqMu.Unlock()
+
+	go func() {
+		defer close(cc.done)
+		defer cc.closeWatchers()
+
+		for u := range m.cacheUpdates {
+			if u.updates == nil {
+				// The full contents of the monitored rows follow.
+				cc.reset()
+				continue
+			}
+
+			cc.apply(u.updates, u.txnID)
+		}
+	}()
+
+	return cc, nil
+}
+
+// Schema returns the schema of the cached database.  If the Client uses the
+// Remonitor option, the schema is replaced when the database changes.
+func (c *Cache) Schema() *Schema {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.schema
+}
+
+// setSchema replaces the Cache's schema after its database changed.  The
+// cached tables and indexed columns must remain valid in the new schema.
+func (c *Cache) setSchema(s *Schema) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, t := range c.tables {
+		ts, ok := s.Tables[name]
+		if !ok {
+			return fmt.Errorf("table %q not found in database %q", name, s.Name)
+		}
+
+		for column := range t.indexes {
+			cs, ok := ts.Columns[column]
+			if !ok || cs.Type.IsMap() {
+				return fmt.Errorf("cannot index column %q in table %q", column, name)
+			}
+		}
+	}
+
+	c.schema = s
+	for name, t := range c.tables {
+		t.schema = s.Tables[name]
+	}
+
+	return nil
+}
+
+// Row returns the row with the specified UUID in table, and reports whether
+// the row was found.
+func (c *Cache) Row(table, uuid string) (Row, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.tables[table]
+	if !ok {
+		return nil, false
+	}
+
+	row, ok := t.rows[uuid]
+	return row, ok
+}
+
+// Rows returns all rows in table, keyed by row UUID.
+func (c *Cache) Rows(table string) map[string]Row {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.tables[table]
+	if !ok {
+		return nil
+	}
+
+	rows := make(map[string]Row, len(t.rows))
+	for uuid, row := range t.rows {
+		rows[uuid] = row
+	}
+
+	return rows
+}
+
+// RowCounts returns the number of rows in each cached table, keyed by table
+// name.
+func (c *Cache) RowCounts() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counts := make(map[string]int, len(c.tables))
+	for name, t := range c.tables {
+		counts[name] = len(t.rows)
+	}
+
+	return counts
+}
+
+// Lookup returns the rows in table whose indexed column contains value,
+// keyed by row UUID.  For a set column, value is a single element of the
+// set.  Lookup returns an error if the column is not indexed.
+func (c *Cache) Lookup(table, column string, value interface{}) (map[string]Row, error) {
+	key, err := indexKey(value)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %q is not cached", table)
+	}
+
+	index, ok := t.indexes[column]
+	if !ok {
+		return nil, fmt.Errorf("column %q in table %q is not indexed", column, table)
+	}
+
+	rows := make(map[string]Row, len(index[key]))
+	for uuid := range index[key] {
+		rows[uuid] = t.rows[uuid]
+	}
+
+	return rows, nil
+}
+
+// A CacheSnapshot contains the rows of a Cache and the ID of the last
+// transaction reflected in them.  CacheSnapshots are created using
+// Cache.Snapshot, may be encoded as JSON and stored, and are passed to
+// Client.MonitorCacheSince to restore a Cache without downloading the full
+// contents of the monitored tables.
+type CacheSnapshot struct {
+	// The name of the cached database.
+	Database string `json:"database"`
+
+	// The ID of the last transaction reflected in Tables.  It is empty if
+	// the Cache was created by Client.MonitorCache.
+	TransactionID string `json:"transaction_id"`
+
+	// The cached rows, keyed by table name and row UUID.
+	Tables map[string]map[string]Row `json:"tables"`
+}
+
+// Snapshot returns the current contents of the Cache and the ID of the last
+// transaction reflected in them.  Rows in the returned CacheSnapshot are
+// shared with the Cache and must not be modified.
+func (c *Cache) Snapshot() *CacheSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tables := make(map[string]map[string]Row, len(c.tables))
+	for name, t := range c.tables {
+		rows := make(map[string]Row, len(t.rows))
+		for uuid, row := range t.rows {
+			rows[uuid] = row
+		}
+
+		tables[name] = rows
+	}
+
+	return &CacheSnapshot{
+		Database:      c.m.db,
+		TransactionID: c.txnID,
+		Tables:        tables,
+	}
+}
+
+// ChangeConditions replaces the conditions which determine the rows cached
+// for one or more tables, as with CondMonitor.ChangeConditions.  Rows which
+// begin or cease to match the conditions are added to or removed from the
+// Cache shortly after ChangeConditions returns.
+func (c *Cache) ChangeConditions(ctx context.Context, where map[string][]Cond) error {
+	return c.m.ChangeConditions(ctx, where)
+}
+
+// Cancel stops updating the Cache.  The contents of the Cache remain
+// available.
+func (c *Cache) Cancel(ctx context.Context) error {
+	err := c.m.Cancel(ctx)
+	<-c.done
+	return err
+}
+
+// apply applies updates from the transaction txnID to the Cache.  If txnID
+// is empty, the Cache's transaction ID is unchanged.
+func (c *Cache) apply(updates TableUpdates2, txnID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if txnID != "" {
+		c.txnID = txnID
+	}
+
+	var changes []rowChange
+	for name, tu := range updates {
+		t, ok := c.tables[name]
+		if !ok {
+			continue
+		}
+
+		for uuid, ru := range tu {
+			old, row, ok := t.update(uuid, ru)
+			if ok && c.prev == nil {
+				changes = append(changes, rowChange{
+					table: name,
+					uuid:  uuid,
+					old:   old,
+					new:   row,
+				})
+			}
+		}
+	}
+
+	if c.prev != nil {
+		// The full contents of the tables were reloaded, so compare them
+		// with the contents before the reset.
+		changes = c.resetChanges()
+		c.prev = nil
+	}
+
+	c.notify(changes)
+}
+
+// reset discards the contents of the Cache.
+func (c *Cache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.txnID = ""
+
+	// Keep the rows from before the first of any consecutive resets, for
+	// comparison with the reloaded contents.
+	if c.prev == nil {
+		c.prev = make(map[string]map[string]Row, len(c.tables))
+		for name, t := range c.tables {
+			c.prev[name] = t.rows
+		}
+	}
+
+	for name, t := range c.tables {
+		nt := newCacheTable(t.schema)
+		for column := range t.indexes {
+			nt.indexes[column] = make(map[string]map[string]struct{})
+		}
+
+		c.tables[name] = nt
+	}
+}
+
+// newCacheTable creates an empty cacheTable with no indexes.
+func newCacheTable(ts TableSchema) *cacheTable {
+	return &cacheTable{
+		schema:  ts,
+		rows:    make(map[string]Row),
+		indexes: make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+// update applies a single row update.  It returns the row before and after
+// the update, and reports whether the update was applied.
+func (t *cacheTable) update(uuid string, ru RowUpdate2) (Row, Row, bool) {
+	old, exists := t.rows[uuid]
+
+	var row Row
+	switch {
+	case ru.Initial != nil:
+		row = t.normalize(ru.Initial)
+	case ru.Insert != nil:
+		row = t.normalize(ru.Insert)
+	case ru.Delete:
+	case ru.Modify != nil:
+		if !exists {
+			// Nothing to modify.
+			return nil, nil, false
+		}
+
+		row = ApplyRowDiff(t.schema, old, ru.Modify)
+	default:
+		return nil, nil, false
+	}
+
+	if !exists && row == nil {
+		// Nothing to delete.
+		return nil, nil, false
+	}
+
+	if exists {
+		t.unindex(uuid, old)
+		delete(t.rows, uuid)
+	}
+
+	if row != nil {
+		t.rows[uuid] = row
+		t.index(uuid, row)
+	}
+
+	return old, row, true
+}
+
+// normalize converts the columns of a row decoded from JSON according to
+// their types.
+func (t *cacheTable) normalize(row Row) Row {
+	out := make(Row, len(row))
+	for column, v := range row {
+		out[column] = columnValue(t.schema, column, v)
+	}
+
+	return out
+}
+
+// index adds row to the table's indexes.
+func (t *cacheTable) index(uuid string, row Row) {
+	t.eachKey(row, func(index map[string]map[string]struct{}, key string) {
+		uuids, ok := index[key]
+		if !ok {
+			uuids = make(map[string]struct{})
+			index[key] = uuids
+		}
+
+		uuids[uuid] = struct{}{}
+	})
+}
+
+// unindex removes row from the table's indexes.
+func (t *cacheTable) unindex(uuid string, row Row) {
+	t.eachKey(row, func(index map[string]map[string]struct{}, key string) {
+		delete(index[key], uuid)
+		if len(index[key]) == 0 {
+			delete(index, key)
+		}
+	})
+}
+
+// eachKey invokes fn with each index key of row's indexed columns.
+func (t *cacheTable) eachKey(row Row, fn func(index map[string]map[string]struct{}, key string)) {
+	for column, index := range t.indexes {
+		v, ok := row[column]
+		if !ok {
+			continue
+		}
+
+		atoms := []interface{}{v}
+		if s, ok := v.(Set); ok {
+			atoms = s
+		}
+
+		for _, atom := range atoms {
+			key, err := indexKey(atom)
+			if err != nil {
+				continue
+			}
+
+			fn(index, key)
+		}
+	}
+}
+
+// indexKey returns the key of an atom in a cacheTable index.  UUIDs and
+// strings have the same key, so that references can be looked up using
+// either type.
+func indexKey(atom interface{}) (string, error) {
+	switch a := atom.(type) {
+	case UUID:
+		atom = string(a)
+	case NamedUUID:
+		atom = string(a)
+	}
+
+	b, err := json.Marshal(atom)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
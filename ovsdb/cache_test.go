@@ -0,0 +1,451 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	cacheBridge0 = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b800"
+	cacheBridge1 = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b801"
+	cacheBridge2 = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b802"
+	cachePort0   = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b810"
+	cachePort1   = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b811"
+	cachePort2   = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b812"
+)
+
+const cacheSchema = `{
+	"name": "Open_vSwitch",
+	"version": "1.0.0",
+	"tables": {
+		"Bridge": {
+			"columns": {
+				"name": {"type": "string"},
+				"ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+				"fail_mode": {"type": {"key": "string", "min": 0, "max": 1}},
+				"external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+				"priority": {"type": "integer"}
+			}
+		}
+	}
+}`
+
+func TestClientMonitorCache(t *testing.T) {
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, testCacheServer(t, idC, func() string {
+		return `{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"initial":{"name":"br0","ports":["set",[["uuid","` + cachePort0 + `"],["uuid","` + cachePort1 + `"]]],"fail_mode":["set",[]],"external_ids":["map",[["foo","bar"],["baz","qux"]]]}},` +
+			`"` + cacheBridge1 + `":{"initial":{"name":"br1","ports":["uuid","` + cachePort2 + `"],"fail_mode":"secure","external_ids":["map",[]]}}` +
+			`}}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+
+	br0 := ovsdb.Row{
+		"name":         "br0",
+		"ports":        ovsdb.Set{ovsdb.UUID(cachePort0), ovsdb.UUID(cachePort1)},
+		"fail_mode":    ovsdb.Set{},
+		"external_ids": ovsdb.Map{"foo": "bar", "baz": "qux"},
+	}
+	br1 := ovsdb.Row{
+		"name":         "br1",
+		"ports":        ovsdb.Set{ovsdb.UUID(cachePort2)},
+		"fail_mode":    ovsdb.Set{"secure"},
+		"external_ids": ovsdb.Map{},
+	}
+
+	row, ok := cache.Row("Bridge", cacheBridge0)
+	if !ok {
+		t.Fatal("expected bridge to be cached")
+	}
+
+	if diff := cmp.Diff(br0, row); diff != "" {
+		t.Fatalf("unexpected cached row (-want +got):\n%s", diff)
+	}
+
+	want := map[string]ovsdb.Row{
+		cacheBridge0: br0,
+		cacheBridge1: br1,
+	}
+
+	if diff := cmp.Diff(want, cache.Rows("Bridge")); diff != "" {
+		t.Fatalf("unexpected cached rows (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]int{"Bridge": 2}, cache.RowCounts()); diff != "" {
+		t.Fatalf("unexpected row counts (-want +got):\n%s", diff)
+	}
+
+	testLookup(t, cache, "name", "br1", map[string]ovsdb.Row{cacheBridge1: br1})
+	testLookup(t, cache, "ports", ovsdb.UUID(cachePort1), map[string]ovsdb.Row{cacheBridge0: br0})
+	testLookup(t, cache, "ports", cachePort2, map[string]ovsdb.Row{cacheBridge1: br1})
+	testLookup(t, cache, "name", "br2", map[string]ovsdb.Row{})
+
+	id := <-idC
+
+	// Add and remove ports, change fail_mode, and update the map.  Delete
+	// one bridge and insert another.
+	notifC <- &jsonrpc.Response{
+		Method: "update2",
+		Params: []byte(`["` + id + `",{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"modify":{"ports":["set",[["uuid","` + cachePort1 + `"],["uuid","` + cachePort2 + `"]]],"fail_mode":"standalone","external_ids":["map",[["foo","bar"],["baz","quux"],["new","value"]]]}},` +
+			`"` + cacheBridge1 + `":{"delete":null},` +
+			`"` + cacheBridge2 + `":{"insert":{"name":"br2"}}` +
+			`}}]`),
+	}
+
+	br0 = ovsdb.Row{
+		"name":         "br0",
+		"ports":        ovsdb.Set{ovsdb.UUID(cachePort0), ovsdb.UUID(cachePort2)},
+		"fail_mode":    ovsdb.Set{"standalone"},
+		"external_ids": ovsdb.Map{"baz": "quux", "new": "value"},
+	}
+	br2 := ovsdb.Row{"name": "br2"}
+
+	waitCache(t, cache, map[string]ovsdb.Row{
+		cacheBridge0: br0,
+		cacheBridge2: br2,
+	})
+
+	testLookup(t, cache, "name", "br1", map[string]ovsdb.Row{})
+	testLookup(t, cache, "ports", cachePort1, map[string]ovsdb.Row{})
+	testLookup(t, cache, "ports", cachePort2, map[string]ovsdb.Row{cacheBridge0: br0})
+	testLookup(t, cache, "name", "br2", map[string]ovsdb.Row{cacheBridge2: br2})
+
+	if err := cache.Cancel(context.Background()); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	// The contents remain available after canceling.
+	if _, ok := cache.Row("Bridge", cacheBridge2); !ok {
+		t.Fatal("expected bridge to remain cached")
+	}
+}
+
+func TestClientMonitorCacheReconnect(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		conns int
+	)
+
+	d := newTestDialer(t, testCacheServer(t, nil, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The first bridge is deleted while the client is disconnected.
+		conns++
+		if conns == 1 {
+			return `{"Bridge":{"` + cacheBridge0 + `":{"initial":{"name":"br0"}}}}`
+		}
+
+		return `{"Bridge":{"` + cacheBridge1 + `":{"initial":{"name":"br1"}}}}`
+	}))
+	defer d.done()
+
+	c := d.client(ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond))
+	defer c.Close()
+
+	cache := testCache(t, c)
+
+	waitCache(t, cache, map[string]ovsdb.Row{
+		cacheBridge0: {"name": "br0"},
+	})
+
+	d.drop()
+
+	waitCache(t, cache, map[string]ovsdb.Row{
+		cacheBridge1: {"name": "br1"},
+	})
+
+	testLookup(t, cache, "name", "br0", map[string]ovsdb.Row{})
+}
+
+func TestClientMonitorCacheSnapshot(t *testing.T) {
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, testCacheServer(t, idC, func() string {
+		return `[false,"txn1",{"Bridge":{"` + cacheBridge0 + `":{"initial":{"name":"br0","ports":["uuid","` + cachePort0 + `"]}}}}]`
+	}))
+	defer done()
+
+	cache := testCacheSince(t, c, nil)
+
+	id := <-idC
+	notifC <- &jsonrpc.Response{
+		Method: "update3",
+		Params: []byte(`["` + id + `","txn2",{"Bridge":{"` + cacheBridge1 + `":{"insert":{"name":"br1"}}}}]`),
+	}
+
+	br0 := ovsdb.Row{"name": "br0", "ports": ovsdb.Set{ovsdb.UUID(cachePort0)}}
+	waitCache(t, cache, map[string]ovsdb.Row{
+		cacheBridge0: br0,
+		cacheBridge1: {"name": "br1"},
+	})
+
+	// The snapshot is stored as JSON, as by a process which is restarting.
+	var snap ovsdb.CacheSnapshot
+	if err := json.Unmarshal(mustMarshalJSON(t, cache.Snapshot()), &snap); err != nil {
+		t.Fatalf("failed to unmarshal snapshot: %v", err)
+	}
+
+	if diff := cmp.Diff("txn2", snap.TransactionID); diff != "" {
+		t.Fatalf("unexpected snapshot transaction ID (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		name    string
+		initial string
+		txnID   string
+		want    map[string]ovsdb.Row
+	}{
+		{
+			name:    "found",
+			initial: `[true,"txn3",{"Bridge":{"` + cacheBridge1 + `":{"modify":{"name":"br1-new"}}}}]`,
+			txnID:   "txn3",
+			want: map[string]ovsdb.Row{
+				cacheBridge0: br0,
+				cacheBridge1: {"name": "br1-new"},
+			},
+		},
+		{
+			name:    "not found",
+			initial: `[false,"txn4",{"Bridge":{"` + cacheBridge2 + `":{"initial":{"name":"br2"}}}}]`,
+			txnID:   "txn4",
+			want: map[string]ovsdb.Row{
+				cacheBridge2: {"name": "br2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+				if req.Method == "monitor_cond_since" {
+					// Monitoring resumes from the snapshot's transaction.
+					if diff := cmp.Diff("txn2", req.Params.([]interface{})[3]); diff != "" {
+						panicf("unexpected last transaction ID (-want +got):\n%s", diff)
+					}
+				}
+
+				return testCacheServer(t, nil, func() string {
+					return tt.initial
+				})(req)
+			})
+			defer done()
+
+			cache := testCacheSince(t, c, &snap)
+
+			if diff := cmp.Diff(tt.want, cache.Rows("Bridge")); diff != "" {
+				t.Fatalf("unexpected cached rows (-want +got):\n%s", diff)
+			}
+
+			// Restored rows are indexed.
+			for uuid, row := range tt.want {
+				testLookup(t, cache, "name", row["name"], map[string]ovsdb.Row{uuid: row})
+			}
+
+			if diff := cmp.Diff(tt.txnID, cache.Snapshot().TransactionID); diff != "" {
+				t.Fatalf("unexpected transaction ID (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientMonitorCacheSinceWrongDatabase(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		panicf("unexpected monitor RPC")
+		return ""
+	}))
+	defer done()
+
+	_, err := c.MonitorCacheSince(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, nil, &ovsdb.CacheSnapshot{Database: "OVN_Northbound"})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestClientMonitorCacheInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests map[string]ovsdb.MonitorCondRequest
+		indexes  map[string][]string
+	}{
+		{
+			name:     "unknown table",
+			requests: map[string]ovsdb.MonitorCondRequest{"Port": {}},
+		},
+		{
+			name:     "table not monitored",
+			requests: map[string]ovsdb.MonitorCondRequest{"Bridge": {}},
+			indexes:  map[string][]string{"Port": {"name"}},
+		},
+		{
+			name:     "unknown column",
+			requests: map[string]ovsdb.MonitorCondRequest{"Bridge": {}},
+			indexes:  map[string][]string{"Bridge": {"foo"}},
+		},
+		{
+			name:     "map column",
+			requests: map[string]ovsdb.MonitorCondRequest{"Bridge": {}},
+			indexes:  map[string][]string{"Bridge": {"external_ids"}},
+		},
+		{
+			name: "select",
+			requests: map[string]ovsdb.MonitorCondRequest{"Bridge": {
+				Select: &ovsdb.MonitorSelect{Initial: true, Insert: true, Modify: true},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+				panicf("unexpected monitor RPC")
+				return ""
+			}))
+			defer done()
+
+			if _, err := c.MonitorCache(context.Background(), "Open_vSwitch", tt.requests, tt.indexes); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestCacheLookupNotIndexed(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		return `{}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+	defer cache.Cancel(context.Background())
+
+	if _, err := cache.Lookup("Bridge", "fail_mode", "secure"); err == nil {
+		t.Fatal("expected an error for unindexed column, but none occurred")
+	}
+
+	if _, err := cache.Lookup("Port", "name", "p0"); err == nil {
+		t.Fatal("expected an error for uncached table, but none occurred")
+	}
+}
+
+// testCacheServer returns a jsonrpc.TestFunc which serves cacheSchema and
+// the monitor result returned by initial.  If idC is not nil, it
+// receives the ID of each monitor.
+func testCacheServer(t *testing.T, idC chan<- string, initial func() string) jsonrpc.TestFunc {
+	return func(req jsonrpc.Request) jsonrpc.Response {
+		var res []byte
+		switch req.Method {
+		case "get_schema":
+			res = []byte(cacheSchema)
+		case "monitor_cond", "monitor_cond_since":
+			if idC != nil {
+				idC <- req.Params.([]interface{})[1].(string)
+			}
+
+			res = []byte(initial())
+		case "monitor_cancel":
+			res = mustMarshalJSON(t, struct{}{})
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: json.RawMessage(res),
+		}
+	}
+}
+
+// testCache creates a Cache of the Bridge table with indexes on its name
+// and ports columns.
+func testCache(t *testing.T, c *ovsdb.Client) *ovsdb.Cache {
+	t.Helper()
+
+	cache, err := c.MonitorCache(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, map[string][]string{
+		"Bridge": {"name", "ports"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	return cache
+}
+
+// testCacheSince is like testCache, but uses Client.MonitorCacheSince to
+// restore the Cache from snap.
+func testCacheSince(t *testing.T, c *ovsdb.Client, snap *ovsdb.CacheSnapshot) *ovsdb.Cache {
+	t.Helper()
+
+	cache, err := c.MonitorCacheSince(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, map[string][]string{
+		"Bridge": {"name", "ports"},
+	}, snap)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	return cache
+}
+
+// testLookup verifies the result of looking up value in a Bridge column.
+func testLookup(t *testing.T, cache *ovsdb.Cache, column string, value interface{}, want map[string]ovsdb.Row) {
+	t.Helper()
+
+	rows, err := cache.Lookup("Bridge", column, value)
+	if err != nil {
+		t.Fatalf("failed to look up %q: %v", column, err)
+	}
+
+	if diff := cmp.Diff(want, rows); diff != "" {
+		t.Fatalf("unexpected rows for %q = %v (-want +got):\n%s", column, value, diff)
+	}
+}
+
+// waitCache waits for the Bridge table of a Cache to contain want.
+func waitCache(t *testing.T, cache *ovsdb.Cache, want map[string]ovsdb.Row) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		got := cache.Rows("Bridge")
+		if cmp.Equal(want, got) {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("unexpected cached rows (-want +got):\n%s", cmp.Diff(want, got))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
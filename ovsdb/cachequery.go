@@ -0,0 +1,336 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A CacheQuery selects the rows of a table in a Cache which match a list of
+// conditions.  CacheQueries are created using Cache.List.
+//
+// Conditions are evaluated in the same way as by an OVSDB server.  If a
+// condition compares an indexed column for equality or inclusion, the
+// Cache's index is used to find matching rows instead of examining every row
+// in the table.
+type CacheQuery struct {
+	c     *Cache
+	table string
+	conds []Cond
+}
+
+// List returns a CacheQuery which selects rows from table.  With no
+// conditions, the query selects every row in the table.
+func (c *Cache) List(table string) *CacheQuery {
+	return &CacheQuery{
+		c:     c,
+		table: table,
+	}
+}
+
+// Where adds conditions to the query.  Only rows which match all conditions
+// are selected.  The "_uuid" column may be used to match row UUIDs.
+func (q *CacheQuery) Where(conds ...Cond) *CacheQuery {
+	q.conds = append(q.conds, conds...)
+	return q
+}
+
+// Rows returns the rows selected by the query, keyed by row UUID.  Rows
+// returns an error if the table is not cached, or if a condition is invalid.
+func (q *CacheQuery) Rows() (map[string]Row, error) {
+	q.c.mu.RLock()
+	defer q.c.mu.RUnlock()
+
+	t, ok := q.c.tables[q.table]
+	if !ok {
+		return nil, fmt.Errorf("table %q is not cached", q.table)
+	}
+
+	conds := make([]cacheCond, 0, len(q.conds))
+	for _, c := range q.conds {
+		cc, err := newCacheCond(t.schema, c)
+		if err != nil {
+			return nil, err
+		}
+
+		conds = append(conds, cc)
+	}
+
+	rows := make(map[string]Row)
+	for uuid, row := range t.candidates(conds) {
+		match, err := matchRow(conds, uuid, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if match {
+			rows[uuid] = row
+		}
+	}
+
+	return rows, nil
+}
+
+// Count returns the number of rows selected by the query.
+func (q *CacheQuery) Count() (int, error) {
+	rows, err := q.Rows()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// candidates returns the rows which may match conds, using an index if
+// possible.
+func (t *cacheTable) candidates(conds []cacheCond) map[string]Row {
+	for _, c := range conds {
+		if c.Function != FunctionEqual && c.Function != FunctionIncludes {
+			continue
+		}
+
+		index, ok := t.indexes[c.Column]
+		if !ok || len(c.keys) == 0 {
+			continue
+		}
+
+		// Every matching row contains the first element of the value.
+		rows := make(map[string]Row, len(index[c.keys[0]]))
+		for uuid := range index[c.keys[0]] {
+			rows[uuid] = t.rows[uuid]
+		}
+
+		return rows
+	}
+
+	return t.rows
+}
+
+// A cacheCond is a Cond whose value has been converted to the column's type.
+type cacheCond struct {
+	Cond
+
+	// The index keys of the elements of the value.
+	keys []string
+}
+
+// newCacheCond converts the value of c according to the column's type in ts.
+// The value is interpreted in the same way as in a transaction.
+func newCacheCond(ts TableSchema, c Cond) (cacheCond, error) {
+	switch c.Function {
+	case FunctionEqual, FunctionNotEqual,
+		FunctionLessThan, FunctionLessThanOrEqual,
+		FunctionGreaterThan, FunctionGreaterThanOrEqual,
+		FunctionIncludes, FunctionExcludes:
+	default:
+		return cacheCond{}, fmt.Errorf("invalid condition function %q for column %q", c.Function, c.Column)
+	}
+
+	b, err := json.Marshal(c.Value)
+	if err != nil {
+		return cacheCond{}, err
+	}
+
+	v, err := decodeNumbers(b)
+	if err != nil {
+		return cacheCond{}, err
+	}
+
+	if c.Column == "_uuid" {
+		v, err = decodeAtom(v)
+	} else if _, ok := ts.Columns[c.Column]; ok {
+		v = columnValue(ts, c.Column, v)
+	} else {
+		err = fmt.Errorf("column %q does not exist", c.Column)
+	}
+	if err != nil {
+		return cacheCond{}, err
+	}
+
+	keys, err := elementKeys(v)
+	if err != nil {
+		return cacheCond{}, err
+	}
+
+	c.Value = v
+	return cacheCond{
+		Cond: c,
+		keys: keys,
+	}, nil
+}
+
+// matchRow reports whether the row with the specified UUID matches all
+// conds.
+func matchRow(conds []cacheCond, uuid string, row Row) (bool, error) {
+	for _, c := range conds {
+		v := row[c.Column]
+		if c.Column == "_uuid" {
+			v = UUID(uuid)
+		}
+
+		match, err := c.match(v)
+		if err != nil || !match {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// match reports whether a column value v matches the condition.
+func (c cacheCond) match(v interface{}) (bool, error) {
+	switch c.Function {
+	case FunctionLessThan, FunctionLessThanOrEqual,
+		FunctionGreaterThan, FunctionGreaterThanOrEqual:
+		cmp, ok := compareAtoms(v, c.Value)
+		if !ok {
+			return false, fmt.Errorf("condition function %q requires an integer or real column, but column %q is not", c.Function, c.Column)
+		}
+
+		switch c.Function {
+		case FunctionLessThan:
+			return cmp < 0, nil
+		case FunctionLessThanOrEqual:
+			return cmp <= 0, nil
+		case FunctionGreaterThan:
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+
+	keys, err := elementKeys(v)
+	if err != nil {
+		return false, err
+	}
+
+	have := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		have[k] = struct{}{}
+	}
+
+	var found int
+	for _, k := range c.keys {
+		if _, ok := have[k]; ok {
+			found++
+		}
+	}
+
+	switch c.Function {
+	case FunctionEqual:
+		return found == len(c.keys) && len(have) == len(c.keys), nil
+	case FunctionNotEqual:
+		return found != len(c.keys) || len(have) != len(c.keys), nil
+	case FunctionIncludes:
+		return found == len(c.keys), nil
+	default:
+		return found == 0, nil
+	}
+}
+
+// elementKeys returns the index keys of the elements of a column value: each
+// element of a Set, each key and value pair of a Map, or a single atom.
+func elementKeys(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case Set:
+		keys := make([]string, 0, len(v))
+		for _, e := range v {
+			k, err := indexKey(e)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, k)
+		}
+
+		return keys, nil
+	case Map:
+		keys := make([]string, 0, len(v))
+		for mk, mv := range v {
+			k, err := indexKey(mk)
+			if err != nil {
+				return nil, err
+			}
+
+			kv, err := indexKey(mv)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, k+":"+kv)
+		}
+
+		return keys, nil
+	default:
+		k, err := indexKey(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{k}, nil
+	}
+}
+
+// compareAtoms compares two integer or real atoms, returning -1, 0, or 1 as
+// a is less than, equal to, or greater than b.  It reports false if either
+// atom is not a number.
+func compareAtoms(a, b interface{}) (int, bool) {
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	x, ok := floatAtom(a)
+	if !ok {
+		return 0, false
+	}
+
+	y, ok := floatAtom(b)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// floatAtom converts an integer or real atom to a float64.
+func floatAtom(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
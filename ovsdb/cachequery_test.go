@@ -0,0 +1,201 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCacheList(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		return `{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"initial":{"name":"br0","ports":["set",[["uuid","` + cachePort0 + `"],["uuid","` + cachePort1 + `"]]],"fail_mode":["set",[]],"external_ids":["map",[["foo","bar"]]],"priority":10}},` +
+			`"` + cacheBridge1 + `":{"initial":{"name":"br1","ports":["uuid","` + cachePort2 + `"],"fail_mode":"secure","external_ids":["map",[]],"priority":20}},` +
+			`"` + cacheBridge2 + `":{"initial":{"name":"br2","ports":["set",[]],"fail_mode":["set",[]],"external_ids":["map",[]],"priority":30}}` +
+			`}}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+	defer cache.Cancel(context.Background())
+
+	tests := []struct {
+		name  string
+		conds []ovsdb.Cond
+		want  []string
+	}{
+		{
+			name: "all",
+			want: []string{cacheBridge0, cacheBridge1, cacheBridge2},
+		},
+		{
+			name:  "indexed equal",
+			conds: []ovsdb.Cond{ovsdb.Equal("name", "br1")},
+			want:  []string{cacheBridge1},
+		},
+		{
+			name:  "indexed equal no match",
+			conds: []ovsdb.Cond{ovsdb.Equal("name", "br3")},
+		},
+		{
+			name:  "not equal",
+			conds: []ovsdb.Cond{ovsdb.NotEqual("name", "br0")},
+			want:  []string{cacheBridge1, cacheBridge2},
+		},
+		{
+			name:  "indexed includes",
+			conds: []ovsdb.Cond{ovsdb.Includes("ports", ovsdb.UUID(cachePort1))},
+			want:  []string{cacheBridge0},
+		},
+		{
+			name: "indexed includes set",
+			conds: []ovsdb.Cond{ovsdb.Includes("ports", ovsdb.Set{
+				ovsdb.UUID(cachePort1),
+				ovsdb.UUID(cachePort0),
+			})},
+			want: []string{cacheBridge0},
+		},
+		{
+			name:  "excludes",
+			conds: []ovsdb.Cond{ovsdb.Excludes("ports", ovsdb.UUID(cachePort2))},
+			want:  []string{cacheBridge0, cacheBridge2},
+		},
+		{
+			name:  "equal empty set",
+			conds: []ovsdb.Cond{ovsdb.Equal("ports", ovsdb.Set{})},
+			want:  []string{cacheBridge2},
+		},
+		{
+			name:  "optional column",
+			conds: []ovsdb.Cond{ovsdb.Equal("fail_mode", "secure")},
+			want:  []string{cacheBridge1},
+		},
+		{
+			name:  "map includes",
+			conds: []ovsdb.Cond{ovsdb.Includes("external_ids", ovsdb.Map{"foo": "bar"})},
+			want:  []string{cacheBridge0},
+		},
+		{
+			name:  "greater than",
+			conds: []ovsdb.Cond{ovsdb.GreaterThan("priority", 10)},
+			want:  []string{cacheBridge1, cacheBridge2},
+		},
+		{
+			name:  "less than or equal",
+			conds: []ovsdb.Cond{ovsdb.LessThanOrEqual("priority", 20)},
+			want:  []string{cacheBridge0, cacheBridge1},
+		},
+		{
+			name:  "UUID",
+			conds: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(cacheBridge2))},
+			want:  []string{cacheBridge2},
+		},
+		{
+			name: "multiple",
+			conds: []ovsdb.Cond{
+				ovsdb.Equal("name", "br0"),
+				ovsdb.GreaterThan("priority", 15),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := cache.List("Bridge").Where(tt.conds...)
+
+			rows, err := q.Rows()
+			if err != nil {
+				t.Fatalf("failed to query cache: %v", err)
+			}
+
+			uuids := make([]string, 0, len(rows))
+			for uuid, row := range rows {
+				// Selected rows are the cached rows.
+				want, _ := cache.Row("Bridge", uuid)
+				if diff := cmp.Diff(want, row); diff != "" {
+					t.Fatalf("unexpected row (-want +got):\n%s", diff)
+				}
+
+				uuids = append(uuids, uuid)
+			}
+			sort.Strings(uuids)
+
+			if diff := cmp.Diff(tt.want, uuids, cmpopts.EquateEmpty()); diff != "" {
+				t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+			}
+
+			n, err := q.Count()
+			if err != nil {
+				t.Fatalf("failed to count rows: %v", err)
+			}
+
+			if diff := cmp.Diff(len(tt.want), n); diff != "" {
+				t.Fatalf("unexpected count (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCacheListInvalid(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		return `{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"initial":{"name":"br0","priority":10}}` +
+			`}}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+	defer cache.Cancel(context.Background())
+
+	tests := []struct {
+		name  string
+		table string
+		conds []ovsdb.Cond
+	}{
+		{
+			name:  "table not cached",
+			table: "Port",
+		},
+		{
+			name:  "unknown column",
+			table: "Bridge",
+			conds: []ovsdb.Cond{ovsdb.Equal("foo", "bar")},
+		},
+		{
+			name:  "unknown function",
+			table: "Bridge",
+			conds: []ovsdb.Cond{{Column: "name", Function: "~=", Value: "br0"}},
+		},
+		{
+			name:  "ordering on string column",
+			table: "Bridge",
+			conds: []ovsdb.Cond{ovsdb.LessThan("name", "br1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cache.List(tt.table).Where(tt.conds...).Rows(); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ChunkOps splits ops into consecutive chunks whose JSON encodings are each at
+// most maxBytes in length, so that each chunk may be performed in a separate
+// transaction.  The order of ops is preserved.
+//
+// An Insert which specifies a UUIDName is always placed in the same chunk as
+// every later operation which refers to it using a NamedUUID.  An error is
+// returned if an operation refers to a NamedUUID which is not defined by an
+// earlier Insert, or if an operation, or a group of operations which must be
+// kept together, is longer than maxBytes.
+func ChunkOps(ops []TransactOp, maxBytes int) ([][]TransactOp, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("maximum chunk size must be greater than zero")
+	}
+
+	// Determine the encoded size of each operation, and the last operation
+	// which must share a chunk with each operation.
+	sizes := make([]int, len(ops))
+	ends := make([]int, len(ops))
+	names := make(map[NamedUUID]int)
+
+	for i, op := range ops {
+		b, err := json.Marshal(op)
+		if err != nil {
+			return nil, err
+		}
+
+		// Account for the comma which separates operations.
+		sizes[i] = len(b) + 1
+		ends[i] = i
+
+		refs, err := namedUUIDs(b)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range refs {
+			j, ok := names[n]
+			if !ok {
+				return nil, fmt.Errorf("operation %d refers to named UUID %q, which is not defined by an earlier insert", i, string(n))
+			}
+
+			ends[j] = i
+		}
+
+		if ins, ok := op.(Insert); ok && ins.UUIDName != "" {
+			names[NamedUUID(ins.UUIDName)] = i
+		}
+	}
+
+	var (
+		out   [][]TransactOp
+		chunk []TransactOp
+		size  int
+	)
+
+	// Split ops into the smallest groups which cannot be separated, and pack
+	// as many groups as possible into each chunk.
+	for start := 0; start < len(ops); {
+		end, n := start, 0
+		for i := start; i <= end; i++ {
+			if ends[i] > end {
+				end = ends[i]
+			}
+
+			n += sizes[i]
+		}
+
+		if n > maxBytes {
+			if start == end {
+				return nil, fmt.Errorf("operation %d is %d bytes, which exceeds the maximum chunk size of %d bytes", start, n, maxBytes)
+			}
+
+			return nil, fmt.Errorf("operations %d through %d are %d bytes, which exceeds the maximum chunk size of %d bytes", start, end, n, maxBytes)
+		}
+
+		if size+n > maxBytes {
+			out = append(out, chunk)
+			chunk, size = nil, 0
+		}
+
+		chunk = append(chunk, ops[start:end+1]...)
+		size += n
+		start = end + 1
+	}
+
+	if len(chunk) > 0 {
+		out = append(out, chunk)
+	}
+
+	return out, nil
+}
+
+// TransactChunked performs ops on the specified database using ChunkOps to
+// split them into chunks of at most maxBytes, each of which is performed in
+// its own transaction.  This enables bulk writes which would otherwise exceed
+// an OVSDB server's maximum message size.
+//
+// Each transaction is atomic, but ops as a whole are not: if a transaction
+// fails, TransactChunked returns its error along with the results of the
+// transactions which were already committed.
+func (c *Client) TransactChunked(ctx context.Context, db string, ops []TransactOp, maxBytes int) ([]OperationResult, error) {
+	chunks, err := ChunkOps(ops, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]OperationResult, 0, len(ops))
+	for _, chunk := range chunks {
+		res, err := c.Transact(ctx, db, chunk)
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, res...)
+	}
+
+	return out, nil
+}
+
+// namedUUIDs returns the NamedUUIDs referred to by the JSON encoding of an
+// operation.
+func namedUUIDs(b []byte) ([]NamedUUID, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	var out []NamedUUID
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			if len(v) == 2 && v[0] == "named-uuid" {
+				if s, ok := v[1].(string); ok {
+					out = append(out, NamedUUID(s))
+					return
+				}
+			}
+
+			for _, e := range v {
+				walk(e)
+			}
+		case map[string]interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+
+	walk(v)
+	return out, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChunkOps(t *testing.T) {
+	// Port inserts which are each referenced by the following bridge insert.
+	port := func(i int) ovsdb.TransactOp {
+		return ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: fmt.Sprintf("port%d", i),
+			Row:      ovsdb.Row{"name": fmt.Sprintf("port%d", i)},
+		}
+	}
+
+	bridge := func(ports ...int) ovsdb.TransactOp {
+		refs := make(ovsdb.Set, 0, len(ports))
+		for _, p := range ports {
+			refs = append(refs, ovsdb.NamedUUID(fmt.Sprintf("port%d", p)))
+		}
+
+		return ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"ports": refs},
+		}
+	}
+
+	sel := ovsdb.Select{Table: "Bridge"}
+
+	tests := []struct {
+		name   string
+		ops    []ovsdb.TransactOp
+		ops0   int
+		chunks [][]ovsdb.TransactOp
+		ok     bool
+	}{
+		{
+			name: "empty",
+			ok:   true,
+		},
+		{
+			name:   "one chunk",
+			ops:    []ovsdb.TransactOp{sel, sel, sel},
+			ops0:   3,
+			chunks: [][]ovsdb.TransactOp{{sel, sel, sel}},
+			ok:     true,
+		},
+		{
+			name:   "independent",
+			ops:    []ovsdb.TransactOp{sel, sel, sel},
+			ops0:   2,
+			chunks: [][]ovsdb.TransactOp{{sel, sel}, {sel}},
+			ok:     true,
+		},
+		{
+			name: "references",
+			ops: []ovsdb.TransactOp{
+				port(1), port(2), bridge(1, 2),
+				port(0), bridge(0),
+				sel,
+			},
+			ops0: 3,
+			chunks: [][]ovsdb.TransactOp{
+				{port(1), port(2), bridge(1, 2)},
+				{port(0), bridge(0), sel},
+			},
+			ok: true,
+		},
+		{
+			name: "overlapping references",
+			ops: []ovsdb.TransactOp{
+				port(0), port(1), bridge(0), sel, bridge(1), sel,
+			},
+			ops0: 5,
+			chunks: [][]ovsdb.TransactOp{
+				{port(0), port(1), bridge(0), sel, bridge(1)},
+				{sel},
+			},
+			ok: true,
+		},
+		{
+			name: "undefined reference",
+			ops:  []ovsdb.TransactOp{bridge(0), port(0)},
+			ops0: 2,
+		},
+		{
+			name: "group too large",
+			ops:  []ovsdb.TransactOp{port(0), port(1), bridge(0, 1)},
+			ops0: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Allow room for the first ops0 operations in each chunk.
+			max := 1
+			if tt.ops0 > 0 {
+				max = chunkSize(t, tt.ops[:tt.ops0])
+			}
+
+			chunks, err := ovsdb.ChunkOps(tt.ops, max)
+			if tt.ok && err != nil {
+				t.Fatalf("failed to chunk operations: %v", err)
+			}
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("expected an error, but none occurred")
+				}
+
+				return
+			}
+
+			if diff := cmp.Diff(tt.chunks, chunks); diff != "" {
+				t.Fatalf("unexpected chunks (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChunkOpsInvalidSize(t *testing.T) {
+	if _, err := ovsdb.ChunkOps(nil, 0); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestClientTransactChunked(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	// Ports are not root rows, so each must be inserted in the same
+	// transaction as its bridge or it would be garbage collected.
+	const n = 32
+	var ops []ovsdb.TransactOp
+	for i := 0; i < n; i++ {
+		port := fmt.Sprintf("port%d", i)
+		ops = append(ops,
+			ovsdb.Insert{
+				Table:    "Port",
+				UUIDName: port,
+				Row:      ovsdb.Row{"name": port},
+			},
+			ovsdb.Insert{
+				Table: "Bridge",
+				Row: ovsdb.Row{
+					"name":  fmt.Sprintf("br%d", i),
+					"ports": ovsdb.Set{ovsdb.NamedUUID(port)},
+				},
+			},
+		)
+	}
+
+	ctx := context.Background()
+
+	// Allow only a few bridges per transaction.
+	res, err := c.TransactChunked(ctx, "Open_vSwitch", ops, chunkSize(t, ops[:6]))
+	if err != nil {
+		t.Fatalf("failed to perform chunked transactions: %v", err)
+	}
+	if diff := cmp.Diff(len(ops), len(res)); diff != "" {
+		t.Fatalf("unexpected number of results (-want +got):\n%s", diff)
+	}
+
+	res, err = c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+		ovsdb.Select{Table: "Port"},
+	})
+	if err != nil {
+		t.Fatalf("failed to select rows: %v", err)
+	}
+
+	for _, r := range res {
+		if diff := cmp.Diff(n, len(r.Rows)); diff != "" {
+			t.Fatalf("unexpected number of rows (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestClientTransactChunkedError(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	ops := []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+		ovsdb.Insert{
+			Table: "foo",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+	}
+
+	// Each operation is performed in its own transaction, so the first
+	// succeeds even though the second fails.
+	res, err := c.TransactChunked(context.Background(), "Open_vSwitch", ops, chunkSize(t, ops[:1]))
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+	if diff := cmp.Diff(1, len(res)); diff != "" {
+		t.Fatalf("unexpected number of results (-want +got):\n%s", diff)
+	}
+}
+
+// chunkSize returns the maximum chunk size in which ops will fit.
+func chunkSize(t *testing.T, ops []ovsdb.TransactOp) int {
+	t.Helper()
+
+	var n int
+	for _, op := range ops {
+		b, err := json.Marshal(op)
+		if err != nil {
+			t.Fatalf("failed to marshal operation: %v", err)
+		}
+
+		n += len(b) + 1
+	}
+
+	return n
+}
@@ -16,8 +16,10 @@ package ovsdb
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net"
 	"strconv"
@@ -32,10 +34,18 @@ import (
 // A Client is an OVSDB client.  Clients can be customized by using OptionFuncs
 // in the Dial and New functions.
 //
+// A Client is safe for concurrent use.  Concurrent RPCs are pipelined on the
+// Client's connection, and their responses are matched to requests by ID, so
+// monitors and transactions can share a single connection.
+//
 // All methods on the Client that accept a context.Context can use the context
 // to cancel or time out requests.  Some methods may use the context for advanced
 // use cases.  If this is the case, the documentation for the method will explain
 // these use cases.
+//
+// The context is also used to interrupt sending a request to a server which
+// has stopped reading from its connection.  An interrupted send closes the
+// connection, as a partial request may have been written.
 type Client struct {
 	// NB: must 64-bit align these atomic integers, so they should appear first
 	// in the Client structure.
@@ -44,22 +54,117 @@ type Client struct {
 	// Incremented atomically when sending RPCs.
 	rpcID int64
 
-	// Statistics about the echo loop.
-	echoOK, echoFail int64
+	// Statistics about the echo loop and replies to server echo requests.
+	echoOK, echoFail           int64
+	echoReplyOK, echoReplyFail int64
+
+	// Statistics about RPCs and reconnections.
+	rpcOK, rpcFail int64
+	reconnects     int64
+
+	// Statistics about updates affected by monitor backpressure.
+	monDropped, monCoalesced int64
+
+	// The time at which a message was last received, in Unix nanoseconds.
+	lastRecv int64
+
+	// The times at which echo RPCs last succeeded and failed, in Unix
+	// nanoseconds, and the round trip time of the last successful echo.
+	lastEchoOK, lastEchoFail int64
+	echoRTT                  int64
 
 	// All other types should occur after atomic integers.
 
-	// The RPC connection, and its logger.
-	c  *jsonrpc.Conn
-	ll *log.Logger
+	// The RPC connection, and its logger.  connMu protects c and closed, as
+	// c may be replaced when reconnecting.
+	connMu sync.RWMutex
+	c      *jsonrpc.Conn
+	closed bool
+	ll     *log.Logger
+
+	// If set, the Codec used in place of package encoding/json.
+	codec Codec
 
-	// Callbacks for RPC responses.
+	// Callbacks for RPC responses.  If draining is set by Shutdown, no new
+	// callbacks may be added, and drained is closed when none remain.
 	cbMu      sync.RWMutex
 	callbacks map[string]callback
+	draining  bool
+	drained   chan struct{}
+
+	// Monitors which receive update notifications, keyed by monitor ID.
+	monMu    sync.RWMutex
+	monitors map[string]monitorHandler
 
 	// Interval at which echo RPCs should occur in the background.
 	echoInterval time.Duration
 
+	// If set, the interval of the inactivity probe.
+	probeInterval time.Duration
+
+	// If set, the read and write timeouts for the connection.
+	readTimeout, writeTimeout time.Duration
+
+	// Optional TLS configuration used when dialing.
+	tlsConfig *tls.Config
+
+	// If set, the policy used to retry idempotent RPCs.
+	retry *RetryPolicy
+
+	// Schemas used to validate transactions, keyed by database name.
+	// schemaMu guards schemas, which are replaced when monitors are
+	// re-established after a database changes.
+	schemaMu sync.RWMutex
+	schemas  map[string]*Schema
+
+	// Whether the server should notify the Client of database changes, which
+	// must be requested again after reconnecting.
+	changeMu    sync.Mutex
+	changeAware bool
+
+	// If set, monitors canceled by the server are re-established, and
+	// onRemonitor is called after each attempt.
+	remonitor   bool
+	onRemonitor func(db string, schema *Schema, err error)
+
+	// Functions which report on the Client's operation.
+	hooks  ClientHooks
+	tracer Tracer
+	rpcLog *rpcLogger
+
+	// If set, the maximum number of updates queued for each monitor, and
+	// the policy applied when the limit is reached.
+	queueLimit  int
+	queuePolicy BackpressurePolicy
+
+	// If set, the database for which the server must be the cluster leader.
+	leaderDB string
+
+	// Reconnection configuration and state.  If the Client was created by
+	// DialRemote, remotes dials its remotes.
+	dial                   func() (Transport, error)
+	remotes                *remoteDialer
+	preferRemote           bool
+	reconnect              bool
+	backoffMin, backoffMax time.Duration
+
+	stateMu sync.Mutex
+	state   ConnState
+	onState func(s ConnState)
+
+	// Optional callbacks for the loss and restoration of the connection.
+	onDisconnect func()
+	onReconnect  func(ctx context.Context)
+
+	// Locks which are requested, and must be requested again after
+	// reconnecting, along with whether they are held.  Changes in ownership
+	// are queued for delivery on lockEvents.
+	lockMu     sync.Mutex
+	locks      map[string]bool
+	lockQueue  []LockEvent
+	lockWake   chan struct{}
+	lockEvents chan LockEvent
+
 	// Track and clean up background goroutines.
 	cancel func()
 	wg     *sync.WaitGroup
@@ -68,7 +173,9 @@ type Client struct {
 // An OptionFunc is a function which can configure a Client.
 type OptionFunc func(c *Client) error
 
-// Debug enables debug logging for a Client.
+// Debug enables debug logging for a Client, which logs the raw messages sent
+// and received on its connection.  To log structured records of each RPC
+// without exposing sensitive column values, use RPCLogging.
 func Debug(ll *log.Logger) OptionFunc {
 	return func(c *Client) error {
 		c.ll = ll
@@ -81,8 +188,8 @@ func Debug(ll *log.Logger) OptionFunc {
 // OVSDB server may also send its own echo RPCs to the Client, and the Client
 // will always reply to those on behalf of the user.
 //
-// If this option is not used, the Client will only send echo RPCs when the
-// server sends an echo RPC to it.
+// If this option is not used, the Client will not send echo RPCs of its own,
+// but will still reply to any echo RPCs sent by the server.
 //
 // Specify a duration of 0 to disable sending background echo RPCs at a
 // fixed interval.
@@ -93,18 +200,69 @@ func EchoInterval(d time.Duration) OptionFunc {
 	}
 }
 
+// TLSConfig specifies a TLS configuration which is used to secure the
+// connection to an OVSDB server, such as one listening on an "ssl:" remote.
+// Client certificates may be specified using cfg.Certificates.
+//
+// TLSConfig only applies to Dial, DialRemote, Listen, and ListenRemote.  When
+// listening, cfg.Certificates must contain the Listener's certificate.  New
+// assumes that its connection has already been secured if needed, such as by
+// using tls.Dial.
+func TLSConfig(cfg *tls.Config) OptionFunc {
+	return func(c *Client) error {
+		c.tlsConfig = cfg
+		return nil
+	}
+}
+
 // Dial dials a connection to an OVSDB server and returns a Client.
 func Dial(network, addr string, options ...OptionFunc) (*Client, error) {
-	conn, err := net.Dial(network, addr)
+	// Options determine how the connection should be dialed.
+	client, err := newClient(options)
 	if err != nil {
 		return nil, err
 	}
 
-	return New(conn, options...)
+	return client.dialStart(network, addr)
+}
+
+// dialStart dials a connection for a configured Client and starts it.
+func (c *Client) dialStart(network, addr string) (*Client, error) {
+	dial := func() (Transport, error) {
+		if c.tlsConfig != nil {
+			return tls.Dial(network, addr, c.tlsConfig)
+		}
+
+		return net.Dial(network, addr)
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+
+	// Dial the same way again if the Client must reconnect, unless the
+	// caller has specified otherwise.
+	if c.dial == nil {
+		c.dial = dial
+	}
+
+	if err := c.start(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return c, nil
 }
 
 // New wraps an existing connection to an OVSDB server and returns a Client.
+// To use a connection which is not a net.Conn, use NewTransport.
 func New(conn net.Conn, options ...OptionFunc) (*Client, error) {
+	return NewTransport(conn, options...)
+}
+
+// newClient creates a Client configured by options.
+func newClient(options []OptionFunc) (*Client, error) {
 	client := &Client{}
 	for _, o := range options {
 		if err := o(client); err != nil {
@@ -112,46 +270,94 @@ func New(conn net.Conn, options ...OptionFunc) (*Client, error) {
 		}
 	}
 
+	return client, nil
+}
+
+// start begins serving RPCs for a Client using conn.
+func (c *Client) start(conn Transport) error {
+	if c.reconnect && c.dial == nil {
+		return errors.New("reconnecting requires Dial, or the DialFunc or DialTransport option")
+	}
+
 	// Set up the JSON-RPC connection.
-	client.c = jsonrpc.NewConn(conn, client.ll)
+	c.c = c.newConn(conn)
+	c.state = StateConnected
+	c.received()
 
-	// Set up callbacks.
-	client.callbacks = make(map[string]callback)
+	// Set up callbacks, monitors, and locks.
+	c.callbacks = make(map[string]callback)
+	c.monitors = make(map[string]monitorHandler)
+	c.locks = make(map[string]bool)
+	c.lockWake = make(chan struct{}, 1)
+	c.lockEvents = make(chan LockEvent)
 
 	// Coordinates the sending of echo messages among multiple goroutines.
 	echoC := make(chan struct{})
 
 	// Start up any background routines, and enable canceling them via context.
 	ctx, cancel := context.WithCancel(context.Background())
-	client.cancel = cancel
+	c.cancel = cancel
 
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
+	c.wg = &wg
 
 	// If configured, trigger echo RPCs in the background at a fixed interval.
-	if d := client.echoInterval; d != 0 {
+	if d := c.echoInterval; d != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.echoTicker(ctx, d, echoC)
+		}()
+	}
+
+	// If configured, probe the connection when it is inactive.
+	if c.probeInterval != 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			client.echoTicker(ctx, d, echoC)
+			c.probeLoop(ctx)
 		}()
 	}
 
 	// Send echo RPCs when triggered by channel.
 	go func() {
 		defer wg.Done()
-		client.echoLoop(ctx, echoC)
+		c.echoLoop(ctx, echoC)
 	}()
 
 	// Handle all incoming RPC responses and notifications.
 	go func() {
 		defer wg.Done()
-		client.listen(ctx, echoC)
+		c.listen(ctx)
 	}()
 
-	client.wg = &wg
+	// Deliver changes in lock ownership.
+	go func() {
+		defer wg.Done()
+		c.deliverLocks(ctx)
+	}()
 
-	return client, nil
+	if c.leaderDB == "" {
+		return nil
+	}
+
+	// Verify that the server is the leader before the Client is used.
+	c.startLeader()
+
+	err := c.checkLeader(ctx)
+	switch {
+	case err == nil:
+		return nil
+	case err == ErrNotLeader && c.reconnect:
+		// Look for the leader in the background.
+		c.setState(StateDisconnected)
+		c.dropConn()
+		return nil
+	default:
+		_ = c.Close()
+		return err
+	}
 }
 
 // requestID returns the next available request ID for an RPC.
@@ -161,14 +367,82 @@ func (c *Client) requestID() string {
 	return strconv.FormatInt(atomic.AddInt64(&c.rpcID, 1), 10)
 }
 
-// Close closes a Client's connection and cleans up its resources.
+// Close closes a Client's connection and cleans up its resources.  Any RPCs
+// which are in progress fail with ErrClosed.  To wait for them to complete,
+// use Shutdown.
 func (c *Client) Close() error {
+	// Prevent any reconnection attempts from replacing the connection.
+	c.connMu.Lock()
+	c.closed = true
+	conn := c.c
+	c.connMu.Unlock()
+
+	// Reject any new RPCs.
+	c.cbMu.Lock()
+	c.draining = true
+	c.cbMu.Unlock()
+
 	c.cancel()
-	err := c.c.Close()
+	err := conn.Close()
 	c.wg.Wait()
+
+	// No more responses or updates can arrive, so fail any remaining RPCs
+	// and stop all monitors.
+	c.failCallbacks(ErrClosed)
+	c.closeMonitors()
+	c.setState(StateClosed)
+
 	return err
 }
 
+// ErrClosed is returned by RPCs which are performed after Shutdown or Close
+// is called, or which are in progress when a Client is closed.
+var ErrClosed = errors.New("OVSDB client closed")
+
+// Shutdown gracefully closes a Client.  Shutdown stops the Client from
+// performing new RPCs, which fail with ErrClosed, and waits for the
+// responses to any RPCs which are in progress.  The Client is then closed as
+// with Close.
+//
+// If ctx is canceled before all responses arrive, the Client is closed
+// immediately, and ctx.Err() is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.cbMu.Lock()
+	c.draining = true
+	if c.drained == nil {
+		// Concurrent calls to Shutdown all wait for the same channel.
+		c.drained = make(chan struct{})
+		if len(c.callbacks) == 0 {
+			close(c.drained)
+		}
+	}
+	drained := c.drained
+	c.cbMu.Unlock()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// The connection may already have been closed by a concurrent call to
+	// Shutdown or Close.
+	if cerr := c.Close(); err == nil && !isClosedNetwork(cerr) {
+		err = cerr
+	}
+
+	return err
+}
+
+// conn returns the Client's current JSON-RPC connection.
+func (c *Client) conn() *jsonrpc.Conn {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+
+	return c.c
+}
+
 // Stats returns a ClientStats with current statistics for the Client.
 func (c *Client) Stats() ClientStats {
 	var s ClientStats
@@ -177,8 +451,23 @@ func (c *Client) Stats() ClientStats {
 	s.Callbacks.Current = len(c.callbacks)
 	c.cbMu.RUnlock()
 
+	c.monMu.RLock()
+	s.Monitors.Current = len(c.monitors)
+	for _, h := range c.monitors {
+		s.Monitors.Queued += h.base().queueLen()
+	}
+	c.monMu.RUnlock()
+
+	s.RPCs.Success = int(atomic.LoadInt64(&c.rpcOK))
+	s.RPCs.Failure = int(atomic.LoadInt64(&c.rpcFail))
+	s.Monitors.Dropped = int(atomic.LoadInt64(&c.monDropped))
+	s.Monitors.Coalesced = int(atomic.LoadInt64(&c.monCoalesced))
+	s.Reconnects.Total = int(atomic.LoadInt64(&c.reconnects))
+
 	s.EchoLoop.Success = int(atomic.LoadInt64(&c.echoOK))
 	s.EchoLoop.Failure = int(atomic.LoadInt64(&c.echoFail))
+	s.EchoReplies.Success = int(atomic.LoadInt64(&c.echoReplyOK))
+	s.EchoReplies.Failure = int(atomic.LoadInt64(&c.echoReplyFail))
 
 	return s
 }
@@ -192,15 +481,75 @@ type ClientStats struct {
 		Current int
 	}
 
+	// Statistics about the Client's monitors.
+	Monitors struct {
+		// The number of monitors currently registered and receiving
+		// update notifications.
+		Current int
+
+		// The number of updates waiting to be sent on the updates
+		// channels of all monitors.
+		Queued int
+
+		// The number of updates discarded or merged into earlier updates
+		// due to the MonitorBackpressure option.
+		Dropped, Coalesced int
+	}
+
+	// Statistics about the Client's RPCs, including echo RPCs sent by the
+	// Client.
+	RPCs struct {
+		// The number of successful and failed RPCs.
+		Success, Failure int
+	}
+
+	// Statistics about the Client's reconnections.
+	Reconnects struct {
+		// The number of times the Client has established a new connection
+		// after its connection was lost.
+		Total int
+	}
+
 	// Statistics about the Client's internal echo RPC loop.
 	EchoLoop struct {
 		// The number of successful and failed echo RPCs in the loop.
 		Success, Failure int
 	}
+
+	// Statistics about the Client's replies to echo RPCs sent by the server.
+	EchoReplies struct {
+		// The number of successful and failed echo replies.
+		Success, Failure int
+	}
 }
 
 // rpc performs a single RPC request, and checks the response for errors.
 func (c *Client) rpc(ctx context.Context, method string, out, arg interface{}) error {
+	return c.doRPC(ctx, method, out, arg, true)
+}
+
+// doRPC implements rpc.  If connected is false, the RPC is sent even if the
+// Client is not yet in the connected state, such as when verifying a new
+// connection before resuming.
+func (c *Client) doRPC(ctx context.Context, method string, out, arg interface{}, connected bool) (err error) {
+	start := time.Now()
+	ctx, end := c.startSpan(ctx, method, arg)
+
+	// The request ID and raw result, if RPCLogging is used.
+	var (
+		id     string
+		result json.RawMessage
+	)
+
+	defer func() {
+		end(out, err)
+		c.rpcDone(method, start, err)
+
+		if c.rpcLog != nil {
+			c.rpcLog.log(method, id, start, arg, result, err)
+		}
+	}()
+
 	// Was the context canceled before sending the RPC?
 	select {
 	case <-ctx.Done():
@@ -213,21 +562,21 @@ func (c *Client) rpc(ctx context.Context, method string, out, arg interface{}) e
 		out = &struct{}{}
 	}
 
-	// Captures any OVSDB errors.
-	r := result{Reply: out}
-
 	req := jsonrpc.Request{
 		Method: method,
 		Params: arg,
 		ID:     c.requestID(),
 	}
+	id = req.ID
 
 	// Add callback for this RPC ID to return results via channel.
 	ch := make(chan rpcResponse, 1)
-	c.addCallback(req.ID, callback{
+	if err := c.addCallback(req.ID, callback{
 		Ctx:      ctx,
 		Response: ch,
-	})
+	}); err != nil {
+		return err
+	}
 
 	// Ensure that the callback is always cleaned up on return from this function.
 	// Note that this will result in the callback being deleted twice if the RPC
@@ -238,10 +587,16 @@ func (c *Client) rpc(ctx context.Context, method string, out, arg interface{}) e
 		c.cbMu.Lock()
 		defer c.cbMu.Unlock()
 
-		delete(c.callbacks, req.ID)
+		c.deleteCallback(req.ID)
 	}()
 
-	if err := c.c.Send(req); err != nil {
+	// The connection may have been lost before the callback was added, in
+	// which case no response will arrive.
+	if connected && c.State() == StateDisconnected {
+		return ErrDisconnected
+	}
+
+	if err := c.conn().SendContext(ctx, req); err != nil {
 		return err
 	}
 
@@ -261,55 +616,110 @@ func (c *Client) rpc(ctx context.Context, method string, out, arg interface{}) e
 		}
 
 		// RPC complete.
-		return rpcResult(res, &r)
+		result = res.Result
+		return c.rpcResult(res, out)
 	}
 }
 
 // listen starts an RPC receive loop that can return RPC results to
 // clients via a callback.
-func (c *Client) listen(ctx context.Context, echoC chan<- struct{}) {
+func (c *Client) listen(ctx context.Context) {
+	// Notifications are handled before the next message is received, so
+	// the buffers used to receive them are reused for each message.
+	var res jsonrpc.Response
+
 	for {
-		res, err := c.c.Receive()
-		if err != nil {
-			// EOF or closed connection means time to stop serving.
-			if err == io.EOF || isClosedNetwork(err) {
+		if err := c.conn().ReceiveInto(&res); err != nil {
+			if ctx.Err() != nil {
+				// Client closed.
+				return
+			}
+
+			if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
+				// Temporary errors may succeed on the next attempt.
+				continue
+			}
+
+			// Any other error, such as EOF, a closed connection, or invalid
+			// JSON, means the connection can no longer be used.  Stop serving,
+			// unless the Client should reconnect.
+			c.disconnected()
+			if !c.reconnect || !c.redial(ctx) {
 				return
 			}
 
-			// For any other connection errors, just keep trying.
 			continue
 		}
 
+		c.received()
+
 		// Handle any JSON-RPC notifications.
-		// TODO(mdlayher): deal with other RPC notifications.
 		switch res.Method {
 		case "echo":
-			// OVSDB server wants us to send an echo to it, but will also send
-			// us a response to that echo.  Since this goroutine is the one that
-			// needs to receive that response and issue the callback for it, we
-			// ask the echo loop goroutine to send an echo on our behalf.
-			select {
-			case <-ctx.Done():
-			case echoC <- struct{}{}:
-			}
+			// OVSDB server sent us an echo request to verify that this
+			// connection is alive, so reply to it on behalf of the user.
+			c.echoReply(&res)
+			continue
+		case "update", "update2", "update3":
+			// Deliver table updates to the appropriate monitor.
+			c.doUpdate(ctx, res.Method, res.Params)
+			continue
+		case "locked", "stolen":
+			// Report changes in lock ownership.
+			c.doLock(res.Method, res.Params)
+			continue
+		case "monitor_canceled":
+			// A monitored database was converted or removed.
+			c.doMonitorCanceled(ctx, res.Params)
+			continue
+		}
+
+		// Any other notifications can't be matched to a callback.
+		if res.ID == nil {
 			continue
 		}
 
 		// Handle any JSON-RPC top-level errors.
-		if err := res.Err(); err != nil {
+		if err := responseError(&res); err != nil {
 			c.doCallback(*res.ID, rpcResponse{
 				Error: err,
 			})
 			continue
 		}
 
-		// Return RPC results via callback.
+		// Return RPC results via callback.  Results are decoded by another
+		// goroutine, so they must be copied from the reused buffer.
 		c.doCallback(*res.ID, rpcResponse{
-			Result: res.Result,
+			Result: append(json.RawMessage(nil), res.Result...),
 		})
 	}
 }
 
+// echoReply replies to an echo request sent by the OVSDB server.
+func (c *Client) echoReply(req *jsonrpc.Response) {
+	// A reply is impossible without an ID.
+	if req.ID == nil {
+		return
+	}
+
+	// The reply must contain the same parameters as the request.
+	params := req.Params
+	if len(params) == 0 {
+		params = []byte("[]")
+	}
+
+	err := c.conn().Reply(jsonrpc.Response{
+		ID:     req.ID,
+		Result: params,
+	})
+	if err != nil {
+		atomic.AddInt64(&c.echoReplyFail, 1)
+		return
+	}
+
+	atomic.AddInt64(&c.echoReplyOK, 1)
+}
+
 // echoTicker starts a loop that triggers echo RPCs via channel at a fixed
 // time interval.
 func (c *Client) echoTicker(ctx context.Context, d time.Duration, echoC chan<- struct{}) {
@@ -377,19 +787,61 @@ func (c *Client) echoLoop(ctx context.Context, echoC <-chan struct{}) {
 type callback struct {
 	Ctx      context.Context
 	Response chan rpcResponse
+
+	// The time at which the callback was registered, shortly before the
+	// RPC was sent.
+	Sent time.Time
 }
 
 // addCallback registers a callback for an RPC response for the specified ID.
-func (c *Client) addCallback(id string, cb callback) {
+// It returns ErrClosed if the Client is shutting down.
+func (c *Client) addCallback(id string, cb callback) error {
 	c.cbMu.Lock()
 	defer c.cbMu.Unlock()
 
+	if c.draining {
+		return ErrClosed
+	}
+
 	if _, ok := c.callbacks[id]; ok {
 		// This ID was already registered.
 		panicf("OVSDB callback with ID %q already registered", id)
 	}
 
+	cb.Sent = time.Now()
 	c.callbacks[id] = cb
+	return nil
+}
+
+// failCallbacks fails all RPCs which are awaiting responses with err.
+func (c *Client) failCallbacks(err error) {
+	c.cbMu.Lock()
+	ids := make([]string, 0, len(c.callbacks))
+	for id := range c.callbacks {
+		ids = append(ids, id)
+	}
+	c.cbMu.Unlock()
+
+	for _, id := range ids {
+		c.doCallback(id, rpcResponse{
+			Error: err,
+		})
+	}
+}
+
+// deleteCallback clears the callback for the specified ID, and reports when
+// the last callback is cleared while shutting down.  c.cbMu must be held.
+func (c *Client) deleteCallback(id string) {
+	if _, ok := c.callbacks[id]; !ok {
+		// Already cleared.
+		return
+	}
+
+	delete(c.callbacks, id)
+
+	if c.draining && c.drained != nil && len(c.callbacks) == 0 {
+		close(c.drained)
+	}
 }
 
 // doCallback performs a callback for an RPC response and clears the
@@ -415,7 +867,7 @@ func (c *Client) doCallback(id string, res rpcResponse) {
 		// Message was successfully sent.
 	}
 
-	delete(c.callbacks, id)
+	c.deleteCallback(id)
 }
 
 // isClosedNetwork checks for errors caused by a closed network connection.
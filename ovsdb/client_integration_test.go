@@ -94,7 +94,7 @@ func testClientDatabases(ctx context.Context, t *testing.T, c *ovsdb.Client) {
 	}
 
 	for _, d := range dbs {
-		rows, err := c.Transact(ctx, d, []ovsdb.TransactOp{
+		results, err := c.Transact(ctx, d, []ovsdb.TransactOp{
 			ovsdb.Select{
 				Table: "Bridge",
 			},
@@ -103,8 +103,10 @@ func testClientDatabases(ctx context.Context, t *testing.T, c *ovsdb.Client) {
 			t.Fatalf("failed to perform transaction: %v", err)
 		}
 
-		for i, r := range rows {
-			t.Logf("[%02d] %v", i, r)
+		for _, res := range results {
+			for i, r := range res.Rows {
+				t.Logf("[%02d] %v", i, r)
+			}
 		}
 	}
 }
@@ -16,11 +16,20 @@ package ovsdb_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/big"
+	"net"
 	"os"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -77,6 +86,105 @@ func TestClientOVSDBError(t *testing.T) {
 	}
 }
 
+func TestClientOVSDBErrorIs(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		var res interface{}
+		switch req.Method {
+		case "transact":
+			res = []interface{}{
+				map[string]int{"count": 1},
+				&ovsdb.Error{
+					Err:     "referential integrity violation",
+					Details: "cannot delete Port row",
+				},
+			}
+		default:
+			// Top-level JSON-RPC errors may also contain OVSDB errors.
+			return jsonrpc.Response{
+				ID: &req.ID,
+				Error: &ovsdb.Error{
+					Err:     "timed out",
+					Details: "convert timed out",
+				},
+			}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+	defer done()
+
+	ctx := context.Background()
+
+	_, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Delete{Table: "Port"},
+	})
+	checkErrorIs(t, err, ovsdb.ErrReferentialIntegrity, ovsdb.ErrConstraintViolation)
+
+	err = c.Convert(ctx, "Open_vSwitch", &ovsdb.Schema{})
+	checkErrorIs(t, err, ovsdb.ErrTimedOut, ovsdb.ErrReferentialIntegrity)
+}
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *ovsdb.Error
+		target error
+		ok     bool
+	}{
+		{
+			name:   "same kind",
+			err:    &ovsdb.Error{Err: "not owner", Details: "lock foo"},
+			target: ovsdb.ErrNotOwner,
+			ok:     true,
+		},
+		{
+			name:   "different kind",
+			err:    &ovsdb.Error{Err: "not owner"},
+			target: ovsdb.ErrAborted,
+		},
+		{
+			name:   "target has details",
+			err:    &ovsdb.Error{Err: "aborted", Details: "foo"},
+			target: &ovsdb.Error{Err: "aborted", Details: "foo"},
+		},
+		{
+			name:   "not an OVSDB error",
+			err:    &ovsdb.Error{Err: "aborted"},
+			target: ovsdb.ErrDisconnected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.ok, tt.err.Is(tt.target)); diff != "" {
+				t.Fatalf("unexpected Is result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// checkErrorIs verifies that err is an *ovsdb.Error which matches want, but
+// not other.  errors.Is is not used so that tests run on older versions of
+// Go.
+func checkErrorIs(t *testing.T, err error, want, other error) {
+	t.Helper()
+
+	oerr, ok := err.(*ovsdb.Error)
+	if !ok {
+		t.Fatalf("error of wrong type: %#v", err)
+	}
+
+	if !oerr.Is(want) {
+		t.Fatalf("error %v does not match %v", err, want)
+	}
+	if oerr.Is(other) {
+		t.Fatalf("error %v unexpectedly matches %v", err, other)
+	}
+}
+
 func TestClientBadCallback(t *testing.T) {
 	c, notifC, done := testClient(t, func(_ jsonrpc.Request) jsonrpc.Response {
 		return jsonrpc.Response{
@@ -146,6 +254,130 @@ func TestClientContextCancelDuringRPC(t *testing.T) {
 	}
 }
 
+func TestClientContextTimeoutAllRPCs(t *testing.T) {
+	c, _, done := testClient(t, func(_ jsonrpc.Request) jsonrpc.Response {
+		// Only respond with messages that don't match an incoming request,
+		// as if the server is stuck.
+		return jsonrpc.Response{
+			ID:     strPtr("foo"),
+			Result: mustMarshalJSON(t, []string{"foo"}),
+		}
+	})
+	defer done()
+
+	const db = "Open_vSwitch"
+	var (
+		requests     = map[string]ovsdb.MonitorRequest{"Bridge": {}}
+		condRequests = map[string]ovsdb.MonitorCondRequest{"Bridge": {}}
+	)
+
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{
+			name: "list_dbs",
+			fn: func(ctx context.Context) error {
+				_, err := c.ListDatabases(ctx)
+				return err
+			},
+		},
+		{
+			name: "echo",
+			fn:   c.Echo,
+		},
+		{
+			name: "transact",
+			fn: func(ctx context.Context) error {
+				_, err := c.Transact(ctx, db, []ovsdb.TransactOp{ovsdb.Select{Table: "Bridge"}})
+				return err
+			},
+		},
+		{
+			name: "get_schema",
+			fn: func(ctx context.Context) error {
+				_, err := c.GetSchema(ctx, db)
+				return err
+			},
+		},
+		{
+			name: "monitor",
+			fn: func(ctx context.Context) error {
+				_, err := c.Monitor(ctx, db, requests)
+				return err
+			},
+		},
+		{
+			name: "monitor_cond",
+			fn: func(ctx context.Context) error {
+				_, err := c.MonitorCond(ctx, db, condRequests)
+				return err
+			},
+		},
+		{
+			name: "monitor_cond_since",
+			fn: func(ctx context.Context) error {
+				_, err := c.MonitorCondSince(ctx, db, condRequests, "")
+				return err
+			},
+		},
+		{
+			name: "lock",
+			fn: func(ctx context.Context) error {
+				_, err := c.Lock(ctx, "foo")
+				return err
+			},
+		},
+		{
+			name: "steal",
+			fn: func(ctx context.Context) error {
+				return c.Steal(ctx, "foo")
+			},
+		},
+		{
+			name: "unlock",
+			fn: func(ctx context.Context) error {
+				return c.Unlock(ctx, "foo")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			if err := tt.fn(ctx); err != context.DeadlineExceeded {
+				t.Fatalf("expected context deadline exceeded error: %v", err)
+			}
+		})
+	}
+
+	// Monitors which failed to start must not remain registered.
+	if diff := cmp.Diff(0, c.Stats().Monitors.Current); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientContextTimeoutSend(t *testing.T) {
+	// The server never reads requests, so sending eventually blocks.
+	conn, server := net.Pipe()
+	defer server.Close()
+
+	c, err := ovsdb.New(conn)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.ListDatabases(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context deadline exceeded error: %v", err)
+	}
+}
+
 func TestClientLeakCallbacks(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping during short test run")
@@ -179,6 +411,9 @@ func TestClientLeakCallbacks(t *testing.T) {
 		}
 	}
 
+	// Each RPC which timed out counts as a failure.
+	want.RPCs.Failure = 5
+
 	if diff := cmp.Diff(want, c.Stats()); diff != "" {
 		t.Fatalf("unexpected ending client stats (-want +got):\n%s", diff)
 	}
@@ -234,51 +469,377 @@ func TestClientEchoLoop(t *testing.T) {
 }
 
 func TestClientEchoNotification(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping during short test run")
-	}
+	replyC := make(chan jsonrpc.Request, 1)
 
 	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
-		if diff := cmp.Diff("echo", req.Method); diff != "" {
+		// The test server decodes the client's reply as a request with
+		// no method.
+		if diff := cmp.Diff("", req.Method); diff != "" {
 			panicf("unexpected RPC method (-want +got):\n%s", diff)
 		}
 
+		replyC <- req
+
+		// Acknowledge with an ID the client doesn't know about, which
+		// should be ignored.
 		return jsonrpc.Response{
-			ID:     &req.ID,
-			Result: mustMarshalJSON(t, req.Params),
+			ID: strPtr("ack"),
 		}
 	})
 	defer done()
 
-	// Prompt the client to send an echo in the same way ovsdb-server does.
+	// Send an echo request in the same way ovsdb-server does.
 	notifC <- &jsonrpc.Response{
 		ID:     strPtr("echo"),
 		Method: "echo",
+		Params: mustMarshalJSON(t, []string{}),
 	}
 
-	// Fail the test if the RPCs don't fire.
+	// Fail the test if the reply doesn't arrive.
 	timer := time.AfterFunc(2*time.Second, func() {
-		panicf("took too long to wait for echo RPCs")
+		panicf("took too long to wait for echo reply")
 	})
 	defer timer.Stop()
 
-	// Ensure that background echo RPCs are being sent.
-	tick := time.NewTicker(100 * time.Millisecond)
+	if diff := cmp.Diff("echo", (<-replyC).ID); diff != "" {
+		t.Fatalf("unexpected echo reply ID (-want +got):\n%s", diff)
+	}
+
+	// The reply may be received before the client updates its statistics.
+	tick := time.NewTicker(10 * time.Millisecond)
 	defer tick.Stop()
 
+	var stats ovsdb.ClientStats
 	for {
-		// Just wait for a single echo RPC cycle before success.
+		stats = c.Stats()
+		if stats.EchoReplies.Success > 0 {
+			break
+		}
+
 		<-tick.C
+	}
 
-		stats := c.Stats()
+	if diff := cmp.Diff(1, stats.EchoReplies.Success); diff != "" {
+		t.Fatalf("unexpected number of echo replies (-want +got):\n%s", diff)
+	}
 
-		if n := stats.EchoLoop.Failure; n > 0 {
-			t.Fatalf("echo loop RPC failed %d times", n)
+	// The client must not send echo requests of its own unless configured
+	// to do so.
+	if diff := cmp.Diff(0, stats.EchoLoop.Success); diff != "" {
+		t.Fatalf("unexpected number of echo RPCs (-want +got):\n%s", diff)
+	}
+}
+
+func TestDialTLS(t *testing.T) {
+	cert, pool := testCertificate(t)
+
+	tests := []struct {
+		name   string
+		server *tls.Config
+		client *tls.Config
+		ok     bool
+	}{
+		{
+			name: "server certificate",
+			server: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+			},
+			client: &tls.Config{
+				RootCAs: pool,
+			},
+			ok: true,
+		},
+		{
+			name: "client certificate",
+			server: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    pool,
+			},
+			client: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				RootCAs:      pool,
+			},
+			ok: true,
+		},
+		{
+			name: "missing client certificate",
+			server: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    pool,
+			},
+			client: &tls.Config{
+				RootCAs: pool,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []string{"OVN_Northbound"}
+
+			addr, done := testTLSServer(t, tt.server, want)
+			defer done()
+
+			c, err := ovsdb.Dial("tcp", addr, ovsdb.TLSConfig(tt.client))
+			if err != nil {
+				if tt.ok {
+					t.Fatalf("failed to dial: %v", err)
+				}
+
+				return
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// With TLS 1.3, a server rejects the client's certificate after
+			// the client completes its handshake, so the failure may not be
+			// observed until an RPC is performed.
+			dbs, err := c.ListDatabases(ctx)
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("expected an error, but none occurred")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			if diff := cmp.Diff(want, dbs); diff != "" {
+				t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDialTLSUntrusted(t *testing.T) {
+	cert, _ := testCertificate(t)
+
+	addr, done := testTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}, nil)
+	defer done()
+
+	// The server's certificate is not trusted by the system roots.
+	_, err := ovsdb.Dial("tcp", addr, ovsdb.TLSConfig(&tls.Config{}))
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// testTLSServer starts a TLS server which serves a single list_dbs RPC,
+// returning dbs.  It returns the server's address and a function to stop it.
+func testTLSServer(t *testing.T, cfg *tls.Config, dbs []string) (string, func()) {
+	t.Helper()
+
+	l, err := tls.Listen("tcp", "localhost:0", cfg)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	return testServe(t, l, dbs)
+}
+
+// testServe serves a single list_dbs RPC on l, returning dbs.  It returns the
+// listener's address and a function to stop serving.
+func TestClientShutdown(t *testing.T) {
+	// Hold the first RPC until the test allows it to complete.
+	startC := make(chan struct{})
+	unblock := make(chan struct{})
+
+	var once sync.Once
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		once.Do(func() {
+			close(startC)
+			<-unblock
+		})
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
 		}
+	})
+	defer done()
+
+	rpcC := make(chan error, 1)
+	go func() {
+		_, err := c.ListDatabases(context.Background())
+		rpcC <- err
+	}()
+	<-startC
 
-		if n := stats.EchoLoop.Success; n > 0 {
+	shutdownC := make(chan error, 1)
+	go func() {
+		shutdownC <- c.Shutdown(context.Background())
+	}()
+
+	// Wait for new RPCs to be rejected.  The server handles one request at
+	// a time, so RPCs sent before Shutdown is called time out.
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := c.ListDatabases(ctx)
+		cancel()
+
+		if err == ovsdb.ErrClosed {
 			break
 		}
+		if err != context.DeadlineExceeded {
+			t.Fatalf("unexpected error while shutting down: %v", err)
+		}
+	}
+
+	// The in-flight RPC completes before the Client is closed.
+	close(unblock)
+
+	if err := <-rpcC; err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if err := <-shutdownC; err != nil {
+		t.Fatalf("failed to shut down: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.StateClosed, c.State()); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientShutdownConcurrent(t *testing.T) {
+	// Hold the first RPC until the test allows it to complete.
+	startC := make(chan struct{})
+	unblock := make(chan struct{})
+
+	var once sync.Once
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		once.Do(func() {
+			close(startC)
+			<-unblock
+		})
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer done()
+
+	rpcC := make(chan error, 1)
+	go func() {
+		_, err := c.ListDatabases(context.Background())
+		rpcC <- err
+	}()
+	<-startC
+
+	// Every caller waits for the in-flight RPC to complete, rather than
+	// for its context to expire.
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdownC := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			shutdownC <- c.Shutdown(ctx)
+		}()
+	}
+
+	// Give each caller time to begin waiting.
+	time.Sleep(50 * time.Millisecond)
+	close(unblock)
+
+	if err := <-rpcC; err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		if err := <-shutdownC; err != nil {
+			t.Fatalf("failed to shut down: %v", err)
+		}
+	}
+
+	if diff := cmp.Diff(ovsdb.StateClosed, c.State()); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientShutdownTimeout(t *testing.T) {
+	// The server never replies.
+	startC := make(chan struct{})
+	unblock := make(chan struct{})
+
+	var once sync.Once
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		once.Do(func() {
+			close(startC)
+		})
+		<-unblock
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer done()
+	defer close(unblock)
+
+	rpcC := make(chan error, 1)
+	go func() {
+		_, err := c.ListDatabases(context.Background())
+		rpcC <- err
+	}()
+	<-startC
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if diff := cmp.Diff(context.DeadlineExceeded, c.Shutdown(ctx), cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})); diff != "" {
+		t.Fatalf("unexpected error (-want +got):\n%s", diff)
+	}
+
+	// The in-flight RPC fails when the Client is closed.
+	if diff := cmp.Diff(ovsdb.ErrClosed, <-rpcC, cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})); diff != "" {
+		t.Fatalf("unexpected RPC error (-want +got):\n%s", diff)
+	}
+}
+
+func testServe(t *testing.T, l net.Listener, dbs []string) (string, func()) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		var req jsonrpc.Request
+		if err := json.NewDecoder(c).Decode(&req); err != nil {
+			return
+		}
+
+		_ = json.NewEncoder(c).Encode(jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, dbs),
+		})
+	}()
+
+	return l.Addr().String(), func() {
+		_ = l.Close()
+		wg.Wait()
 	}
 }
 
@@ -330,3 +891,50 @@ func strPtr(s string) *string {
 func panicf(format string, a ...interface{}) {
 	panic(fmt.Sprintf(format, a...))
 }
+
+// testCertificate generates a self-signed certificate for localhost, and a
+// certificate pool which trusts it.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, pool
+}
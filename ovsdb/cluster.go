@@ -0,0 +1,174 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrNotLeader is returned when the LeaderOnly option is used and an OVSDB
+// server is not the leader of its cluster for the specified database.
+var ErrNotLeader = errors.New("OVSDB server is not the cluster leader for the database")
+
+// LeaderOnly specifies that the Client must only use an OVSDB server which
+// is the leader of its cluster for database db, as transactions sent to
+// other cluster members may fail.  A server which hosts db as a standalone
+// database is always considered the leader.  The server must provide the
+// _Server database, which is available in Open vSwitch 2.9 and later.
+//
+// The server's status is checked each time a connection is established.  If
+// the server is not the leader, or later loses leadership, the connection is
+// closed.  With the Reconnect option, the Client then dials again, until it
+// finds the leader; a DialFunc which cycles through the members of the
+// cluster allows the Client to find the leader quickly.  Otherwise, Dial
+// and New return ErrNotLeader, and a Client which loses leadership becomes
+// disconnected.
+func LeaderOnly(db string) OptionFunc {
+	return func(c *Client) error {
+		c.leaderDB = db
+		return nil
+	}
+}
+
+const (
+	// serverDB is the name of the database which contains information
+	// about an OVSDB server.
+	serverDB = "_Server"
+
+	// leaderMonitorID is the ID of the monitor used by LeaderOnly.  It never
+	// conflicts with the IDs of other monitors.
+	leaderMonitorID = "leader"
+
+	// leaderTimeout is the maximum amount of time to wait for a server to
+	// report whether it is the leader.
+	leaderTimeout = 10 * time.Second
+)
+
+// A leaderMonitor monitors a server's _Server database, and closes the
+// Client's connection if the server is no longer the leader for a database.
+type leaderMonitor struct {
+	monitorBase
+	db string
+}
+
+// startLeader registers the leaderMonitor for a Client using LeaderOnly.
+func (c *Client) startLeader() {
+	m := &leaderMonitor{
+		monitorBase: c.newMonitorBase(),
+		db:          c.leaderDB,
+	}
+	m.id = leaderMonitorID
+
+	c.addMonitor(m.id, m)
+}
+
+// checkLeader monitors the _Server database on the current connection, and
+// returns ErrNotLeader if the server is not the leader.
+func (c *Client) checkLeader(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, leaderTimeout)
+	defer cancel()
+
+	requests := map[string]MonitorRequest{
+		"Database": {
+			Columns: []string{"name", "model", "connected", "leader"},
+		},
+	}
+
+	// The Client is not yet connected on this connection.
+	var initial TableUpdates
+	if err := c.doRPC(ctx, "monitor", &initial, []interface{}{serverDB, leaderMonitorID, requests}, false); err != nil {
+		return err
+	}
+
+	for _, u := range initial["Database"] {
+		if isLeader(u.New, c.leaderDB) {
+			return nil
+		}
+	}
+
+	return ErrNotLeader
+}
+
+// isLeader reports whether a row of the _Server Database table indicates
+// that a server is the leader for db.
+func isLeader(row Row, db string) bool {
+	var d ServerDatabase
+	if err := UnmarshalRow(row, &d); err != nil || d.Name != db {
+		return false
+	}
+
+	return d.IsLeader()
+}
+
+// dropConn closes the Client's current connection so that the Client will
+// reconnect, if configured to do so.
+func (c *Client) dropConn() {
+	_ = c.conn().Close()
+}
+
+// handle implements monitorHandler.
+func (m *leaderMonitor) handle(_ context.Context, method string, args []json.RawMessage) {
+	// Parameters are [<json-value>, <table-updates>].
+	if method != "update" || len(args) != 2 {
+		return
+	}
+
+	var updates TableUpdates
+	if err := m.c.unmarshal(args[1], &updates); err != nil {
+		return
+	}
+
+	for _, u := range updates["Database"] {
+		// Deleted rows and rows for other databases are ignored.
+		if name, _ := u.New["name"].(string); name != m.db {
+			continue
+		}
+
+		if !isLeader(u.New, m.db) {
+			m.c.dropConn()
+			return
+		}
+	}
+}
+
+// resume implements monitorHandler.  The monitor is re-established by
+// checkLeader, before any other state is restored.
+func (m *leaderMonitor) resume(_ context.Context) error {
+	m.finishResume(func(_ <-chan struct{}) {})
+	return nil
+}
+
+// remonitor implements monitorHandler.  The monitor is re-established by
+// checkLeader, and the connection is closed if leadership was lost.
+func (m *leaderMonitor) remonitor(ctx context.Context, _ *Schema) error {
+	m.finishResume(func(_ <-chan struct{}) {})
+
+	if err := m.c.checkLeader(ctx); err != nil {
+		m.c.dropConn()
+	}
+
+	return nil
+}
+
+// database implements monitorHandler.
+func (m *leaderMonitor) database() string { return serverDB }
+
+// close implements monitorHandler.
+func (m *leaderMonitor) close() {
+	m.closeFunc(func() {})
+}
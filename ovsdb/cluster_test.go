@@ -0,0 +1,167 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	clusterDB   = "OVN_Northbound"
+	clusterUUID = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+)
+
+func TestClientLeaderOnly(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+	}{
+		{
+			name:  "leader",
+			model: "clustered",
+		},
+		{
+			name:  "standalone",
+			model: "standalone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, done := testClient(t, testClusterServer(t, func() ovsdb.Row {
+				return clusterRow(tt.model, true)
+			}), ovsdb.LeaderOnly(clusterDB))
+			defer done()
+
+			want := []string{clusterDB}
+
+			dbs, err := c.ListDatabases(context.Background())
+			if err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			if diff := cmp.Diff(want, dbs); diff != "" {
+				t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientLeaderOnlyNotLeader(t *testing.T) {
+	conn, _, done := jsonrpc.TestNetConn(t, testClusterServer(t, func() ovsdb.Row {
+		return clusterRow("clustered", false)
+	}))
+	defer done()
+
+	_, err := ovsdb.New(conn, ovsdb.LeaderOnly(clusterDB))
+	if err != ovsdb.ErrNotLeader {
+		t.Fatalf("expected not leader error: %v", err)
+	}
+}
+
+func TestClientLeaderOnlyReconnect(t *testing.T) {
+	// The first server is a follower, and the second is the leader.
+	var n int64
+	d := newTestDialer(t, testClusterServer(t, func() ovsdb.Row {
+		return clusterRow("clustered", atomic.AddInt64(&n, 1) > 1)
+	}))
+	defer d.done()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c := d.client(
+		ovsdb.LeaderOnly(clusterDB),
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	defer c.Close()
+
+	wantStates := func(states ...ovsdb.ConnState) {
+		t.Helper()
+
+		for _, want := range states {
+			if diff := cmp.Diff(want, <-stateC); diff != "" {
+				t.Fatalf("unexpected state (-want +got):\n%s", diff)
+			}
+		}
+	}
+
+	wantStates(ovsdb.StateDisconnected, ovsdb.StateConnected)
+
+	// The leader loses leadership, so the client must reconnect.
+	d.notify(&jsonrpc.Response{
+		Method: "update",
+		Params: mustMarshalJSON(t, []interface{}{"leader", ovsdb.TableUpdates{
+			"Database": {
+				clusterUUID: {New: clusterRow("clustered", false)},
+			},
+		}}),
+	})
+
+	wantStates(ovsdb.StateDisconnected, ovsdb.StateConnected)
+
+	if _, err := c.ListDatabases(context.Background()); err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+}
+
+// testClusterServer creates a TestFunc for a server which reports the _Server
+// Database row returned by row, and otherwise serves list_dbs.
+func testClusterServer(t *testing.T, row func() ovsdb.Row) jsonrpc.TestFunc {
+	return func(req jsonrpc.Request) jsonrpc.Response {
+		var res interface{}
+		switch req.Method {
+		case "monitor":
+			ps := req.Params.([]interface{})
+			if diff := cmp.Diff([]interface{}{"_Server", "leader"}, ps[:2]); diff != "" {
+				panicf("unexpected monitor parameters (-want +got):\n%s", diff)
+			}
+
+			res = ovsdb.TableUpdates{
+				"Database": {
+					clusterUUID: {New: row()},
+				},
+			}
+		case "list_dbs":
+			res = []string{clusterDB}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	}
+}
+
+// clusterRow returns a _Server Database row for clusterDB.
+func clusterRow(model string, leader bool) ovsdb.Row {
+	return ovsdb.Row{
+		"name":      clusterDB,
+		"model":     model,
+		"connected": true,
+		"leader":    leader,
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"sync"
+)
+
+// A ClusterEventType is a kind of change to the cluster status of a database.
+type ClusterEventType int
+
+// Possible ClusterEventType values.
+const (
+	// The server became the leader of its cluster.
+	ClusterLeader ClusterEventType = iota
+
+	// The server is no longer the leader of its cluster.
+	ClusterFollower
+
+	// The server lost contact with a majority of its cluster, so the
+	// cluster has no quorum from the server's point of view.
+	ClusterDisconnected
+
+	// The server regained contact with a majority of its cluster.
+	ClusterConnected
+)
+
+// String returns the string representation of a ClusterEventType.
+func (t ClusterEventType) String() string {
+	switch t {
+	case ClusterLeader:
+		return "leader"
+	case ClusterFollower:
+		return "follower"
+	case ClusterDisconnected:
+		return "disconnected"
+	case ClusterConnected:
+		return "connected"
+	default:
+		return "unknown"
+	}
+}
+
+// A ClusterEvent reports a change to the cluster status of a database.
+type ClusterEvent struct {
+	// The kind of change.
+	Type ClusterEventType
+
+	// The status of the database after the change.
+	Database ServerDatabase
+}
+
+// A ClusterMonitor reports changes to the status of a clustered database, as
+// seen by an OVSDB server.  ClusterMonitors are created using
+// Client.MonitorCluster.
+type ClusterMonitor struct {
+	m      *Monitor
+	db     string
+	events chan ClusterEvent
+
+	// mu guards the database's status, and whether it is known.
+	mu     sync.Mutex
+	status ServerDatabase
+	known  bool
+}
+
+// MonitorCluster begins monitoring the status of database db using the
+// server's _Server database, which is available in Open vSwitch 2.9 and
+// later.
+//
+// The returned ClusterMonitor delivers a ClusterEvent on its events channel
+// each time the server gains or loses leadership of the database's cluster,
+// or loses or regains contact with a majority of the cluster, so that
+// callers can pause writes before transactions begin to fail.  Only changes
+// are reported: the status when the ClusterMonitor is created, or when the
+// database is first added, is available using ClusterMonitor.Status.
+//
+// If the Client reconnects, changes which occurred while disconnected are
+// reported once the monitor is re-established.
+func (c *Client) MonitorCluster(ctx context.Context, db string) (*ClusterMonitor, error) {
+	m, err := c.Monitor(ctx, serverDB, map[string]MonitorRequest{
+		"Database": {Columns: serverColumns},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &ClusterMonitor{
+		m:      m,
+		db:     db,
+		events: make(chan ClusterEvent),
+	}
+
+	cm.update(m.Initial, func(ClusterEvent) {})
+
+	go func() {
+		defer close(cm.events)
+
+		for u := range m.Updates() {
+			cm.update(u, func(e ClusterEvent) {
+				select {
+				case cm.events <- e:
+				case <-m.done:
+				}
+			})
+		}
+	}()
+
+	return cm, nil
+}
+
+// Events returns a channel which receives changes to the database's cluster
+// status.  The channel is closed when the ClusterMonitor is canceled or its
+// Client is closed.
+func (m *ClusterMonitor) Events() <-chan ClusterEvent {
+	return m.events
+}
+
+// Status returns the database's most recently reported status.
+func (m *ClusterMonitor) Status() ServerDatabase {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.status
+}
+
+// Cancel stops the ClusterMonitor and closes its events channel.
+func (m *ClusterMonitor) Cancel(ctx context.Context) error {
+	return m.m.Cancel(ctx)
+}
+
+// update applies updates to the _Server Database table, and invokes fn with
+// each resulting change to the database's cluster status.
+func (m *ClusterMonitor) update(updates TableUpdates, fn func(e ClusterEvent)) {
+	for _, u := range updates["Database"] {
+		// Deleted rows and rows for other databases are ignored.
+		var d ServerDatabase
+		if err := UnmarshalRow(u.New, &d); err != nil || d.Name != m.db {
+			continue
+		}
+
+		m.mu.Lock()
+		prev, known := m.status, m.known
+		m.status, m.known = d, true
+		m.mu.Unlock()
+
+		if !known {
+			// The first status of the database is not a change.
+			continue
+		}
+
+		for _, typ := range clusterChanges(prev, d) {
+			fn(ClusterEvent{
+				Type:     typ,
+				Database: d,
+			})
+		}
+	}
+}
+
+// clusterChanges returns the changes in cluster status from prev to next.
+// Loss of contact with the cluster is reported before loss of leadership,
+// and gain of leadership after contact is regained.
+func clusterChanges(prev, next ServerDatabase) []ClusterEventType {
+	var changes []ClusterEventType
+	if prev.Connected && !next.Connected {
+		changes = append(changes, ClusterDisconnected)
+	}
+	if !prev.Connected && next.Connected {
+		changes = append(changes, ClusterConnected)
+	}
+
+	if prev.Leader && !next.Leader {
+		changes = append(changes, ClusterFollower)
+	}
+	if !prev.Leader && next.Leader {
+		changes = append(changes, ClusterLeader)
+	}
+
+	return changes
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientMonitorCluster(t *testing.T) {
+	const db = "OVN_Northbound"
+
+	row := func(name string, connected, leader bool) ovsdb.Row {
+		return ovsdb.Row{
+			"name":      name,
+			"model":     ovsdb.ModelClustered,
+			"connected": connected,
+			"leader":    leader,
+		}
+	}
+
+	s, err := ovsdbtest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close()
+
+	s.Handle("monitor", func(_ json.RawMessage) (interface{}, error) {
+		return ovsdb.TableUpdates{
+			"Database": {
+				"a": {New: row(db, true, true)},
+				"b": {New: row("OVN_Southbound", true, false)},
+			},
+		}, nil
+	})
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	m, err := c.MonitorCluster(ctx, db)
+	if err != nil {
+		t.Fatalf("failed to monitor cluster: %v", err)
+	}
+
+	want := ovsdb.ServerDatabase{
+		Name:      db,
+		Model:     ovsdb.ModelClustered,
+		Connected: true,
+		Leader:    true,
+	}
+
+	if diff := cmp.Diff(want, m.Status()); diff != "" {
+		t.Fatalf("unexpected initial status (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		name      string
+		connected bool
+		leader    bool
+		want      []ovsdb.ClusterEventType
+	}{
+		{
+			name:      "leadership lost",
+			connected: true,
+			want:      []ovsdb.ClusterEventType{ovsdb.ClusterFollower},
+		},
+		{
+			name: "quorum lost",
+			want: []ovsdb.ClusterEventType{ovsdb.ClusterDisconnected},
+		},
+		{
+			name:      "quorum regained and leader elected",
+			connected: true,
+			leader:    true,
+			want:      []ovsdb.ClusterEventType{ovsdb.ClusterConnected, ovsdb.ClusterLeader},
+		},
+	}
+
+	for _, tt := range tests {
+		// Changes to other databases are not reported.
+		if err := s.Update("_Server", ovsdb.TableUpdates{
+			"Database": {
+				"b": {New: row("OVN_Southbound", false, false)},
+			},
+		}); err != nil {
+			t.Fatalf("failed to send update: %v", err)
+		}
+
+		if err := s.Update("_Server", ovsdb.TableUpdates{
+			"Database": {
+				"a": {New: row(db, tt.connected, tt.leader)},
+			},
+		}); err != nil {
+			t.Fatalf("failed to send update: %v", err)
+		}
+
+		for _, typ := range tt.want {
+			select {
+			case e := <-m.Events():
+				want := ovsdb.ClusterEvent{
+					Type: typ,
+					Database: ovsdb.ServerDatabase{
+						Name:      db,
+						Model:     ovsdb.ModelClustered,
+						Connected: tt.connected,
+						Leader:    tt.leader,
+					},
+				}
+
+				if diff := cmp.Diff(want, e); diff != "" {
+					t.Fatalf("%s: unexpected event (-want +got):\n%s", tt.name, diff)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: timed out waiting for event", tt.name)
+			}
+		}
+	}
+
+	if err := m.Cancel(ctx); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	select {
+	case e, ok := <-m.Events():
+		if ok {
+			t.Fatalf("unexpected event: %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for events channel to be closed")
+	}
+}
+
+func TestClusterEventTypeString(t *testing.T) {
+	tests := []struct {
+		t    ovsdb.ClusterEventType
+		want string
+	}{
+		{t: ovsdb.ClusterLeader, want: "leader"},
+		{t: ovsdb.ClusterFollower, want: "follower"},
+		{t: ovsdb.ClusterDisconnected, want: "disconnected"},
+		{t: ovsdb.ClusterConnected, want: "connected"},
+		{t: 100, want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.t.String()); diff != "" {
+				t.Fatalf("unexpected string (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
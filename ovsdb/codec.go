@@ -0,0 +1,117 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+)
+
+// A Codec encodes and decodes the JSON messages exchanged by a Client and an
+// OVSDB server.  Large monitors spend much of their time decoding JSON, so a
+// Codec may be used to replace package encoding/json with a faster
+// implementation.
+//
+// A Codec must be compatible with package encoding/json: it must respect
+// struct tags, and the json.Marshaler and json.Unmarshaler implementations of
+// types such as json.RawMessage and the types in this package.
+type Codec interface {
+	// NewEncoder returns an Encoder which writes JSON-RPC messages to w.
+	NewEncoder(w io.Writer) Encoder
+
+	// NewDecoder returns a Decoder which reads JSON-RPC messages from r.
+	NewDecoder(r io.Reader) Decoder
+
+	// Unmarshal decodes the JSON in b into v.  It is used to decode the
+	// results of RPCs and the contents of notifications, such as monitor
+	// updates.
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// An Encoder writes JSON values to a stream.  A json.Encoder is an Encoder.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// A Decoder reads JSON values from a stream.  A json.Decoder is a Decoder.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// JSONCodec specifies a Codec which is used to encode and decode messages
+// instead of package encoding/json.
+func JSONCodec(codec Codec) OptionFunc {
+	return func(c *Client) error {
+		c.codec = codec
+		return nil
+	}
+}
+
+// StandardCodec returns a Codec which uses package encoding/json.  It may be
+// used to build Codecs which only replace some of its behavior.
+func StandardCodec() Codec {
+	return stdCodec{}
+}
+
+var _ Codec = stdCodec{}
+
+// A stdCodec is a Codec which uses package encoding/json.
+type stdCodec struct{}
+
+func (stdCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (stdCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+func (stdCodec) Unmarshal(b []byte, v interface{}) error {
+	return json.Unmarshal(b, v)
+}
+
+// An rpcCodec adapts a Codec for use by a jsonrpc.Conn.
+type rpcCodec struct {
+	c Codec
+}
+
+func (c rpcCodec) NewEncoder(w io.Writer) jsonrpc.Encoder {
+	return c.c.NewEncoder(w)
+}
+
+func (c rpcCodec) NewDecoder(r io.Reader) jsonrpc.Decoder {
+	return c.c.NewDecoder(r)
+}
+
+// newConn creates a JSON-RPC connection using the Client's Codec.
+func (c *Client) newConn(conn Transport) *jsonrpc.Conn {
+	var codec jsonrpc.Codec
+	if c.codec != nil {
+		codec = rpcCodec{c: c.codec}
+	}
+
+	return jsonrpc.NewCodecConn(c.deadlines(conn), c.ll, codec)
+}
+
+// unmarshal decodes the JSON in b into v using the Client's Codec.
+func (c *Client) unmarshal(b []byte, v interface{}) error {
+	if c.codec == nil {
+		return json.Unmarshal(b, v)
+	}
+
+	return c.codec.Unmarshal(b, v)
+}
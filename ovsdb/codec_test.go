@@ -0,0 +1,103 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientJSONCodec(t *testing.T) {
+	codec := &countCodec{Codec: ovsdb.StandardCodec()}
+
+	c, done := testDumpServer(t, ovsdb.JSONCodec(codec))
+	defer done()
+
+	ctx := context.Background()
+
+	m, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {Columns: []string{"name"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	res, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br0"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to transact: %v", err)
+	}
+
+	want := ovsdb.TableUpdates{"Bridge": {
+		res[0].UUID: {New: ovsdb.Row{"name": "br0"}},
+	}}
+
+	if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+	}
+
+	// Both RPCs were sent and their results decoded using the codec, along
+	// with the update notification and its contents.
+	if diff := cmp.Diff(int64(2), atomic.LoadInt64(&codec.encodes)); diff != "" {
+		t.Fatalf("unexpected number of encodes (-want +got):\n%s", diff)
+	}
+	if n := atomic.LoadInt64(&codec.decodes); n < 3 {
+		t.Fatalf("expected at least 3 decodes, but got %d", n)
+	}
+	if n := atomic.LoadInt64(&codec.unmarshals); n < 4 {
+		t.Fatalf("expected at least 4 unmarshals, but got %d", n)
+	}
+}
+
+// A countCodec is an ovsdb.Codec which counts its uses.
+type countCodec struct {
+	ovsdb.Codec
+	encodes, decodes, unmarshals int64
+}
+
+func (c *countCodec) NewEncoder(w io.Writer) ovsdb.Encoder {
+	enc := c.Codec.NewEncoder(w)
+	return encoderFunc(func(v interface{}) error {
+		atomic.AddInt64(&c.encodes, 1)
+		return enc.Encode(v)
+	})
+}
+
+func (c *countCodec) NewDecoder(r io.Reader) ovsdb.Decoder {
+	dec := c.Codec.NewDecoder(r)
+	return decoderFunc(func(v interface{}) error {
+		atomic.AddInt64(&c.decodes, 1)
+		return dec.Decode(v)
+	})
+}
+
+func (c *countCodec) Unmarshal(b []byte, v interface{}) error {
+	atomic.AddInt64(&c.unmarshals, 1)
+	return c.Codec.Unmarshal(b, v)
+}
+
+type encoderFunc func(v interface{}) error
+
+func (fn encoderFunc) Encode(v interface{}) error { return fn(v) }
+
+type decoderFunc func(v interface{}) error
+
+func (fn decoderFunc) Decode(v interface{}) error { return fn(v) }
@@ -0,0 +1,153 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// ErrMonitorCanceled is returned by a monitor's Err method when the OVSDB
+// server canceled the monitor because its database was converted to a new
+// schema or removed.
+var ErrMonitorCanceled = errors.New("monitor canceled by OVSDB server")
+
+// Remonitor specifies that monitors canceled by the OVSDB server, because
+// their database was converted to a new schema or removed and added again,
+// are automatically re-established rather than closed.  The server only
+// cancels monitors once SetDBChangeAware has been called with true.
+//
+// Before a monitor is re-established, the Client retrieves the database's
+// new schema, which replaces any schema passed to ValidateTransactions for
+// the database and is used by Caches to decode rows.  The monitor then
+// delivers the full contents of the monitored tables, as after reconnecting,
+// and a Cache replaces its contents.
+//
+// fn is called after each canceled monitor is re-established, with the name
+// of its database and the database's new schema.  If the monitor could not be
+// re-established, for example because the database was removed or a
+// monitored table no longer exists, fn is called with a nil schema and the
+// error, and the monitor is closed with ErrMonitorCanceled as if Remonitor
+// was not used.  fn may be nil.
+func Remonitor(fn func(db string, schema *Schema, err error)) OptionFunc {
+	return func(c *Client) error {
+		c.remonitor = true
+		c.onRemonitor = fn
+		return nil
+	}
+}
+
+// SetDBChangeAware informs the OVSDB server whether the Client is aware of
+// changes to databases, such as conversion to a new schema or removal.
+//
+// By default, the server closes the Client's connection when a monitored
+// database changes.  When aware is true, the server instead cancels each
+// affected monitor, closing its updates channel, and its Err method returns
+// ErrMonitorCanceled.  The monitor may then be established again using the
+// database's new schema, or re-established automatically using the Remonitor
+// option.
+//
+// If the Client reconnects, the setting is requested again before any
+// monitors are re-established.
+func (c *Client) SetDBChangeAware(ctx context.Context, aware bool) error {
+	if err := c.rpc(ctx, "set_db_change_aware", nil, []bool{aware}); err != nil {
+		return err
+	}
+
+	c.changeMu.Lock()
+	defer c.changeMu.Unlock()
+
+	c.changeAware = aware
+	return nil
+}
+
+// isChangeAware reports whether SetDBChangeAware was last called with true.
+func (c *Client) isChangeAware() bool {
+	c.changeMu.Lock()
+	defer c.changeMu.Unlock()
+
+	return c.changeAware
+}
+
+// doMonitorCanceled closes the monitor canceled by a monitor_canceled
+// notification, or re-establishes it if the Remonitor option is used.
+func (c *Client) doMonitorCanceled(ctx context.Context, params json.RawMessage) {
+	// Parameters are [<json-value>].
+	var ids []string
+	if err := c.unmarshal(params, &ids); err != nil || len(ids) != 1 {
+		return
+	}
+	id := ids[0]
+
+	c.monMu.Lock()
+	h, ok := c.monitors[id]
+	if !c.remonitor {
+		delete(c.monitors, id)
+	}
+	c.monMu.Unlock()
+	if !ok {
+		// Not one of our monitors, or it was already canceled.
+		return
+	}
+
+	if !c.remonitor {
+		h.base().setErr(ErrMonitorCanceled)
+		h.close()
+		return
+	}
+
+	// Hold any updates until the monitor is re-established.  Doing so
+	// requires performing RPCs, which requires this goroutine to continue
+	// receiving responses.
+	h.base().startResume()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.remonitorHandler(ctx, id, h)
+	}()
+}
+
+// remonitorHandler re-establishes the monitor h, which was canceled by the
+// server, using its database's new schema.
+func (c *Client) remonitorHandler(ctx context.Context, id string, h monitorHandler) {
+	db := h.database()
+
+	schema, err := c.GetSchema(ctx, db)
+	if err == nil {
+		c.setValidationSchema(schema)
+		err = h.remonitor(ctx, schema)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			// The Client was closed, which closes its monitors.
+			return
+		}
+		schema = nil
+
+		c.monMu.Lock()
+		delete(c.monitors, id)
+		c.monMu.Unlock()
+
+		h.base().setErr(ErrMonitorCanceled)
+		h.close()
+	}
+
+	if c.onRemonitor != nil {
+		c.onRemonitor(db, schema, err)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientMonitorCanceled(t *testing.T) {
+	s, err := ovsdbtest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if err := c.SetDBChangeAware(ctx, true); err != nil {
+		t.Fatalf("failed to set change awareness: %v", err)
+	}
+
+	m, err := c.MonitorCond(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	// A monitor of another database is unaffected.
+	other, err := c.Monitor(ctx, "_Server", map[string]ovsdb.MonitorRequest{
+		"Database": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if err := s.CancelMonitors("Open_vSwitch"); err != nil {
+		t.Fatalf("failed to cancel monitors: %v", err)
+	}
+
+	select {
+	case _, ok := <-m.Updates():
+		if ok {
+			t.Fatal("expected updates channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for monitor to be canceled")
+	}
+
+	if err := m.Err(); err != ovsdb.ErrMonitorCanceled {
+		t.Fatalf("unexpected monitor error: %v", err)
+	}
+
+	if err := other.Cancel(ctx); err != nil {
+		t.Fatalf("failed to cancel monitor: %v", err)
+	}
+
+	if err := other.Err(); err != nil {
+		t.Fatalf("unexpected error for monitor canceled by client: %v", err)
+	}
+}
+
+func TestClientReconnectDBChangeAware(t *testing.T) {
+	rpcC := make(chan string, 16)
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		rpcC <- req.Method
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, struct{}{}),
+		}
+	})
+	defer d.done()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c := d.client(
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if err := c.SetDBChangeAware(ctx, true); err != nil {
+		t.Fatalf("failed to set change awareness: %v", err)
+	}
+
+	if _, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	}); err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	for _, want := range []string{"set_db_change_aware", "monitor"} {
+		if diff := cmp.Diff(want, <-rpcC); diff != "" {
+			t.Fatalf("unexpected initial RPC (-want +got):\n%s", diff)
+		}
+	}
+
+	d.drop()
+
+	for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+		if diff := cmp.Diff(want, <-stateC); diff != "" {
+			t.Fatalf("unexpected state (-want +got):\n%s", diff)
+		}
+	}
+
+	// Change awareness is requested again before the monitor is resumed.
+	for _, want := range []string{"set_db_change_aware", "monitor"} {
+		if diff := cmp.Diff(want, <-rpcC); diff != "" {
+			t.Fatalf("unexpected resumed RPC (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestClientRemonitor(t *testing.T) {
+	s, err := ovsdbtest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close()
+
+	s.AddSchema(testRemonitorSchema(t, "1.0.0"))
+
+	remonC := make(chan remonitorResult, 1)
+	c, err := s.Client(ovsdb.Remonitor(func(db string, schema *ovsdb.Schema, err error) {
+		remonC <- remonitorResult{db: db, schema: schema, err: err}
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if err := c.SetDBChangeAware(ctx, true); err != nil {
+		t.Fatalf("failed to set change awareness: %v", err)
+	}
+
+	m, err := c.MonitorCond(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	// The database is converted to a new schema.
+	s.AddSchema(testRemonitorSchema(t, "2.0.0"))
+	if err := s.CancelMonitors("Open_vSwitch"); err != nil {
+		t.Fatalf("failed to cancel monitors: %v", err)
+	}
+
+	r := waitRemonitor(t, remonC)
+	if r.err != nil {
+		t.Fatalf("failed to re-establish monitor: %v", r.err)
+	}
+
+	if diff := cmp.Diff("Open_vSwitch", r.db); diff != "" {
+		t.Fatalf("unexpected database (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("2.0.0", r.schema.Version); diff != "" {
+		t.Fatalf("unexpected schema version (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(1, s.Monitors()); diff != "" {
+		t.Fatalf("unexpected number of server monitors (-want +got):\n%s", diff)
+	}
+
+	// Updates are delivered using the re-established monitor.
+	want := ovsdb.TableUpdates2{
+		"Bridge": {
+			"a": {Insert: ovsdb.Row{"name": "br0"}},
+		},
+	}
+
+	if err := s.Update2("Open_vSwitch", want, ""); err != nil {
+		t.Fatalf("failed to send update: %v", err)
+	}
+
+	for {
+		select {
+		case got, ok := <-m.Updates():
+			if !ok {
+				t.Fatalf("updates channel closed: %v", m.Err())
+			}
+			if len(got) == 0 {
+				// The monitor's empty initial contents.
+				continue
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+			}
+			return
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for updates")
+		}
+	}
+}
+
+func TestClientRemonitorCache(t *testing.T) {
+	s, err := ovsdbtest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer s.Close()
+
+	s.AddSchema(testRemonitorSchema(t, "1.0.0"))
+
+	remonC := make(chan remonitorResult, 1)
+	c, err := s.Client(ovsdb.Remonitor(func(db string, schema *ovsdb.Schema, err error) {
+		remonC <- remonitorResult{db: db, schema: schema, err: err}
+	}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if err := c.SetDBChangeAware(ctx, true); err != nil {
+		t.Fatalf("failed to set change awareness: %v", err)
+	}
+
+	cache, err := c.MonitorCache(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	type bridge struct {
+		Name string `ovsdb:"name"`
+	}
+
+	w, err := cache.Watch("Bridge", bridge{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer w.Stop()
+
+	// The Cache uses the new schema after the database is converted.
+	s.AddSchema(testRemonitorSchema(t, "2.0.0"))
+	if err := s.CancelMonitors("Open_vSwitch"); err != nil {
+		t.Fatalf("failed to cancel monitors: %v", err)
+	}
+
+	if r := waitRemonitor(t, remonC); r.err != nil {
+		t.Fatalf("failed to re-establish cache: %v", r.err)
+	}
+
+	if diff := cmp.Diff("2.0.0", cache.Schema().Version); diff != "" {
+		t.Fatalf("unexpected cache schema version (-want +got):\n%s", diff)
+	}
+
+	// The cached table is removed from the database, so the Cache can no
+	// longer be updated.
+	removed := testRemonitorSchema(t, "3.0.0")
+	delete(removed.Tables, "Bridge")
+	s.AddSchema(removed)
+
+	if err := s.CancelMonitors("Open_vSwitch"); err != nil {
+		t.Fatalf("failed to cancel monitors: %v", err)
+	}
+
+	r := waitRemonitor(t, remonC)
+	if r.err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+	if r.schema != nil {
+		t.Fatalf("unexpected schema: %v", r.schema)
+	}
+
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Fatal("expected events channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for cache to be closed")
+	}
+
+	// The previous schema is retained.
+	if diff := cmp.Diff("2.0.0", cache.Schema().Version); diff != "" {
+		t.Fatalf("unexpected cache schema version (-want +got):\n%s", diff)
+	}
+}
+
+// A remonitorResult contains the arguments of a Remonitor function.
+type remonitorResult struct {
+	db     string
+	schema *ovsdb.Schema
+	err    error
+}
+
+// waitRemonitor waits for a monitor to be re-established.
+func waitRemonitor(t *testing.T, remonC <-chan remonitorResult) remonitorResult {
+	t.Helper()
+
+	select {
+	case r := <-remonC:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for monitor to be re-established")
+		return remonitorResult{}
+	}
+}
+
+// testRemonitorSchema returns cacheSchema with the specified version.
+func testRemonitorSchema(t *testing.T, version string) *ovsdb.Schema {
+	t.Helper()
+
+	var s ovsdb.Schema
+	if err := json.Unmarshal([]byte(cacheSchema), &s); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	s.Version = version
+	return &s
+}
@@ -0,0 +1,211 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// ReadTimeout sets a read deadline on the Client's connection while it is
+// waiting for the response to an RPC.  If an RPC has been in progress for
+// the duration d, and nothing has been received from the server during that
+// time, the connection is closed as if it were lost, so that an unresponsive server or a half-open
+// TCP connection is detected promptly.  If the Client is configured to
+// reconnect, it then attempts to do so.
+//
+// While no RPCs are in progress, the Client waits for notifications from
+// the server indefinitely.  ReadTimeout has no effect if the connection does
+// not implement SetReadDeadline(time.Time) error, as net.Conn does.
+func ReadTimeout(d time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("read timeout must be greater than zero")
+		}
+
+		c.readTimeout = d
+		return nil
+	}
+}
+
+// WriteTimeout sets a write deadline on the Client's connection for each
+// message sent to the server.  If a message cannot be written within the
+// duration d, such as when the server has stopped reading from the
+// connection, the connection is closed as if it were lost.  If the Client is
+// configured to reconnect, it then attempts to do so.
+//
+// WriteTimeout has no effect if the connection does not implement
+// SetWriteDeadline(time.Time) error, as net.Conn does.
+func WriteTimeout(d time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("write timeout must be greater than zero")
+		}
+
+		c.writeTimeout = d
+		return nil
+	}
+}
+
+// errTimeout is returned when a deadline set by ReadTimeout or WriteTimeout
+// is exceeded.  It is not a net.Error, so the connection is treated as lost.
+var errTimeout = errors.New("timed out waiting for OVSDB server")
+
+// Interfaces implemented by Transports which support deadlines.
+type (
+	readDeadliner interface {
+		SetReadDeadline(t time.Time) error
+	}
+
+	writeDeadliner interface {
+		SetWriteDeadline(t time.Time) error
+	}
+)
+
+// deadlines returns a Transport which applies the Client's read and write
+// timeouts to t, or t itself if no timeouts are configured.
+func (c *Client) deadlines(t Transport) Transport {
+	rd, _ := t.(readDeadliner)
+	wd, _ := t.(writeDeadliner)
+
+	if c.readTimeout == 0 {
+		rd = nil
+	}
+	if c.writeTimeout == 0 {
+		wd = nil
+	}
+
+	if rd == nil && wd == nil {
+		return t
+	}
+
+	return &deadlineTransport{
+		Transport: t,
+		c:         c,
+		rd:        rd,
+		wd:        wd,
+	}
+}
+
+// A deadlineTransport is a Transport which sets a deadline for each read
+// and write.
+type deadlineTransport struct {
+	Transport
+	c  *Client
+	rd readDeadliner
+	wd writeDeadliner
+}
+
+// Read implements io.Reader.
+func (t *deadlineTransport) Read(b []byte) (int, error) {
+	if t.rd == nil {
+		return t.Transport.Read(b)
+	}
+
+	start := time.Now()
+	for {
+		// Wait for the server from the later of the start of this read and
+		// the time the oldest pending RPC was sent, so that an RPC sent
+		// while the connection is idle is allowed the full timeout.
+		from, ok := t.c.waitingSince()
+		if !ok {
+			from = time.Now()
+		} else if from.Before(start) {
+			from = start
+		}
+
+		_ = t.rd.SetReadDeadline(from.Add(t.c.readTimeout))
+
+		n, err := t.Transport.Read(b)
+		if n > 0 || !isTimeout(err) {
+			return n, err
+		}
+
+		sent, ok := t.c.waitingSince()
+		if !ok {
+			// Nothing is expected from the server, so keep waiting.
+			continue
+		}
+		if sent.Before(start) {
+			sent = start
+		}
+
+		if time.Since(sent) >= t.c.readTimeout {
+			// An RPC is in progress, but the server has not replied.
+			_ = t.Transport.Close()
+			return 0, errTimeout
+		}
+
+		// An RPC was sent after the deadline was set, so keep waiting for
+		// its response.
+	}
+}
+
+// Write implements io.Writer.
+func (t *deadlineTransport) Write(b []byte) (int, error) {
+	if t.wd == nil {
+		return t.Transport.Write(b)
+	}
+
+	_ = t.wd.SetWriteDeadline(time.Now().Add(t.c.writeTimeout))
+
+	n, err := t.Transport.Write(b)
+	if isTimeout(err) {
+		// A partial message may have been written.
+		_ = t.Transport.Close()
+		return n, errTimeout
+	}
+
+	return n, err
+}
+
+// SetWriteDeadline implements writeDeadliner, so that sends can still be
+// interrupted by canceling their contexts.
+func (t *deadlineTransport) SetWriteDeadline(d time.Time) error {
+	wd, ok := t.Transport.(writeDeadliner)
+	if !ok {
+		return errors.New("transport does not support write deadlines")
+	}
+
+	return wd.SetWriteDeadline(d)
+}
+
+// isTimeout reports whether err is a network timeout.
+func isTimeout(err error) bool {
+	nerr, ok := err.(net.Error)
+	return ok && nerr.Timeout()
+}
+
+// waitingSince returns the time at which the oldest RPC which is awaiting a
+// response was sent, and reports whether the Client is waiting for the
+// response to any RPC.
+func (c *Client) waitingSince() (time.Time, bool) {
+	c.cbMu.RLock()
+	defer c.cbMu.RUnlock()
+
+	var (
+		oldest time.Time
+		ok     bool
+	)
+
+	for _, cb := range c.callbacks {
+		if !ok || cb.Sent.Before(oldest) {
+			oldest, ok = cb.Sent, true
+		}
+	}
+
+	return oldest, ok
+}
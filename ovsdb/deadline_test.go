@@ -0,0 +1,128 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientReadTimeout(t *testing.T) {
+	// The server never replies.
+	unblock := make(chan struct{})
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		<-unblock
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	}, ovsdb.ReadTimeout(50*time.Millisecond))
+	defer done()
+	defer close(unblock)
+
+	_, err := c.ListDatabases(context.Background())
+	if diff := cmp.Diff(ovsdb.ErrDisconnected, err, cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})); diff != "" {
+		t.Fatalf("unexpected error (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(ovsdb.StateDisconnected, c.State()); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientReadTimeoutIdle(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	}, ovsdb.ReadTimeout(10*time.Millisecond))
+	defer done()
+
+	// The connection remains open while no RPCs are in progress.
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := c.ListDatabases(context.Background()); err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.StateConnected, c.State()); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientReadTimeoutLateRPC(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		// Reply after the idle read deadline has passed, but within the
+		// timeout of the RPC.
+		time.Sleep(40 * time.Millisecond)
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	}, ovsdb.ReadTimeout(100*time.Millisecond))
+	defer done()
+
+	// Send the RPC just before the idle read deadline expires.
+	time.Sleep(90 * time.Millisecond)
+
+	if _, err := c.ListDatabases(context.Background()); err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.StateConnected, c.State()); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientWriteTimeout(t *testing.T) {
+	// The server never reads from its connection.
+	client, server := net.Pipe()
+	defer server.Close()
+
+	c, err := ovsdb.New(client, ovsdb.WriteTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.ListDatabases(context.Background()); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestClientTimeoutInvalid(t *testing.T) {
+	for _, o := range []ovsdb.OptionFunc{
+		ovsdb.ReadTimeout(0),
+		ovsdb.WriteTimeout(-1),
+	} {
+		conn, _, done := jsonrpc.TestNetConn(t, nil)
+		if _, err := ovsdb.New(conn, o); err == nil {
+			t.Fatal("expected an error, but none occurred")
+		}
+		done()
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import "reflect"
+
+// ApplyRowDiff applies the changes in diff to a copy of row, as described
+// for RowUpdate2.Modify, and returns the result.  The types of the columns of
+// row and diff are determined using ts.
+//
+// Set columns in diff contain the elements which were added to or removed
+// from the column.  Map columns in diff contain the pairs which were added
+// or removed, and the new values of keys whose values changed.  Other
+// columns contain their new value.
+func ApplyRowDiff(ts TableSchema, row, diff Row) Row {
+	out := make(Row, len(row)+len(diff))
+	for column, v := range row {
+		out[column] = v
+	}
+
+	for column, v := range diff {
+		nv := columnValue(ts, column, v)
+
+		switch d := nv.(type) {
+		case Set:
+			old, _ := columnValue(ts, column, out[column]).(Set)
+			out[column] = ApplySetDiff(old, d)
+		case Map:
+			old, _ := columnValue(ts, column, out[column]).(Map)
+			out[column] = ApplyMapDiff(old, d)
+		default:
+			out[column] = nv
+		}
+	}
+
+	return out
+}
+
+// DiffRows returns the changes required to transform old into new, in the
+// form used by RowUpdate2.Modify and accepted by ApplyRowDiff.  Only the
+// columns of new which differ from old are included.  If no columns differ,
+// DiffRows returns nil.
+func DiffRows(ts TableSchema, old, new Row) Row {
+	var diff Row
+	for column, v := range new {
+		o := columnValue(ts, column, old[column])
+		n := columnValue(ts, column, v)
+
+		var d interface{}
+		switch n := n.(type) {
+		case Set:
+			os, _ := o.(Set)
+			if s := DiffSets(os, n); len(s) > 0 {
+				d = s
+			}
+		case Map:
+			om, _ := o.(Map)
+			if m := DiffMaps(om, n); len(m) > 0 {
+				d = m
+			}
+		default:
+			if !reflect.DeepEqual(o, n) {
+				d = n
+			}
+		}
+
+		if d == nil {
+			continue
+		}
+
+		if diff == nil {
+			diff = make(Row)
+		}
+		diff[column] = d
+	}
+
+	return diff
+}
+
+// ApplySetDiff returns the elements of s which are not in diff, followed by
+// the elements of diff which are not in s.
+func ApplySetDiff(s, diff Set) Set {
+	in := make(map[interface{}]bool, len(diff))
+	for _, e := range diff {
+		in[e] = true
+	}
+
+	out := make(Set, 0, len(s)+len(diff))
+	for _, e := range s {
+		if in[e] {
+			// Removed.
+			delete(in, e)
+			continue
+		}
+
+		out = append(out, e)
+	}
+
+	for _, e := range diff {
+		if in[e] {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// ApplyMapDiff applies diff to a copy of m.  Keys which are not in m are
+// added, keys with the same value in m are removed, and keys with a
+// different value in m are updated.
+func ApplyMapDiff(m, diff Map) Map {
+	out := make(Map, len(m)+len(diff))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	for k, v := range diff {
+		if old, ok := out[k]; ok && old == v {
+			delete(out, k)
+			continue
+		}
+
+		out[k] = v
+	}
+
+	return out
+}
+
+// DiffSets returns the elements of old which are not in new, followed by the
+// elements of new which are not in old.  The result may be passed to
+// ApplySetDiff to transform old into new.
+func DiffSets(old, new Set) Set {
+	in := func(s Set) map[interface{}]bool {
+		m := make(map[interface{}]bool, len(s))
+		for _, e := range s {
+			m[e] = true
+		}
+		return m
+	}
+	inOld, inNew := in(old), in(new)
+
+	out := make(Set, 0, len(old)+len(new))
+	for _, e := range old {
+		if !inNew[e] {
+			out = append(out, e)
+		}
+	}
+	for _, e := range new {
+		if !inOld[e] {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// DiffMaps returns the pairs of old whose keys are not in new, and the pairs
+// of new whose keys are not in old or whose values differ from old.  The
+// result may be passed to ApplyMapDiff to transform old into new.
+func DiffMaps(old, new Map) Map {
+	out := make(Map)
+	for k, v := range old {
+		if _, ok := new[k]; !ok {
+			out[k] = v
+		}
+	}
+	for k, v := range new {
+		if o, ok := old[k]; !ok || o != v {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// columnValue converts a column value decoded from JSON according to the
+// column's type in ts.  Values which do not match the column's type, or
+// which are in columns not described by ts, are returned unmodified.
+func columnValue(ts TableSchema, column string, v interface{}) interface{} {
+	cs, ok := ts.Columns[column]
+	if !ok || v == nil {
+		return v
+	}
+
+	var (
+		out interface{}
+		err error
+	)
+
+	switch typ := cs.Type; {
+	case typ.IsMap():
+		// The parsed Map may share storage with v, so convert its atoms
+		// into a new Map.
+		var m Map
+		m, err = ParseMap(v)
+		rm := make(Map, len(m))
+		for k, e := range m {
+			rm[realAtom(typ.Key, k)] = realAtom(*typ.Value, e)
+		}
+		out = rm
+	case typ.Min == 1 && typ.Max == 1:
+		out, err = decodeAtom(v)
+		out = realAtom(typ.Key, out)
+	default:
+		var s Set
+		s, err = ParseSet(v)
+		rs := make(Set, 0, len(s))
+		for _, e := range s {
+			rs = append(rs, realAtom(typ.Key, e))
+		}
+		out = rs
+	}
+
+	if err != nil {
+		return v
+	}
+
+	return out
+}
+
+// realAtom converts an integer atom v to a float64 if typ is TypeReal, since
+// reals without a fractional part are decoded from JSON as int64s.
+func realAtom(typ BaseType, v interface{}) interface{} {
+	if i, ok := v.(int64); ok && typ.Type == TypeReal {
+		return float64(i)
+	}
+
+	return v
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyRowDiff(t *testing.T) {
+	ts := mustSchema(t, validateSchema).Tables["Bridge"]
+
+	tests := []struct {
+		name      string
+		row, diff ovsdb.Row
+		want      ovsdb.Row
+	}{
+		{
+			name: "scalar",
+			row:  ovsdb.Row{"name": "br0", "stp_enable": false},
+			diff: ovsdb.Row{"stp_enable": true},
+			want: ovsdb.Row{"name": "br0", "stp_enable": true},
+		},
+		{
+			name: "set",
+			row: ovsdb.Row{
+				"ports": []interface{}{"set", []interface{}{
+					[]interface{}{"uuid", "a"},
+					[]interface{}{"uuid", "b"},
+				}},
+			},
+			diff: ovsdb.Row{
+				"ports": []interface{}{"set", []interface{}{
+					[]interface{}{"uuid", "a"},
+					[]interface{}{"uuid", "c"},
+				}},
+			},
+			want: ovsdb.Row{
+				"ports": ovsdb.Set{ovsdb.UUID("b"), ovsdb.UUID("c")},
+			},
+		},
+		{
+			name: "optional",
+			row:  ovsdb.Row{"fail_mode": "secure"},
+			diff: ovsdb.Row{"fail_mode": []interface{}{"set", []interface{}{"secure", "standalone"}}},
+			want: ovsdb.Row{"fail_mode": ovsdb.Set{"standalone"}},
+		},
+		{
+			name: "map",
+			row: ovsdb.Row{
+				"external_ids": ovsdb.Map{"a": "1", "b": "2"},
+			},
+			diff: ovsdb.Row{
+				"external_ids": []interface{}{"map", []interface{}{
+					[]interface{}{"a", "1"},
+					[]interface{}{"b", "3"},
+					[]interface{}{"c", "4"},
+				}},
+			},
+			want: ovsdb.Row{
+				"external_ids": ovsdb.Map{"b": "3", "c": "4"},
+			},
+		},
+		{
+			name: "new column",
+			row:  ovsdb.Row{"name": "br0"},
+			diff: ovsdb.Row{"ports": []interface{}{"uuid", "a"}},
+			want: ovsdb.Row{
+				"name":  "br0",
+				"ports": ovsdb.Set{ovsdb.UUID("a")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ovsdb.ApplyRowDiff(ts, tt.row, tt.diff)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("unexpected row (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffRows(t *testing.T) {
+	ts := mustSchema(t, validateSchema).Tables["Bridge"]
+
+	tests := []struct {
+		name     string
+		old, new ovsdb.Row
+		want     ovsdb.Row
+	}{
+		{
+			name: "unchanged",
+			old: ovsdb.Row{
+				"name":  "br0",
+				"ports": ovsdb.Set{ovsdb.UUID("a"), ovsdb.UUID("b")},
+			},
+			new: ovsdb.Row{
+				"name":  "br0",
+				"ports": ovsdb.Set{ovsdb.UUID("b"), ovsdb.UUID("a")},
+			},
+		},
+		{
+			name: "scalar",
+			old:  ovsdb.Row{"name": "br0", "stp_enable": false},
+			new:  ovsdb.Row{"name": "br0", "stp_enable": true},
+			want: ovsdb.Row{"stp_enable": true},
+		},
+		{
+			name: "set",
+			old:  ovsdb.Row{"ports": ovsdb.Set{ovsdb.UUID("a"), ovsdb.UUID("b")}},
+			new:  ovsdb.Row{"ports": ovsdb.Set{ovsdb.UUID("b"), ovsdb.UUID("c")}},
+			want: ovsdb.Row{"ports": ovsdb.Set{ovsdb.UUID("a"), ovsdb.UUID("c")}},
+		},
+		{
+			name: "optional",
+			old:  ovsdb.Row{},
+			new:  ovsdb.Row{"fail_mode": ovsdb.Set{"secure"}},
+			want: ovsdb.Row{"fail_mode": ovsdb.Set{"secure"}},
+		},
+		{
+			name: "map",
+			old:  ovsdb.Row{"external_ids": ovsdb.Map{"a": "1", "b": "2"}},
+			new:  ovsdb.Row{"external_ids": ovsdb.Map{"b": "3", "c": "4"}},
+			want: ovsdb.Row{"external_ids": ovsdb.Map{"a": "1", "b": "3", "c": "4"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ovsdb.DiffRows(ts, tt.old, tt.new)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("unexpected diff (-want +got):\n%s", diff)
+			}
+
+			if got == nil {
+				return
+			}
+
+			// Applying the diff to the old row must produce the new row.
+			row := ovsdb.ApplyRowDiff(ts, tt.old, got)
+			for column, v := range tt.new {
+				if diff := cmp.Diff(v, row[column]); diff != "" {
+					t.Fatalf("unexpected %q after applying diff (-want +got):\n%s",
+						column, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Dump is a snapshot of the contents of one or more tables of a database.
+// Dumps are created using Client.Dump, and can be encoded to and decoded
+// from JSON for storage.
+type Dump struct {
+	// The name of the database.
+	Database string `json:"database"`
+
+	// The rows of each table, keyed by table name and row UUID.  The rows
+	// do not contain the _uuid and _version columns.
+	Tables map[string]map[string]Row `json:"tables"`
+}
+
+// Dump retrieves the contents of the specified tables of a database in a
+// single transaction, so that the Dump is consistent.  If no tables are
+// specified, all tables in the database's schema are dumped.
+func (c *Client) Dump(ctx context.Context, db string, tables ...string) (*Dump, error) {
+	if len(tables) == 0 {
+		schema, err := c.GetSchema(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range schema.Tables {
+			tables = append(tables, name)
+		}
+		sort.Strings(tables)
+	}
+
+	ops := make([]TransactOp, 0, len(tables))
+	for _, table := range tables {
+		ops = append(ops, Select{Table: table})
+	}
+
+	res, err := c.Transact(ctx, db, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dump{
+		Database: db,
+		Tables:   make(map[string]map[string]Row, len(tables)),
+	}
+
+	for i, table := range tables {
+		rows := make(map[string]Row, len(res[i].Rows))
+		for _, row := range res[i].Rows {
+			uuid, err := ParseUUID(row["_uuid"])
+			if err != nil {
+				return nil, fmt.Errorf("table %q: invalid row UUID: %v", table, err)
+			}
+
+			r := make(Row, len(row))
+			for column, v := range row {
+				if column != "_uuid" && column != "_version" {
+					r[column] = v
+				}
+			}
+
+			rows[string(uuid)] = r
+		}
+
+		d.Tables[table] = rows
+	}
+
+	return d, nil
+}
+
+// Restore replaces the contents of the tables in a Dump with the rows of the
+// Dump, in a single transaction.  The existing rows of each table in the
+// Dump are deleted, and the rows of the Dump are inserted.
+//
+// The OVSDB server assigns new UUIDs to the inserted rows, so references
+// between rows in the Dump are updated to refer to the new rows.  References
+// to rows in tables which are not in the Dump are unchanged.  Restore
+// returns the new UUID of each row, keyed by its UUID in the Dump.
+func (c *Client) Restore(ctx context.Context, d *Dump) (map[UUID]UUID, error) {
+	schema, err := c.GetSchema(ctx, d.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0, len(d.Tables))
+	names := make(map[UUID]NamedUUID)
+	for table, rows := range d.Tables {
+		if _, ok := schema.Tables[table]; !ok {
+			return nil, fmt.Errorf("table %q not found in database %q", table, d.Database)
+		}
+
+		tables = append(tables, table)
+		for uuid := range rows {
+			names[UUID(uuid)] = dumpName(uuid)
+		}
+	}
+	sort.Strings(tables)
+
+	ops := make([]TransactOp, 0, len(tables)+len(names))
+	for _, table := range tables {
+		ops = append(ops, Delete{Table: table})
+	}
+
+	// The UUIDs of the inserted rows, in the order of their operations.
+	uuids := make([]UUID, 0, len(names))
+	for _, table := range tables {
+		ts := schema.Tables[table]
+
+		rows := d.Tables[table]
+		keys := make([]string, 0, len(rows))
+		for uuid := range rows {
+			keys = append(keys, uuid)
+		}
+		sort.Strings(keys)
+
+		for _, uuid := range keys {
+			row, err := restoreRow(ts, rows[uuid], names)
+			if err != nil {
+				return nil, fmt.Errorf("table %q, row %s: %v", table, uuid, err)
+			}
+
+			ops = append(ops, Insert{
+				Table:    table,
+				UUIDName: string(names[UUID(uuid)]),
+				Row:      row,
+			})
+			uuids = append(uuids, UUID(uuid))
+		}
+	}
+
+	res, err := c.Transact(ctx, d.Database, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[UUID]UUID, len(uuids))
+	for i, uuid := range uuids {
+		out[uuid] = UUID(res[len(tables)+i].UUID)
+	}
+
+	return out, nil
+}
+
+// dumpName returns the NamedUUID used to insert the row with the specified
+// UUID in a Dump.
+func dumpName(uuid string) NamedUUID {
+	return NamedUUID("row_" + strings.Replace(uuid, "-", "_", -1))
+}
+
+// restoreRow converts the column values of a row in a Dump according to
+// their types, replacing references to rows in the Dump with NamedUUIDs.
+func restoreRow(ts TableSchema, row Row, names map[UUID]NamedUUID) (Row, error) {
+	ref := func(atom interface{}) interface{} {
+		if uuid, ok := atom.(UUID); ok {
+			if name, ok := names[uuid]; ok {
+				return name
+			}
+		}
+
+		return atom
+	}
+
+	out := make(Row, len(row))
+	for column, v := range row {
+		cs, ok := ts.Columns[column]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+
+		if cs.Type.IsMap() {
+			m, err := ParseMap(v)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %v", column, err)
+			}
+
+			nm := make(Map, len(m))
+			for k, e := range m {
+				nm[ref(k)] = ref(e)
+			}
+
+			out[column] = nm
+			continue
+		}
+
+		s, err := ParseSet(v)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %v", column, err)
+		}
+
+		ns := make(Set, 0, len(s))
+		for _, e := range s {
+			ns = append(ns, ref(e))
+		}
+
+		if cs.Type.Min == 1 && cs.Type.Max == 1 && len(ns) == 1 {
+			out[column] = ns[0]
+			continue
+		}
+
+		out[column] = ns
+	}
+
+	return out, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbserver"
+	"github.com/google/go-cmp/cmp"
+)
+
+// dumpSchema is the schema of the database used in dump tests.
+const dumpSchema = `{
+  "name": "Open_vSwitch",
+  "version": "1.0.0",
+  "tables": {
+    "Bridge": {
+      "columns": {
+        "name": {"type": "string"},
+        "ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+        "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+      },
+      "isRoot": true
+    },
+    "Port": {
+      "columns": {
+        "name": {"type": "string"},
+        "tag": {"type": {"key": "integer", "min": 0, "max": 1}}
+      }
+    }
+  }
+}`
+
+func TestClientDumpRestore(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	ctx := context.Background()
+
+	res, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: "port0",
+			Row: ovsdb.Row{
+				"name": "port0",
+				"tag":  10,
+			},
+		},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row: ovsdb.Row{
+				"name":         "br0",
+				"ports":        ovsdb.Set{ovsdb.NamedUUID("port0")},
+				"external_ids": ovsdb.Map{"foo": "bar"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+	port, br := res[0].UUID, res[1].UUID
+
+	d, err := c.Dump(ctx, "Open_vSwitch")
+	if err != nil {
+		t.Fatalf("failed to dump database: %v", err)
+	}
+
+	want := &ovsdb.Dump{
+		Database: "Open_vSwitch",
+		Tables: map[string]map[string]ovsdb.Row{
+			"Bridge": {
+				br: {
+					"name":         "br0",
+					"ports":        []interface{}{"set", []interface{}{[]interface{}{"uuid", port}}},
+					"external_ids": []interface{}{"map", []interface{}{[]interface{}{"foo", "bar"}}},
+				},
+			},
+			"Port": {
+				port: {
+					"name": "port0",
+					"tag":  []interface{}{"set", []interface{}{int64(10)}},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, d); diff != "" {
+		t.Fatalf("unexpected dump (-want +got):\n%s", diff)
+	}
+
+	// Dumps must survive a round trip through JSON.
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("failed to marshal dump: %v", err)
+	}
+
+	var restore ovsdb.Dump
+	if err := json.Unmarshal(b, &restore); err != nil {
+		t.Fatalf("failed to unmarshal dump: %v", err)
+	}
+
+	// Replace the contents of the database before restoring it.
+	_, err = c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Delete{Table: "Bridge"},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to replace rows: %v", err)
+	}
+
+	uuids, err := c.Restore(ctx, &restore)
+	if err != nil {
+		t.Fatalf("failed to restore dump: %v", err)
+	}
+
+	got, err := c.Dump(ctx, "Open_vSwitch", "Bridge")
+	if err != nil {
+		t.Fatalf("failed to dump bridges: %v", err)
+	}
+
+	newPort, newBr := string(uuids[ovsdb.UUID(port)]), string(uuids[ovsdb.UUID(br)])
+	want = &ovsdb.Dump{
+		Database: "Open_vSwitch",
+		Tables: map[string]map[string]ovsdb.Row{
+			"Bridge": {
+				newBr: {
+					"name":         "br0",
+					"ports":        []interface{}{"set", []interface{}{[]interface{}{"uuid", newPort}}},
+					"external_ids": []interface{}{"map", []interface{}{[]interface{}{"foo", "bar"}}},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected restored dump (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientRestoreUnknownTable(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	_, err := c.Restore(context.Background(), &ovsdb.Dump{
+		Database: "Open_vSwitch",
+		Tables: map[string]map[string]ovsdb.Row{
+			"foo": {},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// testDumpServer creates an in-memory OVSDB server using dumpSchema, and a
+// Client connected to it.  done must be called to clean up both.
+func testDumpServer(t *testing.T, options ...ovsdb.OptionFunc) (*ovsdb.Client, func()) {
+	t.Helper()
+
+	var schema ovsdb.Schema
+	if err := json.Unmarshal([]byte(dumpSchema), &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	s, err := ovsdbserver.New(&schema)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	c, err := ovsdb.Dial("tcp", l.Addr().String(), options...)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	return c, func() {
+		_ = c.Close()
+		_ = s.Close()
+	}
+}
@@ -16,8 +16,10 @@ package ovsdb_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/digitalocean/go-openvswitch/ovsdb"
@@ -48,3 +50,212 @@ func ExampleClient_listDatabases() {
 		fmt.Println(d)
 	}
 }
+
+// This example demonstrates dialing an OVSDB server using the same remote
+// strings accepted by ovs-vsctl and other OVSDB clients.
+func ExampleDialRemote() {
+	// Remotes such as "tcp:127.0.0.1:6640" are also supported.  "ssl:"
+	// remotes require the TLSConfig option.  A comma-separated list of
+	// remotes may be used to fail over between servers.
+	c, err := ovsdb.DialRemote("unix:/var/run/openvswitch/db.sock")
+	if err != nil {
+		log.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	dbs, err := c.ListDatabases(ctx)
+	if err != nil {
+		log.Fatalf("failed to list databases: %v", err)
+	}
+
+	for _, d := range dbs {
+		fmt.Println(d)
+	}
+}
+
+// This example demonstrates accepting connections from OVSDB servers which
+// are configured to connect to a manager, such as by using
+// "ovs-vsctl set-manager tcp:10.0.0.1:6640".
+func ExampleListenRemote() {
+	l, err := ovsdb.ListenRemote("ptcp:6640")
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			log.Fatalf("failed to accept: %v", err)
+		}
+
+		go func() {
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			dbs, err := c.ListDatabases(ctx)
+			if err != nil {
+				log.Printf("failed to list databases: %v", err)
+				return
+			}
+
+			fmt.Println(dbs)
+		}()
+	}
+}
+
+// This example demonstrates using struct tags to map the rows returned by a
+// select operation to Go structs.
+func ExampleUnmarshalRow() {
+	c, err := ovsdb.Dial("unix", "/var/run/openvswitch/db.sock")
+	if err != nil {
+		log.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+	})
+	if err != nil {
+		log.Fatalf("failed to select bridges: %v", err)
+	}
+
+	type bridge struct {
+		UUID     ovsdb.UUID   `ovsdb:"_uuid"`
+		Name     string       `ovsdb:"name"`
+		FailMode *string      `ovsdb:"fail_mode"`
+		Ports    []ovsdb.UUID `ovsdb:"ports"`
+	}
+
+	for _, row := range res[0].Rows {
+		var b bridge
+		if err := ovsdb.UnmarshalRow(row, &b); err != nil {
+			log.Fatalf("failed to unmarshal bridge: %v", err)
+		}
+
+		fmt.Printf("%s: %d ports\n", b.Name, len(b.Ports))
+	}
+}
+
+// This example demonstrates backing up the contents of a database to a JSON
+// file, which can later be decoded and passed to Client.Restore.
+func ExampleClient_Dump() {
+	c, err := ovsdb.Dial("unix", "/var/run/openvswitch/db.sock")
+	if err != nil {
+		log.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	d, err := c.Dump(ctx, "Open_vSwitch")
+	if err != nil {
+		log.Fatalf("failed to dump database: %v", err)
+	}
+
+	f, err := os.Create("Open_vSwitch.json")
+	if err != nil {
+		log.Fatalf("failed to create backup: %v", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(d); err != nil {
+		log.Fatalf("failed to write backup: %v", err)
+	}
+}
+
+// This example demonstrates restoring a Cache from a snapshot saved by a
+// previous process, so that only the changes made since the snapshot are
+// downloaded, and saving a new snapshot before exiting.
+func ExampleClient_MonitorCacheSince() {
+	c, err := ovsdb.Dial("unix", "/var/run/openvswitch/db.sock")
+	if err != nil {
+		log.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	// A missing snapshot is not an error: the full contents of the
+	// monitored tables are downloaded instead.
+	var snap *ovsdb.CacheSnapshot
+	if f, err := os.Open("cache.json"); err == nil {
+		snap = new(ovsdb.CacheSnapshot)
+		if err := json.NewDecoder(f).Decode(snap); err != nil {
+			log.Fatalf("failed to read snapshot: %v", err)
+		}
+		_ = f.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cache, err := c.MonitorCacheSince(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, nil, snap)
+	if err != nil {
+		log.Fatalf("failed to create cache: %v", err)
+	}
+
+	fmt.Printf("%d bridges\n", len(cache.Rows("Bridge")))
+
+	f, err := os.Create("cache.json")
+	if err != nil {
+		log.Fatalf("failed to create snapshot: %v", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(cache.Snapshot()); err != nil {
+		log.Fatalf("failed to write snapshot: %v", err)
+	}
+}
+
+// This example demonstrates using a Router to perform transactions using the
+// leader of an OVN Southbound cluster, while monitoring the database using an
+// OVSDB relay.
+func ExampleRouter() {
+	leader, err := ovsdb.DialRemote("tcp:10.0.0.1:6642",
+		ovsdb.LeaderOnly("OVN_Southbound"),
+	)
+	if err != nil {
+		log.Fatalf("failed to dial leader: %v", err)
+	}
+
+	replica, err := ovsdb.DialRemote("tcp:10.0.0.10:6642")
+	if err != nil {
+		log.Fatalf("failed to dial relay: %v", err)
+	}
+
+	r := ovsdb.NewRouter(leader, replica)
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// The monitor is created using the relay.
+	cache, err := r.MonitorCache(ctx, "OVN_Southbound", map[string]ovsdb.MonitorCondRequest{
+		"Chassis": {},
+	}, nil)
+	if err != nil {
+		log.Fatalf("failed to create cache: %v", err)
+	}
+
+	// The transaction is performed by the leader.
+	if _, err := r.Transact(ctx, "OVN_Southbound", []ovsdb.TransactOp{
+		ovsdb.Delete{
+			Table: "Chassis",
+			Where: []ovsdb.Cond{ovsdb.Equal("name", "stale")},
+		},
+	}); err != nil {
+		log.Fatalf("failed to delete chassis: %v", err)
+	}
+
+	fmt.Printf("%d chassis\n", len(cache.Rows("Chassis")))
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Health describes the health of a Client's connection to an OVSDB server.
+//
+// A Client which is in StateConnected, but which has not received anything
+// from the server or completed an echo RPC recently, may be connected to a
+// server which is no longer responsive.
+type Health struct {
+	// The current state of the Client's connection.
+	State ConnState
+
+	// The time at which a message was last received from the server.
+	LastReceived time.Time
+
+	// The times at which an echo RPC last succeeded and failed, or zero if
+	// none has.  Echo RPCs sent by Echo, Ping, the EchoInterval option, and
+	// the InactivityProbe option are all included.
+	LastEcho, LastEchoFailure time.Time
+
+	// The round trip time of the last successful echo RPC.
+	EchoRoundTrip time.Duration
+}
+
+// Ping sends an echo RPC to the OVSDB server like Echo, and returns its round
+// trip time.  The result is also reported by Health.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	return c.echo(ctx)
+}
+
+// Health returns the current Health of the Client's connection.  Health
+// performs no RPCs: use Ping to check that the server is responsive.
+func (c *Client) Health() Health {
+	return Health{
+		State:           c.State(),
+		LastReceived:    unixTime(atomic.LoadInt64(&c.lastRecv)),
+		LastEcho:        unixTime(atomic.LoadInt64(&c.lastEchoOK)),
+		LastEchoFailure: unixTime(atomic.LoadInt64(&c.lastEchoFail)),
+		EchoRoundTrip:   time.Duration(atomic.LoadInt64(&c.echoRTT)),
+	}
+}
+
+// echoed records the result of an echo RPC with round trip time rtt.
+func (c *Client) echoed(rtt time.Duration, err error) {
+	now := time.Now().UnixNano()
+	if err != nil {
+		atomic.StoreInt64(&c.lastEchoFail, now)
+		return
+	}
+
+	atomic.StoreInt64(&c.echoRTT, int64(rtt))
+	atomic.StoreInt64(&c.lastEchoOK, now)
+}
+
+// unixTime converts Unix nanoseconds to a time.Time, or the zero time if n
+// is zero.
+func unixTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, n)
+}
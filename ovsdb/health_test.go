@@ -0,0 +1,93 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientPingHealth(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("echo", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, req.Params),
+		}
+	})
+	defer done()
+
+	h := c.Health()
+	if diff := cmp.Diff(ovsdb.StateConnected, h.State); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+
+	if !h.LastEcho.IsZero() || !h.LastEchoFailure.IsZero() {
+		t.Fatalf("unexpected echo times before ping: %+v", h)
+	}
+
+	start := time.Now()
+
+	rtt, err := c.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	h = c.Health()
+	if h.LastEcho.Before(start) || !h.LastEchoFailure.IsZero() {
+		t.Fatalf("unexpected echo times after ping: %+v", h)
+	}
+
+	if h.LastReceived.Before(start) {
+		t.Fatalf("unexpected last received time: %v", h.LastReceived)
+	}
+
+	if diff := cmp.Diff(rtt, h.EchoRoundTrip); diff != "" {
+		t.Fatalf("unexpected round trip time (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientPingError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"foo"}),
+		}
+	})
+	defer done()
+
+	start := time.Now()
+
+	if _, err := c.Ping(context.Background()); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	h := c.Health()
+	if !h.LastEcho.IsZero() || h.LastEchoFailure.Before(start) {
+		t.Fatalf("unexpected echo times after failed ping: %+v", h)
+	}
+
+	if diff := cmp.Diff(time.Duration(0), h.EchoRoundTrip); diff != "" {
+		t.Fatalf("unexpected round trip time (-want +got):\n%s", diff)
+	}
+}
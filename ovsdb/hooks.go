@@ -0,0 +1,80 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ClientHooks are functions which a Client invokes to report on its
+// operation, such as to record metrics with Prometheus or statsd.  Nil hooks
+// are ignored.
+//
+// Hooks are invoked synchronously from the Client's internal goroutines, and
+// must return promptly.
+type ClientHooks struct {
+	// RPC is invoked when an RPC completes, with the RPC's method, the time
+	// taken for it to complete, and any error which occurred.
+	RPC func(method string, latency time.Duration, err error)
+
+	// Reconnect is invoked each time the Client establishes a new
+	// connection after its connection was lost.
+	Reconnect func()
+
+	// Update is invoked each time an update notification is queued for
+	// delivery to a monitor, with the monitor's ID and the number of
+	// updates waiting to be sent on the monitor's updates channel.
+	Update func(id string, queued int)
+}
+
+// Hooks specifies functions which a Client invokes to report on its
+// operation.
+func Hooks(h ClientHooks) OptionFunc {
+	return func(c *Client) error {
+		c.hooks = h
+		return nil
+	}
+}
+
+// rpcDone records the completion of an RPC which began at start.
+func (c *Client) rpcDone(method string, start time.Time, err error) {
+	if err != nil {
+		atomic.AddInt64(&c.rpcFail, 1)
+	} else {
+		atomic.AddInt64(&c.rpcOK, 1)
+	}
+
+	if fn := c.hooks.RPC; fn != nil {
+		fn(method, time.Since(start), err)
+	}
+}
+
+// reconnected records that the Client established a new connection.
+func (c *Client) reconnected() {
+	atomic.AddInt64(&c.reconnects, 1)
+
+	if fn := c.hooks.Reconnect; fn != nil {
+		fn()
+	}
+}
+
+// queued records that an update was queued for the monitor with the
+// specified ID.
+func (c *Client) queued(id string, n int) {
+	if fn := c.hooks.Update; fn != nil {
+		fn(id, n)
+	}
+}
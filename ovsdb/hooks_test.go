@@ -0,0 +1,192 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientHooksRPC(t *testing.T) {
+	type rpc struct {
+		Method string
+		OK     bool
+	}
+
+	var (
+		mu   sync.Mutex
+		rpcs []rpc
+	)
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if req.Method == "echo" {
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, &ovsdb.Error{Err: "bad echo"}),
+			}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	}, ovsdb.Hooks(ovsdb.ClientHooks{
+		RPC: func(method string, latency time.Duration, err error) {
+			if latency <= 0 {
+				panicf("invalid latency: %v", latency)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			rpcs = append(rpcs, rpc{Method: method, OK: err == nil})
+		},
+	}))
+	defer done()
+
+	ctx := context.Background()
+
+	if _, err := c.ListDatabases(ctx); err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if err := c.Echo(ctx); err == nil {
+		t.Fatal("expected an echo error, but none occurred")
+	}
+
+	want := []rpc{
+		{Method: "list_dbs", OK: true},
+		{Method: "echo", OK: false},
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if diff := cmp.Diff(want, rpcs); diff != "" {
+		t.Fatalf("unexpected RPCs (-want +got):\n%s", diff)
+	}
+
+	stats := c.Stats()
+
+	if diff := cmp.Diff(1, stats.RPCs.Success); diff != "" {
+		t.Fatalf("unexpected successful RPCs (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(1, stats.RPCs.Failure); diff != "" {
+		t.Fatalf("unexpected failed RPCs (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientHooksUpdate(t *testing.T) {
+	const n = monitorBufferSize + 4
+
+	idC := make(chan string, 1)
+	queuedC := make(chan int, n)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		idC <- req.Params.([]interface{})[1].(string)
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, ovsdb.TableUpdates{}),
+		}
+	}, ovsdb.Hooks(ovsdb.ClientHooks{
+		Update: func(_ string, queued int) {
+			queuedC <- queued
+		},
+	}))
+	defer done()
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	id := <-idC
+
+	// Fill the updates channel without consuming any updates, so that the
+	// remaining updates are queued.
+	for i := 0; i < n; i++ {
+		notifC <- &jsonrpc.Response{
+			Method: "update",
+			Params: mustMarshalJSON(t, []interface{}{id, ovsdb.TableUpdates{}}),
+		}
+
+		<-queuedC
+	}
+
+	timeout := time.After(2 * time.Second)
+	for {
+		// One update is held by the goroutine waiting to deliver it.
+		got := c.Stats().Monitors.Queued
+		if got == n-monitorBufferSize-1 {
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("unexpected number of queued updates: %d", got)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		<-m.Updates()
+	}
+
+	if diff := cmp.Diff(0, c.Stats().Monitors.Queued); diff != "" {
+		t.Fatalf("unexpected final number of queued updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientHooksReconnect(t *testing.T) {
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer d.done()
+
+	reconnectC := make(chan struct{}, 1)
+
+	c := d.client(
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.Hooks(ovsdb.ClientHooks{
+			Reconnect: func() {
+				reconnectC <- struct{}{}
+			},
+		}),
+	)
+	defer c.Close()
+
+	d.drop()
+	<-reconnectC
+
+	if diff := cmp.Diff(1, c.Stats().Reconnects.Total); diff != "" {
+		t.Fatalf("unexpected number of reconnects (-want +got):\n%s", diff)
+	}
+}
+
+// monitorBufferSize is the number of updates buffered by a monitor's updates
+// channel.
+const monitorBufferSize = 16
@@ -15,12 +15,15 @@
 package jsonrpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"sync"
+	"time"
 )
 
 // A Request is a JSON-RPC request.
@@ -54,9 +57,34 @@ func (r *Response) Err() error {
 	return fmt.Errorf("received JSON-RPC error: %#v", r.Error)
 }
 
+// An Encoder writes JSON values to a stream.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// A Decoder reads JSON values from a stream.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// A Codec creates the Encoders and Decoders used by a Conn.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
 // NewConn creates a new Conn with the input io.ReadWriteCloser.
 // If a logger is specified, it is used for debug logs.
 func NewConn(rwc io.ReadWriteCloser, ll *log.Logger) *Conn {
+	return NewCodecConn(rwc, ll, nil)
+}
+
+// NewCodecConn is like NewConn, but uses codec to encode and decode messages.
+// If codec is nil, package encoding/json is used.
+func NewCodecConn(rwc io.ReadWriteCloser, ll *log.Logger, codec Codec) *Conn {
+	// Write deadlines are used to interrupt sends, if supported.
+	wd, _ := rwc.(writeDeadliner)
+
 	if ll != nil {
 		rwc = &debugReadWriteCloser{
 			rwc: rwc,
@@ -64,22 +92,45 @@ func NewConn(rwc io.ReadWriteCloser, ll *log.Logger) *Conn {
 		}
 	}
 
+	if codec == nil {
+		codec = stdCodec{}
+	}
+
 	return &Conn{
 		c:   rwc,
-		enc: json.NewEncoder(rwc),
-		dec: json.NewDecoder(rwc),
+		wd:  wd,
+		enc: codec.NewEncoder(rwc),
+		dec: codec.NewDecoder(rwc),
 	}
 }
 
+// A stdCodec is a Codec which uses package encoding/json.
+type stdCodec struct{}
+
+func (stdCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (stdCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// A writeDeadliner is a connection which supports write deadlines, such as
+// a net.Conn.
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
 // A Conn is a JSON-RPC connection.
 type Conn struct {
-	c io.Closer
+	c  io.Closer
+	wd writeDeadliner
 
 	encMu sync.Mutex
-	enc   *json.Encoder
+	enc   Encoder
 
 	decMu sync.Mutex
-	dec   *json.Decoder
+	dec   Decoder
 }
 
 // Close closes the connection.
@@ -91,6 +142,16 @@ func (c *Conn) Close() error {
 
 // Send sends a single JSON-RPC request.
 func (c *Conn) Send(req Request) error {
+	return c.SendContext(context.Background(), req)
+}
+
+// SendContext sends a single JSON-RPC request, and uses ctx to cancel or time
+// out the send if the remote end of the connection stops reading.
+//
+// A canceled send may leave a partial request on the connection, so the
+// connection is closed if a send is interrupted.  Cancelation requires the
+// underlying connection to support write deadlines, such as a net.Conn.
+func (c *Conn) SendContext(ctx context.Context, req Request) error {
 	if req.ID == "" {
 		return errors.New("JSON-RPC request ID must not be empty")
 	}
@@ -103,29 +164,112 @@ func (c *Conn) Send(req Request) error {
 	c.encMu.Lock()
 	defer c.encMu.Unlock()
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.wd != nil && ctx.Done() != nil {
+		stop := c.interruptWrite(ctx)
+		defer stop()
+	}
+
 	if err := c.enc.Encode(req); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			_ = c.c.Close()
+			return ctxErr
+		}
+
 		return fmt.Errorf("failed to encode JSON-RPC request: %v", err)
 	}
 
 	return nil
 }
 
+// interruptWrite interrupts any write in progress when ctx is canceled, by
+// setting a write deadline in the past.  The returned function must be called
+// once the write is complete, and clears the write deadline.
+func (c *Conn) interruptWrite(ctx context.Context) func() {
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+
+	go func() {
+		defer close(doneC)
+
+		select {
+		case <-ctx.Done():
+			_ = c.wd.SetWriteDeadline(time.Unix(1, 0))
+		case <-stopC:
+		}
+	}()
+
+	return func() {
+		close(stopC)
+		<-doneC
+		_ = c.wd.SetWriteDeadline(time.Time{})
+	}
+}
+
+// Reply sends a single JSON-RPC response to a request which was sent by the
+// remote end of the connection.
+func (c *Conn) Reply(res Response) error {
+	if res.ID == nil {
+		return errors.New("JSON-RPC response ID must not be null")
+	}
+
+	// Only response fields may be set.
+	res.Method = ""
+	res.Params = nil
+
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+
+	if err := c.enc.Encode(res); err != nil {
+		return fmt.Errorf("failed to encode JSON-RPC response: %v", err)
+	}
+
+	return nil
+}
+
 // Receive receives a single JSON-RPC response.
 func (c *Conn) Receive() (*Response, error) {
+	var res Response
+	if err := c.ReceiveInto(&res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ReceiveInto is like Receive, but decodes the response into res so that a
+// single Response can be used to receive many messages.  The memory used by
+// the Result and Params fields of res is reused, so their contents are only
+// valid until the next call to ReceiveInto with res.  Result and Params are
+// empty if the message does not contain them.
+func (c *Conn) ReceiveInto(res *Response) error {
+	// Fields which are not present in the message must not retain their
+	// values from the previous message.
+	*res = Response{
+		Result: res.Result[:0],
+		Params: res.Params[:0],
+	}
+
 	c.decMu.Lock()
 	defer c.decMu.Unlock()
 
-	var res Response
-	if err := c.dec.Decode(&res); err != nil {
-		// Don't mask EOF errors with added detail.
+	if err := c.dec.Decode(res); err != nil {
+		// Don't mask EOF or network errors with added detail, so callers
+		// can inspect them.
 		if err == io.EOF {
-			return nil, err
+			return err
+		}
+		if _, ok := err.(net.Error); ok {
+			return err
 		}
 
-		return nil, fmt.Errorf("failed to decode JSON-RPC response: %v", err)
+		return fmt.Errorf("failed to decode JSON-RPC response: %v", err)
 	}
 
-	return &res, nil
+	return nil
 }
 
 type debugReadWriteCloser struct {
@@ -15,13 +15,18 @@
 package jsonrpc_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestConnSendNoRequestID(t *testing.T) {
@@ -33,6 +38,75 @@ func TestConnSendNoRequestID(t *testing.T) {
 	}
 }
 
+func TestConnSendContextCanceled(t *testing.T) {
+	c, _, done := jsonrpc.TestConn(t, nil)
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.SendContext(ctx, jsonrpc.Request{ID: "10"}); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConnSendContextTimeout(t *testing.T) {
+	// Nothing reads from the other end of the pipe, so the send blocks.
+	client, server := net.Pipe()
+	defer server.Close()
+
+	c := jsonrpc.NewConn(client, nil)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := c.SendContext(ctx, jsonrpc.Request{ID: "10"}); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The interrupted connection must not be used again.
+	if err := c.Send(jsonrpc.Request{ID: "11"}); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestConnReplyNoResponseID(t *testing.T) {
+	c, _, done := jsonrpc.TestConn(t, nil)
+	defer done()
+
+	if err := c.Reply(jsonrpc.Response{}); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestConnReplyOK(t *testing.T) {
+	reqC := make(chan jsonrpc.Request, 1)
+
+	c, _, done := jsonrpc.TestConn(t, func(got jsonrpc.Request) jsonrpc.Response {
+		reqC <- got
+		return jsonrpc.Response{ID: strPtr("ack")}
+	})
+	defer done()
+
+	err := c.Reply(jsonrpc.Response{
+		ID:     strPtr("echo"),
+		Result: mustMarshalJSON(t, []string{"foo"}),
+		Method: "echo",
+	})
+	if err != nil {
+		t.Fatalf("failed to reply: %v", err)
+	}
+
+	// The test server decodes all messages as requests, so only the ID
+	// and absence of a method can be verified.
+	want := jsonrpc.Request{ID: "echo"}
+
+	if diff := cmp.Diff(want, <-reqC); diff != "" {
+		t.Fatalf("unexpected response (-want +got):\n%s", diff)
+	}
+}
+
 func TestConnReceiveEOF(t *testing.T) {
 	c := jsonrpc.NewConn(&eofReadWriteCloser{}, nil)
 
@@ -43,6 +117,66 @@ func TestConnReceiveEOF(t *testing.T) {
 	}
 }
 
+func TestConnReceiveInto(t *testing.T) {
+	c := jsonrpc.NewConn(&readerReadWriteCloser{r: strings.NewReader(
+		`{"id":"1","result":["foo"],"error":null}` +
+			`{"id":null,"method":"update","params":["bar",{}]}` +
+			`{"id":"2","error":"baz"}`,
+	)}, nil)
+
+	want := []jsonrpc.Response{
+		{
+			ID:     strPtr("1"),
+			Result: json.RawMessage(`["foo"]`),
+		},
+		{
+			Method: "update",
+			Params: json.RawMessage(`["bar",{}]`),
+		},
+		{
+			ID:    strPtr("2"),
+			Error: "baz",
+		},
+	}
+
+	// Each message is received into the same Response, which must not retain
+	// the fields of previous messages.
+	var res jsonrpc.Response
+	for i, w := range want {
+		if err := c.ReceiveInto(&res); err != nil {
+			t.Fatalf("failed to receive message %d: %v", i, err)
+		}
+
+		if diff := cmp.Diff(w, res, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("unexpected message %d (-want +got):\n%s", i, diff)
+		}
+	}
+
+	if err := c.ReceiveInto(&res); err != io.EOF {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConnReceiveIntoReusesBuffers(t *testing.T) {
+	msg := `{"id":null,"method":"update","params":["foo",{}]}`
+
+	c := jsonrpc.NewConn(&readerReadWriteCloser{r: strings.NewReader(msg + msg)}, nil)
+
+	var res jsonrpc.Response
+	if err := c.ReceiveInto(&res); err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	first := &res.Params[0]
+
+	if err := c.ReceiveInto(&res); err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+
+	if first != &res.Params[0] {
+		t.Fatal("parameters of second message were not decoded into the same buffer")
+	}
+}
+
 func TestConnSendReceiveError(t *testing.T) {
 	// TODO(mdlayher): what does this actually look like?
 	type rpcError struct {
@@ -187,6 +321,64 @@ func TestConnSendReceiveNotificationsOK(t *testing.T) {
 	}
 }
 
+func TestConnCodec(t *testing.T) {
+	conn, _, done := jsonrpc.TestNetConn(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, req.Method),
+		}
+	})
+	defer done()
+
+	codec := &countCodec{}
+	c := jsonrpc.NewCodecConn(conn, nil, codec)
+
+	for i := 0; i < 2; i++ {
+		if err := c.Send(jsonrpc.Request{ID: "1", Method: "echo"}); err != nil {
+			t.Fatalf("failed to send request: %v", err)
+		}
+
+		if _, err := c.Receive(); err != nil {
+			t.Fatalf("failed to receive response: %v", err)
+		}
+	}
+
+	// Every message must pass through the codec.
+	if diff := cmp.Diff(&countCodec{encodes: 2, decodes: 2}, codec, cmp.AllowUnexported(countCodec{})); diff != "" {
+		t.Fatalf("unexpected codec calls (-want +got):\n%s", diff)
+	}
+}
+
+// A countCodec is a jsonrpc.Codec which counts the values it encodes and
+// decodes.
+type countCodec struct {
+	encodes, decodes int
+}
+
+func (c *countCodec) NewEncoder(w io.Writer) jsonrpc.Encoder {
+	enc := json.NewEncoder(w)
+	return encoderFunc(func(v interface{}) error {
+		c.encodes++
+		return enc.Encode(v)
+	})
+}
+
+func (c *countCodec) NewDecoder(r io.Reader) jsonrpc.Decoder {
+	dec := json.NewDecoder(r)
+	return decoderFunc(func(v interface{}) error {
+		c.decodes++
+		return dec.Decode(v)
+	})
+}
+
+type encoderFunc func(v interface{}) error
+
+func (fn encoderFunc) Encode(v interface{}) error { return fn(v) }
+
+type decoderFunc func(v interface{}) error
+
+func (fn decoderFunc) Decode(v interface{}) error { return fn(v) }
+
 func mustMarshalJSON(t *testing.T, v interface{}) []byte {
 	t.Helper()
 
@@ -213,3 +405,12 @@ type eofReadWriteCloser struct {
 func (rwc *eofReadWriteCloser) Read(b []byte) (int, error) {
 	return 0, io.EOF
 }
+
+type readerReadWriteCloser struct {
+	io.ReadWriteCloser
+	r io.Reader
+}
+
+func (rwc *readerReadWriteCloser) Read(b []byte) (int, error) {
+	return rwc.r.Read(b)
+}
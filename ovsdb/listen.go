@@ -0,0 +1,169 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// A Listener accepts connections from OVSDB servers, and returns a Client for
+// each.  A Listener allows a process to act as the passive side of an OVSDB
+// connection, such as a manager to which ovsdb-server connects using an
+// active "tcp:" or "ssl:" remote in its configuration.
+//
+// Listeners are created using Listen or ListenRemote.
+type Listener struct {
+	l       net.Listener
+	options []OptionFunc
+}
+
+// Listen listens for connections from OVSDB servers on the specified network
+// and address.  options are applied to each Client returned by
+// Listener.Accept.
+//
+// If the TLSConfig option is used, connections are secured using TLS, and
+// cfg.Certificates must contain the Listener's certificate.  The Reconnect
+// option may not be used, because the Listener cannot dial the servers.
+func Listen(network, addr string, options ...OptionFunc) (*Listener, error) {
+	c, err := newClient(options)
+	if err != nil {
+		return nil, err
+	}
+	if c.reconnect {
+		return nil, errors.New("clients accepted by a Listener cannot reconnect")
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tlsConfig != nil {
+		l = tls.NewListener(l, c.tlsConfig)
+	}
+
+	return &Listener{
+		l:       l,
+		options: options,
+	}, nil
+}
+
+// ListenRemote listens for connections from OVSDB servers using a passive
+// remote string, as accepted by the --remote flag of ovsdb-server.  The
+// supported remotes are:
+//
+//   - ptcp:[port][:ip]: a TCP listener
+//   - pssl:[port][:ip]: a TLS listener, which requires the TLSConfig option
+//   - punix:file: a UNIX domain socket listener
+//
+// If a port is not specified, DefaultPort is used.  If an IP address is not
+// specified, the Listener listens on all addresses.  IPv6 addresses must be
+// enclosed in square brackets, such as "ptcp:6640:[::1]".
+func ListenRemote(remote string, options ...OptionFunc) (*Listener, error) {
+	network, addr, ssl, err := parsePassiveRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	r := ovsdbRemote{
+		remote:  remote,
+		network: network,
+		addr:    addr,
+		ssl:     ssl,
+	}
+	if err := r.checkTLS(c.tlsConfig); err != nil {
+		return nil, err
+	}
+
+	return Listen(network, addr, options...)
+}
+
+// Accept waits for an OVSDB server to connect to the Listener, and returns a
+// Client which uses the connection.
+func (l *Listener) Accept() (*Client, error) {
+	conn, err := l.l.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := New(conn, l.options...)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Addr returns the Listener's network address.
+func (l *Listener) Addr() net.Addr {
+	return l.l.Addr()
+}
+
+// Close stops listening for connections.  Clients which were already
+// accepted are not closed.
+func (l *Listener) Close() error {
+	return l.l.Close()
+}
+
+// parsePassiveRemote parses a passive OVSDB remote string into a network and
+// address for listening, and reports whether the remote uses TLS.
+func parsePassiveRemote(remote string) (network, addr string, ssl bool, err error) {
+	ss := strings.SplitN(remote, ":", 2)
+	if len(ss) != 2 {
+		return "", "", false, fmt.Errorf("invalid remote %q", remote)
+	}
+
+	method, target := ss[0], ss[1]
+
+	switch method {
+	case "punix":
+		if target == "" {
+			return "", "", false, fmt.Errorf("invalid remote %q", remote)
+		}
+
+		return "unix", target, false, nil
+	case "ptcp", "pssl":
+		port, ip := target, ""
+		if i := strings.Index(target, ":"); i != -1 {
+			port, ip = target[:i], target[i+1:]
+		}
+		if port == "" {
+			port = DefaultPort
+		}
+
+		if strings.HasPrefix(ip, "[") && strings.HasSuffix(ip, "]") {
+			ip = ip[1 : len(ip)-1]
+		}
+		if strings.ContainsAny(ip, "[]") || (ip != "" && net.ParseIP(ip) == nil) {
+			return "", "", false, fmt.Errorf("invalid IP address for remote %q", remote)
+		}
+
+		return "tcp", net.JoinHostPort(ip, port), method == "pssl", nil
+	case "tcp", "ssl", "unix":
+		return "", "", false, fmt.Errorf("active remote %q cannot be listened on", remote)
+	default:
+		return "", "", false, fmt.Errorf("unknown method for remote %q", remote)
+	}
+}
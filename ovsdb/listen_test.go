@@ -0,0 +1,188 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestListenRemote(t *testing.T) {
+	cert, pool := testCertificate(t)
+
+	dir, err := ioutil.TempDir("", "ovsdb-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		remote  string
+		options []ovsdb.OptionFunc
+		dial    func(addr net.Addr) (net.Conn, error)
+	}{
+		{
+			name:   "ptcp",
+			remote: "ptcp:0:127.0.0.1",
+			dial: func(addr net.Addr) (net.Conn, error) {
+				return net.Dial("tcp", addr.String())
+			},
+		},
+		{
+			name:   "pssl",
+			remote: "pssl:0:127.0.0.1",
+			options: []ovsdb.OptionFunc{
+				ovsdb.TLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}),
+			},
+			dial: func(addr net.Addr) (net.Conn, error) {
+				return tls.Dial("tcp", addr.String(), &tls.Config{RootCAs: pool})
+			},
+		},
+		{
+			name:   "punix",
+			remote: "punix:" + filepath.Join(dir, "db.sock"),
+			dial: func(addr net.Addr) (net.Conn, error) {
+				return net.Dial("unix", addr.String())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := ovsdb.ListenRemote(tt.remote, tt.options...)
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer l.Close()
+
+			want := []string{"Open_vSwitch"}
+
+			// Act as an OVSDB server which connects to the Listener.
+			errC := make(chan error, 1)
+			go func() {
+				conn, err := tt.dial(l.Addr())
+				if err != nil {
+					errC <- err
+					return
+				}
+				defer conn.Close()
+
+				var req jsonrpc.Request
+				if err := json.NewDecoder(conn).Decode(&req); err != nil {
+					errC <- err
+					return
+				}
+
+				errC <- json.NewEncoder(conn).Encode(jsonrpc.Response{
+					ID:     &req.ID,
+					Result: mustMarshalJSON(t, want),
+				})
+			}()
+
+			c, err := l.Accept()
+			if err != nil {
+				t.Fatalf("failed to accept: %v", err)
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			dbs, err := c.ListDatabases(ctx)
+			if err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			if diff := cmp.Diff(want, dbs); diff != "" {
+				t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+			}
+
+			if err := <-errC; err != nil {
+				t.Fatalf("failed to serve: %v", err)
+			}
+		})
+	}
+}
+
+func TestListenRemoteInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		options []ovsdb.OptionFunc
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "active",
+			remote: "tcp:127.0.0.1:6640",
+		},
+		{
+			name:   "unknown method",
+			remote: "pudp:6640",
+		},
+		{
+			name:   "no socket",
+			remote: "punix:",
+		},
+		{
+			name:   "bad IP",
+			remote: "ptcp:6640:foo",
+		},
+		{
+			name:   "unbracketed IPv6",
+			remote: "ptcp:6640:[::1",
+		},
+		{
+			name:   "pssl without TLS",
+			remote: "pssl:0:127.0.0.1",
+		},
+		{
+			name:   "ptcp with TLS",
+			remote: "ptcp:0:127.0.0.1",
+			options: []ovsdb.OptionFunc{
+				ovsdb.TLSConfig(&tls.Config{}),
+			},
+		},
+		{
+			name:   "reconnect",
+			remote: "ptcp:0:127.0.0.1",
+			options: []ovsdb.OptionFunc{
+				ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if l, err := ovsdb.ListenRemote(tt.remote, tt.options...); err == nil {
+				_ = l.Close()
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// A LockState is the state of a Client's ownership of a lock.
+type LockState int
+
+// Possible LockState values.
+const (
+	// The Client acquired the lock, either immediately or after waiting
+	// for its previous owner to release it.
+	LockAcquired LockState = iota
+
+	// The lock was stolen by another client.  The Client must stop any
+	// operations which require the lock.
+	LockStolen
+
+	// The Client released the lock by calling Unlock, or lost the lock
+	// because its connection to the OVSDB server was lost.
+	LockReleased
+)
+
+// String returns the string representation of a LockState.
+func (s LockState) String() string {
+	switch s {
+	case LockAcquired:
+		return "acquired"
+	case LockStolen:
+		return "stolen"
+	case LockReleased:
+		return "released"
+	default:
+		return "unknown"
+	}
+}
+
+// A LockEvent reports a change in the Client's ownership of a lock.
+type LockEvent struct {
+	// The ID of the lock.
+	ID string
+
+	// The new state of the lock.
+	State LockState
+}
+
+// Lock requests ownership of the lock with the specified ID.  It returns true
+// if the lock was acquired immediately.  Otherwise, the Client is queued to
+// acquire the lock when its current owner releases it.
+//
+// Locks are held for the lifetime of a connection, or until Unlock is called.
+// If the Client is configured to reconnect, the lock is requested again
+// after reconnecting.  Changes in ownership of the lock, such as acquiring a
+// queued lock, are reported by LockEvents.
+func (c *Client) Lock(ctx context.Context, id string) (bool, error) {
+	var res lockResult
+	if err := c.rpc(ctx, "lock", &res, []string{id}); err != nil {
+		return false, err
+	}
+
+	c.addLock(id)
+	if res.Locked {
+		c.setLock(id, LockAcquired)
+	}
+
+	return res.Locked, nil
+}
+
+// Steal forcibly acquires ownership of the lock with the specified ID,
+// whether or not it is currently owned by another client.
+func (c *Client) Steal(ctx context.Context, id string) error {
+	var res lockResult
+	if err := c.rpc(ctx, "steal", &res, []string{id}); err != nil {
+		return err
+	}
+
+	c.addLock(id)
+	c.setLock(id, LockAcquired)
+	return nil
+}
+
+// Unlock releases ownership of the lock with the specified ID, or removes the
+// Client from the queue of clients waiting to acquire it.
+func (c *Client) Unlock(ctx context.Context, id string) error {
+	if err := c.rpc(ctx, "unlock", nil, []string{id}); err != nil {
+		return err
+	}
+
+	c.removeLock(id)
+	return nil
+}
+
+// LockEvents returns a channel which receives a LockEvent each time the
+// Client acquires or loses ownership of a lock, including when the OVSDB
+// server reports that a queued lock was acquired or that a lock was stolen.
+// The channel is closed when the Client is closed.
+//
+// LockEvents are queued in memory until they are received, so the channel
+// should be drained promptly.
+func (c *Client) LockEvents() <-chan LockEvent {
+	return c.lockEvents
+}
+
+// addLock tracks a lock which must be requested again after reconnecting.
+func (c *Client) addLock(id string) {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	if _, ok := c.locks[id]; !ok {
+		c.locks[id] = false
+	}
+}
+
+// removeLock stops tracking a lock, and reports its release if it was held.
+func (c *Client) removeLock(id string) {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	if c.locks[id] {
+		c.queueLockEvent(id, LockReleased)
+	}
+
+	delete(c.locks, id)
+}
+
+// setLock updates the ownership of a tracked lock, and reports any change.
+func (c *Client) setLock(id string, state LockState) {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	held, ok := c.locks[id]
+	if !ok || held == (state == LockAcquired) {
+		return
+	}
+
+	c.locks[id] = state == LockAcquired
+	c.queueLockEvent(id, state)
+}
+
+// releaseLocks reports the release of all held locks after the Client's
+// connection is lost.
+func (c *Client) releaseLocks() {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	for id, held := range c.locks {
+		if held {
+			c.locks[id] = false
+			c.queueLockEvent(id, LockReleased)
+		}
+	}
+}
+
+// queueLockEvent queues an event for delivery on the lock events channel.
+// lockMu must be held.
+func (c *Client) queueLockEvent(id string, state LockState) {
+	c.lockQueue = append(c.lockQueue, LockEvent{
+		ID:    id,
+		State: state,
+	})
+
+	select {
+	case c.lockWake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverLocks sends queued lock events until ctx is canceled, and then
+// closes the lock events channel.
+func (c *Client) deliverLocks(ctx context.Context) {
+	defer close(c.lockEvents)
+
+	for {
+		c.lockMu.Lock()
+		queue := c.lockQueue
+		c.lockQueue = nil
+		c.lockMu.Unlock()
+
+		for _, e := range queue {
+			select {
+			case <-ctx.Done():
+				return
+			case c.lockEvents <- e:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.lockWake:
+		}
+	}
+}
+
+// doLock handles a locked or stolen notification from the OVSDB server.
+func (c *Client) doLock(method string, params json.RawMessage) {
+	// Parameters are [<id>].
+	var args []string
+	if err := c.unmarshal(params, &args); err != nil || len(args) != 1 {
+		return
+	}
+
+	state := LockAcquired
+	if method == "stolen" {
+		state = LockStolen
+	}
+
+	c.setLock(args[0], state)
+}
+
+// lockIDs returns the IDs of all tracked locks.
+func (c *Client) lockIDs() []string {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	ids := make([]string, 0, len(c.locks))
+	for id := range c.locks {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// A lockResult is the result of a lock or steal RPC.
+type lockResult struct {
+	Locked bool `json:"locked"`
+}
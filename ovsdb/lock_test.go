@@ -0,0 +1,205 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientLock(t *testing.T) {
+	tests := []struct {
+		name   string
+		locked bool
+	}{
+		{
+			name:   "locked",
+			locked: true,
+		},
+		{
+			name: "queued",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, done := testLockClient(t, "lock", tt.locked)
+			defer done()
+
+			locked, err := c.Lock(context.Background(), "foo")
+			if err != nil {
+				t.Fatalf("failed to lock: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.locked, locked); diff != "" {
+				t.Fatalf("unexpected lock state (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientLockNotifications(t *testing.T) {
+	c, notifC, done := testLockClient(t, "lock", false)
+	defer done()
+
+	ctx := context.Background()
+
+	locked, err := c.Lock(ctx, "foo")
+	if err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	if locked {
+		t.Fatal("lock should have been queued")
+	}
+
+	// The server later notifies the client that the lock was acquired, and
+	// then that it was stolen.
+	for _, method := range []string{"locked", "stolen"} {
+		notifC <- &jsonrpc.Response{
+			Method: method,
+			Params: mustMarshalJSON(t, []string{"foo"}),
+		}
+	}
+
+	want := []ovsdb.LockEvent{
+		{ID: "foo", State: ovsdb.LockAcquired},
+		{ID: "foo", State: ovsdb.LockStolen},
+	}
+
+	if diff := cmp.Diff(want, lockEvents(t, c, len(want))); diff != "" {
+		t.Fatalf("unexpected lock events (-want +got):\n%s", diff)
+	}
+
+	// The client must continue to handle RPCs after the notifications.
+	if _, err := c.Lock(ctx, "foo"); err != nil {
+		t.Fatalf("failed to lock after notifications: %v", err)
+	}
+}
+
+func TestClientLockEventsUnlock(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		var res interface{} = struct{}{}
+		switch req.Method {
+		case "lock", "steal":
+			res = map[string]bool{"locked": true}
+		case "unlock":
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+	defer done()
+
+	ctx := context.Background()
+
+	// Releasing a lock which was never held is not reported.
+	if err := c.Unlock(ctx, "bar"); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+
+	if _, err := c.Lock(ctx, "foo"); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	if err := c.Unlock(ctx, "foo"); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	if err := c.Steal(ctx, "baz"); err != nil {
+		t.Fatalf("failed to steal: %v", err)
+	}
+
+	want := []ovsdb.LockEvent{
+		{ID: "foo", State: ovsdb.LockAcquired},
+		{ID: "foo", State: ovsdb.LockReleased},
+		{ID: "baz", State: ovsdb.LockAcquired},
+	}
+
+	if diff := cmp.Diff(want, lockEvents(t, c, len(want))); diff != "" {
+		t.Fatalf("unexpected lock events (-want +got):\n%s", diff)
+	}
+
+	// The channel is closed with the Client.
+	_ = c.Close()
+	if _, ok := <-c.LockEvents(); ok {
+		t.Fatal("lock events channel was not closed")
+	}
+}
+
+func TestClientSteal(t *testing.T) {
+	c, _, done := testLockClient(t, "steal", true)
+	defer done()
+
+	if err := c.Steal(context.Background(), "foo"); err != nil {
+		t.Fatalf("failed to steal: %v", err)
+	}
+}
+
+func TestClientUnlock(t *testing.T) {
+	c, _, done := testLockClient(t, "unlock", false)
+	defer done()
+
+	if err := c.Unlock(context.Background(), "foo"); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+}
+
+// lockEvents receives n events from c's lock events channel.
+func lockEvents(t *testing.T, c *ovsdb.Client, n int) []ovsdb.LockEvent {
+	t.Helper()
+
+	events := make([]ovsdb.LockEvent, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-c.LockEvents():
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for lock event %d", i)
+		}
+	}
+
+	return events
+}
+
+func testLockClient(t *testing.T, method string, locked bool) (*ovsdb.Client, chan<- *jsonrpc.Response, func()) {
+	t.Helper()
+
+	return testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff(method, req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]interface{}{"foo"}, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		var res interface{} = struct{}{}
+		if method != "unlock" {
+			res = map[string]bool{"locked": locked}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+}
@@ -0,0 +1,254 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MarshalRow converts the struct pointed to by v into a Row.  If one or more
+// columns are specified, only those columns are included in the Row.
+//
+// Each exported field of v with an `ovsdb:"<column>"` struct tag is stored in
+// the named column, and fields with no tag or the tag `ovsdb:"-"` are
+// ignored.  The read-only _uuid and _version columns are never included.
+//
+// Fields are encoded according to their type:
+//   - pointers are optional scalars, encoded as empty sets when nil
+//   - slices are encoded as sets
+//   - maps are encoded as maps
+//   - all other types are encoded as atoms
+//
+// Fields which reference other rows should use the UUID type.  A UUID which
+// is not in the canonical UUID format is encoded as a NamedUUID, so that a
+// row can refer to a row inserted earlier in the same transaction.
+func MarshalRow(v interface{}, columns ...string) (Row, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(Row)
+	for _, f := range structFields(rv.Type()) {
+		if f.column == "_uuid" || f.column == "_version" || !wantColumn(columns, f.column) {
+			continue
+		}
+
+		value, err := encodeField(rv.Field(f.index))
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %v", f.column, err)
+		}
+
+		row[f.column] = value
+	}
+
+	return row, nil
+}
+
+// UnmarshalRow stores the columns of row in the struct pointed to by v, using
+// the same struct tags as MarshalRow.  Fields whose columns are not present
+// in row are left unchanged.
+//
+// A nil pointer field is set when its column contains a single value, and an
+// empty set leaves a non-pointer field set to its zero value.
+func UnmarshalRow(row Row, v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range structFields(rv.Type()) {
+		value, ok := row[f.column]
+		if !ok {
+			continue
+		}
+
+		if err := decodeField(value, rv.Field(f.index)); err != nil {
+			return fmt.Errorf("column %q: %v", f.column, err)
+		}
+	}
+
+	return nil
+}
+
+// structValue returns the struct pointed to by v.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected pointer to struct, but got %T", v)
+	}
+
+	return rv.Elem(), nil
+}
+
+// A structField is a struct field which is mapped to a column.
+type structField struct {
+	index  int
+	column string
+}
+
+// structFields returns the fields of typ which are mapped to columns.
+func structFields(typ reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			// Unexported.
+			continue
+		}
+
+		tag := f.Tag.Get("ovsdb")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		// Ignore any options following the column name.
+		column := strings.Split(tag, ",")[0]
+
+		fields = append(fields, structField{
+			index:  i,
+			column: column,
+		})
+	}
+
+	return fields
+}
+
+// encodeField encodes the value of a struct field.
+func encodeField(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return Set{}, nil
+		}
+
+		atom, err := encodeAtom(v.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeRef(atom), nil
+	case reflect.Slice:
+		s, err := NewSet(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range s {
+			s[i] = encodeRef(s[i])
+		}
+
+		return s, nil
+	case reflect.Map:
+		m, err := NewMap(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		out := make(Map, len(m))
+		for k, e := range m {
+			out[encodeRef(k)] = encodeRef(e)
+		}
+
+		return out, nil
+	default:
+		atom, err := encodeAtom(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeRef(atom), nil
+	}
+}
+
+// encodeRef converts a UUID atom which is not in the canonical format into
+// a NamedUUID.
+func encodeRef(atom interface{}) interface{} {
+	if u, ok := atom.(UUID); ok && !isUUID(string(u)) {
+		return NamedUUID(u)
+	}
+
+	return atom
+}
+
+// decodeField decodes a column value into a struct field.
+func decodeField(value interface{}, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		s, err := ParseSet(value)
+		if err != nil {
+			return err
+		}
+
+		return s.Decode(v.Addr().Interface())
+	case reflect.Map:
+		m, err := ParseMap(value)
+		if err != nil {
+			return err
+		}
+
+		return m.Decode(v.Addr().Interface())
+	}
+
+	s, err := ParseSet(value)
+	if err != nil {
+		return err
+	}
+
+	switch len(s) {
+	case 0:
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("expected at most one value, but got %d", len(s))
+	}
+
+	typ := v.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	atom, err := convertAtom(s[0], typ)
+	if err != nil {
+		return err
+	}
+
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(typ)
+		p.Elem().Set(atom)
+		atom = p
+	}
+
+	v.Set(atom)
+	return nil
+}
+
+// wantColumn reports whether column is one of columns, or columns is empty.
+func wantColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testBridge struct {
+	UUID        ovsdb.UUID        `ovsdb:"_uuid"`
+	Name        string            `ovsdb:"name"`
+	FailMode    *string           `ovsdb:"fail_mode"`
+	Ports       []ovsdb.UUID      `ovsdb:"ports"`
+	FloodVLANs  []int             `ovsdb:"flood_vlans"`
+	STPEnable   bool              `ovsdb:"stp_enable"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Controller  *ovsdb.UUID       `ovsdb:"controller"`
+
+	Ignored    string `ovsdb:"-"`
+	NoTag      string
+	unexported string `ovsdb:"unexported"`
+}
+
+func TestMarshalRow(t *testing.T) {
+	secure := "secure"
+
+	tests := []struct {
+		name    string
+		b       testBridge
+		columns []string
+		s       string
+	}{
+		{
+			name: "zero",
+			s:    `{"controller":["set",[]],"external_ids":["map",[]],"fail_mode":["set",[]],"flood_vlans":["set",[]],"name":"","ports":["set",[]],"stp_enable":false}`,
+		},
+		{
+			name: "full",
+			b: testBridge{
+				UUID:        valueUUID,
+				Name:        "br0",
+				FailMode:    &secure,
+				Ports:       []ovsdb.UUID{valueUUID, "port0"},
+				FloodVLANs:  []int{10, 20},
+				STPEnable:   true,
+				ExternalIDs: map[string]string{"foo": "bar"},
+				Controller:  uuidPtr("controller0"),
+				Ignored:     "ignored",
+				NoTag:       "none",
+			},
+			s: `{"controller":["named-uuid","controller0"],"external_ids":["map",[["foo","bar"]]],"fail_mode":"secure","flood_vlans":["set",[10,20]],"name":"br0","ports":["set",[["uuid","` + valueUUID + `"],["named-uuid","port0"]]],"stp_enable":true}`,
+		},
+		{
+			name:    "columns",
+			b:       testBridge{Name: "br0", FailMode: &secure},
+			columns: []string{"name", "fail_mode"},
+			s:       `{"fail_mode":"secure","name":"br0"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, err := ovsdb.MarshalRow(&tt.b, tt.columns...)
+			if err != nil {
+				t.Fatalf("failed to marshal row: %v", err)
+			}
+
+			b, err := json.Marshal(row)
+			if err != nil {
+				t.Fatalf("failed to marshal JSON: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.s, string(b)); diff != "" {
+				t.Fatalf("unexpected row JSON (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRow(t *testing.T) {
+	secure := "secure"
+
+	tests := []struct {
+		name string
+		s    string
+		b    testBridge
+	}{
+		{
+			name: "empty",
+			s:    `{}`,
+			b:    testBridge{Ignored: "ignored"},
+		},
+		{
+			name: "empty sets",
+			s:    `{"fail_mode":["set",[]],"name":["set",[]],"ports":["set",[]],"controller":["set",[]],"external_ids":["map",[]]}`,
+			b: testBridge{
+				Ports:       []ovsdb.UUID{},
+				ExternalIDs: map[string]string{},
+				Ignored:     "ignored",
+			},
+		},
+		{
+			name: "full",
+			s:    `{"_uuid":["uuid","` + valueUUID + `"],"_version":["uuid","` + valueUUID + `"],"controller":["uuid","` + valueUUID + `"],"external_ids":["map",[["foo","bar"]]],"fail_mode":"secure","flood_vlans":["set",[10,20]],"name":"br0","ports":["uuid","` + valueUUID + `"],"stp_enable":true}`,
+			b: testBridge{
+				UUID:        valueUUID,
+				Name:        "br0",
+				FailMode:    &secure,
+				Ports:       []ovsdb.UUID{valueUUID},
+				FloodVLANs:  []int{10, 20},
+				STPEnable:   true,
+				ExternalIDs: map[string]string{"foo": "bar"},
+				Controller:  uuidPtr(valueUUID),
+				Ignored:     "ignored",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var row ovsdb.Row
+			if err := json.Unmarshal([]byte(tt.s), &row); err != nil {
+				t.Fatalf("failed to unmarshal JSON: %v", err)
+			}
+
+			// Fields which are not mapped to columns are left unchanged.
+			b := testBridge{Ignored: "ignored"}
+			if err := ovsdb.UnmarshalRow(row, &b); err != nil {
+				t.Fatalf("failed to unmarshal row: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.b, b, cmp.AllowUnexported(testBridge{})); diff != "" {
+				t.Fatalf("unexpected bridge (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRowInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		row  ovsdb.Row
+		v    interface{}
+	}{
+		{
+			name: "not a pointer",
+			v:    testBridge{},
+		},
+		{
+			name: "not a struct",
+			v:    new(string),
+		},
+		{
+			name: "nil pointer",
+			v:    (*testBridge)(nil),
+		},
+		{
+			name: "multiple values for scalar",
+			row:  ovsdb.Row{"fail_mode": []interface{}{"set", []interface{}{"secure", "standalone"}}},
+			v:    &testBridge{},
+		},
+		{
+			name: "wrong type",
+			row:  ovsdb.Row{"stp_enable": "true"},
+			v:    &testBridge{},
+		},
+		{
+			name: "set for map",
+			row:  ovsdb.Row{"external_ids": []interface{}{"set", []interface{}{}}},
+			v:    &testBridge{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ovsdb.UnmarshalRow(tt.row, tt.v); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestMarshalRowInvalid(t *testing.T) {
+	v := struct {
+		Options map[string][]string `ovsdb:"options"`
+	}{
+		Options: map[string][]string{"foo": {"bar"}},
+	}
+
+	if _, err := ovsdb.MarshalRow(&v); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if _, err := ovsdb.MarshalRow(v); err == nil {
+		t.Fatal("expected an error for a non-pointer, but none occurred")
+	}
+}
+
+func uuidPtr(u ovsdb.UUID) *ovsdb.UUID {
+	return &u
+}
@@ -0,0 +1,525 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// A MonitorRequest specifies which columns of a table should be monitored.
+type MonitorRequest struct {
+	// Zero or more columns to monitor.  If empty, all columns are monitored.
+	Columns []string
+
+	// The kinds of changes to monitor.  If nil, all changes are monitored.
+	Select *MonitorSelect
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r MonitorRequest) MarshalJSON() ([]byte, error) {
+	req := struct {
+		Columns []string       `json:"columns,omitempty"`
+		Select  *MonitorSelect `json:"select,omitempty"`
+	}{
+		Columns: r.Columns,
+		Select:  r.Select,
+	}
+
+	return json.Marshal(req)
+}
+
+// A MonitorSelect specifies the kinds of changes to a table which are
+// reported by a monitor.  Only the changes whose fields are true are
+// reported.
+type MonitorSelect struct {
+	// The contents of the table when the monitor is created, and after it
+	// is re-established when reconnecting.
+	Initial bool `json:"initial"`
+
+	// Rows inserted into, deleted from, or modified in the table.
+	Insert bool `json:"insert"`
+	Delete bool `json:"delete"`
+	Modify bool `json:"modify"`
+}
+
+// all reports whether s selects every kind of change.
+func (s *MonitorSelect) all() bool {
+	return s == nil || (s.Initial && s.Insert && s.Delete && s.Modify)
+}
+
+// TableUpdates contains changes to one or more tables, keyed by table name.
+type TableUpdates map[string]TableUpdate
+
+// A TableUpdate contains changes to the rows of a single table, keyed by
+// row UUID.
+type TableUpdate map[string]RowUpdate
+
+// A RowUpdate describes a change to a single row.  Old is nil if the row
+// was inserted, and New is nil if the row was deleted.  If the row was
+// modified, Old contains only the columns which changed.
+type RowUpdate struct {
+	Old Row `json:"old,omitempty"`
+	New Row `json:"new,omitempty"`
+}
+
+// A Monitor receives notifications about changes to a database's tables.
+// Monitors are created using Client.Monitor.
+type Monitor struct {
+	// Initial contains the contents of the monitored tables at the time
+	// the Monitor was created.
+	Initial TableUpdates
+
+	monitorBase
+	updates chan TableUpdates
+
+	// Used to re-establish the monitor after reconnecting.
+	db       string
+	requests map[string]MonitorRequest
+}
+
+// Monitor begins monitoring one or more tables of the specified database.
+// The keys of requests are the names of the tables to monitor.
+//
+// The returned Monitor contains the initial contents of the tables, and
+// further changes are delivered via Monitor.Updates until Monitor.Cancel is
+// called or the Client is closed.
+//
+// A Client may have any number of monitors of any type.  Each is assigned a
+// distinct ID, and receives only its own updates.
+func (c *Client) Monitor(ctx context.Context, db string, requests map[string]MonitorRequest) (*Monitor, error) {
+	m := &Monitor{
+		monitorBase: c.newMonitorBase(),
+		updates:     make(chan TableUpdates, monitorBuffer),
+		db:          db,
+		requests:    requests,
+	}
+
+	var initial TableUpdates
+	if err := c.monitor(ctx, m.id, m, "monitor", &initial, []interface{}{db, m.id, requests}); err != nil {
+		return nil, err
+	}
+
+	m.Initial = initial
+	return m, nil
+}
+
+// ID returns the Monitor's ID, which is unique within its Client and is
+// reported to ClientHooks.Update.
+func (m *Monitor) ID() string {
+	return m.id
+}
+
+// Updates returns a channel which receives changes to the monitored tables.
+// The channel is closed when the Monitor is canceled or its Client is closed.
+//
+// Updates are queued in memory until they are received, so the channel
+// should be drained promptly.  A slow consumer does not delay RPCs or
+// other monitors, unless the MonitorBackpressure option specifies
+// otherwise.
+func (m *Monitor) Updates() <-chan TableUpdates {
+	return m.updates
+}
+
+// Cancel stops the Monitor and closes its updates channel.
+func (m *Monitor) Cancel(ctx context.Context) error {
+	return m.cancel(ctx, m)
+}
+
+// Err returns ErrMonitorCanceled if the OVSDB server canceled the Monitor,
+// or nil otherwise.  Err should be checked after the updates channel is
+// closed.
+func (m *Monitor) Err() error {
+	return m.getErr()
+}
+
+// handle implements monitorHandler.
+func (m *Monitor) handle(ctx context.Context, method string, args []json.RawMessage) {
+	// Parameters are [<json-value>, <table-updates>].
+	if method != "update" || len(args) != 2 {
+		return
+	}
+
+	var updates TableUpdates
+	if err := m.c.unmarshal(args[1], &updates); err != nil {
+		return
+	}
+
+	m.push(delivery{
+		send: func(done <-chan struct{}) {
+			select {
+			case <-ctx.Done():
+			case <-done:
+			case m.updates <- updates:
+			}
+		},
+		value: updates,
+		merge: func(next delivery) bool {
+			n, ok := next.value.(TableUpdates)
+			return ok && mergeTableUpdates(updates, n)
+		},
+	})
+}
+
+// remonitor implements monitorHandler.
+func (m *Monitor) remonitor(ctx context.Context, _ *Schema) error {
+	return m.resume(ctx)
+}
+
+// database implements monitorHandler.
+func (m *Monitor) database() string { return m.db }
+
+// resume implements monitorHandler.
+func (m *Monitor) resume(ctx context.Context) error {
+	var initial TableUpdates
+	err := m.c.rpc(ctx, "monitor", &initial, []interface{}{m.db, m.id, m.requests})
+
+	m.finishResume(func(done <-chan struct{}) {
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-done:
+		case m.updates <- initial:
+		}
+	})
+
+	return err
+}
+
+// close implements monitorHandler.
+func (m *Monitor) close() {
+	m.closeFunc(func() {
+		close(m.updates)
+	})
+}
+
+// A monitorHandler is a type which handles update notifications for a
+// single monitor ID.
+type monitorHandler interface {
+	// handle processes a notification for this monitor, where args
+	// contains the notification's parameters.
+	handle(ctx context.Context, method string, args []json.RawMessage)
+
+	// resume re-establishes the monitor after reconnecting, and delivers
+	// its initial contents followed by any held updates.
+	resume(ctx context.Context) error
+
+	// remonitor re-establishes the monitor using schema, the new schema of
+	// its database, after the server canceled it.
+	remonitor(ctx context.Context, schema *Schema) error
+
+	// database returns the name of the monitored database.
+	database() string
+
+	// close stops the monitor and closes its channels.  It must be safe to
+	// call close multiple times.
+	close()
+
+	// base returns the monitor's monitorBase.
+	base() *monitorBase
+}
+
+// monitorBuffer is the number of updates buffered for each monitor.
+const monitorBuffer = 16
+
+// A monitorBase contains the state used by all monitor types.
+type monitorBase struct {
+	c  *Client
+	id string
+
+	// mu protects the delivery queue.  While resuming, updates are held in
+	// pending until the monitor has been re-established.  If set, space is
+	// signaled when a delivery is removed from the queue.
+	mu       sync.Mutex
+	closed   bool
+	started  bool
+	resuming bool
+	queue    []delivery
+	pending  []delivery
+	space    *sync.Cond
+
+	// Signals the delivery goroutine that the queue is not empty.
+	wake chan struct{}
+
+	// sendMu is held while delivering an update, and ensures that updates
+	// are never sent on a closed channel.
+	sendMu     sync.Mutex
+	sendClosed bool
+
+	// Closed when the monitor is canceled to unblock any pending sends.
+	done     chan struct{}
+	doneOnce sync.Once
+
+	// The reason the monitor was stopped by the server, if any.
+	errMu sync.Mutex
+	err   error
+}
+
+// newMonitorBase creates a monitorBase with a unique ID.
+func (c *Client) newMonitorBase() monitorBase {
+	return monitorBase{
+		c: c,
+		// Monitor IDs need not be related to RPC IDs, but the RPC ID counter
+		// is a convenient source of unique values.
+		id:   "monitor-" + c.requestID(),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+// monitor registers h and issues the RPC which creates a monitor.
+func (c *Client) monitor(ctx context.Context, id string, h monitorHandler, method string, out, arg interface{}) error {
+	// Register the monitor before the RPC is sent so that no updates
+	// which immediately follow the RPC response are lost.
+	c.addMonitor(id, h)
+
+	if err := c.rpc(ctx, method, out, arg); err != nil {
+		c.removeMonitor(id)
+		h.close()
+		return err
+	}
+
+	return nil
+}
+
+// cancel unregisters and closes h, and informs the server that the monitor
+// is no longer needed.
+func (b *monitorBase) cancel(ctx context.Context, h monitorHandler) error {
+	// Unregister and close first so the channel is closed even if the RPC
+	// fails.
+	b.c.removeMonitor(b.id)
+	h.close()
+
+	return b.c.rpc(ctx, "monitor_cancel", nil, []string{b.id})
+}
+
+// base implements monitorHandler.
+func (b *monitorBase) base() *monitorBase {
+	return b
+}
+
+// setErr records the reason the monitor was stopped by the server.
+func (b *monitorBase) setErr(err error) {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+
+	b.err = err
+}
+
+// getErr returns the reason the monitor was stopped by the server, if any.
+func (b *monitorBase) getErr() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+
+	return b.err
+}
+
+// push queues d to deliver updates to a monitor's consumer, unless the
+// monitor is closed.
+//
+// Updates are delivered in order by a goroutine for each monitor, so that
+// a slow consumer does not delay RPC responses or other monitors, unless
+// the Client's backpressure policy requires it.
+func (b *monitorBase) push(d delivery) {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+
+	switch {
+	case b.resuming:
+		b.pending = append(b.pending, d)
+	case b.admit(d):
+		b.enqueue(d)
+	}
+
+	n := len(b.queue) + len(b.pending)
+	b.mu.Unlock()
+
+	b.c.queued(b.id, n)
+}
+
+// queueLen returns the number of updates waiting to be delivered.
+func (b *monitorBase) queueLen() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.queue) + len(b.pending)
+}
+
+// enqueue adds sends to the delivery queue, starting the delivery goroutine
+// if needed.  b.mu must be held.
+func (b *monitorBase) enqueue(ds ...delivery) {
+	b.queue = append(b.queue, ds...)
+
+	if !b.started {
+		b.started = true
+		go b.deliver()
+	}
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+		// Delivery goroutine was already signaled.
+	}
+}
+
+// deliver invokes each queued send in order until the monitor is closed.
+func (b *monitorBase) deliver() {
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.mu.Unlock()
+
+			select {
+			case <-b.done:
+				return
+			case <-b.wake:
+				continue
+			}
+		}
+
+		d := b.queue[0]
+		b.queue[0] = delivery{}
+		b.queue = b.queue[1:]
+		if b.space != nil {
+			b.space.Broadcast()
+		}
+		b.mu.Unlock()
+
+		b.sendMu.Lock()
+		if b.sendClosed {
+			b.sendMu.Unlock()
+			return
+		}
+
+		d.send(b.done)
+		b.sendMu.Unlock()
+	}
+}
+
+// startResume holds all further updates until finishResume is called.
+func (b *monitorBase) startResume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.resuming = true
+}
+
+// finishResume queues initial to deliver a monitor's initial contents after
+// resuming, followed by any updates which were held.
+func (b *monitorBase) finishResume(initial func(done <-chan struct{})) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending := b.pending
+	b.resuming = false
+	b.pending = nil
+
+	if b.closed {
+		return
+	}
+
+	b.enqueue(append([]delivery{{send: initial}}, pending...)...)
+}
+
+// closeFunc marks a monitor closed and invokes fn to close its channels.
+// fn is invoked at most once.
+func (b *monitorBase) closeFunc(fn func()) {
+	b.doneOnce.Do(func() {
+		// Unblock any pending send before acquiring the locks.
+		close(b.done)
+
+		b.mu.Lock()
+		b.closed = true
+		b.queue = nil
+		b.pending = nil
+		if b.space != nil {
+			// Wake any update blocked by backpressure.
+			b.space.Broadcast()
+		}
+		b.mu.Unlock()
+
+		b.sendMu.Lock()
+		defer b.sendMu.Unlock()
+
+		b.sendClosed = true
+		fn()
+	})
+}
+
+// addMonitor registers a monitor to receive updates.
+func (c *Client) addMonitor(id string, h monitorHandler) {
+	c.monMu.Lock()
+	defer c.monMu.Unlock()
+
+	if _, ok := c.monitors[id]; ok {
+		// This ID was already registered.
+		panicf("OVSDB monitor with ID %q already registered", id)
+	}
+
+	c.monitors[id] = h
+}
+
+// removeMonitor unregisters the monitor with the specified ID.
+func (c *Client) removeMonitor(id string) {
+	c.monMu.Lock()
+	defer c.monMu.Unlock()
+
+	delete(c.monitors, id)
+}
+
+// closeMonitors unregisters and closes all monitors.
+func (c *Client) closeMonitors() {
+	c.monMu.Lock()
+	defer c.monMu.Unlock()
+
+	for id, h := range c.monitors {
+		h.close()
+		delete(c.monitors, id)
+	}
+}
+
+// doUpdate handles an update notification by delivering its contents to
+// the appropriate monitor.
+func (c *Client) doUpdate(ctx context.Context, method string, params json.RawMessage) {
+	// Parameters always begin with the monitor's <json-value>.
+	var args []json.RawMessage
+	if err := c.unmarshal(params, &args); err != nil || len(args) == 0 {
+		return
+	}
+
+	var id string
+	if err := c.unmarshal(args[0], &id); err != nil {
+		// Not one of our monitor IDs.
+		return
+	}
+
+	c.monMu.RLock()
+	h, ok := c.monitors[id]
+	c.monMu.RUnlock()
+	if !ok {
+		// Nobody is listening to this monitor.
+		return
+	}
+
+	h.handle(ctx, method, args)
+}
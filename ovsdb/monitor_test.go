@@ -0,0 +1,424 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientMonitor(t *testing.T) {
+	const (
+		db   = "Open_vSwitch"
+		uuid = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+	)
+
+	// The monitor ID is chosen by the client, so capture it from the
+	// monitor request.
+	idC := make(chan string, 1)
+	cancelC := make(chan []interface{}, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		ps := req.Params.([]interface{})
+
+		switch req.Method {
+		case "monitor":
+			want := []interface{}{
+				db,
+				ps[1],
+				map[string]interface{}{
+					"Bridge": map[string]interface{}{
+						"columns": []interface{}{"name"},
+					},
+				},
+			}
+
+			if diff := cmp.Diff(want, ps); diff != "" {
+				panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+			}
+
+			idC <- ps[1].(string)
+
+			return jsonrpc.Response{
+				ID: &req.ID,
+				Result: mustMarshalJSON(t, ovsdb.TableUpdates{
+					"Bridge": {
+						uuid: {
+							New: ovsdb.Row{"name": "br0"},
+						},
+					},
+				}),
+			}
+		case "monitor_cancel":
+			cancelC <- ps
+
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, struct{}{}),
+			}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+			return jsonrpc.Response{}
+		}
+	})
+	defer done()
+
+	ctx := context.Background()
+
+	m, err := c.Monitor(ctx, db, map[string]ovsdb.MonitorRequest{
+		"Bridge": {
+			Columns: []string{"name"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	wantInitial := ovsdb.TableUpdates{
+		"Bridge": {
+			uuid: {
+				New: ovsdb.Row{"name": "br0"},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(wantInitial, m.Initial); diff != "" {
+		t.Fatalf("unexpected initial updates (-want +got):\n%s", diff)
+	}
+
+	id := <-idC
+
+	// Send an update for our monitor, and one for a monitor that does
+	// not exist, which should be ignored.
+	update := ovsdb.TableUpdates{
+		"Bridge": {
+			uuid: {
+				Old: ovsdb.Row{"name": "br0"},
+				New: ovsdb.Row{"name": "br1"},
+			},
+		},
+	}
+
+	for _, mid := range []string{"foo", id} {
+		notifC <- &jsonrpc.Response{
+			Method: "update",
+			Params: mustMarshalJSON(t, []interface{}{mid, update}),
+		}
+	}
+
+	if diff := cmp.Diff(update, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+	}
+
+	if err := m.Cancel(ctx); err != nil {
+		t.Fatalf("failed to cancel monitor: %v", err)
+	}
+
+	if diff := cmp.Diff([]interface{}{id}, <-cancelC); diff != "" {
+		t.Fatalf("unexpected monitor_cancel parameters (-want +got):\n%s", diff)
+	}
+
+	if _, ok := <-m.Updates(); ok {
+		t.Fatal("expected updates channel to be closed")
+	}
+
+	if diff := cmp.Diff(0, c.Stats().Monitors.Current); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientMonitorCloseClient(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, ovsdb.TableUpdates{}),
+		}
+	})
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	// Closing the client must also close the monitor's channel.
+	done()
+
+	if _, ok := <-m.Updates(); ok {
+		t.Fatal("expected updates channel to be closed")
+	}
+}
+
+func TestClientMonitorError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: &req.ID,
+			Result: mustMarshalJSON(t, &ovsdb.Error{
+				Err:     "unknown database",
+				Details: "foo",
+			}),
+		}
+	})
+	defer done()
+
+	_, err := c.Monitor(context.Background(), "foo", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if diff := cmp.Diff(0, c.Stats().Monitors.Current); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientMonitorSlowConsumer(t *testing.T) {
+	const n = 64
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		switch req.Method {
+		case "monitor":
+			idC <- req.Params.([]interface{})[1].(string)
+
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, ovsdb.TableUpdates{}),
+			}
+		case "list_dbs":
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+			}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+			return jsonrpc.Response{}
+		}
+	})
+	defer done()
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	id := <-idC
+
+	// Send far more updates than the monitor buffers without consuming any.
+	for i := 0; i < n; i++ {
+		notifC <- &jsonrpc.Response{
+			Method: "update",
+			Params: mustMarshalJSON(t, []interface{}{id, ovsdb.TableUpdates{
+				"Bridge": {
+					strconv.Itoa(i): {New: ovsdb.Row{"n": i}},
+				},
+			}}),
+		}
+	}
+
+	// Concurrent RPCs must still complete while the monitor is backed up.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errC := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := c.ListDatabases(ctx)
+			errC <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errC)
+
+	for err := range errC {
+		if err != nil {
+			t.Fatalf("failed to list databases: %v", err)
+		}
+	}
+
+	// All updates are then delivered in order.
+	for i := 0; i < n; i++ {
+		updates := <-m.Updates()
+
+		if _, ok := updates["Bridge"][strconv.Itoa(i)]; !ok {
+			t.Fatalf("unexpected update %d: %v", i, updates)
+		}
+	}
+}
+
+func TestClientMultipleMonitors(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	ctx := context.Background()
+
+	bridges, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {Columns: []string{"name"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor bridges: %v", err)
+	}
+
+	ports, err := c.MonitorCond(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Port": {Columns: []string{"name"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor ports: %v", err)
+	}
+	defer ports.Cancel(ctx)
+
+	if bridges.ID() == ports.ID() {
+		t.Fatalf("monitors share ID %q", bridges.ID())
+	}
+
+	if diff := cmp.Diff(2, c.Stats().Monitors.Current); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+
+	addBridge := func(bridge, port string) {
+		t.Helper()
+
+		_, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+			ovsdb.Insert{Table: "Port", UUIDName: "port", Row: ovsdb.Row{"name": port}},
+			ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{
+				"name":  bridge,
+				"ports": ovsdb.NamedUUID("port"),
+			}},
+		})
+		if err != nil {
+			t.Fatalf("failed to add bridge: %v", err)
+		}
+	}
+
+	addBridge("br0", "p0")
+
+	// Each monitor receives only the changes to its own tables.
+	timeout := time.After(2 * time.Second)
+
+	select {
+	case u := <-bridges.Updates():
+		for _, ru := range u["Bridge"] {
+			if diff := cmp.Diff(ovsdb.Row{"name": "br0"}, ru.New); diff != "" {
+				t.Fatalf("unexpected bridge (-want +got):\n%s", diff)
+			}
+		}
+		if _, ok := u["Port"]; ok {
+			t.Fatal("bridge monitor received port updates")
+		}
+	case <-timeout:
+		t.Fatal("timed out waiting for bridge updates")
+	}
+
+	wantPort := func(name string) {
+		t.Helper()
+
+		select {
+		case u := <-ports.Updates():
+			for _, ru := range u["Port"] {
+				if diff := cmp.Diff(ovsdb.Row{"name": name}, ru.Insert); diff != "" {
+					t.Fatalf("unexpected port (-want +got):\n%s", diff)
+				}
+			}
+			if _, ok := u["Bridge"]; ok {
+				t.Fatal("port monitor received bridge updates")
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for port updates")
+		}
+	}
+
+	wantPort("p0")
+
+	// Canceling one monitor does not affect the other.
+	if err := bridges.Cancel(ctx); err != nil {
+		t.Fatalf("failed to cancel bridge monitor: %v", err)
+	}
+
+	if _, ok := <-bridges.Updates(); ok {
+		t.Fatal("expected bridge updates channel to be closed")
+	}
+
+	addBridge("br1", "p1")
+	wantPort("p1")
+}
+
+func TestClientMonitorSelect(t *testing.T) {
+	c, done := testDumpServer(t)
+	defer done()
+
+	ctx := context.Background()
+
+	transact := func(ops ...ovsdb.TransactOp) []ovsdb.OperationResult {
+		t.Helper()
+
+		res, err := c.Transact(ctx, "Open_vSwitch", ops)
+		if err != nil {
+			t.Fatalf("failed to transact: %v", err)
+		}
+
+		return res
+	}
+
+	transact(ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br0"}})
+
+	// Report only insertions, and only the name column.
+	m, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {
+			Columns: []string{"name"},
+			Select:  &ovsdb.MonitorSelect{Insert: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(0, len(m.Initial)); diff != "" {
+		t.Fatalf("unexpected number of initial tables (-want +got):\n%s", diff)
+	}
+
+	br0 := []ovsdb.Cond{ovsdb.Equal("name", "br0")}
+	transact(
+		ovsdb.Update{Table: "Bridge", Where: br0, Row: ovsdb.Row{"external_ids": ovsdb.Map{"foo": "bar"}}},
+		ovsdb.Update{Table: "Bridge", Where: br0, Row: ovsdb.Row{"name": "br1"}},
+	)
+	transact(ovsdb.Delete{Table: "Bridge"})
+	res := transact(ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br2"}})
+
+	// The modifications and deletion are not reported.
+	want := ovsdb.TableUpdates{"Bridge": {
+		res[0].UUID: {New: ovsdb.Row{"name": "br2"}},
+	}}
+
+	if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A MonitorCondRequest specifies which columns and rows of a table should be
+// monitored by a CondMonitor.
+type MonitorCondRequest struct {
+	// Zero or more columns to monitor.  If empty, all columns are monitored.
+	Columns []string
+
+	// Zero or more Conds which determine the rows to monitor.  A row is
+	// monitored if it matches any of the Conds.  If empty, all rows are
+	// monitored.
+	Where []Cond
+
+	// The kinds of changes to monitor.  If nil, all changes are monitored.
+	Select *MonitorSelect
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r MonitorCondRequest) MarshalJSON() ([]byte, error) {
+	req := struct {
+		Columns []string       `json:"columns,omitempty"`
+		Where   []Cond         `json:"where,omitempty"`
+		Select  *MonitorSelect `json:"select,omitempty"`
+	}{
+		Columns: r.Columns,
+		Where:   r.Where,
+		Select:  r.Select,
+	}
+
+	return json.Marshal(req)
+}
+
+// TableUpdates2 contains changes to one or more tables reported by a
+// CondMonitor, keyed by table name.
+type TableUpdates2 map[string]TableUpdate2
+
+// A TableUpdate2 contains changes to the rows of a single table reported by
+// a CondMonitor, keyed by row UUID.
+type TableUpdate2 map[string]RowUpdate2
+
+// A RowUpdate2 describes a change to a single row reported by a CondMonitor.
+// Exactly one of its fields is set.
+type RowUpdate2 struct {
+	// The contents of a row present when the monitor was created.
+	Initial Row
+
+	// The contents of a newly inserted row.
+	Insert Row
+
+	// Set if the row was deleted.
+	Delete bool
+
+	// The columns of a modified row which changed.  For scalar columns,
+	// the value is the new value.  For set and map columns, the value
+	// contains the elements which were added or removed.
+	Modify Row
+}
+
+// rowUpdate2 is the wire representation of a RowUpdate2.  Delete is a
+// pointer to distinguish an absent member from a null one.
+type rowUpdate2 struct {
+	Initial Row              `json:"initial,omitempty"`
+	Insert  Row              `json:"insert,omitempty"`
+	Delete  *json.RawMessage `json:"delete,omitempty"`
+	Modify  Row              `json:"modify,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r RowUpdate2) MarshalJSON() ([]byte, error) {
+	u := rowUpdate2{
+		Initial: r.Initial,
+		Insert:  r.Insert,
+		Modify:  r.Modify,
+	}
+
+	if r.Delete {
+		null := json.RawMessage("null")
+		u.Delete = &null
+	}
+
+	return json.Marshal(u)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *RowUpdate2) UnmarshalJSON(b []byte) error {
+	// The member is present but null for deletions, so unmarshal into a
+	// map first to check for its presence.
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	var u rowUpdate2
+	if err := json.Unmarshal(b, &u); err != nil {
+		return err
+	}
+
+	_, del := m["delete"]
+	*r = RowUpdate2{
+		Initial: u.Initial,
+		Insert:  u.Insert,
+		Delete:  del,
+		Modify:  u.Modify,
+	}
+
+	return nil
+}
+
+// A CondMonitor receives notifications about changes to a subset of a
+// database's tables and rows.  CondMonitors are created using
+// Client.MonitorCond.
+type CondMonitor struct {
+	// Initial contains the contents of the monitored rows at the time
+	// the CondMonitor was created.  If Found is true, Initial contains only
+	// the changes which occurred after the transaction ID passed to
+	// Client.MonitorCondSince.
+	Initial TableUpdates2
+
+	// Found reports whether the server was able to resume monitoring from
+	// the transaction ID passed to Client.MonitorCondSince.  Found is always
+	// false for CondMonitors created by Client.MonitorCond.
+	Found bool
+
+	monitorBase
+	updates chan TableUpdates2
+
+	// The ID of the last transaction seen by this monitor, when created
+	// with monitor_cond_since.
+	txnMu     sync.Mutex
+	lastTxnID string
+
+	// Used to re-establish the monitor after reconnecting.  reqMu guards
+	// requests, whose conditions may be changed.
+	db       string
+	reqMu    sync.Mutex
+	requests map[string]MonitorCondRequest
+	since    bool
+
+	// If set, called with the database's new schema before the monitor is
+	// re-established after the server canceled it.  Guarded by reqMu.
+	onSchema func(s *Schema) error
+
+	// If set, a nil TableUpdates2 is sent before the full contents of the
+	// monitored rows are delivered again after reconnecting, so that a
+	// Cache can discard rows which were deleted while disconnected.
+	resets bool
+
+	// If set, updates are delivered on cacheUpdates along with the ID of
+	// their transaction rather than on updates, so that a Cache can record
+	// the transaction ID of its contents.
+	cacheUpdates chan txnUpdates
+}
+
+// A txnUpdates is a TableUpdates2 and the ID of the transaction which
+// produced it, if any.
+type txnUpdates struct {
+	updates TableUpdates2
+	txnID   string
+}
+
+// errNoRequests is returned when a conditional monitor is created without
+// any requests.
+var errNoRequests = errors.New("at least one monitor request must be specified")
+
+// MonitorCond begins monitoring the rows which match a set of conditions in
+// one or more tables of the specified database, using the monitor_cond RPC.
+// The keys of requests are the names of the tables to monitor.
+//
+// The returned CondMonitor contains the initial contents of the matching
+// rows, and further changes are delivered via CondMonitor.Updates until
+// CondMonitor.Cancel is called or the Client is closed.
+func (c *Client) MonitorCond(ctx context.Context, db string, requests map[string]MonitorCondRequest) (*CondMonitor, error) {
+	return c.monitorCond(ctx, db, requests, false)
+}
+
+// monitorCond implements MonitorCond, and optionally creates a CondMonitor
+// for use by a Cache.
+func (c *Client) monitorCond(ctx context.Context, db string, requests map[string]MonitorCondRequest, cache bool) (*CondMonitor, error) {
+	if len(requests) == 0 {
+		return nil, errNoRequests
+	}
+
+	m := c.newCondMonitor(db, requests, cache)
+
+	var initial TableUpdates2
+	if err := c.monitor(ctx, m.id, m, "monitor_cond", &initial, []interface{}{db, m.id, requests}); err != nil {
+		return nil, err
+	}
+
+	m.Initial = initial
+	return m, nil
+}
+
+// ZeroTransactionID is a transaction ID which can be passed to
+// Client.MonitorCondSince to request the full contents of the monitored rows.
+const ZeroTransactionID = "00000000-0000-0000-0000-000000000000"
+
+// MonitorCondSince is like MonitorCond, but uses the monitor_cond_since RPC
+// to resume monitoring from the transaction ID lastTxnID, typically obtained
+// from CondMonitor.LastTransactionID before a previous connection was lost.
+//
+// If the server still has a record of lastTxnID, the returned CondMonitor's
+// Found field is true and its Initial field contains only the changes which
+// occurred after that transaction.  Otherwise, Initial contains the full
+// contents of the matching rows.  If lastTxnID is empty, ZeroTransactionID
+// is used.
+func (c *Client) MonitorCondSince(ctx context.Context, db string, requests map[string]MonitorCondRequest, lastTxnID string) (*CondMonitor, error) {
+	return c.monitorCondSince(ctx, db, requests, lastTxnID, false)
+}
+
+// monitorCondSince implements MonitorCondSince, and optionally creates a
+// CondMonitor for use by a Cache.
+func (c *Client) monitorCondSince(ctx context.Context, db string, requests map[string]MonitorCondRequest, lastTxnID string, cache bool) (*CondMonitor, error) {
+	if len(requests) == 0 {
+		return nil, errNoRequests
+	}
+
+	if lastTxnID == "" {
+		lastTxnID = ZeroTransactionID
+	}
+
+	m := c.newCondMonitor(db, requests, cache)
+	m.since = true
+
+	// Result is [<found>, <last-txn-id>, <table-updates2>].
+	var (
+		found   bool
+		txnID   string
+		initial TableUpdates2
+	)
+
+	out := []interface{}{&found, &txnID, &initial}
+	arg := []interface{}{db, m.id, requests, lastTxnID}
+
+	if err := c.monitor(ctx, m.id, m, "monitor_cond_since", &out, arg); err != nil {
+		return nil, err
+	}
+
+	m.Initial = initial
+	m.Found = found
+
+	// An update3 notification may have already been handled by the time
+	// the RPC returns, and its transaction ID is more recent.
+	m.txnMu.Lock()
+	if m.lastTxnID == "" {
+		m.lastTxnID = txnID
+	}
+	m.txnMu.Unlock()
+
+	return m, nil
+}
+
+// newCondMonitor creates a CondMonitor.  If cache is set, the CondMonitor
+// delivers updates for use by a Cache.
+func (c *Client) newCondMonitor(db string, requests map[string]MonitorCondRequest, cache bool) *CondMonitor {
+	m := &CondMonitor{
+		monitorBase: c.newMonitorBase(),
+		updates:     make(chan TableUpdates2, monitorBuffer),
+		db:          db,
+		requests:    requests,
+	}
+
+	if cache {
+		m.resets = true
+		m.cacheUpdates = make(chan txnUpdates, monitorBuffer)
+	}
+
+	return m
+}
+
+// LastTransactionID returns the ID of the last transaction reported to a
+// CondMonitor created by Client.MonitorCondSince.  It returns an empty string
+// if the server did not report a transaction ID.
+//
+// The ID is only updated once the transaction's updates are sent on the
+// updates channel, so it may briefly lag behind the updates received.
+func (m *CondMonitor) LastTransactionID() string {
+	m.txnMu.Lock()
+	defer m.txnMu.Unlock()
+
+	return m.lastTxnID
+}
+
+// setLastTransactionID stores the ID of the last transaction seen.
+func (m *CondMonitor) setLastTransactionID(id string) {
+	m.txnMu.Lock()
+	defer m.txnMu.Unlock()
+
+	m.lastTxnID = id
+}
+
+// ID returns the CondMonitor's ID, which is unique within its Client and is
+// reported to ClientHooks.Update.
+func (m *CondMonitor) ID() string {
+	return m.id
+}
+
+// Updates returns a channel which receives changes to the monitored rows.
+// The channel is closed when the CondMonitor is canceled or its Client is
+// closed.
+//
+// Updates are queued in memory until they are received, so the channel
+// should be drained promptly.  A slow consumer does not delay RPCs or
+// other monitors, unless the MonitorBackpressure option specifies
+// otherwise.
+func (m *CondMonitor) Updates() <-chan TableUpdates2 {
+	return m.updates
+}
+
+// Cancel stops the CondMonitor and closes its updates channel.
+func (m *CondMonitor) Cancel(ctx context.Context) error {
+	return m.cancel(ctx, m)
+}
+
+// ChangeConditions replaces the conditions which determine the rows monitored
+// in one or more tables, using the monitor_cond_change RPC.  The keys of
+// where are the names of the tables, which must already be monitored.  The
+// conditions of other tables are unchanged.  An empty slice of Conds
+// monitors all rows in a table.
+//
+// Rows which begin to match the new conditions are delivered as insertions on
+// the updates channel, and rows which no longer match are delivered as
+// deletions.  The new conditions are also used if the CondMonitor is
+// re-established after reconnecting.
+func (m *CondMonitor) ChangeConditions(ctx context.Context, where map[string][]Cond) error {
+	if len(where) == 0 {
+		return errors.New("at least one table's conditions must be specified")
+	}
+
+	m.reqMu.Lock()
+	defer m.reqMu.Unlock()
+
+	// Copy the requests so that the caller's map is not modified, and so
+	// that they are unchanged if the RPC fails.
+	requests := make(map[string]MonitorCondRequest, len(m.requests))
+	for table, req := range m.requests {
+		requests[table] = req
+	}
+
+	changes := make(map[string][]MonitorCondRequest, len(where))
+	for table, conds := range where {
+		req, ok := requests[table]
+		if !ok {
+			return fmt.Errorf("cannot change conditions of table %q which is not monitored", table)
+		}
+
+		req.Where = conds
+		requests[table] = req
+
+		// Only the conditions may change.
+		changes[table] = []MonitorCondRequest{{Where: conds}}
+	}
+
+	// The monitor's ID is unchanged.
+	if err := m.c.rpc(ctx, "monitor_cond_change", nil, []interface{}{m.id, m.id, changes}); err != nil {
+		return err
+	}
+
+	m.requests = requests
+	return nil
+}
+
+// Err returns ErrMonitorCanceled if the OVSDB server canceled the
+// CondMonitor, or nil otherwise.  Err should be checked after the updates
+// channel is closed.
+func (m *CondMonitor) Err() error {
+	return m.getErr()
+}
+
+// handle implements monitorHandler.
+func (m *CondMonitor) handle(ctx context.Context, method string, args []json.RawMessage) {
+	var u txnUpdates
+
+	switch {
+	case method == "update2" && len(args) == 2:
+		// Parameters are [<json-value>, <table-updates2>].
+		if err := m.c.unmarshal(args[1], &u.updates); err != nil {
+			return
+		}
+	case method == "update3" && len(args) == 3:
+		// Parameters are [<json-value>, <last-txn-id>, <table-updates2>].
+		if err := m.c.unmarshal(args[1], &u.txnID); err != nil {
+			return
+		}
+		if err := m.c.unmarshal(args[2], &u.updates); err != nil {
+			return
+		}
+	default:
+		return
+	}
+
+	// Record the transaction ID only once the updates are delivered, so
+	// that IDs are recorded in order even while resuming, and updates which
+	// are dropped are not skipped when resuming from the ID.
+	m.push(delivery{
+		send: func(done <-chan struct{}) {
+			if m.send(ctx, done, u.updates, u.txnID) && u.txnID != "" {
+				m.setLastTransactionID(u.txnID)
+			}
+		},
+		value: &u,
+		merge: func(next delivery) bool {
+			n, ok := next.value.(*txnUpdates)
+			if !ok || !mergeTableUpdates2(u.updates, n.updates) {
+				return false
+			}
+
+			// The merged updates reflect the later transaction.
+			if n.txnID != "" {
+				u.txnID = n.txnID
+			}
+			return true
+		},
+	})
+}
+
+// send delivers updates from the transaction txnID to the CondMonitor's
+// consumer, and reports whether they were delivered.
+func (m *CondMonitor) send(ctx context.Context, done <-chan struct{}, updates TableUpdates2, txnID string) bool {
+	if m.cacheUpdates != nil {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-done:
+			return false
+		case m.cacheUpdates <- txnUpdates{updates: updates, txnID: txnID}:
+			return true
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-done:
+		return false
+	case m.updates <- updates:
+		return true
+	}
+}
+
+// remonitor implements monitorHandler.
+func (m *CondMonitor) remonitor(ctx context.Context, schema *Schema) error {
+	m.reqMu.Lock()
+	fn := m.onSchema
+	m.reqMu.Unlock()
+
+	if fn != nil {
+		if err := fn(schema); err != nil {
+			// Release any held updates, which are discarded when the
+			// monitor is closed.
+			m.finishResume(func(<-chan struct{}) {})
+			return err
+		}
+	}
+
+	return m.resume(ctx)
+}
+
+// database implements monitorHandler.
+func (m *CondMonitor) database() string { return m.db }
+
+// resume implements monitorHandler.
+func (m *CondMonitor) resume(ctx context.Context) error {
+	var (
+		initial TableUpdates2
+		txnID   string
+		found   bool
+		err     error
+	)
+
+	m.reqMu.Lock()
+	requests := m.requests
+	m.reqMu.Unlock()
+
+	if m.since {
+		// Only request changes since the last transaction seen.
+		out := []interface{}{&found, &txnID, &initial}
+		arg := []interface{}{m.db, m.id, requests, m.LastTransactionID()}
+
+		err = m.c.rpc(ctx, "monitor_cond_since", &out, arg)
+	} else {
+		err = m.c.rpc(ctx, "monitor_cond", &initial, []interface{}{m.db, m.id, requests})
+	}
+
+	m.finishResume(func(done <-chan struct{}) {
+		if err != nil {
+			return
+		}
+
+		// The full contents are delivered unless the server found the
+		// last transaction ID.
+		if m.resets && !found && !m.send(ctx, done, nil, "") {
+			return
+		}
+
+		// Any held updates are more recent than this transaction ID.
+		if m.send(ctx, done, initial, txnID) && txnID != "" {
+			m.setLastTransactionID(txnID)
+		}
+	})
+
+	return err
+}
+
+// close implements monitorHandler.
+func (m *CondMonitor) close() {
+	m.closeFunc(func() {
+		close(m.updates)
+		if m.cacheUpdates != nil {
+			close(m.cacheUpdates)
+		}
+	})
+}
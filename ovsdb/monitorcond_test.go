@@ -0,0 +1,490 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientMonitorCond(t *testing.T) {
+	const (
+		db   = "OVN_Southbound"
+		uuid = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+	)
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("monitor_cond", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		ps := req.Params.([]interface{})
+
+		want := []interface{}{
+			db,
+			ps[1],
+			map[string]interface{}{
+				"Port_Binding": map[string]interface{}{
+					"columns": []interface{}{"logical_port", "chassis"},
+					"where": []interface{}{
+						[]interface{}{"logical_port", "==", "foo"},
+					},
+				},
+			},
+		}
+
+		if diff := cmp.Diff(want, ps); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		idC <- ps[1].(string)
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`{"Port_Binding":{"` + uuid + `":{"initial":{"logical_port":"foo"}}}}`),
+		}
+	})
+	defer done()
+
+	m, err := c.MonitorCond(context.Background(), db, map[string]ovsdb.MonitorCondRequest{
+		"Port_Binding": {
+			Columns: []string{"logical_port", "chassis"},
+			Where: []ovsdb.Cond{
+				ovsdb.Equal("logical_port", "foo"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	wantInitial := ovsdb.TableUpdates2{
+		"Port_Binding": {
+			uuid: {
+				Initial: ovsdb.Row{"logical_port": "foo"},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(wantInitial, m.Initial); diff != "" {
+		t.Fatalf("unexpected initial updates (-want +got):\n%s", diff)
+	}
+
+	id := <-idC
+
+	// A plain update notification for this ID is not valid for a
+	// conditional monitor and should be ignored.
+	notifC <- &jsonrpc.Response{
+		Method: "update",
+		Params: mustMarshalJSON(t, []interface{}{id, ovsdb.TableUpdates{}}),
+	}
+
+	notifC <- &jsonrpc.Response{
+		Method: "update2",
+		Params: []byte(`["` + id + `",{"Port_Binding":{"` + uuid + `":{"delete":null}}}]`),
+	}
+
+	want := ovsdb.TableUpdates2{
+		"Port_Binding": {
+			uuid: {
+				Delete: true,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientMonitorCondNoRequests(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		panicf("unexpected RPC: %q", req.Method)
+		return jsonrpc.Response{}
+	})
+	defer done()
+
+	if _, err := c.MonitorCond(context.Background(), "OVN_Southbound", nil); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestRowUpdate2JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		r    ovsdb.RowUpdate2
+	}{
+		{
+			name: "initial",
+			s:    `{"initial":{"name":"br0"}}`,
+			r:    ovsdb.RowUpdate2{Initial: ovsdb.Row{"name": "br0"}},
+		},
+		{
+			name: "insert",
+			s:    `{"insert":{"name":"br0"}}`,
+			r:    ovsdb.RowUpdate2{Insert: ovsdb.Row{"name": "br0"}},
+		},
+		{
+			name: "delete",
+			s:    `{"delete":null}`,
+			r:    ovsdb.RowUpdate2{Delete: true},
+		},
+		{
+			name: "modify",
+			s:    `{"modify":{"name":"br1"}}`,
+			r:    ovsdb.RowUpdate2{Modify: ovsdb.Row{"name": "br1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r ovsdb.RowUpdate2
+			if err := json.Unmarshal([]byte(tt.s), &r); err != nil {
+				t.Fatalf("failed to unmarshal JSON: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.r, r); diff != "" {
+				t.Fatalf("unexpected RowUpdate2 (-want +got):\n%s", diff)
+			}
+
+			b, err := json.Marshal(r)
+			if err != nil {
+				t.Fatalf("failed to marshal JSON: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.s, string(b)); diff != "" {
+				t.Fatalf("unexpected JSON (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientMonitorCondSince(t *testing.T) {
+	const (
+		db    = "OVN_Southbound"
+		uuid  = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+		txn1  = "1e4e3bd8-2d1a-4fd4-a0b4-7c4d6b9f1d01"
+		txn2  = "1e4e3bd8-2d1a-4fd4-a0b4-7c4d6b9f1d02"
+		txn3  = "1e4e3bd8-2d1a-4fd4-a0b4-7c4d6b9f1d03"
+		table = "Chassis"
+	)
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("monitor_cond_since", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		ps := req.Params.([]interface{})
+
+		want := []interface{}{
+			db,
+			ps[1],
+			map[string]interface{}{
+				table: map[string]interface{}{},
+			},
+			txn1,
+		}
+
+		if diff := cmp.Diff(want, ps); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		idC <- ps[1].(string)
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`[true,"` + txn2 + `",{"Chassis":{"` + uuid + `":{"modify":{"name":"foo"}}}}]`),
+		}
+	})
+	defer done()
+
+	m, err := c.MonitorCondSince(context.Background(), db, map[string]ovsdb.MonitorCondRequest{
+		table: {},
+	}, txn1)
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if !m.Found {
+		t.Fatal("expected transaction ID to be found")
+	}
+
+	wantInitial := ovsdb.TableUpdates2{
+		table: {
+			uuid: {
+				Modify: ovsdb.Row{"name": "foo"},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(wantInitial, m.Initial); diff != "" {
+		t.Fatalf("unexpected initial updates (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(txn2, m.LastTransactionID()); diff != "" {
+		t.Fatalf("unexpected initial transaction ID (-want +got):\n%s", diff)
+	}
+
+	id := <-idC
+
+	notifC <- &jsonrpc.Response{
+		Method: "update3",
+		Params: []byte(`["` + id + `","` + txn3 + `",{"Chassis":{"` + uuid + `":{"delete":null}}}]`),
+	}
+
+	want := ovsdb.TableUpdates2{
+		table: {
+			uuid: {
+				Delete: true,
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates (-want +got):\n%s", diff)
+	}
+
+	waitTransactionID(t, m, txn3)
+}
+
+func TestClientMonitorCondSinceDroppedUpdate(t *testing.T) {
+	const (
+		db    = "OVN_Southbound"
+		uuid  = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+		table = "Chassis"
+	)
+
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		switch req.Method {
+		case "monitor_cond_since":
+			idC <- req.Params.([]interface{})[1].(string)
+
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: []byte(`[false,"` + ovsdb.ZeroTransactionID + `",{}]`),
+			}
+		case "monitor_cancel":
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, struct{}{}),
+			}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+			return jsonrpc.Response{}
+		}
+	})
+	defer done()
+
+	m, err := c.MonitorCondSince(context.Background(), db, map[string]ovsdb.MonitorCondRequest{
+		table: {},
+	}, "")
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	id := <-idC
+
+	// Send more updates than the monitor buffers, without consuming any,
+	// so that the final update is dropped when the monitor is canceled.
+	txnID := func(i int) string {
+		return fmt.Sprintf("1e4e3bd8-2d1a-4fd4-a0b4-7c4d6b9f%04d", i)
+	}
+
+	const n = 16
+	for i := 1; i <= n+1; i++ {
+		notifC <- &jsonrpc.Response{
+			Method: "update3",
+			Params: []byte(`["` + id + `","` + txnID(i) + `",{"Chassis":{"` + uuid + `":{"modify":{"n":` + strconv.Itoa(i) + `}}}}]`),
+		}
+	}
+
+	waitTransactionID(t, m, txnID(n))
+
+	// Give the client time to block while delivering the final update.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := m.Cancel(context.Background()); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	// The dropped update's transaction must not be reported.
+	if diff := cmp.Diff(txnID(n), m.LastTransactionID()); diff != "" {
+		t.Fatalf("unexpected transaction ID (-want +got):\n%s", diff)
+	}
+
+	var got int
+	for range m.Updates() {
+		got++
+	}
+
+	if diff := cmp.Diff(n, got); diff != "" {
+		t.Fatalf("unexpected number of updates (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientMonitorCondSinceZero(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		ps := req.Params.([]interface{})
+
+		if diff := cmp.Diff(ovsdb.ZeroTransactionID, ps[3]); diff != "" {
+			panicf("unexpected transaction ID (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`[false,"",{}]`),
+		}
+	})
+	defer done()
+
+	m, err := c.MonitorCondSince(context.Background(), "OVN_Southbound", map[string]ovsdb.MonitorCondRequest{
+		"Chassis": {},
+	}, "")
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if m.Found {
+		t.Fatal("expected transaction ID not to be found")
+	}
+}
+
+// waitTransactionID waits for a CondMonitor to report the transaction ID want.
+func waitTransactionID(t *testing.T, m *ovsdb.CondMonitor, want string) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		got := m.LastTransactionID()
+		if got == want {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("unexpected transaction ID (-want +got):\n%s", cmp.Diff(want, got))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestClientMonitorCondChange(t *testing.T) {
+	const db = "OVN_Southbound"
+
+	paramsC := make(chan []interface{}, 4)
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		ps := req.Params.([]interface{})
+
+		var res interface{} = struct{}{}
+		switch req.Method {
+		case "monitor_cond":
+			res = ovsdb.TableUpdates2{}
+		case "monitor_cond_change":
+			// The monitor's ID is unchanged.
+			if diff := cmp.Diff(ps[0], ps[1]); diff != "" {
+				panicf("unexpected new monitor ID (-want +got):\n%s", diff)
+			}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+		}
+
+		paramsC <- ps
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+	defer d.done()
+
+	c := d.client(ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond))
+	defer c.Close()
+
+	ctx := context.Background()
+
+	requests := map[string]ovsdb.MonitorCondRequest{
+		"Port_Binding": {
+			Columns: []string{"logical_port"},
+			Where:   []ovsdb.Cond{ovsdb.Equal("logical_port", "foo")},
+		},
+	}
+
+	m, err := c.MonitorCond(ctx, db, requests)
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+	<-paramsC
+
+	if err := m.ChangeConditions(ctx, map[string][]ovsdb.Cond{
+		"Chassis": nil,
+	}); err == nil {
+		t.Fatal("expected an error changing an unmonitored table, but none occurred")
+	}
+
+	if err := m.ChangeConditions(ctx, map[string][]ovsdb.Cond{
+		"Port_Binding": {ovsdb.Equal("logical_port", "bar")},
+	}); err != nil {
+		t.Fatalf("failed to change conditions: %v", err)
+	}
+
+	where := []interface{}{
+		[]interface{}{"logical_port", "==", "bar"},
+	}
+
+	wantChange := map[string]interface{}{
+		"Port_Binding": []interface{}{
+			map[string]interface{}{"where": where},
+		},
+	}
+
+	if diff := cmp.Diff(wantChange, (<-paramsC)[2]); diff != "" {
+		t.Fatalf("unexpected change requests (-want +got):\n%s", diff)
+	}
+
+	// The caller's requests are not modified.
+	if diff := cmp.Diff("foo", requests["Port_Binding"].Where[0].Value); diff != "" {
+		t.Fatalf("unexpected original condition (-want +got):\n%s", diff)
+	}
+
+	// The new conditions are used to re-establish the monitor.
+	d.drop()
+
+	wantResume := map[string]interface{}{
+		"Port_Binding": map[string]interface{}{
+			"columns": []interface{}{"logical_port"},
+			"where":   where,
+		},
+	}
+
+	if diff := cmp.Diff(wantResume, (<-paramsC)[2]); diff != "" {
+		t.Fatalf("unexpected resumed requests (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// Directions which an ACL may apply to.
+const (
+	DirectionFromLPort = "from-lport"
+	DirectionToLPort   = "to-lport"
+)
+
+// Actions which an ACL may take.
+const (
+	ActionAllow          = "allow"
+	ActionAllowRelated   = "allow-related"
+	ActionAllowStateless = "allow-stateless"
+	ActionDrop           = "drop"
+	ActionReject         = "reject"
+)
+
+// Limits on the values of ACL columns.
+const (
+	MaxACLPriority   = 32767
+	MaxACLNameLength = 63
+)
+
+// Validate checks that an ACL's direction, priority, action, and log
+// severity are valid, and that its match expression is well-formed.
+//
+// The syntax of the match expression is only checked for common errors, such
+// as unbalanced parentheses or "=" in place of "==".  ovn-northd performs
+// full validation once the ACL is created.
+func (a *ACL) Validate() error {
+	switch a.Direction {
+	case DirectionFromLPort, DirectionToLPort:
+	default:
+		return fmt.Errorf("invalid ACL direction %q", a.Direction)
+	}
+
+	if a.Priority < 0 || a.Priority > MaxACLPriority {
+		return fmt.Errorf("ACL priority %d must be between 0 and %d", a.Priority, MaxACLPriority)
+	}
+
+	switch a.Action {
+	case ActionAllow, ActionAllowRelated, ActionAllowStateless, ActionDrop, ActionReject:
+	default:
+		return fmt.Errorf("invalid ACL action %q", a.Action)
+	}
+
+	if a.Severity != nil {
+		switch *a.Severity {
+		case "alert", "warning", "notice", "info", "debug":
+		default:
+			return fmt.Errorf("invalid ACL log severity %q", *a.Severity)
+		}
+	}
+
+	if a.Name != nil && len(*a.Name) > MaxACLNameLength {
+		return fmt.Errorf("ACL name %q is longer than %d bytes", *a.Name, MaxACLNameLength)
+	}
+
+	if err := validateMatch(a.Match); err != nil {
+		return fmt.Errorf("invalid ACL match %q: %v", a.Match, err)
+	}
+
+	return nil
+}
+
+// ACLs returns all ACLs.
+func (c *Client) ACLs(ctx context.Context) ([]ACL, error) {
+	var out []ACL
+	err := c.selectRows(ctx, TableACL, nil, func(row ovsdb.Row) error {
+		var acl ACL
+		if err := acl.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, acl)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// PortGroup returns the port group with the specified name, and reports
+// whether it exists.
+func (c *Client) PortGroup(ctx context.Context, name string) (*PortGroup, bool, error) {
+	var pg PortGroup
+	ok, err := c.get(ctx, TablePortGroup, name, &pg)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &pg, true, nil
+}
+
+// SwitchACLs returns the ACLs which are attached to a logical switch.  The
+// switch must exist.
+func (c *Client) SwitchACLs(ctx context.Context, sw string) ([]ACL, error) {
+	ls, ok, err := c.LogicalSwitch(ctx, sw)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("logical switch %q does not exist", sw)
+	}
+
+	return c.aclsIn(ctx, ls.ACLs)
+}
+
+// PortGroupACLs returns the ACLs which are attached to a port group.  The
+// port group must exist.
+func (c *Client) PortGroupACLs(ctx context.Context, pg string) ([]ACL, error) {
+	g, ok, err := c.PortGroup(ctx, pg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("port group %q does not exist", pg)
+	}
+
+	return c.aclsIn(ctx, g.ACLs)
+}
+
+// AddSwitchACL validates and creates an ACL, attaches it to a logical
+// switch, and returns the ACL's UUID.  The switch must exist.
+func (c *Client) AddSwitchACL(ctx context.Context, sw string, acl ACL) (string, error) {
+	ls, ok, err := c.LogicalSwitch(ctx, sw)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("logical switch %q does not exist", sw)
+	}
+
+	return c.addACL(ctx, TableLogicalSwitch, ls.UUID, LogicalSwitchColumnACLs, acl)
+}
+
+// AddPortGroupACL validates and creates an ACL, attaches it to a port group,
+// and returns the ACL's UUID.  The port group must exist.
+func (c *Client) AddPortGroupACL(ctx context.Context, pg string, acl ACL) (string, error) {
+	g, ok, err := c.PortGroup(ctx, pg)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("port group %q does not exist", pg)
+	}
+
+	return c.addACL(ctx, TablePortGroup, g.UUID, PortGroupColumnACLs, acl)
+}
+
+// UpdateACL validates acl and updates the existing ACL with the UUID
+// acl.UUID.  If one or more columns are specified, only those columns are
+// updated.
+func (c *Client) UpdateACL(ctx context.Context, acl ACL, columns ...string) error {
+	if acl.UUID == "" {
+		return errors.New("ACL UUID must be specified to update an ACL")
+	}
+
+	if err := acl.Validate(); err != nil {
+		return err
+	}
+
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Update{
+			Table: TableACL,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(acl.UUID))},
+			Row:   acl.Row(columns...),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res[0].Count == 0 {
+		return fmt.Errorf("ACL %q does not exist", acl.UUID)
+	}
+
+	return nil
+}
+
+// DeleteSwitchACL detaches the ACL with the specified UUID from a logical
+// switch, which deletes the ACL.  The ACL may or may not already be
+// attached.
+func (c *Client) DeleteSwitchACL(ctx context.Context, sw, uuid string) error {
+	return c.deleteACL(ctx, TableLogicalSwitch, sw, LogicalSwitchColumnACLs, uuid)
+}
+
+// DeletePortGroupACL detaches the ACL with the specified UUID from a port
+// group, which deletes the ACL.  The ACL may or may not already be attached.
+func (c *Client) DeletePortGroupACL(ctx context.Context, pg, uuid string) error {
+	return c.deleteACL(ctx, TablePortGroup, pg, PortGroupColumnACLs, uuid)
+}
+
+// addACL creates an ACL referenced by column of the row with the specified
+// UUID in table.
+func (c *Client) addACL(ctx context.Context, table, uuid, column string, acl ACL) (string, error) {
+	if err := acl.Validate(); err != nil {
+		return "", err
+	}
+
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table:    TableACL,
+			UUIDName: "new_acl",
+			Row:      acl.Row(),
+		},
+		addRef(table, uuid, column, "new_acl"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res[0].UUID, nil
+}
+
+// deleteACL removes a reference to an ACL from column of the row named name
+// in table.  ACLs are not root rows, so ovsdb-server deletes the ACL once it
+// is no longer referenced.
+func (c *Client) deleteACL(ctx context.Context, table, name, column, uuid string) error {
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: table,
+			Where: []ovsdb.Cond{ovsdb.Equal("name", name)},
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete(column, ovsdb.Set{ovsdb.UUID(uuid)}),
+			},
+		},
+	})
+}
+
+// aclsIn returns the ACLs with the specified UUIDs.
+func (c *Client) aclsIn(ctx context.Context, uuids []string) ([]ACL, error) {
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+
+	want := make(map[string]bool, len(uuids))
+	for _, u := range uuids {
+		want[u] = true
+	}
+
+	acls, err := c.ACLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ACL, 0, len(uuids))
+	for _, acl := range acls {
+		if want[acl.UUID] {
+			out = append(out, acl)
+		}
+	}
+
+	return out, nil
+}
+
+// validateMatch checks an OVN match expression for common syntax errors.
+func validateMatch(match string) error {
+	if strings.TrimSpace(match) == "" {
+		return errors.New("match must not be empty")
+	}
+
+	var (
+		// Open parentheses and braces, which must be closed in order.
+		open []byte
+		// The most recent operator, if it was the last token.
+		operator string
+	)
+
+	for i := 0; i < len(match); {
+		b := match[i]
+
+		switch {
+		case b == ' ', b == '\t', b == '\n':
+			i++
+			continue
+		case b == '"':
+			// Skip a quoted string, which may contain escaped quotes.
+			j := i + 1
+			for ; j < len(match) && match[j] != '"'; j++ {
+				if match[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(match) {
+				return fmt.Errorf("unterminated string at offset %d", i)
+			}
+
+			i = j + 1
+			operator = ""
+		case b == '(' || b == '{':
+			open = append(open, b)
+			i++
+			operator = ""
+		case b == ')' || b == '}':
+			want := byte('(')
+			if b == '}' {
+				want = '{'
+			}
+			if len(open) == 0 || open[len(open)-1] != want {
+				return fmt.Errorf("unbalanced %q at offset %d", b, i)
+			}
+
+			open = open[:len(open)-1]
+			i++
+			operator = ""
+		case b == ',':
+			if len(open) == 0 || open[len(open)-1] != '{' {
+				return fmt.Errorf("unexpected ',' outside of a set at offset %d", i)
+			}
+
+			i++
+			operator = ""
+		case strings.IndexByte("=!<>&|", b) >= 0:
+			op := matchOperator(match[i:])
+			if op == "" {
+				return fmt.Errorf("invalid operator at offset %d", i)
+			}
+
+			i += len(op)
+			operator = op
+		case isMatchSymbol(b):
+			for i < len(match) && isMatchSymbol(match[i]) {
+				i++
+			}
+
+			operator = ""
+		default:
+			return fmt.Errorf("unexpected character %q at offset %d", b, i)
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed %q", open[len(open)-1])
+	}
+
+	if operator != "" {
+		return fmt.Errorf("match ends with operator %q", operator)
+	}
+
+	return nil
+}
+
+// matchOperator returns the operator at the beginning of s, or the empty
+// string if s does not begin with a valid operator.
+func matchOperator(s string) string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// isMatchSymbol reports whether b may appear in a field name, such as
+// "ip4.src", or a constant, such as an address, a CIDR prefix, or the name of
+// an address set or port group.
+func isMatchSymbol(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		return true
+	default:
+		return strings.IndexByte("_.:/$@-", b) >= 0
+	}
+}
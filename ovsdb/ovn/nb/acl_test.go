@@ -0,0 +1,354 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovn/nb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestACLValidate(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	valid := func() nb.ACL {
+		return nb.ACL{
+			Direction: nb.DirectionToLPort,
+			Priority:  1000,
+			Action:    nb.ActionAllowRelated,
+			Match:     `outport == "lsp0" && ip4.src == {10.0.0.0/8, $as0} && tcp.dst == 22`,
+		}
+	}
+
+	tests := []struct {
+		name string
+		fn   func(acl *nb.ACL)
+		ok   bool
+	}{
+		{
+			name: "OK",
+			fn:   func(*nb.ACL) {},
+			ok:   true,
+		},
+		{
+			name: "OK log options",
+			fn: func(acl *nb.ACL) {
+				acl.Log = true
+				acl.Name = strPtr("allow-ssh")
+				acl.Severity = strPtr("info")
+			},
+			ok: true,
+		},
+		{
+			name: "OK port group and negation",
+			fn: func(acl *nb.ACL) {
+				acl.Match = `inport == @pg0 && !(ip6 || arp)`
+			},
+			ok: true,
+		},
+		{
+			name: "OK quoted escape",
+			fn: func(acl *nb.ACL) {
+				acl.Match = `inport == "a\"b"`
+			},
+			ok: true,
+		},
+		{
+			name: "bad direction",
+			fn: func(acl *nb.ACL) {
+				acl.Direction = "ingress"
+			},
+		},
+		{
+			name: "negative priority",
+			fn: func(acl *nb.ACL) {
+				acl.Priority = -1
+			},
+		},
+		{
+			name: "priority too large",
+			fn: func(acl *nb.ACL) {
+				acl.Priority = nb.MaxACLPriority + 1
+			},
+		},
+		{
+			name: "bad action",
+			fn: func(acl *nb.ACL) {
+				acl.Action = "accept"
+			},
+		},
+		{
+			name: "bad severity",
+			fn: func(acl *nb.ACL) {
+				acl.Severity = strPtr("critical")
+			},
+		},
+		{
+			name: "name too long",
+			fn: func(acl *nb.ACL) {
+				acl.Name = strPtr(strings.Repeat("a", nb.MaxACLNameLength+1))
+			},
+		},
+		{
+			name: "empty match",
+			fn: func(acl *nb.ACL) {
+				acl.Match = " "
+			},
+		},
+		{
+			name: "single equals",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4.src = 10.0.0.1"
+			},
+		},
+		{
+			name: "single ampersand",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4 & tcp"
+			},
+		},
+		{
+			name: "unclosed parenthesis",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "(ip4 || ip6"
+			},
+		},
+		{
+			name: "mismatched brace",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4.src == {10.0.0.1)"
+			},
+		},
+		{
+			name: "unterminated string",
+			fn: func(acl *nb.ACL) {
+				acl.Match = `inport == "lsp0`
+			},
+		},
+		{
+			name: "comma outside set",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4, ip6"
+			},
+		},
+		{
+			name: "trailing operator",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4 &&"
+			},
+		},
+		{
+			name: "bad character",
+			fn: func(acl *nb.ACL) {
+				acl.Match = "ip4 ; drop"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acl := valid()
+			tt.fn(&acl)
+
+			err := acl.Validate()
+			if tt.ok && err != nil {
+				t.Fatalf("failed to validate: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestClientSwitchACLs(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	if err := c.CreateLogicalSwitch(ctx, "ls0"); err != nil {
+		t.Fatalf("failed to create logical switch: %v", err)
+	}
+
+	acl := nb.ACL{
+		Direction: nb.DirectionFromLPort,
+		Priority:  1001,
+		Action:    nb.ActionDrop,
+		Match:     "ip4.dst == 192.0.2.1",
+	}
+
+	uuid, err := c.AddSwitchACL(ctx, "ls0", acl)
+	if err != nil {
+		t.Fatalf("failed to add ACL: %v", err)
+	}
+
+	acls, err := c.SwitchACLs(ctx, "ls0")
+	if err != nil {
+		t.Fatalf("failed to list switch ACLs: %v", err)
+	}
+
+	acl.UUID = uuid
+	if diff := cmp.Diff([]nb.ACL{acl}, acls, cmpopts.EquateEmpty()); diff != "" {
+		t.Fatalf("unexpected switch ACLs (-want +got):\n%s", diff)
+	}
+
+	// Enable logging on the existing ACL without changing its other columns.
+	update := nb.ACL{
+		UUID:      uuid,
+		Direction: acl.Direction,
+		Action:    acl.Action,
+		Match:     acl.Match,
+		Priority:  acl.Priority,
+		Log:       true,
+	}
+
+	if err := c.UpdateACL(ctx, update, nb.ACLColumnLog); err != nil {
+		t.Fatalf("failed to update ACL: %v", err)
+	}
+
+	acls, err = c.ACLs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list ACLs: %v", err)
+	}
+
+	acl.Log = true
+	if diff := cmp.Diff([]nb.ACL{acl}, acls, cmpopts.EquateEmpty()); diff != "" {
+		t.Fatalf("unexpected ACLs after update (-want +got):\n%s", diff)
+	}
+
+	// The ACL is garbage collected once detached, and deleting it again is
+	// not an error.
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteSwitchACL(ctx, "ls0", uuid); err != nil {
+			t.Fatalf("failed to delete ACL: %v", err)
+		}
+	}
+
+	acls, err = c.ACLs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list ACLs: %v", err)
+	}
+	if len(acls) != 0 {
+		t.Fatalf("expected no ACLs after deletion, but found %d", len(acls))
+	}
+}
+
+func TestClientPortGroupACLs(t *testing.T) {
+	c, oc, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	_, err := oc.Transact(ctx, nb.DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table: nb.TablePortGroup,
+			Row:   (&nb.PortGroup{Name: "pg0"}).Row(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create port group: %v", err)
+	}
+
+	acl := nb.ACL{
+		Direction: nb.DirectionToLPort,
+		Priority:  100,
+		Action:    nb.ActionAllow,
+		Match:     "outport == @pg0 && icmp4",
+	}
+
+	uuid, err := c.AddPortGroupACL(ctx, "pg0", acl)
+	if err != nil {
+		t.Fatalf("failed to add ACL: %v", err)
+	}
+
+	pg, ok, err := c.PortGroup(ctx, "pg0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get port group: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff([]string{uuid}, pg.ACLs); diff != "" {
+		t.Fatalf("unexpected port group ACL references (-want +got):\n%s", diff)
+	}
+
+	acls, err := c.PortGroupACLs(ctx, "pg0")
+	if err != nil {
+		t.Fatalf("failed to list port group ACLs: %v", err)
+	}
+
+	acl.UUID = uuid
+	if diff := cmp.Diff([]nb.ACL{acl}, acls, cmpopts.EquateEmpty()); diff != "" {
+		t.Fatalf("unexpected port group ACLs (-want +got):\n%s", diff)
+	}
+
+	if err := c.DeletePortGroupACL(ctx, "pg0", uuid); err != nil {
+		t.Fatalf("failed to delete ACL: %v", err)
+	}
+
+	acls, err = c.PortGroupACLs(ctx, "pg0")
+	if err != nil {
+		t.Fatalf("failed to list port group ACLs: %v", err)
+	}
+	if len(acls) != 0 {
+		t.Fatalf("expected no port group ACLs after deletion, but found %d", len(acls))
+	}
+}
+
+func TestClientACLErrors(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	acl := nb.ACL{
+		Direction: nb.DirectionToLPort,
+		Priority:  100,
+		Action:    nb.ActionAllow,
+		Match:     "ip4",
+	}
+
+	if _, err := c.AddSwitchACL(ctx, "ls0", acl); err == nil {
+		t.Fatal("expected an error adding an ACL to a missing switch, but none occurred")
+	}
+	if _, err := c.AddPortGroupACL(ctx, "pg0", acl); err == nil {
+		t.Fatal("expected an error adding an ACL to a missing port group, but none occurred")
+	}
+	if _, err := c.SwitchACLs(ctx, "ls0"); err == nil {
+		t.Fatal("expected an error listing ACLs of a missing switch, but none occurred")
+	}
+
+	if err := c.CreateLogicalSwitch(ctx, "ls0"); err != nil {
+		t.Fatalf("failed to create logical switch: %v", err)
+	}
+
+	bad := acl
+	bad.Priority = nb.MaxACLPriority + 1
+	if _, err := c.AddSwitchACL(ctx, "ls0", bad); err == nil {
+		t.Fatal("expected an error adding an invalid ACL, but none occurred")
+	}
+
+	if err := c.UpdateACL(ctx, acl); err == nil {
+		t.Fatal("expected an error updating an ACL without a UUID, but none occurred")
+	}
+
+	acl.UUID = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+	if err := c.UpdateACL(ctx, acl); err == nil {
+		t.Fatal("expected an error updating a missing ACL, but none occurred")
+	}
+}
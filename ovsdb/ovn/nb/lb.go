@@ -0,0 +1,418 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// Protocols which a load balancer may balance.
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+	ProtocolSCTP = "sctp"
+)
+
+// LoadBalancerTargets specifies the logical switches and routers to which a
+// load balancer is attached.
+type LoadBalancerTargets struct {
+	Switches []string
+	Routers  []string
+}
+
+// Validate checks that a LoadBalancer's name and protocol are valid, and that
+// each VIP maps to a comma-separated list of backends.
+//
+// A VIP is an IP address with an optional port, such as "10.0.0.1:80" or
+// "[fd00::1]:80".  If the VIP has a port, each of its backends must have a
+// port, and otherwise none of its backends may have a port.
+func (lb *LoadBalancer) Validate() error {
+	if lb.Name == "" {
+		return errors.New("load balancer name must not be empty")
+	}
+
+	if lb.Protocol != nil {
+		switch *lb.Protocol {
+		case ProtocolTCP, ProtocolUDP, ProtocolSCTP:
+		default:
+			return fmt.Errorf("invalid load balancer protocol %q", *lb.Protocol)
+		}
+	}
+
+	for vip, backends := range lb.VIPs {
+		hasPort, err := parseEndpoint(vip)
+		if err != nil {
+			return fmt.Errorf("invalid load balancer VIP %q: %v", vip, err)
+		}
+
+		// An empty list of backends rejects all connections to the VIP.
+		if backends == "" {
+			continue
+		}
+
+		for _, b := range strings.Split(backends, ",") {
+			ok, err := parseEndpoint(b)
+			if err != nil {
+				return fmt.Errorf("invalid backend %q for VIP %q: %v", b, vip, err)
+			}
+			if ok != hasPort {
+				return fmt.Errorf("backend %q for VIP %q must specify a port if and only if the VIP does", b, vip)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadBalancers returns all load balancers.
+func (c *Client) LoadBalancers(ctx context.Context) ([]LoadBalancer, error) {
+	var out []LoadBalancer
+	err := c.selectRows(ctx, TableLoadBalancer, nil, func(row ovsdb.Row) error {
+		var lb LoadBalancer
+		if err := lb.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, lb)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LoadBalancer returns the load balancer with the specified name, and
+// reports whether it exists.
+func (c *Client) LoadBalancer(ctx context.Context, name string) (*LoadBalancer, bool, error) {
+	var lb LoadBalancer
+	ok, err := c.get(ctx, TableLoadBalancer, name, &lb)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &lb, true, nil
+}
+
+// LoadBalancerHealthChecks returns the health checks of a load balancer.  The
+// load balancer must exist.
+func (c *Client) LoadBalancerHealthChecks(ctx context.Context, name string) ([]LoadBalancerHealthCheck, error) {
+	lb, err := c.mustLoadBalancer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]bool, len(lb.HealthCheck))
+	for _, u := range lb.HealthCheck {
+		want[u] = true
+	}
+
+	var out []LoadBalancerHealthCheck
+	err = c.selectRows(ctx, TableLoadBalancerHealthCheck, nil, func(row ovsdb.Row) error {
+		var hc LoadBalancerHealthCheck
+		if err := hc.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		if want[hc.UUID] {
+			out = append(out, hc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// CreateLoadBalancer validates and creates a load balancer with the
+// specified health checks, attaches it to targets, and returns the load
+// balancer's UUID.  All changes are made in a single transaction.
+//
+// Each health check must apply to one of the load balancer's VIPs.  Any
+// existing references in lb.HealthCheck are ignored.
+func (c *Client) CreateLoadBalancer(ctx context.Context, lb LoadBalancer, targets LoadBalancerTargets, checks ...LoadBalancerHealthCheck) (string, error) {
+	if err := lb.Validate(); err != nil {
+		return "", err
+	}
+
+	attach, err := c.targetUUIDs(ctx, targets)
+	if err != nil {
+		return "", err
+	}
+
+	ops := []ovsdb.TransactOp{
+		notExists(TableLoadBalancer, LoadBalancerColumnName, lb.Name),
+	}
+
+	lb.HealthCheck = nil
+	refs := make(ovsdb.Set, 0, len(checks))
+	for i, hc := range checks {
+		if _, ok := lb.VIPs[hc.VIP]; !ok {
+			return "", fmt.Errorf("health check VIP %q is not a VIP of load balancer %q", hc.VIP, lb.Name)
+		}
+
+		name := "new_hc" + strconv.Itoa(i)
+		ops = append(ops, ovsdb.Insert{
+			Table:    TableLoadBalancerHealthCheck,
+			UUIDName: name,
+			Row:      hc.Row(),
+		})
+		refs = append(refs, ovsdb.NamedUUID(name))
+	}
+
+	// The load balancer's UUID is the result of its insert operation.
+	n := len(ops)
+
+	row := lb.Row()
+	row[LoadBalancerColumnHealthCheck] = refs
+	ops = append(ops, ovsdb.Insert{
+		Table:    TableLoadBalancer,
+		UUIDName: "new_lb",
+		Row:      row,
+	})
+
+	for _, t := range attach {
+		ops = append(ops, addRef(t.table, t.uuid, t.column, "new_lb"))
+	}
+
+	res, err := c.c.Transact(ctx, DatabaseName, ops)
+	if err != nil {
+		return "", err
+	}
+
+	return res[n].UUID, nil
+}
+
+// UpdateLoadBalancer validates lb and updates the existing load balancer
+// with the UUID lb.UUID.  If one or more columns are specified, only those
+// columns are updated.
+func (c *Client) UpdateLoadBalancer(ctx context.Context, lb LoadBalancer, columns ...string) error {
+	if lb.UUID == "" {
+		return errors.New("load balancer UUID must be specified to update a load balancer")
+	}
+
+	if err := lb.Validate(); err != nil {
+		return err
+	}
+
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Update{
+			Table: TableLoadBalancer,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(lb.UUID))},
+			Row:   lb.Row(columns...),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res[0].Count == 0 {
+		return fmt.Errorf("load balancer %q does not exist", lb.UUID)
+	}
+
+	return nil
+}
+
+// SetLoadBalancerVIP adds a VIP to a load balancer, or replaces the backends
+// of an existing VIP.  The load balancer must exist.
+func (c *Client) SetLoadBalancerVIP(ctx context.Context, name, vip string, backends ...string) error {
+	lb, err := c.mustLoadBalancer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// Validate the VIP in the context of the load balancer's other columns.
+	b := strings.Join(backends, ",")
+	check := *lb
+	check.VIPs = map[string]string{vip: b}
+	if err := check.Validate(); err != nil {
+		return err
+	}
+
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: TableLoadBalancer,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(lb.UUID))},
+			Mutations: []ovsdb.Mutation{
+				// Inserting into a map does not replace existing keys.
+				ovsdb.MutateDelete(LoadBalancerColumnVIPs, ovsdb.Set{vip}),
+				ovsdb.MutateInsert(LoadBalancerColumnVIPs, ovsdb.Map{vip: b}),
+			},
+		},
+	})
+}
+
+// DeleteLoadBalancerVIP removes a VIP from a load balancer.  The VIP may or
+// may not exist.
+func (c *Client) DeleteLoadBalancerVIP(ctx context.Context, name, vip string) error {
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: TableLoadBalancer,
+			Where: []ovsdb.Cond{ovsdb.Equal(LoadBalancerColumnName, name)},
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete(LoadBalancerColumnVIPs, ovsdb.Set{vip}),
+			},
+		},
+	})
+}
+
+// AttachLoadBalancer attaches an existing load balancer to targets in a
+// single transaction.
+func (c *Client) AttachLoadBalancer(ctx context.Context, name string, targets LoadBalancerTargets) error {
+	return c.mutateTargets(ctx, name, targets, ovsdb.MutateInsert)
+}
+
+// DetachLoadBalancer detaches an existing load balancer from targets in a
+// single transaction.  The load balancer may or may not already be attached.
+func (c *Client) DetachLoadBalancer(ctx context.Context, name string, targets LoadBalancerTargets) error {
+	return c.mutateTargets(ctx, name, targets, ovsdb.MutateDelete)
+}
+
+// DeleteLoadBalancer deletes a load balancer and its health checks.  The load
+// balancer is detached from any logical switches and routers.  The load
+// balancer may or may not exist.
+func (c *Client) DeleteLoadBalancer(ctx context.Context, name string) error {
+	// References to load balancers are weak, and health checks are not root
+	// rows, so ovsdb-server removes both along with the load balancer.
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Delete{
+			Table: TableLoadBalancer,
+			Where: []ovsdb.Cond{ovsdb.Equal(LoadBalancerColumnName, name)},
+		},
+	})
+}
+
+// mutateTargets applies a mutation which references the named load balancer
+// to the load_balancer column of each of targets.
+func (c *Client) mutateTargets(ctx context.Context, name string, targets LoadBalancerTargets, mutate func(column string, value interface{}) ovsdb.Mutation) error {
+	lb, err := c.mustLoadBalancer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	ts, err := c.targetUUIDs(ctx, targets)
+	if err != nil {
+		return err
+	}
+
+	ops := make([]ovsdb.TransactOp, 0, len(ts))
+	for _, t := range ts {
+		ops = append(ops, ovsdb.Mutate{
+			Table: t.table,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(t.uuid))},
+			Mutations: []ovsdb.Mutation{
+				mutate(t.column, ovsdb.Set{ovsdb.UUID(lb.UUID)}),
+			},
+		})
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	return c.transact(ctx, ops)
+}
+
+// A target is a row which references load balancers.
+type target struct {
+	table, uuid, column string
+}
+
+// targetUUIDs looks up the rows for the switches and routers in targets, all
+// of which must exist.
+func (c *Client) targetUUIDs(ctx context.Context, targets LoadBalancerTargets) ([]target, error) {
+	out := make([]target, 0, len(targets.Switches)+len(targets.Routers))
+
+	for _, name := range targets.Switches {
+		ls, ok, err := c.LogicalSwitch(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("logical switch %q does not exist", name)
+		}
+
+		out = append(out, target{
+			table:  TableLogicalSwitch,
+			uuid:   ls.UUID,
+			column: LogicalSwitchColumnLoadBalancer,
+		})
+	}
+
+	for _, name := range targets.Routers {
+		lr, ok, err := c.LogicalRouter(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("logical router %q does not exist", name)
+		}
+
+		out = append(out, target{
+			table:  TableLogicalRouter,
+			uuid:   lr.UUID,
+			column: LogicalRouterColumnLoadBalancer,
+		})
+	}
+
+	return out, nil
+}
+
+// mustLoadBalancer returns the named load balancer, or an error if it does
+// not exist.
+func (c *Client) mustLoadBalancer(ctx context.Context, name string) (*LoadBalancer, error) {
+	lb, ok, err := c.LoadBalancer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("load balancer %q does not exist", name)
+	}
+
+	return lb, nil
+}
+
+// parseEndpoint parses an IP address with an optional port, and reports
+// whether a port was present.
+func parseEndpoint(s string) (bool, error) {
+	if net.ParseIP(s) != nil {
+		return false, nil
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return false, err
+	}
+
+	if net.ParseIP(host) == nil {
+		return false, fmt.Errorf("invalid IP address %q", host)
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return false, fmt.Errorf("invalid port %q", port)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,311 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovn/nb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLoadBalancerValidate(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		lb   nb.LoadBalancer
+		ok   bool
+	}{
+		{
+			name: "OK",
+			lb: nb.LoadBalancer{
+				Name:     "lb0",
+				Protocol: strPtr(nb.ProtocolTCP),
+				VIPs: map[string]string{
+					"10.0.0.1:80":    "192.168.0.1:8080,192.168.0.2:8080",
+					"10.0.0.2":       "192.168.0.3",
+					"[fd00::1]:443":  "[fd01::1]:8443",
+					"10.0.0.3:53":    "",
+					"fd00::2":        "fd01::2,fd01::3",
+					"172.16.0.1:123": "172.16.1.1:123",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "no name",
+			lb:   nb.LoadBalancer{},
+		},
+		{
+			name: "bad protocol",
+			lb: nb.LoadBalancer{
+				Name:     "lb0",
+				Protocol: strPtr("icmp"),
+			},
+		},
+		{
+			name: "bad VIP",
+			lb: nb.LoadBalancer{
+				Name: "lb0",
+				VIPs: map[string]string{"foo:80": "192.168.0.1:80"},
+			},
+		},
+		{
+			name: "bad port",
+			lb: nb.LoadBalancer{
+				Name: "lb0",
+				VIPs: map[string]string{"10.0.0.1:65536": "192.168.0.1:80"},
+			},
+		},
+		{
+			name: "bad backend",
+			lb: nb.LoadBalancer{
+				Name: "lb0",
+				VIPs: map[string]string{"10.0.0.1:80": "192.168.0.1:80,"},
+			},
+		},
+		{
+			name: "backend missing port",
+			lb: nb.LoadBalancer{
+				Name: "lb0",
+				VIPs: map[string]string{"10.0.0.1:80": "192.168.0.1"},
+			},
+		},
+		{
+			name: "backend unexpected port",
+			lb: nb.LoadBalancer{
+				Name: "lb0",
+				VIPs: map[string]string{"10.0.0.1": "192.168.0.1:80"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lb.Validate()
+			if tt.ok && err != nil {
+				t.Fatalf("failed to validate: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestClientLoadBalancer(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	if err := c.CreateLogicalSwitch(ctx, "ls0"); err != nil {
+		t.Fatalf("failed to create logical switch: %v", err)
+	}
+	if err := c.CreateRouter(ctx, "lr0"); err != nil {
+		t.Fatalf("failed to create logical router: %v", err)
+	}
+
+	tcp := nb.ProtocolTCP
+	lb := nb.LoadBalancer{
+		Name:     "lb0",
+		Protocol: &tcp,
+		VIPs: map[string]string{
+			"10.0.0.1:80": "192.168.0.1:8080,192.168.0.2:8080",
+		},
+	}
+
+	hc := nb.LoadBalancerHealthCheck{
+		VIP: "10.0.0.1:80",
+		Options: map[string]string{
+			"interval": "5",
+			"timeout":  "20",
+		},
+	}
+
+	uuid, err := c.CreateLoadBalancer(ctx, lb, nb.LoadBalancerTargets{
+		Switches: []string{"ls0"},
+		Routers:  []string{"lr0"},
+	}, hc)
+	if err != nil {
+		t.Fatalf("failed to create load balancer: %v", err)
+	}
+
+	// The load balancer's name is unique.
+	if _, err := c.CreateLoadBalancer(ctx, lb, nb.LoadBalancerTargets{}); err == nil {
+		t.Fatal("expected an error creating a duplicate load balancer, but none occurred")
+	}
+
+	checks, err := c.LoadBalancerHealthChecks(ctx, "lb0")
+	if err != nil {
+		t.Fatalf("failed to list health checks: %v", err)
+	}
+	if len(checks) != 1 {
+		t.Fatalf("expected 1 health check, but found %d", len(checks))
+	}
+
+	hc.UUID = checks[0].UUID
+	if diff := cmp.Diff([]nb.LoadBalancerHealthCheck{hc}, checks, cmpopts.EquateEmpty()); diff != "" {
+		t.Fatalf("unexpected health checks (-want +got):\n%s", diff)
+	}
+
+	// Both the switch and router reference the load balancer.
+	checkRefs := func(want []string) {
+		t.Helper()
+
+		ls, _, err := c.LogicalSwitch(ctx, "ls0")
+		if err != nil {
+			t.Fatalf("failed to get logical switch: %v", err)
+		}
+		lr, _, err := c.LogicalRouter(ctx, "lr0")
+		if err != nil {
+			t.Fatalf("failed to get logical router: %v", err)
+		}
+
+		if diff := cmp.Diff(want, ls.LoadBalancer, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("unexpected switch load balancers (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(want, lr.LoadBalancer, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("unexpected router load balancers (-want +got):\n%s", diff)
+		}
+	}
+
+	checkRefs([]string{uuid})
+
+	// Replace the backends of the existing VIP and add another.
+	if err := c.SetLoadBalancerVIP(ctx, "lb0", "10.0.0.1:80", "192.168.0.3:8080"); err != nil {
+		t.Fatalf("failed to set VIP: %v", err)
+	}
+	if err := c.SetLoadBalancerVIP(ctx, "lb0", "10.0.0.2:443", "192.168.0.4:8443", "192.168.0.5:8443"); err != nil {
+		t.Fatalf("failed to set VIP: %v", err)
+	}
+	if err := c.SetLoadBalancerVIP(ctx, "lb0", "10.0.0.3:80", "192.168.0.4"); err == nil {
+		t.Fatal("expected an error setting an invalid VIP, but none occurred")
+	}
+
+	got, ok, err := c.LoadBalancer(ctx, "lb0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get load balancer: %v, %v", ok, err)
+	}
+
+	wantVIPs := map[string]string{
+		"10.0.0.1:80":  "192.168.0.3:8080",
+		"10.0.0.2:443": "192.168.0.4:8443,192.168.0.5:8443",
+	}
+	if diff := cmp.Diff(wantVIPs, got.VIPs); diff != "" {
+		t.Fatalf("unexpected VIPs (-want +got):\n%s", diff)
+	}
+
+	if err := c.DeleteLoadBalancerVIP(ctx, "lb0", "10.0.0.2:443"); err != nil {
+		t.Fatalf("failed to delete VIP: %v", err)
+	}
+
+	// Update only the protocol.
+	udp := nb.ProtocolUDP
+	got.Protocol = &udp
+	if err := c.UpdateLoadBalancer(ctx, *got, nb.LoadBalancerColumnProtocol); err != nil {
+		t.Fatalf("failed to update load balancer: %v", err)
+	}
+
+	got, _, err = c.LoadBalancer(ctx, "lb0")
+	if err != nil {
+		t.Fatalf("failed to get load balancer: %v", err)
+	}
+	if diff := cmp.Diff(udp, *got.Protocol); diff != "" {
+		t.Fatalf("unexpected protocol (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"10.0.0.1:80": "192.168.0.3:8080"}, got.VIPs); diff != "" {
+		t.Fatalf("unexpected VIPs after update (-want +got):\n%s", diff)
+	}
+
+	// Detach and reattach the load balancer.
+	targets := nb.LoadBalancerTargets{
+		Switches: []string{"ls0"},
+		Routers:  []string{"lr0"},
+	}
+
+	if err := c.DetachLoadBalancer(ctx, "lb0", targets); err != nil {
+		t.Fatalf("failed to detach load balancer: %v", err)
+	}
+	checkRefs(nil)
+
+	if err := c.AttachLoadBalancer(ctx, "lb0", targets); err != nil {
+		t.Fatalf("failed to attach load balancer: %v", err)
+	}
+	checkRefs([]string{uuid})
+
+	// Deleting the load balancer removes its references and health checks,
+	// and deleting it again is not an error.
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteLoadBalancer(ctx, "lb0"); err != nil {
+			t.Fatalf("failed to delete load balancer: %v", err)
+		}
+	}
+	checkRefs(nil)
+
+	lbs, err := c.LoadBalancers(ctx)
+	if err != nil {
+		t.Fatalf("failed to list load balancers: %v", err)
+	}
+	if len(lbs) != 0 {
+		t.Fatalf("expected no load balancers after deletion, but found %d", len(lbs))
+	}
+}
+
+func TestClientLoadBalancerErrors(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	lb := nb.LoadBalancer{
+		Name: "lb0",
+		VIPs: map[string]string{"10.0.0.1:80": "192.168.0.1:80"},
+	}
+
+	if _, err := c.CreateLoadBalancer(ctx, lb, nb.LoadBalancerTargets{Switches: []string{"ls0"}}); err == nil {
+		t.Fatal("expected an error attaching to a missing switch, but none occurred")
+	}
+	if _, err := c.CreateLoadBalancer(ctx, lb, nb.LoadBalancerTargets{Routers: []string{"lr0"}}); err == nil {
+		t.Fatal("expected an error attaching to a missing router, but none occurred")
+	}
+
+	hc := nb.LoadBalancerHealthCheck{VIP: "10.0.0.2:80"}
+	if _, err := c.CreateLoadBalancer(ctx, lb, nb.LoadBalancerTargets{}, hc); err == nil {
+		t.Fatal("expected an error creating a health check for a missing VIP, but none occurred")
+	}
+
+	if err := c.AttachLoadBalancer(ctx, "lb0", nb.LoadBalancerTargets{}); err == nil {
+		t.Fatal("expected an error attaching a missing load balancer, but none occurred")
+	}
+	if err := c.SetLoadBalancerVIP(ctx, "lb0", "10.0.0.1:80"); err == nil {
+		t.Fatal("expected an error setting a VIP of a missing load balancer, but none occurred")
+	}
+	if err := c.UpdateLoadBalancer(ctx, lb); err == nil {
+		t.Fatal("expected an error updating a load balancer without a UUID, but none occurred")
+	}
+
+	// Nothing was created by the failed transactions.
+	lbs, err := c.LoadBalancers(ctx)
+	if err != nil {
+		t.Fatalf("failed to list load balancers: %v", err)
+	}
+	if len(lbs) != 0 {
+		t.Fatalf("expected no load balancers, but found %d", len(lbs))
+	}
+}
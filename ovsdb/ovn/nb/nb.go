@@ -0,0 +1,232 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// A Client provides typed access to an OVN Northbound database using an
+// OVSDB connection to its ovsdb-server.
+type Client struct {
+	c *ovsdb.Client
+}
+
+// New creates a Client which uses the OVSDB connection c.  The Client does
+// not take ownership of c, which must be closed by the caller.
+func New(c *ovsdb.Client) *Client {
+	return &Client{c: c}
+}
+
+// NBGlobal returns the database's single NB_Global row.
+func (c *Client) NBGlobal(ctx context.Context) (*NBGlobal, error) {
+	var gs []NBGlobal
+	err := c.selectRows(ctx, TableNBGlobal, nil, func(row ovsdb.Row) error {
+		var g NBGlobal
+		if err := g.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		gs = append(gs, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gs) == 0 {
+		return nil, errors.New("NB_Global row does not exist")
+	}
+
+	return &gs[0], nil
+}
+
+// LogicalSwitches returns all logical switches.
+func (c *Client) LogicalSwitches(ctx context.Context) ([]LogicalSwitch, error) {
+	var out []LogicalSwitch
+	err := c.selectRows(ctx, TableLogicalSwitch, nil, func(row ovsdb.Row) error {
+		var ls LogicalSwitch
+		if err := ls.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, ls)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LogicalSwitch returns the logical switch with the specified name, and
+// reports whether it exists.
+func (c *Client) LogicalSwitch(ctx context.Context, name string) (*LogicalSwitch, bool, error) {
+	var ls LogicalSwitch
+	ok, err := c.get(ctx, TableLogicalSwitch, name, &ls)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &ls, true, nil
+}
+
+// LogicalSwitchPorts returns all logical switch ports.
+func (c *Client) LogicalSwitchPorts(ctx context.Context) ([]LogicalSwitchPort, error) {
+	var out []LogicalSwitchPort
+	err := c.selectRows(ctx, TableLogicalSwitchPort, nil, func(row ovsdb.Row) error {
+		var lsp LogicalSwitchPort
+		if err := lsp.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, lsp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LogicalSwitchPort returns the logical switch port with the specified name,
+// and reports whether it exists.
+func (c *Client) LogicalSwitchPort(ctx context.Context, name string) (*LogicalSwitchPort, bool, error) {
+	var lsp LogicalSwitchPort
+	ok, err := c.get(ctx, TableLogicalSwitchPort, name, &lsp)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &lsp, true, nil
+}
+
+// LogicalRouters returns all logical routers.
+func (c *Client) LogicalRouters(ctx context.Context) ([]LogicalRouter, error) {
+	var out []LogicalRouter
+	err := c.selectRows(ctx, TableLogicalRouter, nil, func(row ovsdb.Row) error {
+		var lr LogicalRouter
+		if err := lr.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, lr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LogicalRouter returns the logical router with the specified name, and
+// reports whether it exists.
+func (c *Client) LogicalRouter(ctx context.Context, name string) (*LogicalRouter, bool, error) {
+	var lr LogicalRouter
+	ok, err := c.get(ctx, TableLogicalRouter, name, &lr)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &lr, true, nil
+}
+
+// LogicalRouterPorts returns all logical router ports.
+func (c *Client) LogicalRouterPorts(ctx context.Context) ([]LogicalRouterPort, error) {
+	var out []LogicalRouterPort
+	err := c.selectRows(ctx, TableLogicalRouterPort, nil, func(row ovsdb.Row) error {
+		var lrp LogicalRouterPort
+		if err := lrp.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, lrp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// LogicalRouterPort returns the logical router port with the specified name,
+// and reports whether it exists.
+func (c *Client) LogicalRouterPort(ctx context.Context, name string) (*LogicalRouterPort, bool, error) {
+	var lrp LogicalRouterPort
+	ok, err := c.get(ctx, TableLogicalRouterPort, name, &lrp)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &lrp, true, nil
+}
+
+// A rowUnmarshaler is a generated type which can be populated from a Row.
+type rowUnmarshaler interface {
+	UnmarshalRow(row ovsdb.Row) error
+}
+
+// get populates r with the row named name in table, and reports whether the
+// row exists.  Names need not be unique in all tables, so it is an error for
+// more than one row to have the name.
+func (c *Client) get(ctx context.Context, table, name string, r rowUnmarshaler) (bool, error) {
+	var rows []ovsdb.Row
+	err := c.selectRows(ctx, table, []ovsdb.Cond{ovsdb.Equal("name", name)}, func(row ovsdb.Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch len(rows) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, r.UnmarshalRow(rows[0])
+	default:
+		return false, fmt.Errorf("multiple rows named %q in table %q", name, table)
+	}
+}
+
+// selectRows selects the rows of table which match where, and invokes fn for
+// each row.
+func (c *Client) selectRows(ctx context.Context, table string, where []ovsdb.Cond, fn func(row ovsdb.Row) error) error {
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Select{
+			Table: table,
+			Where: where,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range res[0].Rows {
+		if err := fn(row); err != nil {
+			return fmt.Errorf("invalid row in table %q: %v", table, err)
+		}
+	}
+
+	return nil
+}
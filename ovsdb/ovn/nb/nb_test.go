@@ -0,0 +1,232 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovn/nb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbserver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClient(t *testing.T) {
+	c, oc, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	up := true
+	lsp := nb.LogicalSwitchPort{
+		Name:      "lsp0",
+		Addresses: []string{"00:00:00:00:00:01 10.0.0.1"},
+		Up:        &up,
+	}
+	ls := nb.LogicalSwitch{
+		Name:        "ls0",
+		Ports:       []string{"new_lsp"},
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}
+	lrp := nb.LogicalRouterPort{
+		Name:     "lrp0",
+		MAC:      "00:00:00:00:00:02",
+		Networks: []string{"10.0.0.254/24"},
+	}
+	lr := nb.LogicalRouter{
+		Name:  "lr0",
+		Ports: []string{"new_lrp"},
+	}
+	g := nb.NBGlobal{NBCfg: 1}
+
+	res, err := oc.Transact(ctx, nb.DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Insert{Table: nb.TableNBGlobal, Row: g.Row()},
+		ovsdb.Insert{Table: nb.TableLogicalSwitchPort, UUIDName: "new_lsp", Row: lsp.Row()},
+		ovsdb.Insert{Table: nb.TableLogicalSwitch, UUIDName: "new_ls", Row: ls.Row()},
+		ovsdb.Insert{Table: nb.TableLogicalRouterPort, UUIDName: "new_lrp", Row: lrp.Row()},
+		ovsdb.Insert{Table: nb.TableLogicalRouter, UUIDName: "new_lr", Row: lr.Row()},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+
+	// Fill in the UUIDs assigned by the server.
+	g.UUID = res[0].UUID
+	lsp.UUID = res[1].UUID
+	ls.UUID = res[2].UUID
+	ls.Ports = []string{lsp.UUID}
+	lrp.UUID = res[3].UUID
+	lr.UUID = res[4].UUID
+	lr.Ports = []string{lrp.UUID}
+
+	// Empty collections are decoded as empty values rather than nil.
+	opts := []cmp.Option{cmpopts.EquateEmpty()}
+
+	gotG, err := c.NBGlobal(ctx)
+	if err != nil {
+		t.Fatalf("failed to get NB_Global: %v", err)
+	}
+	if diff := cmp.Diff(&g, gotG, opts...); diff != "" {
+		t.Fatalf("unexpected NB_Global (-want +got):\n%s", diff)
+	}
+
+	switches, err := c.LogicalSwitches(ctx)
+	if err != nil {
+		t.Fatalf("failed to list logical switches: %v", err)
+	}
+	if diff := cmp.Diff([]nb.LogicalSwitch{ls}, switches, opts...); diff != "" {
+		t.Fatalf("unexpected logical switches (-want +got):\n%s", diff)
+	}
+
+	gotLS, ok, err := c.LogicalSwitch(ctx, "ls0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get logical switch: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&ls, gotLS, opts...); diff != "" {
+		t.Fatalf("unexpected logical switch (-want +got):\n%s", diff)
+	}
+
+	ports, err := c.LogicalSwitchPorts(ctx)
+	if err != nil {
+		t.Fatalf("failed to list logical switch ports: %v", err)
+	}
+	if diff := cmp.Diff([]nb.LogicalSwitchPort{lsp}, ports, opts...); diff != "" {
+		t.Fatalf("unexpected logical switch ports (-want +got):\n%s", diff)
+	}
+
+	gotLSP, ok, err := c.LogicalSwitchPort(ctx, "lsp0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get logical switch port: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&lsp, gotLSP, opts...); diff != "" {
+		t.Fatalf("unexpected logical switch port (-want +got):\n%s", diff)
+	}
+
+	routers, err := c.LogicalRouters(ctx)
+	if err != nil {
+		t.Fatalf("failed to list logical routers: %v", err)
+	}
+	if diff := cmp.Diff([]nb.LogicalRouter{lr}, routers, opts...); diff != "" {
+		t.Fatalf("unexpected logical routers (-want +got):\n%s", diff)
+	}
+
+	gotLR, ok, err := c.LogicalRouter(ctx, "lr0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get logical router: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&lr, gotLR, opts...); diff != "" {
+		t.Fatalf("unexpected logical router (-want +got):\n%s", diff)
+	}
+
+	rports, err := c.LogicalRouterPorts(ctx)
+	if err != nil {
+		t.Fatalf("failed to list logical router ports: %v", err)
+	}
+	if diff := cmp.Diff([]nb.LogicalRouterPort{lrp}, rports, opts...); diff != "" {
+		t.Fatalf("unexpected logical router ports (-want +got):\n%s", diff)
+	}
+
+	gotLRP, ok, err := c.LogicalRouterPort(ctx, "lrp0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get logical router port: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&lrp, gotLRP, opts...); diff != "" {
+		t.Fatalf("unexpected logical router port (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	if _, err := c.NBGlobal(ctx); err == nil {
+		t.Fatal("expected an error for missing NB_Global, but none occurred")
+	}
+
+	if _, ok, err := c.LogicalSwitch(ctx, "ls0"); err != nil || ok {
+		t.Fatalf("unexpected logical switch: %v, %v", ok, err)
+	}
+
+	if _, ok, err := c.LogicalRouter(ctx, "lr0"); err != nil || ok {
+		t.Fatalf("unexpected logical router: %v, %v", ok, err)
+	}
+}
+
+func TestClientDuplicateName(t *testing.T) {
+	c, oc, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	ls := nb.LogicalSwitch{Name: "ls0"}
+	_, err := oc.Transact(ctx, nb.DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Insert{Table: nb.TableLogicalSwitch, Row: ls.Row()},
+		ovsdb.Insert{Table: nb.TableLogicalSwitch, Row: ls.Row()},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+
+	if _, _, err := c.LogicalSwitch(ctx, "ls0"); err == nil {
+		t.Fatal("expected an error for duplicate names, but none occurred")
+	}
+}
+
+// testClient creates a Client backed by an in-memory server which uses the
+// package's Northbound schema.  The underlying ovsdb.Client is also
+// returned to set up the contents of the database.
+func testClient(t *testing.T) (*nb.Client, *ovsdb.Client, func()) {
+	t.Helper()
+
+	b, err := ioutil.ReadFile("nb.ovsschema")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var schema ovsdb.Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	s, err := ovsdbserver.New(&schema)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	oc, err := ovsdb.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	return nb.New(oc), oc, func() {
+		_ = oc.Close()
+		_ = s.Close()
+	}
+}
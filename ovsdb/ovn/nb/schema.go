@@ -0,0 +1,3080 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by ovsdbgen. DO NOT EDIT.
+
+// Package nb contains bindings for the OVN_Northbound OVSDB schema, version 5.32.1.
+package nb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// DatabaseName is the name of the OVN_Northbound database.
+const DatabaseName = "OVN_Northbound"
+
+// Names of the ACL table and its columns.
+const (
+	TableACL = "ACL"
+
+	ACLColumnAction      = "action"
+	ACLColumnDirection   = "direction"
+	ACLColumnExternalIDs = "external_ids"
+	ACLColumnLabel       = "label"
+	ACLColumnLog         = "log"
+	ACLColumnMatch       = "match"
+	ACLColumnMeter       = "meter"
+	ACLColumnName        = "name"
+	ACLColumnOptions     = "options"
+	ACLColumnPriority    = "priority"
+	ACLColumnSeverity    = "severity"
+)
+
+// ACL is a row in the ACL table.
+type ACL struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Action      string            `ovsdb:"action"`
+	Direction   string            `ovsdb:"direction"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Label       int               `ovsdb:"label"`
+	Log         bool              `ovsdb:"log"`
+	Match       string            `ovsdb:"match"`
+	Meter       *string           `ovsdb:"meter"`
+	Name        *string           `ovsdb:"name"`
+	Options     map[string]string `ovsdb:"options"`
+	Priority    int               `ovsdb:"priority"`
+	Severity    *string           `ovsdb:"severity"`
+}
+
+// Row converts a ACL into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *ACL) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	set(ACLColumnAction, genEncodeAtom(r.Action))
+	set(ACLColumnDirection, genEncodeAtom(r.Direction))
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(ACLColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(ACLColumnLabel, genEncodeAtom(r.Label))
+	set(ACLColumnLog, genEncodeAtom(r.Log))
+	set(ACLColumnMatch, genEncodeAtom(r.Match))
+	if r.Meter != nil {
+		set(ACLColumnMeter, genEncodeAtom(*r.Meter))
+	} else {
+		set(ACLColumnMeter, []interface{}{"set", []interface{}{}})
+	}
+	if r.Name != nil {
+		set(ACLColumnName, genEncodeAtom(*r.Name))
+	} else {
+		set(ACLColumnName, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(ACLColumnOptions, []interface{}{"map", pairs})
+	}
+	set(ACLColumnPriority, genEncodeAtom(r.Priority))
+	if r.Severity != nil {
+		set(ACLColumnSeverity, genEncodeAtom(*r.Severity))
+	} else {
+		set(ACLColumnSeverity, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a ACL using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *ACL) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[ACLColumnAction]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Action = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnAction, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnDirection]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Direction = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnDirection, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnLabel]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.Label = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnLabel, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnLog]; ok {
+		if err := func() error {
+			x, err := genDecodeBoolean(v)
+			if err != nil {
+				return err
+			}
+
+			r.Log = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnLog, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnMatch]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Match = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnMatch, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnMeter]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Meter = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Meter = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnMeter, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnName]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Name = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Name = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnName, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnPriority]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.Priority = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnPriority, err)
+		}
+	}
+
+	if v, ok := row[ACLColumnSeverity]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Severity = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Severity = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ACLColumnSeverity, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Address_Set table and its columns.
+const (
+	TableAddressSet = "Address_Set"
+
+	AddressSetColumnAddresses   = "addresses"
+	AddressSetColumnExternalIDs = "external_ids"
+	AddressSetColumnName        = "name"
+)
+
+// AddressSet is a row in the Address_Set table.
+type AddressSet struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Addresses   []string          `ovsdb:"addresses"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Name        string            `ovsdb:"name"`
+}
+
+// Row converts a AddressSet into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *AddressSet) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.Addresses))
+		for _, e := range r.Addresses {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(AddressSetColumnAddresses, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(AddressSetColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(AddressSetColumnName, genEncodeAtom(r.Name))
+
+	return row
+}
+
+// UnmarshalRow populates a AddressSet using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *AddressSet) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[AddressSetColumnAddresses]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Addresses = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", AddressSetColumnAddresses, err)
+		}
+	}
+
+	if v, ok := row[AddressSetColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", AddressSetColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[AddressSetColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", AddressSetColumnName, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Load_Balancer table and its columns.
+const (
+	TableLoadBalancer = "Load_Balancer"
+
+	LoadBalancerColumnExternalIDs     = "external_ids"
+	LoadBalancerColumnHealthCheck     = "health_check"
+	LoadBalancerColumnIPPortMappings  = "ip_port_mappings"
+	LoadBalancerColumnName            = "name"
+	LoadBalancerColumnOptions         = "options"
+	LoadBalancerColumnProtocol        = "protocol"
+	LoadBalancerColumnSelectionFields = "selection_fields"
+	LoadBalancerColumnVIPs            = "vips"
+)
+
+// LoadBalancer is a row in the Load_Balancer table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type LoadBalancer struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs     map[string]string `ovsdb:"external_ids"`
+	HealthCheck     []string          `ovsdb:"health_check"`
+	IPPortMappings  map[string]string `ovsdb:"ip_port_mappings"`
+	Name            string            `ovsdb:"name"`
+	Options         map[string]string `ovsdb:"options"`
+	Protocol        *string           `ovsdb:"protocol"`
+	SelectionFields []string          `ovsdb:"selection_fields"`
+	VIPs            map[string]string `ovsdb:"vips"`
+}
+
+// Row converts a LoadBalancer into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LoadBalancer) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.HealthCheck))
+		for _, e := range r.HealthCheck {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LoadBalancerColumnHealthCheck, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.IPPortMappings))
+		for k, v := range r.IPPortMappings {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerColumnIPPortMappings, []interface{}{"map", pairs})
+	}
+	set(LoadBalancerColumnName, genEncodeAtom(r.Name))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerColumnOptions, []interface{}{"map", pairs})
+	}
+	if r.Protocol != nil {
+		set(LoadBalancerColumnProtocol, genEncodeAtom(*r.Protocol))
+	} else {
+		set(LoadBalancerColumnProtocol, []interface{}{"set", []interface{}{}})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.SelectionFields))
+		for _, e := range r.SelectionFields {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(LoadBalancerColumnSelectionFields, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.VIPs))
+		for k, v := range r.VIPs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerColumnVIPs, []interface{}{"map", pairs})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a LoadBalancer using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LoadBalancer) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LoadBalancerColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnHealthCheck]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.HealthCheck = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnHealthCheck, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnIPPortMappings]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.IPPortMappings = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnIPPortMappings, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnName, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnProtocol]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Protocol = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Protocol = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnProtocol, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnSelectionFields]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.SelectionFields = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnSelectionFields, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerColumnVIPs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.VIPs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerColumnVIPs, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Load_Balancer_Health_Check table and its columns.
+const (
+	TableLoadBalancerHealthCheck = "Load_Balancer_Health_Check"
+
+	LoadBalancerHealthCheckColumnExternalIDs = "external_ids"
+	LoadBalancerHealthCheckColumnOptions     = "options"
+	LoadBalancerHealthCheckColumnVIP         = "vip"
+)
+
+// LoadBalancerHealthCheck is a row in the Load_Balancer_Health_Check table.
+type LoadBalancerHealthCheck struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Options     map[string]string `ovsdb:"options"`
+	VIP         string            `ovsdb:"vip"`
+}
+
+// Row converts a LoadBalancerHealthCheck into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LoadBalancerHealthCheck) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerHealthCheckColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LoadBalancerHealthCheckColumnOptions, []interface{}{"map", pairs})
+	}
+	set(LoadBalancerHealthCheckColumnVIP, genEncodeAtom(r.VIP))
+
+	return row
+}
+
+// UnmarshalRow populates a LoadBalancerHealthCheck using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LoadBalancerHealthCheck) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LoadBalancerHealthCheckColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerHealthCheckColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerHealthCheckColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerHealthCheckColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LoadBalancerHealthCheckColumnVIP]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.VIP = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LoadBalancerHealthCheckColumnVIP, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Logical_Router table and its columns.
+const (
+	TableLogicalRouter = "Logical_Router"
+
+	LogicalRouterColumnEnabled      = "enabled"
+	LogicalRouterColumnExternalIDs  = "external_ids"
+	LogicalRouterColumnLoadBalancer = "load_balancer"
+	LogicalRouterColumnName         = "name"
+	LogicalRouterColumnNAT          = "nat"
+	LogicalRouterColumnOptions      = "options"
+	LogicalRouterColumnPorts        = "ports"
+	LogicalRouterColumnStaticRoutes = "static_routes"
+)
+
+// LogicalRouter is a row in the Logical_Router table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type LogicalRouter struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Enabled      *bool             `ovsdb:"enabled"`
+	ExternalIDs  map[string]string `ovsdb:"external_ids"`
+	LoadBalancer []string          `ovsdb:"load_balancer"`
+	Name         string            `ovsdb:"name"`
+	NAT          []string          `ovsdb:"nat"`
+	Options      map[string]string `ovsdb:"options"`
+	Ports        []string          `ovsdb:"ports"`
+	StaticRoutes []string          `ovsdb:"static_routes"`
+}
+
+// Row converts a LogicalRouter into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LogicalRouter) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	if r.Enabled != nil {
+		set(LogicalRouterColumnEnabled, genEncodeAtom(*r.Enabled))
+	} else {
+		set(LogicalRouterColumnEnabled, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.LoadBalancer))
+		for _, e := range r.LoadBalancer {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalRouterColumnLoadBalancer, []interface{}{"set", elems})
+	}
+	set(LogicalRouterColumnName, genEncodeAtom(r.Name))
+	{
+		elems := make([]interface{}, 0, len(r.NAT))
+		for _, e := range r.NAT {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalRouterColumnNAT, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterColumnOptions, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.Ports))
+		for _, e := range r.Ports {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalRouterColumnPorts, []interface{}{"set", elems})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.StaticRoutes))
+		for _, e := range r.StaticRoutes {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalRouterColumnStaticRoutes, []interface{}{"set", elems})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a LogicalRouter using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LogicalRouter) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LogicalRouterColumnEnabled]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Enabled = nil
+				return nil
+			}
+
+			x, err := genDecodeBoolean(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Enabled = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnEnabled, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnLoadBalancer]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.LoadBalancer = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnLoadBalancer, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnName, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnNAT]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.NAT = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnNAT, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnPorts]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Ports = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnPorts, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterColumnStaticRoutes]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.StaticRoutes = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterColumnStaticRoutes, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Logical_Router_Port table and its columns.
+const (
+	TableLogicalRouterPort = "Logical_Router_Port"
+
+	LogicalRouterPortColumnEnabled     = "enabled"
+	LogicalRouterPortColumnExternalIDs = "external_ids"
+	LogicalRouterPortColumnMAC         = "mac"
+	LogicalRouterPortColumnName        = "name"
+	LogicalRouterPortColumnNetworks    = "networks"
+	LogicalRouterPortColumnOptions     = "options"
+	LogicalRouterPortColumnPeer        = "peer"
+)
+
+// LogicalRouterPort is a row in the Logical_Router_Port table.
+type LogicalRouterPort struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Enabled     *bool             `ovsdb:"enabled"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	MAC         string            `ovsdb:"mac"`
+	Name        string            `ovsdb:"name"`
+	Networks    []string          `ovsdb:"networks"`
+	Options     map[string]string `ovsdb:"options"`
+	Peer        *string           `ovsdb:"peer"`
+}
+
+// Row converts a LogicalRouterPort into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LogicalRouterPort) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	if r.Enabled != nil {
+		set(LogicalRouterPortColumnEnabled, genEncodeAtom(*r.Enabled))
+	} else {
+		set(LogicalRouterPortColumnEnabled, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterPortColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(LogicalRouterPortColumnMAC, genEncodeAtom(r.MAC))
+	set(LogicalRouterPortColumnName, genEncodeAtom(r.Name))
+	{
+		elems := make([]interface{}, 0, len(r.Networks))
+		for _, e := range r.Networks {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(LogicalRouterPortColumnNetworks, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterPortColumnOptions, []interface{}{"map", pairs})
+	}
+	if r.Peer != nil {
+		set(LogicalRouterPortColumnPeer, genEncodeAtom(*r.Peer))
+	} else {
+		set(LogicalRouterPortColumnPeer, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a LogicalRouterPort using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LogicalRouterPort) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LogicalRouterPortColumnEnabled]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Enabled = nil
+				return nil
+			}
+
+			x, err := genDecodeBoolean(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Enabled = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnEnabled, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnMAC]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.MAC = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnMAC, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnName, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnNetworks]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Networks = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnNetworks, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterPortColumnPeer]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Peer = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Peer = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterPortColumnPeer, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Logical_Router_Static_Route table and its columns.
+const (
+	TableLogicalRouterStaticRoute = "Logical_Router_Static_Route"
+
+	LogicalRouterStaticRouteColumnExternalIDs = "external_ids"
+	LogicalRouterStaticRouteColumnIPPrefix    = "ip_prefix"
+	LogicalRouterStaticRouteColumnNexthop     = "nexthop"
+	LogicalRouterStaticRouteColumnOptions     = "options"
+	LogicalRouterStaticRouteColumnOutputPort  = "output_port"
+	LogicalRouterStaticRouteColumnPolicy      = "policy"
+	LogicalRouterStaticRouteColumnRouteTable  = "route_table"
+)
+
+// LogicalRouterStaticRoute is a row in the Logical_Router_Static_Route table.
+type LogicalRouterStaticRoute struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	IPPrefix    string            `ovsdb:"ip_prefix"`
+	Nexthop     string            `ovsdb:"nexthop"`
+	Options     map[string]string `ovsdb:"options"`
+	OutputPort  *string           `ovsdb:"output_port"`
+	Policy      *string           `ovsdb:"policy"`
+	RouteTable  string            `ovsdb:"route_table"`
+}
+
+// Row converts a LogicalRouterStaticRoute into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LogicalRouterStaticRoute) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterStaticRouteColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(LogicalRouterStaticRouteColumnIPPrefix, genEncodeAtom(r.IPPrefix))
+	set(LogicalRouterStaticRouteColumnNexthop, genEncodeAtom(r.Nexthop))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalRouterStaticRouteColumnOptions, []interface{}{"map", pairs})
+	}
+	if r.OutputPort != nil {
+		set(LogicalRouterStaticRouteColumnOutputPort, genEncodeAtom(*r.OutputPort))
+	} else {
+		set(LogicalRouterStaticRouteColumnOutputPort, []interface{}{"set", []interface{}{}})
+	}
+	if r.Policy != nil {
+		set(LogicalRouterStaticRouteColumnPolicy, genEncodeAtom(*r.Policy))
+	} else {
+		set(LogicalRouterStaticRouteColumnPolicy, []interface{}{"set", []interface{}{}})
+	}
+	set(LogicalRouterStaticRouteColumnRouteTable, genEncodeAtom(r.RouteTable))
+
+	return row
+}
+
+// UnmarshalRow populates a LogicalRouterStaticRoute using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LogicalRouterStaticRoute) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnIPPrefix]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.IPPrefix = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnIPPrefix, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnNexthop]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Nexthop = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnNexthop, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnOutputPort]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.OutputPort = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.OutputPort = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnOutputPort, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnPolicy]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Policy = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Policy = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnPolicy, err)
+		}
+	}
+
+	if v, ok := row[LogicalRouterStaticRouteColumnRouteTable]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.RouteTable = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalRouterStaticRouteColumnRouteTable, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Logical_Switch table and its columns.
+const (
+	TableLogicalSwitch = "Logical_Switch"
+
+	LogicalSwitchColumnACLs         = "acls"
+	LogicalSwitchColumnExternalIDs  = "external_ids"
+	LogicalSwitchColumnLoadBalancer = "load_balancer"
+	LogicalSwitchColumnName         = "name"
+	LogicalSwitchColumnOtherConfig  = "other_config"
+	LogicalSwitchColumnPorts        = "ports"
+)
+
+// LogicalSwitch is a row in the Logical_Switch table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type LogicalSwitch struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ACLs         []string          `ovsdb:"acls"`
+	ExternalIDs  map[string]string `ovsdb:"external_ids"`
+	LoadBalancer []string          `ovsdb:"load_balancer"`
+	Name         string            `ovsdb:"name"`
+	OtherConfig  map[string]string `ovsdb:"other_config"`
+	Ports        []string          `ovsdb:"ports"`
+}
+
+// Row converts a LogicalSwitch into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LogicalSwitch) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.ACLs))
+		for _, e := range r.ACLs {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalSwitchColumnACLs, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalSwitchColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.LoadBalancer))
+		for _, e := range r.LoadBalancer {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalSwitchColumnLoadBalancer, []interface{}{"set", elems})
+	}
+	set(LogicalSwitchColumnName, genEncodeAtom(r.Name))
+	{
+		pairs := make([]interface{}, 0, len(r.OtherConfig))
+		for k, v := range r.OtherConfig {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalSwitchColumnOtherConfig, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.Ports))
+		for _, e := range r.Ports {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(LogicalSwitchColumnPorts, []interface{}{"set", elems})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a LogicalSwitch using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LogicalSwitch) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LogicalSwitchColumnACLs]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.ACLs = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnACLs, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchColumnLoadBalancer]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.LoadBalancer = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnLoadBalancer, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnName, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchColumnOtherConfig]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.OtherConfig = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnOtherConfig, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchColumnPorts]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Ports = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchColumnPorts, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Logical_Switch_Port table and its columns.
+const (
+	TableLogicalSwitchPort = "Logical_Switch_Port"
+
+	LogicalSwitchPortColumnAddresses        = "addresses"
+	LogicalSwitchPortColumnDynamicAddresses = "dynamic_addresses"
+	LogicalSwitchPortColumnEnabled          = "enabled"
+	LogicalSwitchPortColumnExternalIDs      = "external_ids"
+	LogicalSwitchPortColumnName             = "name"
+	LogicalSwitchPortColumnOptions          = "options"
+	LogicalSwitchPortColumnParentName       = "parent_name"
+	LogicalSwitchPortColumnPortSecurity     = "port_security"
+	LogicalSwitchPortColumnTag              = "tag"
+	LogicalSwitchPortColumnTagRequest       = "tag_request"
+	LogicalSwitchPortColumnType             = "type"
+	LogicalSwitchPortColumnUp               = "up"
+)
+
+// LogicalSwitchPort is a row in the Logical_Switch_Port table.
+type LogicalSwitchPort struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Addresses        []string          `ovsdb:"addresses"`
+	DynamicAddresses *string           `ovsdb:"dynamic_addresses"`
+	Enabled          *bool             `ovsdb:"enabled"`
+	ExternalIDs      map[string]string `ovsdb:"external_ids"`
+	Name             string            `ovsdb:"name"`
+	Options          map[string]string `ovsdb:"options"`
+	ParentName       *string           `ovsdb:"parent_name"`
+	PortSecurity     []string          `ovsdb:"port_security"`
+	Tag              *int              `ovsdb:"tag"`
+	TagRequest       *int              `ovsdb:"tag_request"`
+	Type             string            `ovsdb:"type"`
+	Up               *bool             `ovsdb:"up"`
+}
+
+// Row converts a LogicalSwitchPort into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *LogicalSwitchPort) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.Addresses))
+		for _, e := range r.Addresses {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(LogicalSwitchPortColumnAddresses, []interface{}{"set", elems})
+	}
+	if r.DynamicAddresses != nil {
+		set(LogicalSwitchPortColumnDynamicAddresses, genEncodeAtom(*r.DynamicAddresses))
+	} else {
+		set(LogicalSwitchPortColumnDynamicAddresses, []interface{}{"set", []interface{}{}})
+	}
+	if r.Enabled != nil {
+		set(LogicalSwitchPortColumnEnabled, genEncodeAtom(*r.Enabled))
+	} else {
+		set(LogicalSwitchPortColumnEnabled, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalSwitchPortColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(LogicalSwitchPortColumnName, genEncodeAtom(r.Name))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(LogicalSwitchPortColumnOptions, []interface{}{"map", pairs})
+	}
+	if r.ParentName != nil {
+		set(LogicalSwitchPortColumnParentName, genEncodeAtom(*r.ParentName))
+	} else {
+		set(LogicalSwitchPortColumnParentName, []interface{}{"set", []interface{}{}})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.PortSecurity))
+		for _, e := range r.PortSecurity {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(LogicalSwitchPortColumnPortSecurity, []interface{}{"set", elems})
+	}
+	if r.Tag != nil {
+		set(LogicalSwitchPortColumnTag, genEncodeAtom(*r.Tag))
+	} else {
+		set(LogicalSwitchPortColumnTag, []interface{}{"set", []interface{}{}})
+	}
+	if r.TagRequest != nil {
+		set(LogicalSwitchPortColumnTagRequest, genEncodeAtom(*r.TagRequest))
+	} else {
+		set(LogicalSwitchPortColumnTagRequest, []interface{}{"set", []interface{}{}})
+	}
+	set(LogicalSwitchPortColumnType, genEncodeAtom(r.Type))
+	if r.Up != nil {
+		set(LogicalSwitchPortColumnUp, genEncodeAtom(*r.Up))
+	} else {
+		set(LogicalSwitchPortColumnUp, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a LogicalSwitchPort using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *LogicalSwitchPort) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnAddresses]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Addresses = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnAddresses, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnDynamicAddresses]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.DynamicAddresses = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.DynamicAddresses = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnDynamicAddresses, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnEnabled]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Enabled = nil
+				return nil
+			}
+
+			x, err := genDecodeBoolean(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Enabled = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnEnabled, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnName, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnParentName]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.ParentName = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.ParentName = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnParentName, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnPortSecurity]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.PortSecurity = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnPortSecurity, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnTag]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Tag = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Tag = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnTag, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnTagRequest]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.TagRequest = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.TagRequest = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnTagRequest, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnType]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Type = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnType, err)
+		}
+	}
+
+	if v, ok := row[LogicalSwitchPortColumnUp]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Up = nil
+				return nil
+			}
+
+			x, err := genDecodeBoolean(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Up = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", LogicalSwitchPortColumnUp, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the NAT table and its columns.
+const (
+	TableNAT = "NAT"
+
+	NATColumnExternalIDs = "external_ids"
+	NATColumnExternalIP  = "external_ip"
+	NATColumnExternalMAC = "external_mac"
+	NATColumnLogicalIP   = "logical_ip"
+	NATColumnLogicalPort = "logical_port"
+	NATColumnOptions     = "options"
+	NATColumnType        = "type"
+)
+
+// NAT is a row in the NAT table.
+type NAT struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	ExternalIP  string            `ovsdb:"external_ip"`
+	ExternalMAC *string           `ovsdb:"external_mac"`
+	LogicalIP   string            `ovsdb:"logical_ip"`
+	LogicalPort *string           `ovsdb:"logical_port"`
+	Options     map[string]string `ovsdb:"options"`
+	Type        string            `ovsdb:"type"`
+}
+
+// Row converts a NAT into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *NAT) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(NATColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(NATColumnExternalIP, genEncodeAtom(r.ExternalIP))
+	if r.ExternalMAC != nil {
+		set(NATColumnExternalMAC, genEncodeAtom(*r.ExternalMAC))
+	} else {
+		set(NATColumnExternalMAC, []interface{}{"set", []interface{}{}})
+	}
+	set(NATColumnLogicalIP, genEncodeAtom(r.LogicalIP))
+	if r.LogicalPort != nil {
+		set(NATColumnLogicalPort, genEncodeAtom(*r.LogicalPort))
+	} else {
+		set(NATColumnLogicalPort, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(NATColumnOptions, []interface{}{"map", pairs})
+	}
+	set(NATColumnType, genEncodeAtom(r.Type))
+
+	return row
+}
+
+// UnmarshalRow populates a NAT using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *NAT) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[NATColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[NATColumnExternalIP]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.ExternalIP = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnExternalIP, err)
+		}
+	}
+
+	if v, ok := row[NATColumnExternalMAC]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.ExternalMAC = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.ExternalMAC = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnExternalMAC, err)
+		}
+	}
+
+	if v, ok := row[NATColumnLogicalIP]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.LogicalIP = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnLogicalIP, err)
+		}
+	}
+
+	if v, ok := row[NATColumnLogicalPort]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.LogicalPort = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.LogicalPort = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnLogicalPort, err)
+		}
+	}
+
+	if v, ok := row[NATColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[NATColumnType]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Type = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NATColumnType, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the NB_Global table and its columns.
+const (
+	TableNBGlobal = "NB_Global"
+
+	NBGlobalColumnExternalIDs    = "external_ids"
+	NBGlobalColumnHvCfg          = "hv_cfg"
+	NBGlobalColumnHvCfgTimestamp = "hv_cfg_timestamp"
+	NBGlobalColumnIpsec          = "ipsec"
+	NBGlobalColumnName           = "name"
+	NBGlobalColumnNBCfg          = "nb_cfg"
+	NBGlobalColumnNBCfgTimestamp = "nb_cfg_timestamp"
+	NBGlobalColumnOptions        = "options"
+	NBGlobalColumnSBCfg          = "sb_cfg"
+	NBGlobalColumnSBCfgTimestamp = "sb_cfg_timestamp"
+)
+
+// NBGlobal is a row in the NB_Global table.
+type NBGlobal struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs    map[string]string `ovsdb:"external_ids"`
+	HvCfg          int               `ovsdb:"hv_cfg"`
+	HvCfgTimestamp int               `ovsdb:"hv_cfg_timestamp"`
+	Ipsec          bool              `ovsdb:"ipsec"`
+	Name           string            `ovsdb:"name"`
+	NBCfg          int               `ovsdb:"nb_cfg"`
+	NBCfgTimestamp int               `ovsdb:"nb_cfg_timestamp"`
+	Options        map[string]string `ovsdb:"options"`
+	SBCfg          int               `ovsdb:"sb_cfg"`
+	SBCfgTimestamp int               `ovsdb:"sb_cfg_timestamp"`
+}
+
+// Row converts a NBGlobal into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *NBGlobal) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(NBGlobalColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(NBGlobalColumnHvCfg, genEncodeAtom(r.HvCfg))
+	set(NBGlobalColumnHvCfgTimestamp, genEncodeAtom(r.HvCfgTimestamp))
+	set(NBGlobalColumnIpsec, genEncodeAtom(r.Ipsec))
+	set(NBGlobalColumnName, genEncodeAtom(r.Name))
+	set(NBGlobalColumnNBCfg, genEncodeAtom(r.NBCfg))
+	set(NBGlobalColumnNBCfgTimestamp, genEncodeAtom(r.NBCfgTimestamp))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(NBGlobalColumnOptions, []interface{}{"map", pairs})
+	}
+	set(NBGlobalColumnSBCfg, genEncodeAtom(r.SBCfg))
+	set(NBGlobalColumnSBCfgTimestamp, genEncodeAtom(r.SBCfgTimestamp))
+
+	return row
+}
+
+// UnmarshalRow populates a NBGlobal using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *NBGlobal) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[NBGlobalColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnHvCfg]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.HvCfg = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnHvCfg, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnHvCfgTimestamp]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.HvCfgTimestamp = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnHvCfgTimestamp, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnIpsec]; ok {
+		if err := func() error {
+			x, err := genDecodeBoolean(v)
+			if err != nil {
+				return err
+			}
+
+			r.Ipsec = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnIpsec, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnName, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnNBCfg]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.NBCfg = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnNBCfg, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnNBCfgTimestamp]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.NBCfgTimestamp = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnNBCfgTimestamp, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnSBCfg]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.SBCfg = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnSBCfg, err)
+		}
+	}
+
+	if v, ok := row[NBGlobalColumnSBCfgTimestamp]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.SBCfgTimestamp = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", NBGlobalColumnSBCfgTimestamp, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Port_Group table and its columns.
+const (
+	TablePortGroup = "Port_Group"
+
+	PortGroupColumnACLs        = "acls"
+	PortGroupColumnExternalIDs = "external_ids"
+	PortGroupColumnName        = "name"
+	PortGroupColumnPorts       = "ports"
+)
+
+// PortGroup is a row in the Port_Group table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type PortGroup struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ACLs        []string          `ovsdb:"acls"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Name        string            `ovsdb:"name"`
+	Ports       []string          `ovsdb:"ports"`
+}
+
+// Row converts a PortGroup into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *PortGroup) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.ACLs))
+		for _, e := range r.ACLs {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(PortGroupColumnACLs, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(PortGroupColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(PortGroupColumnName, genEncodeAtom(r.Name))
+	{
+		elems := make([]interface{}, 0, len(r.Ports))
+		for _, e := range r.Ports {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(PortGroupColumnPorts, []interface{}{"set", elems})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a PortGroup using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *PortGroup) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[PortGroupColumnACLs]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.ACLs = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortGroupColumnACLs, err)
+		}
+	}
+
+	if v, ok := row[PortGroupColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortGroupColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[PortGroupColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortGroupColumnName, err)
+		}
+	}
+
+	if v, ok := row[PortGroupColumnPorts]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Ports = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortGroupColumnPorts, err)
+		}
+	}
+
+	return nil
+}
+
+// genWantColumn reports whether column appears in columns, or columns is
+// empty.
+func genWantColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// genEncodeAtom encodes an atom which requires no special encoding.
+func genEncodeAtom(v interface{}) interface{} {
+	return v
+}
+
+// genEncodeUUID encodes a UUID atom.  If s is not a UUID, it is assumed to be
+// the UUID name of a row inserted earlier in the same transaction.
+func genEncodeUUID(s string) interface{} {
+	if !genIsUUID(s) {
+		return ovsdb.NamedUUID(s)
+	}
+
+	return []interface{}{"uuid", s}
+}
+
+// genIsUUID reports whether s is in the canonical UUID format.
+func genIsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// genDecodeSet decodes a set, which may be encoded as a single atom.
+func genDecodeSet(v interface{}) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "set" {
+		// A set with one element may be encoded as the element itself.
+		return []interface{}{v}, nil
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set: %v", v)
+	}
+
+	return elems, nil
+}
+
+// genDecodeMap decodes a map into key/value pairs.
+func genDecodeMap(v interface{}) ([][2]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "map" {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	pairs := make([][2]interface{}, 0, len(elems))
+	for _, e := range elems {
+		p, ok := e.([]interface{})
+		if !ok || len(p) != 2 {
+			return nil, fmt.Errorf("invalid map pair: %v", e)
+		}
+
+		pairs = append(pairs, [2]interface{}{p[0], p[1]})
+	}
+
+	return pairs, nil
+}
+
+// genDecodeInteger decodes an integer atom.
+func genDecodeInteger(v interface{}) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case float64:
+		if x != float64(int(x)) {
+			return 0, fmt.Errorf("invalid integer: %v", v)
+		}
+
+		return int(x), nil
+	case json.Number:
+		n, err := x.Int64()
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("invalid integer: %v", v)
+	}
+}
+
+// genDecodeReal decodes a real atom.
+func genDecodeReal(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	default:
+		return 0, fmt.Errorf("invalid real: %v", v)
+	}
+}
+
+// genDecodeBoolean decodes a boolean atom.
+func genDecodeBoolean(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid boolean: %v", v)
+	}
+
+	return b, nil
+}
+
+// genDecodeString decodes a string atom.
+func genDecodeString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid string: %v", v)
+	}
+
+	return s, nil
+}
+
+// genDecodeUUID decodes a UUID atom.
+func genDecodeUUID(v interface{}) (string, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "uuid" {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	s, ok := a[1].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	return s, nil
+}
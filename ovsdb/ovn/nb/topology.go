@@ -0,0 +1,235 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// CreateLogicalSwitch creates a logical switch.  The switch may or may not
+// already exist.
+func (c *Client) CreateLogicalSwitch(ctx context.Context, name string) error {
+	_, ok, err := c.LogicalSwitch(ctx, name)
+	if err != nil || ok {
+		return err
+	}
+
+	ls := LogicalSwitch{Name: name}
+	return c.transact(ctx, []ovsdb.TransactOp{
+		// Guard against the switch being created concurrently.
+		notExists(TableLogicalSwitch, LogicalSwitchColumnName, name),
+		ovsdb.Insert{
+			Table: TableLogicalSwitch,
+			Row:   ls.Row(),
+		},
+	})
+}
+
+// DeleteLogicalSwitch deletes a logical switch and all of its ports.  The
+// switch may or may not already exist.
+func (c *Client) DeleteLogicalSwitch(ctx context.Context, name string) error {
+	ls, ok, err := c.LogicalSwitch(ctx, name)
+	if err != nil || !ok {
+		return err
+	}
+
+	// The switch's ports are garbage collected by ovsdb-server once they are
+	// no longer referenced.
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Delete{
+			Table: TableLogicalSwitch,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(ls.UUID))},
+		},
+	})
+}
+
+// AddSwitchPort creates a logical switch port with zero or more addresses,
+// such as "00:00:00:00:00:01 10.0.0.1", and attaches it to a logical switch.
+// The switch must exist, but the port may or may not already exist.
+func (c *Client) AddSwitchPort(ctx context.Context, sw, port string, addresses ...string) error {
+	ls, ok, err := c.LogicalSwitch(ctx, sw)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("logical switch %q does not exist", sw)
+	}
+
+	_, ok, err = c.LogicalSwitchPort(ctx, port)
+	if err != nil || ok {
+		return err
+	}
+
+	lsp := LogicalSwitchPort{
+		Name:      port,
+		Addresses: addresses,
+	}
+
+	return c.transact(ctx, []ovsdb.TransactOp{
+		notExists(TableLogicalSwitchPort, LogicalSwitchPortColumnName, port),
+		ovsdb.Insert{
+			Table:    TableLogicalSwitchPort,
+			UUIDName: "new_lsp",
+			Row:      lsp.Row(),
+		},
+		addRef(TableLogicalSwitch, ls.UUID, LogicalSwitchColumnPorts, "new_lsp"),
+	})
+}
+
+// DeleteSwitchPort detaches a logical switch port from a logical switch and
+// deletes the port.  The port may or may not already exist.
+func (c *Client) DeleteSwitchPort(ctx context.Context, sw, port string) error {
+	lsp, ok, err := c.LogicalSwitchPort(ctx, port)
+	if err != nil || !ok {
+		return err
+	}
+
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: TableLogicalSwitch,
+			Where: []ovsdb.Cond{ovsdb.Equal(LogicalSwitchColumnName, sw)},
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete(LogicalSwitchColumnPorts, ovsdb.Set{ovsdb.UUID(lsp.UUID)}),
+			},
+		},
+	})
+}
+
+// CreateRouter creates a logical router.  The router may or may not already
+// exist.
+func (c *Client) CreateRouter(ctx context.Context, name string) error {
+	_, ok, err := c.LogicalRouter(ctx, name)
+	if err != nil || ok {
+		return err
+	}
+
+	lr := LogicalRouter{Name: name}
+	return c.transact(ctx, []ovsdb.TransactOp{
+		notExists(TableLogicalRouter, LogicalRouterColumnName, name),
+		ovsdb.Insert{
+			Table: TableLogicalRouter,
+			Row:   lr.Row(),
+		},
+	})
+}
+
+// DeleteRouter deletes a logical router and all of its ports.  The router
+// may or may not already exist.
+func (c *Client) DeleteRouter(ctx context.Context, name string) error {
+	lr, ok, err := c.LogicalRouter(ctx, name)
+	if err != nil || !ok {
+		return err
+	}
+
+	return c.transact(ctx, []ovsdb.TransactOp{
+		ovsdb.Delete{
+			Table: TableLogicalRouter,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(lr.UUID))},
+		},
+	})
+}
+
+// ConnectRouterToSwitch connects a logical router to a logical switch, as
+// with "ovn-nbctl lrp-add" followed by "ovn-nbctl lsp-add" of a router port.
+// Both the router and the switch must exist.
+//
+// A logical router port named "<router>-<switch>" is added to the router with
+// the specified MAC address and networks, such as "10.0.0.1/24".  A logical
+// switch port named "<switch>-<router>" of type "router" is added to the
+// switch and attached to the router port.
+func (c *Client) ConnectRouterToSwitch(ctx context.Context, router, sw, mac string, networks ...string) error {
+	if len(networks) == 0 {
+		return fmt.Errorf("at least one network is required to connect router %q to switch %q", router, sw)
+	}
+
+	lr, ok, err := c.LogicalRouter(ctx, router)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("logical router %q does not exist", router)
+	}
+
+	ls, ok, err := c.LogicalSwitch(ctx, sw)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("logical switch %q does not exist", sw)
+	}
+
+	lrp := LogicalRouterPort{
+		Name:     router + "-" + sw,
+		MAC:      mac,
+		Networks: networks,
+	}
+
+	lsp := LogicalSwitchPort{
+		Name:      sw + "-" + router,
+		Type:      "router",
+		Addresses: []string{"router"},
+		Options:   map[string]string{"router-port": lrp.Name},
+	}
+
+	return c.transact(ctx, []ovsdb.TransactOp{
+		// Guard against either port already existing.
+		notExists(TableLogicalRouterPort, LogicalRouterPortColumnName, lrp.Name),
+		notExists(TableLogicalSwitchPort, LogicalSwitchPortColumnName, lsp.Name),
+		ovsdb.Insert{
+			Table:    TableLogicalRouterPort,
+			UUIDName: "new_lrp",
+			Row:      lrp.Row(),
+		},
+		addRef(TableLogicalRouter, lr.UUID, LogicalRouterColumnPorts, "new_lrp"),
+		ovsdb.Insert{
+			Table:    TableLogicalSwitchPort,
+			UUIDName: "new_lsp",
+			Row:      lsp.Row(),
+		},
+		addRef(TableLogicalSwitch, ls.UUID, LogicalSwitchColumnPorts, "new_lsp"),
+	})
+}
+
+// transact executes a transaction on the Northbound database.
+func (c *Client) transact(ctx context.Context, ops []ovsdb.TransactOp) error {
+	_, err := c.c.Transact(ctx, DatabaseName, ops)
+	return err
+}
+
+// notExists returns a TransactOp which aborts a transaction if a row whose
+// column has the specified value exists in table.
+func notExists(table, column, value string) ovsdb.TransactOp {
+	return ovsdb.Wait{
+		Table:   table,
+		Where:   []ovsdb.Cond{ovsdb.Equal(column, value)},
+		Columns: []string{column},
+		Rows:    []ovsdb.Row{},
+	}
+}
+
+// addRef returns a TransactOp which adds a reference to the row with UUID
+// name ref to the set column of the row with the specified UUID in table.
+func addRef(table, uuid, column, ref string) ovsdb.TransactOp {
+	return ovsdb.Mutate{
+		Table: table,
+		Where: []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(uuid))},
+		Mutations: []ovsdb.Mutation{
+			ovsdb.MutateInsert(column, ovsdb.Set{ovsdb.NamedUUID(ref)}),
+		},
+	}
+}
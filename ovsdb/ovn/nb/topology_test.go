@@ -0,0 +1,159 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nb_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientTopology(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	// Creating switches and routers is idempotent.
+	for i := 0; i < 2; i++ {
+		if err := c.CreateLogicalSwitch(ctx, "ls0"); err != nil {
+			t.Fatalf("failed to create logical switch: %v", err)
+		}
+		if err := c.CreateRouter(ctx, "lr0"); err != nil {
+			t.Fatalf("failed to create logical router: %v", err)
+		}
+		if err := c.AddSwitchPort(ctx, "ls0", "lsp0", "00:00:00:00:00:01 10.0.0.1"); err != nil {
+			t.Fatalf("failed to add switch port: %v", err)
+		}
+	}
+
+	if err := c.ConnectRouterToSwitch(ctx, "lr0", "ls0", "00:00:00:00:00:fe", "10.0.0.254/24"); err != nil {
+		t.Fatalf("failed to connect router to switch: %v", err)
+	}
+
+	// The connection's ports already exist.
+	if err := c.ConnectRouterToSwitch(ctx, "lr0", "ls0", "00:00:00:00:00:fe", "10.0.0.254/24"); err == nil {
+		t.Fatal("expected an error connecting router to switch twice, but none occurred")
+	}
+
+	lsp, ok, err := c.LogicalSwitchPort(ctx, "lsp0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get switch port: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff([]string{"00:00:00:00:00:01 10.0.0.1"}, lsp.Addresses); diff != "" {
+		t.Fatalf("unexpected switch port addresses (-want +got):\n%s", diff)
+	}
+
+	rsp, ok, err := c.LogicalSwitchPort(ctx, "ls0-lr0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get router's switch port: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff("router", rsp.Type); diff != "" {
+		t.Fatalf("unexpected switch port type (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"router-port": "lr0-ls0"}, rsp.Options); diff != "" {
+		t.Fatalf("unexpected switch port options (-want +got):\n%s", diff)
+	}
+
+	lrp, ok, err := c.LogicalRouterPort(ctx, "lr0-ls0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get router port: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff([]string{"10.0.0.254/24"}, lrp.Networks); diff != "" {
+		t.Fatalf("unexpected router port networks (-want +got):\n%s", diff)
+	}
+
+	ls, _, err := c.LogicalSwitch(ctx, "ls0")
+	if err != nil {
+		t.Fatalf("failed to get switch: %v", err)
+	}
+	if diff := cmp.Diff(sorted(lsp.UUID, rsp.UUID), sorted(ls.Ports...)); diff != "" {
+		t.Fatalf("unexpected switch ports (-want +got):\n%s", diff)
+	}
+
+	lr, _, err := c.LogicalRouter(ctx, "lr0")
+	if err != nil {
+		t.Fatalf("failed to get router: %v", err)
+	}
+	if diff := cmp.Diff([]string{lrp.UUID}, lr.Ports); diff != "" {
+		t.Fatalf("unexpected router ports (-want +got):\n%s", diff)
+	}
+
+	// Ports are deleted with their switches and routers.
+	if err := c.DeleteSwitchPort(ctx, "ls0", "lsp0"); err != nil {
+		t.Fatalf("failed to delete switch port: %v", err)
+	}
+	if _, ok, err := c.LogicalSwitchPort(ctx, "lsp0"); err != nil || ok {
+		t.Fatalf("unexpected switch port after deletion: %v, %v", ok, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteLogicalSwitch(ctx, "ls0"); err != nil {
+			t.Fatalf("failed to delete logical switch: %v", err)
+		}
+		if err := c.DeleteRouter(ctx, "lr0"); err != nil {
+			t.Fatalf("failed to delete logical router: %v", err)
+		}
+	}
+
+	ports, err := c.LogicalSwitchPorts(ctx)
+	if err != nil {
+		t.Fatalf("failed to list switch ports: %v", err)
+	}
+	rports, err := c.LogicalRouterPorts(ctx)
+	if err != nil {
+		t.Fatalf("failed to list router ports: %v", err)
+	}
+
+	if n := len(ports) + len(rports); n != 0 {
+		t.Fatalf("expected no ports after deleting topology, but found %d", n)
+	}
+}
+
+func TestClientTopologyErrors(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	if err := c.AddSwitchPort(ctx, "ls0", "lsp0"); err == nil {
+		t.Fatal("expected an error adding a port to a missing switch, but none occurred")
+	}
+
+	if err := c.CreateLogicalSwitch(ctx, "ls0"); err != nil {
+		t.Fatalf("failed to create logical switch: %v", err)
+	}
+
+	if err := c.ConnectRouterToSwitch(ctx, "lr0", "ls0", "00:00:00:00:00:fe", "10.0.0.254/24"); err == nil {
+		t.Fatal("expected an error connecting a missing router, but none occurred")
+	}
+
+	if err := c.CreateRouter(ctx, "lr0"); err != nil {
+		t.Fatalf("failed to create logical router: %v", err)
+	}
+
+	if err := c.ConnectRouterToSwitch(ctx, "lr0", "ls0", "00:00:00:00:00:fe"); err == nil {
+		t.Fatal("expected an error connecting without networks, but none occurred")
+	}
+}
+
+// sorted returns a sorted copy of ss.
+func sorted(ss ...string) []string {
+	out := append([]string(nil), ss...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,210 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// A Client provides typed access to an OVN Southbound database using an
+// OVSDB connection to its ovsdb-server.
+type Client struct {
+	c *ovsdb.Client
+}
+
+// New creates a Client which uses the OVSDB connection c.  The Client does
+// not take ownership of c, which must be closed by the caller.
+func New(c *ovsdb.Client) *Client {
+	return &Client{c: c}
+}
+
+// SBGlobal returns the database's single SB_Global row.
+func (c *Client) SBGlobal(ctx context.Context) (*SBGlobal, error) {
+	var gs []SBGlobal
+	err := c.selectRows(ctx, TableSBGlobal, nil, func(row ovsdb.Row) error {
+		var g SBGlobal
+		if err := g.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		gs = append(gs, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gs) == 0 {
+		return nil, errors.New("SB_Global row does not exist")
+	}
+
+	return &gs[0], nil
+}
+
+// AllChassis returns all chassis.
+func (c *Client) AllChassis(ctx context.Context) ([]Chassis, error) {
+	var out []Chassis
+	err := c.selectRows(ctx, TableChassis, nil, func(row ovsdb.Row) error {
+		var ch Chassis
+		if err := ch.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, ch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Chassis returns the chassis with the specified name, and reports whether
+// it exists.
+func (c *Client) Chassis(ctx context.Context, name string) (*Chassis, bool, error) {
+	var ch Chassis
+	ok, err := c.get(ctx, TableChassis, ChassisColumnName, name, &ch)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &ch, true, nil
+}
+
+// Encaps returns the tunnel encapsulations of all chassis.
+func (c *Client) Encaps(ctx context.Context) ([]Encap, error) {
+	var out []Encap
+	err := c.selectRows(ctx, TableEncap, nil, func(row ovsdb.Row) error {
+		var e Encap
+		if err := e.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// PortBindings returns all port bindings.
+func (c *Client) PortBindings(ctx context.Context) ([]PortBinding, error) {
+	return c.portBindings(ctx, nil)
+}
+
+// PortBinding returns the port binding of the logical port with the
+// specified name, and reports whether it exists.
+func (c *Client) PortBinding(ctx context.Context, logicalPort string) (*PortBinding, bool, error) {
+	var pb PortBinding
+	ok, err := c.get(ctx, TablePortBinding, PortBindingColumnLogicalPort, logicalPort, &pb)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &pb, true, nil
+}
+
+// BoundPorts returns the port bindings of the logical ports which are bound
+// to the chassis with the specified name.  The chassis must exist.
+func (c *Client) BoundPorts(ctx context.Context, chassis string) ([]PortBinding, error) {
+	ch, ok, err := c.Chassis(ctx, chassis)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("chassis %q does not exist", chassis)
+	}
+
+	return c.portBindings(ctx, []ovsdb.Cond{
+		ovsdb.Equal(PortBindingColumnChassis, ovsdb.UUID(ch.UUID)),
+	})
+}
+
+// portBindings returns the port bindings which match where.
+func (c *Client) portBindings(ctx context.Context, where []ovsdb.Cond) ([]PortBinding, error) {
+	var out []PortBinding
+	err := c.selectRows(ctx, TablePortBinding, where, func(row ovsdb.Row) error {
+		var pb PortBinding
+		if err := pb.UnmarshalRow(row); err != nil {
+			return err
+		}
+
+		out = append(out, pb)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// A rowUnmarshaler is a generated type which can be populated from a Row.
+type rowUnmarshaler interface {
+	UnmarshalRow(row ovsdb.Row) error
+}
+
+// get populates r with the row in table whose column has the specified
+// value, and reports whether the row exists.  It is an error for more than
+// one row to match.
+func (c *Client) get(ctx context.Context, table, column, value string, r rowUnmarshaler) (bool, error) {
+	var rows []ovsdb.Row
+	err := c.selectRows(ctx, table, []ovsdb.Cond{ovsdb.Equal(column, value)}, func(row ovsdb.Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch len(rows) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, r.UnmarshalRow(rows[0])
+	default:
+		return false, fmt.Errorf("multiple rows with %s %q in table %q", column, value, table)
+	}
+}
+
+// selectRows selects the rows of table which match where, and invokes fn for
+// each row.
+func (c *Client) selectRows(ctx context.Context, table string, where []ovsdb.Cond, fn func(row ovsdb.Row) error) error {
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Select{
+			Table: table,
+			Where: where,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range res[0].Rows {
+		if err := fn(row); err != nil {
+			return fmt.Errorf("invalid row in table %q: %v", table, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sb_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovn/sb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbserver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClient(t *testing.T) {
+	c, oc, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	g := sb.SBGlobal{NBCfg: 2}
+	dp := sb.DatapathBinding{TunnelKey: 1}
+	encap := sb.Encap{
+		Type:        "geneve",
+		IP:          "192.0.2.1",
+		ChassisName: "chassis-1",
+	}
+	chassis := sb.Chassis{
+		Name:     "chassis-1",
+		Hostname: "host-1",
+		Encaps:   []string{"new_encap"},
+	}
+
+	bound := "new_chassis"
+	pb0 := sb.PortBinding{
+		LogicalPort: "lp0",
+		Datapath:    "new_dp",
+		TunnelKey:   1,
+		Chassis:     &bound,
+		MAC:         []string{"00:00:00:00:00:01 10.0.0.1"},
+	}
+	pb1 := sb.PortBinding{
+		LogicalPort: "lp1",
+		Datapath:    "new_dp",
+		TunnelKey:   2,
+	}
+
+	res, err := oc.Transact(ctx, sb.DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Insert{Table: sb.TableSBGlobal, Row: g.Row()},
+		ovsdb.Insert{Table: sb.TableDatapathBinding, UUIDName: "new_dp", Row: dp.Row()},
+		ovsdb.Insert{Table: sb.TableEncap, UUIDName: "new_encap", Row: encap.Row()},
+		ovsdb.Insert{Table: sb.TableChassis, UUIDName: "new_chassis", Row: chassis.Row()},
+		ovsdb.Insert{Table: sb.TablePortBinding, Row: pb0.Row()},
+		ovsdb.Insert{Table: sb.TablePortBinding, Row: pb1.Row()},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+
+	// Fill in the UUIDs assigned by the server.
+	g.UUID = res[0].UUID
+	encap.UUID = res[2].UUID
+	chassis.UUID = res[3].UUID
+	chassis.Encaps = []string{encap.UUID}
+	pb0.UUID = res[4].UUID
+	pb0.Datapath = res[1].UUID
+	pb0.Chassis = &chassis.UUID
+	pb1.UUID = res[5].UUID
+	pb1.Datapath = res[1].UUID
+
+	// Empty collections are decoded as empty values rather than nil.
+	opts := []cmp.Option{cmpopts.EquateEmpty()}
+
+	gotG, err := c.SBGlobal(ctx)
+	if err != nil {
+		t.Fatalf("failed to get SB_Global: %v", err)
+	}
+	if diff := cmp.Diff(&g, gotG, opts...); diff != "" {
+		t.Fatalf("unexpected SB_Global (-want +got):\n%s", diff)
+	}
+
+	allChassis, err := c.AllChassis(ctx)
+	if err != nil {
+		t.Fatalf("failed to list chassis: %v", err)
+	}
+	if diff := cmp.Diff([]sb.Chassis{chassis}, allChassis, opts...); diff != "" {
+		t.Fatalf("unexpected chassis (-want +got):\n%s", diff)
+	}
+
+	gotChassis, ok, err := c.Chassis(ctx, "chassis-1")
+	if err != nil || !ok {
+		t.Fatalf("failed to get chassis: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&chassis, gotChassis, opts...); diff != "" {
+		t.Fatalf("unexpected chassis (-want +got):\n%s", diff)
+	}
+
+	encaps, err := c.Encaps(ctx)
+	if err != nil {
+		t.Fatalf("failed to list encaps: %v", err)
+	}
+	if diff := cmp.Diff([]sb.Encap{encap}, encaps, opts...); diff != "" {
+		t.Fatalf("unexpected encaps (-want +got):\n%s", diff)
+	}
+
+	pbs, err := c.PortBindings(ctx)
+	if err != nil {
+		t.Fatalf("failed to list port bindings: %v", err)
+	}
+	if diff := cmp.Diff([]sb.PortBinding{pb0, pb1}, pbs, append(opts, sortPortBindings())...); diff != "" {
+		t.Fatalf("unexpected port bindings (-want +got):\n%s", diff)
+	}
+
+	gotPB, ok, err := c.PortBinding(ctx, "lp0")
+	if err != nil || !ok {
+		t.Fatalf("failed to get port binding: %v, %v", ok, err)
+	}
+	if diff := cmp.Diff(&pb0, gotPB, opts...); diff != "" {
+		t.Fatalf("unexpected port binding (-want +got):\n%s", diff)
+	}
+
+	boundPorts, err := c.BoundPorts(ctx, "chassis-1")
+	if err != nil {
+		t.Fatalf("failed to list bound ports: %v", err)
+	}
+	if diff := cmp.Diff([]sb.PortBinding{pb0}, boundPorts, opts...); diff != "" {
+		t.Fatalf("unexpected bound ports (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	c, _, done := testClient(t)
+	defer done()
+
+	ctx := context.Background()
+
+	if _, err := c.SBGlobal(ctx); err == nil {
+		t.Fatal("expected an error for missing SB_Global, but none occurred")
+	}
+
+	if _, ok, err := c.PortBinding(ctx, "lp0"); err != nil || ok {
+		t.Fatalf("unexpected port binding: %v, %v", ok, err)
+	}
+
+	if _, err := c.BoundPorts(ctx, "chassis-1"); err == nil {
+		t.Fatal("expected an error for missing chassis, but none occurred")
+	}
+}
+
+// sortPortBindings sorts port bindings by logical port name for comparison.
+func sortPortBindings() cmp.Option {
+	return cmpopts.SortSlices(func(a, b sb.PortBinding) bool {
+		return a.LogicalPort < b.LogicalPort
+	})
+}
+
+// testClient creates a Client backed by an in-memory server which uses the
+// package's Southbound schema.  The underlying ovsdb.Client is also
+// returned to set up the contents of the database.
+func testClient(t *testing.T) (*sb.Client, *ovsdb.Client, func()) {
+	t.Helper()
+
+	b, err := ioutil.ReadFile("sb.ovsschema")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var schema ovsdb.Schema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	s, err := ovsdbserver.New(&schema)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	oc, err := ovsdb.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	return sb.New(oc), oc, func() {
+		_ = oc.Close()
+		_ = s.Close()
+	}
+}
@@ -0,0 +1,1292 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by ovsdbgen. DO NOT EDIT.
+
+// Package sb contains bindings for the OVN_Southbound OVSDB schema, version 20.17.0.
+package sb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// DatabaseName is the name of the OVN_Southbound database.
+const DatabaseName = "OVN_Southbound"
+
+// Names of the Chassis table and its columns.
+const (
+	TableChassis = "Chassis"
+
+	ChassisColumnEncaps              = "encaps"
+	ChassisColumnExternalIDs         = "external_ids"
+	ChassisColumnHostname            = "hostname"
+	ChassisColumnName                = "name"
+	ChassisColumnNBCfg               = "nb_cfg"
+	ChassisColumnOtherConfig         = "other_config"
+	ChassisColumnTransportZones      = "transport_zones"
+	ChassisColumnVTEPLogicalSwitches = "vtep_logical_switches"
+)
+
+// Chassis is a row in the Chassis table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type Chassis struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Encaps              []string          `ovsdb:"encaps"`
+	ExternalIDs         map[string]string `ovsdb:"external_ids"`
+	Hostname            string            `ovsdb:"hostname"`
+	Name                string            `ovsdb:"name"`
+	NBCfg               int               `ovsdb:"nb_cfg"`
+	OtherConfig         map[string]string `ovsdb:"other_config"`
+	TransportZones      []string          `ovsdb:"transport_zones"`
+	VTEPLogicalSwitches []string          `ovsdb:"vtep_logical_switches"`
+}
+
+// Row converts a Chassis into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Chassis) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		elems := make([]interface{}, 0, len(r.Encaps))
+		for _, e := range r.Encaps {
+			elems = append(elems, genEncodeUUID(e))
+		}
+		set(ChassisColumnEncaps, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(ChassisColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(ChassisColumnHostname, genEncodeAtom(r.Hostname))
+	set(ChassisColumnName, genEncodeAtom(r.Name))
+	set(ChassisColumnNBCfg, genEncodeAtom(r.NBCfg))
+	{
+		pairs := make([]interface{}, 0, len(r.OtherConfig))
+		for k, v := range r.OtherConfig {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(ChassisColumnOtherConfig, []interface{}{"map", pairs})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.TransportZones))
+		for _, e := range r.TransportZones {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(ChassisColumnTransportZones, []interface{}{"set", elems})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.VTEPLogicalSwitches))
+		for _, e := range r.VTEPLogicalSwitches {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(ChassisColumnVTEPLogicalSwitches, []interface{}{"set", elems})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a Chassis using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Chassis) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[ChassisColumnEncaps]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeUUID(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.Encaps = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnEncaps, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnHostname]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Hostname = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnHostname, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Name = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnName, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnNBCfg]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.NBCfg = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnNBCfg, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnOtherConfig]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.OtherConfig = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnOtherConfig, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnTransportZones]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.TransportZones = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnTransportZones, err)
+		}
+	}
+
+	if v, ok := row[ChassisColumnVTEPLogicalSwitches]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.VTEPLogicalSwitches = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", ChassisColumnVTEPLogicalSwitches, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Datapath_Binding table and its columns.
+const (
+	TableDatapathBinding = "Datapath_Binding"
+
+	DatapathBindingColumnExternalIDs = "external_ids"
+	DatapathBindingColumnTunnelKey   = "tunnel_key"
+)
+
+// DatapathBinding is a row in the Datapath_Binding table.
+type DatapathBinding struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	TunnelKey   int               `ovsdb:"tunnel_key"`
+}
+
+// Row converts a DatapathBinding into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *DatapathBinding) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(DatapathBindingColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(DatapathBindingColumnTunnelKey, genEncodeAtom(r.TunnelKey))
+
+	return row
+}
+
+// UnmarshalRow populates a DatapathBinding using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *DatapathBinding) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[DatapathBindingColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", DatapathBindingColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[DatapathBindingColumnTunnelKey]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.TunnelKey = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", DatapathBindingColumnTunnelKey, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Encap table and its columns.
+const (
+	TableEncap = "Encap"
+
+	EncapColumnChassisName = "chassis_name"
+	EncapColumnIP          = "ip"
+	EncapColumnOptions     = "options"
+	EncapColumnType        = "type"
+)
+
+// Encap is a row in the Encap table.
+type Encap struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ChassisName string            `ovsdb:"chassis_name"`
+	IP          string            `ovsdb:"ip"`
+	Options     map[string]string `ovsdb:"options"`
+	Type        string            `ovsdb:"type"`
+}
+
+// Row converts a Encap into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *Encap) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	set(EncapColumnChassisName, genEncodeAtom(r.ChassisName))
+	set(EncapColumnIP, genEncodeAtom(r.IP))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(EncapColumnOptions, []interface{}{"map", pairs})
+	}
+	set(EncapColumnType, genEncodeAtom(r.Type))
+
+	return row
+}
+
+// UnmarshalRow populates a Encap using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *Encap) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[EncapColumnChassisName]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.ChassisName = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", EncapColumnChassisName, err)
+		}
+	}
+
+	if v, ok := row[EncapColumnIP]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.IP = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", EncapColumnIP, err)
+		}
+	}
+
+	if v, ok := row[EncapColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", EncapColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[EncapColumnType]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Type = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", EncapColumnType, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the Port_Binding table and its columns.
+const (
+	TablePortBinding = "Port_Binding"
+
+	PortBindingColumnChassis          = "chassis"
+	PortBindingColumnDatapath         = "datapath"
+	PortBindingColumnEncap            = "encap"
+	PortBindingColumnExternalIDs      = "external_ids"
+	PortBindingColumnLogicalPort      = "logical_port"
+	PortBindingColumnMAC              = "mac"
+	PortBindingColumnNATAddresses     = "nat_addresses"
+	PortBindingColumnOptions          = "options"
+	PortBindingColumnParentPort       = "parent_port"
+	PortBindingColumnRequestedChassis = "requested_chassis"
+	PortBindingColumnTag              = "tag"
+	PortBindingColumnTunnelKey        = "tunnel_key"
+	PortBindingColumnType             = "type"
+	PortBindingColumnUp               = "up"
+	PortBindingColumnVirtualParent    = "virtual_parent"
+)
+
+// PortBinding is a row in the Port_Binding table.
+//
+// Fields which reference other rows contain either the UUID of an existing
+// row, or the UUID name of a row inserted earlier in the same transaction.
+type PortBinding struct {
+	UUID string `ovsdb:"_uuid"`
+
+	Chassis          *string           `ovsdb:"chassis"`
+	Datapath         string            `ovsdb:"datapath"`
+	Encap            *string           `ovsdb:"encap"`
+	ExternalIDs      map[string]string `ovsdb:"external_ids"`
+	LogicalPort      string            `ovsdb:"logical_port"`
+	MAC              []string          `ovsdb:"mac"`
+	NATAddresses     []string          `ovsdb:"nat_addresses"`
+	Options          map[string]string `ovsdb:"options"`
+	ParentPort       *string           `ovsdb:"parent_port"`
+	RequestedChassis *string           `ovsdb:"requested_chassis"`
+	Tag              *int              `ovsdb:"tag"`
+	TunnelKey        int               `ovsdb:"tunnel_key"`
+	Type             string            `ovsdb:"type"`
+	Up               *bool             `ovsdb:"up"`
+	VirtualParent    *string           `ovsdb:"virtual_parent"`
+}
+
+// Row converts a PortBinding into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *PortBinding) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	if r.Chassis != nil {
+		set(PortBindingColumnChassis, genEncodeUUID(*r.Chassis))
+	} else {
+		set(PortBindingColumnChassis, []interface{}{"set", []interface{}{}})
+	}
+	set(PortBindingColumnDatapath, genEncodeUUID(r.Datapath))
+	if r.Encap != nil {
+		set(PortBindingColumnEncap, genEncodeUUID(*r.Encap))
+	} else {
+		set(PortBindingColumnEncap, []interface{}{"set", []interface{}{}})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(PortBindingColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(PortBindingColumnLogicalPort, genEncodeAtom(r.LogicalPort))
+	{
+		elems := make([]interface{}, 0, len(r.MAC))
+		for _, e := range r.MAC {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(PortBindingColumnMAC, []interface{}{"set", elems})
+	}
+	{
+		elems := make([]interface{}, 0, len(r.NATAddresses))
+		for _, e := range r.NATAddresses {
+			elems = append(elems, genEncodeAtom(e))
+		}
+		set(PortBindingColumnNATAddresses, []interface{}{"set", elems})
+	}
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(PortBindingColumnOptions, []interface{}{"map", pairs})
+	}
+	if r.ParentPort != nil {
+		set(PortBindingColumnParentPort, genEncodeAtom(*r.ParentPort))
+	} else {
+		set(PortBindingColumnParentPort, []interface{}{"set", []interface{}{}})
+	}
+	if r.RequestedChassis != nil {
+		set(PortBindingColumnRequestedChassis, genEncodeUUID(*r.RequestedChassis))
+	} else {
+		set(PortBindingColumnRequestedChassis, []interface{}{"set", []interface{}{}})
+	}
+	if r.Tag != nil {
+		set(PortBindingColumnTag, genEncodeAtom(*r.Tag))
+	} else {
+		set(PortBindingColumnTag, []interface{}{"set", []interface{}{}})
+	}
+	set(PortBindingColumnTunnelKey, genEncodeAtom(r.TunnelKey))
+	set(PortBindingColumnType, genEncodeAtom(r.Type))
+	if r.Up != nil {
+		set(PortBindingColumnUp, genEncodeAtom(*r.Up))
+	} else {
+		set(PortBindingColumnUp, []interface{}{"set", []interface{}{}})
+	}
+	if r.VirtualParent != nil {
+		set(PortBindingColumnVirtualParent, genEncodeAtom(*r.VirtualParent))
+	} else {
+		set(PortBindingColumnVirtualParent, []interface{}{"set", []interface{}{}})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a PortBinding using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *PortBinding) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[PortBindingColumnChassis]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Chassis = nil
+				return nil
+			}
+
+			x, err := genDecodeUUID(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Chassis = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnChassis, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnDatapath]; ok {
+		if err := func() error {
+			x, err := genDecodeUUID(v)
+			if err != nil {
+				return err
+			}
+
+			r.Datapath = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnDatapath, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnEncap]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Encap = nil
+				return nil
+			}
+
+			x, err := genDecodeUUID(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Encap = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnEncap, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnLogicalPort]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.LogicalPort = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnLogicalPort, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnMAC]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.MAC = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnMAC, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnNATAddresses]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			s := make([]string, 0, len(elems))
+			for _, e := range elems {
+				x, err := genDecodeString(e)
+				if err != nil {
+					return err
+				}
+				s = append(s, x)
+			}
+
+			r.NATAddresses = s
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnNATAddresses, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnOptions, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnParentPort]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.ParentPort = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.ParentPort = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnParentPort, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnRequestedChassis]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.RequestedChassis = nil
+				return nil
+			}
+
+			x, err := genDecodeUUID(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.RequestedChassis = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnRequestedChassis, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnTag]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Tag = nil
+				return nil
+			}
+
+			x, err := genDecodeInteger(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Tag = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnTag, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnTunnelKey]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.TunnelKey = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnTunnelKey, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnType]; ok {
+		if err := func() error {
+			x, err := genDecodeString(v)
+			if err != nil {
+				return err
+			}
+
+			r.Type = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnType, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnUp]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.Up = nil
+				return nil
+			}
+
+			x, err := genDecodeBoolean(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.Up = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnUp, err)
+		}
+	}
+
+	if v, ok := row[PortBindingColumnVirtualParent]; ok {
+		if err := func() error {
+			elems, err := genDecodeSet(v)
+			if err != nil {
+				return err
+			}
+
+			if len(elems) == 0 {
+				r.VirtualParent = nil
+				return nil
+			}
+
+			x, err := genDecodeString(elems[0])
+			if err != nil {
+				return err
+			}
+
+			r.VirtualParent = &x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", PortBindingColumnVirtualParent, err)
+		}
+	}
+
+	return nil
+}
+
+// Names of the SB_Global table and its columns.
+const (
+	TableSBGlobal = "SB_Global"
+
+	SBGlobalColumnExternalIDs = "external_ids"
+	SBGlobalColumnIpsec       = "ipsec"
+	SBGlobalColumnNBCfg       = "nb_cfg"
+	SBGlobalColumnOptions     = "options"
+)
+
+// SBGlobal is a row in the SB_Global table.
+type SBGlobal struct {
+	UUID string `ovsdb:"_uuid"`
+
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Ipsec       bool              `ovsdb:"ipsec"`
+	NBCfg       int               `ovsdb:"nb_cfg"`
+	Options     map[string]string `ovsdb:"options"`
+}
+
+// Row converts a SBGlobal into an ovsdb.Row.  If one or more columns are
+// specified, only those columns are included in the ovsdb.Row.  The UUID
+// field is never included.
+func (r *SBGlobal) Row(columns ...string) ovsdb.Row {
+	row := make(ovsdb.Row)
+	set := func(column string, v interface{}) {
+		if genWantColumn(columns, column) {
+			row[column] = v
+		}
+	}
+
+	{
+		pairs := make([]interface{}, 0, len(r.ExternalIDs))
+		for k, v := range r.ExternalIDs {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(SBGlobalColumnExternalIDs, []interface{}{"map", pairs})
+	}
+	set(SBGlobalColumnIpsec, genEncodeAtom(r.Ipsec))
+	set(SBGlobalColumnNBCfg, genEncodeAtom(r.NBCfg))
+	{
+		pairs := make([]interface{}, 0, len(r.Options))
+		for k, v := range r.Options {
+			pairs = append(pairs, []interface{}{genEncodeAtom(k), genEncodeAtom(v)})
+		}
+		set(SBGlobalColumnOptions, []interface{}{"map", pairs})
+	}
+
+	return row
+}
+
+// UnmarshalRow populates a SBGlobal using the columns of an ovsdb.Row.
+// Columns which are not present in the ovsdb.Row are left unchanged.
+func (r *SBGlobal) UnmarshalRow(row ovsdb.Row) error {
+	if v, ok := row["_uuid"]; ok {
+		u, err := genDecodeUUID(v)
+		if err != nil {
+			return fmt.Errorf("column %q: %v", "_uuid", err)
+		}
+		r.UUID = u
+	}
+
+	if v, ok := row[SBGlobalColumnExternalIDs]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.ExternalIDs = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", SBGlobalColumnExternalIDs, err)
+		}
+	}
+
+	if v, ok := row[SBGlobalColumnIpsec]; ok {
+		if err := func() error {
+			x, err := genDecodeBoolean(v)
+			if err != nil {
+				return err
+			}
+
+			r.Ipsec = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", SBGlobalColumnIpsec, err)
+		}
+	}
+
+	if v, ok := row[SBGlobalColumnNBCfg]; ok {
+		if err := func() error {
+			x, err := genDecodeInteger(v)
+			if err != nil {
+				return err
+			}
+
+			r.NBCfg = x
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", SBGlobalColumnNBCfg, err)
+		}
+	}
+
+	if v, ok := row[SBGlobalColumnOptions]; ok {
+		if err := func() error {
+			pairs, err := genDecodeMap(v)
+			if err != nil {
+				return err
+			}
+
+			m := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				k, err := genDecodeString(p[0])
+				if err != nil {
+					return err
+				}
+				v, err := genDecodeString(p[1])
+				if err != nil {
+					return err
+				}
+				m[k] = v
+			}
+
+			r.Options = m
+			return nil
+		}(); err != nil {
+			return fmt.Errorf("column %q: %v", SBGlobalColumnOptions, err)
+		}
+	}
+
+	return nil
+}
+
+// genWantColumn reports whether column appears in columns, or columns is
+// empty.
+func genWantColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// genEncodeAtom encodes an atom which requires no special encoding.
+func genEncodeAtom(v interface{}) interface{} {
+	return v
+}
+
+// genEncodeUUID encodes a UUID atom.  If s is not a UUID, it is assumed to be
+// the UUID name of a row inserted earlier in the same transaction.
+func genEncodeUUID(s string) interface{} {
+	if !genIsUUID(s) {
+		return ovsdb.NamedUUID(s)
+	}
+
+	return []interface{}{"uuid", s}
+}
+
+// genIsUUID reports whether s is in the canonical UUID format.
+func genIsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// genDecodeSet decodes a set, which may be encoded as a single atom.
+func genDecodeSet(v interface{}) ([]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "set" {
+		// A set with one element may be encoded as the element itself.
+		return []interface{}{v}, nil
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set: %v", v)
+	}
+
+	return elems, nil
+}
+
+// genDecodeMap decodes a map into key/value pairs.
+func genDecodeMap(v interface{}) ([][2]interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "map" {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	pairs := make([][2]interface{}, 0, len(elems))
+	for _, e := range elems {
+		p, ok := e.([]interface{})
+		if !ok || len(p) != 2 {
+			return nil, fmt.Errorf("invalid map pair: %v", e)
+		}
+
+		pairs = append(pairs, [2]interface{}{p[0], p[1]})
+	}
+
+	return pairs, nil
+}
+
+// genDecodeInteger decodes an integer atom.
+func genDecodeInteger(v interface{}) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case int64:
+		return int(x), nil
+	case float64:
+		if x != float64(int(x)) {
+			return 0, fmt.Errorf("invalid integer: %v", v)
+		}
+
+		return int(x), nil
+	case json.Number:
+		n, err := x.Int64()
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("invalid integer: %v", v)
+	}
+}
+
+// genDecodeReal decodes a real atom.
+func genDecodeReal(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return x.Float64()
+	default:
+		return 0, fmt.Errorf("invalid real: %v", v)
+	}
+}
+
+// genDecodeBoolean decodes a boolean atom.
+func genDecodeBoolean(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid boolean: %v", v)
+	}
+
+	return b, nil
+}
+
+// genDecodeString decodes a string atom.
+func genDecodeString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid string: %v", v)
+	}
+
+	return s, nil
+}
+
+// genDecodeUUID decodes a UUID atom.
+func genDecodeUUID(v interface{}) (string, error) {
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "uuid" {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	s, ok := a[1].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	return s, nil
+}
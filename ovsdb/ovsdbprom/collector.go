@@ -0,0 +1,227 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbprom
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "ovsdb"
+	subsystem = "client"
+)
+
+var _ prometheus.Collector = &Collector{}
+
+// A Collector is a prometheus.Collector which exposes metrics about an
+// ovsdb.Client, and the ovsdb.Caches added with AddCache.  Collectors are
+// created using NewCollector.
+//
+// RPC and monitor notification metrics are recorded using the ClientHooks
+// returned by Collector.Hooks, which must be passed to the ovsdb.Hooks
+// option when the Client is created.  Other metrics are gathered from the
+// Client set with Collector.SetClient each time the Collector is collected.
+type Collector struct {
+	rpcs    *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+
+	// The time of the last update notification, in Unix nanoseconds.
+	// Accessed atomically.
+	lastUpdate int64
+
+	mu     sync.Mutex
+	client *ovsdb.Client
+	caches map[string]*ovsdb.Cache
+
+	connected    *prometheus.Desc
+	reconnects   *prometheus.Desc
+	pending      *prometheus.Desc
+	monitors     *prometheus.Desc
+	queued       *prometheus.Desc
+	updated      *prometheus.Desc
+	echoFailures *prometheus.Desc
+	cacheRows    *prometheus.Desc
+}
+
+// NewCollector creates a Collector.
+func NewCollector() *Collector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, name),
+			help, labels, nil,
+		)
+	}
+
+	return &Collector{
+		rpcs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rpcs_total",
+			Help:      "The number of RPCs completed by the client, by method and result.",
+		}, []string{"method", "result"}),
+
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_duration_seconds",
+			Help:      "The time taken for RPCs to complete, by method.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+		}, []string{"method"}),
+
+		caches: make(map[string]*ovsdb.Cache),
+
+		connected: desc("connected",
+			"Whether the client is connected to an OVSDB server."),
+
+		reconnects: desc("reconnects_total",
+			"The number of times the client established a new connection after its connection was lost."),
+
+		pending: desc("pending_rpcs",
+			"The number of RPCs waiting for a response from the server."),
+
+		monitors: desc("monitors",
+			"The number of monitors receiving update notifications."),
+
+		queued: desc("monitor_queued_updates",
+			"The number of update notifications waiting to be delivered to the consumers of all monitors."),
+
+		updated: desc("monitor_last_update_timestamp_seconds",
+			"The UNIX timestamp of the last update notification received for any monitor."),
+
+		echoFailures: desc("echo_failures_total",
+			"The number of failed echo RPCs sent by the client to check the health of its connection."),
+
+		cacheRows: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "rows"),
+			"The number of rows in each table of a cache.",
+			[]string{"cache", "table"}, nil,
+		),
+	}
+}
+
+// Hooks returns ClientHooks which record metrics for the Collector.  The
+// hooks must be passed to the ovsdb.Hooks option when the Client is created.
+func (c *Collector) Hooks() ovsdb.ClientHooks {
+	return ovsdb.ClientHooks{
+		RPC: func(method string, latency time.Duration, err error) {
+			result := "success"
+			if err != nil {
+				result = "failure"
+			}
+
+			c.rpcs.WithLabelValues(method, result).Inc()
+			c.latency.WithLabelValues(method).Observe(latency.Seconds())
+		},
+		Update: func(_ string, _ int) {
+			atomic.StoreInt64(&c.lastUpdate, time.Now().UnixNano())
+		},
+	}
+}
+
+// SetClient sets the Client whose statistics are reported by the Collector.
+func (c *Collector) SetClient(client *ovsdb.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.client = client
+}
+
+// AddCache adds a Cache whose row counts are reported by the Collector,
+// using name as the value of the cache label.  A Cache added with an
+// existing name replaces the previous Cache.
+func (c *Collector) AddCache(name string, cache *ovsdb.Cache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.caches[name] = cache
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.rpcs.Describe(ch)
+	c.latency.Describe(ch)
+
+	ds := []*prometheus.Desc{
+		c.connected,
+		c.reconnects,
+		c.pending,
+		c.monitors,
+		c.queued,
+		c.updated,
+		c.echoFailures,
+		c.cacheRows,
+	}
+
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.rpcs.Collect(ch)
+	c.latency.Collect(ch)
+
+	c.mu.Lock()
+	client := c.client
+	caches := make(map[string]*ovsdb.Cache, len(c.caches))
+	for name, cache := range c.caches {
+		caches[name] = cache
+	}
+	c.mu.Unlock()
+
+	if client != nil {
+		c.collectClient(ch, client)
+	}
+
+	for name, cache := range caches {
+		for table, n := range cache.RowCounts() {
+			ch <- prometheus.MustNewConstMetric(
+				c.cacheRows,
+				prometheus.GaugeValue,
+				float64(n),
+				name, table,
+			)
+		}
+	}
+}
+
+// collectClient collects the statistics of client.
+func (c *Collector) collectClient(ch chan<- prometheus.Metric, client *ovsdb.Client) {
+	var connected float64
+	if client.State() == ovsdb.StateConnected {
+		connected = 1
+	}
+
+	s := client.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.connected, prometheus.GaugeValue, connected)
+	ch <- prometheus.MustNewConstMetric(c.reconnects, prometheus.CounterValue, float64(s.Reconnects.Total))
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(s.Callbacks.Current))
+	ch <- prometheus.MustNewConstMetric(c.monitors, prometheus.GaugeValue, float64(s.Monitors.Current))
+	ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(s.Monitors.Queued))
+	ch <- prometheus.MustNewConstMetric(c.echoFailures, prometheus.CounterValue, float64(s.EchoLoop.Failure))
+
+	// Only report the time of the last update once one has arrived.
+	if last := atomic.LoadInt64(&c.lastUpdate); last != 0 {
+		ch <- prometheus.MustNewConstMetric(c.updated, prometheus.GaugeValue,
+			float64(last)/float64(time.Second))
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbprom_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbprom"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbserver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const schema = `{
+	"name": "Open_vSwitch",
+	"version": "1.0.0",
+	"tables": {
+		"Bridge": {
+			"columns": {
+				"name": {"type": "string"}
+			},
+			"isRoot": true
+		}
+	}
+}`
+
+func TestCollector(t *testing.T) {
+	col := ovsdbprom.NewCollector()
+
+	c, done := testClient(t, ovsdb.Hooks(col.Hooks()))
+	defer done()
+
+	col.SetClient(c)
+
+	ctx := context.Background()
+
+	cache, err := c.MonitorCache(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	defer cache.Cancel(ctx)
+
+	col.AddCache("bridges", cache)
+
+	if _, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br0"}},
+	}); err != nil {
+		t.Fatalf("failed to insert bridge: %v", err)
+	}
+
+	// A transaction on an unknown database fails.
+	if _, err := c.Transact(ctx, "foo", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+	}); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	// Wait for the cache to observe the insertion.
+	timeout := time.After(2 * time.Second)
+	for len(cache.Rows("Bridge")) == 0 {
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for cache update")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	want := map[string]float64{
+		`ovsdb_cache_rows{cache="bridges",table="Bridge"}`:                1,
+		`ovsdb_client_connected`:                                          1,
+		`ovsdb_client_echo_failures_total`:                                0,
+		`ovsdb_client_monitor_queued_updates`:                             0,
+		`ovsdb_client_monitors`:                                           1,
+		`ovsdb_client_pending_rpcs`:                                       0,
+		`ovsdb_client_reconnects_total`:                                   0,
+		`ovsdb_client_rpc_duration_seconds{method="get_schema"}`:          1,
+		`ovsdb_client_rpc_duration_seconds{method="monitor_cond"}`:        1,
+		`ovsdb_client_rpc_duration_seconds{method="transact"}`:            2,
+		`ovsdb_client_rpcs_total{method="get_schema",result="success"}`:   1,
+		`ovsdb_client_rpcs_total{method="monitor_cond",result="success"}`: 1,
+		`ovsdb_client_rpcs_total{method="transact",result="failure"}`:     1,
+		`ovsdb_client_rpcs_total{method="transact",result="success"}`:     1,
+		`ovsdb_client_monitor_last_update_timestamp_seconds`:              -1,
+	}
+
+	got := gather(t, col)
+
+	// The timestamp of the last update varies, so only check its presence.
+	if v, ok := got["ovsdb_client_monitor_last_update_timestamp_seconds"]; ok && v > 0 {
+		got["ovsdb_client_monitor_last_update_timestamp_seconds"] = -1
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected metrics (-want +got):\n%s", diff)
+	}
+}
+
+func TestCollectorNoClient(t *testing.T) {
+	col := ovsdbprom.NewCollector()
+
+	// Only metrics recorded by hooks are reported, and none have been yet.
+	if diff := cmp.Diff(map[string]float64{}, gather(t, col)); diff != "" {
+		t.Fatalf("unexpected metrics (-want +got):\n%s", diff)
+	}
+}
+
+// gather registers col and gathers its metrics, keyed by name and labels.
+// Histograms are reported using their sample counts.
+func gather(t *testing.T, col prometheus.Collector) map[string]float64 {
+	t.Helper()
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(col); err != nil {
+		t.Fatalf("failed to register collector: %v", err)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	out := make(map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+`="`+l.GetValue()+`"`)
+			}
+			sort.Strings(labels)
+
+			name := mf.GetName()
+			if len(labels) > 0 {
+				name += "{" + strings.Join(labels, ",") + "}"
+			}
+
+			switch {
+			case m.Counter != nil:
+				out[name] = m.GetCounter().GetValue()
+			case m.Gauge != nil:
+				out[name] = m.GetGauge().GetValue()
+			case m.Histogram != nil:
+				out[name] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	return out
+}
+
+// testClient creates a Client connected to an ovsdbserver.Server which
+// serves schema.
+func testClient(t *testing.T, options ...ovsdb.OptionFunc) (*ovsdb.Client, func()) {
+	t.Helper()
+
+	var sc ovsdb.Schema
+	if err := json.Unmarshal([]byte(schema), &sc); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	s, err := ovsdbserver.New(&sc)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	c, err := ovsdb.Dial("tcp", l.Addr().String(), options...)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	return c, func() {
+		_ = c.Close()
+		_ = s.Close()
+	}
+}
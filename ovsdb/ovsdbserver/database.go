@@ -0,0 +1,588 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// Kinds of errors reported to clients, as described in RFC 7047.
+const (
+	errAborted         = "aborted"
+	errConstraint      = "constraint violation"
+	errDuplicateName   = "duplicate uuid-name"
+	errDomain          = "domain error"
+	errNotSupported    = "not supported"
+	errRange           = "range error"
+	errReferential     = "referential integrity violation"
+	errSyntax          = "syntax error"
+	errTimedOut        = "timed out"
+	errUnknownColumn   = "unknown column"
+	errUnknownDatabase = "unknown database"
+	errUnknownTable    = "unknown table"
+)
+
+// errorf creates an *ovsdb.Error of the specified kind.
+func errorf(kind, format string, v ...interface{}) *ovsdb.Error {
+	return &ovsdb.Error{
+		Err:     kind,
+		Details: fmt.Sprintf(format, v...),
+	}
+}
+
+// uuidColumn is the schema of the _uuid and _version columns.
+var uuidColumn = ovsdb.ColumnSchema{
+	Type: ovsdb.ColumnType{
+		Key: ovsdb.BaseType{Type: ovsdb.TypeUUID},
+		Min: 1,
+		Max: 1,
+	},
+}
+
+// A database is the contents of a single database.  Rows are never modified
+// once stored in a database, so a database can be copied cheaply before
+// applying a transaction.
+type database struct {
+	schema *ovsdb.Schema
+
+	// Rows keyed by table name and row UUID.  Each row contains every
+	// column in its table's schema, along with _uuid and _version.
+	//
+	// Scalar column values are atoms, map column values are ovsdb.Maps,
+	// and all other column values are ovsdb.Sets.  Atoms are strings,
+	// int64s for integers, float64s for reals, bools, or ovsdb.UUIDs.
+	tables map[string]map[string]ovsdb.Row
+
+	// Tables whose rows are exempt from garbage collection.
+	root map[string]bool
+
+	// The ID of the last committed transaction.
+	txnID string
+}
+
+// newDatabase creates an empty database using schema.
+func newDatabase(schema *ovsdb.Schema) (*database, error) {
+	if schema.Name == "" {
+		return nil, fmt.Errorf("schema has no name")
+	}
+
+	d := &database{
+		schema: schema,
+		tables: make(map[string]map[string]ovsdb.Row, len(schema.Tables)),
+		root:   make(map[string]bool, len(schema.Tables)),
+		txnID:  string(newUUID()),
+	}
+
+	var anyRoot bool
+	for name, ts := range schema.Tables {
+		for column, cs := range ts.Columns {
+			if err := checkColumnType(schema, cs.Type); err != nil {
+				return nil, fmt.Errorf("invalid column %s in table %s: %v", column, name, err)
+			}
+		}
+
+		d.tables[name] = make(map[string]ovsdb.Row)
+		d.root[name] = ts.IsRoot
+		anyRoot = anyRoot || ts.IsRoot
+	}
+
+	// As with ovsdb-server, garbage collection is disabled if no table is a
+	// root table.
+	if !anyRoot {
+		for name := range d.root {
+			d.root[name] = true
+		}
+	}
+
+	return d, nil
+}
+
+// checkColumnType verifies that a column type is valid in schema.
+func checkColumnType(schema *ovsdb.Schema, typ ovsdb.ColumnType) error {
+	types := []ovsdb.BaseType{typ.Key}
+	if typ.Value != nil {
+		types = append(types, *typ.Value)
+	}
+
+	for _, bt := range types {
+		switch bt.Type {
+		case ovsdb.TypeInteger, ovsdb.TypeReal, ovsdb.TypeBoolean, ovsdb.TypeString, ovsdb.TypeUUID:
+		default:
+			return fmt.Errorf("unknown atomic type %q", bt.Type)
+		}
+
+		if bt.RefTable == "" {
+			continue
+		}
+
+		if _, ok := schema.Tables[bt.RefTable]; !ok {
+			return fmt.Errorf("reference to unknown table %q", bt.RefTable)
+		}
+	}
+
+	return nil
+}
+
+// clone returns a copy of the database which can be modified independently.
+func (d *database) clone() *database {
+	out := *d
+	out.tables = make(map[string]map[string]ovsdb.Row, len(d.tables))
+	for name, rows := range d.tables {
+		t := make(map[string]ovsdb.Row, len(rows))
+		for uuid, row := range rows {
+			t[uuid] = row
+		}
+
+		out.tables[name] = t
+	}
+
+	return &out
+}
+
+// table returns the schema of a table.
+func (d *database) table(name string) (ovsdb.TableSchema, error) {
+	ts, ok := d.schema.Tables[name]
+	if !ok {
+		return ovsdb.TableSchema{}, errorf(errUnknownTable, "no table named %s", name)
+	}
+
+	return ts, nil
+}
+
+// column returns the schema of a column in a table, including the _uuid and
+// _version columns present in every table.
+func (d *database) column(table, column string) (ovsdb.ColumnSchema, error) {
+	ts, err := d.table(table)
+	if err != nil {
+		return ovsdb.ColumnSchema{}, err
+	}
+
+	if column == "_uuid" || column == "_version" {
+		return uuidColumn, nil
+	}
+
+	cs, ok := ts.Columns[column]
+	if !ok {
+		return ovsdb.ColumnSchema{}, errorf(errUnknownColumn, "%s table does not have a %s column", table, column)
+	}
+
+	return cs, nil
+}
+
+// newUUID generates a random, version 4 UUID.
+func newUUID() ovsdb.UUID {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panicf("ovsdbserver: failed to generate UUID: %v", err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return ovsdb.UUID(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]))
+}
+
+// defaultValue returns the default value of a column with type typ.
+func defaultValue(typ ovsdb.ColumnType) interface{} {
+	switch {
+	case typ.IsMap():
+		return ovsdb.Map{}
+	case typ.Min == 1 && typ.Max == 1:
+		switch typ.Key.Type {
+		case ovsdb.TypeInteger:
+			return int64(0)
+		case ovsdb.TypeReal:
+			return float64(0)
+		case ovsdb.TypeBoolean:
+			return false
+		case ovsdb.TypeString:
+			return ""
+		default:
+			return ovsdb.UUID("00000000-0000-0000-0000-000000000000")
+		}
+	default:
+		return ovsdb.Set{}
+	}
+}
+
+// parseValue parses a column value decoded from JSON according to the
+// column's type, replacing named UUIDs with the UUIDs in names.  The number
+// of elements in the value is checked separately by checkSize.
+func parseValue(typ ovsdb.ColumnType, v interface{}, names map[string]ovsdb.UUID) (interface{}, error) {
+	switch {
+	case typ.IsMap():
+		m, err := ovsdb.ParseMap(v)
+		if err != nil {
+			return nil, errorf(errSyntax, "%v", err)
+		}
+
+		out := make(ovsdb.Map, len(m))
+		for k, e := range m {
+			key, err := parseAtom(typ.Key, k, names)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := parseAtom(*typ.Value, e, names)
+			if err != nil {
+				return nil, err
+			}
+
+			out[key] = value
+		}
+
+		return out, nil
+	case typ.Min == 1 && typ.Max == 1:
+		s, err := ovsdb.ParseSet(v)
+		if err != nil {
+			return nil, errorf(errSyntax, "%v", err)
+		}
+		if len(s) != 1 {
+			return nil, errorf(errConstraint, "%d values when type requires exactly 1", len(s))
+		}
+
+		return parseAtom(typ.Key, s[0], names)
+	default:
+		s, err := ovsdb.ParseSet(v)
+		if err != nil {
+			return nil, errorf(errSyntax, "%v", err)
+		}
+
+		out := make(ovsdb.Set, 0, len(s))
+		seen := make(map[interface{}]bool, len(s))
+		for _, e := range s {
+			atom, err := parseAtom(typ.Key, e, names)
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[atom] {
+				continue
+			}
+
+			seen[atom] = true
+			out = append(out, atom)
+		}
+
+		return out, nil
+	}
+}
+
+// parseAtom parses and validates an atom of type bt.
+func parseAtom(bt ovsdb.BaseType, v interface{}, names map[string]ovsdb.UUID) (interface{}, error) {
+	if n, ok := v.(ovsdb.NamedUUID); ok {
+		uuid, ok := names[string(n)]
+		if !ok {
+			return nil, errorf(errSyntax, "unknown named-uuid %q", string(n))
+		}
+
+		v = uuid
+	}
+
+	bad := errorf(errSyntax, "expected %s, got %v", bt.Type, v)
+
+	switch bt.Type {
+	case ovsdb.TypeInteger:
+		i, ok := integerOf(v)
+		if !ok {
+			return nil, bad
+		}
+		v = i
+
+		if (bt.MinInteger != nil && i < *bt.MinInteger) ||
+			(bt.MaxInteger != nil && i > *bt.MaxInteger) {
+			return nil, errorf(errConstraint, "integer %v is out of range", v)
+		}
+	case ovsdb.TypeReal:
+		f, ok := realOf(v)
+		if !ok {
+			return nil, bad
+		}
+		v = f
+
+		if (bt.MinReal != nil && f < *bt.MinReal) ||
+			(bt.MaxReal != nil && f > *bt.MaxReal) {
+			return nil, errorf(errConstraint, "real %v is out of range", v)
+		}
+	case ovsdb.TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return nil, bad
+		}
+	case ovsdb.TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, bad
+		}
+
+		n := utf8.RuneCountInString(s)
+		if (bt.MinLength != nil && n < *bt.MinLength) ||
+			(bt.MaxLength != nil && n > *bt.MaxLength) {
+			return nil, errorf(errConstraint, "length of string %q is out of range", s)
+		}
+	case ovsdb.TypeUUID:
+		if _, ok := v.(ovsdb.UUID); !ok {
+			return nil, bad
+		}
+	}
+
+	if len(bt.Enum) == 0 {
+		return v, nil
+	}
+
+	for _, e := range bt.Enum {
+		if e == v {
+			return v, nil
+		}
+	}
+
+	return nil, errorf(errConstraint, "%v is not one of the allowed values", v)
+}
+
+// integerOf returns the value of an integer atom decoded from JSON, which may
+// be a json.Number, an int64, or a float64 without a fractional part.
+func integerOf(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int64:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// realOf returns the value of a real atom decoded from JSON, which may be a
+// json.Number, an int64, or a float64.
+func realOf(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// checkSize verifies that the number of elements in a value is permitted by
+// its column's type.
+func checkSize(typ ovsdb.ColumnType, v interface{}) error {
+	var n int
+	switch v := v.(type) {
+	case ovsdb.Set:
+		n = len(v)
+	case ovsdb.Map:
+		n = len(v)
+	default:
+		return nil
+	}
+
+	if n < typ.Min || (typ.Max != ovsdb.Unlimited && n > typ.Max) {
+		return errorf(errConstraint, "%d values when type requires between %d and %d", n, typ.Min, typ.Max)
+	}
+
+	return nil
+}
+
+// equalValues reports whether two column values are equal.
+func equalValues(a, b interface{}) bool {
+	switch a := a.(type) {
+	case ovsdb.Set:
+		b, ok := b.(ovsdb.Set)
+		return ok && len(a) == len(b) && includesSet(a, b)
+	case ovsdb.Map:
+		b, ok := b.(ovsdb.Map)
+		return ok && len(a) == len(b) && includesMap(a, b)
+	default:
+		return a == b
+	}
+}
+
+// includesSet reports whether every element of sub is in s.
+func includesSet(s, sub ovsdb.Set) bool {
+	in := make(map[interface{}]bool, len(s))
+	for _, e := range s {
+		in[e] = true
+	}
+
+	for _, e := range sub {
+		if !in[e] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// includesMap reports whether every pair of sub is in m.
+func includesMap(m, sub ovsdb.Map) bool {
+	for k, v := range sub {
+		if e, ok := m[k]; !ok || e != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// valueKey returns a string which uniquely identifies a column value, for
+// use in detecting duplicate values in an index.
+func valueKey(v interface{}) string {
+	var elems []string
+	switch v := v.(type) {
+	case ovsdb.Set:
+		for _, e := range v {
+			elems = append(elems, fmt.Sprintf("%T:%v", e, e))
+		}
+	case ovsdb.Map:
+		for k, e := range v {
+			elems = append(elems, fmt.Sprintf("%T:%v=%T:%v", k, k, e, e))
+		}
+	default:
+		return fmt.Sprintf("%T:%v", v, v)
+	}
+
+	sort.Strings(elems)
+	return fmt.Sprint(elems)
+}
+
+// equalRows reports whether two rows contain the same values, ignoring
+// _version.
+func equalRows(a, b ovsdb.Row) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for column, v := range a {
+		if column == "_version" {
+			continue
+		}
+
+		if !equalValues(v, b[column]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyRow returns a copy of row which can be modified.
+func copyRow(row ovsdb.Row) ovsdb.Row {
+	out := make(ovsdb.Row, len(row))
+	for column, v := range row {
+		out[column] = v
+	}
+
+	return out
+}
+
+// A ref is a reference from a column value to a row.
+type ref struct {
+	table  string
+	uuid   ovsdb.UUID
+	strong bool
+}
+
+// refs returns the references to other rows in a column value.
+func refs(typ ovsdb.ColumnType, v interface{}) []ref {
+	var out []ref
+	add := func(bt ovsdb.BaseType, atom interface{}) {
+		uuid, ok := atom.(ovsdb.UUID)
+		if !ok || bt.RefTable == "" {
+			return
+		}
+
+		out = append(out, ref{
+			table:  bt.RefTable,
+			uuid:   uuid,
+			strong: bt.RefType != "weak",
+		})
+	}
+
+	switch v := v.(type) {
+	case ovsdb.Set:
+		for _, e := range v {
+			add(typ.Key, e)
+		}
+	case ovsdb.Map:
+		for k, e := range v {
+			add(typ.Key, k)
+			add(*typ.Value, e)
+		}
+	default:
+		add(typ.Key, v)
+	}
+
+	return out
+}
+
+// removeWeak removes the weak references to rows for which exists returns
+// false from a set or map column value.  It reports whether any references
+// were removed.
+func removeWeak(typ ovsdb.ColumnType, v interface{}, exists func(table string, uuid ovsdb.UUID) bool) (interface{}, bool) {
+	dangling := func(bt *ovsdb.BaseType, atom interface{}) bool {
+		if bt == nil || bt.RefTable == "" || bt.RefType != "weak" {
+			return false
+		}
+
+		uuid, ok := atom.(ovsdb.UUID)
+		return ok && !exists(bt.RefTable, uuid)
+	}
+
+	switch v := v.(type) {
+	case ovsdb.Set:
+		out := make(ovsdb.Set, 0, len(v))
+		for _, e := range v {
+			if !dangling(&typ.Key, e) {
+				out = append(out, e)
+			}
+		}
+
+		return out, len(out) != len(v)
+	case ovsdb.Map:
+		out := make(ovsdb.Map, len(v))
+		for k, e := range v {
+			if !dangling(&typ.Key, k) && !dangling(typ.Value, e) {
+				out[k] = e
+			}
+		}
+
+		return out, len(out) != len(v)
+	default:
+		return v, false
+	}
+}
+
+// panicf is a helper for panic(fmt.Sprintf(...)).
+func panicf(format string, a ...interface{}) {
+	panic(fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ovsdbserver implements a minimal, in-memory OVSDB server, as
+// described in RFC 7047.
+//
+// A Server stores the contents of one or more databases in memory, and
+// supports the transact, monitor, monitor_cond, monitor_cond_since, and
+// monitor_cond_change RPCs used by package ovsdb.  It is intended for
+// integration tests and lightweight tooling which cannot depend on the
+// ovsdb-server binary, and does not implement clustering, locks, or
+// persistent storage.
+package ovsdbserver
@@ -0,0 +1,447 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// A monitor is a monitor created by a client using the monitor,
+// monitor_cond, or monitor_cond_since RPCs.
+type monitor struct {
+	id     json.RawMessage
+	db     string
+	method string
+	tables map[string]*monitorTable
+}
+
+// A monitorTable specifies the columns, rows, and types of changes monitored
+// in a single table.
+type monitorTable struct {
+	columns []string
+	initial bool
+	insert  bool
+	delete  bool
+	modify  bool
+
+	// A row is monitored if it matches every condition in any element of
+	// where.  If where is nil, all rows are monitored.
+	where [][]condFunc
+}
+
+// A monitorRequest is a monitor request for a table, as described in RFC
+// 7047, section 4.1.5, with the extensions used by monitor_cond.
+type monitorRequest struct {
+	Columns []string      `json:"columns"`
+	Where   []interface{} `json:"where"`
+	Select  *struct {
+		Initial *bool `json:"initial"`
+		Insert  *bool `json:"insert"`
+		Delete  *bool `json:"delete"`
+		Modify  *bool `json:"modify"`
+	} `json:"select"`
+}
+
+// newMonitor creates a monitor from the monitor requests of a monitor RPC.
+func (d *database) newMonitor(method string, id, params json.RawMessage) (*monitor, error) {
+	var requests map[string]json.RawMessage
+	if err := json.Unmarshal(params, &requests); err != nil {
+		return nil, errorf(errSyntax, "invalid monitor requests: %v", err)
+	}
+
+	m := &monitor{
+		id:     id,
+		db:     d.schema.Name,
+		method: method,
+		tables: make(map[string]*monitorTable, len(requests)),
+	}
+
+	for table, b := range requests {
+		ts, err := d.table(table)
+		if err != nil {
+			return nil, err
+		}
+
+		reqs, err := parseMonitorRequests(table, b)
+		if err != nil {
+			return nil, err
+		}
+
+		mt, err := d.monitorTable(table, ts, method, reqs)
+		if err != nil {
+			return nil, err
+		}
+
+		m.tables[table] = mt
+	}
+
+	return m, nil
+}
+
+// parseMonitorRequests parses the monitor requests for a table, which may be
+// a single request or an array of requests.
+func parseMonitorRequests(table string, b json.RawMessage) ([]monitorRequest, error) {
+	var (
+		reqs []monitorRequest
+		err  error
+	)
+
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '[' {
+		err = json.Unmarshal(b, &reqs)
+	} else {
+		var req monitorRequest
+		err = json.Unmarshal(b, &req)
+		reqs = append(reqs, req)
+	}
+	if err != nil {
+		return nil, errorf(errSyntax, "invalid monitor request for table %s: %v", table, err)
+	}
+
+	return reqs, nil
+}
+
+// changeConditions replaces the conditions of the tables of m with those in
+// the requests of a monitor_cond_change RPC, and returns updates which insert
+// the rows of d which begin to match and delete those which no longer match.
+// m is unchanged if an error is returned.
+func (d *database) changeConditions(m *monitor, params json.RawMessage) (map[string]map[string]interface{}, error) {
+	var requests map[string]json.RawMessage
+	if err := json.Unmarshal(params, &requests); err != nil {
+		return nil, errorf(errSyntax, "invalid monitor requests: %v", err)
+	}
+
+	wheres := make(map[string][][]condFunc, len(requests))
+	for table, b := range requests {
+		if _, ok := m.tables[table]; !ok {
+			return nil, errorf(errSyntax, "table %s is not monitored", table)
+		}
+
+		reqs, err := parseMonitorRequests(table, b)
+		if err != nil {
+			return nil, err
+		}
+
+		// As with monitor_cond, a request without conditions monitors every
+		// row.
+		var where [][]condFunc
+		for _, req := range reqs {
+			if len(req.Where) == 0 {
+				where = nil
+				break
+			}
+
+			conds, err := d.conds(table, req.Where, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			where = append(where, conds)
+		}
+
+		wheres[table] = where
+	}
+
+	var updates map[string]map[string]interface{}
+	for table, where := range wheres {
+		mt := m.tables[table]
+		next := *mt
+		next.where = where
+
+		for uuid, row := range d.tables[table] {
+			var u interface{}
+			switch oldMatch, newMatch := mt.matches(row), next.matches(row); {
+			case !oldMatch && newMatch:
+				u = ovsdb.RowUpdate2{Insert: project(row, mt.columns)}
+			case oldMatch && !newMatch:
+				u = ovsdb.RowUpdate2{Delete: true}
+			default:
+				continue
+			}
+
+			if updates == nil {
+				updates = make(map[string]map[string]interface{})
+			}
+			add(updates, table, uuid, u)
+		}
+
+		mt.where = where
+	}
+
+	return updates, nil
+}
+
+// monitorTable combines the requests for a table into a monitorTable.
+func (d *database) monitorTable(table string, ts ovsdb.TableSchema, method string, reqs []monitorRequest) (*monitorTable, error) {
+	mt := &monitorTable{}
+
+	columns := make(map[string]bool)
+	var all, everyRow bool
+	for _, req := range reqs {
+		if len(req.Columns) == 0 {
+			all = true
+		}
+
+		for _, column := range req.Columns {
+			if _, err := d.column(table, column); err != nil {
+				return nil, err
+			}
+
+			columns[column] = true
+		}
+
+		// Selections default to true.
+		sel := func(b *bool) bool { return b == nil || *b }
+		if s := req.Select; s != nil {
+			mt.initial = mt.initial || sel(s.Initial)
+			mt.insert = mt.insert || sel(s.Insert)
+			mt.delete = mt.delete || sel(s.Delete)
+			mt.modify = mt.modify || sel(s.Modify)
+		} else {
+			mt.initial, mt.insert, mt.delete, mt.modify = true, true, true, true
+		}
+
+		// Conditions are only supported by monitor_cond and later.
+		if method == "monitor" || len(req.Where) == 0 {
+			everyRow = true
+			continue
+		}
+
+		conds, err := d.conds(table, req.Where, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		mt.where = append(mt.where, conds)
+	}
+
+	if all {
+		for column := range ts.Columns {
+			columns[column] = true
+		}
+	}
+
+	for column := range columns {
+		mt.columns = append(mt.columns, column)
+	}
+	sort.Strings(mt.columns)
+
+	if everyRow {
+		mt.where = nil
+	}
+
+	return mt, nil
+}
+
+// matches reports whether a row is monitored.
+func (mt *monitorTable) matches(row ovsdb.Row) bool {
+	if row == nil {
+		return false
+	}
+
+	if mt.where == nil {
+		return true
+	}
+
+	for _, conds := range mt.where {
+		if matchAll(conds, row) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// initial returns the initial contents of the monitored tables of d.
+func (m *monitor) initial(d *database) interface{} {
+	updates := make(map[string]map[string]interface{})
+	for table, mt := range m.tables {
+		if !mt.initial {
+			continue
+		}
+
+		for uuid, row := range d.tables[table] {
+			if !mt.matches(row) {
+				continue
+			}
+
+			row = project(row, mt.columns)
+			if m.method == "monitor" {
+				add(updates, table, uuid, ovsdb.RowUpdate{New: row})
+			} else {
+				add(updates, table, uuid, ovsdb.RowUpdate2{Initial: row})
+			}
+		}
+	}
+
+	return updates
+}
+
+// notification returns the notification which delivers updates for d to the
+// monitor's client.
+func (m *monitor) notification(d *database, updates map[string]map[string]interface{}) notification {
+	switch m.method {
+	case "monitor":
+		return notification{Method: "update", Params: []interface{}{m.id, updates}}
+	case "monitor_cond":
+		return notification{Method: "update2", Params: []interface{}{m.id, updates}}
+	default:
+		return notification{Method: "update3", Params: []interface{}{m.id, d.txnID, updates}}
+	}
+}
+
+// updates returns the updates for changes to the monitored tables, or nil if
+// none of the changes are monitored.
+func (m *monitor) updates(changes []rowChange) map[string]map[string]interface{} {
+	var updates map[string]map[string]interface{}
+	for _, c := range changes {
+		mt, ok := m.tables[c.Table]
+		if !ok {
+			continue
+		}
+
+		var u interface{}
+		if m.method == "monitor" {
+			u = mt.update(c)
+		} else {
+			u = mt.update2(c)
+		}
+
+		if u == nil {
+			continue
+		}
+
+		if updates == nil {
+			updates = make(map[string]map[string]interface{})
+		}
+		add(updates, c.Table, c.UUID, u)
+	}
+
+	return updates
+}
+
+// update returns the RowUpdate for a change, or nil if the change is not
+// monitored.
+func (mt *monitorTable) update(c rowChange) interface{} {
+	switch {
+	case c.Old == nil:
+		if !mt.insert {
+			return nil
+		}
+
+		return ovsdb.RowUpdate{New: project(c.New, mt.columns)}
+	case c.New == nil:
+		if !mt.delete {
+			return nil
+		}
+
+		return ovsdb.RowUpdate{Old: project(c.Old, mt.columns)}
+	}
+
+	if !mt.modify {
+		return nil
+	}
+
+	// Old contains only the columns which changed.
+	old := make(ovsdb.Row)
+	for _, column := range mt.columns {
+		if !equalValues(c.Old[column], c.New[column]) {
+			old[column] = c.Old[column]
+		}
+	}
+
+	if len(old) == 0 {
+		return nil
+	}
+
+	return ovsdb.RowUpdate{
+		Old: old,
+		New: project(c.New, mt.columns),
+	}
+}
+
+// update2 returns the RowUpdate2 for a change, or nil if the change is not
+// monitored.  A row which begins or ceases to match the monitor's conditions
+// is reported as inserted or deleted.
+func (mt *monitorTable) update2(c rowChange) interface{} {
+	oldMatch, newMatch := mt.matches(c.Old), mt.matches(c.New)
+
+	switch {
+	case !oldMatch && newMatch:
+		if !mt.insert {
+			return nil
+		}
+
+		return ovsdb.RowUpdate2{Insert: project(c.New, mt.columns)}
+	case oldMatch && !newMatch:
+		if !mt.delete {
+			return nil
+		}
+
+		return ovsdb.RowUpdate2{Delete: true}
+	case !oldMatch && !newMatch:
+		return nil
+	}
+
+	if !mt.modify {
+		return nil
+	}
+
+	diff := make(ovsdb.Row)
+	for _, column := range mt.columns {
+		o, n := c.Old[column], c.New[column]
+		if equalValues(o, n) {
+			continue
+		}
+
+		diff[column] = valueDiff(o, n)
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+
+	return ovsdb.RowUpdate2{Modify: diff}
+}
+
+// valueDiff returns the difference between two column values, as described
+// for the modify member of a <row-update2>.  Sets contain the elements which
+// were added or removed.  Maps contain the pairs which were added or
+// removed, and the new values of keys whose values changed.  Scalars contain
+// the new value.
+func valueDiff(o, n interface{}) interface{} {
+	switch o := o.(type) {
+	case ovsdb.Set:
+		return ovsdb.DiffSets(o, n.(ovsdb.Set))
+	case ovsdb.Map:
+		return ovsdb.DiffMaps(o, n.(ovsdb.Map))
+	default:
+		return n
+	}
+}
+
+// add adds a row update to updates.
+func add(updates map[string]map[string]interface{}, table, uuid string, u interface{}) {
+	rows, ok := updates[table]
+	if !ok {
+		rows = make(map[string]interface{})
+		updates[table] = rows
+	}
+
+	rows[uuid] = u
+}
@@ -0,0 +1,311 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMonitor(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	br0 := transact(t, c, insertBridge(nil))[0].UUID
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {Columns: []string{"name", "stp_enable"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	want := ovsdb.TableUpdates{
+		"Bridge": {
+			br0: {New: ovsdb.Row{"name": "br0", "stp_enable": false}},
+		},
+	}
+
+	if diff := cmp.Diff(want, m.Initial); diff != "" {
+		t.Fatalf("unexpected initial contents (-want +got):\n%s", diff)
+	}
+
+	// Changes to unmonitored columns are not reported.
+	transact(t, c,
+		ovsdb.Update{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"external_ids": ovsdb.Map{"foo": "bar"}},
+		},
+	)
+	transact(t, c,
+		ovsdb.Update{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"stp_enable": true},
+		},
+	)
+	transact(t, c, ovsdb.Delete{Table: "Bridge"})
+
+	for _, want := range []ovsdb.TableUpdates{
+		{"Bridge": {br0: {
+			Old: ovsdb.Row{"stp_enable": false},
+			New: ovsdb.Row{"name": "br0", "stp_enable": true},
+		}}},
+		{"Bridge": {br0: {
+			Old: ovsdb.Row{"name": "br0", "stp_enable": true},
+		}}},
+	} {
+		if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+			t.Fatalf("unexpected update (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMonitorCond(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	m, err := c.MonitorCond(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {
+			Columns: []string{"name", "flood_vlans"},
+			Where:   []ovsdb.Cond{ovsdb.Equal("stp_enable", true)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.TableUpdates2{}, m.Initial); diff != "" {
+		t.Fatalf("unexpected initial contents (-want +got):\n%s", diff)
+	}
+
+	// The bridge is only monitored once it matches the condition.
+	br0 := transact(t, c, insertBridge(nil))[0].UUID
+	transact(t, c, ovsdb.Update{
+		Table: "Bridge",
+		Row:   ovsdb.Row{"stp_enable": true},
+	})
+	transact(t, c, ovsdb.Mutate{
+		Table: "Bridge",
+		Mutations: []ovsdb.Mutation{
+			{Column: "flood_vlans", Mutator: ovsdb.MutatorInsert, Value: ovsdb.Set{1}},
+		},
+	})
+	transact(t, c, ovsdb.Update{
+		Table: "Bridge",
+		Row:   ovsdb.Row{"stp_enable": false},
+	})
+
+	set := func(elems ...interface{}) interface{} {
+		if elems == nil {
+			elems = []interface{}{}
+		}
+
+		return []interface{}{"set", elems}
+	}
+
+	for _, want := range []ovsdb.TableUpdates2{
+		{"Bridge": {br0: {Insert: ovsdb.Row{"name": "br0", "flood_vlans": set()}}}},
+		{"Bridge": {br0: {Modify: ovsdb.Row{"flood_vlans": set(int64(1))}}}},
+		{"Bridge": {br0: {Delete: true}}},
+	} {
+		if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+			t.Fatalf("unexpected update (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMonitorCache(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	transact(t, c, insertBridge(nil))
+
+	cache, err := c.MonitorCache(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {},
+		"Port":   {},
+	}, map[string][]string{
+		"Bridge": {"name"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	defer cache.Cancel(context.Background())
+
+	transact(t, c,
+		ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: "port0",
+			Row:      ovsdb.Row{"name": "port0"},
+		},
+		ovsdb.Mutate{
+			Table: "Bridge",
+			Mutations: []ovsdb.Mutation{
+				{Column: "ports", Mutator: ovsdb.MutatorInsert, Value: ovsdb.Set{ovsdb.NamedUUID("port0")}},
+			},
+		},
+	)
+
+	// Wait for the port to be added to the cached bridge.
+	timeout := time.After(5 * time.Second)
+	for {
+		rows, err := cache.Lookup("Bridge", "name", "br0")
+		if err != nil {
+			t.Fatalf("failed to look up bridge: %v", err)
+		}
+
+		var b bridge
+		for _, row := range rows {
+			if err := ovsdb.UnmarshalRow(row, &b); err != nil {
+				t.Fatalf("failed to unmarshal bridge: %v", err)
+			}
+		}
+
+		if len(b.Ports) == 1 {
+			if _, ok := cache.Row("Port", string(b.Ports[0])); !ok {
+				t.Fatal("port referenced by bridge is not cached")
+			}
+
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for cache update")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestMonitorCondSince(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	transact(t, c, insertBridge(nil))
+
+	requests := map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {Columns: []string{"name"}},
+	}
+
+	m, err := c.MonitorCondSince(context.Background(), "Open_vSwitch", requests, "")
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(1, len(m.Initial["Bridge"])); diff != "" {
+		t.Fatalf("unexpected number of initial bridges (-want +got):\n%s", diff)
+	}
+
+	last := m.LastTransactionID()
+	if err := m.Cancel(context.Background()); err != nil {
+		t.Fatalf("failed to cancel monitor: %v", err)
+	}
+
+	// The database has not changed, so no initial contents are sent.
+	m, err = c.MonitorCondSince(context.Background(), "Open_vSwitch", requests, last)
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(0, len(m.Initial)); diff != "" {
+		t.Fatalf("unexpected number of initial tables (-want +got):\n%s", diff)
+	}
+
+	transact(t, c, ovsdb.Delete{Table: "Bridge"})
+	<-m.Updates()
+
+	if m.LastTransactionID() == last {
+		t.Fatal("transaction ID was not updated")
+	}
+}
+
+func TestMonitorCondChange(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	br0 := transact(t, c, insertBridge(nil))[0].UUID
+	br1 := transact(t, c, insertBridge(ovsdb.Row{"name": "br1"}))[0].UUID
+
+	ctx := context.Background()
+
+	m, err := c.MonitorCond(ctx, "Open_vSwitch", map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {
+			Columns: []string{"name"},
+			Where:   []ovsdb.Cond{ovsdb.Equal("name", "br0")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(1, len(m.Initial["Bridge"])); diff != "" {
+		t.Fatalf("unexpected number of initial bridges (-want +got):\n%s", diff)
+	}
+
+	// Switch from br0 to br1, and then to every bridge.
+	if err := m.ChangeConditions(ctx, map[string][]ovsdb.Cond{
+		"Bridge": {ovsdb.Equal("name", "br1")},
+	}); err != nil {
+		t.Fatalf("failed to change conditions: %v", err)
+	}
+
+	if err := m.ChangeConditions(ctx, map[string][]ovsdb.Cond{
+		"Bridge": nil,
+	}); err != nil {
+		t.Fatalf("failed to change conditions: %v", err)
+	}
+
+	// Changes after the conditions change are monitored using the new
+	// conditions.
+	transact(t, c, ovsdb.Update{
+		Table: "Bridge",
+		Row:   ovsdb.Row{"stp_enable": true},
+	})
+	transact(t, c, insertBridge(ovsdb.Row{"name": "br2", "stp_enable": true}))
+
+	for i, want := range []ovsdb.TableUpdates2{
+		{"Bridge": {
+			br0: {Delete: true},
+			br1: {Insert: ovsdb.Row{"name": "br1"}},
+		}},
+		{"Bridge": {
+			br0: {Insert: ovsdb.Row{"name": "br0"}},
+		}},
+	} {
+		if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+			t.Fatalf("unexpected update %d (-want +got):\n%s", i, diff)
+		}
+	}
+
+	// Only the name column is monitored, so only the insertion of br2 is
+	// reported.
+	u := <-m.Updates()
+	if diff := cmp.Diff(1, len(u["Bridge"])); diff != "" {
+		t.Fatalf("unexpected number of updated bridges (-want +got):\n%s", diff)
+	}
+	for _, ru := range u["Bridge"] {
+		if diff := cmp.Diff(ovsdb.Row{"name": "br2"}, ru.Insert); diff != "" {
+			t.Fatalf("unexpected insertion (-want +got):\n%s", diff)
+		}
+	}
+
+	// Tables which are not monitored cannot be changed.
+	if err := m.ChangeConditions(ctx, map[string][]ovsdb.Cond{"Port": nil}); err == nil {
+		t.Fatal("expected an error changing an unmonitored table, but none occurred")
+	}
+}
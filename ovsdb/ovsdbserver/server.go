@@ -0,0 +1,504 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// ErrServerClosed is returned by Server.Serve after Server.Close is called.
+var ErrServerClosed = errors.New("OVSDB server closed")
+
+// A Server is an in-memory OVSDB server.  Servers are created using New.
+type Server struct {
+	wg sync.WaitGroup
+
+	mu        sync.Mutex
+	closed    bool
+	dbs       map[string]*database
+	conns     map[*conn]struct{}
+	listeners map[net.Listener]struct{}
+}
+
+// New creates a Server which serves empty databases with the specified
+// schemas.
+func New(schemas ...*ovsdb.Schema) (*Server, error) {
+	s := &Server{
+		dbs:       make(map[string]*database, len(schemas)),
+		conns:     make(map[*conn]struct{}),
+		listeners: make(map[net.Listener]struct{}),
+	}
+
+	for _, schema := range schemas {
+		if _, ok := s.dbs[schema.Name]; ok {
+			return nil, fmt.Errorf("duplicate database %q", schema.Name)
+		}
+
+		d, err := newDatabase(schema)
+		if err != nil {
+			return nil, err
+		}
+
+		s.dbs[schema.Name] = d
+	}
+
+	return s, nil
+}
+
+// Serve accepts connections on l and serves each of them in a new goroutine.
+// Serve always returns a non-nil error, and returns ErrServerClosed after
+// Close is called.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+	}()
+
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.ServeConn(nc)
+		}()
+	}
+}
+
+// ServeConn serves a single connection, and blocks until the connection is
+// closed by the client or the Server is closed.
+func (s *Server) ServeConn(nc net.Conn) {
+	c := newConn(s, nc)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = nc.Close()
+		return
+	}
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	c.serve()
+
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+}
+
+// Close closes all listeners and connections served by the Server.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+
+	var err error
+	for l := range s.listeners {
+		if lerr := l.Close(); lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	for c := range s.conns {
+		c.close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// A conn is a client connection served by a Server.
+type conn struct {
+	s  *Server
+	nc net.Conn
+
+	// Monitors keyed by the JSON encoding of their IDs.  Guarded by the
+	// Server's mutex, so that updates are sent in the order in which
+	// transactions are committed.
+	monitors map[string]*monitor
+
+	// Notifications which are sent after the response to the request being
+	// handled.  Guarded by the Server's mutex.
+	pending []notification
+
+	// Messages waiting to be written by the writer goroutine, so that a
+	// slow client cannot block the Server.
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []interface{}
+	closed bool
+	done   chan struct{}
+}
+
+// newConn creates a conn which serves nc.
+func newConn(s *Server, nc net.Conn) *conn {
+	c := &conn{
+		s:        s,
+		nc:       nc,
+		monitors: make(map[string]*monitor),
+		done:     make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// A request is a JSON-RPC request or notification sent by a client.
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// A response is a JSON-RPC response sent to a client.
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// A notification is a JSON-RPC notification sent to a client.
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// serve handles requests until the connection is closed.
+func (c *conn) serve() {
+	go c.write()
+	defer func() {
+		c.close()
+		<-c.done
+	}()
+
+	dec := json.NewDecoder(c.nc)
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		// Responses to requests sent by the server are ignored.
+		if req.Method == "" {
+			continue
+		}
+
+		c.s.mu.Lock()
+		result, err := c.handle(req.Method, req.Params)
+
+		// Notifications do not receive a response.
+		if len(req.ID) > 0 && string(req.ID) != "null" {
+			res := response{
+				ID:     req.ID,
+				Result: result,
+			}
+
+			if err != nil {
+				oerr, ok := err.(*ovsdb.Error)
+				if !ok {
+					oerr = errorf(errSyntax, "%v", err)
+				}
+
+				res.Result = nil
+				res.Error = oerr
+			}
+
+			// Send the response while holding the Server's mutex, so that it
+			// precedes the updates for any later transaction.
+			c.send(res)
+		}
+
+		for _, n := range c.pending {
+			c.send(n)
+		}
+		c.pending = nil
+		c.s.mu.Unlock()
+	}
+}
+
+// close closes the connection and stops its writer goroutine.
+func (c *conn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	c.queue = nil
+	c.cond.Broadcast()
+	_ = c.nc.Close()
+}
+
+// send queues a message to be written to the client.
+func (c *conn) send(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.queue = append(c.queue, v)
+	c.cond.Signal()
+}
+
+// write writes queued messages to the client until the connection is closed.
+func (c *conn) write() {
+	defer close(c.done)
+
+	enc := json.NewEncoder(c.nc)
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+
+		queue := c.queue
+		c.queue = nil
+		c.mu.Unlock()
+
+		for _, v := range queue {
+			if err := enc.Encode(v); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// handle handles a single RPC.  The Server's mutex must be held.
+func (c *conn) handle(method string, raw json.RawMessage) (interface{}, error) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, errorf(errSyntax, "parameters of %s must be an array", method)
+	}
+
+	switch method {
+	case "echo":
+		return params, nil
+	case "list_dbs":
+		dbs := make([]string, 0, len(c.s.dbs))
+		for name := range c.s.dbs {
+			dbs = append(dbs, name)
+		}
+
+		sort.Strings(dbs)
+		return dbs, nil
+	case "get_schema":
+		d, err := c.database(params, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.schema, nil
+	case "transact":
+		return c.transact(params)
+	case "monitor", "monitor_cond":
+		return c.monitor(method, params, 3)
+	case "monitor_cond_since":
+		return c.monitor(method, params, 4)
+	case "monitor_cond_change":
+		return c.condChange(params)
+	case "monitor_cancel":
+		return c.cancel(params)
+	case "set_db_change_aware":
+		// Databases are never converted or removed, so no notifications are
+		// ever sent.
+		return struct{}{}, nil
+	default:
+		return nil, errorf(errNotSupported, "unknown method %q", method)
+	}
+}
+
+// database returns the database named by the first of params, and checks
+// that params has n elements.
+func (c *conn) database(params []json.RawMessage, n int) (*database, error) {
+	if len(params) != n {
+		return nil, errorf(errSyntax, "expected %d parameters, got %d", n, len(params))
+	}
+
+	var name string
+	if err := json.Unmarshal(params[0], &name); err != nil {
+		return nil, errorf(errSyntax, "invalid database name: %v", err)
+	}
+
+	d, ok := c.s.dbs[name]
+	if !ok {
+		return nil, errorf(errUnknownDatabase, "%s", name)
+	}
+
+	return d, nil
+}
+
+// transact handles the transact RPC.
+func (c *conn) transact(params []json.RawMessage) (interface{}, error) {
+	if len(params) == 0 {
+		return nil, errorf(errSyntax, "transact requires a database")
+	}
+
+	d, err := c.database(params[:1], 1)
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, changes := d.transact(params[1:])
+	if next != nil {
+		c.s.dbs[d.schema.Name] = next
+		c.s.notify(next, changes)
+	}
+
+	return results, nil
+}
+
+// notify sends updates for changes committed to d to each of its monitors.
+// The Server's mutex must be held.
+func (s *Server) notify(d *database, changes []rowChange) {
+	for c := range s.conns {
+		for _, m := range c.monitors {
+			if m.db != d.schema.Name {
+				continue
+			}
+
+			updates := m.updates(changes)
+			if updates == nil {
+				continue
+			}
+
+			c.send(m.notification(d, updates))
+		}
+	}
+}
+
+// monitor handles the monitor, monitor_cond, and monitor_cond_since RPCs,
+// whose parameters contain n elements.
+func (c *conn) monitor(method string, params []json.RawMessage, n int) (interface{}, error) {
+	d, err := c.database(params, n)
+	if err != nil {
+		return nil, err
+	}
+
+	key := string(params[1])
+	if _, ok := c.monitors[key]; ok {
+		return nil, errorf(errSyntax, "duplicate monitor ID %s", key)
+	}
+
+	m, err := d.newMonitor(method, params[1], params[2])
+	if err != nil {
+		return nil, err
+	}
+
+	c.monitors[key] = m
+
+	if method != "monitor_cond_since" {
+		return m.initial(d), nil
+	}
+
+	var last string
+	if err := json.Unmarshal(params[3], &last); err != nil {
+		return nil, errorf(errSyntax, "invalid last transaction ID: %v", err)
+	}
+
+	// No history is kept, so the monitor can only resume if the database is
+	// unchanged since the client's last transaction.
+	if last == d.txnID {
+		return []interface{}{true, d.txnID, struct{}{}}, nil
+	}
+
+	return []interface{}{false, d.txnID, m.initial(d)}, nil
+}
+
+// condChange handles the monitor_cond_change RPC.
+func (c *conn) condChange(params []json.RawMessage) (interface{}, error) {
+	if len(params) != 3 {
+		return nil, errorf(errSyntax, "expected 3 parameters, got %d", len(params))
+	}
+
+	key, newKey := string(params[0]), string(params[1])
+	m, ok := c.monitors[key]
+	if !ok {
+		return nil, errorf(errSyntax, "unknown monitor %s", key)
+	}
+	if m.method == "monitor" {
+		return nil, errorf(errNotSupported, "monitor %s does not support conditions", key)
+	}
+	if _, ok := c.monitors[newKey]; ok && newKey != key {
+		return nil, errorf(errSyntax, "duplicate monitor ID %s", newKey)
+	}
+
+	d := c.s.dbs[m.db]
+	updates, err := d.changeConditions(m, params[2])
+	if err != nil {
+		return nil, err
+	}
+
+	delete(c.monitors, key)
+	m.id = params[1]
+	c.monitors[newKey] = m
+
+	if updates != nil {
+		c.pending = append(c.pending, m.notification(d, updates))
+	}
+
+	return struct{}{}, nil
+}
+
+// cancel handles the monitor_cancel RPC.
+func (c *conn) cancel(params []json.RawMessage) (interface{}, error) {
+	if len(params) != 1 {
+		return nil, errorf(errSyntax, "expected 1 parameter, got %d", len(params))
+	}
+
+	key := string(params[0])
+	if _, ok := c.monitors[key]; !ok {
+		return nil, errorf(errSyntax, "unknown monitor %s", key)
+	}
+
+	delete(c.monitors, key)
+	return struct{}{}, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbserver"
+	"github.com/google/go-cmp/cmp"
+)
+
+// schema is the schema of the database used in tests.
+const schema = `{
+  "name": "Open_vSwitch",
+  "version": "1.0.0",
+  "tables": {
+    "Bridge": {
+      "columns": {
+        "name": {"type": "string", "mutable": false},
+        "ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+        "controller": {"type": {"key": {"type": "uuid", "refTable": "Controller", "refType": "weak"}, "min": 0, "max": 1}},
+        "flood_vlans": {"type": {"key": {"type": "integer", "minInteger": 0, "maxInteger": 4095}, "min": 0, "max": 4}},
+        "fail_mode": {"type": {"key": {"type": "string", "enum": ["set", ["secure", "standalone"]]}, "min": 0, "max": 1}},
+        "stp_enable": {"type": "boolean"},
+        "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+      },
+      "isRoot": true,
+      "indexes": [["name"]]
+    },
+    "Port": {
+      "columns": {
+        "name": {"type": "string"},
+        "tag": {"type": {"key": {"type": "integer", "minInteger": 0, "maxInteger": 4095}, "min": 0, "max": 1}},
+        "cost": {"type": "real"},
+        "cookie": {"type": "integer"}
+      }
+    },
+    "Controller": {
+      "columns": {
+        "target": {"type": "string"}
+      },
+      "isRoot": true,
+      "maxRows": 2
+    }
+  }
+}`
+
+func TestServerListDatabases(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	dbs, err := c.ListDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"Open_vSwitch"}, dbs); diff != "" {
+		t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerGetSchema(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	got, err := c.GetSchema(context.Background(), "Open_vSwitch")
+	if err != nil {
+		t.Fatalf("failed to get schema: %v", err)
+	}
+
+	if diff := cmp.Diff(testSchema(t), got); diff != "" {
+		t.Fatalf("unexpected schema (-want +got):\n%s", diff)
+	}
+
+	if _, err := c.GetSchema(context.Background(), "foo"); err == nil {
+		t.Fatal("expected an error for an unknown database")
+	}
+}
+
+func TestServerEcho(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	if err := c.Echo(context.Background()); err != nil {
+		t.Fatalf("failed to echo: %v", err)
+	}
+}
+
+func TestNewInvalidSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *ovsdb.Schema
+	}{
+		{
+			name:   "no name",
+			schema: &ovsdb.Schema{},
+		},
+		{
+			name: "unknown type",
+			schema: &ovsdb.Schema{
+				Name: "foo",
+				Tables: map[string]ovsdb.TableSchema{
+					"bar": {
+						Columns: map[string]ovsdb.ColumnSchema{
+							"baz": {Type: ovsdb.ColumnType{Key: ovsdb.BaseType{Type: "foo"}, Min: 1, Max: 1}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown reference",
+			schema: &ovsdb.Schema{
+				Name: "foo",
+				Tables: map[string]ovsdb.TableSchema{
+					"bar": {
+						Columns: map[string]ovsdb.ColumnSchema{
+							"baz": {Type: ovsdb.ColumnType{Key: ovsdb.BaseType{Type: ovsdb.TypeUUID, RefTable: "qux"}, Min: 1, Max: 1}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ovsdbserver.New(tt.schema); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+
+	if _, err := ovsdbserver.New(testSchema(t), testSchema(t)); err == nil {
+		t.Fatal("expected an error for a duplicate database")
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	s, err := ovsdbserver.New(testSchema(t))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.Serve(l)
+	}()
+
+	c, err := ovsdb.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Echo(context.Background()); err != nil {
+		t.Fatalf("failed to echo: %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close server: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdbserver.ErrServerClosed, <-errC, cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})); diff != "" {
+		t.Fatalf("unexpected Serve error (-want +got):\n%s", diff)
+	}
+
+	if err := c.Echo(context.Background()); err == nil {
+		t.Fatal("expected an error after the server was closed")
+	}
+}
+
+// testServer creates a Server using schema, and a Client connected to it.
+// done must be called to clean up both.
+func testServer(t *testing.T) (*ovsdbserver.Server, *ovsdb.Client, func()) {
+	t.Helper()
+
+	s, err := ovsdbserver.New(testSchema(t))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	go func() {
+		if err := s.Serve(l); err != ovsdbserver.ErrServerClosed {
+			panicf("failed to serve: %v", err)
+		}
+	}()
+
+	c, err := ovsdb.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	return s, c, func() {
+		_ = c.Close()
+		_ = s.Close()
+	}
+}
+
+// testSchema parses schema.
+func testSchema(t *testing.T) *ovsdb.Schema {
+	t.Helper()
+
+	var s ovsdb.Schema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	return &s
+}
+
+func panicf(format string, a ...interface{}) {
+	panic(fmt.Sprintf(format, a...))
+}
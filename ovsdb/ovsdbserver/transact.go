@@ -0,0 +1,1101 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// An operation is a single operation within a transaction, as described in
+// RFC 7047, section 5.2.  Only the members used by the operation are set.
+type operation struct {
+	Op        string                   `json:"op"`
+	Table     string                   `json:"table"`
+	Row       map[string]interface{}   `json:"row"`
+	UUIDName  string                   `json:"uuid-name"`
+	Where     []interface{}            `json:"where"`
+	Columns   []string                 `json:"columns"`
+	Mutations []interface{}            `json:"mutations"`
+	Until     string                   `json:"until"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
+
+// A rowChange is a change to a single row made by a committed transaction.
+// Old is nil if the row was inserted, and New is nil if the row was deleted.
+type rowChange struct {
+	Table    string
+	UUID     string
+	Old, New ovsdb.Row
+}
+
+// A txn is a transaction which is applied to a copy of a database.
+type txn struct {
+	db *database
+
+	// UUIDs assigned to named rows inserted by the transaction.
+	names map[string]ovsdb.UUID
+
+	// The tables and UUIDs of rows which the transaction may have changed.
+	changed map[string]map[string]bool
+}
+
+// transact applies the operations of a transaction to a copy of d.  It
+// returns the result of each operation, and if the transaction modified the
+// database, the new contents of the database and the changes which were
+// made.
+func (d *database) transact(params []json.RawMessage) ([]interface{}, *database, []rowChange) {
+	t := &txn{
+		db:      d.clone(),
+		names:   make(map[string]ovsdb.UUID),
+		changed: make(map[string]map[string]bool),
+	}
+
+	results := make([]interface{}, len(params))
+
+	// Parse every operation and assign the UUIDs of named rows first, so
+	// that named UUIDs may be used by any operation in the transaction.
+	ops := make([]operation, 0, len(params))
+	var parseErr error
+	for _, p := range params {
+		// Preserve the precision of integers.
+		d := json.NewDecoder(bytes.NewReader(p))
+		d.UseNumber()
+
+		var op operation
+		if err := d.Decode(&op); err != nil {
+			parseErr = errorf(errSyntax, "invalid operation: %v", err)
+			break
+		}
+
+		if op.Op == "insert" && op.UUIDName != "" {
+			if _, ok := t.names[op.UUIDName]; ok {
+				parseErr = errorf(errDuplicateName, "%s occurs more than once", op.UUIDName)
+				break
+			}
+
+			t.names[op.UUIDName] = newUUID()
+		}
+
+		ops = append(ops, op)
+	}
+
+	for i, op := range ops {
+		res, err := t.apply(op)
+		if err != nil {
+			results[i] = err
+			return results, nil, nil
+		}
+
+		results[i] = res
+	}
+
+	if parseErr != nil {
+		results[len(ops)] = parseErr
+		return results, nil, nil
+	}
+
+	changes, err := t.commit(d)
+	if err != nil {
+		// Errors detected while committing are reported after the results of
+		// the operations.
+		return append(results, err), nil, nil
+	}
+
+	if len(changes) == 0 {
+		return results, nil, nil
+	}
+
+	t.db.txnID = string(newUUID())
+	return results, t.db, changes
+}
+
+// apply applies a single operation.
+func (t *txn) apply(op operation) (interface{}, error) {
+	if op.Op != "commit" && op.Op != "abort" && op.Op != "comment" && op.Op != "assert" {
+		if _, err := t.db.table(op.Table); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "insert":
+		return t.insert(op)
+	case "select":
+		return t.selectRows(op)
+	case "update":
+		return t.update(op)
+	case "mutate":
+		return t.mutate(op)
+	case "delete":
+		return t.delete(op)
+	case "wait":
+		return t.wait(op)
+	case "commit", "comment":
+		return struct{}{}, nil
+	case "abort":
+		return nil, errorf(errAborted, "aborted by request")
+	case "assert":
+		return nil, errorf(errNotSupported, "locks are not supported")
+	default:
+		return nil, errorf(errSyntax, "unknown operation %q", op.Op)
+	}
+}
+
+// insert implements the insert operation.
+func (t *txn) insert(op operation) (interface{}, error) {
+	uuid := newUUID()
+	if op.UUIDName != "" {
+		uuid = t.names[op.UUIDName]
+	}
+
+	ts := t.db.schema.Tables[op.Table]
+	row := ovsdb.Row{
+		"_uuid":    uuid,
+		"_version": newUUID(),
+	}
+	for column, cs := range ts.Columns {
+		row[column] = defaultValue(cs.Type)
+	}
+
+	values, err := t.values(op.Table, op.Row, false)
+	if err != nil {
+		return nil, err
+	}
+	for column, v := range values {
+		row[column] = v
+	}
+
+	t.db.tables[op.Table][string(uuid)] = row
+	t.mark(op.Table, string(uuid))
+
+	return map[string]interface{}{"uuid": uuid}, nil
+}
+
+// selectRows implements the select operation.
+func (t *txn) selectRows(op operation) (interface{}, error) {
+	uuids, err := t.where(op.Table, op.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkColumns(op.Table, op.Columns); err != nil {
+		return nil, err
+	}
+
+	rows := make([]ovsdb.Row, 0, len(uuids))
+	for _, uuid := range uuids {
+		rows = append(rows, project(t.db.tables[op.Table][uuid], op.Columns))
+	}
+
+	return map[string]interface{}{"rows": rows}, nil
+}
+
+// update implements the update operation.
+func (t *txn) update(op operation) (interface{}, error) {
+	values, err := t.values(op.Table, op.Row, true)
+	if err != nil {
+		return nil, err
+	}
+
+	uuids, err := t.where(op.Table, op.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := t.db.tables[op.Table]
+	for _, uuid := range uuids {
+		row := copyRow(rows[uuid])
+		for column, v := range values {
+			row[column] = v
+		}
+
+		rows[uuid] = row
+		t.mark(op.Table, uuid)
+	}
+
+	return map[string]interface{}{"count": len(uuids)}, nil
+}
+
+// mutate implements the mutate operation.
+func (t *txn) mutate(op operation) (interface{}, error) {
+	mutations := make([]mutation, 0, len(op.Mutations))
+	for _, m := range op.Mutations {
+		mut, err := t.mutation(op.Table, m)
+		if err != nil {
+			return nil, err
+		}
+
+		mutations = append(mutations, mut)
+	}
+
+	uuids, err := t.where(op.Table, op.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := t.db.tables[op.Table]
+	for _, uuid := range uuids {
+		row := copyRow(rows[uuid])
+		for _, m := range mutations {
+			v, err := m.apply(row[m.column])
+			if err != nil {
+				return nil, err
+			}
+
+			row[m.column] = v
+		}
+
+		rows[uuid] = row
+		t.mark(op.Table, uuid)
+	}
+
+	return map[string]interface{}{"count": len(uuids)}, nil
+}
+
+// delete implements the delete operation.
+func (t *txn) delete(op operation) (interface{}, error) {
+	uuids, err := t.where(op.Table, op.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uuid := range uuids {
+		delete(t.db.tables[op.Table], uuid)
+		t.mark(op.Table, uuid)
+	}
+
+	return map[string]interface{}{"count": len(uuids)}, nil
+}
+
+// wait implements the wait operation.  The condition is evaluated once, so
+// a wait which is not satisfied immediately fails regardless of its timeout.
+func (t *txn) wait(op operation) (interface{}, error) {
+	uuids, err := t.where(op.Table, op.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkColumns(op.Table, op.Columns); err != nil {
+		return nil, err
+	}
+
+	want := make([]ovsdb.Row, 0, len(op.Rows))
+	for _, r := range op.Rows {
+		row, err := t.values(op.Table, r, false)
+		if err != nil {
+			return nil, err
+		}
+
+		want = append(want, row)
+	}
+
+	got := make([]ovsdb.Row, 0, len(uuids))
+	for _, uuid := range uuids {
+		got = append(got, project(t.db.tables[op.Table][uuid], op.Columns))
+	}
+
+	var equal bool
+	switch op.Until {
+	case ovsdb.FunctionEqual:
+		equal = true
+	case ovsdb.FunctionNotEqual:
+	default:
+		return nil, errorf(errSyntax, "invalid wait until function %q", op.Until)
+	}
+
+	if sameRows(want, got, op.Columns) != equal {
+		return nil, errorf(errTimedOut, "\"wait\" timed out")
+	}
+
+	return struct{}{}, nil
+}
+
+// sameRows reports whether a and b contain the same rows in any order,
+// comparing only the specified columns.
+func sameRows(a, b []ovsdb.Row, columns []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+	for _, ra := range a {
+		found := false
+		for i, rb := range b {
+			if used[i] {
+				continue
+			}
+
+			match := true
+			for _, column := range columns {
+				if !equalValues(ra[column], rb[column]) {
+					match = false
+					break
+				}
+			}
+
+			if match {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// values parses the column values in row.  If update is true, the values
+// are checked for use by an update operation.
+func (t *txn) values(table string, row map[string]interface{}, update bool) (ovsdb.Row, error) {
+	out := make(ovsdb.Row, len(row))
+	for column, v := range row {
+		cs, err := t.db.column(table, column)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(column, "_") {
+			return nil, errorf(errConstraint, "cannot set column %s", column)
+		}
+		if update && !cs.Mutable {
+			return nil, errorf(errConstraint, "cannot modify immutable column %s in table %s", column, table)
+		}
+
+		value, err := parseValue(cs.Type, v, t.names)
+		if err != nil {
+			return nil, err
+		}
+
+		out[column] = value
+	}
+
+	return out, nil
+}
+
+// checkColumns verifies that each column exists in table.
+func (t *txn) checkColumns(table string, columns []string) error {
+	for _, column := range columns {
+		if _, err := t.db.column(table, column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// where returns the UUIDs of the rows in table which match the conditions
+// in where, in sorted order.
+func (t *txn) where(table string, where []interface{}) ([]string, error) {
+	conds, err := t.db.conds(table, where, t.names)
+	if err != nil {
+		return nil, err
+	}
+
+	var uuids []string
+	for uuid, row := range t.db.tables[table] {
+		if matchAll(conds, row) {
+			uuids = append(uuids, uuid)
+		}
+	}
+
+	sort.Strings(uuids)
+	return uuids, nil
+}
+
+// mark records that a row may have been changed by the transaction.
+func (t *txn) mark(table, uuid string) {
+	rows, ok := t.changed[table]
+	if !ok {
+		rows = make(map[string]bool)
+		t.changed[table] = rows
+	}
+
+	rows[uuid] = true
+}
+
+// commit verifies the integrity of the transaction's changes to old, and
+// returns the rows which the transaction changed.
+func (t *txn) commit(old *database) ([]rowChange, error) {
+	d := t.db
+
+	// Removing rows may leave more rows unreferenced, so repeat until no
+	// further rows are removed.
+	for t.removeWeak() || t.collect() {
+	}
+
+	for table, rows := range d.tables {
+		ts := d.schema.Tables[table]
+
+		for uuid, row := range rows {
+			for column, cs := range ts.Columns {
+				if t.changed[table][uuid] {
+					if err := checkSize(cs.Type, row[column]); err != nil {
+						return nil, err
+					}
+				}
+
+				for _, r := range refs(cs.Type, row[column]) {
+					if !r.strong {
+						continue
+					}
+
+					if _, ok := d.tables[r.table][string(r.uuid)]; !ok {
+						return nil, errorf(errReferential, "reference to nonexistent row %s in table %s by column %s of row %s in table %s",
+							r.uuid, r.table, column, uuid, table)
+					}
+				}
+			}
+		}
+
+		if _, ok := t.changed[table]; !ok {
+			continue
+		}
+
+		if ts.MaxRows > 0 && len(rows) > ts.MaxRows {
+			return nil, errorf(errConstraint, "transaction causes %s table to contain %d rows, greater than the schema-defined limit of %d",
+				table, len(rows), ts.MaxRows)
+		}
+
+		if err := checkIndexes(table, ts, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	var changes []rowChange
+	for table, uuids := range t.changed {
+		for uuid := range uuids {
+			o, n := old.tables[table][uuid], d.tables[table][uuid]
+			switch {
+			case o == nil && n == nil:
+				// Inserted and removed by the same transaction.
+				continue
+			case o != nil && n != nil:
+				if equalRows(o, n) {
+					d.tables[table][uuid] = o
+					continue
+				}
+
+				n = copyRow(n)
+				n["_version"] = newUUID()
+				d.tables[table][uuid] = n
+			}
+
+			changes = append(changes, rowChange{
+				Table: table,
+				UUID:  uuid,
+				Old:   o,
+				New:   n,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+
+		return changes[i].UUID < changes[j].UUID
+	})
+
+	return changes, nil
+}
+
+// removeWeak removes weak references to rows which no longer exist.  It
+// reports whether any rows were modified.
+func (t *txn) removeWeak() bool {
+	d := t.db
+	exists := func(table string, uuid ovsdb.UUID) bool {
+		_, ok := d.tables[table][string(uuid)]
+		return ok
+	}
+
+	var modified bool
+	for table, rows := range d.tables {
+		ts := d.schema.Tables[table]
+
+		for uuid, row := range rows {
+			var out ovsdb.Row
+			for column, cs := range ts.Columns {
+				v, removed := removeWeak(cs.Type, row[column], exists)
+				if !removed {
+					continue
+				}
+
+				if out == nil {
+					out = copyRow(row)
+				}
+				out[column] = v
+			}
+
+			if out != nil {
+				rows[uuid] = out
+				t.mark(table, uuid)
+				modified = true
+			}
+		}
+	}
+
+	return modified
+}
+
+// collect removes rows in non-root tables which are not referenced by a
+// strong reference.  It reports whether any rows were removed.
+func (t *txn) collect() bool {
+	d := t.db
+
+	referenced := make(map[string]map[ovsdb.UUID]bool)
+	for table, rows := range d.tables {
+		ts := d.schema.Tables[table]
+
+		for _, row := range rows {
+			for column, cs := range ts.Columns {
+				for _, r := range refs(cs.Type, row[column]) {
+					if !r.strong {
+						continue
+					}
+
+					if referenced[r.table] == nil {
+						referenced[r.table] = make(map[ovsdb.UUID]bool)
+					}
+					referenced[r.table][r.uuid] = true
+				}
+			}
+		}
+	}
+
+	var removed bool
+	for table, rows := range d.tables {
+		if d.root[table] {
+			continue
+		}
+
+		for uuid := range rows {
+			if referenced[table][ovsdb.UUID(uuid)] {
+				continue
+			}
+
+			delete(rows, uuid)
+			t.mark(table, uuid)
+			removed = true
+		}
+	}
+
+	return removed
+}
+
+// checkIndexes verifies that no two rows in a table have the same values
+// for the columns of any of the table's indexes.
+func checkIndexes(table string, ts ovsdb.TableSchema, rows map[string]ovsdb.Row) error {
+	for _, index := range ts.Indexes {
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			keys := make([]string, 0, len(index))
+			for _, column := range index {
+				keys = append(keys, valueKey(row[column]))
+			}
+
+			key := strings.Join(keys, ",")
+			if seen[key] {
+				return errorf(errConstraint, "transaction causes multiple rows in %s table to have identical values for index on columns %s",
+					table, strings.Join(index, ", "))
+			}
+
+			seen[key] = true
+		}
+	}
+
+	return nil
+}
+
+// project returns a copy of row containing only the specified columns.  If
+// columns is nil, all columns are returned.
+func project(row ovsdb.Row, columns []string) ovsdb.Row {
+	if columns == nil {
+		return copyRow(row)
+	}
+
+	out := make(ovsdb.Row, len(columns))
+	for _, column := range columns {
+		out[column] = row[column]
+	}
+
+	return out
+}
+
+// A condFunc reports whether a row matches a condition.
+type condFunc func(row ovsdb.Row) bool
+
+// matchAll reports whether row matches every condition in conds.
+func matchAll(conds []condFunc, row ovsdb.Row) bool {
+	for _, fn := range conds {
+		if !fn(row) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conds parses the conditions in where for rows of table, as described in
+// RFC 7047, section 5.1.  The boolean conditions true and false are also
+// accepted, as used by monitor_cond.
+func (d *database) conds(table string, where []interface{}, names map[string]ovsdb.UUID) ([]condFunc, error) {
+	conds := make([]condFunc, 0, len(where))
+	for _, w := range where {
+		fn, err := d.cond(table, w, names)
+		if err != nil {
+			return nil, err
+		}
+
+		conds = append(conds, fn)
+	}
+
+	return conds, nil
+}
+
+// cond parses a single condition.
+func (d *database) cond(table string, w interface{}, names map[string]ovsdb.UUID) (condFunc, error) {
+	if b, ok := w.(bool); ok {
+		return func(ovsdb.Row) bool { return b }, nil
+	}
+
+	a, ok := w.([]interface{})
+	if !ok || len(a) != 3 {
+		return nil, errorf(errSyntax, "invalid condition: %v", w)
+	}
+
+	column, ok1 := a[0].(string)
+	function, ok2 := a[1].(string)
+	if !ok1 || !ok2 {
+		return nil, errorf(errSyntax, "invalid condition: %v", w)
+	}
+
+	cs, err := d.column(table, column)
+	if err != nil {
+		return nil, err
+	}
+	typ := cs.Type
+
+	switch function {
+	case ovsdb.FunctionLessThan, ovsdb.FunctionLessThanOrEqual,
+		ovsdb.FunctionGreaterThan, ovsdb.FunctionGreaterThanOrEqual:
+		numeric := typ.Key.Type == ovsdb.TypeInteger || typ.Key.Type == ovsdb.TypeReal
+		if !numeric || typ.IsMap() || typ.Max != 1 {
+			return nil, errorf(errSyntax, "function %s is not valid for column %s", function, column)
+		}
+
+		v, err := parseValue(scalar(typ.Key), a[2], names)
+		if err != nil {
+			return nil, err
+		}
+		arg := v
+
+		return func(row ovsdb.Row) bool {
+			v := row[column]
+			if s, ok := v.(ovsdb.Set); ok {
+				// An empty optional column matches no comparison.
+				if len(s) != 1 {
+					return false
+				}
+				v = s[0]
+			}
+
+			c := compareNumbers(v, arg)
+			switch function {
+			case ovsdb.FunctionLessThan:
+				return c < 0
+			case ovsdb.FunctionLessThanOrEqual:
+				return c <= 0
+			case ovsdb.FunctionGreaterThan:
+				return c > 0
+			default:
+				return c >= 0
+			}
+		}, nil
+	case ovsdb.FunctionEqual, ovsdb.FunctionNotEqual, ovsdb.FunctionIncludes, ovsdb.FunctionExcludes:
+		arg, err := parseValue(typ, a[2], names)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(row ovsdb.Row) bool {
+			v := row[column]
+			switch function {
+			case ovsdb.FunctionEqual:
+				return equalValues(v, arg)
+			case ovsdb.FunctionNotEqual:
+				return !equalValues(v, arg)
+			case ovsdb.FunctionIncludes:
+				return includes(v, arg)
+			default:
+				return excludes(v, arg)
+			}
+		}, nil
+	default:
+		return nil, errorf(errSyntax, "unknown function %q", function)
+	}
+}
+
+// includes reports whether the column value v includes every element of
+// arg.
+func includes(v, arg interface{}) bool {
+	switch v := v.(type) {
+	case ovsdb.Set:
+		return includesSet(v, arg.(ovsdb.Set))
+	case ovsdb.Map:
+		return includesMap(v, arg.(ovsdb.Map))
+	default:
+		return v == arg
+	}
+}
+
+// excludes reports whether the column value v includes none of the elements
+// of arg.
+func excludes(v, arg interface{}) bool {
+	switch v := v.(type) {
+	case ovsdb.Set:
+		for _, e := range arg.(ovsdb.Set) {
+			if includesSet(v, ovsdb.Set{e}) {
+				return false
+			}
+		}
+
+		return true
+	case ovsdb.Map:
+		for k, e := range arg.(ovsdb.Map) {
+			if includesMap(v, ovsdb.Map{k: e}) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return v != arg
+	}
+}
+
+// scalar returns the type of a scalar column with base type bt.
+func scalar(bt ovsdb.BaseType) ovsdb.ColumnType {
+	return ovsdb.ColumnType{
+		Key: bt,
+		Min: 1,
+		Max: 1,
+	}
+}
+
+// A mutation is a parsed mutation, as described in RFC 7047, section 5.1.
+type mutation struct {
+	column  string
+	typ     ovsdb.ColumnType
+	mutator string
+	value   interface{}
+}
+
+// mutation parses a single mutation for a row of table.
+func (t *txn) mutation(table string, m interface{}) (mutation, error) {
+	a, ok := m.([]interface{})
+	if !ok || len(a) != 3 {
+		return mutation{}, errorf(errSyntax, "invalid mutation: %v", m)
+	}
+
+	column, ok1 := a[0].(string)
+	mutator, ok2 := a[1].(string)
+	if !ok1 || !ok2 {
+		return mutation{}, errorf(errSyntax, "invalid mutation: %v", m)
+	}
+
+	cs, err := t.db.column(table, column)
+	if err != nil {
+		return mutation{}, err
+	}
+	if strings.HasPrefix(column, "_") || !cs.Mutable {
+		return mutation{}, errorf(errConstraint, "cannot mutate immutable column %s in table %s", column, table)
+	}
+
+	typ := cs.Type
+	out := mutation{
+		column:  column,
+		typ:     typ,
+		mutator: mutator,
+	}
+
+	switch mutator {
+	case "+=", "-=", "*=", "/=", "%=":
+		ok := typ.Key.Type == ovsdb.TypeInteger || (typ.Key.Type == ovsdb.TypeReal && mutator != "%=")
+		if !ok || typ.IsMap() {
+			return mutation{}, errorf(errSyntax, "mutator %s is not valid for column %s", mutator, column)
+		}
+
+		// The argument has the column's base type, without its
+		// constraints, which are checked against the result instead.
+		out.value, err = parseValue(scalar(ovsdb.BaseType{Type: typ.Key.Type}), a[2], t.names)
+	case "insert", "delete":
+		if typ.Min == 1 && typ.Max == 1 {
+			return mutation{}, errorf(errSyntax, "mutator %s is not valid for scalar column %s", mutator, column)
+		}
+
+		set := ovsdb.ColumnType{Key: typ.Key, Max: ovsdb.Unlimited}
+		switch {
+		case !typ.IsMap():
+			out.value, err = parseValue(set, a[2], t.names)
+		case mutator == "delete":
+			// Map deletions may specify either pairs or only keys.
+			if _, perr := ovsdb.ParseMap(a[2]); perr == nil {
+				out.value, err = parseValue(typ, a[2], t.names)
+			} else {
+				out.value, err = parseValue(set, a[2], t.names)
+			}
+		default:
+			out.value, err = parseValue(typ, a[2], t.names)
+		}
+	default:
+		return mutation{}, errorf(errSyntax, "unknown mutator %q", mutator)
+	}
+	if err != nil {
+		return mutation{}, err
+	}
+
+	return out, nil
+}
+
+// apply applies the mutation to a column value.
+func (m mutation) apply(v interface{}) (interface{}, error) {
+	switch m.mutator {
+	case "insert":
+		return m.insert(v), nil
+	case "delete":
+		return m.delete(v), nil
+	}
+
+	// Arithmetic mutators apply to each element of a set.
+	s, ok := v.(ovsdb.Set)
+	if !ok {
+		return m.arithmetic(v)
+	}
+
+	out := make(ovsdb.Set, 0, len(s))
+	seen := make(map[interface{}]bool, len(s))
+	for _, e := range s {
+		r, err := m.arithmetic(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+
+	return out, nil
+}
+
+// arithmetic applies an arithmetic mutator to an atom.
+func (m mutation) arithmetic(atom interface{}) (interface{}, error) {
+	var (
+		r  interface{}
+		ok bool
+	)
+
+	if m.typ.Key.Type == ovsdb.TypeInteger {
+		r, ok = integerArithmetic(m.mutator, atom.(int64), m.value.(int64))
+	} else {
+		r, ok = realArithmetic(m.mutator, atom.(float64), m.value.(float64))
+	}
+	if !ok {
+		return nil, errorf(errDomain, "division by zero")
+	}
+
+	rangeErr := errorf(errRange, "result of mutation %s on column %s is out of range", m.mutator, m.column)
+	if r == nil {
+		return nil, rangeErr
+	}
+
+	out, err := parseAtom(m.typ.Key, r, nil)
+	if err != nil {
+		return nil, rangeErr
+	}
+
+	return out, nil
+}
+
+// integerArithmetic applies an arithmetic mutator to integers a and b.  It
+// returns a nil result if the mutation overflows, and false if b is zero and
+// the mutator divides.
+func integerArithmetic(mutator string, a, b int64) (interface{}, bool) {
+	var r int64
+	switch mutator {
+	case "+=":
+		r = a + b
+		if (b > 0 && r < a) || (b < 0 && r > a) {
+			return nil, true
+		}
+	case "-=":
+		r = a - b
+		if (b > 0 && r > a) || (b < 0 && r < a) {
+			return nil, true
+		}
+	case "*=":
+		r = a * b
+		if a != 0 && (r/a != b || (a == -1 && b == math.MinInt64)) {
+			return nil, true
+		}
+	case "/=", "%=":
+		if b == 0 {
+			return nil, false
+		}
+
+		if b == -1 {
+			// Avoid the overflow of math.MinInt64 / -1.
+			if mutator == "%=" {
+				return int64(0), true
+			}
+			if a == math.MinInt64 {
+				return nil, true
+			}
+
+			return -a, true
+		}
+
+		if mutator == "%=" {
+			r = a % b
+		} else {
+			r = a / b
+		}
+	}
+
+	return r, true
+}
+
+// realArithmetic applies an arithmetic mutator other than "%=" to reals a and
+// b.  It returns false if the mutator divides by zero.
+func realArithmetic(mutator string, a, b float64) (interface{}, bool) {
+	switch mutator {
+	case "+=":
+		return a + b, true
+	case "-=":
+		return a - b, true
+	case "*=":
+		return a * b, true
+	default:
+		if b == 0 {
+			return nil, false
+		}
+
+		return a / b, true
+	}
+}
+
+// compareNumbers compares two numeric atoms of the same type, returning a
+// negative number, zero, or a positive number if a is less than, equal to,
+// or greater than b.
+func compareNumbers(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// insert applies the insert mutator to a set or map.
+func (m mutation) insert(v interface{}) interface{} {
+	if s, ok := v.(ovsdb.Set); ok {
+		out := append(ovsdb.Set(nil), s...)
+		for _, e := range m.value.(ovsdb.Set) {
+			if !includesSet(out, ovsdb.Set{e}) {
+				out = append(out, e)
+			}
+		}
+
+		return out
+	}
+
+	mv := v.(ovsdb.Map)
+	out := make(ovsdb.Map, len(mv))
+	for k, e := range mv {
+		out[k] = e
+	}
+
+	// Existing keys are not modified.
+	for k, e := range m.value.(ovsdb.Map) {
+		if _, ok := out[k]; !ok {
+			out[k] = e
+		}
+	}
+
+	return out
+}
+
+// delete applies the delete mutator to a set or map.
+func (m mutation) delete(v interface{}) interface{} {
+	if s, ok := v.(ovsdb.Set); ok {
+		del := m.value.(ovsdb.Set)
+
+		out := make(ovsdb.Set, 0, len(s))
+		for _, e := range s {
+			if !includesSet(del, ovsdb.Set{e}) {
+				out = append(out, e)
+			}
+		}
+
+		return out
+	}
+
+	mv := v.(ovsdb.Map)
+	out := make(ovsdb.Map, len(mv))
+	for k, e := range mv {
+		out[k] = e
+	}
+
+	switch del := m.value.(type) {
+	case ovsdb.Map:
+		for k, e := range del {
+			if out[k] == e {
+				delete(out, k)
+			}
+		}
+	case ovsdb.Set:
+		for _, k := range del {
+			delete(out, k)
+		}
+	}
+
+	return out
+}
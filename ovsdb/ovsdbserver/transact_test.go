@@ -0,0 +1,478 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+// A bridge is a row of the Bridge table.
+type bridge struct {
+	UUID        ovsdb.UUID        `ovsdb:"_uuid"`
+	Name        string            `ovsdb:"name"`
+	Ports       []ovsdb.UUID      `ovsdb:"ports"`
+	Controller  *ovsdb.UUID       `ovsdb:"controller"`
+	FloodVLANs  []int             `ovsdb:"flood_vlans"`
+	FailMode    *string           `ovsdb:"fail_mode"`
+	STPEnable   bool              `ovsdb:"stp_enable"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+}
+
+func TestTransactInsertSelect(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	res := transact(t, c,
+		ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: "port0",
+			Row:      ovsdb.Row{"name": "port0"},
+		},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row: ovsdb.Row{
+				"name":         "br0",
+				"ports":        ovsdb.Set{ovsdb.NamedUUID("port0")},
+				"fail_mode":    "secure",
+				"external_ids": ovsdb.Map{"foo": "bar"},
+			},
+		},
+	)
+
+	secure := "secure"
+	want := []bridge{{
+		UUID:        ovsdb.UUID(res[1].UUID),
+		Name:        "br0",
+		Ports:       []ovsdb.UUID{ovsdb.UUID(res[0].UUID)},
+		FloodVLANs:  []int{},
+		FailMode:    &secure,
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}}
+
+	if diff := cmp.Diff(want, bridges(t, c)); diff != "" {
+		t.Fatalf("unexpected bridges (-want +got):\n%s", diff)
+	}
+
+	// Select only the requested columns of matching rows.
+	res = transact(t, c, ovsdb.Select{
+		Table:   "Port",
+		Where:   []ovsdb.Cond{ovsdb.Equal("_uuid", ovsdb.UUID(res[0].UUID))},
+		Columns: []string{"name", "tag"},
+	})
+
+	wantRows := []ovsdb.Row{{
+		"name": "port0",
+		"tag":  []interface{}{"set", []interface{}{}},
+	}}
+
+	if diff := cmp.Diff(wantRows, res[0].Rows); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactUpdateMutateDelete(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	res := transact(t, c,
+		ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: "port0",
+			Row:      ovsdb.Row{"name": "port0"},
+		},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row: ovsdb.Row{
+				"name":  "br0",
+				"ports": ovsdb.Set{ovsdb.NamedUUID("port0")},
+			},
+		},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br1"},
+		},
+	)
+	br0 := ovsdb.UUID(res[1].UUID)
+
+	where := []ovsdb.Cond{ovsdb.Equal("name", "br0")}
+	res = transact(t, c,
+		ovsdb.Update{
+			Table: "Bridge",
+			Where: where,
+			Row:   ovsdb.Row{"stp_enable": true},
+		},
+		ovsdb.Mutate{
+			Table: "Bridge",
+			Where: where,
+			Mutations: []ovsdb.Mutation{
+				{Column: "flood_vlans", Mutator: ovsdb.MutatorInsert, Value: ovsdb.Set{1, 2, 3}},
+				{Column: "external_ids", Mutator: ovsdb.MutatorInsert, Value: ovsdb.Map{"foo": "bar", "baz": "qux"}},
+			},
+		},
+		ovsdb.Mutate{
+			Table: "Bridge",
+			Where: []ovsdb.Cond{ovsdb.Includes("flood_vlans", ovsdb.Set{2})},
+			Mutations: []ovsdb.Mutation{
+				{Column: "flood_vlans", Mutator: ovsdb.MutatorDelete, Value: ovsdb.Set{1}},
+				{Column: "flood_vlans", Mutator: ovsdb.MutatorAdd, Value: 10},
+				{Column: "external_ids", Mutator: ovsdb.MutatorDelete, Value: ovsdb.Set{"baz"}},
+			},
+		},
+	)
+
+	for i, r := range res {
+		if diff := cmp.Diff(1, r.Count); diff != "" {
+			t.Fatalf("unexpected count for operation %d (-want +got):\n%s", i, diff)
+		}
+	}
+
+	got := bridges(t, c)
+	if diff := cmp.Diff(2, len(got)); diff != "" {
+		t.Fatalf("unexpected number of bridges (-want +got):\n%s", diff)
+	}
+
+	want := bridge{
+		UUID:        br0,
+		Name:        "br0",
+		Ports:       got[0].Ports,
+		FloodVLANs:  []int{12, 13},
+		STPEnable:   true,
+		ExternalIDs: map[string]string{"foo": "bar"},
+	}
+
+	if diff := cmp.Diff(want, got[0]); diff != "" {
+		t.Fatalf("unexpected bridge (-want +got):\n%s", diff)
+	}
+
+	// Deleting the bridge also removes its unreferenced port.
+	res = transact(t, c, ovsdb.Delete{
+		Table: "Bridge",
+		Where: []ovsdb.Cond{ovsdb.Equal("name", "br0")},
+	})
+	if diff := cmp.Diff(1, res[0].Count); diff != "" {
+		t.Fatalf("unexpected count (-want +got):\n%s", diff)
+	}
+
+	res = transact(t, c, ovsdb.Select{Table: "Port"})
+	if diff := cmp.Diff(0, len(res[0].Rows)); diff != "" {
+		t.Fatalf("unexpected number of ports (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactLargeIntegers(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	// Integers which cannot be represented exactly by a float64.
+	const cookie = 1<<62 + 1
+
+	transact(t, c,
+		ovsdb.Insert{
+			Table:    "Port",
+			UUIDName: "port0",
+			Row:      ovsdb.Row{"name": "port0", "cookie": cookie, "cost": 1.5},
+		},
+		insertBridge(ovsdb.Row{"ports": ovsdb.Set{ovsdb.NamedUUID("port0")}}),
+	)
+
+	where := []ovsdb.Cond{ovsdb.Equal("cookie", cookie)}
+	transact(t, c, ovsdb.Mutate{
+		Table:     "Port",
+		Where:     where,
+		Mutations: []ovsdb.Mutation{{Column: "cookie", Mutator: ovsdb.MutatorAdd, Value: 2}},
+	})
+
+	res := transact(t, c, ovsdb.Select{
+		Table:   "Port",
+		Where:   []ovsdb.Cond{ovsdb.GreaterThan("cookie", cookie+1)},
+		Columns: []string{"cookie", "cost"},
+	})
+
+	want := []ovsdb.Row{{"cookie": int64(cookie + 2), "cost": 1.5}}
+	if diff := cmp.Diff(want, res[0].Rows); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactGarbageCollection(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	// Ports which are not referenced by a bridge are removed immediately.
+	transact(t, c, ovsdb.Insert{
+		Table: "Port",
+		Row:   ovsdb.Row{"name": "port0"},
+	})
+
+	res := transact(t, c, ovsdb.Select{Table: "Port"})
+	if diff := cmp.Diff(0, len(res[0].Rows)); diff != "" {
+		t.Fatalf("unexpected number of ports (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactWeakReference(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	res := transact(t, c,
+		ovsdb.Insert{
+			Table:    "Controller",
+			UUIDName: "ctl",
+			Row:      ovsdb.Row{"target": "tcp:127.0.0.1:6653"},
+		},
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row: ovsdb.Row{
+				"name":       "br0",
+				"controller": ovsdb.NamedUUID("ctl"),
+			},
+		},
+	)
+
+	ctl := ovsdb.UUID(res[0].UUID)
+	if diff := cmp.Diff(&ctl, bridges(t, c)[0].Controller); diff != "" {
+		t.Fatalf("unexpected controller (-want +got):\n%s", diff)
+	}
+
+	// Deleting the controller removes the weak reference to it.
+	transact(t, c, ovsdb.Delete{Table: "Controller"})
+
+	if got := bridges(t, c)[0].Controller; got != nil {
+		t.Fatalf("expected no controller, but got: %v", *got)
+	}
+}
+
+func TestTransactWait(t *testing.T) {
+	_, c, done := testServer(t)
+	defer done()
+
+	transact(t, c, ovsdb.Insert{
+		Table: "Bridge",
+		Row:   ovsdb.Row{"name": "br0"},
+	})
+
+	transact(t, c,
+		ovsdb.Wait{
+			Table:   "Bridge",
+			Where:   []ovsdb.Cond{ovsdb.Equal("name", "br0")},
+			Columns: []string{"stp_enable"},
+			Until:   ovsdb.FunctionEqual,
+			Rows:    []ovsdb.Row{{"stp_enable": false}},
+		},
+		ovsdb.Wait{
+			Table:   "Bridge",
+			Columns: []string{"name"},
+			Until:   ovsdb.FunctionNotEqual,
+			Rows:    []ovsdb.Row{{"name": "br1"}},
+		},
+	)
+}
+
+func TestTransactErrors(t *testing.T) {
+	bad := ovsdb.UUID("6f5e6b50-d3d7-4d3d-8b63-2a9ab6e0b6c1")
+
+	tests := []struct {
+		name string
+		ops  []ovsdb.TransactOp
+		err  string
+	}{
+		{
+			name: "unknown table",
+			ops:  []ovsdb.TransactOp{ovsdb.Select{Table: "foo"}},
+			err:  "unknown table",
+		},
+		{
+			name: "unknown column",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"foo": "bar"})},
+			err:  "unknown column",
+		},
+		{
+			name: "wrong type",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"stp_enable": "yes"})},
+			err:  "syntax error",
+		},
+		{
+			name: "enum",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"fail_mode": "foo"})},
+			err:  "constraint violation",
+		},
+		{
+			name: "integer range",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"flood_vlans": ovsdb.Set{5000}})},
+			err:  "constraint violation",
+		},
+		{
+			name: "too many elements",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"flood_vlans": ovsdb.Set{1, 2, 3, 4, 5}})},
+			err:  "constraint violation",
+		},
+		{
+			name: "immutable column",
+			ops: []ovsdb.TransactOp{
+				insertBridge(nil),
+				ovsdb.Update{
+					Table: "Bridge",
+					Row:   ovsdb.Row{"name": "br1"},
+				},
+			},
+			err: "constraint violation",
+		},
+		{
+			name: "duplicate index",
+			ops:  []ovsdb.TransactOp{insertBridge(nil), insertBridge(nil)},
+			err:  "constraint violation",
+		},
+		{
+			name: "max rows",
+			ops: []ovsdb.TransactOp{
+				ovsdb.Insert{Table: "Controller"},
+				ovsdb.Insert{Table: "Controller"},
+				ovsdb.Insert{Table: "Controller"},
+			},
+			err: "constraint violation",
+		},
+		{
+			name: "referential integrity",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"ports": ovsdb.Set{bad}})},
+			err:  "referential integrity violation",
+		},
+		{
+			name: "unknown named UUID",
+			ops:  []ovsdb.TransactOp{insertBridge(ovsdb.Row{"ports": ovsdb.Set{ovsdb.NamedUUID("foo")}})},
+			err:  "syntax error",
+		},
+		{
+			name: "division by zero",
+			ops: []ovsdb.TransactOp{
+				insertBridge(ovsdb.Row{"flood_vlans": ovsdb.Set{1}}),
+				ovsdb.Mutate{
+					Table:     "Bridge",
+					Mutations: []ovsdb.Mutation{{Column: "flood_vlans", Mutator: ovsdb.MutatorDivide, Value: 0}},
+				},
+			},
+			err: "domain error",
+		},
+		{
+			name: "integer overflow",
+			ops: []ovsdb.TransactOp{
+				insertBridge(nil),
+				ovsdb.Insert{Table: "Port", Row: ovsdb.Row{"cookie": math.MaxInt64}},
+				ovsdb.Mutate{
+					Table:     "Port",
+					Mutations: []ovsdb.Mutation{{Column: "cookie", Mutator: ovsdb.MutatorAdd, Value: 1}},
+				},
+			},
+			err: "range error",
+		},
+		{
+			name: "wait",
+			ops: []ovsdb.TransactOp{
+				insertBridge(nil),
+				ovsdb.Wait{
+					Table:   "Bridge",
+					Columns: []string{"name"},
+					Rows:    []ovsdb.Row{{"name": "br1"}},
+				},
+			},
+			err: "timed out",
+		},
+		{
+			name: "abort",
+			ops: []ovsdb.TransactOp{
+				insertBridge(nil),
+				json.RawMessage(`{"op":"abort"}`),
+			},
+			err: "aborted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c, done := testServer(t)
+			defer done()
+
+			_, err := c.Transact(context.Background(), "Open_vSwitch", tt.ops)
+			oerr, ok := err.(*ovsdb.Error)
+			if !ok {
+				t.Fatalf("expected an OVSDB error, but got: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.err, oerr.Err); diff != "" {
+				t.Fatalf("unexpected error (-want +got):\n%s", diff)
+			}
+
+			// Failed transactions make no changes.
+			if diff := cmp.Diff(0, len(bridges(t, c))); diff != "" {
+				t.Fatalf("unexpected number of bridges (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// insertBridge creates an Insert for a bridge named br0 with the additional
+// columns in row.
+func insertBridge(row ovsdb.Row) ovsdb.Insert {
+	r := ovsdb.Row{"name": "br0"}
+	for column, v := range row {
+		r[column] = v
+	}
+
+	return ovsdb.Insert{
+		Table: "Bridge",
+		Row:   r,
+	}
+}
+
+// transact performs a transaction which must succeed.
+func transact(t *testing.T, c *ovsdb.Client, ops ...ovsdb.TransactOp) []ovsdb.OperationResult {
+	t.Helper()
+
+	res, err := c.Transact(context.Background(), "Open_vSwitch", ops)
+	if err != nil {
+		t.Fatalf("failed to transact: %v", err)
+	}
+
+	return res
+}
+
+// bridges returns all bridges, sorted by name.
+func bridges(t *testing.T, c *ovsdb.Client) []bridge {
+	t.Helper()
+
+	res := transact(t, c, ovsdb.Select{Table: "Bridge"})
+
+	bs := make([]bridge, 0, len(res[0].Rows))
+	for _, row := range res[0].Rows {
+		var b bridge
+		if err := ovsdb.UnmarshalRow(row, &b); err != nil {
+			t.Fatalf("failed to unmarshal bridge: %v", err)
+		}
+
+		bs = append(bs, b)
+	}
+
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Name < bs[j].Name
+	})
+
+	return bs
+}
@@ -0,0 +1,546 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// A HandlerFunc handles an RPC with the specified parameters.  result is
+// marshaled to JSON as the RPC's result.  If err is not nil, the RPC fails
+// with a JSON-RPC error containing err's message instead.
+//
+// OVSDB errors, which are reported as successful RPC results, can be
+// returned by using an *ovsdb.Error as result.
+type HandlerFunc func(params json.RawMessage) (result interface{}, err error)
+
+// A TransactFunc handles a transaction on the database db.  Each operation
+// in ops is the JSON object of a single operation decoded by encoding/json,
+// so ops can be compared with the expected operations.
+//
+// The returned results are sent to the client in the same order as ops.
+// An ovsdb.OperationResult with a non-nil Error reports a failed operation.
+type TransactFunc func(db string, ops []map[string]interface{}) ([]ovsdb.OperationResult, error)
+
+// A Server is a fake OVSDB server which listens for connections on a TCP
+// loopback address.  Servers are created using NewServer.
+//
+// By default, a Server implements the echo, list_dbs, and get_schema RPCs
+// using the schemas added with AddSchema, and accepts the monitor, lock,
+// steal, and unlock RPCs.  The responses to any RPC can be customized using
+// Handle, and transactions are handled using HandleTransact.
+type Server struct {
+	l  net.Listener
+	wg sync.WaitGroup
+
+	mu       sync.Mutex
+	closed   bool
+	conns    map[*conn]struct{}
+	schemas  map[string]*ovsdb.Schema
+	handlers map[string]HandlerFunc
+	transact TransactFunc
+}
+
+// NewServer creates a Server which listens on a TCP loopback address.  Use
+// Addr to dial the Server, or Client to create an ovsdb.Client connected to
+// it.  The Server must be closed with Close when it is no longer needed.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		l:        l,
+		conns:    make(map[*conn]struct{}),
+		schemas:  make(map[string]*ovsdb.Schema),
+		handlers: make(map[string]HandlerFunc),
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serve()
+	}()
+
+	return s, nil
+}
+
+// Addr returns the TCP address of the Server, for use with ovsdb.Dial.
+func (s *Server) Addr() string {
+	return s.l.Addr().String()
+}
+
+// Client dials the Server and returns an ovsdb.Client.
+func (s *Server) Client(options ...ovsdb.OptionFunc) (*ovsdb.Client, error) {
+	return ovsdb.Dial("tcp", s.Addr(), options...)
+}
+
+// Close closes the Server and all of its connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		_ = c.c.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.l.Close()
+	s.wg.Wait()
+	return err
+}
+
+// AddSchema adds a database with the specified schema to the Server.
+func (s *Server) AddSchema(schema *ovsdb.Schema) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schemas[schema.Name] = schema
+}
+
+// Handle sets the HandlerFunc for the RPC method, replacing any default
+// handler.  The monitors created by the monitor RPCs are still tracked for
+// use with Update and Update2.
+func (s *Server) Handle(method string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = fn
+}
+
+// HandleTransact sets the TransactFunc which handles transactions.  If no
+// TransactFunc is set, transactions fail with a JSON-RPC error.
+func (s *Server) HandleTransact(fn TransactFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transact = fn
+}
+
+// Update sends an update notification containing updates to each monitor of
+// the database db created using the monitor RPC.
+func (s *Server) Update(db string, updates ovsdb.TableUpdates) error {
+	return s.notify(db, func(m *monitor) (string, []interface{}) {
+		if m.method != "monitor" {
+			return "", nil
+		}
+
+		return "update", []interface{}{m.id, updates}
+	})
+}
+
+// Update2 sends an update2 notification containing updates to each monitor
+// of the database db created using the monitor_cond RPC, and an update3
+// notification with the transaction ID txnID to each monitor created using
+// the monitor_cond_since RPC.
+func (s *Server) Update2(db string, updates ovsdb.TableUpdates2, txnID string) error {
+	return s.notify(db, func(m *monitor) (string, []interface{}) {
+		switch m.method {
+		case "monitor_cond":
+			return "update2", []interface{}{m.id, updates}
+		case "monitor_cond_since":
+			return "update3", []interface{}{m.id, txnID, updates}
+		default:
+			return "", nil
+		}
+	})
+}
+
+// CancelMonitors sends a monitor_canceled notification to each monitor of the
+// database db, as if db had been converted or removed, and forgets the
+// monitors.
+func (s *Server) CancelMonitors(db string) error {
+	err := s.notify(db, func(m *monitor) (string, []interface{}) {
+		return "monitor_canceled", []interface{}{m.id}
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.conns {
+		c.mu.Lock()
+		for id, m := range c.monitors {
+			if m.db == db {
+				delete(c.monitors, id)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return err
+}
+
+// Monitors returns the number of monitors which clients have created and not
+// canceled.
+func (s *Server) Monitors() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for c := range s.conns {
+		c.mu.Lock()
+		n += len(c.monitors)
+		c.mu.Unlock()
+	}
+
+	return n
+}
+
+// notify sends a notification to each monitor of db.  fn returns the
+// notification's method and parameters for a monitor, or an empty method to
+// skip the monitor.
+func (s *Server) notify(db string, fn func(m *monitor) (string, []interface{})) error {
+	s.mu.Lock()
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.mu.Lock()
+		var ns []notification
+		for _, m := range c.monitors {
+			if m.db != db {
+				continue
+			}
+
+			method, params := fn(m)
+			if method == "" {
+				continue
+			}
+
+			ns = append(ns, notification{
+				Method: method,
+				Params: params,
+			})
+		}
+		c.mu.Unlock()
+
+		for _, n := range ns {
+			if err := c.write(n); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// serve accepts connections until the Server is closed.
+func (s *Server) serve() {
+	for {
+		nc, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &conn{
+			s:        s,
+			c:        nc,
+			enc:      json.NewEncoder(nc),
+			monitors: make(map[string]*monitor),
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = nc.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.serve()
+
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// A conn is a single client connection to a Server.
+type conn struct {
+	s *Server
+	c net.Conn
+
+	encMu sync.Mutex
+	enc   *json.Encoder
+
+	// Monitors created on this connection, keyed by the JSON encoding of
+	// their IDs.
+	mu       sync.Mutex
+	monitors map[string]*monitor
+}
+
+// A monitor is a monitor created by a client.
+type monitor struct {
+	method string
+	db     string
+	id     json.RawMessage
+}
+
+// A request is a JSON-RPC request sent by a client.
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// A response is a JSON-RPC response sent to a client.
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// A notification is a JSON-RPC notification sent to a client.
+type notification struct {
+	ID     interface{} `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+// serve handles requests until the connection is closed.
+func (c *conn) serve() {
+	defer c.c.Close()
+
+	dec := json.NewDecoder(c.c)
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			if err != io.EOF && !isClosed(err) {
+				// Invalid JSON; nothing else can be read.
+				_ = c.c.Close()
+			}
+
+			return
+		}
+
+		// A reply to a notification sent by the server, such as echo.
+		if req.Method == "" {
+			continue
+		}
+
+		params, _ := json.Marshal(req.Params)
+		result, err := c.handle(req.Method, req.Params, params)
+
+		res := response{
+			ID:     req.ID,
+			Result: result,
+		}
+		if err != nil {
+			res.Result = nil
+			res.Error = err.Error()
+		}
+
+		if err := c.write(res); err != nil {
+			return
+		}
+	}
+}
+
+// write encodes v to the connection.
+func (c *conn) write(v interface{}) error {
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+
+	return c.enc.Encode(v)
+}
+
+// handle handles a single RPC.
+func (c *conn) handle(method string, args []json.RawMessage, params json.RawMessage) (interface{}, error) {
+	// Track monitors regardless of the handler used.
+	switch method {
+	case "monitor", "monitor_cond", "monitor_cond_since":
+		if err := c.addMonitor(method, args); err != nil {
+			return nil, err
+		}
+	case "monitor_cancel":
+		if len(args) == 1 {
+			c.mu.Lock()
+			delete(c.monitors, string(args[0]))
+			c.mu.Unlock()
+		}
+	}
+
+	c.s.mu.Lock()
+	fn, ok := c.s.handlers[method]
+	transact := c.s.transact
+	c.s.mu.Unlock()
+
+	if ok {
+		return fn(params)
+	}
+
+	switch method {
+	case "echo":
+		return args, nil
+	case "list_dbs":
+		return c.s.listDatabases(), nil
+	case "get_schema":
+		return c.s.getSchema(args)
+	case "transact":
+		return doTransact(transact, args)
+	case "monitor", "monitor_cond":
+		return struct{}{}, nil
+	case "monitor_cond_since":
+		return []interface{}{false, ovsdb.ZeroTransactionID, struct{}{}}, nil
+	case "lock", "steal":
+		return map[string]bool{"locked": true}, nil
+	case "monitor_cancel", "unlock", "set_db_change_aware":
+		return struct{}{}, nil
+	default:
+		return nil, fmt.Errorf("unknown method: %q", method)
+	}
+}
+
+// addMonitor records a monitor created with the params of a monitor RPC.
+func (c *conn) addMonitor(method string, args []json.RawMessage) error {
+	if len(args) < 2 {
+		return fmt.Errorf("invalid %s parameters", method)
+	}
+
+	var db string
+	if err := json.Unmarshal(args[0], &db); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.monitors[string(args[1])] = &monitor{
+		method: method,
+		db:     db,
+		id:     args[1],
+	}
+
+	return nil
+}
+
+// listDatabases returns the names of the Server's databases.
+func (s *Server) listDatabases() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dbs := make([]string, 0, len(s.schemas))
+	for name := range s.schemas {
+		dbs = append(dbs, name)
+	}
+
+	sort.Strings(dbs)
+	return dbs
+}
+
+// getSchema returns the schema of the database named in args.
+func (s *Server) getSchema(args []json.RawMessage) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, errors.New("invalid get_schema parameters")
+	}
+
+	var db string
+	if err := json.Unmarshal(args[0], &db); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schema, ok := s.schemas[db]
+	if !ok {
+		return &ovsdb.Error{
+			Err:     "unknown database",
+			Details: fmt.Sprintf("database %s not in schema", db),
+		}, nil
+	}
+
+	return schema, nil
+}
+
+// doTransact handles a transaction using fn.
+func doTransact(fn TransactFunc, args []json.RawMessage) (interface{}, error) {
+	if fn == nil {
+		return nil, errors.New("transactions are not handled by this server")
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("invalid transact parameters")
+	}
+
+	var db string
+	if err := json.Unmarshal(args[0], &db); err != nil {
+		return nil, err
+	}
+
+	ops := make([]map[string]interface{}, 0, len(args)-1)
+	for _, a := range args[1:] {
+		var op map[string]interface{}
+		if err := json.Unmarshal(a, &op); err != nil {
+			return nil, err
+		}
+
+		ops = append(ops, op)
+	}
+
+	results, err := fn(db, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		out = append(out, encodeResult(r))
+	}
+
+	return out, nil
+}
+
+// encodeResult returns the JSON representation of an OperationResult.
+func encodeResult(r ovsdb.OperationResult) interface{} {
+	if r.Error != nil {
+		return r.Error
+	}
+
+	out := make(map[string]interface{})
+	if r.Count != 0 {
+		out["count"] = r.Count
+	}
+	if r.UUID != "" {
+		out["uuid"] = []string{"uuid", r.UUID}
+	}
+	if r.Rows != nil {
+		out["rows"] = r.Rows
+	}
+
+	return out
+}
+
+// isClosed reports whether err was caused by a closed connection.
+func isClosed(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
@@ -0,0 +1,341 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdbtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/ovsdbtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServerSchemas(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	want := &ovsdb.Schema{
+		Name:    "Open_vSwitch",
+		Version: "1.0.0",
+		Tables: map[string]ovsdb.TableSchema{
+			"Bridge": {
+				Columns: map[string]ovsdb.ColumnSchema{
+					"name": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{Type: "string"},
+							Min: 1,
+							Max: 1,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.AddSchema(want)
+	s.AddSchema(&ovsdb.Schema{Name: "_Server"})
+
+	dbs, err := c.ListDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"Open_vSwitch", "_Server"}, dbs); diff != "" {
+		t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+	}
+
+	got, err := c.GetSchema(context.Background(), "Open_vSwitch")
+	if err != nil {
+		t.Fatalf("failed to get schema: %v", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected schema (-want +got):\n%s", diff)
+	}
+
+	if _, err := c.GetSchema(context.Background(), "foo"); err == nil {
+		t.Fatal("expected an error for an unknown database")
+	}
+}
+
+func TestServerTransact(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	s.HandleTransact(func(db string, ops []map[string]interface{}) ([]ovsdb.OperationResult, error) {
+		if diff := cmp.Diff("Open_vSwitch", db); diff != "" {
+			panicf("unexpected database (-want +got):\n%s", diff)
+		}
+
+		want := []map[string]interface{}{
+			{
+				"op":    "insert",
+				"table": "Bridge",
+				"row":   map[string]interface{}{"name": "br0"},
+			},
+			{
+				"op":    "select",
+				"table": "Bridge",
+				"where": []interface{}{},
+			},
+		}
+
+		if diff := cmp.Diff(want, ops); diff != "" {
+			panicf("unexpected operations (-want +got):\n%s", diff)
+		}
+
+		return []ovsdb.OperationResult{
+			{UUID: "2f77b348-9768-4866-b761-89d5177ecda0"},
+			{Rows: []ovsdb.Row{{"name": "br0"}}},
+		}, nil
+	})
+
+	got, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+		ovsdb.Select{Table: "Bridge"},
+	})
+	if err != nil {
+		t.Fatalf("failed to transact: %v", err)
+	}
+
+	want := []ovsdb.OperationResult{
+		{UUID: "2f77b348-9768-4866-b761-89d5177ecda0"},
+		{Rows: []ovsdb.Row{{"name": "br0"}}},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerTransactError(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	s.HandleTransact(func(_ string, _ []map[string]interface{}) ([]ovsdb.OperationResult, error) {
+		return []ovsdb.OperationResult{{
+			Error: &ovsdb.Error{
+				Err:     "constraint violation",
+				Details: "duplicate name",
+			},
+		}}, nil
+	})
+
+	_, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{
+			Table: "Bridge",
+			Row:   ovsdb.Row{"name": "br0"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an operation error")
+	}
+}
+
+func TestServerMonitor(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {Columns: []string{"name"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(1, s.Monitors()); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+
+	want := ovsdb.TableUpdates{
+		"Bridge": {
+			"2f77b348-9768-4866-b761-89d5177ecda0": {
+				New: ovsdb.Row{"name": "br0"},
+			},
+		},
+	}
+
+	// Updates for other databases are not delivered.
+	if err := s.Update("OVN_Northbound", ovsdb.TableUpdates{"Logical_Switch": {}}); err != nil {
+		t.Fatalf("failed to send update: %v", err)
+	}
+	if err := s.Update("Open_vSwitch", want); err != nil {
+		t.Fatalf("failed to send update: %v", err)
+	}
+
+	select {
+	case got := <-m.Updates():
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected update (-want +got):\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+
+	if err := m.Cancel(context.Background()); err != nil {
+		t.Fatalf("failed to cancel monitor: %v", err)
+	}
+
+	if diff := cmp.Diff(0, s.Monitors()); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerMonitorCond(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	requests := map[string]ovsdb.MonitorCondRequest{
+		"Bridge": {Columns: []string{"name"}},
+	}
+
+	m2, err := c.MonitorCond(context.Background(), "Open_vSwitch", requests)
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	m3, err := c.MonitorCondSince(context.Background(), "Open_vSwitch", requests, "")
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	want := ovsdb.TableUpdates2{
+		"Bridge": {
+			"2f77b348-9768-4866-b761-89d5177ecda0": {
+				Insert: ovsdb.Row{"name": "br0"},
+			},
+		},
+	}
+
+	const txnID = "9c1d4f1b-4fdd-4bde-82f7-3d0e34b2fb3d"
+	if err := s.Update2("Open_vSwitch", want, txnID); err != nil {
+		t.Fatalf("failed to send update: %v", err)
+	}
+
+	for _, ch := range []<-chan ovsdb.TableUpdates2{m2.Updates(), m3.Updates()} {
+		select {
+		case got := <-ch:
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected update (-want +got):\n%s", diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for update")
+		}
+	}
+
+	if diff := cmp.Diff(txnID, m3.LastTransactionID()); diff != "" {
+		t.Fatalf("unexpected transaction ID (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerCancelMonitors(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	m, err := c.Monitor(context.Background(), "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	if err := s.CancelMonitors("Open_vSwitch"); err != nil {
+		t.Fatalf("failed to cancel monitors: %v", err)
+	}
+
+	select {
+	case _, ok := <-m.Updates():
+		if ok {
+			t.Fatal("expected updates channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for monitor to be canceled")
+	}
+
+	if diff := cmp.Diff(0, s.Monitors()); diff != "" {
+		t.Fatalf("unexpected number of monitors (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerHandle(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	s.Handle("list_dbs", func(params json.RawMessage) (interface{}, error) {
+		if diff := cmp.Diff(`[]`, string(params)); diff != "" {
+			panicf("unexpected parameters (-want +got):\n%s", diff)
+		}
+
+		return []string{"foo"}, nil
+	})
+
+	dbs, err := c.ListDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"foo"}, dbs); diff != "" {
+		t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+	s, c := testServer(t)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.Transact(context.Background(), "Open_vSwitch", nil); err == nil {
+		t.Fatal("expected an error for an unhandled transaction")
+	}
+
+	// The connection remains usable after an error.
+	if err := c.Echo(context.Background()); err != nil {
+		t.Fatalf("failed to echo: %v", err)
+	}
+}
+
+func testServer(t *testing.T) (*ovsdbtest.Server, *ovsdb.Client) {
+	t.Helper()
+
+	s, err := ovsdbtest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+
+	return s, c
+}
+
+func panicf(format string, a ...interface{}) {
+	panic(fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// InactivityProbe enables an inactivity probe, which detects a connection to
+// an OVSDB server which is no longer responsive, in the same way as
+// ovsdb-server's inactivity probe for its clients.
+//
+// If nothing is received from the server for the duration d, the Client
+// sends an echo RPC.  If the server does not reply within another d, the
+// connection is closed as if it were lost.  If the Client is configured to
+// reconnect, it then attempts to do so.
+//
+// Unlike EchoInterval, the inactivity probe sends no echo RPCs while other
+// traffic is being received from the server.
+func InactivityProbe(d time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("inactivity probe interval must be greater than zero")
+		}
+
+		c.probeInterval = d
+		return nil
+	}
+}
+
+// received records that a message was received from the server.
+func (c *Client) received() {
+	atomic.StoreInt64(&c.lastRecv, time.Now().UnixNano())
+}
+
+// probeLoop sends echo RPCs when the connection is inactive, and closes the
+// connection if they fail, until ctx is canceled.
+func (c *Client) probeLoop(ctx context.Context) {
+	d := c.probeInterval
+
+	for {
+		// Wait until the connection has been idle for d.
+		last := time.Unix(0, atomic.LoadInt64(&c.lastRecv))
+		if wait := d - time.Since(last); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			continue
+		}
+
+		if c.State() != StateConnected {
+			// Nothing to probe while reconnecting.
+			c.received()
+			continue
+		}
+
+		conn := c.conn()
+
+		pctx, cancel := context.WithTimeout(ctx, d)
+		err := c.Echo(pctx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil && err != ErrDisconnected {
+			// The server did not reply in time, so treat the connection as
+			// lost.  The receive loop handles the resulting error.
+			_ = conn.Close()
+		}
+
+		// Wait a full interval before probing again.
+		c.received()
+	}
+}
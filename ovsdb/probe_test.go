@@ -0,0 +1,97 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientInactivityProbe(t *testing.T) {
+	// The first echo is never answered, so the connection appears to have
+	// stopped responding.
+	unblock := make(chan struct{})
+	var once sync.Once
+
+	echoC := make(chan struct{}, 16)
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("echo", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		echoC <- struct{}{}
+
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			<-unblock
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, req.Params),
+		}
+	})
+	defer d.done()
+	defer close(unblock)
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c := d.client(
+		ovsdb.InactivityProbe(20*time.Millisecond),
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	defer c.Close()
+
+	// The probe is sent once the connection is idle, and the unanswered
+	// probe causes the Client to reconnect.
+	<-echoC
+
+	for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+		select {
+		case got := <-stateC:
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected state (-want +got):\n%s", diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for state %s", want)
+		}
+	}
+
+	// Probes continue on the new connection.
+	select {
+	case <-echoC:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for probe on new connection")
+	}
+}
+
+func TestInactivityProbeInvalid(t *testing.T) {
+	conn, _, done := jsonrpc.TestNetConn(t, nil)
+	defer done()
+
+	if _, err := ovsdb.New(conn, ovsdb.InactivityProbe(0)); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+)
+
+// A ConnState is the state of a Client's connection to an OVSDB server.
+type ConnState int
+
+// Possible ConnState values.
+const (
+	// The Client is connected and able to perform RPCs.
+	StateConnected ConnState = iota
+
+	// The Client's connection was lost.  If the Client is configured to
+	// reconnect, it is attempting to do so.
+	StateDisconnected
+
+	// The Client was closed.
+	StateClosed
+)
+
+// String returns the string representation of a ConnState.
+func (s ConnState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrDisconnected is returned by RPCs which were in progress when a Client's
+// connection to an OVSDB server was lost, or which are performed before the
+// Client reconnects.
+var ErrDisconnected = errors.New("connection to OVSDB server lost")
+
+// Reconnect enables automatic reconnection when a Client's connection to an
+// OVSDB server is lost.  The Client waits between reconnection attempts
+// using exponential backoff, beginning at min and doubling after each failed
+// attempt, up to max.
+//
+// After reconnecting, the Client requests any locks it held or was waiting
+// for, and re-establishes all active monitors.  Monitors created with
+// Client.MonitorCondSince resume from their last transaction ID, so only the
+// changes which occurred while disconnected are delivered on their updates
+// channels.  Other monitors deliver the full contents of the monitored
+// tables on their updates channels.
+//
+// RPCs which are in progress when the connection is lost, or which are
+// performed while reconnecting, fail with ErrDisconnected.
+//
+// Reconnect may be used with Dial, which will dial the same network and
+// address again.  When used with New or NewTransport, the DialFunc or
+// DialTransport option must also be used.
+func Reconnect(min, max time.Duration) OptionFunc {
+	return func(c *Client) error {
+		if min <= 0 || max < min {
+			return errors.New("invalid reconnect backoff durations")
+		}
+
+		c.reconnect = true
+		c.backoffMin, c.backoffMax = min, max
+		return nil
+	}
+}
+
+// DialFunc specifies a function which is used to dial a new connection to an
+// OVSDB server when the Client must reconnect.
+func DialFunc(fn func() (net.Conn, error)) OptionFunc {
+	return func(c *Client) error {
+		c.dial = func() (Transport, error) {
+			return fn()
+		}
+		return nil
+	}
+}
+
+// OnStateChange specifies a function which is called each time the state of
+// the Client's connection changes.
+//
+// When reconnecting, fn is called with StateConnected before any locks are
+// requested or monitors are re-established, and may perform RPCs.
+func OnStateChange(fn func(s ConnState)) OptionFunc {
+	return func(c *Client) error {
+		c.onState = fn
+		return nil
+	}
+}
+
+// OnDisconnect specifies a function which is called each time the Client's
+// connection is lost, after any RPCs which were in progress have failed with
+// ErrDisconnected.
+//
+// fn is called before the Client attempts to reconnect, and must not block.
+func OnDisconnect(fn func()) OptionFunc {
+	return func(c *Client) error {
+		c.onDisconnect = fn
+		return nil
+	}
+}
+
+// OnReconnect specifies a function which is called each time the Client
+// reconnects, after any locks have been requested and monitors
+// re-established.  fn may perform RPCs, and may be used to synchronize the
+// contents of a database with the caller's desired state.
+//
+// ctx is canceled when the Client is closed.  fn is not called for the
+// Client's initial connection.
+func OnReconnect(fn func(ctx context.Context)) OptionFunc {
+	return func(c *Client) error {
+		c.onReconnect = fn
+		return nil
+	}
+}
+
+// State returns the current state of the Client's connection.
+func (c *Client) State() ConnState {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	return c.state
+}
+
+// setState updates the Client's state, and reports any change to the
+// caller's hook.
+func (c *Client) setState(s ConnState) {
+	c.stateMu.Lock()
+	changed := c.state != s
+	c.state = s
+	fn := c.onState
+	c.stateMu.Unlock()
+
+	if s == StateConnected && c.remotes != nil {
+		c.remotes.connected()
+	}
+
+	if changed && fn != nil {
+		fn(s)
+	}
+}
+
+// disconnected handles the loss of the Client's connection.
+func (c *Client) disconnected() {
+	c.setState(StateDisconnected)
+
+	// Locks are released by the server when a connection is lost.
+	c.releaseLocks()
+
+	// No responses will arrive for any in-flight RPCs.
+	c.failCallbacks(ErrDisconnected)
+
+	if c.onDisconnect != nil {
+		c.onDisconnect()
+	}
+}
+
+// redial dials new connections with exponential backoff until one succeeds
+// or ctx is canceled.  It reports whether a new connection was established.
+func (c *Client) redial(ctx context.Context) bool {
+	d := c.backoffMin
+
+	for {
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return false
+		case <-t.C:
+		}
+
+		if d *= 2; d > c.backoffMax {
+			d = c.backoffMax
+		}
+
+		conn, err := c.dial()
+		if err != nil {
+			continue
+		}
+
+		c.connMu.Lock()
+		if c.closed {
+			// Client closed while dialing.
+			c.connMu.Unlock()
+			_ = conn.Close()
+			return false
+		}
+
+		c.c = c.newConn(conn)
+		c.connMu.Unlock()
+		c.received()
+
+		break
+	}
+
+	c.reconnected()
+
+	conn := c.conn()
+
+	// Hold any updates for existing monitors until they are re-established,
+	// so that they are delivered in order.
+	c.monMu.RLock()
+	for _, h := range c.monitors {
+		h.base().startResume()
+	}
+	c.monMu.RUnlock()
+
+	// Resuming requires performing RPCs, which requires this goroutine to
+	// continue receiving responses.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.resume(ctx, conn)
+	}()
+
+	return true
+}
+
+// resume restores the Client's state after reconnecting using conn.
+func (c *Client) resume(ctx context.Context, conn *jsonrpc.Conn) {
+	if c.leaderDB != "" {
+		if err := c.checkLeader(ctx); err != nil {
+			// Look for the leader using another connection.
+			_ = conn.Close()
+			return
+		}
+	}
+
+	c.setState(StateConnected)
+
+	if c.isChangeAware() {
+		// Errors are ignored: resume is called again after the next reconnect.
+		_ = c.rpc(ctx, "set_db_change_aware", nil, []bool{true})
+	}
+
+	for _, id := range c.lockIDs() {
+		// Errors are ignored: if the connection was lost again, resume is
+		// called again after the next reconnect.
+		var res lockResult
+		if err := c.rpc(ctx, "lock", &res, []string{id}); err == nil && res.Locked {
+			c.setLock(id, LockAcquired)
+		}
+	}
+
+	c.monMu.RLock()
+	hs := make([]monitorHandler, 0, len(c.monitors))
+	for _, h := range c.monitors {
+		hs = append(hs, h)
+	}
+	c.monMu.RUnlock()
+
+	for _, h := range hs {
+		_ = h.resume(ctx)
+	}
+
+	if c.onReconnect != nil {
+		c.onReconnect(ctx)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientReconnect(t *testing.T) {
+	const uuid = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+
+	// Record the RPCs issued to each connection.
+	rpcC := make(chan string, 16)
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		rpcC <- req.Method
+
+		var res interface{} = struct{}{}
+		switch req.Method {
+		case "lock":
+			res = map[string]bool{"locked": true}
+		case "monitor":
+			res = ovsdb.TableUpdates{
+				"Bridge": {
+					uuid: {New: ovsdb.Row{"name": "br0"}},
+				},
+			}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+	defer d.done()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c := d.client(
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+
+	ctx := context.Background()
+
+	if _, err := c.Lock(ctx, "foo"); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+
+	m, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	})
+	if err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	for _, want := range []string{"lock", "monitor"} {
+		if diff := cmp.Diff(want, <-rpcC); diff != "" {
+			t.Fatalf("unexpected initial RPC (-want +got):\n%s", diff)
+		}
+	}
+
+	// Break the first connection and expect the client to reconnect.
+	d.drop()
+
+	for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+		if diff := cmp.Diff(want, <-stateC); diff != "" {
+			t.Fatalf("unexpected state (-want +got):\n%s", diff)
+		}
+	}
+
+	// The lock and monitor should be re-established on the new connection.
+	for _, want := range []string{"lock", "monitor"} {
+		if diff := cmp.Diff(want, <-rpcC); diff != "" {
+			t.Fatalf("unexpected resumed RPC (-want +got):\n%s", diff)
+		}
+	}
+
+	// The monitor's contents are delivered again after reconnecting.
+	want := ovsdb.TableUpdates{
+		"Bridge": {
+			uuid: {New: ovsdb.Row{"name": "br0"}},
+		},
+	}
+
+	if diff := cmp.Diff(want, <-m.Updates()); diff != "" {
+		t.Fatalf("unexpected updates after reconnect (-want +got):\n%s", diff)
+	}
+
+	// The lock is lost with the connection, and acquired again afterward.
+	wantEvents := []ovsdb.LockEvent{
+		{ID: "foo", State: ovsdb.LockAcquired},
+		{ID: "foo", State: ovsdb.LockReleased},
+		{ID: "foo", State: ovsdb.LockAcquired},
+	}
+
+	if diff := cmp.Diff(wantEvents, lockEvents(t, c, len(wantEvents))); diff != "" {
+		t.Fatalf("unexpected lock events (-want +got):\n%s", diff)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.StateClosed, c.State()); diff != "" {
+		t.Fatalf("unexpected final state (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientReconnectInFlightRPC(t *testing.T) {
+	// Hold the first RPC until the connection is dropped.
+	unblock := make(chan struct{})
+
+	var once sync.Once
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		once.Do(func() {
+			<-unblock
+		})
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer d.done()
+
+	c := d.client(ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond))
+	defer c.Close()
+
+	errC := make(chan error, 1)
+	go func() {
+		_, err := c.ListDatabases(context.Background())
+		errC <- err
+	}()
+
+	// Give the RPC time to be sent before dropping the connection.  The
+	// server's handler is still blocked, so drop the connection in the
+	// background rather than waiting for the handler to return.
+	time.Sleep(50 * time.Millisecond)
+
+	dropped := make(chan struct{})
+	go func() {
+		defer close(dropped)
+		d.drop()
+	}()
+
+	if diff := cmp.Diff(ovsdb.ErrDisconnected, <-errC, cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})); diff != "" {
+		t.Fatalf("unexpected error (-want +got):\n%s", diff)
+	}
+
+	close(unblock)
+	<-dropped
+
+	// RPCs on the new connection should succeed.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for {
+		if _, err := c.ListDatabases(ctx); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatal("timed out waiting for reconnect")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClientReconnectDecodeError(t *testing.T) {
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer d.done()
+
+	// The first connection sends invalid JSON, but is never closed.
+	client, server := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		_, _ = server.Write([]byte("}{"))
+	}()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c, err := ovsdb.New(client,
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.DialFunc(d.dial),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+		if diff := cmp.Diff(want, <-stateC); diff != "" {
+			t.Fatalf("unexpected state (-want +got):\n%s", diff)
+		}
+	}
+
+	if _, err := c.ListDatabases(context.Background()); err != nil {
+		t.Fatalf("failed to list databases after reconnect: %v", err)
+	}
+}
+
+func TestClientReconnectCallbacks(t *testing.T) {
+	rpcC := make(chan string, 16)
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		rpcC <- req.Method
+
+		var res interface{} = struct{}{}
+		switch req.Method {
+		case "list_dbs":
+			res = []string{"Open_vSwitch"}
+		case "lock":
+			res = map[string]bool{"locked": true}
+		case "monitor":
+			res = ovsdb.TableUpdates{}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, res),
+		}
+	})
+	defer d.done()
+
+	eventC := make(chan string, 8)
+
+	var c *ovsdb.Client
+	c = d.client(
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.OnDisconnect(func() {
+			eventC <- "disconnect"
+		}),
+		ovsdb.OnReconnect(func(ctx context.Context) {
+			// RPCs may be performed from the callback.
+			if _, err := c.ListDatabases(ctx); err != nil {
+				panicf("failed to list databases: %v", err)
+			}
+
+			eventC <- "reconnect"
+		}),
+	)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	if _, err := c.Lock(ctx, "foo"); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+
+	if _, err := c.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+		"Bridge": {},
+	}); err != nil {
+		t.Fatalf("failed to monitor: %v", err)
+	}
+
+	for range []string{"lock", "monitor"} {
+		<-rpcC
+	}
+
+	d.drop()
+
+	for _, want := range []string{"disconnect", "reconnect"} {
+		if diff := cmp.Diff(want, <-eventC); diff != "" {
+			t.Fatalf("unexpected event (-want +got):\n%s", diff)
+		}
+	}
+
+	// The callback's RPC is performed only after the lock and monitor are
+	// re-established.
+	for _, want := range []string{"lock", "monitor", "list_dbs"} {
+		if diff := cmp.Diff(want, <-rpcC); diff != "" {
+			t.Fatalf("unexpected RPC (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestNewReconnectNoDialFunc(t *testing.T) {
+	conn, _, done := jsonrpc.TestNetConn(t, nil)
+	defer done()
+
+	_, err := ovsdb.New(conn, ovsdb.Reconnect(time.Second, time.Second))
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// A testDialer dials connections to test servers which use the same TestFunc.
+type testDialer struct {
+	t  *testing.T
+	fn jsonrpc.TestFunc
+
+	mu     sync.Mutex
+	dones  []func()
+	notifs []chan<- *jsonrpc.Response
+}
+
+func newTestDialer(t *testing.T, fn jsonrpc.TestFunc) *testDialer {
+	return &testDialer{
+		t:  t,
+		fn: fn,
+	}
+}
+
+// dial dials a new connection to a test server.
+func (d *testDialer) dial() (net.Conn, error) {
+	conn, notifC, done := jsonrpc.TestNetConn(d.t, d.fn)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dones = append(d.dones, done)
+	d.notifs = append(d.notifs, notifC)
+
+	return conn, nil
+}
+
+// client creates a Client using the testDialer.
+func (d *testDialer) client(options ...ovsdb.OptionFunc) *ovsdb.Client {
+	d.t.Helper()
+
+	conn, _ := d.dial()
+
+	c, err := ovsdb.New(conn, append(options, ovsdb.DialFunc(d.dial))...)
+	if err != nil {
+		d.t.Fatalf("failed to create client: %v", err)
+	}
+
+	return c
+}
+
+// notify sends a notification on the most recently dialed connection.
+func (d *testDialer) notify(n *jsonrpc.Response) {
+	d.mu.Lock()
+	notifC := d.notifs[len(d.notifs)-1]
+	d.mu.Unlock()
+
+	notifC <- n
+}
+
+// drop closes the oldest open test connection.
+func (d *testDialer) drop() {
+	d.mu.Lock()
+	done := d.dones[0]
+	d.dones = d.dones[1:]
+	d.mu.Unlock()
+
+	done()
+}
+
+// done closes all open test connections.
+func (d *testDialer) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, done := range d.dones {
+		done()
+	}
+}
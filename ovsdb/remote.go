@@ -0,0 +1,311 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// DefaultPort is the port used by OVSDB servers, when a tcp: or ssl: remote
+// does not specify a port.
+const DefaultPort = "6640"
+
+// DialRemote dials a connection to an OVSDB server using a remote string, as
+// accepted by the --remote flag of ovs-vsctl and other OVSDB clients, and
+// returns a Client.  The supported remotes are:
+//
+//   - tcp:host[:port]: a TCP connection
+//   - ssl:host[:port]: a TLS connection, which requires the TLSConfig option
+//   - unix:file: a UNIX domain socket connection
+//
+// IPv6 addresses must be enclosed in square brackets, such as
+// "tcp:[::1]:6640".  If a port is not specified, DefaultPort is used.
+//
+// remote may also be a comma-separated list of remotes, such as the
+// members of an OVSDB cluster: "tcp:10.0.0.1:6641,tcp:10.0.0.2:6641".  The
+// remotes are dialed in order until a connection is established, skipping
+// servers which are not the leader if the LeaderOnly option is used.  With
+// the Reconnect option, the Client fails over by dialing the next remote in
+// the list when its connection is lost, unless the PreferLastRemote option
+// is used.
+func DialRemote(remote string, options ...OptionFunc) (*Client, error) {
+	remotes, err := parseRemotes(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		errs      []string
+		notLeader = true
+	)
+
+	for i, r := range remotes {
+		c, err := newClient(options)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.checkTLS(c.tlsConfig); err != nil {
+			return nil, err
+		}
+
+		d := &remoteDialer{
+			remotes:   remotes,
+			tlsConfig: c.tlsConfig,
+			prefer:    c.preferRemote,
+			current:   i,
+			preferred: -1,
+		}
+
+		err = c.startRemote(d, i)
+		if err == nil {
+			return c, nil
+		}
+
+		if len(remotes) == 1 {
+			return nil, err
+		}
+
+		errs = append(errs, fmt.Sprintf("%s: %v", r.remote, err))
+		notLeader = notLeader && err == ErrNotLeader
+	}
+
+	if notLeader {
+		return nil, ErrNotLeader
+	}
+
+	return nil, fmt.Errorf("failed to connect to any remote: %s", strings.Join(errs, "; "))
+}
+
+// PreferLastRemote specifies that a Client created by DialRemote with a list
+// of remotes should first dial the remote it was last connected to when
+// reconnecting, rather than failing over to the next remote in the list.
+// If that remote cannot be dialed, or is not the leader when the LeaderOnly
+// option is used, the other remotes are dialed in order.
+func PreferLastRemote() OptionFunc {
+	return func(c *Client) error {
+		c.preferRemote = true
+		return nil
+	}
+}
+
+// Remote returns the remote to which a Client created by DialRemote is
+// connected, or was last connected.  It returns the empty string for Clients
+// created in other ways.
+func (c *Client) Remote() string {
+	if c.remotes == nil {
+		return ""
+	}
+
+	return c.remotes.remote()
+}
+
+// startRemote dials the remote with index i using d, and starts the Client.
+func (c *Client) startRemote(d *remoteDialer, i int) error {
+	conn, err := d.remotes[i].dial(d.tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	// Dial the remotes again if the Client must reconnect, unless the
+	// caller has specified otherwise.
+	if c.dial == nil {
+		c.dial = d.dial
+	}
+	c.remotes = d
+
+	if err := c.start(conn); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	// With the Reconnect option, a Client which is not connected to the
+	// leader looks for it in the background.
+	if c.State() == StateConnected {
+		d.connected()
+	}
+
+	return nil
+}
+
+// An ovsdbRemote is a parsed OVSDB remote string.
+type ovsdbRemote struct {
+	remote, network, addr string
+	ssl                   bool
+}
+
+// parseRemotes parses a comma-separated list of OVSDB remote strings.
+func parseRemotes(s string) ([]ovsdbRemote, error) {
+	var remotes []ovsdbRemote
+	for _, remote := range strings.Split(s, ",") {
+		remote = strings.TrimSpace(remote)
+
+		network, addr, ssl, err := parseRemote(remote)
+		if err != nil {
+			return nil, err
+		}
+
+		remotes = append(remotes, ovsdbRemote{
+			remote:  remote,
+			network: network,
+			addr:    addr,
+			ssl:     ssl,
+		})
+	}
+
+	return remotes, nil
+}
+
+// checkTLS verifies that a TLS configuration is specified only for ssl:
+// remotes.
+func (r ovsdbRemote) checkTLS(cfg *tls.Config) error {
+	switch {
+	case r.ssl && cfg == nil:
+		return fmt.Errorf("remote %q requires the TLSConfig option", r.remote)
+	case !r.ssl && cfg != nil:
+		return fmt.Errorf("TLSConfig option requires an ssl: remote, but got %q", r.remote)
+	default:
+		return nil
+	}
+}
+
+// dial dials a connection to the remote.
+func (r ovsdbRemote) dial(cfg *tls.Config) (Transport, error) {
+	if r.ssl {
+		return tls.Dial(r.network, r.addr, cfg)
+	}
+
+	return net.Dial(r.network, r.addr)
+}
+
+// A remoteDialer dials connections to a list of remotes, failing over to the
+// next remote when a connection is lost.
+type remoteDialer struct {
+	remotes   []ovsdbRemote
+	tlsConfig *tls.Config
+	prefer    bool
+
+	// mu protects the index of the remote for the current connection, and
+	// the index of the remote which should be dialed first, or -1.
+	mu        sync.Mutex
+	current   int
+	preferred int
+}
+
+// dial dials the remotes in order until a connection is established.
+func (d *remoteDialer) dial() (Transport, error) {
+	d.mu.Lock()
+	start := (d.current + 1) % len(d.remotes)
+	if d.preferred >= 0 {
+		// Prefer the last remote only once, so that another remote is
+		// dialed if it is no longer usable.
+		start = d.preferred
+		d.preferred = -1
+	}
+	d.mu.Unlock()
+
+	var err error
+	for i := 0; i < len(d.remotes); i++ {
+		n := (start + i) % len(d.remotes)
+
+		var conn Transport
+		conn, err = d.remotes[n].dial(d.tlsConfig)
+		if err != nil {
+			continue
+		}
+
+		d.mu.Lock()
+		d.current = n
+		d.mu.Unlock()
+
+		return conn, nil
+	}
+
+	return nil, err
+}
+
+// connected records that the Client is connected and usable on its current
+// connection.
+func (d *remoteDialer) connected() {
+	if !d.prefer {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.preferred = d.current
+}
+
+// remote returns the remote for the current connection.
+func (d *remoteDialer) remote() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.remotes[d.current].remote
+}
+
+// parseRemote parses an OVSDB remote string into a network and address for
+// dialing, and reports whether the remote uses TLS.
+func parseRemote(remote string) (network, addr string, ssl bool, err error) {
+	ss := strings.SplitN(remote, ":", 2)
+	if len(ss) != 2 || ss[1] == "" {
+		return "", "", false, fmt.Errorf("invalid remote %q", remote)
+	}
+
+	method, target := ss[0], ss[1]
+
+	switch method {
+	case "unix":
+		return "unix", target, false, nil
+	case "tcp", "ssl":
+		addr, err := hostPort(target)
+		if err != nil {
+			return "", "", false, fmt.Errorf("invalid remote %q: %v", remote, err)
+		}
+
+		return "tcp", addr, method == "ssl", nil
+	case "ptcp", "pssl", "punix":
+		return "", "", false, fmt.Errorf("passive remote %q cannot be dialed", remote)
+	default:
+		return "", "", false, fmt.Errorf("unknown method for remote %q", remote)
+	}
+}
+
+// hostPort parses a host with an optional port, adding DefaultPort if needed.
+func hostPort(s string) (string, error) {
+	// A bracketed IPv6 address or host without a colon has no port.
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return net.JoinHostPort(s[1:len(s)-1], DefaultPort), nil
+	}
+	if !strings.Contains(s, ":") {
+		return net.JoinHostPort(s, DefaultPort), nil
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", err
+	}
+	if host == "" || port == "" {
+		return "", errors.New("host and port must not be empty")
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
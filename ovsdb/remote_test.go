@@ -0,0 +1,413 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDialRemote(t *testing.T) {
+	cert, pool := testCertificate(t)
+
+	dir, err := ioutil.TempDir("", "ovsdb-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		listen  func(t *testing.T) net.Listener
+		remote  func(addr string) string
+		options []ovsdb.OptionFunc
+	}{
+		{
+			name: "tcp",
+			listen: func(t *testing.T) net.Listener {
+				return mustListen(t, "tcp", "127.0.0.1:0")
+			},
+			remote: func(addr string) string {
+				return "tcp:" + addr
+			},
+		},
+		{
+			name: "tcp IPv6",
+			listen: func(t *testing.T) net.Listener {
+				l, err := net.Listen("tcp", "[::1]:0")
+				if err != nil {
+					t.Skipf("skipping, IPv6 loopback unavailable: %v", err)
+				}
+
+				return l
+			},
+			remote: func(addr string) string {
+				return "tcp:" + addr
+			},
+		},
+		{
+			name: "ssl",
+			listen: func(t *testing.T) net.Listener {
+				return tls.NewListener(mustListen(t, "tcp", "127.0.0.1:0"), &tls.Config{
+					Certificates: []tls.Certificate{cert},
+				})
+			},
+			remote: func(addr string) string {
+				return "ssl:" + addr
+			},
+			options: []ovsdb.OptionFunc{
+				ovsdb.TLSConfig(&tls.Config{RootCAs: pool}),
+			},
+		},
+		{
+			name: "unix",
+			listen: func(t *testing.T) net.Listener {
+				return mustListen(t, "unix", filepath.Join(dir, "db.sock"))
+			},
+			remote: func(addr string) string {
+				return "unix:" + addr
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []string{"Open_vSwitch"}
+
+			addr, done := testServe(t, tt.listen(t), want)
+			defer done()
+
+			c, err := ovsdb.DialRemote(tt.remote(addr), tt.options...)
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			dbs, err := c.ListDatabases(ctx)
+			if err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			if diff := cmp.Diff(want, dbs); diff != "" {
+				t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDialRemoteInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		options []ovsdb.OptionFunc
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "no method",
+			remote: "/var/run/openvswitch/db.sock",
+		},
+		{
+			name:   "no target",
+			remote: "tcp:",
+		},
+		{
+			name:   "unknown method",
+			remote: "udp:127.0.0.1:6640",
+		},
+		{
+			name:   "passive",
+			remote: "ptcp:6640",
+		},
+		{
+			name:   "bad IPv6",
+			remote: "tcp:::1:6640",
+		},
+		{
+			name:   "empty port",
+			remote: "tcp:127.0.0.1:",
+		},
+		{
+			name:   "ssl without TLS",
+			remote: "ssl:127.0.0.1:6640",
+		},
+		{
+			name:   "tcp with TLS",
+			remote: "tcp:127.0.0.1:6640",
+			options: []ovsdb.OptionFunc{
+				ovsdb.TLSConfig(&tls.Config{}),
+			},
+		},
+		{
+			name:   "invalid list entry",
+			remote: "tcp:127.0.0.1:6640,udp:127.0.0.1:6640",
+		},
+		{
+			name:   "empty list entry",
+			remote: "tcp:127.0.0.1:6640,",
+		},
+		{
+			name:   "list with ssl and tcp",
+			remote: "ssl:127.0.0.1:6640,tcp:127.0.0.1:6641",
+			options: []ovsdb.OptionFunc{
+				ovsdb.TLSConfig(&tls.Config{}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ovsdb.DialRemote(tt.remote, tt.options...); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestDialRemoteList(t *testing.T) {
+	// The first remote is not listening.
+	closed := mustListen(t, "tcp", "127.0.0.1:0")
+	_ = closed.Close()
+
+	s := newTestRemoteServer(t, "b")
+	defer s.done()
+
+	remote := "tcp:" + s.addr()
+	c, err := ovsdb.DialRemote("tcp:" + closed.Addr().String() + ", " + remote)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	if diff := cmp.Diff(remote, c.Remote()); diff != "" {
+		t.Fatalf("unexpected remote (-want +got):\n%s", diff)
+	}
+
+	dbs, err := c.ListDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"b"}, dbs); diff != "" {
+		t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+	}
+}
+
+func TestDialRemoteListUnreachable(t *testing.T) {
+	var remotes []string
+	for i := 0; i < 2; i++ {
+		l := mustListen(t, "tcp", "127.0.0.1:0")
+		_ = l.Close()
+
+		remotes = append(remotes, "tcp:"+l.Addr().String())
+	}
+
+	if _, err := ovsdb.DialRemote(strings.Join(remotes, ",")); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestDialRemoteListFailover(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []ovsdb.OptionFunc
+		want    string
+	}{
+		{
+			name: "next",
+			want: "b",
+		},
+		{
+			name:    "prefer last",
+			options: []ovsdb.OptionFunc{ovsdb.PreferLastRemote()},
+			want:    "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestRemoteServer(t, "a")
+			defer a.done()
+			b := newTestRemoteServer(t, "b")
+			defer b.done()
+
+			stateC := make(chan ovsdb.ConnState, 8)
+
+			options := append([]ovsdb.OptionFunc{
+				ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+				ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+					stateC <- s
+				}),
+			}, tt.options...)
+
+			c, err := ovsdb.DialRemote("tcp:"+a.addr()+",tcp:"+b.addr(), options...)
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer c.Close()
+
+			// Ensure the first server is serving the connection before
+			// dropping it.
+			if _, err := c.ListDatabases(context.Background()); err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			// Both servers are available, so the Client either fails over
+			// to the next remote or returns to the previous one.
+			a.drop()
+
+			for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+				if diff := cmp.Diff(want, <-stateC); diff != "" {
+					t.Fatalf("unexpected state (-want +got):\n%s", diff)
+				}
+			}
+
+			dbs, err := c.ListDatabases(context.Background())
+			if err != nil {
+				t.Fatalf("failed to list databases: %v", err)
+			}
+
+			if diff := cmp.Diff([]string{tt.want}, dbs); diff != "" {
+				t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+			}
+
+			want := "tcp:" + a.addr()
+			if tt.want == "b" {
+				want = "tcp:" + b.addr()
+			}
+
+			if diff := cmp.Diff(want, c.Remote()); diff != "" {
+				t.Fatalf("unexpected remote (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// A testRemoteServer serves any number of connections, replying to every RPC
+// with a list containing its database.
+type testRemoteServer struct {
+	t  *testing.T
+	l  net.Listener
+	db string
+	wg sync.WaitGroup
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newTestRemoteServer(t *testing.T, db string) *testRemoteServer {
+	t.Helper()
+
+	s := &testRemoteServer{
+		t:  t,
+		l:  mustListen(t, "tcp", "127.0.0.1:0"),
+		db: db,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			c, err := s.l.Accept()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.conns = append(s.conns, c)
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(c)
+			}()
+		}
+	}()
+
+	return s
+}
+
+// serve replies to RPCs on c until it is closed.
+func (s *testRemoteServer) serve(c net.Conn) {
+	dec := json.NewDecoder(c)
+	enc := json.NewEncoder(c)
+
+	for {
+		var req jsonrpc.Request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+
+		if err := enc.Encode(jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(s.t, []string{s.db}),
+		}); err != nil {
+			return
+		}
+	}
+}
+
+// addr returns the server's listening address.
+func (s *testRemoteServer) addr() string {
+	return s.l.Addr().String()
+}
+
+// drop closes all connections to the server, but continues listening.
+func (s *testRemoteServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+// done stops the server and closes all of its connections.
+func (s *testRemoteServer) done() {
+	_ = s.l.Close()
+	s.drop()
+	s.wg.Wait()
+}
+
+func mustListen(t *testing.T, network, addr string) net.Listener {
+	t.Helper()
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	return l
+}
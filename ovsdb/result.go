@@ -18,13 +18,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-)
 
-// A result is used to unmarshal JSON-RPC results, and to check for any errors.
-type result struct {
-	Reply interface{}
-	Err   *Error
-}
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+)
 
 // An rpcResponse is a response used in RPC callbacks.
 type rpcResponse struct {
@@ -32,41 +28,91 @@ type rpcResponse struct {
 	Error  error
 }
 
-// rpcResult handles any errors from an rpcResponse and unmarshals results into
-// a result.
-func rpcResult(res rpcResponse, r *result) error {
+// errPrefix is a prefix that occurs if an error is present in a JSON-RPC response.
+var errPrefix = []byte(`{"error":`)
+
+// rpcResult handles any errors from an rpcResponse and unmarshals its result
+// into out using the Client's Codec.
+func (c *Client) rpcResult(res rpcResponse, out interface{}) error {
 	if err := res.Error; err != nil {
 		return err
 	}
 
-	if err := json.Unmarshal(res.Result, &r); err != nil {
-		return err
+	// No error? Return the result.
+	if !bytes.HasPrefix(res.Result, errPrefix) {
+		return c.unmarshal(res.Result, out)
 	}
 
 	// OVSDB server returned an error, return it.
-	if r.Err != nil {
-		return r.Err
+	var e Error
+	if err := json.Unmarshal(res.Result, &e); err != nil {
+		return err
 	}
 
-	return nil
+	return &e
 }
 
-// errPrefix is a prefix that occurs if an error is present in a JSON-RPC response.
-var errPrefix = []byte(`{"error":`)
+// An OperationResult is the result of a single TransactOp within a
+// transaction.  Which fields are set depends on the type of the operation.
+type OperationResult struct {
+	// The number of rows affected by an update, mutate, or delete.
+	Count int
 
-func (r *result) UnmarshalJSON(b []byte) error {
-	// No error? Return the result.
-	if !bytes.HasPrefix(b, errPrefix) {
-		return json.Unmarshal(b, r.Reply)
+	// The UUID of a row created by an insert.
+	UUID string
+
+	// The rows returned by a select.
+	Rows []Row
+
+	// Non-nil if this operation failed.
+	Error *Error
+}
+
+var _ json.Unmarshaler = &OperationResult{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *OperationResult) UnmarshalJSON(b []byte) error {
+	// Operations that follow a failed operation are never executed, and
+	// their results are reported as null.
+	if bytes.Equal(b, []byte("null")) {
+		return nil
 	}
 
-	// Found an error, unmarshal and return it later.
-	var e Error
-	if err := json.Unmarshal(b, &e); err != nil {
+	if bytes.HasPrefix(b, errPrefix) {
+		var e Error
+		if err := json.Unmarshal(b, &e); err != nil {
+			return err
+		}
+
+		r.Error = &e
+		return nil
+	}
+
+	var v struct {
+		Count int             `json:"count"`
+		UUID  json.RawMessage `json:"uuid"`
+		Rows  []Row           `json:"rows"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
 
-	r.Err = &e
+	r.Count = v.Count
+	r.Rows = v.Rows
+
+	if len(v.UUID) > 0 {
+		// UUIDs are encoded as ["uuid", "<uuid>"].
+		var uuid [2]string
+		if err := json.Unmarshal(v.UUID, &uuid); err != nil {
+			return err
+		}
+		if uuid[0] != "uuid" {
+			return fmt.Errorf("invalid UUID type: %q", uuid[0])
+		}
+
+		r.UUID = uuid[1]
+	}
+
 	return nil
 }
 
@@ -83,3 +129,49 @@ type Error struct {
 func (e *Error) Error() string {
 	return fmt.Sprintf("%s: %s: %s", e.Err, e.Details, e.Syntax)
 }
+
+// Is reports whether target is an *Error of the same kind with no details,
+// such as ErrConstraintViolation.  Is enables the use of errors.Is to check
+// the cause of an error returned by an OVSDB server.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return t.Err == e.Err && t.Details == "" && t.Syntax == ""
+}
+
+// Errors which may be returned by an OVSDB server, as described in RFC 7047.
+// An *Error returned by the server
+// matches one of these errors using errors.Is, or its Is method, when its
+// Err field is the same, regardless of its details.
+var (
+	ErrAborted              = &Error{Err: "aborted"}
+	ErrConstraintViolation  = &Error{Err: "constraint violation"}
+	ErrDomain               = &Error{Err: "domain error"}
+	ErrDuplicateUUIDName    = &Error{Err: "duplicate uuid-name"}
+	ErrIO                   = &Error{Err: "I/O error"}
+	ErrNotOwner             = &Error{Err: "not owner"}
+	ErrNotSupported         = &Error{Err: "not supported"}
+	ErrRange                = &Error{Err: "range error"}
+	ErrReferentialIntegrity = &Error{Err: "referential integrity violation"}
+	ErrResourcesExhausted   = &Error{Err: "resources exhausted"}
+	ErrTimedOut             = &Error{Err: "timed out"}
+	ErrUnknownDatabase      = &Error{Err: "unknown database"}
+)
+
+// responseError returns the error in a JSON-RPC response.  Error objects
+// in the form used by OVSDB are returned as an *Error.
+func responseError(res *jsonrpc.Response) error {
+	if m, ok := res.Error.(map[string]interface{}); ok {
+		if kind, ok := m["error"].(string); ok {
+			e := &Error{Err: kind}
+			e.Details, _ = m["details"].(string)
+			e.Syntax, _ = m["syntax"].(string)
+			return e
+		}
+	}
+
+	return res.Err()
+}
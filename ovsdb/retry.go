@@ -0,0 +1,114 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// A RetryPolicy configures how a Client retries idempotent RPCs which fail
+// because of a transient error.
+type RetryPolicy struct {
+	// The maximum number of times an RPC is attempted, including the first
+	// attempt.  Must be at least 1.
+	MaxAttempts int
+
+	// The time to wait before the first retry, which doubles after each
+	// further attempt, up to MaxBackoff if it is nonzero.
+	Backoff, MaxBackoff time.Duration
+
+	// Retryable reports whether an RPC which failed with err should be
+	// retried.  If nil, only RPCs which fail with ErrDisconnected are
+	// retried.
+	Retryable func(err error) bool
+}
+
+// Retry enables retrying idempotent RPCs according to policy.  The RPCs made
+// by Client.ListDatabases and Client.GetSchema are retried, as are those made
+// by Client.Transact when the transaction only contains Select, Wait, and
+// Comment operations.  Other RPCs are never retried, because they may have been
+// applied by the server before the connection was lost.
+//
+// Retrying after a lost connection is useful with the Reconnect option, so
+// that RPCs performed while reconnecting succeed once the Client reconnects.
+func Retry(policy RetryPolicy) OptionFunc {
+	return func(c *Client) error {
+		if policy.MaxAttempts < 1 {
+			return errors.New("retry policy must allow at least one attempt")
+		}
+		if policy.Backoff < 0 || policy.MaxBackoff < 0 {
+			return errors.New("invalid retry backoff durations")
+		}
+
+		c.retry = &policy
+		return nil
+	}
+}
+
+// idempotentRPC performs an RPC which may safely be retried, retrying it
+// according to the Client's RetryPolicy.
+func (c *Client) idempotentRPC(ctx context.Context, method string, out, arg interface{}) error {
+	p := c.retry
+	if p == nil {
+		return c.rpc(ctx, method, out, arg)
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = func(err error) bool {
+			return err == ErrDisconnected
+		}
+	}
+
+	d := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := c.rpc(ctx, method, out, arg)
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			// Report the error which caused the retry.
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+
+		if d *= 2; p.MaxBackoff > 0 && d > p.MaxBackoff {
+			d = p.MaxBackoff
+		}
+	}
+}
+
+// readOnly reports whether ops never modify a database, so that a
+// transaction containing them may be retried, or performed by a Router's
+// replica.  Operations whose type is unknown, such as raw JSON, are assumed
+// to be writes.  Assert is not read-only, as it refers to a lock which is
+// held by a particular Client.
+func readOnly(ops []TransactOp) bool {
+	for _, op := range ops {
+		switch op.(type) {
+		case Select, *Select, Wait, *Wait, Comment, *Comment:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
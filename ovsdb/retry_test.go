@@ -0,0 +1,230 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientRetryReconnect(t *testing.T) {
+	// Hold the first RPC until the connection is dropped.
+	unblock := make(chan struct{})
+
+	var once sync.Once
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		once.Do(func() {
+			<-unblock
+		})
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, []string{"Open_vSwitch"}),
+		}
+	})
+	defer d.done()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c := d.client(
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.Retry(ovsdb.RetryPolicy{
+			MaxAttempts: 20,
+			Backoff:     10 * time.Millisecond,
+			MaxBackoff:  50 * time.Millisecond,
+		}),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	defer c.Close()
+
+	type result struct {
+		dbs []string
+		err error
+	}
+
+	resC := make(chan result, 1)
+	go func() {
+		dbs, err := c.ListDatabases(context.Background())
+		resC <- result{dbs: dbs, err: err}
+	}()
+
+	// Give the RPC time to be sent before dropping the connection, and
+	// drop it in the background because the server's handler is blocked.
+	time.Sleep(50 * time.Millisecond)
+
+	dropped := make(chan struct{})
+	go func() {
+		defer close(dropped)
+		d.drop()
+	}()
+
+	if diff := cmp.Diff(ovsdb.StateDisconnected, <-stateC); diff != "" {
+		t.Fatalf("unexpected state (-want +got):\n%s", diff)
+	}
+
+	close(unblock)
+	<-dropped
+
+	// The RPC is retried on the new connection rather than failing with
+	// ErrDisconnected.
+	select {
+	case res := <-resC:
+		if res.err != nil {
+			t.Fatalf("failed to list databases: %v", res.err)
+		}
+
+		if diff := cmp.Diff([]string{"Open_vSwitch"}, res.dbs); diff != "" {
+			t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for retried RPC")
+	}
+}
+
+func TestClientRetryIdempotent(t *testing.T) {
+	rpcC := make(chan string, 16)
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		rpcC <- req.Method
+
+		return jsonrpc.Response{
+			ID:    &req.ID,
+			Error: "transient",
+		}
+	}, ovsdb.Retry(ovsdb.RetryPolicy{
+		MaxAttempts: 3,
+		Retryable: func(err error) bool {
+			return err != nil
+		},
+	}))
+	defer done()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		fn   func() error
+		n    int
+	}{
+		{
+			name: "list_dbs",
+			fn: func() error {
+				_, err := c.ListDatabases(ctx)
+				return err
+			},
+			n: 3,
+		},
+		{
+			name: "get_schema",
+			fn: func() error {
+				_, err := c.GetSchema(ctx, "Open_vSwitch")
+				return err
+			},
+			n: 3,
+		},
+		{
+			name: "read-only transaction",
+			fn: func() error {
+				_, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Select{Table: "Bridge"},
+				})
+				return err
+			},
+			n: 3,
+		},
+		{
+			name: "read-only transaction with comment",
+			fn: func() error {
+				_, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					&ovsdb.Select{Table: "Bridge"},
+					ovsdb.Comment{Text: "list bridges"},
+				})
+				return err
+			},
+			n: 3,
+		},
+		{
+			name: "read-write transaction",
+			fn: func() error {
+				_, err := c.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Select{Table: "Bridge"},
+					ovsdb.Delete{Table: "Bridge"},
+				})
+				return err
+			},
+			n: 1,
+		},
+		{
+			name: "echo",
+			fn: func() error {
+				return c.Echo(ctx)
+			},
+			n: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			if diff := cmp.Diff(tt.n, len(rpcC)); diff != "" {
+				t.Fatalf("unexpected number of attempts (-want +got):\n%s", diff)
+			}
+
+			for i := 0; i < tt.n; i++ {
+				<-rpcC
+			}
+		})
+	}
+}
+
+func TestRetryInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ovsdb.RetryPolicy
+	}{
+		{
+			name: "no attempts",
+		},
+		{
+			name: "negative backoff",
+			policy: ovsdb.RetryPolicy{
+				MaxAttempts: 1,
+				Backoff:     -1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _, done := jsonrpc.TestNetConn(t, nil)
+			defer done()
+
+			if _, err := ovsdb.New(conn, ovsdb.Retry(tt.policy)); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+)
+
+// A Router routes requests between two Clients: one connected to the leader
+// of an OVSDB cluster, which performs transactions which modify the
+// database, and one connected to an OVSDB relay or follower, which performs
+// read-only requests such as monitors and selects.  Routing reads to
+// replicas reduces the load on a cluster's leader, which is typically the
+// bottleneck for large OVN deployments.
+//
+// The leader's Client typically uses the LeaderOnly and Reconnect options.
+// A replica may lag behind the leader, so the results of a transaction are
+// not necessarily visible to reads performed immediately afterward.  Use
+// the leader's Client directly when reads must observe earlier writes.
+//
+// A Router is safe for concurrent use.
+type Router struct {
+	leader, replica *Client
+}
+
+// NewRouter creates a Router which routes transactions to leader and
+// read-only requests to replica.  If replica is nil, all requests are
+// routed to leader.
+func NewRouter(leader, replica *Client) *Router {
+	if replica == nil {
+		replica = leader
+	}
+
+	return &Router{
+		leader:  leader,
+		replica: replica,
+	}
+}
+
+// Leader returns the Client connected to the cluster leader.  It may be used
+// for requests which the Router does not route, such as locks.
+func (r *Router) Leader() *Client {
+	return r.leader
+}
+
+// Replica returns the Client used for read-only requests.
+func (r *Router) Replica() *Client {
+	return r.replica
+}
+
+// Close closes both of the Router's Clients.
+func (r *Router) Close() error {
+	err := r.leader.Close()
+	if r.replica == r.leader {
+		return err
+	}
+
+	if rerr := r.replica.Close(); err == nil {
+		err = rerr
+	}
+
+	return err
+}
+
+// Transact performs a transaction using the replica if it only contains
+// read-only operations, or the leader otherwise.  As with the Retry option,
+// only Select, Wait, and Comment operations are read-only.
+func (r *Router) Transact(ctx context.Context, db string, ops []TransactOp) ([]OperationResult, error) {
+	return r.route(ops).Transact(ctx, db, ops)
+}
+
+// TransactResult performs a transaction like Client.TransactResult, using
+// the Client chosen as for Transact.
+func (r *Router) TransactResult(ctx context.Context, db string, ops []TransactOp) (*TransactionResult, error) {
+	return r.route(ops).TransactResult(ctx, db, ops)
+}
+
+// route returns the Client which should perform ops.
+func (r *Router) route(ops []TransactOp) *Client {
+	if readOnly(ops) {
+		return r.replica
+	}
+
+	return r.leader
+}
+
+// GetSchema retrieves the schema of a database using the replica.
+func (r *Router) GetSchema(ctx context.Context, db string) (*Schema, error) {
+	return r.replica.GetSchema(ctx, db)
+}
+
+// Dump retrieves the contents of a database using the replica.  See
+// Client.Dump.
+func (r *Router) Dump(ctx context.Context, db string, tables ...string) (*Dump, error) {
+	return r.replica.Dump(ctx, db, tables...)
+}
+
+// Monitor creates a Monitor using the replica.  See Client.Monitor.
+func (r *Router) Monitor(ctx context.Context, db string, requests map[string]MonitorRequest) (*Monitor, error) {
+	return r.replica.Monitor(ctx, db, requests)
+}
+
+// MonitorCond creates a CondMonitor using the replica.  See
+// Client.MonitorCond.
+func (r *Router) MonitorCond(ctx context.Context, db string, requests map[string]MonitorCondRequest) (*CondMonitor, error) {
+	return r.replica.MonitorCond(ctx, db, requests)
+}
+
+// MonitorCondSince creates a CondMonitor using the replica.  See
+// Client.MonitorCondSince.
+func (r *Router) MonitorCondSince(ctx context.Context, db string, requests map[string]MonitorCondRequest, lastTxnID string) (*CondMonitor, error) {
+	return r.replica.MonitorCondSince(ctx, db, requests, lastTxnID)
+}
+
+// MonitorCache creates a Cache using the replica.  See Client.MonitorCache.
+func (r *Router) MonitorCache(ctx context.Context, db string, requests map[string]MonitorCondRequest, indexes map[string][]string) (*Cache, error) {
+	return r.replica.MonitorCache(ctx, db, requests, indexes)
+}
+
+// MonitorCacheSince creates a Cache using the replica.  See
+// Client.MonitorCacheSince.
+func (r *Router) MonitorCacheSince(ctx context.Context, db string, requests map[string]MonitorCondRequest, indexes map[string][]string, snap *CacheSnapshot) (*Cache, error) {
+	return r.replica.MonitorCacheSince(ctx, db, requests, indexes, snap)
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRouter(t *testing.T) {
+	rpcC := make(chan string, 8)
+
+	// Each server records which of the Router's Clients performed an RPC.
+	server := func(name string) jsonrpc.TestFunc {
+		return func(req jsonrpc.Request) jsonrpc.Response {
+			rpcC <- name + " " + req.Method
+
+			var res interface{} = []ovsdb.OperationResult{}
+			if req.Method == "monitor" {
+				res = ovsdb.TableUpdates{}
+			}
+
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, res),
+			}
+		}
+	}
+
+	leader, _, leaderDone := testClient(t, server("leader"))
+	defer leaderDone()
+
+	replica, _, replicaDone := testClient(t, server("replica"))
+	defer replicaDone()
+
+	r := ovsdb.NewRouter(leader, replica)
+	defer r.Close()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		fn   func() error
+		want string
+	}{
+		{
+			name: "read-only transaction",
+			fn: func() error {
+				_, err := r.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Comment{Text: "list bridges"},
+					ovsdb.Select{Table: "Bridge"},
+					&ovsdb.Wait{Table: "Bridge", Until: "==", Rows: []ovsdb.Row{}},
+				})
+				return err
+			},
+			want: "replica transact",
+		},
+		{
+			name: "transaction",
+			fn: func() error {
+				_, err := r.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Select{Table: "Bridge"},
+					ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br0"}},
+				})
+				return err
+			},
+			want: "leader transact",
+		},
+		{
+			name: "transaction result",
+			fn: func() error {
+				_, err := r.TransactResult(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Insert{Table: "Bridge", UUIDName: "br0"},
+				})
+				return err
+			},
+			want: "leader transact",
+		},
+		{
+			name: "assert",
+			fn: func() error {
+				_, err := r.Transact(ctx, "Open_vSwitch", []ovsdb.TransactOp{
+					ovsdb.Assert{Lock: "foo"},
+				})
+				return err
+			},
+			want: "leader transact",
+		},
+		{
+			name: "monitor",
+			fn: func() error {
+				_, err := r.Monitor(ctx, "Open_vSwitch", map[string]ovsdb.MonitorRequest{
+					"Bridge": {},
+				})
+				return err
+			},
+			want: "replica monitor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err != nil {
+				t.Fatalf("failed to perform request: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, <-rpcC); diff != "" {
+				t.Fatalf("unexpected RPC (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouterNoReplica(t *testing.T) {
+	c, _, done := testClient(t, nil)
+	defer done()
+
+	r := ovsdb.NewRouter(c, nil)
+
+	if r.Leader() != c || r.Replica() != c {
+		t.Fatal("Router must use its leader for all requests")
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+}
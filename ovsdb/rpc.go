@@ -16,13 +16,16 @@ package ovsdb
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 )
 
 // ListDatabases returns the name of all databases known to the OVSDB server.
 func (c *Client) ListDatabases(ctx context.Context) ([]string, error) {
 	var dbs []string
-	if err := c.rpc(ctx, "list_dbs", &dbs, nil); err != nil {
+	if err := c.idempotentRPC(ctx, "list_dbs", &dbs, nil); err != nil {
 		return nil, err
 	}
 
@@ -32,49 +35,132 @@ func (c *Client) ListDatabases(ctx context.Context) ([]string, error) {
 // Echo verifies that the OVSDB connection is alive, and can be used to keep
 // the connection alive.
 func (c *Client) Echo(ctx context.Context) error {
+	_, err := c.echo(ctx)
+	return err
+}
+
+// echo implements Echo, and records the result of the echo for Health.
+func (c *Client) echo(ctx context.Context) (time.Duration, error) {
 	req := [1]string{"github.com/digitalocean/go-openvswitch/ovsdb"}
 
+	start := time.Now()
+
 	var res [1]string
 	if err := c.rpc(ctx, "echo", &res, req); err != nil {
-		return err
+		c.echoed(0, err)
+		return 0, err
 	}
 
 	if res[0] != req[0] {
-		return fmt.Errorf("invalid echo response: %q", res[0])
+		err := fmt.Errorf("invalid echo response: %q", res[0])
+		c.echoed(0, err)
+		return 0, err
 	}
 
-	return nil
+	rtt := time.Since(start)
+	c.echoed(rtt, nil)
+
+	return rtt, nil
+}
+
+// Call performs an RPC with an arbitrary method, such as one which is not yet
+// supported by this package, or is specific to a particular OVSDB server.
+//
+// params must encode as a JSON array, or be nil if the method has no
+// parameters.  If result is not nil, the RPC's result is unmarshaled into
+// it, like json.Unmarshal.  An error returned by the OVSDB server is of type
+// *Error.
+//
+// Call is never retried, even if a RetryPolicy is set.
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	if method == "" {
+		return errors.New("RPC method must not be empty")
+	}
+
+	return c.rpc(ctx, method, result, params)
 }
 
 // A Row is a database row.  Its keys are database column names, and its values
 // are database column values.
+//
+// Integers in a Row decoded from JSON are int64s, and other numbers are
+// float64s.  Use UnmarshalRow or a Cache to decode values according to their
+// column types.
 type Row map[string]interface{}
 
+var _ json.Unmarshaler = (*Row)(nil)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Row) UnmarshalJSON(b []byte) error {
+	v, err := decodeNumbers(b)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		*r = nil
+	case map[string]interface{}:
+		*r = v
+	default:
+		return fmt.Errorf("invalid row: %s", string(b))
+	}
+
+	return nil
+}
+
 // TODO(mdlayher): try to make concrete types for row values.
 
 // Transact creates and executes a transaction on the specified database.
-// Each operation is applied in the order they appear in ops.
-func (c *Client) Transact(ctx context.Context, db string, ops []TransactOp) ([]Row, error) {
+// Each operation is applied in the order they appear in ops, and the result
+// of each operation is returned in the same order.
+//
+// If any operation fails, or the transaction as a whole fails to commit, the
+// first *Error returned by the OVSDB server is returned.  If the
+// ValidateTransactions option is used, invalid operations are reported using
+// a *ValidationError before the transaction is sent.
+func (c *Client) Transact(ctx context.Context, db string, ops []TransactOp) ([]OperationResult, error) {
+	if s := c.validationSchema(db); s != nil {
+		if err := s.ValidateOps(ops); err != nil {
+			return nil, err
+		}
+	}
+
 	// Required because transact uses an unusual syntax for its arguments.
 	arg := transactArg{
 		Database: db,
 		Ops:      ops,
 	}
 
-	// TODO(mdlayher): deal with non-select ops too.
-	var out []struct {
-		Rows []Row `json:"rows"`
+	rpc := c.rpc
+	if readOnly(ops) {
+		rpc = c.idempotentRPC
 	}
 
-	if err := c.rpc(ctx, "transact", &out, arg); err != nil {
+	var out []OperationResult
+	if err := rpc(ctx, "transact", &out, arg); err != nil {
 		return nil, err
 	}
 
-	// Flatten results from all selects into one slice of rows.
-	var rows []Row
+	// OVSDB reports errors on a per-operation basis, and may append one
+	// additional error if the transaction could not be committed.
 	for _, o := range out {
-		rows = append(rows, o.Rows...)
+		if o.Error != nil {
+			return nil, o.Error
+		}
+	}
+
+	return out, nil
+}
+
+// TransactResult is like Transact, but also maps the UUIDName of each Insert
+// in ops to the UUID of the row it created, so that the new rows can be
+// referred to by later transactions.
+func (c *Client) TransactResult(ctx context.Context, db string, ops []TransactOp) (*TransactionResult, error) {
+	results, err := c.Transact(ctx, db, ops)
+	if err != nil {
+		return nil, err
 	}
 
-	return rows, nil
+	return newTransactionResult(ops, results), nil
 }
@@ -16,6 +16,7 @@ package ovsdb_test
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/digitalocean/go-openvswitch/ovsdb"
@@ -93,6 +94,72 @@ func TestClientEchoOK(t *testing.T) {
 	}
 }
 
+func TestClientCall(t *testing.T) {
+	type status struct {
+		Role string `json:"role"`
+		Term int    `json:"term"`
+	}
+
+	want := status{Role: "leader", Term: 2}
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("vendor_status", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]interface{}{"OVN_Southbound"}, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, want),
+		}
+	})
+	defer done()
+
+	var got status
+	if err := c.Call(context.Background(), "vendor_status", []string{"OVN_Southbound"}, &got); err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientCallError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		// Parameters default to an empty array.
+		if diff := cmp.Diff([]interface{}{}, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`{"error":"unknown method","details":"vendor_status"}`),
+		}
+	})
+	defer done()
+
+	// The result is optional.
+	err := c.Call(context.Background(), "vendor_status", nil, nil)
+
+	want := &ovsdb.Error{Err: "unknown method", Details: "vendor_status"}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Fatalf("unexpected error (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientCallNoMethod(t *testing.T) {
+	c, _, done := testClient(t, nil)
+	defer done()
+
+	if err := c.Call(context.Background(), "", nil, nil); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
 func TestClientTransactSelect(t *testing.T) {
 	const db = "Open_vSwitch"
 
@@ -139,16 +206,171 @@ func TestClientTransactSelect(t *testing.T) {
 		},
 	}}
 
-	rows, err := c.Transact(context.Background(), db, ops)
+	results, err := c.Transact(context.Background(), db, ops)
+	if err != nil {
+		t.Fatalf("failed to perform transaction: %v", err)
+	}
+
+	want := []ovsdb.OperationResult{{
+		Rows: []ovsdb.Row{{
+			"name": "ovsbr0",
+		}},
+	}}
+
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Fatalf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientTransactResults(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: strPtr("1"),
+			Result: []byte(`[
+				{"uuid":["uuid","36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"]},
+				{"count":2}
+			]`),
+		}
+	})
+	defer done()
+
+	results, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+		ovsdb.Select{Table: "Bridge"},
+	})
+	if err != nil {
+		t.Fatalf("failed to perform transaction: %v", err)
+	}
+
+	want := []ovsdb.OperationResult{
+		{UUID: "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"},
+		{Count: 2},
+	}
+
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Fatalf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientTransactResultUUIDs(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: &req.ID,
+			Result: []byte(`[
+				{"uuid":["uuid","36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"]},
+				{"uuid":["uuid","2f77b348-9768-4866-b761-89d5177ecda0"]},
+				{"uuid":["uuid","8d2a6d1c-51a3-4b4e-9f5d-0a3b0b8e2c1f"]},
+				{"count":1}
+			]`),
+		}
+	})
+	defer done()
+
+	res, err := c.TransactResult(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Insert{Table: "Port", UUIDName: "port0"},
+		&ovsdb.Insert{Table: "Port", UUIDName: "port1"},
+		// Inserts without a UUIDName are not included.
+		ovsdb.Insert{Table: "Port"},
+		ovsdb.Mutate{Table: "Bridge"},
+	})
 	if err != nil {
 		t.Fatalf("failed to perform transaction: %v", err)
 	}
 
+	want := &ovsdb.TransactionResult{
+		Operations: []ovsdb.OperationResult{
+			{UUID: "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"},
+			{UUID: "2f77b348-9768-4866-b761-89d5177ecda0"},
+			{UUID: "8d2a6d1c-51a3-4b4e-9f5d-0a3b0b8e2c1f"},
+			{Count: 1},
+		},
+		UUIDs: map[ovsdb.NamedUUID]ovsdb.UUID{
+			"port0": "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2",
+			"port1": "2f77b348-9768-4866-b761-89d5177ecda0",
+		},
+	}
+
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientTransactLargeIntegers(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: strPtr("1"),
+			Result: []byte(`[{"rows":[{
+				"cookie":9223372036854775807,
+				"tunnel_keys":["set",[4611686018427387905,-4611686018427387905]],
+				"ratio":0.5
+			}]}]`),
+		}
+	})
+	defer done()
+
+	results, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Flow"},
+	})
+	if err != nil {
+		t.Fatalf("failed to perform transaction: %v", err)
+	}
+
+	// Integers are decoded without loss of precision.
 	want := []ovsdb.Row{{
-		"name": "ovsbr0",
+		"cookie":      int64(math.MaxInt64),
+		"tunnel_keys": []interface{}{"set", []interface{}{int64(1<<62 + 1), int64(-1<<62 - 1)}},
+		"ratio":       0.5,
 	}}
 
-	if diff := cmp.Diff(want, rows); diff != "" {
+	if diff := cmp.Diff(want, results[0].Rows); diff != "" {
 		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
 	}
+
+	var flow struct {
+		Cookie     uint64  `ovsdb:"cookie"`
+		TunnelKeys []int64 `ovsdb:"tunnel_keys"`
+		Ratio      float64 `ovsdb:"ratio"`
+	}
+	if err := ovsdb.UnmarshalRow(results[0].Rows[0], &flow); err != nil {
+		t.Fatalf("failed to unmarshal row: %v", err)
+	}
+
+	if diff := cmp.Diff(uint64(math.MaxInt64), flow.Cookie); diff != "" {
+		t.Fatalf("unexpected cookie (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{1<<62 + 1, -1<<62 - 1}, flow.TunnelKeys); diff != "" {
+		t.Fatalf("unexpected tunnel keys (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientTransactOperationError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: strPtr("1"),
+			Result: []byte(`[
+				{"count":1},
+				{"error":"constraint violation","details":"duplicate name"},
+				null
+			]`),
+		}
+	})
+	defer done()
+
+	_, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+		ovsdb.Select{Table: "Bridge"},
+		ovsdb.Select{Table: "Bridge"},
+	})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	oerr, ok := err.(*ovsdb.Error)
+	if !ok {
+		t.Fatalf("error of wrong type: %#v", err)
+	}
+
+	if diff := cmp.Diff("constraint violation", oerr.Err); diff != "" {
+		t.Fatalf("unexpected error (-want +got):\n%s", diff)
+	}
 }
@@ -0,0 +1,178 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// An RPCRecord describes an RPC performed by a Client.
+type RPCRecord struct {
+	// The RPC's method and JSON-RPC request ID.
+	Method string
+	ID     string
+
+	// The time taken for the RPC to complete.
+	Duration time.Duration
+
+	// The JSON parameters of the request and the JSON result of the
+	// response, after redaction and truncation.  Result is empty if no
+	// response was received.
+	Params, Result string
+
+	// Any error which occurred.
+	Err error
+}
+
+// RPCLogConfig configures the records produced by RPCLogging.
+type RPCLogConfig struct {
+	// The maximum length of the Params and Result of each RPCRecord.
+	// Longer payloads are truncated and end with "...".  If zero,
+	// DefaultMaxPayload is used.  If negative, payloads are not truncated.
+	MaxPayload int
+
+	// Columns whose values are replaced with "<redacted>" wherever they
+	// appear in rows, conditions, or mutations, such as "private_key" in
+	// the SSL table of the Open_vSwitch database.
+	Redact []string
+}
+
+// DefaultMaxPayload is the length at which the payloads of an RPCRecord are
+// truncated if RPCLogConfig.MaxPayload is zero.
+const DefaultMaxPayload = 1024
+
+// redacted replaces the values of redacted columns.
+const redacted = "<redacted>"
+
+// RPCLogging specifies a function which receives an RPCRecord each time an
+// RPC completes, configured by cfg.  Unlike Debug, which logs the raw bytes
+// read from and written to the connection, RPCLogging reports structured
+// records which may be passed to any logging library, and does not expose
+// the values of redacted columns.
+//
+// fn is invoked synchronously by the goroutine which performed the RPC, and
+// must return promptly.
+func RPCLogging(fn func(r RPCRecord), cfg RPCLogConfig) OptionFunc {
+	return func(c *Client) error {
+		redact := make(map[string]bool, len(cfg.Redact))
+		for _, column := range cfg.Redact {
+			redact[column] = true
+		}
+
+		max := cfg.MaxPayload
+		if max == 0 {
+			max = DefaultMaxPayload
+		}
+
+		c.rpcLog = &rpcLogger{
+			fn:     fn,
+			max:    max,
+			redact: redact,
+		}
+		return nil
+	}
+}
+
+// An rpcLogger produces RPCRecords for RPCLogging.
+type rpcLogger struct {
+	fn     func(r RPCRecord)
+	max    int
+	redact map[string]bool
+}
+
+// log reports an RPC which began at start.
+func (l *rpcLogger) log(method, id string, start time.Time, arg interface{}, result json.RawMessage, err error) {
+	params, perr := json.Marshal(arg)
+	if perr != nil {
+		params = nil
+	}
+
+	l.fn(RPCRecord{
+		Method:   method,
+		ID:       id,
+		Duration: time.Since(start),
+		Params:   l.payload(params),
+		Result:   l.payload(result),
+		Err:      err,
+	})
+}
+
+// payload redacts and truncates the JSON in b.
+func (l *rpcLogger) payload(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	if len(l.redact) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			// Never log a payload which could not be redacted.
+			return redacted
+		}
+
+		// Avoid escaping the redaction marker.
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(l.redactValue(v)); err != nil {
+			return redacted
+		}
+		b = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	}
+
+	if l.max > 0 && len(b) > l.max {
+		return string(b[:l.max]) + "..."
+	}
+
+	return string(b)
+}
+
+// redactValue replaces the values of redacted columns in v, which contains
+// the rows, conditions, and mutations of a payload.
+func (l *rpcLogger) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// Rows and row updates are objects keyed by column.
+		for k, vv := range v {
+			if l.redact[k] {
+				v[k] = redacted
+				continue
+			}
+
+			v[k] = l.redactValue(vv)
+		}
+	case []interface{}:
+		// Conditions and mutations are [<column>, <function>, <value>].
+		if len(v) == 3 {
+			column, ok := v[0].(string)
+			_, isFunc := v[1].(string)
+			if ok && isFunc && l.redact[column] {
+				v[2] = redacted
+				return v
+			}
+		}
+
+		for i, vv := range v {
+			v[i] = l.redactValue(vv)
+		}
+	}
+
+	return v
+}
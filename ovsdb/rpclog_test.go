@@ -0,0 +1,134 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestClientRPCLogging(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ovsdb.RPCLogConfig
+		want ovsdb.RPCRecord
+	}{
+		{
+			name: "full",
+			cfg:  ovsdb.RPCLogConfig{MaxPayload: -1},
+			want: ovsdb.RPCRecord{
+				Params: `["Open_vSwitch",{"op":"select","table":"SSL","where":[["private_key","==","secret"]]}]`,
+				Result: `[{"rows":[{"certificate":"cert","private_key":"secret"}]}]`,
+			},
+		},
+		{
+			name: "redacted",
+			cfg: ovsdb.RPCLogConfig{
+				MaxPayload: -1,
+				Redact:     []string{"private_key"},
+			},
+			want: ovsdb.RPCRecord{
+				Params: `["Open_vSwitch",{"op":"select","table":"SSL","where":[["private_key","==","<redacted>"]]}]`,
+				Result: `[{"rows":[{"certificate":"cert","private_key":"<redacted>"}]}]`,
+			},
+		},
+		{
+			name: "truncated",
+			cfg: ovsdb.RPCLogConfig{
+				MaxPayload: 8,
+				Redact:     []string{"private_key"},
+			},
+			want: ovsdb.RPCRecord{
+				Params: `["Open_v...`,
+				Result: `[{"rows"...`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recC := make(chan ovsdb.RPCRecord, 1)
+
+			c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+				return jsonrpc.Response{
+					ID:     &req.ID,
+					Result: json.RawMessage(`[{"rows":[{"certificate":"cert","private_key":"secret"}]}]`),
+				}
+			}, ovsdb.RPCLogging(func(r ovsdb.RPCRecord) {
+				recC <- r
+			}, tt.cfg))
+			defer done()
+
+			_, err := c.Transact(context.Background(), "Open_vSwitch", []ovsdb.TransactOp{
+				ovsdb.Select{
+					Table: "SSL",
+					Where: []ovsdb.Cond{ovsdb.Equal("private_key", "secret")},
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to perform transaction: %v", err)
+			}
+
+			want := tt.want
+			want.Method = "transact"
+			want.ID = "1"
+
+			got := <-recC
+			if got.Duration <= 0 {
+				t.Fatalf("unexpected RPC duration: %v", got.Duration)
+			}
+
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(ovsdb.RPCRecord{}, "Duration")); diff != "" {
+				t.Fatalf("unexpected record (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientRPCLoggingError(t *testing.T) {
+	recC := make(chan ovsdb.RPCRecord, 1)
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:    &req.ID,
+			Error: "unknown method",
+		}
+	}, ovsdb.RPCLogging(func(r ovsdb.RPCRecord) {
+		recC <- r
+	}, ovsdb.RPCLogConfig{}))
+	defer done()
+
+	if _, err := c.ListDatabases(context.Background()); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	r := <-recC
+	if r.Err == nil {
+		t.Fatal("expected an error in record, but none occurred")
+	}
+
+	if diff := cmp.Diff("list_dbs", r.Method); diff != "" {
+		t.Fatalf("unexpected method (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff("", r.Result); diff != "" {
+		t.Fatalf("unexpected result (-want +got):\n%s", diff)
+	}
+}
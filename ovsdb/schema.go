@@ -0,0 +1,363 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GetSchema retrieves the schema of the specified database.
+func (c *Client) GetSchema(ctx context.Context, db string) (*Schema, error) {
+	var s Schema
+	if err := c.idempotentRPC(ctx, "get_schema", &s, []string{db}); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Convert converts the specified database to a new schema while the OVSDB
+// server continues to serve it, as with "ovsdb-client convert".  Columns and
+// tables which are not present in schema are removed, and new columns take
+// their default values.
+//
+// When the conversion is complete, the server closes connections which
+// monitor the database, or cancels their monitors if they used
+// Client.SetDBChangeAware.
+func (c *Client) Convert(ctx context.Context, db string, schema *Schema) error {
+	if schema == nil {
+		return errors.New("schema must not be nil")
+	}
+
+	return c.rpc(ctx, "convert", nil, []interface{}{db, schema})
+}
+
+// A Schema is an OVSDB database schema, as described in RFC 7047,
+// section 3.2.
+type Schema struct {
+	Name     string                 `json:"name"`
+	Version  string                 `json:"version"`
+	Checksum string                 `json:"cksum,omitempty"`
+	Tables   map[string]TableSchema `json:"tables"`
+}
+
+// HasTable reports whether the schema contains the specified table.
+func (s *Schema) HasTable(table string) bool {
+	_, ok := s.Tables[table]
+	return ok
+}
+
+// HasColumn reports whether the schema contains the specified column of a
+// table.  Columns are often added in new versions of a schema, so HasColumn
+// can be used to enable features only when a server supports them.
+func (s *Schema) HasColumn(table, column string) bool {
+	ts, ok := s.Tables[table]
+	if !ok {
+		return false
+	}
+
+	_, ok = ts.Columns[column]
+	return ok
+}
+
+// AtLeast reports whether the schema's version is greater than or equal to
+// version, which must be of the form "<major>.<minor>.<patch>".
+func (s *Schema) AtLeast(version string) (bool, error) {
+	have, err := ParseSchemaVersion(s.Version)
+	if err != nil {
+		return false, err
+	}
+
+	want, err := ParseSchemaVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	return have.Compare(want) >= 0, nil
+}
+
+// A TableSchema is the schema of a single table within a Schema.
+type TableSchema struct {
+	Columns map[string]ColumnSchema `json:"columns"`
+
+	// The maximum number of rows in the table.  Zero means unlimited.
+	MaxRows int `json:"maxRows,omitempty"`
+
+	// Whether rows in this table are exempt from garbage collection.
+	IsRoot bool `json:"isRoot,omitempty"`
+
+	// Sets of columns whose values must be unique across all rows.
+	Indexes [][]string `json:"indexes,omitempty"`
+}
+
+// A ColumnSchema is the schema of a single column within a TableSchema.
+type ColumnSchema struct {
+	Type ColumnType `json:"type"`
+
+	// Whether the column's value is lost when the database restarts.
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// Whether the column's value may be modified after the row is
+	// inserted.  Note that columns are mutable by default.
+	Mutable bool `json:"mutable"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *ColumnSchema) UnmarshalJSON(b []byte) error {
+	// Use a type alias to avoid recursion, and set the default that the
+	// column is mutable if not otherwise specified.
+	type column ColumnSchema
+	v := column{Mutable: true}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	*c = ColumnSchema(v)
+	return nil
+}
+
+// Unlimited indicates that a ColumnType has no maximum number of elements.
+const Unlimited = -1
+
+// A ColumnType is the type of a column.  Scalar columns have Min and Max
+// equal to 1.  Set columns have a Max greater than 1 and a nil Value.  Map
+// columns have a non-nil Value.
+type ColumnType struct {
+	Key   BaseType
+	Value *BaseType
+
+	// The minimum and maximum number of elements in the column.  Max may be
+	// Unlimited.
+	Min, Max int
+}
+
+// IsOptional reports whether the column may be empty, and contains at most
+// one element.
+func (t ColumnType) IsOptional() bool {
+	return t.Min == 0 && t.Max == 1
+}
+
+// IsSet reports whether the column is a set which may contain more than
+// one element.
+func (t ColumnType) IsSet() bool {
+	return t.Value == nil && (t.Max > 1 || t.Max == Unlimited)
+}
+
+// IsMap reports whether the column is a map.
+func (t ColumnType) IsMap() bool {
+	return t.Value != nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t ColumnType) MarshalJSON() ([]byte, error) {
+	// Use the abbreviated form for scalar atomic types.
+	if t.Value == nil && t.Min == 1 && t.Max == 1 && t.Key.isAtomic() {
+		return json.Marshal(t.Key.Type)
+	}
+
+	var max interface{} = t.Max
+	if t.Max == Unlimited {
+		max = "unlimited"
+	}
+
+	v := struct {
+		Key   BaseType    `json:"key"`
+		Value *BaseType   `json:"value,omitempty"`
+		Min   int         `json:"min"`
+		Max   interface{} `json:"max"`
+	}{
+		Key:   t.Key,
+		Value: t.Value,
+		Min:   t.Min,
+		Max:   max,
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *ColumnType) UnmarshalJSON(b []byte) error {
+	// A type may be a bare atomic type, which is a scalar.
+	var atomic string
+	if err := json.Unmarshal(b, &atomic); err == nil {
+		*t = ColumnType{
+			Key: BaseType{Type: atomic},
+			Min: 1,
+			Max: 1,
+		}
+		return nil
+	}
+
+	var v struct {
+		Key   BaseType        `json:"key"`
+		Value *BaseType       `json:"value"`
+		Min   *int            `json:"min"`
+		Max   json.RawMessage `json:"max"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	// Both min and max default to 1.
+	min, max := 1, 1
+	if v.Min != nil {
+		min = *v.Min
+	}
+
+	if len(v.Max) > 0 {
+		var s string
+		if err := json.Unmarshal(v.Max, &s); err == nil {
+			if s != "unlimited" {
+				return fmt.Errorf("invalid column type max: %q", s)
+			}
+
+			max = Unlimited
+		} else if err := json.Unmarshal(v.Max, &max); err != nil {
+			return err
+		}
+	}
+
+	*t = ColumnType{
+		Key:   v.Key,
+		Value: v.Value,
+		Min:   min,
+		Max:   max,
+	}
+
+	return nil
+}
+
+// Atomic types which may appear in a BaseType.
+const (
+	TypeInteger = "integer"
+	TypeReal    = "real"
+	TypeBoolean = "boolean"
+	TypeString  = "string"
+	TypeUUID    = "uuid"
+)
+
+// A BaseType is the type of the keys or values of a column, along with
+// any constraints on those keys or values.
+type BaseType struct {
+	// One of the Type constants.
+	Type string `json:"type"`
+
+	// If non-empty, the only values permitted.
+	Enum []interface{} `json:"enum,omitempty"`
+
+	// Constraints for integer types.
+	MinInteger *int64 `json:"minInteger,omitempty"`
+	MaxInteger *int64 `json:"maxInteger,omitempty"`
+
+	// Constraints for real types.
+	MinReal *float64 `json:"minReal,omitempty"`
+	MaxReal *float64 `json:"maxReal,omitempty"`
+
+	// Constraints for string types.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+
+	// For uuid types, the table referenced and whether the reference
+	// is "strong" or "weak".
+	RefTable string `json:"refTable,omitempty"`
+	RefType  string `json:"refType,omitempty"`
+}
+
+// isAtomic reports whether b is an atomic type with no constraints.
+func (b BaseType) isAtomic() bool {
+	return b.Enum == nil &&
+		b.MinInteger == nil && b.MaxInteger == nil &&
+		b.MinReal == nil && b.MaxReal == nil &&
+		b.MinLength == nil && b.MaxLength == nil &&
+		b.RefTable == "" && b.RefType == ""
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b BaseType) MarshalJSON() ([]byte, error) {
+	if b.isAtomic() {
+		return json.Marshal(b.Type)
+	}
+
+	// Use a type alias to avoid recursion, and encode enums as OVSDB sets.
+	type base BaseType
+	v := struct {
+		base
+		Enum interface{} `json:"enum,omitempty"`
+	}{
+		base: base(b),
+	}
+
+	if b.Enum != nil {
+		v.Enum = []interface{}{"set", b.Enum}
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *BaseType) UnmarshalJSON(buf []byte) error {
+	// A base type may be a bare atomic type.
+	var atomic string
+	if err := json.Unmarshal(buf, &atomic); err == nil {
+		*b = BaseType{Type: atomic}
+		return nil
+	}
+
+	type base BaseType
+	var v struct {
+		base
+		Enum json.RawMessage `json:"enum"`
+	}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+
+	*b = BaseType(v.base)
+
+	if len(v.Enum) == 0 {
+		return nil
+	}
+
+	// An enum is a set, which is either a single atom or ["set", [...]].
+	e, err := decodeNumbers(v.Enum)
+	if err != nil {
+		return err
+	}
+
+	set, ok := e.([]interface{})
+	if !ok {
+		b.Enum = []interface{}{realAtom(*b, e)}
+		return nil
+	}
+
+	if len(set) != 2 || set[0] != "set" {
+		return fmt.Errorf("invalid enum: %s", string(v.Enum))
+	}
+
+	elems, ok := set[1].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid enum: %s", string(v.Enum))
+	}
+
+	for i := range elems {
+		elems[i] = realAtom(*b, elems[i])
+	}
+
+	b.Enum = elems
+	return nil
+}
@@ -0,0 +1,333 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testSchema is an abbreviated version of the Open_vSwitch schema.
+const testSchema = `{
+	"name": "Open_vSwitch",
+	"version": "7.15.1",
+	"cksum": "3682332033 23608",
+	"tables": {
+		"Open_vSwitch": {
+			"columns": {
+				"bridges": {
+					"type": {
+						"key": {"type": "uuid", "refTable": "Bridge"},
+						"min": 0,
+						"max": "unlimited"
+					}
+				},
+				"next_cfg": {"type": "integer"}
+			},
+			"isRoot": true,
+			"maxRows": 1
+		},
+		"Bridge": {
+			"columns": {
+				"name": {"type": "string", "mutable": false},
+				"datapath_type": {"type": "string"},
+				"fail_mode": {
+					"type": {
+						"key": {"type": "string", "enum": ["set", ["standalone", "secure"]]},
+						"min": 0,
+						"max": 1
+					}
+				},
+				"external_ids": {
+					"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}
+				},
+				"mcast_snooping_enable": {
+					"type": {"key": {"type": "boolean", "enum": true}}
+				},
+				"datapath_version": {"type": "string", "ephemeral": true}
+			},
+			"indexes": [["name"]]
+		}
+	}
+}`
+
+func TestClientGetSchema(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("get_schema", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]interface{}{"Open_vSwitch"}, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(testSchema),
+		}
+	})
+	defer done()
+
+	s, err := c.GetSchema(context.Background(), "Open_vSwitch")
+	if err != nil {
+		t.Fatalf("failed to get schema: %v", err)
+	}
+
+	str := ovsdb.BaseType{Type: ovsdb.TypeString}
+
+	want := &ovsdb.Schema{
+		Name:     "Open_vSwitch",
+		Version:  "7.15.1",
+		Checksum: "3682332033 23608",
+		Tables: map[string]ovsdb.TableSchema{
+			"Open_vSwitch": {
+				Columns: map[string]ovsdb.ColumnSchema{
+					"bridges": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{
+								Type:     ovsdb.TypeUUID,
+								RefTable: "Bridge",
+							},
+							Min: 0,
+							Max: ovsdb.Unlimited,
+						},
+						Mutable: true,
+					},
+					"next_cfg": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{Type: ovsdb.TypeInteger},
+							Min: 1,
+							Max: 1,
+						},
+						Mutable: true,
+					},
+				},
+				IsRoot:  true,
+				MaxRows: 1,
+			},
+			"Bridge": {
+				Columns: map[string]ovsdb.ColumnSchema{
+					"name": {
+						Type: ovsdb.ColumnType{Key: str, Min: 1, Max: 1},
+					},
+					"datapath_type": {
+						Type:    ovsdb.ColumnType{Key: str, Min: 1, Max: 1},
+						Mutable: true,
+					},
+					"fail_mode": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{
+								Type: ovsdb.TypeString,
+								Enum: []interface{}{"standalone", "secure"},
+							},
+							Min: 0,
+							Max: 1,
+						},
+						Mutable: true,
+					},
+					"external_ids": {
+						Type: ovsdb.ColumnType{
+							Key:   str,
+							Value: &str,
+							Min:   0,
+							Max:   ovsdb.Unlimited,
+						},
+						Mutable: true,
+					},
+					"mcast_snooping_enable": {
+						Type: ovsdb.ColumnType{
+							Key: ovsdb.BaseType{
+								Type: ovsdb.TypeBoolean,
+								Enum: []interface{}{true},
+							},
+							Min: 1,
+							Max: 1,
+						},
+						Mutable: true,
+					},
+					"datapath_version": {
+						Type:      ovsdb.ColumnType{Key: str, Min: 1, Max: 1},
+						Ephemeral: true,
+						Mutable:   true,
+					},
+				},
+				Indexes: [][]string{{"name"}},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Fatalf("unexpected schema (-want +got):\n%s", diff)
+	}
+
+	// Verify helpers for column types.
+	bridge := s.Tables["Bridge"]
+	for _, tt := range []struct {
+		column              string
+		optional, set, mapp bool
+	}{
+		{column: "name"},
+		{column: "fail_mode", optional: true},
+		{column: "external_ids", mapp: true},
+	} {
+		ct := bridge.Columns[tt.column].Type
+
+		if diff := cmp.Diff(tt.optional, ct.IsOptional()); diff != "" {
+			t.Fatalf("unexpected %q optional (-want +got):\n%s", tt.column, diff)
+		}
+		if diff := cmp.Diff(tt.set, ct.IsSet()); diff != "" {
+			t.Fatalf("unexpected %q set (-want +got):\n%s", tt.column, diff)
+		}
+		if diff := cmp.Diff(tt.mapp, ct.IsMap()); diff != "" {
+			t.Fatalf("unexpected %q map (-want +got):\n%s", tt.column, diff)
+		}
+	}
+
+	if !s.Tables["Open_vSwitch"].Columns["bridges"].Type.IsSet() {
+		t.Fatal("expected bridges column to be a set")
+	}
+
+	// The schema must survive a round trip through JSON.
+	var s2 ovsdb.Schema
+	if err := json.Unmarshal(mustMarshalJSON(t, s), &s2); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	if diff := cmp.Diff(want, &s2); diff != "" {
+		t.Fatalf("unexpected schema after round trip (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientConvert(t *testing.T) {
+	var want ovsdb.Schema
+	if err := json.Unmarshal([]byte(testSchema), &want); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("convert", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		params, ok := req.Params.([]interface{})
+		if !ok || len(params) != 2 {
+			panicf("unexpected RPC parameters: %#v", req.Params)
+		}
+
+		if diff := cmp.Diff("Open_vSwitch", params[0]); diff != "" {
+			panicf("unexpected database (-want +got):\n%s", diff)
+		}
+
+		var got ovsdb.Schema
+		if err := json.Unmarshal(mustMarshalJSON(t, params[1]), &got); err != nil {
+			panicf("failed to unmarshal schema: %v", err)
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			panicf("unexpected schema (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, struct{}{}),
+		}
+	})
+	defer done()
+
+	if err := c.Convert(context.Background(), "Open_vSwitch", &want); err != nil {
+		t.Fatalf("failed to convert: %v", err)
+	}
+}
+
+func TestClientConvertError(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID: &req.ID,
+			Error: &ovsdb.Error{
+				Err:     "constraint violation",
+				Details: "column \"name\" cannot be removed",
+			},
+		}
+	})
+	defer done()
+
+	if err := c.Convert(context.Background(), "Open_vSwitch", &ovsdb.Schema{}); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	if err := c.Convert(context.Background(), "Open_vSwitch", nil); err == nil {
+		t.Fatal("expected an error for a nil schema, but none occurred")
+	}
+}
+
+func TestColumnTypeUnmarshalJSONBadMax(t *testing.T) {
+	var ct ovsdb.ColumnType
+	if err := json.Unmarshal([]byte(`{"key":"string","max":"foo"}`), &ct); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestSchemaHasColumn(t *testing.T) {
+	s := mustSchema(t, testSchema)
+
+	tests := []struct {
+		table, column string
+		ok            bool
+	}{
+		{table: "Bridge", column: "name", ok: true},
+		{table: "Bridge", column: "mcast_snooping_enable", ok: true},
+		{table: "Bridge", column: "lldp"},
+		{table: "Interface", column: "lldp"},
+	}
+
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.ok, s.HasColumn(tt.table, tt.column)); diff != "" {
+			t.Fatalf("unexpected result for %s.%s (-want +got):\n%s", tt.table, tt.column, diff)
+		}
+	}
+
+	if !s.HasTable("Open_vSwitch") || s.HasTable("Interface") {
+		t.Fatal("unexpected tables in schema")
+	}
+}
+
+func TestSchemaAtLeast(t *testing.T) {
+	s := mustSchema(t, testSchema)
+
+	for v, want := range map[string]bool{
+		"7.15.0": true,
+		"7.15.1": true,
+		"7.15.2": false,
+		"8.0.0":  false,
+	} {
+		ok, err := s.AtLeast(v)
+		if err != nil {
+			t.Fatalf("failed to compare version %q: %v", v, err)
+		}
+
+		if diff := cmp.Diff(want, ok); diff != "" {
+			t.Fatalf("unexpected result for %q (-want +got):\n%s", v, diff)
+		}
+	}
+
+	if _, err := s.AtLeast("7.15"); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
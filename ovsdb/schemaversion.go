@@ -0,0 +1,78 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A SchemaVersion is the version of a Schema, as described in RFC 7047,
+// section 3.1.  When a schema changes, its major version is incremented if
+// the change is incompatible, and its minor version if columns or tables
+// are added.
+type SchemaVersion struct {
+	Major, Minor, Patch int
+}
+
+// ParseSchemaVersion parses a version of the form "<major>.<minor>.<patch>".
+func ParseSchemaVersion(s string) (SchemaVersion, error) {
+	fields := strings.Split(s, ".")
+	if len(fields) != 3 {
+		return SchemaVersion{}, fmt.Errorf("invalid schema version: %q", s)
+	}
+
+	var ns [3]int
+	for i, f := range fields {
+		// Only decimal digits are permitted, without a sign.
+		n, err := strconv.Atoi(f)
+		if err != nil || strings.Trim(f, "0123456789") != "" {
+			return SchemaVersion{}, fmt.Errorf("invalid schema version: %q", s)
+		}
+
+		ns[i] = n
+	}
+
+	return SchemaVersion{
+		Major: ns[0],
+		Minor: ns[1],
+		Patch: ns[2],
+	}, nil
+}
+
+// Compare returns -1, 0, or 1 if v is less than, equal to, or greater than
+// other.
+func (v SchemaVersion) Compare(other SchemaVersion) int {
+	for _, p := range [][2]int{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+	} {
+		switch {
+		case p[0] < p[1]:
+			return -1
+		case p[0] > p[1]:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// String returns the string representation of a SchemaVersion.
+func (v SchemaVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
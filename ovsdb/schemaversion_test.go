@@ -0,0 +1,93 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSchemaVersion(t *testing.T) {
+	tests := []struct {
+		s  string
+		v  ovsdb.SchemaVersion
+		ok bool
+	}{
+		{s: "7.15.1", v: ovsdb.SchemaVersion{Major: 7, Minor: 15, Patch: 1}, ok: true},
+		{s: "0.0.0", ok: true},
+		{s: ""},
+		{s: "7.15"},
+		{s: "7.15.1.2"},
+		{s: "7.x.1"},
+		{s: "7.-1.1"},
+		{s: "7.+1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			v, err := ovsdb.ParseSchemaVersion(tt.s)
+			if tt.ok && err != nil {
+				t.Fatalf("failed to parse version: %v", err)
+			}
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("expected an error, but none occurred")
+				}
+
+				return
+			}
+
+			if diff := cmp.Diff(tt.v, v); diff != "" {
+				t.Fatalf("unexpected version (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.s, v.String()); diff != "" {
+				t.Fatalf("unexpected string (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "7.15.1", b: "7.15.1", want: 0},
+		{a: "7.15.1", b: "7.16.0", want: -1},
+		{a: "8.0.0", b: "7.16.0", want: 1},
+		{a: "7.15.10", b: "7.15.9", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			a, err := ovsdb.ParseSchemaVersion(tt.a)
+			if err != nil {
+				t.Fatalf("failed to parse version: %v", err)
+			}
+
+			b, err := ovsdb.ParseSchemaVersion(tt.b)
+			if err != nil {
+				t.Fatalf("failed to parse version: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, a.Compare(b)); diff != "" {
+				t.Fatalf("unexpected comparison (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Possible values of ServerDatabase.Model.
+const (
+	ModelStandalone = "standalone"
+	ModelClustered  = "clustered"
+	ModelRelay      = "relay"
+)
+
+// A ServerDatabase describes a database hosted by an OVSDB server, as
+// reported by the Database table of the server's _Server database.
+type ServerDatabase struct {
+	// The name of the database, and its storage model: ModelStandalone,
+	// ModelClustered, or ModelRelay.
+	Name  string `ovsdb:"name"`
+	Model string `ovsdb:"model"`
+
+	// Whether the server is connected to the cluster or relay source, and
+	// whether it is the leader of its cluster.  Standalone databases are
+	// always connected and the leader.
+	Connected bool `ovsdb:"connected"`
+	Leader    bool `ovsdb:"leader"`
+
+	// For clustered databases, the cluster ID and the server's ID within
+	// the cluster, and the index of the last Raft log entry the server has
+	// applied.  Each is nil until known, and for other models.
+	ClusterID *UUID  `ovsdb:"cid"`
+	ServerID  *UUID  `ovsdb:"sid"`
+	Index     *int64 `ovsdb:"index"`
+}
+
+// IsLeader reports whether transactions on the database may be sent to the
+// server: that is, the database is not clustered, or the server is connected
+// to its cluster and is the leader.
+func (d *ServerDatabase) IsLeader() bool {
+	// Only clustered databases have leaders.
+	if d.Model != ModelClustered {
+		return true
+	}
+
+	return d.Connected && d.Leader
+}
+
+// serverColumns are the columns of the _Server Database table which are
+// stored in a ServerDatabase.
+var serverColumns = []string{"name", "model", "connected", "leader", "cid", "sid", "index"}
+
+// ServerDatabases returns the status of each database hosted by the OVSDB
+// server, sorted by name, using the server's _Server database.  The server
+// must provide the _Server database, which is available in Open vSwitch 2.9
+// and later.
+func (c *Client) ServerDatabases(ctx context.Context) ([]ServerDatabase, error) {
+	return c.serverDatabases(ctx, nil)
+}
+
+// ServerDatabase returns the status of database db, as described for
+// ServerDatabases.
+func (c *Client) ServerDatabase(ctx context.Context, db string) (*ServerDatabase, error) {
+	dbs, err := c.serverDatabases(ctx, []Cond{Equal("name", db)})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbs) == 0 {
+		return nil, fmt.Errorf("database %q not found in %s database", db, serverDB)
+	}
+
+	return &dbs[0], nil
+}
+
+// serverDatabases selects the rows of the _Server Database table which match
+// where.
+func (c *Client) serverDatabases(ctx context.Context, where []Cond) ([]ServerDatabase, error) {
+	res, err := c.Transact(ctx, serverDB, []TransactOp{
+		Select{
+			Table:   "Database",
+			Where:   where,
+			Columns: serverColumns,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := res[0].Rows
+	dbs := make([]ServerDatabase, 0, len(rows))
+	for _, row := range rows {
+		var d ServerDatabase
+		if err := UnmarshalRow(row, &d); err != nil {
+			return nil, fmt.Errorf("failed to decode %s database row: %v", serverDB, err)
+		}
+
+		dbs = append(dbs, d)
+	}
+
+	sort.Slice(dbs, func(i, j int) bool {
+		return dbs[i].Name < dbs[j].Name
+	})
+
+	return dbs, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	serverCID = "4b2f7a9e-4f7e-4bd1-9f4c-2f11c7b8d0a1"
+	serverSID = "d6c1a3e2-91b9-4f8e-a0c3-6a4f0a5c2e77"
+)
+
+func TestClientServerDatabases(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("transact", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		params := []interface{}{
+			"_Server",
+			map[string]interface{}{
+				"op":      "select",
+				"table":   "Database",
+				"where":   []interface{}{},
+				"columns": []interface{}{"name", "model", "connected", "leader", "cid", "sid", "index"},
+			},
+		}
+
+		if diff := cmp.Diff(params, req.Params); diff != "" {
+			panicf("unexpected RPC parameters (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID: &req.ID,
+			Result: json.RawMessage(`[{"rows":[` +
+				`{"name":"OVN_Southbound","model":"clustered","connected":true,"leader":false,` +
+				`"cid":["uuid","` + serverCID + `"],"sid":["uuid","` + serverSID + `"],"index":42},` +
+				`{"name":"_Server","model":"standalone","connected":true,"leader":true,` +
+				`"cid":["set",[]],"sid":["set",[]],"index":["set",[]]}` +
+				`]}]`),
+		}
+	})
+	defer done()
+
+	dbs, err := c.ServerDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get server databases: %v", err)
+	}
+
+	cid, sid, index := ovsdb.UUID(serverCID), ovsdb.UUID(serverSID), int64(42)
+
+	want := []ovsdb.ServerDatabase{
+		{
+			Name:      "OVN_Southbound",
+			Model:     ovsdb.ModelClustered,
+			Connected: true,
+			ClusterID: &cid,
+			ServerID:  &sid,
+			Index:     &index,
+		},
+		{
+			Name:      "_Server",
+			Model:     ovsdb.ModelStandalone,
+			Connected: true,
+			Leader:    true,
+		},
+	}
+
+	if diff := cmp.Diff(want, dbs); diff != "" {
+		t.Fatalf("unexpected server databases (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientServerDatabase(t *testing.T) {
+	tests := []struct {
+		name string
+		rows string
+		want *ovsdb.ServerDatabase
+		ok   bool
+	}{
+		{
+			name: "found",
+			rows: `[{"name":"Open_vSwitch","model":"standalone","connected":true,"leader":true}]`,
+			want: &ovsdb.ServerDatabase{
+				Name:      "Open_vSwitch",
+				Model:     ovsdb.ModelStandalone,
+				Connected: true,
+				Leader:    true,
+			},
+			ok: true,
+		},
+		{
+			name: "not found",
+			rows: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+				where := req.Params.([]interface{})[1].(map[string]interface{})["where"]
+
+				want := []interface{}{
+					[]interface{}{"name", "==", "Open_vSwitch"},
+				}
+
+				if diff := cmp.Diff(want, where); diff != "" {
+					panicf("unexpected conditions (-want +got):\n%s", diff)
+				}
+
+				return jsonrpc.Response{
+					ID:     &req.ID,
+					Result: json.RawMessage(`[{"rows":` + tt.rows + `}]`),
+				}
+			})
+			defer done()
+
+			d, err := c.ServerDatabase(context.Background(), "Open_vSwitch")
+			if tt.ok && err != nil {
+				t.Fatalf("failed to get server database: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			if diff := cmp.Diff(tt.want, d); diff != "" {
+				t.Fatalf("unexpected server database (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServerDatabaseIsLeader(t *testing.T) {
+	tests := []struct {
+		name string
+		d    ovsdb.ServerDatabase
+		ok   bool
+	}{
+		{
+			name: "standalone",
+			d:    ovsdb.ServerDatabase{Model: ovsdb.ModelStandalone},
+			ok:   true,
+		},
+		{
+			name: "relay",
+			d:    ovsdb.ServerDatabase{Model: ovsdb.ModelRelay},
+			ok:   true,
+		},
+		{
+			name: "clustered leader",
+			d: ovsdb.ServerDatabase{
+				Model:     ovsdb.ModelClustered,
+				Connected: true,
+				Leader:    true,
+			},
+			ok: true,
+		},
+		{
+			name: "clustered follower",
+			d: ovsdb.ServerDatabase{
+				Model:     ovsdb.ModelClustered,
+				Connected: true,
+			},
+		},
+		{
+			name: "clustered disconnected",
+			d: ovsdb.ServerDatabase{
+				Model:  ovsdb.ModelClustered,
+				Leader: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.ok, tt.d.IsLeader()); diff != "" {
+				t.Fatalf("unexpected leader status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
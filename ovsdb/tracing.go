@@ -0,0 +1,172 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// A Tracer creates spans which trace a Client's RPCs, such as by using an
+// OpenTelemetry trace provider.  A Tracer must be safe for concurrent use.
+//
+// Each RPC is traced using a span, and each operation of a transaction is
+// traced using a child of the transaction's span.
+type Tracer interface {
+	// Start starts a span described by info, as a child of any span in
+	// ctx.  The returned context contains the new span.
+	Start(ctx context.Context, info SpanInfo) (context.Context, Span)
+}
+
+// A Span is a single traced operation created by a Tracer.
+type Span interface {
+	// End completes the span.  err is the error which occurred during the
+	// span, if any.
+	End(err error)
+}
+
+// SpanInfo describes a Span.
+type SpanInfo struct {
+	// The name of the span, such as "ovsdb.transact" for an RPC, or
+	// "ovsdb.transact.insert" for an operation within a transaction.
+	Name string
+
+	// The RPC method.
+	Method string
+
+	// The database used by the RPC, if any.
+	Database string
+
+	// For operations within a transaction, the type of operation, such as
+	// "insert", and the table it applies to.
+	Operation, Table string
+}
+
+// Tracing specifies a Tracer which traces each RPC performed by a Client.
+func Tracing(t Tracer) OptionFunc {
+	return func(c *Client) error {
+		c.tracer = t
+		return nil
+	}
+}
+
+// startSpan starts tracing an RPC if a Tracer is configured.  The returned
+// function must be invoked with the RPC's output and error to end the spans.
+func (c *Client) startSpan(ctx context.Context, method string, arg interface{}) (context.Context, func(out interface{}, err error)) {
+	if c.tracer == nil {
+		return ctx, func(interface{}, error) {}
+	}
+
+	info := SpanInfo{
+		Name:     "ovsdb." + method,
+		Method:   method,
+		Database: rpcDatabase(method, arg),
+	}
+
+	ctx, span := c.tracer.Start(ctx, info)
+
+	// Trace each operation of a transaction as a child of its span.
+	targ, ok := arg.(transactArg)
+	if !ok {
+		return ctx, func(_ interface{}, err error) {
+			span.End(err)
+		}
+	}
+
+	ops := make([]Span, 0, len(targ.Ops))
+	for _, op := range targ.Ops {
+		oi := info
+		oi.Operation, oi.Table = opInfo(op)
+		oi.Name = info.Name + "." + oi.Operation
+
+		_, s := c.tracer.Start(ctx, oi)
+		ops = append(ops, s)
+	}
+
+	return ctx, func(out interface{}, err error) {
+		var results []OperationResult
+		if p, ok := out.(*[]OperationResult); ok && err == nil {
+			results = *p
+		}
+
+		// As with Client.Transact, the transaction fails with the first
+		// error reported by the server.
+		txnErr := err
+		for i, s := range ops {
+			switch {
+			case err != nil:
+				// The transaction as a whole failed.
+				s.End(err)
+			case i < len(results) && results[i].Error != nil:
+				if txnErr == nil {
+					txnErr = results[i].Error
+				}
+
+				s.End(results[i].Error)
+			default:
+				s.End(nil)
+			}
+		}
+
+		// An additional result reports a failure to commit.
+		for i := len(ops); i < len(results) && txnErr == nil; i++ {
+			if results[i].Error != nil {
+				txnErr = results[i].Error
+			}
+		}
+
+		span.End(txnErr)
+	}
+}
+
+// rpcDatabase returns the database used by an RPC, if any.
+func rpcDatabase(method string, arg interface{}) string {
+	switch method {
+	case "transact":
+		if targ, ok := arg.(transactArg); ok {
+			return targ.Database
+		}
+	case "get_schema":
+		if args, ok := arg.([]string); ok && len(args) > 0 {
+			return args[0]
+		}
+	case "monitor", "monitor_cond", "monitor_cond_since":
+		if args, ok := arg.([]interface{}); ok && len(args) > 0 {
+			db, _ := args[0].(string)
+			return db
+		}
+	}
+
+	return ""
+}
+
+// opInfo returns the type of a transaction operation and the table it
+// applies to.
+func opInfo(op TransactOp) (string, string) {
+	switch op := op.(type) {
+	case Select:
+		return "select", op.Table
+	case Insert:
+		return "insert", op.Table
+	case Update:
+		return "update", op.Table
+	case Mutate:
+		return "mutate", op.Table
+	case Wait:
+		return "wait", op.Table
+	default:
+		return fmt.Sprintf("%T", op), ""
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientTracing(t *testing.T) {
+	const db = "Open_vSwitch"
+
+	tr := &testTracer{}
+
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		switch req.Method {
+		case "get_schema":
+			return jsonrpc.Response{
+				ID:     &req.ID,
+				Result: mustMarshalJSON(t, &ovsdb.Error{Err: "unknown database"}),
+			}
+		case "transact":
+			return jsonrpc.Response{
+				ID: &req.ID,
+				Result: []byte(`[
+					{"rows":[]},
+					{"error":"constraint violation","details":"duplicate name"},
+					null
+				]`),
+			}
+		default:
+			panicf("unexpected RPC method: %q", req.Method)
+			return jsonrpc.Response{}
+		}
+	}, ovsdb.Tracing(tr))
+	defer done()
+
+	ctx := context.WithValue(context.Background(), parentKey{}, "root")
+
+	if _, err := c.GetSchema(ctx, db); err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	_, err := c.Transact(ctx, db, []ovsdb.TransactOp{
+		ovsdb.Select{Table: "Bridge"},
+		ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"name": "br0"}},
+		ovsdb.Mutate{Table: "Open_vSwitch"},
+	})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+
+	const (
+		schemaErr = "unknown database: : "
+		txnErr    = "constraint violation: duplicate name: "
+	)
+
+	want := []testSpan{
+		{
+			Parent: "root",
+			Info:   ovsdb.SpanInfo{Name: "ovsdb.get_schema", Method: "get_schema", Database: db},
+			Err:    schemaErr,
+		},
+		{
+			Parent: "ovsdb.transact",
+			Info:   ovsdb.SpanInfo{Name: "ovsdb.transact.select", Method: "transact", Database: db, Operation: "select", Table: "Bridge"},
+		},
+		{
+			Parent: "ovsdb.transact",
+			Info:   ovsdb.SpanInfo{Name: "ovsdb.transact.insert", Method: "transact", Database: db, Operation: "insert", Table: "Bridge"},
+			Err:    txnErr,
+		},
+		{
+			Parent: "ovsdb.transact",
+			Info:   ovsdb.SpanInfo{Name: "ovsdb.transact.mutate", Method: "transact", Database: db, Operation: "mutate", Table: "Open_vSwitch"},
+		},
+		{
+			Parent: "root",
+			Info:   ovsdb.SpanInfo{Name: "ovsdb.transact", Method: "transact", Database: db},
+			Err:    txnErr,
+		},
+	}
+
+	if diff := cmp.Diff(want, tr.ended()); diff != "" {
+		t.Fatalf("unexpected spans (-want +got):\n%s", diff)
+	}
+}
+
+type parentKey struct{}
+
+// A testTracer records the spans it creates.
+type testTracer struct {
+	mu    sync.Mutex
+	spans []testSpan
+}
+
+// A testSpan is a span recorded by a testTracer once it ends.
+type testSpan struct {
+	Parent string
+	Info   ovsdb.SpanInfo
+	Err    string
+}
+
+func (t *testTracer) Start(ctx context.Context, info ovsdb.SpanInfo) (context.Context, ovsdb.Span) {
+	parent, _ := ctx.Value(parentKey{}).(string)
+
+	s := &testTracerSpan{
+		t: t,
+		s: testSpan{
+			Parent: parent,
+			Info:   info,
+		},
+	}
+
+	return context.WithValue(ctx, parentKey{}, info.Name), s
+}
+
+// ended returns the spans which have ended.
+func (t *testTracer) ended() []testSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.spans
+}
+
+// A testTracerSpan is an ovsdb.Span created by a testTracer.
+type testTracerSpan struct {
+	t *testTracer
+	s testSpan
+}
+
+func (s *testTracerSpan) End(err error) {
+	if err != nil {
+		s.s.Err = err.Error()
+	}
+
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+
+	s.t.spans = append(s.t.spans, s.s)
+}
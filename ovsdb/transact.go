@@ -14,22 +14,85 @@
 
 package ovsdb
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // A Cond is a conditional expression which is evaluated by the OVSDB server
 // in a transaction.
 type Cond struct {
-	Column, Function, Value string
+	Column, Function string
+	Value            interface{}
 }
 
-// TODO(mdlayher): more helper functions?  Cond as an interface?
+// Functions which can be used to compare a column's value in a Cond.
+const (
+	FunctionLessThan           = "<"
+	FunctionLessThanOrEqual    = "<="
+	FunctionEqual              = "=="
+	FunctionNotEqual           = "!="
+	FunctionGreaterThan        = ">"
+	FunctionGreaterThanOrEqual = ">="
+	FunctionIncludes           = "includes"
+	FunctionExcludes           = "excludes"
+)
 
 // Equal creates a Cond that ensures a column's value equals the
 // specified value.
-func Equal(column, value string) Cond {
+func Equal(column string, value interface{}) Cond {
+	return newCond(column, FunctionEqual, value)
+}
+
+// NotEqual creates a Cond that ensures a column's value does not equal the
+// specified value.
+func NotEqual(column string, value interface{}) Cond {
+	return newCond(column, FunctionNotEqual, value)
+}
+
+// LessThan creates a Cond that ensures a column's value is less than the
+// specified value.  Only valid for integer and real columns.
+func LessThan(column string, value interface{}) Cond {
+	return newCond(column, FunctionLessThan, value)
+}
+
+// LessThanOrEqual creates a Cond that ensures a column's value is less than
+// or equal to the specified value.  Only valid for integer and real columns.
+func LessThanOrEqual(column string, value interface{}) Cond {
+	return newCond(column, FunctionLessThanOrEqual, value)
+}
+
+// GreaterThan creates a Cond that ensures a column's value is greater than
+// the specified value.  Only valid for integer and real columns.
+func GreaterThan(column string, value interface{}) Cond {
+	return newCond(column, FunctionGreaterThan, value)
+}
+
+// GreaterThanOrEqual creates a Cond that ensures a column's value is greater
+// than or equal to the specified value.  Only valid for integer and real
+// columns.
+func GreaterThanOrEqual(column string, value interface{}) Cond {
+	return newCond(column, FunctionGreaterThanOrEqual, value)
+}
+
+// Includes creates a Cond that ensures a set or map column's value contains
+// all of the elements of the specified value.
+func Includes(column string, value interface{}) Cond {
+	return newCond(column, FunctionIncludes, value)
+}
+
+// Excludes creates a Cond that ensures a set or map column's value contains
+// none of the elements of the specified value.
+func Excludes(column string, value interface{}) Cond {
+	return newCond(column, FunctionExcludes, value)
+}
+
+// newCond creates a Cond using the input parameters.
+func newCond(column, function string, value interface{}) Cond {
 	return Cond{
 		Column:   column,
-		Function: "==",
+		Function: function,
 		Value:    value,
 	}
 }
@@ -37,7 +100,7 @@ func Equal(column, value string) Cond {
 // MarshalJSON implements json.Marshaler.
 func (c Cond) MarshalJSON() ([]byte, error) {
 	// Conditionals are expected in three element arrays.
-	return json.Marshal([3]string{
+	return json.Marshal([3]interface{}{
 		c.Column,
 		c.Function,
 		c.Value,
@@ -59,28 +122,428 @@ type Select struct {
 	// Zero or more Conds for conditional select.
 	Where []Cond
 
-	// TODO(mdlayher): specify columns.
+	// Zero or more columns to return for each row.  If empty, all columns
+	// are returned.
+	Columns []string
 }
 
 // MarshalJSON implements json.Marshaler.
 func (s Select) MarshalJSON() ([]byte, error) {
-	// Send an empty array instead of nil if no where clause.
-	where := s.Where
-	if where == nil {
-		where = []Cond{}
+	sel := struct {
+		Op      string   `json:"op"`
+		Table   string   `json:"table"`
+		Where   []Cond   `json:"where"`
+		Columns []string `json:"columns,omitempty"`
+	}{
+		Op:      "select",
+		Table:   s.Table,
+		Where:   where(s.Where),
+		Columns: s.Columns,
 	}
 
-	sel := struct {
+	return json.Marshal(sel)
+}
+
+// where returns a non-nil slice of Conds, because OVSDB requires an empty
+// array instead of null if no where clause is specified.
+func where(conds []Cond) []Cond {
+	if conds == nil {
+		return []Cond{}
+	}
+
+	return conds
+}
+
+var _ TransactOp = Insert{}
+
+// Insert is a TransactOp which inserts a new row into a table.
+type Insert struct {
+	// The name of the table to insert into.
+	Table string
+
+	// An optional name which can be used to refer to the new row's UUID
+	// elsewhere in the same transaction, using a NamedUUID.
+	UUIDName string
+
+	// The initial column values for the new row.  Columns which are not
+	// specified are set to their default values.
+	Row Row
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i Insert) MarshalJSON() ([]byte, error) {
+	if i.UUIDName != "" && !isID(i.UUIDName) {
+		return nil, fmt.Errorf("invalid insert UUID name: %q", i.UUIDName)
+	}
+
+	// Send an empty object instead of nil if no row.
+	row := i.Row
+	if row == nil {
+		row = Row{}
+	}
+
+	ins := struct {
+		Op       string `json:"op"`
+		Table    string `json:"table"`
+		UUIDName string `json:"uuid-name,omitempty"`
+		Row      Row    `json:"row"`
+	}{
+		Op:       "insert",
+		Table:    i.Table,
+		UUIDName: i.UUIDName,
+		Row:      row,
+	}
+
+	return json.Marshal(ins)
+}
+
+var _ TransactOp = Update{}
+
+// Update is a TransactOp which updates the columns of all rows in a table
+// which match a set of conditions.
+type Update struct {
+	// The name of the table to update.
+	Table string
+
+	// Zero or more Conds which determine the rows to update.  If empty,
+	// all rows in the table are updated.
+	Where []Cond
+
+	// The new column values for the matching rows.
+	Row Row
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u Update) MarshalJSON() ([]byte, error) {
+	row := u.Row
+	if row == nil {
+		row = Row{}
+	}
+
+	upd := struct {
 		Op    string `json:"op"`
 		Table string `json:"table"`
 		Where []Cond `json:"where"`
+		Row   Row    `json:"row"`
 	}{
-		Op:    "select",
-		Table: s.Table,
-		Where: where,
+		Op:    "update",
+		Table: u.Table,
+		Where: where(u.Where),
+		Row:   row,
 	}
 
-	return json.Marshal(sel)
+	return json.Marshal(upd)
+}
+
+// A Mutation is an operation which modifies the value of a column in place,
+// for use with Mutate.
+type Mutation struct {
+	Column, Mutator string
+	Value           interface{}
+}
+
+// Mutators which can be used to modify a column's value in a Mutation.
+const (
+	MutatorAdd      = "+="
+	MutatorSubtract = "-="
+	MutatorMultiply = "*="
+	MutatorDivide   = "/="
+	MutatorModulo   = "%="
+	MutatorInsert   = "insert"
+	MutatorDelete   = "delete"
+)
+
+// MutateAdd creates a Mutation that adds value to an integer or real column,
+// or to each element of a set column of integers or reals.
+func MutateAdd(column string, value interface{}) Mutation {
+	return newMutation(column, MutatorAdd, value)
+}
+
+// MutateSubtract creates a Mutation that subtracts value from an integer or
+// real column, or from each element of a set column of integers or reals.
+func MutateSubtract(column string, value interface{}) Mutation {
+	return newMutation(column, MutatorSubtract, value)
+}
+
+// MutateInsert creates a Mutation that inserts the elements of value into a
+// set or map column.  For a map column, keys which already exist are left
+// unchanged.
+func MutateInsert(column string, value interface{}) Mutation {
+	return newMutation(column, MutatorInsert, value)
+}
+
+// MutateDelete creates a Mutation that deletes the elements of value from a
+// set or map column.  For a map column, value may either be a map, in
+// which case only matching key/value pairs are removed, or a set of keys.
+func MutateDelete(column string, value interface{}) Mutation {
+	return newMutation(column, MutatorDelete, value)
+}
+
+// newMutation creates a Mutation using the input parameters.
+func newMutation(column, mutator string, value interface{}) Mutation {
+	return Mutation{
+		Column:  column,
+		Mutator: mutator,
+		Value:   value,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Mutation) MarshalJSON() ([]byte, error) {
+	// Mutations are expected in three element arrays.
+	return json.Marshal([3]interface{}{
+		m.Column,
+		m.Mutator,
+		m.Value,
+	})
+}
+
+var _ TransactOp = Mutate{}
+
+// Mutate is a TransactOp which applies one or more Mutations to all rows in
+// a table which match a set of conditions.
+type Mutate struct {
+	// The name of the table to mutate.
+	Table string
+
+	// Zero or more Conds which determine the rows to mutate.  If empty,
+	// all rows in the table are mutated.
+	Where []Cond
+
+	// The Mutations to apply to the matching rows.
+	Mutations []Mutation
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Mutate) MarshalJSON() ([]byte, error) {
+	mutations := m.Mutations
+	if mutations == nil {
+		mutations = []Mutation{}
+	}
+
+	mut := struct {
+		Op        string     `json:"op"`
+		Table     string     `json:"table"`
+		Where     []Cond     `json:"where"`
+		Mutations []Mutation `json:"mutations"`
+	}{
+		Op:        "mutate",
+		Table:     m.Table,
+		Where:     where(m.Where),
+		Mutations: mutations,
+	}
+
+	return json.Marshal(mut)
+}
+
+var _ TransactOp = Delete{}
+
+// Delete is a TransactOp which deletes all rows in a table which match a set
+// of conditions.
+type Delete struct {
+	// The name of the table to delete from.
+	Table string
+
+	// Zero or more Conds which determine the rows to delete.  If empty,
+	// all rows in the table are deleted.
+	Where []Cond
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Delete) MarshalJSON() ([]byte, error) {
+	del := struct {
+		Op    string `json:"op"`
+		Table string `json:"table"`
+		Where []Cond `json:"where"`
+	}{
+		Op:    "delete",
+		Table: d.Table,
+		Where: where(d.Where),
+	}
+
+	return json.Marshal(del)
+}
+
+var _ TransactOp = Wait{}
+
+// Wait is a TransactOp which waits until the rows in a table which match a
+// set of conditions are equal (or not equal) to an expected set of rows.
+// If the Wait is not satisfied before its timeout, the transaction is
+// aborted and no later operations are applied.
+//
+// Wait is typically used with a Timeout of 0 to implement optimistic
+// concurrency: the transaction only commits if the database still contains
+// the values the caller previously read.
+type Wait struct {
+	// The name of the table to wait on.
+	Table string
+
+	// Zero or more Conds which determine the rows to compare.
+	Where []Cond
+
+	// The columns to compare for each row.
+	Columns []string
+
+	// Until specifies whether the matching rows must be equal
+	// (FunctionEqual) or not equal (FunctionNotEqual) to Rows for the
+	// operation to succeed.  If empty, FunctionEqual is used.
+	Until string
+
+	// The expected rows, containing only the columns in Columns.
+	Rows []Row
+
+	// The amount of time to wait for the condition to be satisfied, with
+	// millisecond granularity.  A Timeout of 0 fails immediately if the
+	// condition is not satisfied.  A negative Timeout waits indefinitely.
+	Timeout time.Duration
+}
+
+// MarshalJSON implements json.Marshaler.
+func (w Wait) MarshalJSON() ([]byte, error) {
+	until := w.Until
+	switch until {
+	case "":
+		until = FunctionEqual
+	case FunctionEqual, FunctionNotEqual:
+	default:
+		return nil, fmt.Errorf("invalid wait until function: %q", until)
+	}
+
+	columns := w.Columns
+	if columns == nil {
+		columns = []string{}
+	}
+
+	rows := w.Rows
+	if rows == nil {
+		rows = []Row{}
+	}
+
+	// Omit the timeout to wait indefinitely.
+	var timeout *int64
+	if w.Timeout >= 0 {
+		ms := int64(w.Timeout / time.Millisecond)
+		timeout = &ms
+	}
+
+	wait := struct {
+		Op      string   `json:"op"`
+		Timeout *int64   `json:"timeout,omitempty"`
+		Table   string   `json:"table"`
+		Where   []Cond   `json:"where"`
+		Columns []string `json:"columns"`
+		Until   string   `json:"until"`
+		Rows    []Row    `json:"rows"`
+	}{
+		Op:      "wait",
+		Timeout: timeout,
+		Table:   w.Table,
+		Where:   where(w.Where),
+		Columns: columns,
+		Until:   until,
+		Rows:    rows,
+	}
+
+	return json.Marshal(wait)
+}
+
+var _ TransactOp = Comment{}
+
+// Comment is a TransactOp which has no effect on the database.  The OVSDB
+// server records its text in the database log along with the transaction,
+// which helps identify the source of a change, as with the comments added
+// by ovs-vsctl.
+type Comment struct {
+	// The text to record.
+	Text string
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Comment) MarshalJSON() ([]byte, error) {
+	comment := struct {
+		Op      string `json:"op"`
+		Comment string `json:"comment"`
+	}{
+		Op:      "comment",
+		Comment: c.Text,
+	}
+
+	return json.Marshal(comment)
+}
+
+var _ TransactOp = Assert{}
+
+// Assert is a TransactOp which aborts the transaction unless the Client owns
+// the specified lock, acquired using Client.Lock or Client.Steal.  Assert is
+// typically the first operation in a transaction which must only be applied
+// by the owner of a lock.
+type Assert struct {
+	// The ID of the lock.
+	Lock string
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Assert) MarshalJSON() ([]byte, error) {
+	if !isID(a.Lock) {
+		return nil, fmt.Errorf("invalid assert lock ID: %q", a.Lock)
+	}
+
+	assert := struct {
+		Op   string `json:"op"`
+		Lock string `json:"lock"`
+	}{
+		Op:   "assert",
+		Lock: a.Lock,
+	}
+
+	return json.Marshal(assert)
+}
+
+// A NamedUUID is a reference to the UUID of a row inserted earlier in the
+// same transaction, using the same name as Insert.UUIDName.  NamedUUIDs can
+// be used as column values in any operation that follows the Insert.
+type NamedUUID string
+
+// MarshalJSON implements json.Marshaler.
+func (n NamedUUID) MarshalJSON() ([]byte, error) {
+	if !isID(string(n)) {
+		return nil, fmt.Errorf("invalid named UUID: %q", string(n))
+	}
+
+	return json.Marshal([2]string{"named-uuid", string(n)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NamedUUID) UnmarshalJSON(b []byte) error {
+	var v [2]string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v[0] != "named-uuid" {
+		return fmt.Errorf("invalid named UUID type: %q", v[0])
+	}
+
+	*n = NamedUUID(v[1])
+	return nil
+}
+
+// isID reports whether s is a valid OVSDB <id>, as described in RFC 7047,
+// section 3.1.
+func isID(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+
+	return true
 }
 
 // A transactArg is used to properly JSON marshal the arguments for a
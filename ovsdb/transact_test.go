@@ -0,0 +1,229 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransactOpMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		op   ovsdb.TransactOp
+		want string
+		ok   bool
+	}{
+		{
+			name: "select",
+			op: ovsdb.Select{
+				Table: "Bridge",
+			},
+			want: `{"op":"select","table":"Bridge","where":[]}`,
+			ok:   true,
+		},
+		{
+			name: "select columns where",
+			op: ovsdb.Select{
+				Table: "Interface",
+				Where: []ovsdb.Cond{
+					ovsdb.NotEqual("name", "br0"),
+					ovsdb.GreaterThanOrEqual("ofport", 1),
+					ovsdb.Includes("external_ids", []interface{}{
+						"map",
+						[][2]string{{"iface-id", "foo"}},
+					}),
+				},
+				Columns: []string{"name", "ofport"},
+			},
+			want: `{"op":"select","table":"Interface","where":[["name","!=","br0"],["ofport","\u003e=",1],["external_ids","includes",["map",[["iface-id","foo"]]]]],"columns":["name","ofport"]}`,
+			ok:   true,
+		},
+		{
+			name: "insert empty row",
+			op: ovsdb.Insert{
+				Table: "Bridge",
+			},
+			want: `{"op":"insert","table":"Bridge","row":{}}`,
+			ok:   true,
+		},
+		{
+			name: "insert bad UUID name",
+			op: ovsdb.Insert{
+				Table:    "Bridge",
+				UUIDName: "1bridge",
+			},
+		},
+		{
+			name: "insert named UUID",
+			op: ovsdb.Insert{
+				Table:    "Bridge",
+				UUIDName: "br0",
+				Row: ovsdb.Row{
+					"name": "br0",
+				},
+			},
+			want: `{"op":"insert","table":"Bridge","uuid-name":"br0","row":{"name":"br0"}}`,
+			ok:   true,
+		},
+		{
+			name: "insert reference named UUID",
+			op: ovsdb.Insert{
+				Table: "Port",
+				Row: ovsdb.Row{
+					"interfaces": ovsdb.NamedUUID("if0"),
+				},
+			},
+			want: `{"op":"insert","table":"Port","row":{"interfaces":["named-uuid","if0"]}}`,
+			ok:   true,
+		},
+		{
+			name: "insert reference bad named UUID",
+			op: ovsdb.Insert{
+				Table: "Port",
+				Row: ovsdb.Row{
+					"interfaces": ovsdb.NamedUUID("if-0"),
+				},
+			},
+		},
+		{
+			name: "update",
+			op: ovsdb.Update{
+				Table: "Bridge",
+				Where: []ovsdb.Cond{
+					ovsdb.Equal("name", "br0"),
+				},
+				Row: ovsdb.Row{
+					"stp_enable": true,
+				},
+			},
+			want: `{"op":"update","table":"Bridge","where":[["name","==","br0"]],"row":{"stp_enable":true}}`,
+			ok:   true,
+		},
+		{
+			name: "mutate empty",
+			op: ovsdb.Mutate{
+				Table: "Bridge",
+			},
+			want: `{"op":"mutate","table":"Bridge","where":[],"mutations":[]}`,
+			ok:   true,
+		},
+		{
+			name: "mutate",
+			op: ovsdb.Mutate{
+				Table: "Open_vSwitch",
+				Mutations: []ovsdb.Mutation{
+					ovsdb.MutateInsert("bridges", ovsdb.NamedUUID("br0")),
+					ovsdb.MutateDelete("external_ids", []interface{}{
+						"set",
+						[]string{"foo"},
+					}),
+					ovsdb.MutateAdd("next_cfg", 1),
+					ovsdb.MutateSubtract("cur_cfg", 1),
+				},
+			},
+			want: `{"op":"mutate","table":"Open_vSwitch","where":[],"mutations":[["bridges","insert",["named-uuid","br0"]],["external_ids","delete",["set",["foo"]]],["next_cfg","+=",1],["cur_cfg","-=",1]]}`,
+			ok:   true,
+		},
+		{
+			name: "delete",
+			op: ovsdb.Delete{
+				Table: "Bridge",
+				Where: []ovsdb.Cond{
+					ovsdb.Equal("name", "br0"),
+				},
+			},
+			want: `{"op":"delete","table":"Bridge","where":[["name","==","br0"]]}`,
+			ok:   true,
+		},
+		{
+			name: "wait",
+			op: ovsdb.Wait{
+				Table: "Bridge",
+				Where: []ovsdb.Cond{
+					ovsdb.Equal("name", "br0"),
+				},
+				Columns: []string{"datapath_type"},
+				Rows: []ovsdb.Row{{
+					"datapath_type": "netdev",
+				}},
+			},
+			want: `{"op":"wait","timeout":0,"table":"Bridge","where":[["name","==","br0"]],"columns":["datapath_type"],"until":"==","rows":[{"datapath_type":"netdev"}]}`,
+			ok:   true,
+		},
+		{
+			name: "wait indefinitely not equal",
+			op: ovsdb.Wait{
+				Table:   "Bridge",
+				Until:   ovsdb.FunctionNotEqual,
+				Timeout: -1,
+			},
+			want: `{"op":"wait","table":"Bridge","where":[],"columns":[],"until":"!=","rows":[]}`,
+			ok:   true,
+		},
+		{
+			name: "wait timeout",
+			op: ovsdb.Wait{
+				Table:   "Bridge",
+				Timeout: 2 * time.Second,
+			},
+			want: `{"op":"wait","timeout":2000,"table":"Bridge","where":[],"columns":[],"until":"==","rows":[]}`,
+			ok:   true,
+		},
+		{
+			name: "wait bad until",
+			op: ovsdb.Wait{
+				Table: "Bridge",
+				Until: ovsdb.FunctionIncludes,
+			},
+		},
+		{
+			name: "comment",
+			op:   ovsdb.Comment{Text: "vswitch: add-br br0"},
+			want: `{"op":"comment","comment":"vswitch: add-br br0"}`,
+			ok:   true,
+		},
+		{
+			name: "assert",
+			op:   ovsdb.Assert{Lock: "ovn_northd"},
+			want: `{"op":"assert","lock":"ovn_northd"}`,
+			ok:   true,
+		},
+		{
+			name: "assert bad lock",
+			op:   ovsdb.Assert{Lock: "ovn-northd"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.op)
+			if err != nil && tt.ok {
+				t.Fatalf("failed to marshal JSON: %v", err)
+			}
+			if err == nil && !tt.ok {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			if diff := cmp.Diff(tt.want, string(b)); diff != "" {
+				t.Fatalf("unexpected JSON (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
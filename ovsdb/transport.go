@@ -0,0 +1,68 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"io"
+	"net"
+)
+
+// A Transport is a bidirectional stream of bytes which carries a Client's
+// JSON-RPC messages to and from an OVSDB server.
+//
+// Any net.Conn is a Transport, but a Transport may also be a stream which is
+// not a network connection, such as a WebSocket adapted to io.ReadWriteCloser,
+// a channel of an SSH connection, or a stream relayed by a proxy.  This
+// enables managing an OVSDB server which can only be reached through a
+// bastion host.
+//
+// If a Transport also implements SetWriteDeadline(time.Time) error, as
+// net.Conn does, RPCs whose contexts are canceled while the server is not
+// reading can interrupt their sends.  Otherwise, such sends block until the
+// Transport accepts the data or is closed.  The ReadTimeout and WriteTimeout options
+// similarly require SetReadDeadline and SetWriteDeadline methods.
+type Transport interface {
+	io.ReadWriteCloser
+}
+
+var _ Transport = net.Conn(nil)
+
+// NewTransport creates a Client which communicates with an OVSDB server
+// using an established Transport.
+//
+// To reconnect using a Transport, use the DialTransport option with the
+// Reconnect option.
+func NewTransport(t Transport, options ...OptionFunc) (*Client, error) {
+	client, err := newClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.start(t); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// DialTransport specifies a function which is used to establish a new
+// Transport when the Client must reconnect.  DialTransport is the Transport
+// equivalent of DialFunc.
+func DialTransport(fn func() (Transport, error)) OptionFunc {
+	return func(c *Client) error {
+		c.dial = fn
+		return nil
+	}
+}
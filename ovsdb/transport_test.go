@@ -0,0 +1,119 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewTransport(t *testing.T) {
+	want := []string{"Open_vSwitch"}
+
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		if diff := cmp.Diff("list_dbs", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, want),
+		}
+	})
+	defer d.done()
+
+	c, err := ovsdb.NewTransport(d.transport())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	dbs, err := c.ListDatabases(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list databases: %v", err)
+	}
+
+	if diff := cmp.Diff(want, dbs); diff != "" {
+		t.Fatalf("unexpected databases (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewTransportReconnect(t *testing.T) {
+	d := newTestDialer(t, func(req jsonrpc.Request) jsonrpc.Response {
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: mustMarshalJSON(t, req.Params),
+		}
+	})
+	defer d.done()
+
+	stateC := make(chan ovsdb.ConnState, 8)
+
+	c, err := ovsdb.NewTransport(
+		d.transport(),
+		ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond),
+		ovsdb.DialTransport(func() (ovsdb.Transport, error) {
+			return d.transport(), nil
+		}),
+		ovsdb.OnStateChange(func(s ovsdb.ConnState) {
+			stateC <- s
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	d.drop()
+
+	for _, want := range []ovsdb.ConnState{ovsdb.StateDisconnected, ovsdb.StateConnected} {
+		select {
+		case got := <-stateC:
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected state (-want +got):\n%s", diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for state %s", want)
+		}
+	}
+
+	if err := c.Echo(context.Background()); err != nil {
+		t.Fatalf("failed to echo after reconnecting: %v", err)
+	}
+}
+
+func TestNewTransportReconnectNoDialFunc(t *testing.T) {
+	d := newTestDialer(t, nil)
+	defer d.done()
+
+	_, err := ovsdb.NewTransport(d.transport(), ovsdb.Reconnect(time.Second, time.Second))
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// transport dials a new connection to a test server, and hides all of its
+// methods other than those of io.ReadWriteCloser.
+func (d *testDialer) transport() ovsdb.Transport {
+	conn, _ := d.dial()
+
+	return struct{ io.ReadWriteCloser }{conn}
+}
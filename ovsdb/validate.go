@@ -0,0 +1,542 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ValidateTransactions enables client-side validation of transactions using
+// the specified schemas, which are typically fetched using Client.GetSchema.
+//
+// Before a transaction is sent to a database whose name matches one of the
+// schemas, Client.Transact checks each operation using Schema.ValidateOps, and
+// returns a *ValidationError without performing the RPC if an operation is
+// invalid.  Transactions on other databases are not validated.
+func ValidateTransactions(schemas ...*Schema) OptionFunc {
+	return func(c *Client) error {
+		if c.schemas == nil {
+			c.schemas = make(map[string]*Schema, len(schemas))
+		}
+
+		for _, s := range schemas {
+			if s == nil || s.Name == "" {
+				return errors.New("validating transactions requires named schemas")
+			}
+
+			c.schemas[s.Name] = s
+		}
+
+		return nil
+	}
+}
+
+// validationSchema returns the schema used to validate transactions on db, or
+// nil if transactions on db are not validated.
+func (c *Client) validationSchema(db string) *Schema {
+	c.schemaMu.RLock()
+	defer c.schemaMu.RUnlock()
+
+	return c.schemas[db]
+}
+
+// setValidationSchema replaces the schema used to validate transactions on
+// the database named by s, if transactions on that database are validated.
+func (c *Client) setValidationSchema(s *Schema) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+
+	if _, ok := c.schemas[s.Name]; ok {
+		c.schemas[s.Name] = s
+	}
+}
+
+// A ValidationError describes an operation which is not valid according to a
+// Schema.
+type ValidationError struct {
+	// The index of the operation within its transaction.
+	Op int
+
+	// The table and column involved, if any.
+	Table, Column string
+
+	// A description of the problem.
+	Reason string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	s := fmt.Sprintf("invalid operation %d", e.Op)
+	if e.Table != "" {
+		s += fmt.Sprintf(" on table %q", e.Table)
+	}
+	if e.Column != "" {
+		s += fmt.Sprintf(", column %q", e.Column)
+	}
+
+	return s + ": " + e.Reason
+}
+
+// ValidateOps checks ops against the schema, and returns a *ValidationError
+// describing the first invalid operation, if any.
+//
+// The tables and columns referred to by each operation must exist, and each
+// value must match the type and constraints of its column.  Updates and
+// mutations must not modify immutable columns, and mutators must be valid for
+// their columns.  References to other rows and the uniqueness of indexes are
+// not checked, as they depend on the contents of the database.
+func (s *Schema) ValidateOps(ops []TransactOp) error {
+	for i, op := range ops {
+		b, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+
+		// Preserve the precision of integers.
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+
+		var o operation
+		if err := d.Decode(&o); err != nil {
+			return &ValidationError{
+				Op:     i,
+				Reason: fmt.Sprintf("failed to decode operation: %v", err),
+			}
+		}
+
+		if err := s.validateOp(o); err != nil {
+			err.Op = i
+			return err
+		}
+	}
+
+	return nil
+}
+
+// An operation is the decoded JSON encoding of a TransactOp.
+type operation struct {
+	Op        string          `json:"op"`
+	Table     string          `json:"table"`
+	Where     [][]interface{} `json:"where"`
+	Columns   []string        `json:"columns"`
+	Row       Row             `json:"row"`
+	Rows      []Row           `json:"rows"`
+	Mutations [][]interface{} `json:"mutations"`
+}
+
+// validateOp checks a single operation.
+func (s *Schema) validateOp(o operation) *ValidationError {
+	switch o.Op {
+	case "select", "insert", "update", "mutate", "delete", "wait":
+	default:
+		// Other operations do not refer to tables.
+		return nil
+	}
+
+	ts, ok := s.Tables[o.Table]
+	if !ok {
+		return &ValidationError{
+			Table:  o.Table,
+			Reason: fmt.Sprintf("table does not exist in database %q", s.Name),
+		}
+	}
+
+	v := &opValidator{
+		table: o.Table,
+		ts:    ts,
+	}
+
+	for _, c := range o.Where {
+		if err := v.cond(c); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range o.Columns {
+		if _, err := v.column(c); err != nil {
+			return err
+		}
+	}
+
+	switch o.Op {
+	case "insert", "update":
+		for c, d := range o.Row {
+			cs, err := v.writable(c, o.Op == "update")
+			if err != nil {
+				return err
+			}
+
+			if err := checkDatum(cs.Type, d); err != nil {
+				return v.fail(c, err.Error())
+			}
+		}
+	case "mutate":
+		for _, m := range o.Mutations {
+			if err := v.mutation(m); err != nil {
+				return err
+			}
+		}
+	case "wait":
+		for _, row := range o.Rows {
+			for c, d := range row {
+				cs, err := v.column(c)
+				if err != nil {
+					return err
+				}
+
+				if err := checkDatum(cs.Type, d); err != nil {
+					return v.fail(c, err.Error())
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// An opValidator validates the columns of an operation on a single table.
+type opValidator struct {
+	table string
+	ts    TableSchema
+}
+
+// fail returns a *ValidationError for column.
+func (v *opValidator) fail(column, reason string) *ValidationError {
+	return &ValidationError{
+		Table:  v.table,
+		Column: column,
+		Reason: reason,
+	}
+}
+
+// uuidColumn is the schema of the _uuid and _version columns.
+var uuidColumn = ColumnSchema{
+	Type: ColumnType{
+		Key: BaseType{Type: TypeUUID},
+		Min: 1,
+		Max: 1,
+	},
+}
+
+// column returns the schema of a column, which must exist.
+func (v *opValidator) column(column string) (ColumnSchema, *ValidationError) {
+	switch column {
+	case "_uuid", "_version":
+		return uuidColumn, nil
+	}
+
+	cs, ok := v.ts.Columns[column]
+	if !ok {
+		return ColumnSchema{}, v.fail(column, "column does not exist")
+	}
+
+	return cs, nil
+}
+
+// writable returns the schema of a column which is modified by an operation.
+// If modify is true, the column must also be mutable.
+func (v *opValidator) writable(column string, modify bool) (ColumnSchema, *ValidationError) {
+	cs, err := v.column(column)
+	if err != nil {
+		return ColumnSchema{}, err
+	}
+
+	switch {
+	case column == "_uuid" || column == "_version":
+		return ColumnSchema{}, v.fail(column, "column is read-only")
+	case modify && !cs.Mutable:
+		return ColumnSchema{}, v.fail(column, "column is immutable")
+	}
+
+	return cs, nil
+}
+
+// cond checks a condition of the form [column, function, value].
+func (v *opValidator) cond(c []interface{}) *ValidationError {
+	column, function, ok := triple(c)
+	if !ok {
+		return v.fail("", fmt.Sprintf("invalid condition: %v", c))
+	}
+
+	cs, err := v.column(column)
+	if err != nil {
+		return err
+	}
+
+	typ := cs.Type
+	switch function {
+	case FunctionEqual, FunctionNotEqual:
+	case FunctionIncludes, FunctionExcludes:
+		// The value may contain any number of elements.
+		typ.Min, typ.Max = 0, Unlimited
+	case FunctionLessThan, FunctionLessThanOrEqual, FunctionGreaterThan, FunctionGreaterThanOrEqual:
+		numeric := typ.Key.Type == TypeInteger || typ.Key.Type == TypeReal
+		if !numeric || typ.IsMap() || typ.Max != 1 {
+			return v.fail(column, fmt.Sprintf("function %s requires an integer or real column", function))
+		}
+	default:
+		return v.fail(column, fmt.Sprintf("unknown function %q", function))
+	}
+
+	if err := checkDatum(typ, c[2]); err != nil {
+		return v.fail(column, err.Error())
+	}
+
+	return nil
+}
+
+// mutation checks a mutation of the form [column, mutator, value].
+func (v *opValidator) mutation(m []interface{}) *ValidationError {
+	column, mutator, ok := triple(m)
+	if !ok {
+		return v.fail("", fmt.Sprintf("invalid mutation: %v", m))
+	}
+
+	cs, err := v.writable(column, true)
+	if err != nil {
+		return err
+	}
+
+	typ := cs.Type
+	switch mutator {
+	case MutatorAdd, MutatorSubtract, MutatorMultiply, MutatorDivide, MutatorModulo:
+		ok := typ.Key.Type == TypeInteger || (typ.Key.Type == TypeReal && mutator != MutatorModulo)
+		if !ok || typ.IsMap() {
+			return v.fail(column, fmt.Sprintf("mutator %s is not valid for the column's type", mutator))
+		}
+
+		// Constraints apply to the result rather than the argument.
+		typ = ColumnType{Key: BaseType{Type: typ.Key.Type}, Min: 1, Max: 1}
+	case MutatorInsert, MutatorDelete:
+		if typ.Min == 1 && typ.Max == 1 {
+			return v.fail(column, fmt.Sprintf("mutator %s is not valid for a scalar column", mutator))
+		}
+
+		// Map deletions may specify only keys.
+		if typ.IsMap() && mutator == MutatorDelete && !isMapDatum(m[2]) {
+			typ.Value = nil
+		}
+
+		typ.Min, typ.Max = 0, Unlimited
+	default:
+		return v.fail(column, fmt.Sprintf("unknown mutator %q", mutator))
+	}
+
+	if err := checkDatum(typ, m[2]); err != nil {
+		return v.fail(column, err.Error())
+	}
+
+	return nil
+}
+
+// triple returns the column and function or mutator of a three element
+// condition or mutation.
+func triple(v []interface{}) (string, string, bool) {
+	if len(v) != 3 {
+		return "", "", false
+	}
+
+	a, ok1 := v[0].(string)
+	b, ok2 := v[1].(string)
+	return a, b, ok1 && ok2
+}
+
+// isMapDatum reports whether v is the JSON encoding of a map.
+func isMapDatum(v interface{}) bool {
+	a, ok := v.([]interface{})
+	return ok && len(a) == 2 && a[0] == "map"
+}
+
+// checkDatum checks that the JSON encoding of a column value matches typ.
+func checkDatum(typ ColumnType, v interface{}) error {
+	var n int
+	if typ.IsMap() {
+		pairs, ok := pairsOf(v)
+		if !ok {
+			return fmt.Errorf("expected a map, but got %v", v)
+		}
+
+		for _, p := range pairs {
+			if err := checkAtom(typ.Key, p[0]); err != nil {
+				return fmt.Errorf("invalid map key: %v", err)
+			}
+			if err := checkAtom(*typ.Value, p[1]); err != nil {
+				return fmt.Errorf("invalid map value: %v", err)
+			}
+		}
+
+		n = len(pairs)
+	} else {
+		elems := []interface{}{v}
+		if a, ok := v.([]interface{}); ok && len(a) == 2 && a[0] == "set" {
+			if elems, ok = a[1].([]interface{}); !ok {
+				return fmt.Errorf("invalid set: %v", v)
+			}
+		}
+
+		for _, e := range elems {
+			if err := checkAtom(typ.Key, e); err != nil {
+				return err
+			}
+		}
+
+		n = len(elems)
+	}
+
+	if n < typ.Min || (typ.Max != Unlimited && n > typ.Max) {
+		max := "unlimited"
+		if typ.Max != Unlimited {
+			max = fmt.Sprint(typ.Max)
+		}
+
+		return fmt.Errorf("value has %d elements, but must have between %d and %s", n, typ.Min, max)
+	}
+
+	return nil
+}
+
+// pairsOf returns the key/value pairs of the JSON encoding of a map.
+func pairsOf(v interface{}) ([][]interface{}, bool) {
+	if !isMapDatum(v) {
+		return nil, false
+	}
+
+	elems, ok := v.([]interface{})[1].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	out := make([][]interface{}, 0, len(elems))
+	for _, e := range elems {
+		p, ok := e.([]interface{})
+		if !ok || len(p) != 2 {
+			return nil, false
+		}
+
+		out = append(out, p)
+	}
+
+	return out, true
+}
+
+// checkAtom checks that the JSON encoding of an atom matches typ.
+func checkAtom(typ BaseType, v interface{}) error {
+	switch typ.Type {
+	case TypeInteger:
+		i, ok := integerOf(v)
+		if !ok {
+			return fmt.Errorf("expected an integer, but got %v", v)
+		}
+
+		if (typ.MinInteger != nil && i < *typ.MinInteger) || (typ.MaxInteger != nil && i > *typ.MaxInteger) {
+			return fmt.Errorf("integer %d is out of range", i)
+		}
+	case TypeReal:
+		f, ok := realOf(v)
+		if !ok {
+			return fmt.Errorf("expected a real, but got %v", v)
+		}
+
+		if (typ.MinReal != nil && f < *typ.MinReal) || (typ.MaxReal != nil && f > *typ.MaxReal) {
+			return fmt.Errorf("real %v is out of range", f)
+		}
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expected a boolean, but got %v", v)
+		}
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, but got %v", v)
+		}
+
+		// Lengths are measured in UTF-8 characters.
+		l := utf8.RuneCountInString(s)
+		if (typ.MinLength != nil && l < *typ.MinLength) || (typ.MaxLength != nil && l > *typ.MaxLength) {
+			return fmt.Errorf("string %q has invalid length %d", s, l)
+		}
+	case TypeUUID:
+		a, ok := v.([]interface{})
+		if !ok || len(a) != 2 {
+			return fmt.Errorf("expected a UUID, but got %v", v)
+		}
+
+		s, ok := a[1].(string)
+		switch {
+		case ok && a[0] == "uuid" && isUUID(s):
+		case ok && a[0] == "named-uuid" && isID(s):
+		default:
+			return fmt.Errorf("expected a UUID, but got %v", v)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", typ.Type)
+	}
+
+	if len(typ.Enum) > 0 && !inEnum(typ, v) {
+		return fmt.Errorf("value %v is not one of the permitted values %v", v, typ.Enum)
+	}
+
+	return nil
+}
+
+// integerOf returns the value of an integer atom, which may have been decoded
+// from JSON as a json.Number or an int64.
+func integerOf(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// realOf returns the value of a real atom, which may have been decoded from
+// JSON as a json.Number, an int64, or a float64.
+func realOf(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// inEnum reports whether the atom v is one of the values in typ's enum.
+func inEnum(typ BaseType, v interface{}) bool {
+	if n, ok := v.(json.Number); ok {
+		v = parseNumber(n)
+	}
+	v = realAtom(typ, v)
+
+	for _, e := range typ.Enum {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
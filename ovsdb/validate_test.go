@@ -0,0 +1,325 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const validateSchema = `{
+  "name": "Open_vSwitch",
+  "version": "1.0.0",
+  "tables": {
+    "Bridge": {
+      "columns": {
+        "name": {"type": "string", "mutable": false},
+        "ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+        "fail_mode": {"type": {"key": {"type": "string", "enum": ["set", ["standalone", "secure"]]}, "min": 0, "max": 1}},
+        "stp_enable": {"type": "boolean"},
+        "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}
+      },
+      "isRoot": true
+    },
+    "Port": {
+      "columns": {
+        "name": {"type": {"key": {"type": "string", "maxLength": 15}}},
+        "tag": {"type": {"key": {"type": "integer", "minInteger": 0, "maxInteger": 4095}, "min": 0, "max": 1}},
+        "ratio": {"type": "real"}
+      }
+    }
+  }
+}`
+
+func TestSchemaValidateOps(t *testing.T) {
+	const uuid = ovsdb.UUID("36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2")
+
+	tests := []struct {
+		name string
+		ops  []ovsdb.TransactOp
+		err  *ovsdb.ValidationError
+	}{
+		{
+			name: "OK",
+			ops: []ovsdb.TransactOp{
+				ovsdb.Insert{
+					Table:    "Port",
+					UUIDName: "port0",
+					Row: ovsdb.Row{
+						"name":  "port0",
+						"tag":   ovsdb.Set{10},
+						"ratio": 1.5,
+					},
+				},
+				ovsdb.Insert{
+					Table: "Bridge",
+					Row: ovsdb.Row{
+						"name":         "br0",
+						"ports":        ovsdb.Set{ovsdb.NamedUUID("port0"), uuid},
+						"fail_mode":    "secure",
+						"stp_enable":   true,
+						"external_ids": ovsdb.Map{"foo": "bar"},
+					},
+				},
+				ovsdb.Select{
+					Table:   "Port",
+					Where:   []ovsdb.Cond{ovsdb.LessThan("tag", 100), ovsdb.Equal("_uuid", uuid)},
+					Columns: []string{"name", "_uuid"},
+				},
+				ovsdb.Mutate{
+					Table: "Port",
+					Mutations: []ovsdb.Mutation{
+						ovsdb.MutateAdd("tag", 1),
+						ovsdb.MutateSubtract("ratio", 0.5),
+					},
+				},
+				ovsdb.Mutate{
+					Table: "Bridge",
+					Where: []ovsdb.Cond{ovsdb.Includes("ports", ovsdb.Set{uuid})},
+					Mutations: []ovsdb.Mutation{
+						ovsdb.MutateDelete("external_ids", ovsdb.Set{"foo"}),
+						ovsdb.MutateInsert("external_ids", ovsdb.Map{"foo": "baz"}),
+					},
+				},
+				ovsdb.Wait{
+					Table:   "Bridge",
+					Columns: []string{"name"},
+					Rows:    []ovsdb.Row{{"name": "br0"}},
+				},
+				ovsdb.Delete{Table: "Bridge"},
+			},
+		},
+		{
+			name: "unknown table",
+			ops:  []ovsdb.TransactOp{ovsdb.Select{Table: "foo"}},
+			err:  &ovsdb.ValidationError{Table: "foo"},
+		},
+		{
+			name: "unknown column",
+			ops: []ovsdb.TransactOp{
+				ovsdb.Select{Table: "Bridge"},
+				ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"foo": "bar"}},
+			},
+			err: &ovsdb.ValidationError{Op: 1, Table: "Bridge", Column: "foo"},
+		},
+		{
+			name: "unknown select column",
+			ops:  []ovsdb.TransactOp{ovsdb.Select{Table: "Bridge", Columns: []string{"foo"}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "foo"},
+		},
+		{
+			name: "wrong type",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"stp_enable": "true"}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "stp_enable"},
+		},
+		{
+			name: "not an integer",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Port", Row: ovsdb.Row{"tag": 1.5}}},
+			err:  &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+		{
+			name: "integer out of range",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Port", Row: ovsdb.Row{"tag": 4096}}},
+			err:  &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+		{
+			name: "string too long",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Port", Row: ovsdb.Row{"name": "abcdefghijklmnop"}}},
+			err:  &ovsdb.ValidationError{Table: "Port", Column: "name"},
+		},
+		{
+			name: "not in enum",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"fail_mode": "foo"}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "fail_mode"},
+		},
+		{
+			name: "too many elements",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Port", Row: ovsdb.Row{"tag": ovsdb.Set{1, 2}}}},
+			err:  &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+		{
+			name: "invalid UUID",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"ports": []string{"uuid", "foo"}}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "ports"},
+		},
+		{
+			name: "map expected",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"external_ids": "foo"}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "external_ids"},
+		},
+		{
+			name: "read-only column",
+			ops:  []ovsdb.TransactOp{ovsdb.Insert{Table: "Bridge", Row: ovsdb.Row{"_uuid": uuid}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "_uuid"},
+		},
+		{
+			name: "immutable update",
+			ops:  []ovsdb.TransactOp{ovsdb.Update{Table: "Bridge", Row: ovsdb.Row{"name": "br1"}}},
+			err:  &ovsdb.ValidationError{Table: "Bridge", Column: "name"},
+		},
+		{
+			name: "immutable mutate",
+			ops: []ovsdb.TransactOp{ovsdb.Mutate{
+				Table:     "Bridge",
+				Mutations: []ovsdb.Mutation{ovsdb.MutateInsert("name", "foo")},
+			}},
+			err: &ovsdb.ValidationError{Table: "Bridge", Column: "name"},
+		},
+		{
+			name: "arithmetic on string",
+			ops: []ovsdb.TransactOp{ovsdb.Mutate{
+				Table:     "Port",
+				Mutations: []ovsdb.Mutation{ovsdb.MutateAdd("name", "foo")},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "name"},
+		},
+		{
+			name: "modulo on real",
+			ops: []ovsdb.TransactOp{ovsdb.Mutate{
+				Table:     "Port",
+				Mutations: []ovsdb.Mutation{{Column: "ratio", Mutator: ovsdb.MutatorModulo, Value: 2}},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "ratio"},
+		},
+		{
+			name: "insert on scalar",
+			ops: []ovsdb.TransactOp{ovsdb.Mutate{
+				Table:     "Port",
+				Mutations: []ovsdb.Mutation{ovsdb.MutateInsert("ratio", 1.0)},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "ratio"},
+		},
+		{
+			name: "unknown mutator",
+			ops: []ovsdb.TransactOp{ovsdb.Mutate{
+				Table:     "Port",
+				Mutations: []ovsdb.Mutation{{Column: "tag", Mutator: "^=", Value: 1}},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+		{
+			name: "ordering on string",
+			ops: []ovsdb.TransactOp{ovsdb.Delete{
+				Table: "Port",
+				Where: []ovsdb.Cond{ovsdb.GreaterThan("name", "foo")},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "name"},
+		},
+		{
+			name: "condition type",
+			ops: []ovsdb.TransactOp{ovsdb.Delete{
+				Table: "Port",
+				Where: []ovsdb.Cond{ovsdb.Equal("tag", "foo")},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+		{
+			name: "wait row",
+			ops: []ovsdb.TransactOp{ovsdb.Wait{
+				Table:   "Port",
+				Columns: []string{"tag"},
+				Rows:    []ovsdb.Row{{"tag": -1}},
+			}},
+			err: &ovsdb.ValidationError{Table: "Port", Column: "tag"},
+		},
+	}
+
+	s := mustSchema(t, validateSchema)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateOps(tt.ops)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("failed to validate operations: %v", err)
+				}
+
+				return
+			}
+
+			verr, ok := err.(*ovsdb.ValidationError)
+			if !ok {
+				t.Fatalf("expected *ovsdb.ValidationError, but got: %#v", err)
+			}
+			if verr.Reason == "" {
+				t.Fatal("expected a reason for the validation error")
+			}
+
+			if diff := cmp.Diff(tt.err, verr, cmpopts.IgnoreFields(ovsdb.ValidationError{}, "Reason")); diff != "" {
+				t.Fatalf("unexpected validation error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientValidateTransactions(t *testing.T) {
+	c, _, done := testClient(t, func(req jsonrpc.Request) jsonrpc.Response {
+		var params []interface{}
+		if err := json.Unmarshal(mustMarshalJSON(t, req.Params), &params); err != nil {
+			panicf("failed to unmarshal params: %v", err)
+		}
+
+		// Only transactions on other databases should be sent.
+		if diff := cmp.Diff("foo", params[0]); diff != "" {
+			panicf("unexpected database (-want +got):\n%s", diff)
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: []byte(`[{}]`),
+		}
+	}, ovsdb.ValidateTransactions(mustSchema(t, validateSchema)))
+	defer done()
+
+	ctx := context.Background()
+	ops := []ovsdb.TransactOp{ovsdb.Select{Table: "Interface"}}
+
+	_, err := c.Transact(ctx, "Open_vSwitch", ops)
+	if _, ok := err.(*ovsdb.ValidationError); !ok {
+		t.Fatalf("expected *ovsdb.ValidationError, but got: %#v", err)
+	}
+
+	if _, err := c.Transact(ctx, "foo", ops); err != nil {
+		t.Fatalf("failed to perform transaction: %v", err)
+	}
+}
+
+func TestValidateTransactionsNoSchema(t *testing.T) {
+	conn, _, done := jsonrpc.TestNetConn(t, nil)
+	defer done()
+
+	_, err := ovsdb.New(conn, ovsdb.ValidateTransactions(&ovsdb.Schema{}))
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+// mustSchema unmarshals a Schema from JSON.
+func mustSchema(t *testing.T, s string) *ovsdb.Schema {
+	t.Helper()
+
+	var schema ovsdb.Schema
+	if err := json.Unmarshal([]byte(s), &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	return &schema
+}
@@ -0,0 +1,519 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A UUID is the UUID of a row, which is encoded as ["uuid", <uuid>] in
+// OVSDB JSON.
+type UUID string
+
+var (
+	_ json.Marshaler   = UUID("")
+	_ json.Unmarshaler = (*UUID)(nil)
+)
+
+// MarshalJSON implements json.Marshaler.
+func (u UUID) MarshalJSON() ([]byte, error) {
+	if !isUUID(string(u)) {
+		return nil, fmt.Errorf("invalid UUID: %q", string(u))
+	}
+
+	return json.Marshal([2]string{"uuid", string(u)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *UUID) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	uuid, err := ParseUUID(v)
+	if err != nil {
+		return err
+	}
+
+	*u = uuid
+	return nil
+}
+
+// ParseUUID parses a UUID from a value decoded by encoding/json, such as a
+// column value in a Row.
+func ParseUUID(v interface{}) (UUID, error) {
+	atom, err := decodeAtom(v)
+	if err != nil {
+		return "", err
+	}
+
+	u, ok := atom.(UUID)
+	if !ok {
+		return "", fmt.Errorf("invalid UUID: %v", v)
+	}
+
+	return u, nil
+}
+
+// A Set is an OVSDB set, which is encoded as ["set", [<atom>...]] in OVSDB
+// JSON.  The elements of a Set decoded from JSON are strings, int64s,
+// float64s, bools, UUIDs, or NamedUUIDs.
+type Set []interface{}
+
+var (
+	_ json.Marshaler   = Set(nil)
+	_ json.Unmarshaler = (*Set)(nil)
+)
+
+// NewSet creates a Set from a slice or array of atoms, such as a []string.
+func NewSet(slice interface{}) (Set, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot create set from %T", slice)
+	}
+
+	s := make(Set, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		atom, err := encodeAtom(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		s = append(s, atom)
+	}
+
+	return s, nil
+}
+
+// ParseSet parses a Set from a value decoded by encoding/json, such as a
+// column value in a Row.  A single atom is parsed as a Set with one element,
+// as OVSDB permits.
+func ParseSet(v interface{}) (Set, error) {
+	if s, ok := v.(Set); ok {
+		return s, nil
+	}
+
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "set" {
+		atom, err := decodeAtom(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return Set{atom}, nil
+	}
+
+	elems, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid set: %v", v)
+	}
+
+	s := make(Set, 0, len(elems))
+	for _, e := range elems {
+		atom, err := decodeAtom(e)
+		if err != nil {
+			return nil, err
+		}
+
+		s = append(s, atom)
+	}
+
+	return s, nil
+}
+
+// Decode stores the elements of the Set in the slice pointed to by out,
+// converting them to the slice's element type.
+func (s Set) Decode(out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("cannot decode set into %T", out)
+	}
+
+	typ := v.Elem().Type()
+	slice := reflect.MakeSlice(typ, 0, len(s))
+	for _, e := range s {
+		ev, err := convertAtom(e, typ.Elem())
+		if err != nil {
+			return err
+		}
+
+		slice = reflect.Append(slice, ev)
+	}
+
+	v.Elem().Set(slice)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Set) MarshalJSON() ([]byte, error) {
+	elems := []interface{}(s)
+	if elems == nil {
+		elems = []interface{}{}
+	}
+
+	return json.Marshal([]interface{}{"set", elems})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Set) UnmarshalJSON(b []byte) error {
+	v, err := decodeNumbers(b)
+	if err != nil {
+		return err
+	}
+
+	set, err := ParseSet(v)
+	if err != nil {
+		return err
+	}
+
+	*s = set
+	return nil
+}
+
+// A Map is an OVSDB map, which is encoded as ["map", [[<atom>, <atom>]...]]
+// in OVSDB JSON.  The keys and values of a Map decoded from JSON are strings,
+// int64s, float64s, bools, UUIDs, or NamedUUIDs.
+type Map map[interface{}]interface{}
+
+var (
+	_ json.Marshaler   = Map(nil)
+	_ json.Unmarshaler = (*Map)(nil)
+)
+
+// NewMap creates a Map from a map of atoms, such as a map[string]string.
+func NewMap(m interface{}) (Map, error) {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("cannot create map from %T", m)
+	}
+
+	out := make(Map, v.Len())
+	for _, k := range v.MapKeys() {
+		key, err := encodeAtom(k)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := encodeAtom(v.MapIndex(k))
+		if err != nil {
+			return nil, err
+		}
+
+		out[key] = value
+	}
+
+	return out, nil
+}
+
+// ParseMap parses a Map from a value decoded by encoding/json, such as a
+// column value in a Row.
+func ParseMap(v interface{}) (Map, error) {
+	if m, ok := v.(Map); ok {
+		return m, nil
+	}
+
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 || a[0] != "map" {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	pairs, ok := a[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid map: %v", v)
+	}
+
+	m := make(Map, len(pairs))
+	for _, p := range pairs {
+		kv, ok := p.([]interface{})
+		if !ok || len(kv) != 2 {
+			return nil, fmt.Errorf("invalid map pair: %v", p)
+		}
+
+		key, err := decodeAtom(kv[0])
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := decodeAtom(kv[1])
+		if err != nil {
+			return nil, err
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// Decode stores the pairs of the Map in the map pointed to by out, converting
+// them to the map's key and element types.
+func (m Map) Decode(out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Map {
+		return fmt.Errorf("cannot decode map into %T", out)
+	}
+
+	typ := v.Elem().Type()
+	mv := reflect.MakeMapWithSize(typ, len(m))
+	for k, e := range m {
+		kv, err := convertAtom(k, typ.Key())
+		if err != nil {
+			return err
+		}
+
+		ev, err := convertAtom(e, typ.Elem())
+		if err != nil {
+			return err
+		}
+
+		mv.SetMapIndex(kv, ev)
+	}
+
+	v.Elem().Set(mv)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Map) MarshalJSON() ([]byte, error) {
+	// Sort the pairs so the encoding is stable.
+	pairs := make([][2]interface{}, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, [2]interface{}{k, v})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return fmt.Sprint(pairs[i][0]) < fmt.Sprint(pairs[j][0])
+	})
+
+	return json.Marshal([]interface{}{"map", pairs})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Map) UnmarshalJSON(b []byte) error {
+	v, err := decodeNumbers(b)
+	if err != nil {
+		return err
+	}
+
+	mm, err := ParseMap(v)
+	if err != nil {
+		return err
+	}
+
+	*m = mm
+	return nil
+}
+
+// decodeNumbers decodes the JSON value in b like json.Unmarshal into an
+// interface{}, but decodes integers as int64s rather than float64s, so that
+// 64-bit integers such as OpenFlow cookies and tunnel keys are not rounded.
+// Other numbers are decoded as float64s.
+func decodeNumbers(b []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return convertNumbers(v), nil
+}
+
+// convertNumbers replaces the json.Numbers in v, which was decoded using
+// json.Decoder.UseNumber, with int64s or float64s.
+func convertNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		return parseNumber(x)
+	case []interface{}:
+		for i := range x {
+			x[i] = convertNumbers(x[i])
+		}
+	case map[string]interface{}:
+		for k := range x {
+			x[k] = convertNumbers(x[k])
+		}
+	}
+
+	return v
+}
+
+// parseNumber converts n to an int64 if it is an integer which fits in 64
+// bits, or a float64 otherwise.
+func parseNumber(n json.Number) interface{} {
+	if !strings.ContainsAny(string(n), ".eE") {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return n
+}
+
+// decodeAtom converts an atom decoded by encoding/json into a UUID or
+// NamedUUID if needed.
+func decodeAtom(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case string, int64, float64, bool, json.Number, UUID, NamedUUID:
+		return v, nil
+	}
+
+	a, ok := v.([]interface{})
+	if !ok || len(a) != 2 {
+		return nil, fmt.Errorf("invalid atom: %v", v)
+	}
+
+	s, ok := a[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid atom: %v", v)
+	}
+
+	switch a[0] {
+	case "uuid":
+		return UUID(s), nil
+	case "named-uuid":
+		return NamedUUID(s), nil
+	default:
+		return nil, fmt.Errorf("invalid atom: %v", v)
+	}
+}
+
+// encodeAtom returns the value of an atom for use in a Set or Map.
+func encodeAtom(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return v.Interface(), nil
+	case reflect.Invalid:
+		return nil, fmt.Errorf("invalid nil atom")
+	default:
+		return nil, fmt.Errorf("invalid atom of type %s", v.Type())
+	}
+}
+
+// convertAtom converts an atom to type typ.
+func convertAtom(atom interface{}, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(atom)
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot convert nil atom to %s", typ)
+	}
+
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+
+	if n, ok := atom.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v = reflect.ValueOf(f)
+	}
+
+	bad := fmt.Errorf("cannot convert %T atom %v to %s", atom, atom, typ)
+
+	switch typ.Kind() {
+	case reflect.String:
+		if v.Kind() != reflect.String {
+			return reflect.Value{}, bad
+		}
+	case reflect.Bool:
+		if v.Kind() != reflect.Bool {
+			return reflect.Value{}, bad
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := toInt(v)
+		if !ok || reflect.Zero(typ).OverflowInt(i) {
+			return reflect.Value{}, bad
+		}
+
+		v = reflect.ValueOf(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := toInt(v)
+		if !ok || i < 0 || reflect.Zero(typ).OverflowUint(uint64(i)) {
+			return reflect.Value{}, bad
+		}
+
+		v = reflect.ValueOf(i)
+	case reflect.Float32, reflect.Float64:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			return reflect.Value{}, bad
+		}
+	default:
+		return reflect.Value{}, bad
+	}
+
+	return v.Convert(typ), nil
+}
+
+// toInt converts an integer or integral float value to an int64.
+func toInt(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f != float64(int64(f)) {
+			return 0, false
+		}
+
+		return int64(f), true
+	default:
+		return 0, false
+	}
+}
+
+// isUUID reports whether s is in the canonical UUID format.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,347 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/google/go-cmp/cmp"
+)
+
+const valueUUID = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+
+func TestValueJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		v    interface{}
+		out  interface{}
+	}{
+		{
+			name: "UUID",
+			s:    `["uuid","` + valueUUID + `"]`,
+			v:    ovsdb.UUID(valueUUID),
+			out:  new(ovsdb.UUID),
+		},
+		{
+			name: "named UUID",
+			s:    `["named-uuid","row0"]`,
+			v:    ovsdb.NamedUUID("row0"),
+			out:  new(ovsdb.NamedUUID),
+		},
+		{
+			name: "empty set",
+			s:    `["set",[]]`,
+			v:    ovsdb.Set{},
+			out:  new(ovsdb.Set),
+		},
+		{
+			name: "set",
+			s:    `["set",["foo",1,true,["uuid","` + valueUUID + `"],["named-uuid","row0"]]]`,
+			v:    ovsdb.Set{"foo", int64(1), true, ovsdb.UUID(valueUUID), ovsdb.NamedUUID("row0")},
+			out:  new(ovsdb.Set),
+		},
+		{
+			name: "set numbers",
+			s:    `["set",[9223372036854775807,-4611686018427387903,1.5]]`,
+			v:    ovsdb.Set{int64(math.MaxInt64), int64(-1<<62 + 1), 1.5},
+			out:  new(ovsdb.Set),
+		},
+		{
+			name: "map",
+			s:    `["map",[["bar",["uuid","` + valueUUID + `"]],["foo",2]]]`,
+			v: ovsdb.Map{
+				"foo": int64(2),
+				"bar": ovsdb.UUID(valueUUID),
+			},
+			out: new(ovsdb.Map),
+		},
+		{
+			name: "map numbers",
+			s:    `["map",[[4611686018427387905,0.25]]]`,
+			v: ovsdb.Map{
+				int64(1<<62 + 1): 0.25,
+			},
+			out: new(ovsdb.Map),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("failed to marshal JSON: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.s, string(b)); diff != "" {
+				t.Fatalf("unexpected JSON (-want +got):\n%s", diff)
+			}
+
+			if err := json.Unmarshal(b, tt.out); err != nil {
+				t.Fatalf("failed to unmarshal JSON: %v", err)
+			}
+
+			// out is a pointer to a value of the same type as v.
+			if diff := cmp.Diff(tt.v, deref(tt.out)); diff != "" {
+				t.Fatalf("unexpected value (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValueJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{
+			name: "UUID",
+			v:    ovsdb.UUID("foo"),
+		},
+		{
+			name: "named UUID",
+			v:    ovsdb.NamedUUID("1foo"),
+		},
+		{
+			name: "set UUID",
+			v:    ovsdb.Set{ovsdb.UUID("foo")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := json.Marshal(tt.v); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestParseSet(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		s    ovsdb.Set
+		ok   bool
+	}{
+		{
+			name: "set",
+			v:    []interface{}{"set", []interface{}{"foo", "bar"}},
+			s:    ovsdb.Set{"foo", "bar"},
+			ok:   true,
+		},
+		{
+			name: "single atom",
+			v:    "foo",
+			s:    ovsdb.Set{"foo"},
+			ok:   true,
+		},
+		{
+			name: "single UUID",
+			v:    []interface{}{"uuid", valueUUID},
+			s:    ovsdb.Set{ovsdb.UUID(valueUUID)},
+			ok:   true,
+		},
+		{
+			name: "bad elements",
+			v:    []interface{}{"set", "foo"},
+		},
+		{
+			name: "map",
+			v:    []interface{}{"map", []interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ovsdb.ParseSet(tt.v)
+			if tt.ok && err != nil {
+				t.Fatalf("failed to parse set: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+
+			if diff := cmp.Diff(tt.s, s); diff != "" {
+				t.Fatalf("unexpected set (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseMapInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{
+			name: "atom",
+			v:    "foo",
+		},
+		{
+			name: "set",
+			v:    []interface{}{"set", []interface{}{}},
+		},
+		{
+			name: "bad pair",
+			v:    []interface{}{"map", []interface{}{[]interface{}{"foo"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ovsdb.ParseMap(tt.v); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestSetConvert(t *testing.T) {
+	s, err := ovsdb.NewSet([]int{10, 20})
+	if err != nil {
+		t.Fatalf("failed to create set: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.Set{10, 20}, s); diff != "" {
+		t.Fatalf("unexpected set (-want +got):\n%s", diff)
+	}
+
+	// Integers decoded from JSON are int64s.
+	var ints []uint16
+	if err := (ovsdb.Set{int64(10), int64(20)}).Decode(&ints); err != nil {
+		t.Fatalf("failed to decode set: %v", err)
+	}
+
+	if diff := cmp.Diff([]uint16{10, 20}, ints); diff != "" {
+		t.Fatalf("unexpected integers (-want +got):\n%s", diff)
+	}
+
+	var uuids []string
+	if err := (ovsdb.Set{ovsdb.UUID(valueUUID)}).Decode(&uuids); err != nil {
+		t.Fatalf("failed to decode set: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{valueUUID}, uuids); diff != "" {
+		t.Fatalf("unexpected UUIDs (-want +got):\n%s", diff)
+	}
+}
+
+func TestSetConvertInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		s    ovsdb.Set
+		out  interface{}
+	}{
+		{
+			name: "not a pointer",
+			out:  []string{},
+		},
+		{
+			name: "not a slice",
+			out:  new(string),
+		},
+		{
+			name: "fractional integer",
+			s:    ovsdb.Set{1.5},
+			out:  new([]int),
+		},
+		{
+			name: "overflow",
+			s:    ovsdb.Set{256.0},
+			out:  new([]uint8),
+		},
+		{
+			name: "negative unsigned",
+			s:    ovsdb.Set{-1.0},
+			out:  new([]uint),
+		},
+		{
+			name: "string to bool",
+			s:    ovsdb.Set{"true"},
+			out:  new([]bool),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Decode(tt.out); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+
+	if _, err := ovsdb.NewSet("foo"); err == nil {
+		t.Fatal("expected an error creating a set from a string")
+	}
+	if _, err := ovsdb.NewSet([][]string{{"foo"}}); err == nil {
+		t.Fatal("expected an error creating a set of slices")
+	}
+}
+
+func TestMapConvert(t *testing.T) {
+	in := map[string]string{"foo": "bar", "baz": "qux"}
+
+	m, err := ovsdb.NewMap(in)
+	if err != nil {
+		t.Fatalf("failed to create map: %v", err)
+	}
+
+	if diff := cmp.Diff(ovsdb.Map{"foo": "bar", "baz": "qux"}, m); diff != "" {
+		t.Fatalf("unexpected map (-want +got):\n%s", diff)
+	}
+
+	var out map[string]string
+	if err := m.Decode(&out); err != nil {
+		t.Fatalf("failed to decode map: %v", err)
+	}
+
+	if diff := cmp.Diff(in, out); diff != "" {
+		t.Fatalf("unexpected decoded map (-want +got):\n%s", diff)
+	}
+
+	var queues map[int]ovsdb.UUID
+	if err := (ovsdb.Map{0.0: ovsdb.UUID(valueUUID)}).Decode(&queues); err != nil {
+		t.Fatalf("failed to decode map: %v", err)
+	}
+
+	if diff := cmp.Diff(map[int]ovsdb.UUID{0: valueUUID}, queues); diff != "" {
+		t.Fatalf("unexpected queues (-want +got):\n%s", diff)
+	}
+
+	if err := m.Decode(new([]string)); err == nil {
+		t.Fatal("expected an error decoding a map into a slice")
+	}
+}
+
+// deref dereferences a pointer to one of the OVSDB value types.
+func deref(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *ovsdb.UUID:
+		return *v
+	case *ovsdb.NamedUUID:
+		return *v
+	case *ovsdb.Set:
+		return *v
+	case *ovsdb.Map:
+		return *v
+	default:
+		panicf("unexpected type: %T", v)
+		return nil
+	}
+}
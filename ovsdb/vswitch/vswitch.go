@@ -0,0 +1,305 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vswitch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+)
+
+// DatabaseName is the name of the Open vSwitch configuration database.
+const DatabaseName = "Open_vSwitch"
+
+// Names of the tables used by a Client.
+const (
+	tableOpenvSwitch = "Open_vSwitch"
+	tableBridge      = "Bridge"
+	tablePort        = "Port"
+	tableInterface   = "Interface"
+)
+
+// A Client manages Open vSwitch using an OVSDB connection to ovsdb-server.
+type Client struct {
+	c *ovsdb.Client
+}
+
+// New creates a Client which uses the OVSDB connection c.  The Client does
+// not take ownership of c, which must be closed by the caller.
+func New(c *ovsdb.Client) *Client {
+	return &Client{c: c}
+}
+
+// AddBridge creates a bridge, along with an internal port and interface of
+// the same name.  The bridge may or may not already exist.
+func (c *Client) AddBridge(ctx context.Context, bridge string) error {
+	_, ok, err := c.lookup(ctx, tableBridge, bridge)
+	if err != nil || ok {
+		return err
+	}
+
+	ops := []ovsdb.TransactOp{
+		// Guard against the bridge being added concurrently.
+		notExists(tableBridge, bridge),
+		insertInterface(bridge, "internal"),
+		insertPort(bridge),
+		ovsdb.Insert{
+			Table:    tableBridge,
+			UUIDName: "new_bridge",
+			Row: ovsdb.Row{
+				"name":  bridge,
+				"ports": ovsdb.Set{ovsdb.NamedUUID("new_port")},
+			},
+		},
+		ovsdb.Mutate{
+			Table: tableOpenvSwitch,
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateInsert("bridges", ovsdb.Set{ovsdb.NamedUUID("new_bridge")}),
+			},
+		},
+	}
+
+	return c.transact(ctx, ops)
+}
+
+// DelBridge deletes a bridge and all of its ports and interfaces.  The
+// bridge may or may not already exist.
+func (c *Client) DelBridge(ctx context.Context, bridge string) error {
+	uuid, ok, err := c.lookup(ctx, tableBridge, bridge)
+	if err != nil || !ok {
+		return err
+	}
+
+	// The bridge's rows are garbage collected by ovsdb-server once they
+	// are no longer referenced.
+	ops := []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: tableOpenvSwitch,
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete("bridges", ovsdb.Set{uuid}),
+			},
+		},
+	}
+
+	return c.transact(ctx, ops)
+}
+
+// AddPort creates a port and an interface of the same name, and attaches the
+// port to a bridge.  The bridge must exist, but the port may or may not
+// already exist.
+func (c *Client) AddPort(ctx context.Context, bridge, port string) error {
+	uuid, ok, err := c.lookup(ctx, tableBridge, bridge)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("bridge %q does not exist", bridge)
+	}
+
+	_, ok, err = c.lookup(ctx, tablePort, port)
+	if err != nil || ok {
+		return err
+	}
+
+	ops := []ovsdb.TransactOp{
+		notExists(tablePort, port),
+		insertInterface(port, ""),
+		insertPort(port),
+		ovsdb.Mutate{
+			Table: tableBridge,
+			Where: []ovsdb.Cond{ovsdb.Equal("_uuid", uuid)},
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateInsert("ports", ovsdb.Set{ovsdb.NamedUUID("new_port")}),
+			},
+		},
+	}
+
+	return c.transact(ctx, ops)
+}
+
+// DelPort detaches a port from a bridge and deletes the port and its
+// interfaces.  The port may or may not already exist.
+func (c *Client) DelPort(ctx context.Context, bridge, port string) error {
+	uuid, ok, err := c.lookup(ctx, tablePort, port)
+	if err != nil || !ok {
+		return err
+	}
+
+	ops := []ovsdb.TransactOp{
+		ovsdb.Mutate{
+			Table: tableBridge,
+			Where: []ovsdb.Cond{ovsdb.Equal("name", bridge)},
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete("ports", ovsdb.Set{uuid}),
+			},
+		},
+	}
+
+	return c.transact(ctx, ops)
+}
+
+// InterfaceOptions are options which can be set on an interface using
+// SetInterfaceOptions.  Only non-zero options are set.
+type InterfaceOptions struct {
+	// Type specifies the Open vSwitch interface type, such as "internal",
+	// "patch", or "vxlan".
+	Type string
+
+	// Options specifies key/value pairs to set in the interface's options
+	// column, such as "peer" for patch interfaces or "remote_ip" and "key"
+	// for tunnel interfaces.  Other existing options are left unchanged.
+	Options map[string]string
+
+	// IngressPolicingRate specifies the maximum rate for data received on
+	// the interface in kbps.  Set to 0 to disable policing.
+	IngressPolicingRate *int
+
+	// IngressPolicingBurst specifies the maximum burst size for data
+	// received on the interface in kb.  Set to 0 to use the default burst
+	// size.
+	IngressPolicingBurst *int
+}
+
+// SetInterfaceOptions applies options to an existing interface.
+func (c *Client) SetInterfaceOptions(ctx context.Context, ifi string, options InterfaceOptions) error {
+	where := []ovsdb.Cond{ovsdb.Equal("name", ifi)}
+
+	row := make(ovsdb.Row)
+	if options.Type != "" {
+		row["type"] = options.Type
+	}
+	if options.IngressPolicingRate != nil {
+		row["ingress_policing_rate"] = *options.IngressPolicingRate
+	}
+	if options.IngressPolicingBurst != nil {
+		row["ingress_policing_burst"] = *options.IngressPolicingBurst
+	}
+
+	// The first operation always reports whether the interface exists,
+	// even if there are no columns to update.
+	ops := []ovsdb.TransactOp{
+		ovsdb.Update{
+			Table: tableInterface,
+			Where: where,
+			Row:   row,
+		},
+	}
+
+	if len(options.Options) > 0 {
+		keys := make(ovsdb.Set, 0, len(options.Options))
+		for k := range options.Options {
+			keys = append(keys, k)
+		}
+
+		m, err := ovsdb.NewMap(options.Options)
+		if err != nil {
+			return err
+		}
+
+		// Insert does not replace the values of existing keys, so delete
+		// them first.
+		ops = append(ops, ovsdb.Mutate{
+			Table: tableInterface,
+			Where: where,
+			Mutations: []ovsdb.Mutation{
+				ovsdb.MutateDelete("options", keys),
+				ovsdb.MutateInsert("options", m),
+			},
+		})
+	}
+
+	res, err := c.c.Transact(ctx, DatabaseName, ops)
+	if err != nil {
+		return err
+	}
+
+	if res[0].Count == 0 {
+		return fmt.Errorf("interface %q does not exist", ifi)
+	}
+
+	return nil
+}
+
+// lookup returns the UUID of the row with the specified name in table, and
+// reports whether the row exists.
+func (c *Client) lookup(ctx context.Context, table, name string) (ovsdb.UUID, bool, error) {
+	res, err := c.c.Transact(ctx, DatabaseName, []ovsdb.TransactOp{
+		ovsdb.Select{
+			Table:   table,
+			Where:   []ovsdb.Cond{ovsdb.Equal("name", name)},
+			Columns: []string{"_uuid"},
+		},
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if len(res) == 0 || len(res[0].Rows) == 0 {
+		return "", false, nil
+	}
+
+	uuid, err := ovsdb.ParseUUID(res[0].Rows[0]["_uuid"])
+	if err != nil {
+		return "", false, err
+	}
+
+	return uuid, true, nil
+}
+
+// transact executes a transaction on the Open vSwitch database.
+func (c *Client) transact(ctx context.Context, ops []ovsdb.TransactOp) error {
+	_, err := c.c.Transact(ctx, DatabaseName, ops)
+	return err
+}
+
+// notExists returns a TransactOp which aborts a transaction if a row with
+// the specified name exists in table.
+func notExists(table, name string) ovsdb.TransactOp {
+	return ovsdb.Wait{
+		Table:   table,
+		Where:   []ovsdb.Cond{ovsdb.Equal("name", name)},
+		Columns: []string{"name"},
+		Rows:    []ovsdb.Row{},
+	}
+}
+
+// insertInterface returns a TransactOp which inserts an interface with the
+// UUID name new_interface.
+func insertInterface(name, typ string) ovsdb.TransactOp {
+	row := ovsdb.Row{"name": name}
+	if typ != "" {
+		row["type"] = typ
+	}
+
+	return ovsdb.Insert{
+		Table:    tableInterface,
+		UUIDName: "new_interface",
+		Row:      row,
+	}
+}
+
+// insertPort returns a TransactOp which inserts a port with the UUID name
+// new_port, which contains the interface inserted by insertInterface.
+func insertPort(name string) ovsdb.TransactOp {
+	return ovsdb.Insert{
+		Table:    tablePort,
+		UUIDName: "new_port",
+		Row: ovsdb.Row{
+			"name":       name,
+			"interfaces": ovsdb.Set{ovsdb.NamedUUID("new_interface")},
+		},
+	}
+}
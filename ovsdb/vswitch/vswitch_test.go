@@ -0,0 +1,289 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vswitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/digitalocean/go-openvswitch/ovsdb/vswitch"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	bridgeUUID = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a2"
+	portUUID   = "36bb9a65-3d5b-4e1f-a345-6cf0c3d2b8a3"
+)
+
+func TestClient(t *testing.T) {
+	var (
+		selectBridge = `{"op":"select","table":"Bridge","where":[["name","==","br0"]],"columns":["_uuid"]}`
+		selectPort   = `{"op":"select","table":"Port","where":[["name","==","eth0"]],"columns":["_uuid"]}`
+
+		bridgeRows = `[{"rows":[{"_uuid":["uuid","` + bridgeUUID + `"]}]}]`
+		portRows   = `[{"rows":[{"_uuid":["uuid","` + portUUID + `"]}]}]`
+		noRows     = `[{"rows":[]}]`
+	)
+
+	rate := 1000
+
+	tests := []struct {
+		name  string
+		fn    func(ctx context.Context, c *vswitch.Client) error
+		steps []step
+	}{
+		{
+			name: "add bridge",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddBridge(ctx, "br0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: noRows},
+				{
+					ops: `{"op":"wait","timeout":0,"table":"Bridge","where":[["name","==","br0"]],"columns":["name"],"until":"==","rows":[]}` +
+						`,{"op":"insert","table":"Interface","uuid-name":"new_interface","row":{"name":"br0","type":"internal"}}` +
+						`,{"op":"insert","table":"Port","uuid-name":"new_port","row":{"interfaces":["set",[["named-uuid","new_interface"]]],"name":"br0"}}` +
+						`,{"op":"insert","table":"Bridge","uuid-name":"new_bridge","row":{"name":"br0","ports":["set",[["named-uuid","new_port"]]]}}` +
+						`,{"op":"mutate","table":"Open_vSwitch","where":[],"mutations":[["bridges","insert",["set",[["named-uuid","new_bridge"]]]]]}`,
+					result: `[{},{"uuid":["uuid","` + bridgeUUID + `"]},{"uuid":["uuid","` + bridgeUUID + `"]},{"uuid":["uuid","` + bridgeUUID + `"]},{"count":1}]`,
+				},
+			},
+		},
+		{
+			name: "add bridge exists",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddBridge(ctx, "br0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: bridgeRows},
+			},
+		},
+		{
+			name: "delete bridge",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.DelBridge(ctx, "br0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: bridgeRows},
+				{
+					ops:    `{"op":"mutate","table":"Open_vSwitch","where":[],"mutations":[["bridges","delete",["set",[["uuid","` + bridgeUUID + `"]]]]]}`,
+					result: `[{"count":1}]`,
+				},
+			},
+		},
+		{
+			name: "delete bridge not exists",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.DelBridge(ctx, "br0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: noRows},
+			},
+		},
+		{
+			name: "add port",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddPort(ctx, "br0", "eth0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: bridgeRows},
+				{ops: selectPort, result: noRows},
+				{
+					ops: `{"op":"wait","timeout":0,"table":"Port","where":[["name","==","eth0"]],"columns":["name"],"until":"==","rows":[]}` +
+						`,{"op":"insert","table":"Interface","uuid-name":"new_interface","row":{"name":"eth0"}}` +
+						`,{"op":"insert","table":"Port","uuid-name":"new_port","row":{"interfaces":["set",[["named-uuid","new_interface"]]],"name":"eth0"}}` +
+						`,{"op":"mutate","table":"Bridge","where":[["_uuid","==",["uuid","` + bridgeUUID + `"]]],"mutations":[["ports","insert",["set",[["named-uuid","new_port"]]]]]}`,
+					result: `[{},{"uuid":["uuid","` + portUUID + `"]},{"uuid":["uuid","` + portUUID + `"]},{"count":1}]`,
+				},
+			},
+		},
+		{
+			name: "add port exists",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddPort(ctx, "br0", "eth0")
+			},
+			steps: []step{
+				{ops: selectBridge, result: bridgeRows},
+				{ops: selectPort, result: portRows},
+			},
+		},
+		{
+			name: "delete port",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.DelPort(ctx, "br0", "eth0")
+			},
+			steps: []step{
+				{ops: selectPort, result: portRows},
+				{
+					ops:    `{"op":"mutate","table":"Bridge","where":[["name","==","br0"]],"mutations":[["ports","delete",["set",[["uuid","` + portUUID + `"]]]]]}`,
+					result: `[{"count":1}]`,
+				},
+			},
+		},
+		{
+			name: "set interface options",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.SetInterfaceOptions(ctx, "vx0", vswitch.InterfaceOptions{
+					Type: "vxlan",
+					Options: map[string]string{
+						"remote_ip": "192.0.2.1",
+					},
+					IngressPolicingRate: &rate,
+				})
+			},
+			steps: []step{
+				{
+					ops: `{"op":"update","table":"Interface","where":[["name","==","vx0"]],"row":{"ingress_policing_rate":1000,"type":"vxlan"}}` +
+						`,{"op":"mutate","table":"Interface","where":[["name","==","vx0"]],"mutations":[["options","delete",["set",["remote_ip"]]],["options","insert",["map",[["remote_ip","192.0.2.1"]]]]]}`,
+					result: `[{"count":1},{"count":1}]`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, done := testClient(t, tt.steps)
+			defer done()
+
+			if err := tt.fn(context.Background(), c); err != nil {
+				t.Fatalf("failed to perform operation: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    func(ctx context.Context, c *vswitch.Client) error
+		steps []step
+	}{
+		{
+			name: "add port no bridge",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddPort(ctx, "br0", "eth0")
+			},
+			steps: []step{
+				{result: `[{"rows":[]}]`},
+			},
+		},
+		{
+			name: "set options no interface",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.SetInterfaceOptions(ctx, "vx0", vswitch.InterfaceOptions{Type: "vxlan"})
+			},
+			steps: []step{
+				{result: `[{"count":0}]`},
+			},
+		},
+		{
+			name: "add bridge conflict",
+			fn: func(ctx context.Context, c *vswitch.Client) error {
+				return c.AddBridge(ctx, "br0")
+			},
+			steps: []step{
+				{result: `[{"rows":[]}]`},
+				{result: `[{"error":"timed out","details":"\"wait\" timed out"},null,null,null,null]`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, done := testClient(t, tt.steps)
+			defer done()
+
+			if err := tt.fn(context.Background(), c); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+// A step is an expected transaction and its result.  If ops is empty, the
+// transaction's operations are not checked.
+type step struct {
+	ops, result string
+}
+
+// testClient creates a vswitch.Client backed by a server which expects the
+// transactions in steps, in order.
+func testClient(t *testing.T, steps []step) (*vswitch.Client, func()) {
+	t.Helper()
+
+	var (
+		mu sync.Mutex
+		i  int
+	)
+
+	conn, _, done := jsonrpc.TestNetConn(t, func(req jsonrpc.Request) jsonrpc.Response {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if diff := cmp.Diff("transact", req.Method); diff != "" {
+			panicf("unexpected RPC method (-want +got):\n%s", diff)
+		}
+
+		if i >= len(steps) {
+			panicf("unexpected transaction: %v", req.Params)
+		}
+
+		s := steps[i]
+		i++
+
+		if s.ops != "" {
+			var want []interface{}
+			if err := json.Unmarshal([]byte(`["Open_vSwitch",`+s.ops+`]`), &want); err != nil {
+				panicf("failed to unmarshal operations: %v", err)
+			}
+
+			if diff := cmp.Diff(want, req.Params); diff != "" {
+				panicf("unexpected transaction (-want +got):\n%s", diff)
+			}
+		}
+
+		return jsonrpc.Response{
+			ID:     &req.ID,
+			Result: json.RawMessage(s.result),
+		}
+	})
+
+	oc, err := ovsdb.New(conn)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return vswitch.New(oc), func() {
+		_ = oc.Close()
+		done()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if diff := cmp.Diff(len(steps), i); diff != "" {
+			t.Fatalf("unexpected number of transactions (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func panicf(format string, a ...interface{}) {
+	panic(fmt.Sprintf(format, a...))
+}
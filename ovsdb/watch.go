@@ -0,0 +1,356 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A WatchEventType is the type of change reported by a WatchEvent.
+type WatchEventType int
+
+// Possible WatchEventType values.
+const (
+	// A row was added to the table, or was present when the Watcher was
+	// created.
+	WatchAdd WatchEventType = iota
+
+	// A row in the table was modified.
+	WatchUpdate
+
+	// A row was deleted from the table.
+	WatchDelete
+)
+
+// String returns the string representation of a WatchEventType.
+func (t WatchEventType) String() string {
+	switch t {
+	case WatchAdd:
+		return "add"
+	case WatchUpdate:
+		return "update"
+	case WatchDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// A WatchEvent describes a change to a row, decoded into the model type
+// passed to Cache.Watch.
+type WatchEvent struct {
+	// The type of change, and the row's UUID.
+	Type WatchEventType
+	UUID string
+
+	// Pointers to new values of the model type, containing the row before
+	// and after the change.  Old is nil for WatchAdd, and New is nil for
+	// WatchDelete.
+	Old, New interface{}
+
+	// Non-nil if the row could not be decoded into the model type, in which
+	// case Old and New are nil.
+	Err error
+}
+
+// A Watcher delivers changes to the rows of a cached table as WatchEvents.
+// Watchers are created using Cache.Watch.
+type Watcher struct {
+	c     *Cache
+	table string
+	typ   reflect.Type
+
+	events chan WatchEvent
+
+	// Changes waiting to be delivered, and whether the Cache is no longer
+	// updated.
+	mu     sync.Mutex
+	queue  []WatchEvent
+	closed bool
+
+	notify   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// Watch creates a Watcher which delivers changes to the rows of table,
+// decoded into the struct type of model using the struct tags described for
+// UnmarshalRow.  model may be a struct or a pointer to a struct, and is only
+// used to determine the type.  The "_uuid" column of each row is available
+// for decoding.
+//
+// The Watcher first delivers a WatchAdd event for each row currently in the
+// table, followed by changes as they are applied to the Cache.  If the Client
+// reconnects, the Watcher delivers the differences between the contents of
+// the table before and after reconnecting, so that no changes are missed.
+//
+// Events are queued in memory until they are received, and never delay
+// updates to the Cache.  The events channel is closed when Watcher.Stop is
+// called, or after the last change is delivered once the Cache is canceled
+// or its Client is closed.
+func (c *Cache) Watch(table string, model interface{}) (*Watcher, error) {
+	typ := reflect.TypeOf(model)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct or pointer to struct model, but got %T", model)
+	}
+
+	w := &Watcher{
+		c:      c,
+		table:  table,
+		typ:    typ,
+		events: make(chan WatchEvent),
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.finished {
+		return nil, errors.New("cache is no longer updated")
+	}
+
+	t, ok := c.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %q is not cached", table)
+	}
+
+	adds := make([]rowChange, 0, len(t.rows))
+	for uuid, row := range t.rows {
+		adds = append(adds, rowChange{
+			table: table,
+			uuid:  uuid,
+			new:   row,
+		})
+	}
+
+	w.push(adds)
+	c.watchers = append(c.watchers, w)
+
+	go w.run()
+
+	return w, nil
+}
+
+// Events returns a channel which receives changes to the watched table.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Stop stops the Watcher and closes its events channel.  Any changes which
+// have not been received are discarded.
+func (w *Watcher) Stop() {
+	w.c.mu.Lock()
+	for i, cw := range w.c.watchers {
+		if cw == w {
+			w.c.watchers = append(w.c.watchers[:i], w.c.watchers[i+1:]...)
+			break
+		}
+	}
+	w.c.mu.Unlock()
+
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// push queues changes to the watched table for delivery.
+func (w *Watcher) push(changes []rowChange) {
+	events := make([]WatchEvent, 0, len(changes))
+	for _, ch := range changes {
+		if ch.table == w.table {
+			events = append(events, w.event(ch))
+		}
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.queue = append(w.queue, events...)
+	w.mu.Unlock()
+
+	w.wake()
+}
+
+// finish reports that no more changes will be pushed.
+func (w *Watcher) finish() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	w.wake()
+}
+
+// wake notifies the delivery goroutine that the queue has changed.
+func (w *Watcher) wake() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued events until the Watcher is stopped, or all events
+// are delivered after finish is called.
+func (w *Watcher) run() {
+	defer close(w.done)
+	defer close(w.events)
+
+	for {
+		w.mu.Lock()
+		queue, closed := w.queue, w.closed
+		w.queue = nil
+		w.mu.Unlock()
+
+		for _, e := range queue {
+			select {
+			case w.events <- e:
+			case <-w.stop:
+				return
+			}
+		}
+
+		if len(queue) > 0 {
+			// More events may have been queued while delivering.
+			continue
+		}
+
+		if closed {
+			return
+		}
+
+		select {
+		case <-w.notify:
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// event decodes a change into a WatchEvent.
+func (w *Watcher) event(ch rowChange) WatchEvent {
+	e := WatchEvent{UUID: ch.uuid}
+	switch {
+	case ch.old == nil:
+		e.Type = WatchAdd
+	case ch.new == nil:
+		e.Type = WatchDelete
+	default:
+		e.Type = WatchUpdate
+	}
+
+	var err error
+	if e.Old, err = w.decode(ch.uuid, ch.old); err != nil {
+		return WatchEvent{Type: e.Type, UUID: ch.uuid, Err: err}
+	}
+	if e.New, err = w.decode(ch.uuid, ch.new); err != nil {
+		return WatchEvent{Type: e.Type, UUID: ch.uuid, Err: err}
+	}
+
+	return e
+}
+
+// decode decodes row into a new value of the model type, or returns nil if
+// row is nil.
+func (w *Watcher) decode(uuid string, row Row) (interface{}, error) {
+	if row == nil {
+		return nil, nil
+	}
+
+	// Cached rows are shared, so add the UUID to a copy.
+	r := make(Row, len(row)+1)
+	for column, v := range row {
+		r[column] = v
+	}
+	r["_uuid"] = UUID(uuid)
+
+	v := reflect.New(w.typ).Interface()
+	if err := UnmarshalRow(r, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// A rowChange is a change to a row in a Cache.  old is nil if the row was
+// inserted, and new is nil if the row was deleted.
+type rowChange struct {
+	table, uuid string
+	old, new    Row
+}
+
+// notify delivers changes to the Cache's Watchers.  c.mu must be held.
+func (c *Cache) notify(changes []rowChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, w := range c.watchers {
+		w.push(changes)
+	}
+}
+
+// resetChanges returns the differences between each table's rows before the
+// Cache was reset and its current rows.  c.mu must be held.
+func (c *Cache) resetChanges() []rowChange {
+	if len(c.watchers) == 0 {
+		return nil
+	}
+
+	var changes []rowChange
+	for name, t := range c.tables {
+		prev := c.prev[name]
+
+		for uuid, old := range prev {
+			row, ok := t.rows[uuid]
+			switch {
+			case !ok:
+				changes = append(changes, rowChange{table: name, uuid: uuid, old: old})
+			case !reflect.DeepEqual(old, row):
+				changes = append(changes, rowChange{table: name, uuid: uuid, old: old, new: row})
+			}
+		}
+
+		for uuid, row := range t.rows {
+			if _, ok := prev[uuid]; !ok {
+				changes = append(changes, rowChange{table: name, uuid: uuid, new: row})
+			}
+		}
+	}
+
+	return changes
+}
+
+// closeWatchers finishes all Watchers once the Cache is no longer updated.
+func (c *Cache) closeWatchers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, w := range c.watchers {
+		w.finish()
+	}
+	c.watchers = nil
+	c.finished = true
+}
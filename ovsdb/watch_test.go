@@ -0,0 +1,236 @@
+// Copyright 2017 DigitalOcean.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsdb_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/go-openvswitch/ovsdb"
+	"github.com/digitalocean/go-openvswitch/ovsdb/internal/jsonrpc"
+	"github.com/google/go-cmp/cmp"
+)
+
+// A watchBridge is the model used to watch the Bridge table.
+type watchBridge struct {
+	UUID     ovsdb.UUID `ovsdb:"_uuid"`
+	Name     string     `ovsdb:"name"`
+	FailMode *string    `ovsdb:"fail_mode"`
+}
+
+func TestCacheWatch(t *testing.T) {
+	idC := make(chan string, 1)
+
+	c, notifC, done := testClient(t, testCacheServer(t, idC, func() string {
+		return `{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"initial":{"name":"br0","fail_mode":["set",[]]}},` +
+			`"` + cacheBridge1 + `":{"initial":{"name":"br1","fail_mode":"secure"}}` +
+			`}}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+
+	w, err := cache.Watch("Bridge", &watchBridge{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	secure, standalone := "secure", "standalone"
+
+	br0 := &watchBridge{UUID: cacheBridge0, Name: "br0"}
+	br1 := &watchBridge{UUID: cacheBridge1, Name: "br1", FailMode: &secure}
+
+	// The current rows are delivered first.
+	want := []ovsdb.WatchEvent{
+		{Type: ovsdb.WatchAdd, UUID: cacheBridge0, New: br0},
+		{Type: ovsdb.WatchAdd, UUID: cacheBridge1, New: br1},
+	}
+
+	if diff := cmp.Diff(want, watchEvents(t, w, len(want))); diff != "" {
+		t.Fatalf("unexpected initial events (-want +got):\n%s", diff)
+	}
+
+	id := <-idC
+
+	notifC <- &jsonrpc.Response{
+		Method: "update2",
+		Params: []byte(`["` + id + `",{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"modify":{"fail_mode":"standalone"}},` +
+			`"` + cacheBridge1 + `":{"delete":null},` +
+			`"` + cacheBridge2 + `":{"insert":{"name":"br2"}}` +
+			`}}]`),
+	}
+
+	want = []ovsdb.WatchEvent{
+		{
+			Type: ovsdb.WatchUpdate,
+			UUID: cacheBridge0,
+			Old:  br0,
+			New:  &watchBridge{UUID: cacheBridge0, Name: "br0", FailMode: &standalone},
+		},
+		{Type: ovsdb.WatchDelete, UUID: cacheBridge1, Old: br1},
+		{Type: ovsdb.WatchAdd, UUID: cacheBridge2, New: &watchBridge{UUID: cacheBridge2, Name: "br2"}},
+	}
+
+	if diff := cmp.Diff(want, watchEvents(t, w, len(want))); diff != "" {
+		t.Fatalf("unexpected events (-want +got):\n%s", diff)
+	}
+
+	if err := cache.Cancel(context.Background()); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	// The events channel is closed once the Cache is no longer updated.
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("expected events channel to be closed")
+	}
+
+	if _, err := cache.Watch("Bridge", watchBridge{}); err == nil {
+		t.Fatal("expected an error after canceling, but none occurred")
+	}
+}
+
+func TestCacheWatchReconnect(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		conns int
+	)
+
+	d := newTestDialer(t, testCacheServer(t, nil, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// While the client is disconnected, the first bridge is modified,
+		// the second is deleted, and the third is inserted.
+		conns++
+		if conns == 1 {
+			return `{"Bridge":{` +
+				`"` + cacheBridge0 + `":{"initial":{"name":"br0"}},` +
+				`"` + cacheBridge1 + `":{"initial":{"name":"br1"}}` +
+				`}}`
+		}
+
+		return `{"Bridge":{` +
+			`"` + cacheBridge0 + `":{"initial":{"name":"br0","fail_mode":"secure"}},` +
+			`"` + cacheBridge2 + `":{"initial":{"name":"br2"}}` +
+			`}}`
+	}))
+	defer d.done()
+
+	c := d.client(ovsdb.Reconnect(10*time.Millisecond, 50*time.Millisecond))
+	defer c.Close()
+
+	cache := testCache(t, c)
+
+	w, err := cache.Watch("Bridge", watchBridge{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer w.Stop()
+
+	br0 := &watchBridge{UUID: cacheBridge0, Name: "br0"}
+	br1 := &watchBridge{UUID: cacheBridge1, Name: "br1"}
+
+	if diff := cmp.Diff(2, len(watchEvents(t, w, 2))); diff != "" {
+		t.Fatalf("unexpected number of initial events (-want +got):\n%s", diff)
+	}
+
+	d.drop()
+
+	secure := "secure"
+	want := []ovsdb.WatchEvent{
+		{
+			Type: ovsdb.WatchUpdate,
+			UUID: cacheBridge0,
+			Old:  br0,
+			New:  &watchBridge{UUID: cacheBridge0, Name: "br0", FailMode: &secure},
+		},
+		{Type: ovsdb.WatchDelete, UUID: cacheBridge1, Old: br1},
+		{Type: ovsdb.WatchAdd, UUID: cacheBridge2, New: &watchBridge{UUID: cacheBridge2, Name: "br2"}},
+	}
+
+	if diff := cmp.Diff(want, watchEvents(t, w, len(want))); diff != "" {
+		t.Fatalf("unexpected events after reconnect (-want +got):\n%s", diff)
+	}
+}
+
+func TestCacheWatchStop(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		return `{"Bridge":{"` + cacheBridge0 + `":{"initial":{"name":"br0"}}}}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+	defer cache.Cancel(context.Background())
+
+	w, err := cache.Watch("Bridge", watchBridge{})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	// Undelivered events are discarded.
+	w.Stop()
+
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("expected events channel to be closed")
+	}
+}
+
+func TestCacheWatchInvalid(t *testing.T) {
+	c, _, done := testClient(t, testCacheServer(t, nil, func() string {
+		return `{}`
+	}))
+	defer done()
+
+	cache := testCache(t, c)
+	defer cache.Cancel(context.Background())
+
+	if _, err := cache.Watch("Bridge", "br0"); err == nil {
+		t.Fatal("expected an error for non-struct model, but none occurred")
+	}
+
+	if _, err := cache.Watch("Port", watchBridge{}); err == nil {
+		t.Fatal("expected an error for uncached table, but none occurred")
+	}
+}
+
+// watchEvents receives n events from w, sorted by UUID.
+func watchEvents(t *testing.T, w *ovsdb.Watcher, n int) []ovsdb.WatchEvent {
+	t.Helper()
+
+	events := make([]ovsdb.WatchEvent, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-w.Events():
+			if e.Err != nil {
+				t.Fatalf("failed to decode event: %v", e.Err)
+			}
+
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].UUID < events[j].UUID
+	})
+
+	return events
+}